@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/backup"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupOutput string
+	backupInput  string
+	restoreApply bool
+	restoreForce bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Data directory backup commands",
+	Long:  `Create, verify and restore archives of the whole Pulse data directory`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a backup of the data directory",
+	Long: `Create a versioned, checksummed archive of the Pulse data directory: configuration,
+encryption keys, alert history, capacity and rightsizing history. Pulse may keep running.
+
+The archive contains the encryption key and credentials; store it securely.
+
+Metrics history and Docker host state only live in the server's memory. Backups created
+through the API (POST /api/system/backup) or the backup schedule include them.`,
+	Example: `  # Write a backup to the current directory
+  pulse backup create
+
+  # Write a backup to a specific file
+  pulse backup create -o /srv/backups/pulse.tar.gz`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output := backupOutput
+		if output == "" {
+			output = backup.ArchiveName(time.Now())
+		}
+
+		tmp := output + ".tmp"
+		file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create backup file: %w", err)
+		}
+		manifest, err := backup.Create(file, backup.Options{DataDir: configDataPath(), PulseVersion: Version})
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+		if err := os.Rename(tmp, output); err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+
+		fmt.Printf("Backup written to %s (%d files, %d bytes uncompressed)\n", output, len(manifest.Files), manifest.TotalSize())
+		return nil
+	},
+}
+
+var backupVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a backup archive",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := openBackupInput()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		manifest, err := backup.Verify(file)
+		if err != nil {
+			return err
+		}
+		printManifest(manifest)
+		fmt.Println("Backup is intact")
+		return nil
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the data directory from a backup",
+	Long: `Verify a backup and stage it in the data directory. Every checksum is checked, JSON files
+must parse and encrypted files must decrypt with the archived key before anything is replaced.
+
+The staged backup replaces the data directory the next time Pulse starts. The previous files
+are kept in a .pre-restore-<timestamp> directory. Use --apply to replace the files right away
+while Pulse is stopped.`,
+	Example: `  # Stage a restore, then restart Pulse
+  pulse backup restore -i pulse-backup-20250101T000000Z.tar.gz
+  systemctl restart pulse
+
+  # Restore immediately while Pulse is stopped
+  systemctl stop pulse
+  pulse backup restore -i pulse-backup-20250101T000000Z.tar.gz --apply
+  systemctl start pulse`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := openBackupInput()
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		dataDir := configDataPath()
+		manifest, err := backup.Stage(file, dataDir)
+		if err != nil {
+			return err
+		}
+		printManifest(manifest)
+
+		if !restoreApply {
+			fmt.Println("Backup verified and staged. Restart Pulse to apply it.")
+			return nil
+		}
+
+		if !restoreForce {
+			fmt.Printf("This replaces the contents of %s. Pulse must not be running.\n", dataDir)
+			if !confirm("Apply the restore now? (yes/no): ") {
+				fmt.Println("Restore staged; it will be applied when Pulse next starts")
+				return nil
+			}
+		}
+		if _, err := backup.ApplyPending(dataDir); err != nil {
+			return err
+		}
+		fmt.Printf("Data directory restored; previous files moved to %s\n", filepath.Join(dataDir, ".pre-restore-*"))
+		return nil
+	},
+}
+
+func openBackupInput() (*os.File, error) {
+	if backupInput == "" {
+		return nil, fmt.Errorf("input file is required (use -i or --input)")
+	}
+	return os.Open(backupInput)
+}
+
+func printManifest(manifest *backup.Manifest) {
+	version := manifest.PulseVersion
+	if version == "" {
+		version = "unknown"
+	}
+	fmt.Printf("Created:   %s on %s\n", manifest.CreatedAt.Local().Format(time.RFC1123), manifest.Hostname)
+	fmt.Printf("Version:   %s\n", version)
+	fmt.Printf("Files:     %d (%d bytes)\n", len(manifest.Files), manifest.TotalSize())
+	if runtime := manifest.RuntimeNames(); len(runtime) > 0 {
+		fmt.Printf("Runtime:   %v\n", runtime)
+	}
+}
+
+func init() {
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupVerifyCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	backupCreateCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "Output file (default pulse-backup-<timestamp>.tar.gz)")
+	backupVerifyCmd.Flags().StringVarP(&backupInput, "input", "i", "", "Backup archive to verify")
+	backupRestoreCmd.Flags().StringVarP(&backupInput, "input", "i", "", "Backup archive to restore")
+	backupRestoreCmd.Flags().BoolVar(&restoreApply, "apply", false, "Replace the data directory now instead of at next start")
+	backupRestoreCmd.Flags().BoolVar(&restoreForce, "force", false, "Apply without confirmation")
+}
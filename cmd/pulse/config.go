@@ -18,10 +18,13 @@ import (
 )
 
 var (
-	exportFile  string
-	importFile  string
-	passphrase  string
-	forceImport bool
+	exportFile   string
+	importFile   string
+	passphrase   string
+	forceImport  bool
+	exportFormat string
+	declFile     string
+	forceApply   bool
 )
 
 var configCmd = &cobra.Command{
@@ -61,8 +64,18 @@ var configExportCmd = &cobra.Command{
   pulse config export -o pulse-config.enc
   
   # Export with passphrase from environment variable
-  PULSE_PASSPHRASE=mysecret pulse config export -o pulse-config.enc`,
+  PULSE_PASSPHRASE=mysecret pulse config export -o pulse-config.enc
+
+  # Export as a config-as-code YAML document (secrets become ${PULSE_...} references)
+  pulse config export --format yaml -o pulse.yaml`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportFormat == "yaml" {
+			return exportDeclarativeConfig()
+		}
+		if exportFormat != "" && exportFormat != "encrypted" {
+			return fmt.Errorf("unsupported export format %q (use encrypted or yaml)", exportFormat)
+		}
+
 		// Get passphrase
 		pass := getPassphrase("Enter passphrase for encryption: ", false)
 		if pass == "" {
@@ -163,6 +176,131 @@ var configImportCmd = &cobra.Command{
 	},
 }
 
+var configPlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show changes a config-as-code document would make",
+	Long:  `Compare a declarative YAML configuration document with the live configuration and show the differences`,
+	Example: `  # Show what applying pulse.yaml would change
+  pulse config plan -f pulse.yaml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, plan, err := loadDeclarativePlan()
+		if err != nil {
+			return err
+		}
+		printDeclarativePlan(plan)
+		return nil
+	},
+}
+
+var configApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a config-as-code document",
+	Long:  `Apply a declarative YAML configuration document. All changed settings are written in a single transaction.`,
+	Example: `  # Review and apply pulse.yaml
+  pulse config apply -f pulse.yaml
+
+  # Apply without confirmation (e.g. from CI)
+  pulse config apply -f pulse.yaml --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		persistence, plan, err := loadDeclarativePlan()
+		if err != nil {
+			return err
+		}
+		printDeclarativePlan(plan)
+		if len(plan.Changes) == 0 {
+			return nil
+		}
+
+		// Confirm apply unless forced
+		if !forceApply {
+			fmt.Print("Apply these changes? (yes/no): ")
+			reader := bufio.NewReader(os.Stdin)
+			response, _ := reader.ReadString('\n')
+			response = strings.TrimSpace(strings.ToLower(response))
+			if response != "yes" && response != "y" {
+				fmt.Println("Apply cancelled")
+				return nil
+			}
+		}
+
+		if err := persistence.ApplyDeclarativeConfig(plan); err != nil {
+			return fmt.Errorf("failed to apply configuration: %w", err)
+		}
+
+		fmt.Println("Configuration applied successfully")
+		fmt.Println("Please restart Pulse for changes to take effect:")
+		fmt.Println("  sudo systemctl restart pulse")
+		return nil
+	},
+}
+
+// loadDeclarativePlan parses the config-as-code document and plans it against the live configuration
+func loadDeclarativePlan() (*config.ConfigPersistence, *config.ConfigPlan, error) {
+	if declFile == "" {
+		return nil, nil, fmt.Errorf("config file is required (use -f flag)")
+	}
+
+	data, err := ioutil.ReadFile(declFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decl, err := config.ParseDeclarativeConfig(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	persistence := config.NewConfigPersistence(configDataPath())
+	plan, err := persistence.PlanDeclarativeConfig(decl)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to plan configuration: %w", err)
+	}
+	return persistence, plan, nil
+}
+
+func printDeclarativePlan(plan *config.ConfigPlan) {
+	for _, warning := range plan.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	if len(plan.Changes) == 0 {
+		fmt.Println("No changes. Live configuration matches the document.")
+		return
+	}
+
+	for _, change := range plan.Changes {
+		fmt.Println(change.String())
+	}
+	add, change, remove := plan.Counts()
+	fmt.Println()
+	fmt.Printf("Plan: %d to add, %d to change, %d to remove.\n", add, change, remove)
+}
+
+func exportDeclarativeConfig() error {
+	persistence := config.NewConfigPersistence(configDataPath())
+	document, err := persistence.ExportDeclarativeConfig()
+	if err != nil {
+		return fmt.Errorf("failed to export configuration: %w", err)
+	}
+
+	if exportFile != "" {
+		if err := ioutil.WriteFile(exportFile, document, 0600); err != nil {
+			return fmt.Errorf("failed to write export file: %w", err)
+		}
+		fmt.Printf("Configuration exported to %s\n", exportFile)
+		return nil
+	}
+	fmt.Print(string(document))
+	return nil
+}
+
+// configDataPath returns the Pulse configuration directory
+func configDataPath() string {
+	if configPath := os.Getenv("PULSE_DATA_DIR"); configPath != "" {
+		return configPath
+	}
+	return "/etc/pulse"
+}
+
 // getPassphrase prompts for a passphrase or gets it from environment
 func getPassphrase(prompt string, confirm bool) string {
 	// Check environment variable first
@@ -292,13 +430,21 @@ func init() {
 	configCmd.AddCommand(configExportCmd)
 	configCmd.AddCommand(configImportCmd)
 	configCmd.AddCommand(configAutoImportCmd)
+	configCmd.AddCommand(configPlanCmd)
+	configCmd.AddCommand(configApplyCmd)
 
 	// Export flags
 	configExportCmd.Flags().StringVarP(&exportFile, "output", "o", "", "Output file for encrypted configuration")
 	configExportCmd.Flags().StringVarP(&passphrase, "passphrase", "p", "", "Passphrase for encryption (or use PULSE_PASSPHRASE env var)")
+	configExportCmd.Flags().StringVar(&exportFormat, "format", "encrypted", "Export format: encrypted or yaml")
 
 	// Import flags
 	configImportCmd.Flags().StringVarP(&importFile, "input", "i", "", "Input file with encrypted configuration")
 	configImportCmd.Flags().StringVarP(&passphrase, "passphrase", "p", "", "Passphrase for decryption (or use PULSE_PASSPHRASE env var)")
 	configImportCmd.Flags().BoolVarP(&forceImport, "force", "f", false, "Force import without confirmation")
+
+	// Plan/apply flags
+	configPlanCmd.Flags().StringVarP(&declFile, "file", "f", "", "Config-as-code YAML document")
+	configApplyCmd.Flags().StringVarP(&declFile, "file", "f", "", "Config-as-code YAML document")
+	configApplyCmd.Flags().BoolVar(&forceApply, "force", false, "Apply without confirmation")
 }
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/crypto"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	forceRotate bool
+	wrapMethod  string
+)
+
+var cryptoCmd = &cobra.Command{
+	Use:   "crypto",
+	Short: "Encryption key management commands",
+	Long:  `Manage the keyring used to encrypt credentials in the Pulse data directory`,
+}
+
+var cryptoStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show encryption keyring information",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cm, err := crypto.NewCryptoManager()
+		if err != nil {
+			return err
+		}
+
+		info := cm.KeyInfo()
+		versions := make([]string, 0, len(info.Versions))
+		for _, version := range info.Versions {
+			versions = append(versions, fmt.Sprint(version))
+		}
+		wrap := info.WrapMethod
+		if wrap == "" {
+			wrap = "none (keys stored in the clear)"
+		}
+
+		fmt.Printf("Active key version: %d\n", info.ActiveVersion)
+		fmt.Printf("Key versions:       %s\n", strings.Join(versions, ", "))
+		fmt.Printf("Key wrapping:       %s\n", wrap)
+		return nil
+	},
+}
+
+var cryptoRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the encryption key",
+	Long: `Generate a new encryption key and re-encrypt every encrypted file in the data directory
+with it. All files are replaced in a single transaction and the old key is removed afterwards.
+Stop the Pulse service first: a running server could still write files with the old key, so
+rotation refuses to start while a server is using the data directory.`,
+	Example: `  # Rotate the key
+  systemctl stop pulse && pulse crypto rotate && systemctl start pulse
+
+  # Rotate without confirmation (e.g. from a timer)
+  pulse crypto rotate --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !forceRotate {
+			fmt.Println("The old key will be removed. Backups of the data directory taken before")
+			fmt.Println("the rotation need the old key (or an exported configuration) to be restored.")
+			if !confirm("Rotate the encryption key? (yes/no): ") {
+				fmt.Println("Rotation cancelled")
+				return nil
+			}
+		}
+
+		persistence := config.NewConfigPersistence(configDataPath())
+		result, err := persistence.RotateEncryptionKey()
+		if errors.Is(err, config.ErrDataDirInUse) {
+			return fmt.Errorf("%w; stop the Pulse service before rotating the key", err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to rotate encryption key: %w", err)
+		}
+
+		fmt.Printf("Re-encrypted %d file(s) with key version %d\n", len(result.Reencrypted), result.KeyVersion)
+		for _, name := range result.Skipped {
+			fmt.Printf("Warning: %s could not be decrypted and was left unchanged\n", name)
+		}
+		return nil
+	},
+}
+
+var cryptoWrapCmd = &cobra.Command{
+	Use:   "wrap",
+	Short: "Protect the encryption keys with a passphrase or plugin",
+	Long: `Wrap the keys in the keyring so that a copy of the data directory alone does not reveal
+stored credentials. With --method passphrase the keys are wrapped with an argon2id-derived key;
+Pulse then needs PULSE_MASTER_PASSPHRASE, PULSE_MASTER_PASSPHRASE_FILE or the systemd credential
+pulse-master-passphrase to start. With --method plugin the external program (e.g. a KMS client or
+TPM sealing tool) named in PULSE_KEY_WRAP_PLUGIN wraps the keys; the Pulse service needs the same
+PULSE_KEY_WRAP_PLUGIN to start. --method none stores the keys unwrapped again.
+Stop the Pulse service first; wrapping refuses to start while a server is using the data directory.
+
+To change the passphrase of a wrapped keyring, provide the current one in PULSE_MASTER_PASSPHRASE;
+the new one is read from PULSE_NEW_MASTER_PASSPHRASE or prompted for.`,
+	Example: `  # Require a passphrase to unlock the keys
+  pulse crypto wrap --method passphrase
+
+  # Wrap the keys with a KMS plugin
+  PULSE_KEY_WRAP_PLUGIN="/usr/local/bin/pulse-kms --key-id alias/pulse" pulse crypto wrap --method plugin`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lock, err := config.LockDataDirExclusive(configDataPath())
+		if errors.Is(err, config.ErrDataDirInUse) {
+			return fmt.Errorf("%w; stop the Pulse service before wrapping the keys", err)
+		}
+		if err != nil {
+			return err
+		}
+		defer lock.Release()
+
+		cm, err := crypto.NewCryptoManager()
+		if err != nil {
+			return err
+		}
+
+		var wrapper crypto.KeyWrapper
+		switch wrapMethod {
+		case "passphrase":
+			passphrase := os.Getenv("PULSE_NEW_MASTER_PASSPHRASE")
+			if passphrase == "" {
+				passphrase = promptNewMasterPassphrase()
+			}
+			if wrapper, err = crypto.NewPassphraseWrapper(passphrase); err != nil {
+				return err
+			}
+		case "plugin":
+			command := crypto.KeyWrapPluginFromEnv()
+			if command == "" {
+				return fmt.Errorf("set %s to the key wrap plugin command", crypto.KeyWrapPluginEnv)
+			}
+			if wrapper, err = crypto.NewPluginWrapper(command); err != nil {
+				return err
+			}
+		case "none":
+		default:
+			return fmt.Errorf("unknown wrap method %q (use passphrase, plugin or none)", wrapMethod)
+		}
+
+		if err := cm.SetKeyWrapper(wrapper); err != nil {
+			return fmt.Errorf("failed to wrap keys: %w", err)
+		}
+
+		switch wrapMethod {
+		case "passphrase":
+			fmt.Println("Keys are now wrapped with the master passphrase.")
+			fmt.Println("Set PULSE_MASTER_PASSPHRASE or PULSE_MASTER_PASSPHRASE_FILE for the Pulse service before starting it again.")
+		case "plugin":
+			fmt.Println("Keys are now wrapped by the plugin.")
+			fmt.Printf("Set %s to the same command for the Pulse service before starting it again.\n", crypto.KeyWrapPluginEnv)
+		default:
+			fmt.Println("Keys are now stored unwrapped.")
+		}
+		return nil
+	},
+}
+
+// promptNewMasterPassphrase reads and confirms a new master passphrase from the terminal
+func promptNewMasterPassphrase() string {
+	fmt.Print("New master passphrase: ")
+	first, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil {
+		return ""
+	}
+	fmt.Print("Confirm master passphrase: ")
+	second, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil || string(first) != string(second) {
+		fmt.Println("Passphrases do not match")
+		return ""
+	}
+	return string(first)
+}
+
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "yes" || response == "y"
+}
+
+func init() {
+	cryptoCmd.AddCommand(cryptoStatusCmd)
+	cryptoCmd.AddCommand(cryptoRotateCmd)
+	cryptoCmd.AddCommand(cryptoWrapCmd)
+
+	cryptoRotateCmd.Flags().BoolVar(&forceRotate, "force", false, "Rotate without confirmation")
+	cryptoWrapCmd.Flags().StringVar(&wrapMethod, "method", "passphrase", "Wrap method: passphrase, plugin or none")
+}
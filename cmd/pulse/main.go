@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -13,10 +14,14 @@ import (
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/api"
+	"github.com/RouXx67/PulseUp/internal/backup"
 	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/ha"
 	"github.com/RouXx67/PulseUp/internal/logging"
 	_ "github.com/RouXx67/PulseUp/internal/mock" // Import for init() to run
 	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/tlscert"
+	"github.com/RouXx67/PulseUp/internal/tracing"
 	"github.com/RouXx67/PulseUp/internal/websocket"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -44,6 +49,12 @@ var rootCmd = &cobra.Command{
 func init() {
 	// Add config command
 	rootCmd.AddCommand(configCmd)
+	// Add crypto command
+	rootCmd.AddCommand(cryptoCmd)
+	// Add backup command
+	rootCmd.AddCommand(backupCmd)
+	// Add update command
+	rootCmd.AddCommand(updateCmd)
 	// Add version command
 	rootCmd.AddCommand(versionCmd)
 }
@@ -77,6 +88,20 @@ func runServer() {
 		Component: "pulse",
 	})
 
+	// Hold the data directory so "pulse crypto rotate" cannot rewrite it underneath the server
+	dataDirLock, err := config.LockDataDirShared(configDataPath())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to lock the data directory")
+	}
+	defer dataDirLock.Release()
+
+	// Swap in a restore staged by the API or "pulse backup restore" before anything reads the data directory
+	if manifest, err := backup.ApplyPending(configDataPath()); err != nil {
+		log.Error().Err(err).Msg("Failed to apply staged restore, starting with existing data")
+	} else if manifest != nil {
+		log.Info().Time("backupCreatedAt", manifest.CreatedAt).Msg("Applied staged restore")
+	}
+
 	// Check for auto-import on first startup
 	if shouldAutoImport() {
 		if err := performAutoImport(); err != nil {
@@ -99,6 +124,27 @@ func runServer() {
 
 	log.Info().Msg("Starting Pulse monitoring server")
 
+	// Export traces of polls and API requests to an OTLP collector
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+			Endpoint:       cfg.Tracing.Endpoint,
+			SampleRatio:    cfg.Tracing.SampleRatio,
+			ServiceVersion: Version,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to initialize tracing, continuing without it")
+		} else {
+			log.Info().Str("endpoint", cfg.Tracing.Endpoint).Float64("sampleRatio", cfg.Tracing.SampleRatio).Msg("OpenTelemetry tracing enabled")
+			defer func() {
+				flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer flushCancel()
+				if err := shutdownTracing(flushCtx); err != nil {
+					log.Warn().Err(err).Msg("Failed to flush traces")
+				}
+			}()
+		}
+	}
+
 	// Create context that cancels on interrupt
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -137,6 +183,22 @@ func runServer() {
 		return reloadableMonitor.GetState()
 	})
 
+	// With high availability, stand by until this instance holds the lease
+	var haNode *ha.Node
+	if cfg.HA.Enabled {
+		haConfig := cfg.HA
+		if haConfig.AdvertiseURL == "" {
+			haConfig.AdvertiseURL = strings.TrimRight(cfg.PublicURL, "/")
+		}
+		haNode, err = ha.New(haConfig, cfg.DataPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize high availability")
+		}
+		if err := reloadableMonitor.SetStandby(true); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start as HA standby")
+		}
+	}
+
 	// Start monitoring
 	reloadableMonitor.Start(ctx)
 
@@ -155,6 +217,21 @@ func runServer() {
 		return nil
 	}
 	router = api.NewRouter(cfg, reloadableMonitor.GetMonitor(), wsHub, reloadFunc)
+	router.StartUpdateTasks(ctx)
+
+	if haNode != nil {
+		router.SetHA(haNode)
+		go haNode.Run(ctx, func(leader bool) {
+			if err := reloadableMonitor.SetStandby(!leader); err != nil {
+				log.Error().Err(err).Bool("leader", leader).Msg("Failed to switch HA role")
+				return
+			}
+			router.SetMonitor(reloadableMonitor.GetMonitor())
+			if cfg := reloadableMonitor.GetConfig(); cfg != nil {
+				router.SetConfig(cfg)
+			}
+		})
+	}
 
 	// Create HTTP server with unified configuration
 	// In production, serve everything (frontend + API) on the frontend port
@@ -184,21 +261,72 @@ func runServer() {
 			}
 		})
 
+		// Reconnect nodes with the new values when a referenced secret is rotated
+		configWatcher.SetCredentialsChangeCallback(func() {
+			if err := reloadableMonitor.Reload(); err != nil {
+				log.Error().Err(err).Msg("Failed to reload monitor after credential change")
+			} else if router != nil {
+				router.SetMonitor(reloadableMonitor.GetMonitor())
+				if cfg := reloadableMonitor.GetConfig(); cfg != nil {
+					router.SetConfig(cfg)
+				}
+			}
+		})
+
 		if err := configWatcher.Start(); err != nil {
 			log.Warn().Err(err).Msg("Failed to start config watcher")
 		}
 		defer configWatcher.Stop()
 	}
 
+	// Serve the certificate through a store so renewed files are picked up without a restart
+	var certStore *tlscert.Store
+	if cfg.HTTPSEnabled && cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
+		certStore, err = tlscert.NewStore(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load TLS certificate")
+		}
+		srv.TLSConfig = certStore.TLSConfig()
+		go certStore.Watch(ctx, 30*time.Second, func(leaf *x509.Certificate) {
+			if monitor := reloadableMonitor.GetMonitor(); monitor != nil {
+				monitor.GetAlertManager().CheckPulseCertificate(leaf.Subject.CommonName, leaf.NotAfter)
+			}
+		})
+
+		if cfg.ACME.Enabled {
+			issuer, err := tlscert.NewIssuer(cfg.ACME, cfg.DataPath, certStore)
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to set up ACME certificates")
+			}
+			if haNode != nil {
+				issuer.SetRenewCondition(haNode.IsLeader)
+			}
+			if cfg.ACME.Challenge == config.ACMEChallengeHTTP01 {
+				challengeSrv := &http.Server{
+					Addr:              cfg.ACME.HTTPAddress,
+					Handler:           issuer.HTTPHandler(),
+					ReadHeaderTimeout: 10 * time.Second,
+				}
+				go func() {
+					if err := challengeSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+						log.Error().Err(err).Str("addr", cfg.ACME.HTTPAddress).Msg("Failed to start ACME HTTP-01 listener")
+					}
+				}()
+				defer challengeSrv.Close()
+			}
+			go issuer.Run(ctx)
+		}
+	}
+
 	// Start server
 	go func() {
-		if cfg.HTTPSEnabled && cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
+		if certStore != nil {
 			log.Info().
 				Str("host", cfg.BackendHost).
 				Int("port", cfg.FrontendPort).
 				Str("protocol", "HTTPS").
 				Msg("Server listening")
-			if err := srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile); err != nil && err != http.ErrServerClosed {
+			if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
 				log.Fatal().Err(err).Msg("Failed to start HTTPS server")
 			}
 		} else {
@@ -236,6 +364,13 @@ func runServer() {
 				configWatcher.ReloadConfig()
 			}
 
+			// Pick up a replaced TLS certificate right away instead of at the next check
+			if certStore != nil {
+				if _, err := certStore.Reload(); err != nil {
+					log.Error().Err(err).Msg("Failed to reload TLS certificate")
+				}
+			}
+
 			// Reload system.json
 			persistence := config.NewConfigPersistence(cfg.DataPath)
 			if persistence != nil {
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/RouXx67/PulseUp/internal/logging"
+	"github.com/RouXx67/PulseUp/internal/updates"
+	"github.com/spf13/cobra"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update commands",
+}
+
+var updateSuperviseCmd = &cobra.Command{
+	Use:   "supervise",
+	Short: "Restart Pulse into an in-app update and roll back if it is unhealthy",
+	Long: `Handle a restart requested by an in-app update. The command restarts the Pulse service,
+waits for its health endpoint and restores the pre-update backup when the automatic
+rollback policy is enabled.
+
+It runs as root from the pulse-update-verify service that install.sh sets up, outside
+the Pulse service, so an update that crashes or hangs on start is still rolled back.
+The root process re-executes the command as the pulse user, which reads the request,
+the update history and the backup; root only starts, stops and restarts the service.
+Nothing happens when no restart was requested.`,
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Init(logging.Config{
+			Format:    "auto",
+			Level:     "info",
+			Component: "pulse-update-verify",
+		})
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return updates.SuperviseUpdate(ctx, configDataPath())
+	},
+}
+
+func init() {
+	updateCmd.AddCommand(updateSuperviseCmd)
+}
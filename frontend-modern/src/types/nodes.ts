@@ -11,6 +11,19 @@ export interface ClusterEndpoint {
   LastSeen: string;
 }
 
+export interface PVEResourceFilter {
+  includeNodes?: string[];
+  excludeNodes?: string[];
+  includeVMIDs?: string[];
+  excludeVMIDs?: string[];
+  includeTags?: string[];
+  excludeTags?: string[];
+  includePools?: string[];
+  excludePools?: string[];
+  includeStorage?: string[];
+  excludeStorage?: string[];
+}
+
 export interface PVENodeConfig {
   id: string;
   name: string;
@@ -27,6 +40,9 @@ export interface PVENodeConfig {
   monitorStorage: boolean;
   monitorBackups: boolean;
   monitorPhysicalDisks: boolean;
+  // Fixed poll interval in seconds (0 or unset = adaptive)
+  pollIntervalSeconds?: number;
+  filters?: PVEResourceFilter;
   // Cluster information
   isCluster?: boolean;
   clusterName?: string;
@@ -15,6 +15,7 @@ NC='\033[0m' # No Color
 # Configuration
 INSTALL_DIR="/opt/pulse"
 CONFIG_DIR="/etc/pulse"  # All config and data goes here for manual installs
+SUPERVISOR_DIR="/usr/local/lib/pulse"  # Root-owned copy of the binary for the update supervisor
 SERVICE_NAME="pulse"
 GITHUB_REPO="RouXx67/PulseUP"
 BUILD_FROM_SOURCE=false
@@ -2414,10 +2415,51 @@ ReadWritePaths=$INSTALL_DIR $CONFIG_DIR
 WantedBy=multi-user.target
 EOF
 
+    install_update_supervisor
+
     # Reload systemd daemon
     safe_systemctl daemon-reload
 }
 
+# Supervisor for in-app updates: restarts Pulse, checks its health and rolls back from outside the service.
+# It starts as root from a root-owned copy of the binary, since the pulse user can replace $INSTALL_DIR/bin/pulse,
+# and drops to the pulse user for everything except service control.
+install_update_supervisor() {
+    mkdir -p "$SUPERVISOR_DIR"
+    chown root:root "$SUPERVISOR_DIR"
+    chmod 755 "$SUPERVISOR_DIR"
+    install -o root -g root -m 755 "$INSTALL_DIR/bin/pulse" "$SUPERVISOR_DIR/pulse-update-verify"
+
+    cat > /etc/systemd/system/pulse-update-verify.service << EOF
+[Unit]
+Description=Verify Pulse after an in-app update
+Documentation=https://github.com/RouXx67/PulseUp
+
+[Service]
+Type=oneshot
+User=root
+Group=root
+ExecStart=$SUPERVISOR_DIR/pulse-update-verify update supervise
+Environment="PULSE_DATA_DIR=$CONFIG_DIR"
+TimeoutStartSec=600
+StandardOutput=journal
+StandardError=journal
+SyslogIdentifier=pulse-update-verify
+EOF
+
+    cat > /etc/systemd/system/pulse-update-verify.path << EOF
+[Unit]
+Description=Watch for Pulse in-app update restarts
+Documentation=https://github.com/RouXx67/PulseUp
+
+[Path]
+PathExists=$CONFIG_DIR/.update-supervise.json
+
+[Install]
+WantedBy=multi-user.target
+EOF
+}
+
 start_pulse() {
     print_info "Starting Pulse..."
     
@@ -2425,6 +2467,7 @@ start_pulse() {
     if ! safe_systemctl enable $SERVICE_NAME; then
         print_info "Note: systemctl enable failed (common in unprivileged containers)"
     fi
+    safe_systemctl enable --now pulse-update-verify.path || true
     
     if ! safe_systemctl start $SERVICE_NAME; then
         print_info "Note: systemctl start failed (common in unprivileged containers)"
@@ -2810,6 +2853,8 @@ main() {
                 create_user
                 download_pulse
                 setup_update_command
+                install_update_supervisor
+                safe_systemctl daemon-reload
                 
                 # Setup auto-updates if requested during update
                 if [[ "$ENABLE_AUTO_UPDATES" == "true" ]]; then
@@ -3015,6 +3060,11 @@ uninstall_pulse() {
         systemctl disable --now pulse-update.timer
     fi
     
+    # Stop and disable the in-app update supervisor if it exists
+    if systemctl is-enabled --quiet pulse-update-verify.path 2>/dev/null; then
+        systemctl disable --now pulse-update-verify.path
+    fi
+    
     # Remove files
     echo "Removing Pulse files..."
     rm -rf /opt/pulse
@@ -3023,6 +3073,9 @@ uninstall_pulse() {
     rm -f /etc/systemd/system/pulse-backend.service
     rm -f /etc/systemd/system/pulse-update.service
     rm -f /etc/systemd/system/pulse-update.timer
+    rm -f /etc/systemd/system/pulse-update-verify.service
+    rm -f /etc/systemd/system/pulse-update-verify.path
+    rm -rf "$SUPERVISOR_DIR"
     rm -f /usr/local/bin/pulse
     rm -f /usr/local/bin/pulse-auto-update.sh
     # Don't remove update commands - might be from community scripts
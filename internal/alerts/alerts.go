@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/models"
@@ -63,8 +64,10 @@ type Alert struct {
 	// Notification tracking
 	LastNotified *time.Time `json:"lastNotified,omitempty"` // Last time notification was sent
 	// Escalation tracking
-	LastEscalation  int         `json:"lastEscalation,omitempty"`  // Last escalation level notified
-	EscalationTimes []time.Time `json:"escalationTimes,omitempty"` // Times when escalations were sent
+	LastEscalation   int         `json:"lastEscalation,omitempty"`   // Last escalation level notified
+	EscalationTimes  []time.Time `json:"escalationTimes,omitempty"`  // Times when escalations were sent
+	EscalationPolicy string      `json:"escalationPolicy,omitempty"` // Policy used for the last escalation
+	EscalationTarget string      `json:"escalationTarget,omitempty"` // Target notified by the last escalation
 }
 
 // Clone returns a deep copy of the alert so it can be safely shared across goroutines.
@@ -203,7 +206,13 @@ type EscalationLevel struct {
 // EscalationConfig represents alert escalation configuration
 type EscalationConfig struct {
 	Enabled bool              `json:"enabled"`
-	Levels  []EscalationLevel `json:"levels"`
+	Levels  []EscalationLevel `json:"levels"` // Legacy levels used when no policy applies
+	// Named escalation policies resolved from on-call schedules
+	Targets       []EscalationTarget `json:"targets,omitempty"`
+	Schedules     []OnCallSchedule   `json:"schedules,omitempty"`
+	Policies      []EscalationPolicy `json:"policies,omitempty"`
+	Routes        []AlertRoute       `json:"routes,omitempty"`
+	DefaultPolicy string             `json:"defaultPolicy,omitempty"`
 }
 
 // GroupingConfig represents alert grouping configuration
@@ -308,6 +317,44 @@ type BackupAlertConfig struct {
 	CriticalDays int  `json:"criticalDays"`
 }
 
+// MaintenanceAlertConfig represents certificate expiry and pending kernel reboot alert configuration
+type MaintenanceAlertConfig struct {
+	CertificateExpiryEnabled bool `json:"certificateExpiryEnabled"`
+	CertificateWarningDays   int  `json:"certificateWarningDays"`
+	CertificateCriticalDays  int  `json:"certificateCriticalDays"`
+	KernelOutdatedEnabled    bool `json:"kernelOutdatedEnabled"`
+}
+
+// TaskAlertConfig represents failed task alert configuration. Types enables or disables alerts per
+// Proxmox task type (qmigrate, qmclone, vzdump, ...); types not listed fall back to the built-in defaults.
+type TaskAlertConfig struct {
+	Enabled          bool            `json:"enabled"`
+	Types            map[string]bool `json:"types,omitempty"`
+	AutoResolveHours int             `json:"autoResolveHours"`
+}
+
+// ForecastAlertConfig represents predicted capacity exhaustion alert configuration. Alerts are raised
+// when a node, storage, PBS datastore or Ceph pool is projected to be full within WarningDays and
+// the forecast confidence is at least MinConfidence.
+type ForecastAlertConfig struct {
+	Enabled       bool    `json:"enabled"`
+	WarningDays   int     `json:"warningDays"`
+	CriticalDays  int     `json:"criticalDays"`
+	MinConfidence float64 `json:"minConfidence"` // 0-1
+}
+
+// AnomalyAlertConfig represents baseline-aware anomaly alert configuration for guest and node CPU,
+// network and disk IO. A metric is anomalous when it exceeds its learned baseline for the time of
+// day and day of week by both a ratio and an absolute margin for ConfirmMinutes.
+type AnomalyAlertConfig struct {
+	Enabled        bool    `json:"enabled"`
+	WarningRatio   float64 `json:"warningRatio"`   // Multiple of the baseline for a warning
+	CriticalRatio  float64 `json:"criticalRatio"`  // Multiple of the baseline for a critical alert
+	MinCPUDelta    float64 `json:"minCpuDelta"`    // Percentage points above the baseline
+	MinIODelta     float64 `json:"minIoDelta"`     // MB/s above the baseline
+	ConfirmMinutes int     `json:"confirmMinutes"` // How long a metric must stay anomalous
+}
+
 // GuestLookup describes a guest identity used for snapshot/backup evaluations.
 type GuestLookup struct {
 	Name     string
@@ -331,6 +378,10 @@ type AlertConfig struct {
 	PMGDefaults                    PMGThresholdConfig         `json:"pmgDefaults"`
 	SnapshotDefaults               SnapshotAlertConfig        `json:"snapshotDefaults"`
 	BackupDefaults                 BackupAlertConfig          `json:"backupDefaults"`
+	MaintenanceDefaults            MaintenanceAlertConfig     `json:"maintenanceDefaults"`
+	TaskDefaults                   TaskAlertConfig            `json:"taskDefaults"`
+	ForecastDefaults               ForecastAlertConfig        `json:"forecastDefaults"`
+	AnomalyDefaults                AnomalyAlertConfig         `json:"anomalyDefaults"`
 	Overrides                      map[string]ThresholdConfig `json:"overrides"` // keyed by resource ID
 	CustomRules                    []CustomAlertRule          `json:"customRules,omitempty"`
 	Schedule                       ScheduleConfig             `json:"schedule"`
@@ -390,9 +441,10 @@ type pmgAnomalyTracker struct {
 // Manager handles alert monitoring and state
 //
 // Lock Ordering Documentation:
-// The Manager uses two mutexes to prevent deadlocks:
+// The Manager uses three mutexes to prevent deadlocks:
 //  1. m.mu (primary lock) - protects most manager state
 //  2. m.resolvedMutex - protects only recentlyResolved map
+//  3. m.silenceMu - protects only silences map (leaf lock, never held while acquiring another lock)
 //
 // Lock Ordering Rules:
 //   - NEVER hold m.mu when acquiring resolvedMutex
@@ -430,8 +482,16 @@ type Manager struct {
 	pmgQuarantineHistory map[string][]pmgQuarantineSnapshot // Track quarantine snapshots for growth detection
 	// PMG anomaly detection tracking
 	pmgAnomalyTrackers map[string]*pmgAnomalyTracker // Track mail metrics for anomaly detection per PMG instance
+	// Guest and node anomaly detection
+	baselines      *baselineEngine      // Learned per-metric baselines
+	anomalyPending map[string]time.Time // When each unconfirmed anomaly was first seen
 	// Persistent acknowledgement state so quick alert rebuilds keep user acknowledgements
 	ackState map[string]ackRecord
+	// Temporary notification silences keyed by silence ID
+	silences  map[string]Silence
+	silenceMu sync.RWMutex // Leaf lock - see Lock Ordering Documentation above
+	// Set on an HA standby so its idle copy never overwrites the state replicated from the leader
+	readOnly atomic.Bool
 }
 
 type ackRecord struct {
@@ -458,6 +518,7 @@ func NewManager() *Manager {
 		recentAlerts:          make(map[string]*Alert),
 		suppressedUntil:       make(map[string]time.Time),
 		recentlyResolved:      make(map[string]*ResolvedAlert),
+		silences:              make(map[string]Silence),
 		pendingAlerts:         make(map[string]time.Time),
 		nodeOfflineCount:      make(map[string]int),
 		offlineConfirmations:  make(map[string]int),
@@ -467,6 +528,8 @@ func NewManager() *Manager {
 		dockerLastExitCode:    make(map[string]int),
 		pmgQuarantineHistory:  make(map[string][]pmgQuarantineSnapshot),
 		pmgAnomalyTrackers:    make(map[string]*pmgAnomalyTracker),
+		baselines:             newBaselineEngine(),
+		anomalyPending:        make(map[string]time.Time),
 		ackState:              make(map[string]ackRecord),
 		config: AlertConfig{
 			Enabled:                true,
@@ -526,6 +589,30 @@ func NewManager() *Manager {
 				WarningDays:  7,
 				CriticalDays: 14,
 			},
+			MaintenanceDefaults: MaintenanceAlertConfig{
+				CertificateExpiryEnabled: true,
+				CertificateWarningDays:   30,
+				CertificateCriticalDays:  7,
+				KernelOutdatedEnabled:    true,
+			},
+			TaskDefaults: TaskAlertConfig{
+				Enabled:          true,
+				AutoResolveHours: 24,
+			},
+			ForecastDefaults: ForecastAlertConfig{
+				Enabled:       true,
+				WarningDays:   30,
+				CriticalDays:  7,
+				MinConfidence: 0.6,
+			},
+			AnomalyDefaults: AnomalyAlertConfig{
+				Enabled:        true,
+				WarningRatio:   3,
+				CriticalRatio:  6,
+				MinCPUDelta:    40,
+				MinIODelta:     50,
+				ConfirmMinutes: 5,
+			},
 			StorageDefault:    HysteresisThreshold{Trigger: 85, Clear: 80},
 			MinimumDelta:      2.0, // 2% minimum change
 			SuppressionWindow: 5,   // 5 minutes
@@ -580,6 +667,12 @@ func NewManager() *Manager {
 	if err := m.LoadActiveAlerts(); err != nil {
 		log.Error().Err(err).Msg("Failed to load active alerts")
 	}
+	if err := m.LoadAnomalyBaselines(); err != nil {
+		log.Error().Err(err).Msg("Failed to load anomaly baselines")
+	}
+	if err := m.LoadSilences(); err != nil {
+		log.Error().Err(err).Msg("Failed to load alert silences")
+	}
 
 	// Start escalation checker
 	go m.escalationChecker()
@@ -699,6 +792,16 @@ func (m *Manager) dispatchAlert(alert *Alert, async bool) bool {
 		return false
 	}
 
+	if silence, silenced := m.silenceForAlert(alert, time.Now()); silenced {
+		log.Debug().
+			Str("alertID", alert.ID).
+			Str("silenceID", silence.ID).
+			Str("resource", silence.Resource).
+			Time("until", silence.Until).
+			Msg("Alert notification suppressed by silence")
+		return false
+	}
+
 	alertCopy := alert.Clone()
 	if async {
 		go func(a *Alert) {
@@ -864,6 +967,45 @@ func (m *Manager) UpdateConfig(config AlertConfig) {
 	if config.BackupDefaults.CriticalDays > 0 && config.BackupDefaults.WarningDays > config.BackupDefaults.CriticalDays {
 		config.BackupDefaults.WarningDays = config.BackupDefaults.CriticalDays
 	}
+	if config.MaintenanceDefaults.CertificateWarningDays <= 0 {
+		config.MaintenanceDefaults.CertificateWarningDays = 30
+	}
+	if config.MaintenanceDefaults.CertificateCriticalDays <= 0 {
+		config.MaintenanceDefaults.CertificateCriticalDays = 7
+	}
+	if config.MaintenanceDefaults.CertificateCriticalDays > config.MaintenanceDefaults.CertificateWarningDays {
+		config.MaintenanceDefaults.CertificateCriticalDays = config.MaintenanceDefaults.CertificateWarningDays
+	}
+	if config.TaskDefaults.AutoResolveHours <= 0 {
+		config.TaskDefaults.AutoResolveHours = 24
+	}
+	if config.ForecastDefaults.WarningDays <= 0 {
+		config.ForecastDefaults.WarningDays = 30
+	}
+	if config.ForecastDefaults.CriticalDays <= 0 {
+		config.ForecastDefaults.CriticalDays = 7
+	}
+	if config.ForecastDefaults.CriticalDays > config.ForecastDefaults.WarningDays {
+		config.ForecastDefaults.CriticalDays = config.ForecastDefaults.WarningDays
+	}
+	if config.ForecastDefaults.MinConfidence <= 0 || config.ForecastDefaults.MinConfidence > 1 {
+		config.ForecastDefaults.MinConfidence = 0.6
+	}
+	if config.AnomalyDefaults.WarningRatio <= 1 {
+		config.AnomalyDefaults.WarningRatio = 3
+	}
+	if config.AnomalyDefaults.CriticalRatio < config.AnomalyDefaults.WarningRatio {
+		config.AnomalyDefaults.CriticalRatio = config.AnomalyDefaults.WarningRatio
+	}
+	if config.AnomalyDefaults.MinCPUDelta <= 0 {
+		config.AnomalyDefaults.MinCPUDelta = 40
+	}
+	if config.AnomalyDefaults.MinIODelta <= 0 {
+		config.AnomalyDefaults.MinIODelta = 50
+	}
+	if config.AnomalyDefaults.ConfirmMinutes < 0 {
+		config.AnomalyDefaults.ConfirmMinutes = 5
+	}
 
 	// Ensure minimums for other important fields
 	if config.MinimumDelta <= 0 {
@@ -975,6 +1117,19 @@ func (m *Manager) UpdateConfig(config AlertConfig) {
 	if !m.config.BackupDefaults.Enabled {
 		m.clearBackupAlertsLocked()
 	}
+	if !m.config.MaintenanceDefaults.CertificateExpiryEnabled {
+		m.clearAlertsOfTypeLocked(certificateExpiryAlertType)
+	}
+	if !m.config.MaintenanceDefaults.KernelOutdatedEnabled {
+		m.clearAlertsOfTypeLocked(kernelOutdatedAlertType)
+	}
+	m.clearDisabledTaskAlertsLocked()
+	if !m.config.ForecastDefaults.Enabled {
+		m.clearAlertsOfTypeLocked(capacityForecastAlertType)
+	}
+	if !m.config.AnomalyDefaults.Enabled {
+		m.clearBaselineAnomalyAlertsLocked()
+	}
 
 	m.applyGlobalOfflineSettingsLocked()
 
@@ -1156,6 +1311,11 @@ func (m *Manager) reevaluateActiveAlertsLocked() {
 			}
 		}
 
+		if isBaselineAnomalyAlert(alert) {
+			// Anomalies are judged against learned baselines, not thresholds
+			continue
+		}
+
 		if alert.Type == "docker-host-offline" ||
 			strings.HasPrefix(alertID, "docker-container-health-") ||
 			strings.HasPrefix(alertID, "docker-container-state-") ||
@@ -1825,6 +1985,15 @@ func (m *Manager) CheckGuest(guest interface{}, instanceName string) {
 	if thresholds.NetworkOut != nil && thresholds.NetworkOut.Trigger > 0 {
 		m.checkMetric(guestID, name, node, instanceName, guestType, "networkOut", float64(netOut)/1024/1024, thresholds.NetworkOut, nil)
 	}
+
+	// Check against the guest's own baseline, catching unusual load below static thresholds
+	m.checkAnomalies(guestID, name, node, instanceName, guestType, []anomalyMetric{
+		{"cpu", cpu},
+		{"networkIn", float64(netIn) / 1024 / 1024},
+		{"networkOut", float64(netOut) / 1024 / 1024},
+		{"diskRead", float64(diskRead) / 1024 / 1024},
+		{"diskWrite", float64(diskWrite) / 1024 / 1024},
+	})
 }
 
 // CheckNode checks a node against thresholds
@@ -1876,6 +2045,10 @@ func (m *Manager) CheckNode(node models.Node) {
 			}
 			m.checkMetric(node.ID, node.Name, node.Name, node.Instance, "Node", "temperature", temp, thresholds.Temperature, nil)
 		}
+
+		if !thresholds.Disabled {
+			m.checkAnomalies(node.ID, node.Name, node.Name, node.Instance, "Node", []anomalyMetric{{"cpu", node.CPU * 100}})
+		}
 	}
 }
 
@@ -3509,6 +3682,11 @@ func (m *Manager) CheckBackups(
 		if record.filename != "" {
 			metadata["filename"] = record.filename
 		}
+		if record.lookup.VMID > 0 && record.lookup.Instance != "" {
+			// Identify the guest so backup remediation can act on the alert
+			metadata["vmid"] = record.lookup.VMID
+			metadata["guestType"] = record.lookup.Type
+		}
 
 		m.mu.Lock()
 		if existing, exists := m.activeAlerts[alertID]; exists {
@@ -4405,6 +4583,8 @@ func (m *Manager) preserveAlertState(alertID string, updated *Alert) {
 			updated.AckTime = nil
 		}
 		updated.LastEscalation = existing.LastEscalation
+		updated.EscalationPolicy = existing.EscalationPolicy
+		updated.EscalationTarget = existing.EscalationTarget
 		if len(existing.EscalationTimes) > 0 {
 			updated.EscalationTimes = append([]time.Time(nil), existing.EscalationTimes...)
 		} else {
@@ -4460,6 +4640,18 @@ func (m *Manager) NotifyExistingAlert(alertID string) {
 	m.dispatchAlert(alert, true)
 }
 
+// GetResolvedAlert returns a copy of a recently resolved alert
+func (m *Manager) GetResolvedAlert(alertID string) (*Alert, bool) {
+	m.resolvedMutex.RLock()
+	defer m.resolvedMutex.RUnlock()
+
+	resolved, ok := m.recentlyResolved[alertID]
+	if !ok || resolved == nil || resolved.Alert == nil {
+		return nil, false
+	}
+	return resolved.Alert.Clone(), true
+}
+
 // GetRecentlyResolved returns recently resolved alerts
 func (m *Manager) GetRecentlyResolved() []models.ResolvedAlert {
 	m.resolvedMutex.RLock()
@@ -5537,66 +5729,8 @@ func (m *Manager) checkQuarantineMetric(pmg models.PMGInstance, metricType strin
 // calculateTrimmedBaseline computes a robust baseline from historical samples
 // using trimmed mean with median fallback for statistical robustness
 func calculateTrimmedBaseline(samples []float64) (baseline float64, trustworthy bool) {
-	sampleCount := len(samples)
-
 	// Need at least 12 samples for trustworthy baseline (warmup period)
-	if sampleCount < 12 {
-		return 0, false
-	}
-
-	// For full 24-sample baseline, use trimmed mean
-	if sampleCount >= 24 {
-		// Create a copy for sorting
-		sorted := make([]float64, len(samples))
-		copy(sorted, samples)
-
-		// Sort samples
-		for i := 0; i < len(sorted); i++ {
-			for j := i + 1; j < len(sorted); j++ {
-				if sorted[i] > sorted[j] {
-					sorted[i], sorted[j] = sorted[j], sorted[i]
-				}
-			}
-		}
-
-		// Calculate median
-		var median float64
-		mid := len(sorted) / 2
-		if len(sorted)%2 == 0 {
-			median = (sorted[mid-1] + sorted[mid]) / 2
-		} else {
-			median = sorted[mid]
-		}
-
-		// Calculate trimmed mean: drop top and bottom 2, average remaining 20
-		if len(sorted) >= 24 {
-			trimmed := sorted[2 : len(sorted)-2]
-			sum := 0.0
-			for _, val := range trimmed {
-				sum += val
-			}
-			trimmedMean := sum / float64(len(trimmed))
-
-			// Fallback rule: if trimmed mean differs from median by >40%, use median
-			diff := trimmedMean - median
-			if diff < 0 {
-				diff = -diff
-			}
-			percentDiff := (diff / median) * 100
-
-			if percentDiff > 40 {
-				return median, true
-			}
-			return trimmedMean, true
-		}
-	}
-
-	// For 12-23 samples, use simple mean (not enough for trimming)
-	sum := 0.0
-	for _, val := range samples {
-		sum += val
-	}
-	return sum / float64(len(samples)), true
+	return trimmedBaseline(samples, 12)
 }
 
 // checkPMGAnomalies detects spam/virus rate anomalies using trimmed baseline
@@ -6189,6 +6323,14 @@ func (m *Manager) Cleanup(maxAge time.Duration) {
 		}
 	}
 
+	// Drop baselines of removed guests and nodes, and anomalies that never confirmed
+	m.baselines.prune(now.Add(-baselineRetention))
+	for id, firstSeen := range m.anomalyPending {
+		if now.Sub(firstSeen) > 24*time.Hour {
+			delete(m.anomalyPending, id)
+		}
+	}
+
 	// Clean up old Docker restart tracking (containers not seen in 24h)
 	// Prevents memory leak from ephemeral containers in CI/CD environments
 	for resourceID, record := range m.dockerRestartTracking {
@@ -6663,15 +6805,24 @@ func (m *Manager) checkEscalations() {
 		return
 	}
 
+	escalation := m.config.Schedule.Escalation
 	now := time.Now()
 	for _, alert := range m.activeAlerts {
-		// Skip acknowledged alerts
+		// Escalation stops once someone has acknowledged the alert, and pauses while silenced
 		if alert.Acknowledged {
 			continue
 		}
+		if _, silenced := m.silenceForAlert(alert, now); silenced {
+			continue
+		}
+
+		if policy := escalation.PolicyFor(alert); policy != nil {
+			m.escalatePolicy(alert, escalation, policy, now)
+			continue
+		}
 
 		// Check each escalation level
-		for i, level := range m.config.Schedule.Escalation.Levels {
+		for i, level := range escalation.Levels {
 			// Skip if we've already escalated to this level
 			if alert.LastEscalation >= i+1 {
 				continue
@@ -6680,9 +6831,12 @@ func (m *Manager) checkEscalations() {
 			// Check if it's time to escalate
 			escalateTime := alert.StartTime.Add(time.Duration(level.After) * time.Minute)
 			if now.After(escalateTime) {
-				// Update alert escalation state
+				// Update alert escalation state. Drop the target of a policy that no longer
+				// applies, so the level's own destination is notified.
 				alert.LastEscalation = i + 1
 				alert.EscalationTimes = append(alert.EscalationTimes, now)
+				alert.EscalationPolicy = ""
+				alert.EscalationTarget = ""
 
 				log.Info().
 					Str("alertID", alert.ID).
@@ -6697,6 +6851,37 @@ func (m *Manager) checkEscalations() {
 	}
 }
 
+// escalatePolicy advances an alert through the levels of a named escalation policy.
+// Callers must hold m.mu.
+func (m *Manager) escalatePolicy(alert *Alert, escalation EscalationConfig, policy *EscalationPolicy, now time.Time) {
+	for i, level := range policy.Levels {
+		if alert.LastEscalation >= i+1 {
+			continue
+		}
+
+		escalateTime := alert.StartTime.Add(time.Duration(level.After) * time.Minute)
+		if !now.After(escalateTime) {
+			// Levels are ordered by delay, later levels cannot be due yet
+			return
+		}
+
+		target := escalation.ResolveLevel(level, now)
+		alert.LastEscalation = i + 1
+		alert.EscalationTimes = append(alert.EscalationTimes, now)
+		alert.EscalationPolicy = policy.ID
+		alert.EscalationTarget = target
+
+		log.Info().
+			Str("alertID", alert.ID).
+			Str("policy", policy.ID).
+			Int("level", i+1).
+			Str("target", target).
+			Msg("Alert escalated")
+
+		m.safeCallEscalateCallback(alert, i+1)
+	}
+}
+
 // Stop stops the alert manager and saves history
 func (m *Manager) Stop() {
 	close(m.escalationStop)
@@ -6705,10 +6890,41 @@ func (m *Manager) Stop() {
 	// Give background goroutines time to exit cleanly
 	time.Sleep(100 * time.Millisecond)
 
+	if m.readOnly.Load() {
+		return
+	}
+
 	// Save active alerts before stopping
 	if err := m.SaveActiveAlerts(); err != nil {
 		log.Error().Err(err).Msg("Failed to save active alerts on stop")
 	}
+	if err := m.SaveAnomalyBaselines(); err != nil {
+		log.Error().Err(err).Msg("Failed to save anomaly baselines on stop")
+	}
+	if err := m.SaveSilences(); err != nil {
+		log.Error().Err(err).Msg("Failed to save alert silences on stop")
+	}
+}
+
+// PersistState writes active alerts, silences, alert history and anomaly baselines to disk without
+// stopping the manager, so a copy of the data directory is current
+func (m *Manager) PersistState() error {
+	if err := m.SaveActiveAlerts(); err != nil {
+		return err
+	}
+	if err := m.SaveSilences(); err != nil {
+		return err
+	}
+	if err := m.historyManager.saveHistory(); err != nil {
+		return err
+	}
+	return m.SaveAnomalyBaselines()
+}
+
+// SetReadOnly turns off the periodic and shutdown saves of alert state and history
+func (m *Manager) SetReadOnly(readOnly bool) {
+	m.readOnly.Store(readOnly)
+	m.historyManager.readOnly.Store(readOnly)
 }
 
 // SaveActiveAlerts persists active alerts to disk
@@ -6931,13 +7147,26 @@ func (m *Manager) ClearActiveAlerts() {
 func (m *Manager) periodicSaveAlerts() {
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
+	// Baselines only change meaningfully once an hour
+	baselineTicker := time.NewTicker(1 * time.Hour)
+	defer baselineTicker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
+			if m.readOnly.Load() {
+				continue
+			}
 			if err := m.SaveActiveAlerts(); err != nil {
 				log.Error().Err(err).Msg("Failed to save active alerts during periodic save")
 			}
+		case <-baselineTicker.C:
+			if m.readOnly.Load() {
+				continue
+			}
+			if err := m.SaveAnomalyBaselines(); err != nil {
+				log.Error().Err(err).Msg("Failed to save anomaly baselines during periodic save")
+			}
 		case <-m.escalationStop:
 			return
 		}
@@ -479,6 +479,9 @@ func TestCheckBackupsCreatesAndClearsAlerts(t *testing.T) {
 	if alert.Level != AlertLevelCritical {
 		t.Fatalf("expected critical backup alert, got %s", alert.Level)
 	}
+	if vmid, _ := alert.Metadata["vmid"].(int); vmid != 100 || alert.Metadata["guestType"] != "qemu" {
+		t.Fatalf("expected guest identity in backup alert metadata, got %v", alert.Metadata)
+	}
 
 	// Recent backup clears alert
 	storageBackups[0].Time = now
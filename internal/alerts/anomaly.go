@@ -0,0 +1,178 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+const anomalyAlertPrefix = "anomaly-"
+
+// anomalyMetric is one observed value checked against its learned baseline. CPU is a
+// percentage; network and disk IO are MB/s.
+type anomalyMetric struct {
+	name  string
+	value float64
+}
+
+var anomalyMetricLabels = map[string]string{
+	"cpu":        "CPU",
+	"networkIn":  "network in",
+	"networkOut": "network out",
+	"diskRead":   "disk read",
+	"diskWrite":  "disk write",
+}
+
+// isBaselineAnomalyAlert reports whether an alert was raised by the guest/node baseline engine,
+// as opposed to PMG mail anomalies
+func isBaselineAnomalyAlert(alert *Alert) bool {
+	if alert == nil || !strings.HasPrefix(alert.Type, anomalyAlertPrefix) {
+		return false
+	}
+	_, ok := anomalyMetricLabels[strings.TrimPrefix(alert.Type, anomalyAlertPrefix)]
+	return ok
+}
+
+// CheckNodeIO checks a node's network and disk throughput against its baseline. Nodes do not
+// report IO counters, so the rates are the totals of the node's running guests in bytes/s.
+func (m *Manager) CheckNodeIO(node models.Node, netIn, netOut, diskRead, diskWrite float64) {
+	m.mu.RLock()
+	skip := !m.config.Enabled || m.config.DisableAllNodes || node.Status == "offline"
+	if override, exists := m.config.Overrides[node.ID]; exists && override.Disabled {
+		skip = true
+	}
+	m.mu.RUnlock()
+	if skip {
+		return
+	}
+
+	m.checkAnomalies(node.ID, node.Name, node.Name, node.Instance, "Node", []anomalyMetric{
+		{"networkIn", netIn / 1024 / 1024},
+		{"networkOut", netOut / 1024 / 1024},
+		{"diskRead", diskRead / 1024 / 1024},
+		{"diskWrite", diskWrite / 1024 / 1024},
+	})
+}
+
+// checkAnomalies feeds the metrics into their baselines and raises an alert for each metric
+// that stays above its baseline by both AnomalyDefaults ratio and absolute margin for
+// ConfirmMinutes
+func (m *Manager) checkAnomalies(resourceID, name, node, instance, resourceType string, metrics []anomalyMetric) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg := m.config.AnomalyDefaults
+	for _, metric := range metrics {
+		key := resourceID + ":" + metric.name
+		alertID := fmt.Sprintf("%s-anomaly-%s", resourceID, metric.name)
+		m.baselines.observe(key, metric.value, now)
+
+		estimate, ok := m.baselines.estimate(key, now)
+		if !cfg.Enabled || !ok {
+			delete(m.anomalyPending, alertID)
+			if _, exists := m.activeAlerts[alertID]; exists {
+				m.clearAlertNoLock(alertID)
+			}
+			continue
+		}
+
+		warning, critical := anomalyThresholds(cfg, metric.name, estimate.Value)
+		_, active := m.activeAlerts[alertID]
+		trigger := warning
+		if active {
+			// Hysteresis: an active anomaly clears a fifth of the way back towards the baseline
+			trigger = estimate.Value + 0.8*(warning-estimate.Value)
+		}
+		if metric.value < trigger {
+			delete(m.anomalyPending, alertID)
+			if active {
+				m.clearAlertNoLock(alertID)
+			}
+			continue
+		}
+
+		if !active {
+			firstSeen, pending := m.anomalyPending[alertID]
+			if !pending {
+				m.anomalyPending[alertID] = now
+				firstSeen = now
+			}
+			if now.Sub(firstSeen) < time.Duration(cfg.ConfirmMinutes)*time.Minute {
+				continue
+			}
+			delete(m.anomalyPending, alertID)
+		}
+
+		level := AlertLevelWarning
+		if metric.value >= critical {
+			level = AlertLevelCritical
+		}
+		ratio := metric.value / math.Max(estimate.Value, 0.01)
+
+		m.raiseStateAlertLocked(&Alert{
+			ID:           alertID,
+			Type:         anomalyAlertPrefix + metric.name,
+			Level:        level,
+			ResourceID:   resourceID,
+			ResourceName: name,
+			Node:         node,
+			Instance:     instance,
+			Message: fmt.Sprintf("%s %s %s at %s, usually %s %s",
+				resourceType, name, anomalyMetricLabels[metric.name], formatAnomalyValue(metric.name, metric.value),
+				formatAnomalyValue(metric.name, estimate.Value), formatBaselineTime(estimate.Seasonality, now)),
+			Value:     metric.value,
+			Threshold: warning,
+			Metadata: map[string]interface{}{
+				"resourceType":    resourceType,
+				"baseline":        estimate.Value,
+				"seasonality":     estimate.Seasonality,
+				"baselineSamples": estimate.Samples,
+				"ratio":           ratio,
+			},
+		}, now)
+	}
+}
+
+// anomalyThresholds returns the warning and critical values for a metric with the given baseline.
+// Both a multiple of the baseline and an absolute margin above it must be exceeded, so idle
+// resources are not flagged for small absolute changes.
+func anomalyThresholds(cfg AnomalyAlertConfig, metric string, baseline float64) (warning, critical float64) {
+	margin := cfg.MinIODelta
+	if metric == "cpu" {
+		margin = cfg.MinCPUDelta
+	}
+	warning = math.Max(baseline*cfg.WarningRatio, baseline+margin)
+	critical = math.Max(baseline*cfg.CriticalRatio, baseline+2*margin)
+	return warning, critical
+}
+
+func formatAnomalyValue(metric string, value float64) string {
+	if metric == "cpu" {
+		return fmt.Sprintf("%.1f%%", value)
+	}
+	return fmt.Sprintf("%.1f MB/s", value)
+}
+
+func formatBaselineTime(seasonality string, now time.Time) string {
+	local := now.Local()
+	hour := fmt.Sprintf("%02d:00", local.Hour())
+	if seasonality == BaselineWeekly {
+		return "on " + local.Weekday().String() + "s around " + hour
+	}
+	return "around " + hour
+}
+
+// clearBaselineAnomalyAlertsLocked clears all guest and node anomaly alerts (must be called with lock held)
+func (m *Manager) clearBaselineAnomalyAlertsLocked() {
+	for alertID, alert := range m.activeAlerts {
+		if isBaselineAnomalyAlert(alert) {
+			m.clearAlertNoLock(alertID)
+		}
+	}
+	m.anomalyPending = make(map[string]time.Time)
+}
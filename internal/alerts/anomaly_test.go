@@ -0,0 +1,107 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+// seedBaseline fills every hour of the week with the same value
+func seedBaseline(m *Manager, key string, value float64) {
+	series := &baselineSeries{LastSeen: time.Now()}
+	for slot := range series.Slots {
+		series.Slots[slot] = []float64{value, value, value, value}
+	}
+	m.baselines.series[key] = series
+}
+
+func TestCheckAnomaliesAgainstBaseline(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.config.AnomalyDefaults = AnomalyAlertConfig{
+		Enabled: true, WarningRatio: 3, CriticalRatio: 6, MinCPUDelta: 40, MinIODelta: 50, ConfirmMinutes: 0,
+	}
+	m.baselines = newBaselineEngine()
+	m.mu.Unlock()
+	seedBaseline(m, "vm-101:networkOut", 2)
+	seedBaseline(m, "vm-101:cpu", 5)
+
+	alertID := "vm-101-anomaly-networkOut"
+	check := func(netOut, cpu float64) {
+		m.checkAnomalies("vm-101", "idle-vm", "pve1", "pve", "VM", []anomalyMetric{{"networkOut", netOut}, {"cpu", cpu}})
+	}
+
+	// Three times the baseline, but within the absolute margin
+	check(8, 20)
+	m.mu.RLock()
+	if len(m.activeAlerts) != 0 {
+		t.Errorf("expected small absolute changes to be ignored, got %d alerts", len(m.activeAlerts))
+	}
+	m.mu.RUnlock()
+
+	// A normally idle VM pushing 500 MB/s
+	check(500, 20)
+	m.mu.RLock()
+	alert, ok := m.activeAlerts[alertID]
+	if !ok || alert.Level != AlertLevelCritical || alert.Type != "anomaly-networkOut" {
+		t.Fatalf("expected critical network anomaly, got %+v", alert)
+	}
+	if alert.Metadata["seasonality"] != BaselineWeekly {
+		t.Errorf("expected day-of-week baseline, got %v", alert.Metadata["seasonality"])
+	}
+	m.mu.RUnlock()
+
+	// Hysteresis keeps the alert just below the trigger, then it clears
+	check(45, 20)
+	m.mu.RLock()
+	if alert, ok := m.activeAlerts[alertID]; !ok || alert.Level != AlertLevelWarning {
+		t.Errorf("expected anomaly to downgrade to warning within hysteresis, got %+v", alert)
+	}
+	m.mu.RUnlock()
+	check(3, 20)
+	m.mu.RLock()
+	if _, ok := m.activeAlerts[alertID]; ok {
+		t.Error("expected anomaly to clear once back at the baseline")
+	}
+	m.mu.RUnlock()
+}
+
+func TestCheckAnomaliesRequiresConfirmation(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.config.AnomalyDefaults.Enabled = true
+	m.config.AnomalyDefaults.ConfirmMinutes = 5
+	m.baselines = newBaselineEngine()
+	m.mu.Unlock()
+	seedBaseline(m, "node-pve1:cpu", 5)
+
+	m.checkAnomalies("node-pve1", "pve1", "pve1", "pve", "Node", []anomalyMetric{{"cpu", 95}})
+	m.mu.Lock()
+	if len(m.activeAlerts) != 0 {
+		t.Fatalf("expected a spike to wait for confirmation, got %d alerts", len(m.activeAlerts))
+	}
+	m.anomalyPending["node-pve1-anomaly-cpu"] = time.Now().Add(-6 * time.Minute)
+	m.mu.Unlock()
+
+	m.checkAnomalies("node-pve1", "pve1", "pve1", "pve", "Node", []anomalyMetric{{"cpu", 95}})
+	m.mu.RLock()
+	_, ok := m.activeAlerts["node-pve1-anomaly-cpu"]
+	m.mu.RUnlock()
+	if !ok {
+		t.Fatal("expected sustained anomaly to alert")
+	}
+
+	cfg := m.GetConfig()
+	cfg.AnomalyDefaults.Enabled = false
+	m.UpdateConfig(cfg)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.activeAlerts) != 0 {
+		t.Errorf("expected anomaly alerts to clear when disabled, got %d", len(m.activeAlerts))
+	}
+}
@@ -0,0 +1,243 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// baselineSlots covers every hour of the week
+	baselineSlots = 7 * 24
+	// baselineWeeks is how many hourly averages each slot keeps
+	baselineWeeks = 4
+	// baselineWeeklyMinSamples is the history needed for a day-of-week baseline: the slot and
+	// its neighbouring hours over three weeks
+	baselineWeeklyMinSamples = 9
+	// baselineDailyMinSamples is the history needed for a time-of-day baseline: the hour and
+	// its neighbours over four days
+	baselineDailyMinSamples = 12
+	// baselineRetention drops series that have not been observed for this long
+	baselineRetention = baselineWeeks * 7 * 24 * time.Hour
+	baselineFile      = "anomaly-baselines.json"
+)
+
+// Baseline seasonality used for an estimate
+const (
+	BaselineWeekly = "day-of-week"
+	BaselineDaily  = "time-of-day"
+)
+
+// baselineSeries is the learned history of one metric. Observations are averaged per hour and
+// each finished hour is stored in its hour-of-week slot, keeping the last baselineWeeks values.
+type baselineSeries struct {
+	Slots    [baselineSlots][]float64 `json:"slots"`
+	Hour     int64                    `json:"hour"` // Unix hour being accumulated
+	Sum      float64                  `json:"sum"`
+	Count    int                      `json:"count"`
+	LastSeen time.Time                `json:"lastSeen"`
+}
+
+// baselineEstimate is the expected value of a metric at a point in time
+type baselineEstimate struct {
+	Value       float64
+	Seasonality string
+	Samples     int
+}
+
+// baselineEngine learns per-metric baselines with time-of-day and day-of-week seasonality.
+// It is not safe for concurrent use; the Manager guards it with its lock.
+type baselineEngine struct {
+	series map[string]*baselineSeries
+}
+
+func newBaselineEngine() *baselineEngine {
+	return &baselineEngine{series: make(map[string]*baselineSeries)}
+}
+
+// baselineSlot returns the hour-of-week slot of a time in the server's local time zone
+func baselineSlot(ts time.Time) int {
+	local := ts.Local()
+	return int(local.Weekday())*24 + local.Hour()
+}
+
+// observe records a value for the metric identified by key
+func (e *baselineEngine) observe(key string, value float64, ts time.Time) {
+	series := e.series[key]
+	if series == nil {
+		series = &baselineSeries{}
+		e.series[key] = series
+	}
+
+	hour := ts.Unix() / 3600
+	if hour != series.Hour {
+		series.flush()
+		series.Hour = hour
+	}
+	series.Sum += value
+	series.Count++
+	series.LastSeen = ts
+}
+
+// flush stores the average of the accumulated hour in its slot
+func (s *baselineSeries) flush() {
+	if s.Count == 0 {
+		return
+	}
+	slot := baselineSlot(time.Unix(s.Hour*3600, 0))
+	s.Slots[slot] = append(s.Slots[slot], s.Sum/float64(s.Count))
+	if len(s.Slots[slot]) > baselineWeeks {
+		s.Slots[slot] = s.Slots[slot][len(s.Slots[slot])-baselineWeeks:]
+	}
+	s.Sum, s.Count = 0, 0
+}
+
+// estimate returns the baseline of the metric at ts. The same hour on the same weekday is
+// preferred; until enough weeks are recorded the same hour across all days is used. Neighbouring
+// hours are included so a job that shifts by a few minutes stays within its baseline.
+func (e *baselineEngine) estimate(key string, ts time.Time) (baselineEstimate, bool) {
+	series := e.series[key]
+	if series == nil {
+		return baselineEstimate{}, false
+	}
+
+	slot := baselineSlot(ts)
+	var weekly []float64
+	for offset := -1; offset <= 1; offset++ {
+		weekly = append(weekly, series.Slots[(slot+offset+baselineSlots)%baselineSlots]...)
+	}
+	if value, ok := trimmedBaseline(weekly, baselineWeeklyMinSamples); ok {
+		return baselineEstimate{Value: value, Seasonality: BaselineWeekly, Samples: len(weekly)}, true
+	}
+
+	hour := slot % 24
+	var daily []float64
+	for day := 0; day < 7; day++ {
+		for offset := -1; offset <= 1; offset++ {
+			daily = append(daily, series.Slots[(day*24+hour+offset+baselineSlots)%baselineSlots]...)
+		}
+	}
+	if value, ok := trimmedBaseline(daily, baselineDailyMinSamples); ok {
+		return baselineEstimate{Value: value, Seasonality: BaselineDaily, Samples: len(daily)}, true
+	}
+	return baselineEstimate{}, false
+}
+
+// prune drops series that have not been observed since the cutoff
+func (e *baselineEngine) prune(cutoff time.Time) {
+	for key, series := range e.series {
+		if series.LastSeen.Before(cutoff) {
+			delete(e.series, key)
+		}
+	}
+}
+
+// trimmedBaseline computes a robust baseline: a trimmed mean that falls back to the median when
+// outliers pull the mean far away from it, or a plain mean for short histories
+func trimmedBaseline(samples []float64, minSamples int) (float64, bool) {
+	if len(samples) == 0 || len(samples) < minSamples {
+		return 0, false
+	}
+	if len(samples) < 24 {
+		sum := 0.0
+		for _, value := range samples {
+			sum += value
+		}
+		return sum / float64(len(samples)), true
+	}
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	var median float64
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		median = sorted[mid]
+	}
+
+	// Drop the top and bottom 1/12th, i.e. 2 of 24 samples
+	trim := len(sorted) / 12
+	trimmed := sorted[trim : len(sorted)-trim]
+	sum := 0.0
+	for _, value := range trimmed {
+		sum += value
+	}
+	trimmedMean := sum / float64(len(trimmed))
+
+	diff := trimmedMean - median
+	if diff < 0 {
+		diff = -diff
+	}
+	if median == 0 {
+		if diff > 0 {
+			return median, true
+		}
+		return trimmedMean, true
+	}
+	if diff/median*100 > 40 {
+		return median, true
+	}
+	return trimmedMean, true
+}
+
+func baselinePath() string {
+	return filepath.Join(utils.GetDataDir(), "alerts", baselineFile)
+}
+
+// SaveAnomalyBaselines persists the learned baselines so they survive restarts
+func (m *Manager) SaveAnomalyBaselines() error {
+	m.mu.RLock()
+	data, err := json.Marshal(m.baselines.series)
+	m.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal anomaly baselines: %w", err)
+	}
+
+	path := baselinePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create alerts directory: %w", err)
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write anomaly baselines: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("failed to rename anomaly baselines file: %w", err)
+	}
+	return nil
+}
+
+// LoadAnomalyBaselines restores the learned baselines from disk
+func (m *Manager) LoadAnomalyBaselines() error {
+	data, err := os.ReadFile(baselinePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read anomaly baselines: %w", err)
+	}
+
+	series := make(map[string]*baselineSeries)
+	if err := json.Unmarshal(data, &series); err != nil {
+		return fmt.Errorf("failed to unmarshal anomaly baselines: %w", err)
+	}
+	for key, entry := range series {
+		if entry == nil {
+			delete(series, key)
+		}
+	}
+
+	m.mu.Lock()
+	m.baselines.series = series
+	m.mu.Unlock()
+	log.Info().Int("series", len(series)).Msg("Loaded anomaly baselines")
+	return nil
+}
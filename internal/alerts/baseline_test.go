@@ -0,0 +1,97 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBaselineEngineAveragesHours(t *testing.T) {
+	engine := newBaselineEngine()
+	start := time.Date(2026, 10, 5, 10, 0, 0, 0, time.Local) // Monday
+
+	engine.observe("vm:cpu", 10, start)
+	engine.observe("vm:cpu", 30, start.Add(30*time.Minute))
+	// The next hour flushes the average of the first one
+	engine.observe("vm:cpu", 90, start.Add(time.Hour))
+
+	series := engine.series["vm:cpu"]
+	slot := baselineSlot(start)
+	if len(series.Slots[slot]) != 1 || series.Slots[slot][0] != 20 {
+		t.Fatalf("expected hourly average 20 in slot %d, got %v", slot, series.Slots[slot])
+	}
+	if len(series.Slots[slot+1]) != 0 {
+		t.Errorf("expected the current hour to stay unflushed, got %v", series.Slots[slot+1])
+	}
+}
+
+func TestBaselineEngineSeasonality(t *testing.T) {
+	engine := newBaselineEngine()
+	start := time.Date(2026, 9, 7, 0, 0, 0, 0, time.Local) // Monday
+
+	// Busy from 02:00 to 04:00 every night, busier still on Sundays
+	record := func(fromDay, toDay int) {
+		for hour := fromDay * 24; hour < toDay*24; hour++ {
+			ts := start.Add(time.Duration(hour) * time.Hour)
+			value := 5.0
+			if ts.Hour() >= 2 && ts.Hour() <= 4 {
+				value = 200
+				if ts.Weekday() == time.Sunday {
+					value = 400
+				}
+			}
+			engine.observe("vm:diskWrite", value, ts)
+		}
+	}
+
+	record(0, 3)
+	if _, ok := engine.estimate("vm:diskWrite", start.Add(3*24*time.Hour+3*time.Hour)); ok {
+		t.Fatal("expected no baseline after three days")
+	}
+
+	record(3, 5)
+	estimate, ok := engine.estimate("vm:diskWrite", start.Add(5*24*time.Hour+3*time.Hour))
+	if !ok || estimate.Seasonality != BaselineDaily {
+		t.Fatalf("expected a time-of-day baseline after five days, got %+v", estimate)
+	}
+	if estimate.Value < 150 {
+		t.Errorf("expected the nightly job to be part of the 03:00 baseline, got %.1f", estimate.Value)
+	}
+	if midday, _ := engine.estimate("vm:diskWrite", start.Add(5*24*time.Hour+12*time.Hour)); midday.Value != 5 {
+		t.Errorf("expected an idle midday baseline, got %.1f", midday.Value)
+	}
+
+	engine = newBaselineEngine()
+	record(0, 22)
+	sunday := start.Add(20*24*time.Hour + 3*time.Hour)
+	estimate, ok = engine.estimate("vm:diskWrite", sunday)
+	if !ok || estimate.Seasonality != BaselineWeekly {
+		t.Fatalf("expected a day-of-week baseline after three weeks, got %+v", estimate)
+	}
+	if estimate.Value < 300 {
+		t.Errorf("expected the Sunday baseline to reflect the heavier Sunday job, got %.1f", estimate.Value)
+	}
+
+	engine.prune(sunday.Add(baselineRetention + 24*time.Hour))
+	if len(engine.series) != 0 {
+		t.Error("expected unobserved series to be pruned")
+	}
+}
+
+func TestTrimmedBaseline(t *testing.T) {
+	if _, ok := trimmedBaseline([]float64{1, 2, 3}, 4); ok {
+		t.Error("expected too few samples to be untrustworthy")
+	}
+	if value, ok := trimmedBaseline([]float64{1, 2, 3, 6}, 4); !ok || value != 3 {
+		t.Errorf("expected mean of short history, got %.1f", value)
+	}
+
+	// Outliers are trimmed away from a full history
+	samples := make([]float64, 24)
+	for i := range samples {
+		samples[i] = 10
+	}
+	samples[0], samples[1] = 1000, 900
+	if value, ok := trimmedBaseline(samples, 12); !ok || value != 10 {
+		t.Errorf("expected outliers to be trimmed, got %.1f", value)
+	}
+}
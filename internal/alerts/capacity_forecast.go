@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+const capacityForecastAlertType = "capacity-forecast"
+
+// CheckCapacityForecasts raises "predicted full" alerts for resources projected to run out of
+// capacity within ForecastDefaults.WarningDays with enough confidence. The forecasts are the
+// complete current set; forecast alerts for resources no longer at risk are cleared.
+func (m *Manager) CheckCapacityForecasts(forecasts []models.CapacityForecast) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	valid := make(map[string]bool)
+	cfg := m.config.ForecastDefaults
+	if m.config.Enabled && cfg.Enabled {
+		now := time.Now()
+		for _, forecast := range forecasts {
+			if forecast.DaysRemaining == nil || forecast.Confidence < cfg.MinConfidence {
+				continue
+			}
+			days := *forecast.DaysRemaining
+			if days > float64(cfg.WarningDays) {
+				continue
+			}
+
+			level := AlertLevelWarning
+			if days <= float64(cfg.CriticalDays) {
+				level = AlertLevelCritical
+			}
+			alertID := fmt.Sprintf("%s-%s", capacityForecastAlertType, sanitizeAlertKey(forecast.ID))
+			valid[alertID] = true
+
+			m.raiseStateAlertLocked(&Alert{
+				ID:           alertID,
+				Type:         capacityForecastAlertType,
+				Level:        level,
+				ResourceID:   forecast.ResourceID,
+				ResourceName: forecast.Name,
+				Node:         forecast.Node,
+				Instance:     forecast.Instance,
+				Message: fmt.Sprintf("%s %s is predicted to be full in %s (%.0f%% used, %.0f%% confidence)",
+					forecastKindLabel(forecast.Kind), forecast.Name, formatForecastDays(days), forecast.Usage, forecast.Confidence*100),
+				Value:     days,
+				Threshold: float64(cfg.WarningDays),
+				Metadata: map[string]interface{}{
+					"kind":         forecast.Kind,
+					"model":        forecast.Model,
+					"usage":        forecast.Usage,
+					"growthPerDay": forecast.GrowthPerDay,
+					"exhaustionAt": forecast.ExhaustionAt,
+					"confidence":   forecast.Confidence,
+				},
+			}, now)
+		}
+	}
+
+	for alertID, alert := range m.activeAlerts {
+		if alert != nil && alert.Type == capacityForecastAlertType && !valid[alertID] {
+			m.clearAlertNoLock(alertID)
+		}
+	}
+}
+
+func forecastKindLabel(kind string) string {
+	switch kind {
+	case models.ForecastNodeMemory:
+		return "Memory of node"
+	case models.ForecastPBSDatastore:
+		return "PBS datastore"
+	case models.ForecastCephPool:
+		return "Ceph pool"
+	default:
+		return "Storage"
+	}
+}
+
+func formatForecastDays(days float64) string {
+	switch {
+	case days < 1:
+		return "less than a day"
+	case days < 1.5:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", int(math.Round(days)))
+	}
+}
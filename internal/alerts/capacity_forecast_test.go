@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func forecastWithin(id string, days, confidence float64) models.CapacityForecast {
+	return models.CapacityForecast{
+		ID: models.ForecastStorage + ":" + id, Kind: models.ForecastStorage, ResourceID: id, Name: id,
+		Instance: "pve", Node: "pve1", Usage: 80, DaysRemaining: &days, Confidence: confidence,
+	}
+}
+
+func TestCheckCapacityForecastsRaisesAndClearsAlerts(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.config.ForecastDefaults = ForecastAlertConfig{Enabled: true, WarningDays: 30, CriticalDays: 7, MinConfidence: 0.6}
+	m.mu.Unlock()
+
+	soon := forecastWithin("pve-pve1-local", 3, 0.9)
+	later := forecastWithin("pve-pve1-data", 20, 0.9)
+	distant := forecastWithin("pve-pve1-backup", 90, 0.9)
+	uncertain := forecastWithin("pve-pve1-scratch", 2, 0.3)
+	m.CheckCapacityForecasts([]models.CapacityForecast{soon, later, distant, uncertain})
+
+	soonID := capacityForecastAlertType + "-" + sanitizeAlertKey(soon.ID)
+	laterID := capacityForecastAlertType + "-" + sanitizeAlertKey(later.ID)
+	m.mu.RLock()
+	if alert, ok := m.activeAlerts[soonID]; !ok || alert.Level != AlertLevelCritical {
+		t.Errorf("expected critical alert for storage full in 3 days, got %+v", alert)
+	}
+	if alert, ok := m.activeAlerts[laterID]; !ok || alert.Level != AlertLevelWarning {
+		t.Errorf("expected warning alert for storage full in 20 days, got %+v", alert)
+	}
+	if len(m.activeAlerts) != 2 {
+		t.Errorf("expected distant and low-confidence forecasts to be ignored, got %d alerts", len(m.activeAlerts))
+	}
+	m.mu.RUnlock()
+
+	// The growth stopped on one storage
+	m.CheckCapacityForecasts([]models.CapacityForecast{soon})
+	m.mu.RLock()
+	if _, ok := m.activeAlerts[laterID]; ok {
+		t.Error("expected alert to clear once the forecast no longer predicts exhaustion")
+	}
+	m.mu.RUnlock()
+
+	m.mu.Lock()
+	m.config.ForecastDefaults.Enabled = false
+	m.mu.Unlock()
+	m.CheckCapacityForecasts([]models.CapacityForecast{soon})
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.activeAlerts) != 0 {
+		t.Errorf("expected disabled forecast alerts to clear, got %d", len(m.activeAlerts))
+	}
+}
@@ -0,0 +1,187 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Cluster quorum and HA alert types
+const (
+	clusterQuorumAlertType = "cluster-quorum"
+	haResourceAlertType    = "ha-resource"
+	haLRMAlertType         = "ha-lrm"
+)
+
+// haAlertStates are HA resource states that need an operator: error needs a manual
+// disable/enable cycle, fence and recovery mean the owning node was lost.
+var haAlertStates = map[string]bool{
+	"error":    true,
+	"fence":    true,
+	"recovery": true,
+}
+
+// CheckClusterHA raises alerts for lost quorum, HA resources in error or fence state and
+// dead local resource managers, and clears the ones that no longer apply.
+func (m *Manager) CheckClusterHA(status models.ClusterHAStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.config.Enabled {
+		m.clearClusterHAAlertsLocked(status.Instance)
+		return
+	}
+
+	now := time.Now()
+	valid := make(map[string]bool)
+	clusterName := status.ClusterName
+	if clusterName == "" {
+		clusterName = status.Instance
+	}
+
+	if !status.Quorate {
+		alertID := fmt.Sprintf("%s-%s", clusterQuorumAlertType, sanitizeAlertKey(status.Instance))
+		valid[alertID] = true
+
+		online := 0
+		for _, member := range status.Members {
+			if member.Online {
+				online++
+			}
+		}
+		m.raiseStateAlertLocked(&Alert{
+			ID:           alertID,
+			Type:         clusterQuorumAlertType,
+			Level:        AlertLevelCritical,
+			ResourceID:   status.ID,
+			ResourceName: fmt.Sprintf("Cluster %s", clusterName),
+			Instance:     status.Instance,
+			Message: fmt.Sprintf("Cluster %s has lost quorum: %d of %d expected votes present (%d needed), %d of %d nodes online",
+				clusterName, status.TotalVotes, status.ExpectedVotes, status.QuorumThreshold, online, len(status.Members)),
+			Value:     float64(status.TotalVotes),
+			Threshold: float64(status.QuorumThreshold),
+			Metadata: map[string]interface{}{
+				"expectedVotes":   status.ExpectedVotes,
+				"totalVotes":      status.TotalVotes,
+				"quorumThreshold": status.QuorumThreshold,
+			},
+		}, now)
+	}
+
+	for _, resource := range status.Resources {
+		state := strings.ToLower(resource.State)
+		if !haAlertStates[state] {
+			continue
+		}
+		alertID := fmt.Sprintf("%s-%s-%s", haResourceAlertType, sanitizeAlertKey(status.Instance), sanitizeAlertKey(resource.SID))
+		valid[alertID] = true
+
+		m.raiseStateAlertLocked(&Alert{
+			ID:           alertID,
+			Type:         haResourceAlertType,
+			Level:        AlertLevelCritical,
+			ResourceID:   fmt.Sprintf("%s-%s", status.Instance, resource.SID),
+			ResourceName: fmt.Sprintf("HA resource %s", resource.SID),
+			Node:         resource.Node,
+			Instance:     status.Instance,
+			Message:      fmt.Sprintf("HA resource %s on %s is in %s state (requested: %s)", resource.SID, resource.Node, state, resource.RequestState),
+			Metadata: map[string]interface{}{
+				"sid":          resource.SID,
+				"vmid":         resource.VMID,
+				"haState":      state,
+				"requestState": resource.RequestState,
+			},
+		}, now)
+	}
+
+	// A dead LRM only matters when the cluster actually runs HA resources
+	if len(status.Resources) > 0 {
+		for _, lrm := range status.LRMs {
+			if !lrm.Dead {
+				continue
+			}
+			alertID := fmt.Sprintf("%s-%s-%s", haLRMAlertType, sanitizeAlertKey(status.Instance), sanitizeAlertKey(lrm.Node))
+			valid[alertID] = true
+
+			m.raiseStateAlertLocked(&Alert{
+				ID:           alertID,
+				Type:         haLRMAlertType,
+				Level:        AlertLevelCritical,
+				ResourceID:   fmt.Sprintf("%s-%s", status.Instance, lrm.Node),
+				ResourceName: fmt.Sprintf("HA LRM on %s", lrm.Node),
+				Node:         lrm.Node,
+				Instance:     status.Instance,
+				Message:      fmt.Sprintf("HA local resource manager on %s is dead (%s)", lrm.Node, lrm.State),
+				Metadata: map[string]interface{}{
+					"lrmState": lrm.State,
+				},
+			}, now)
+		}
+	}
+
+	for alertID, alert := range m.activeAlerts {
+		if alert == nil || alert.Instance != status.Instance || valid[alertID] || !isClusterHAAlertType(alert.Type) {
+			continue
+		}
+		m.clearAlertNoLock(alertID)
+	}
+}
+
+// ClearClusterHAAlerts clears quorum and HA alerts for an instance that is no longer a cluster
+func (m *Manager) ClearClusterHAAlerts(instance string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clearClusterHAAlertsLocked(instance)
+}
+
+func (m *Manager) clearClusterHAAlertsLocked(instance string) {
+	for alertID, alert := range m.activeAlerts {
+		if alert == nil || alert.Instance != instance || !isClusterHAAlertType(alert.Type) {
+			continue
+		}
+		m.clearAlertNoLock(alertID)
+	}
+}
+
+func isClusterHAAlertType(alertType string) bool {
+	return alertType == clusterQuorumAlertType || alertType == haResourceAlertType || alertType == haLRMAlertType
+}
+
+// raiseStateAlertLocked creates or refreshes an alert that mirrors polled state rather than a
+// metric threshold (must be called with lock held)
+func (m *Manager) raiseStateAlertLocked(alert *Alert, now time.Time) {
+	if existing, exists := m.activeAlerts[alert.ID]; exists {
+		existing.LastSeen = now
+		existing.Level = alert.Level
+		existing.Node = alert.Node
+		existing.Message = alert.Message
+		existing.Value = alert.Value
+		existing.Threshold = alert.Threshold
+		existing.Metadata = alert.Metadata
+		return
+	}
+
+	alert.StartTime = now
+	alert.LastSeen = now
+
+	m.preserveAlertState(alert.ID, alert)
+
+	m.activeAlerts[alert.ID] = alert
+	m.recentAlerts[alert.ID] = alert
+	m.historyManager.AddAlert(*alert)
+
+	if m.dispatchAlert(alert, true) {
+		notified := now
+		alert.LastNotified = &notified
+	}
+
+	log.Error().
+		Str("alertID", alert.ID).
+		Str("type", alert.Type).
+		Str("instance", alert.Instance).
+		Str("message", alert.Message).
+		Msg("State alert created")
+}
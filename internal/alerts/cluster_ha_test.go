@@ -0,0 +1,91 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func TestCheckClusterHARaisesAndClearsAlerts(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.mu.Unlock()
+
+	status := models.ClusterHAStatus{
+		ID:              "pve-prod",
+		Instance:        "pve",
+		ClusterName:     "prod",
+		Quorate:         false,
+		ExpectedVotes:   3,
+		TotalVotes:      1,
+		QuorumThreshold: 2,
+		Members: []models.ClusterMember{
+			{Name: "pve1", Online: true, Votes: 1},
+			{Name: "pve2", Online: false, Votes: 1},
+			{Name: "pve3", Online: false, Votes: 1},
+		},
+		LRMs: []models.HALRMStatus{
+			{Node: "pve1", State: "active"},
+			{Node: "pve2", State: "old timestamp - dead?", Dead: true},
+		},
+		Resources: []models.HAResourceState{
+			{SID: "vm:100", Node: "pve2", State: "fence", RequestState: "started"},
+			{SID: "vm:101", Node: "pve1", State: "started", RequestState: "started"},
+		},
+	}
+
+	m.CheckClusterHA(status)
+
+	wantIDs := []string{"cluster-quorum-pve", "ha-resource-pve-" + sanitizeAlertKey("vm:100"), "ha-lrm-pve-pve2"}
+	m.mu.RLock()
+	for _, id := range wantIDs {
+		if _, ok := m.activeAlerts[id]; !ok {
+			t.Errorf("expected alert %s to be active", id)
+		}
+	}
+	if len(m.activeAlerts) != len(wantIDs) {
+		t.Errorf("expected %d alerts, got %d", len(wantIDs), len(m.activeAlerts))
+	}
+	m.mu.RUnlock()
+
+	// Quorum restored and the resource recovered: only the dead LRM remains
+	status.Quorate = true
+	status.TotalVotes = 2
+	status.Resources[0].State = "started"
+	m.CheckClusterHA(status)
+
+	m.mu.RLock()
+	_, lrmActive := m.activeAlerts["ha-lrm-pve-pve2"]
+	count := len(m.activeAlerts)
+	m.mu.RUnlock()
+	if !lrmActive || count != 1 {
+		t.Fatalf("expected only the LRM alert to remain, got %d alerts", count)
+	}
+
+	m.ClearClusterHAAlerts("pve")
+	if alerts := m.GetActiveAlerts(); len(alerts) != 0 {
+		t.Fatalf("expected cluster alerts to be cleared, got %d", len(alerts))
+	}
+}
+
+func TestCheckClusterHAIgnoresDeadLRMWithoutResources(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.mu.Unlock()
+
+	m.CheckClusterHA(models.ClusterHAStatus{
+		Instance: "pve",
+		Quorate:  true,
+		LRMs:     []models.HALRMStatus{{Node: "pve2", Dead: true}},
+	})
+
+	if alerts := m.GetActiveAlerts(); len(alerts) != 0 {
+		t.Fatalf("expected no alerts when HA has no resources, got %d", len(alerts))
+	}
+}
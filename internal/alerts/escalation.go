@@ -0,0 +1,328 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EscalationTarget is a named notification destination used by escalation policies
+type EscalationTarget struct {
+	Name     string   `json:"name"`
+	Emails   []string `json:"emails,omitempty"`   // Email recipients for this target
+	Webhooks []string `json:"webhooks,omitempty"` // IDs of configured webhooks to notify
+}
+
+// OnCallOverride temporarily replaces the primary on-call participant of a schedule
+type OnCallOverride struct {
+	Target string    `json:"target"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// OnCallSchedule rotates through participants with weekly handoffs
+type OnCallSchedule struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Participants  []string         `json:"participants"`  // Target names in rotation order
+	HandoffDay    string           `json:"handoffDay"`    // monday, tuesday, etc.
+	HandoffTime   string           `json:"handoffTime"`   // 24-hour format "HH:MM"
+	Timezone      string           `json:"timezone"`      // IANA timezone, defaults to UTC
+	RotationStart time.Time        `json:"rotationStart"` // The first participant is on call from the handoff at or before this time
+	Overrides     []OnCallOverride `json:"overrides,omitempty"`
+}
+
+// EscalationPolicyLevel is one step of an escalation policy. A level notifies either
+// whoever a schedule has on call at the given position or a fixed target.
+type EscalationPolicyLevel struct {
+	After    int    `json:"after"`              // minutes unacknowledged after initial alert
+	Schedule string `json:"schedule,omitempty"` // Schedule ID to resolve the on-call target from
+	Position int    `json:"position,omitempty"` // 0 = primary, 1 = secondary, ...
+	Target   string `json:"target,omitempty"`   // Fixed target name (e.g. a team channel)
+}
+
+// EscalationPolicy is a named, ordered list of escalation levels
+type EscalationPolicy struct {
+	ID     string                  `json:"id"`
+	Name   string                  `json:"name"`
+	Levels []EscalationPolicyLevel `json:"levels"`
+}
+
+// AlertRoute attaches an escalation policy to alerts matching its filters.
+// Empty filters match any alert.
+type AlertRoute struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Levels        []string `json:"levels,omitempty"`
+	Types         []string `json:"types,omitempty"`
+	Nodes         []string `json:"nodes,omitempty"`
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	Policy        string   `json:"policy"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Validate checks that all policies, schedules and routes reference existing entries
+func (c EscalationConfig) Validate() error {
+	targets := make(map[string]bool, len(c.Targets))
+	for _, target := range c.Targets {
+		if strings.TrimSpace(target.Name) == "" {
+			return fmt.Errorf("escalation target name is required")
+		}
+		if targets[target.Name] {
+			return fmt.Errorf("duplicate escalation target %q", target.Name)
+		}
+		targets[target.Name] = true
+	}
+
+	schedules := make(map[string]OnCallSchedule, len(c.Schedules))
+	for _, schedule := range c.Schedules {
+		if schedule.ID == "" {
+			return fmt.Errorf("on-call schedule %q is missing an id", schedule.Name)
+		}
+		if _, exists := schedules[schedule.ID]; exists {
+			return fmt.Errorf("duplicate on-call schedule %q", schedule.ID)
+		}
+		if len(schedule.Participants) == 0 {
+			return fmt.Errorf("on-call schedule %q has no participants", schedule.ID)
+		}
+		if _, _, _, err := schedule.handoff(); err != nil {
+			return fmt.Errorf("on-call schedule %q: %w", schedule.ID, err)
+		}
+		for _, participant := range schedule.Participants {
+			if !targets[participant] {
+				return fmt.Errorf("on-call schedule %q references unknown target %q", schedule.ID, participant)
+			}
+		}
+		for _, override := range schedule.Overrides {
+			if !targets[override.Target] {
+				return fmt.Errorf("on-call schedule %q override references unknown target %q", schedule.ID, override.Target)
+			}
+			if !override.End.After(override.Start) {
+				return fmt.Errorf("on-call schedule %q override for %q must end after it starts", schedule.ID, override.Target)
+			}
+		}
+		schedules[schedule.ID] = schedule
+	}
+
+	policies := make(map[string]bool, len(c.Policies))
+	for _, policy := range c.Policies {
+		if policy.ID == "" {
+			return fmt.Errorf("escalation policy %q is missing an id", policy.Name)
+		}
+		if policies[policy.ID] {
+			return fmt.Errorf("duplicate escalation policy %q", policy.ID)
+		}
+		for i, level := range policy.Levels {
+			switch {
+			case level.Schedule != "" && level.Target != "":
+				return fmt.Errorf("escalation policy %q level %d must use either a schedule or a target", policy.ID, i+1)
+			case level.Schedule != "":
+				if _, ok := schedules[level.Schedule]; !ok {
+					return fmt.Errorf("escalation policy %q level %d references unknown schedule %q", policy.ID, i+1, level.Schedule)
+				}
+				if level.Position < 0 {
+					return fmt.Errorf("escalation policy %q level %d has a negative position", policy.ID, i+1)
+				}
+			case level.Target != "":
+				if !targets[level.Target] {
+					return fmt.Errorf("escalation policy %q level %d references unknown target %q", policy.ID, i+1, level.Target)
+				}
+			default:
+				return fmt.Errorf("escalation policy %q level %d has no schedule or target", policy.ID, i+1)
+			}
+			if level.After < 0 || (i > 0 && level.After < policy.Levels[i-1].After) {
+				return fmt.Errorf("escalation policy %q levels must have non-decreasing delays", policy.ID)
+			}
+		}
+		policies[policy.ID] = true
+	}
+
+	for _, route := range c.Routes {
+		if !policies[route.Policy] {
+			return fmt.Errorf("alert route %q references unknown policy %q", route.Name, route.Policy)
+		}
+	}
+	if c.DefaultPolicy != "" && !policies[c.DefaultPolicy] {
+		return fmt.Errorf("default escalation policy %q does not exist", c.DefaultPolicy)
+	}
+
+	return nil
+}
+
+// FindTarget returns the escalation target with the given name
+func (c EscalationConfig) FindTarget(name string) (EscalationTarget, bool) {
+	for _, target := range c.Targets {
+		if target.Name == name {
+			return target, true
+		}
+	}
+	return EscalationTarget{}, false
+}
+
+// PolicyFor returns the escalation policy attached to the first route matching the alert,
+// falling back to the default policy. It returns nil when legacy levels should be used.
+func (c EscalationConfig) PolicyFor(alert *Alert) *EscalationPolicy {
+	policyID := c.DefaultPolicy
+	for _, route := range c.Routes {
+		if route.Matches(alert) {
+			policyID = route.Policy
+			break
+		}
+	}
+	if policyID == "" {
+		return nil
+	}
+	for i := range c.Policies {
+		if c.Policies[i].ID == policyID {
+			return &c.Policies[i]
+		}
+	}
+	return nil
+}
+
+// ResolveLevel returns the target name a policy level notifies at the given time
+func (c EscalationConfig) ResolveLevel(level EscalationPolicyLevel, at time.Time) string {
+	if level.Target != "" {
+		return level.Target
+	}
+	for _, schedule := range c.Schedules {
+		if schedule.ID == level.Schedule {
+			return schedule.OnCall(at, level.Position)
+		}
+	}
+	return ""
+}
+
+// Matches reports whether an alert matches the route filters
+func (r AlertRoute) Matches(alert *Alert) bool {
+	if alert == nil {
+		return false
+	}
+	resourceType := ""
+	if alert.Metadata != nil {
+		resourceType, _ = alert.Metadata["resourceType"].(string)
+	}
+	return matchesAny(r.Levels, string(alert.Level)) &&
+		matchesAny(r.Types, alert.Type) &&
+		matchesAny(r.Nodes, alert.Node) &&
+		matchesAny(r.ResourceTypes, resourceType)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// OnCall returns the participant on call at the given rotation position (0 = primary).
+// Overrides replace the primary participant while they are active.
+func (s OnCallSchedule) OnCall(at time.Time, position int) string {
+	if len(s.Participants) == 0 {
+		return ""
+	}
+	if position == 0 {
+		for _, override := range s.Overrides {
+			if !at.Before(override.Start) && at.Before(override.End) {
+				return override.Target
+			}
+		}
+	}
+
+	weekday, hour, minute, err := s.handoff()
+	if err != nil {
+		return ""
+	}
+	loc := s.location()
+	anchor := s.RotationStart
+	if anchor.IsZero() {
+		anchor = time.Unix(0, 0)
+	}
+
+	current := lastHandoff(at.In(loc), weekday, hour, minute)
+	first := lastHandoff(anchor.In(loc), weekday, hour, minute)
+	weeks := calendarDaysBetween(first, current) / 7
+
+	count := len(s.Participants)
+	index := ((weeks+position)%count + count) % count
+	return s.Participants[index]
+}
+
+func (s OnCallSchedule) handoff() (time.Weekday, int, int, error) {
+	day := strings.ToLower(strings.TrimSpace(s.HandoffDay))
+	if day == "" {
+		day = "monday"
+	}
+	weekday, ok := weekdays[day]
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid handoff day %q", s.HandoffDay)
+	}
+
+	handoffTime := strings.TrimSpace(s.HandoffTime)
+	if handoffTime == "" {
+		handoffTime = "09:00"
+	}
+	parts := strings.Split(handoffTime, ":")
+	if len(parts) != 2 {
+		return 0, 0, 0, fmt.Errorf("invalid handoff time %q", s.HandoffTime)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, 0, fmt.Errorf("invalid handoff time %q", s.HandoffTime)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, 0, fmt.Errorf("invalid handoff time %q", s.HandoffTime)
+	}
+
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid timezone %q", s.Timezone)
+		}
+	}
+	return weekday, hour, minute, nil
+}
+
+func (s OnCallSchedule) location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// lastHandoff returns the most recent handoff at or before t, in t's location
+func lastHandoff(t time.Time, weekday time.Weekday, hour, minute int) time.Time {
+	candidate := time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, t.Location())
+	diff := (int(t.Weekday()) - int(weekday) + 7) % 7
+	candidate = candidate.AddDate(0, 0, -diff)
+	if candidate.After(t) {
+		candidate = candidate.AddDate(0, 0, -7)
+	}
+	return candidate
+}
+
+// calendarDaysBetween counts calendar days between two dates, ignoring DST shifts
+func calendarDaysBetween(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
@@ -0,0 +1,233 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+func testEscalationConfig() EscalationConfig {
+	return EscalationConfig{
+		Enabled: true,
+		Targets: []EscalationTarget{
+			{Name: "alice", Emails: []string{"alice@example.com"}},
+			{Name: "bob", Emails: []string{"bob@example.com"}},
+			{Name: "carol", Emails: []string{"carol@example.com"}},
+			{Name: "team", Webhooks: []string{"slack-team"}},
+		},
+		Schedules: []OnCallSchedule{
+			{
+				ID:            "ops",
+				Name:          "Ops rotation",
+				Participants:  []string{"alice", "bob", "carol"},
+				HandoffDay:    "monday",
+				HandoffTime:   "09:00",
+				Timezone:      "UTC",
+				RotationStart: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), // a Monday
+			},
+		},
+		Policies: []EscalationPolicy{
+			{
+				ID:   "default",
+				Name: "Primary, secondary, team",
+				Levels: []EscalationPolicyLevel{
+					{After: 0, Schedule: "ops", Position: 0},
+					{After: 15, Schedule: "ops", Position: 1},
+					{After: 30, Target: "team"},
+				},
+			},
+		},
+		Routes: []AlertRoute{
+			{ID: "critical", Name: "Critical alerts", Levels: []string{"critical"}, Policy: "default"},
+		},
+	}
+}
+
+func TestOnCallScheduleWeeklyRotation(t *testing.T) {
+	schedule := testEscalationConfig().Schedules[0]
+
+	cases := []struct {
+		at        time.Time
+		primary   string
+		secondary string
+	}{
+		{time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), "alice", "bob"},
+		{time.Date(2024, 1, 8, 8, 59, 0, 0, time.UTC), "alice", "bob"},
+		{time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC), "bob", "carol"},
+		{time.Date(2024, 1, 17, 12, 0, 0, 0, time.UTC), "carol", "alice"},
+		{time.Date(2024, 1, 22, 10, 0, 0, 0, time.UTC), "alice", "bob"},
+		{time.Date(2023, 12, 30, 10, 0, 0, 0, time.UTC), "carol", "alice"},
+	}
+
+	for _, tc := range cases {
+		if got := schedule.OnCall(tc.at, 0); got != tc.primary {
+			t.Errorf("%s: expected primary %q, got %q", tc.at, tc.primary, got)
+		}
+		if got := schedule.OnCall(tc.at, 1); got != tc.secondary {
+			t.Errorf("%s: expected secondary %q, got %q", tc.at, tc.secondary, got)
+		}
+	}
+}
+
+func TestOnCallScheduleOverride(t *testing.T) {
+	schedule := testEscalationConfig().Schedules[0]
+	schedule.Overrides = []OnCallOverride{{
+		Target: "carol",
+		Start:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}}
+
+	if got := schedule.OnCall(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), 0); got != "carol" {
+		t.Fatalf("expected override to replace primary, got %q", got)
+	}
+	if got := schedule.OnCall(time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), 1); got != "bob" {
+		t.Fatalf("expected override to leave secondary unchanged, got %q", got)
+	}
+	if got := schedule.OnCall(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), 0); got != "alice" {
+		t.Fatalf("expected rotation to resume after override ends, got %q", got)
+	}
+}
+
+func TestEscalationConfigValidate(t *testing.T) {
+	if err := testEscalationConfig().Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	mutations := map[string]func(c *EscalationConfig){
+		"unknown participant": func(c *EscalationConfig) { c.Schedules[0].Participants = append(c.Schedules[0].Participants, "dave") },
+		"bad handoff day":     func(c *EscalationConfig) { c.Schedules[0].HandoffDay = "someday" },
+		"bad timezone":        func(c *EscalationConfig) { c.Schedules[0].Timezone = "Mars/Olympus" },
+		"unknown schedule":    func(c *EscalationConfig) { c.Policies[0].Levels[0].Schedule = "missing" },
+		"unknown policy":      func(c *EscalationConfig) { c.Routes[0].Policy = "missing" },
+		"decreasing delays":   func(c *EscalationConfig) { c.Policies[0].Levels[2].After = 5 },
+		"no destination":      func(c *EscalationConfig) { c.Policies[0].Levels[2].Target = "" },
+	}
+	for name, mutate := range mutations {
+		cfg := testEscalationConfig()
+		mutate(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestCheckEscalationsFollowsPolicyUntilAcknowledged(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	notified := make(chan string, 10)
+	m.SetEscalateCallback(func(alert *Alert, level int) {
+		notified <- alert.EscalationTarget
+	})
+
+	m.mu.Lock()
+	m.config.Schedule.Escalation = testEscalationConfig()
+	m.activeAlerts["vm-101-cpu"] = &Alert{
+		ID:        "vm-101-cpu",
+		Type:      "cpu",
+		Level:     AlertLevelCritical,
+		StartTime: time.Now().Add(-20 * time.Minute),
+	}
+	m.mu.Unlock()
+
+	m.checkEscalations()
+
+	expected := testEscalationConfig().Schedules[0]
+	now := time.Now()
+	// Callbacks run asynchronously, so compare the notified targets as a set
+	want := map[string]bool{expected.OnCall(now, 0): true, expected.OnCall(now, 1): true}
+	for range want {
+		select {
+		case got := <-notified:
+			if !want[got] {
+				t.Fatalf("unexpected escalation to %q", got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected escalations to primary and secondary")
+		}
+	}
+
+	m.mu.Lock()
+	alert := m.activeAlerts["vm-101-cpu"]
+	if alert.LastEscalation != 2 || alert.EscalationPolicy != "default" {
+		m.mu.Unlock()
+		t.Fatalf("expected alert at level 2 of default policy, got level %d policy %q", alert.LastEscalation, alert.EscalationPolicy)
+	}
+	// Make the team level due, then acknowledge before the checker runs
+	alert.StartTime = time.Now().Add(-45 * time.Minute)
+	m.mu.Unlock()
+
+	if err := m.AcknowledgeAlert("vm-101-cpu", "alice"); err != nil {
+		t.Fatalf("ack failed: %v", err)
+	}
+	m.checkEscalations()
+
+	select {
+	case got := <-notified:
+		t.Fatalf("expected no escalation after acknowledgement, got %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCheckEscalationsDropsRemovedPolicyTarget(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	notified := make(chan *Alert, 10)
+	m.SetEscalateCallback(func(alert *Alert, level int) {
+		notified <- alert
+	})
+
+	// The alert was escalated under a policy that has since been removed
+	cfg := testEscalationConfig()
+	cfg.Policies = nil
+	cfg.Routes = nil
+	cfg.Levels = []EscalationLevel{{After: 5, Notify: "email"}, {After: 10, Notify: "all"}}
+
+	m.mu.Lock()
+	m.config.Schedule.Escalation = cfg
+	m.activeAlerts["vm-101-cpu"] = &Alert{
+		ID:               "vm-101-cpu",
+		Type:             "cpu",
+		Level:            AlertLevelCritical,
+		StartTime:        time.Now().Add(-20 * time.Minute),
+		LastEscalation:   1,
+		EscalationPolicy: "default",
+		EscalationTarget: "alice",
+	}
+	m.mu.Unlock()
+
+	m.checkEscalations()
+
+	select {
+	case got := <-notified:
+		if got.LastEscalation != 2 || got.EscalationPolicy != "" || got.EscalationTarget != "" {
+			t.Fatalf("expected a level 2 escalation without the removed policy, got level %d policy %q target %q",
+				got.LastEscalation, got.EscalationPolicy, got.EscalationTarget)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the legacy level to escalate")
+	}
+}
+
+func TestAlertRouteMatches(t *testing.T) {
+	route := AlertRoute{Types: []string{"cpu", "memory"}, ResourceTypes: []string{"vm"}, Policy: "p"}
+
+	if !route.Matches(&Alert{Type: "CPU", Metadata: map[string]interface{}{"resourceType": "vm"}}) {
+		t.Fatalf("expected route to match cpu alert on vm")
+	}
+	if route.Matches(&Alert{Type: "disk", Metadata: map[string]interface{}{"resourceType": "vm"}}) {
+		t.Fatalf("expected route not to match disk alert")
+	}
+	if route.Matches(&Alert{Type: "cpu"}) {
+		t.Fatalf("expected route not to match alert without resource type")
+	}
+
+	cfg := testEscalationConfig()
+	if cfg.PolicyFor(&Alert{Level: AlertLevelWarning}) != nil {
+		t.Fatalf("expected warning alert to fall back to legacy levels")
+	}
+	cfg.DefaultPolicy = "default"
+	if policy := cfg.PolicyFor(&Alert{Level: AlertLevelWarning}); policy == nil || policy.ID != "default" {
+		t.Fatalf("expected default policy for unmatched alert")
+	}
+}
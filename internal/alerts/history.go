@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/utils"
@@ -37,6 +38,7 @@ type HistoryManager struct {
 	saveInterval time.Duration
 	stopChan     chan struct{}
 	saveTicker   *time.Ticker
+	readOnly     atomic.Bool
 }
 
 // NewHistoryManager creates a new history manager
@@ -207,6 +209,9 @@ func (hm *HistoryManager) startPeriodicSave() {
 		for {
 			select {
 			case <-hm.saveTicker.C:
+				if hm.readOnly.Load() {
+					continue
+				}
 				if err := hm.saveHistory(); err != nil {
 					log.Error().Err(err).Msg("Failed to save alert history")
 				}
@@ -308,6 +313,10 @@ func (hm *HistoryManager) Stop() {
 		hm.saveTicker.Stop()
 	}
 
+	if hm.readOnly.Load() {
+		return
+	}
+
 	// Save one final time
 	if err := hm.saveHistory(); err != nil {
 		log.Error().Err(err).Msg("Failed to save alert history on shutdown")
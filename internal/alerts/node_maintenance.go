@@ -0,0 +1,121 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+// Node maintenance alert types
+const (
+	certificateExpiryAlertType = "certificate-expiry"
+	kernelOutdatedAlertType    = "kernel-outdated"
+)
+
+// CheckNodeMaintenance raises alerts for expiring TLS certificates and nodes running an older
+// kernel than the newest one installed, for every node of a PVE, PBS or PMG instance. Alerts
+// for the instance that no longer apply are cleared.
+func (m *Manager) CheckNodeMaintenance(platform, instance string, entries []models.NodeMaintenance) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	valid := make(map[string]bool)
+	if m.config.Enabled {
+		cfg := m.config.MaintenanceDefaults
+		now := time.Now()
+		for _, entry := range entries {
+			if cfg.CertificateExpiryEnabled {
+				m.checkCertificatesLocked(cfg, entry, valid, now)
+			}
+			if cfg.KernelOutdatedEnabled && entry.KernelOutdated {
+				alertID := fmt.Sprintf("%s-%s", kernelOutdatedAlertType, sanitizeAlertKey(entry.ID))
+				valid[alertID] = true
+				m.raiseStateAlertLocked(&Alert{
+					ID:           alertID,
+					Type:         kernelOutdatedAlertType,
+					Level:        AlertLevelWarning,
+					ResourceID:   entry.ID,
+					ResourceName: entry.Node,
+					Node:         entry.Node,
+					Instance:     instance,
+					Message: fmt.Sprintf("Node %s is running kernel %s but %s is installed; reboot to apply it",
+						entry.Node, entry.RunningKernel, entry.LatestKernel),
+					Metadata: map[string]interface{}{
+						"platform":      platform,
+						"runningKernel": entry.RunningKernel,
+						"latestKernel":  entry.LatestKernel,
+					},
+				}, now)
+			}
+		}
+	}
+
+	for alertID, alert := range m.activeAlerts {
+		if alert == nil || alert.Instance != instance || valid[alertID] || !isNodeMaintenanceAlertType(alert.Type) {
+			continue
+		}
+		if alertPlatform, _ := alert.Metadata["platform"].(string); alertPlatform != platform {
+			continue
+		}
+		m.clearAlertNoLock(alertID)
+	}
+}
+
+func (m *Manager) checkCertificatesLocked(cfg MaintenanceAlertConfig, entry models.NodeMaintenance, valid map[string]bool, now time.Time) {
+	for _, cert := range entry.Certificates {
+		if cert.NotAfter.IsZero() || cert.DaysRemaining > cfg.CertificateWarningDays {
+			continue
+		}
+
+		level := AlertLevelWarning
+		if cert.DaysRemaining <= cfg.CertificateCriticalDays {
+			level = AlertLevelCritical
+		}
+		message := fmt.Sprintf("Certificate %s on %s expires in %d days (%s)",
+			cert.Filename, entry.Node, cert.DaysRemaining, cert.NotAfter.Format("2006-01-02"))
+		if cert.DaysRemaining < 0 {
+			message = fmt.Sprintf("Certificate %s on %s expired on %s", cert.Filename, entry.Node, cert.NotAfter.Format("2006-01-02"))
+		}
+		threshold := cfg.CertificateWarningDays
+		if level == AlertLevelCritical {
+			threshold = cfg.CertificateCriticalDays
+		}
+
+		alertID := fmt.Sprintf("%s-%s-%s", certificateExpiryAlertType, sanitizeAlertKey(entry.ID), sanitizeAlertKey(cert.Filename))
+		valid[alertID] = true
+		m.raiseStateAlertLocked(&Alert{
+			ID:           alertID,
+			Type:         certificateExpiryAlertType,
+			Level:        level,
+			ResourceID:   entry.ID,
+			ResourceName: fmt.Sprintf("%s certificate on %s", cert.Filename, entry.Node),
+			Node:         entry.Node,
+			Instance:     entry.Instance,
+			Message:      message,
+			Value:        float64(cert.DaysRemaining),
+			Threshold:    float64(threshold),
+			Metadata: map[string]interface{}{
+				"platform":    entry.Platform,
+				"filename":    cert.Filename,
+				"subject":     cert.Subject,
+				"notAfter":    cert.NotAfter,
+				"fingerprint": cert.Fingerprint,
+			},
+		}, now)
+	}
+}
+
+func isNodeMaintenanceAlertType(alertType string) bool {
+	return alertType == certificateExpiryAlertType || alertType == kernelOutdatedAlertType
+}
+
+// clearAlertsOfTypeLocked clears every active alert of a type (must be called with lock held)
+func (m *Manager) clearAlertsOfTypeLocked(alertType string) {
+	for alertID, alert := range m.activeAlerts {
+		if alert == nil || alert.Type != alertType {
+			continue
+		}
+		m.clearAlertNoLock(alertID)
+	}
+}
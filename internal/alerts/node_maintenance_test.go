@@ -0,0 +1,81 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func TestCheckNodeMaintenanceRaisesAndClearsAlerts(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.config.MaintenanceDefaults = MaintenanceAlertConfig{
+		CertificateExpiryEnabled: true,
+		CertificateWarningDays:   30,
+		CertificateCriticalDays:  7,
+		KernelOutdatedEnabled:    true,
+	}
+	m.mu.Unlock()
+
+	now := time.Now()
+	entries := []models.NodeMaintenance{
+		{
+			ID:             "pve-lab-pve1",
+			Platform:       "pve",
+			Instance:       "lab",
+			Node:           "pve1",
+			RunningKernel:  "6.8.8-2-pve",
+			LatestKernel:   "6.8.12-4-pve",
+			KernelOutdated: true,
+			Certificates: []models.NodeCertificate{
+				{Filename: "pveproxy-ssl.pem", NotAfter: now.AddDate(0, 0, 3), DaysRemaining: 3},
+				{Filename: "pve-ssl.pem", NotAfter: now.AddDate(0, 0, 20), DaysRemaining: 20},
+				{Filename: "pve-root-ca.pem", NotAfter: now.AddDate(10, 0, 0), DaysRemaining: 3650},
+			},
+		},
+	}
+
+	m.CheckNodeMaintenance("pve", "lab", entries)
+
+	criticalID := "certificate-expiry-pve-lab-pve1-" + sanitizeAlertKey("pveproxy-ssl.pem")
+	warningID := "certificate-expiry-pve-lab-pve1-" + sanitizeAlertKey("pve-ssl.pem")
+	kernelID := "kernel-outdated-pve-lab-pve1"
+
+	m.mu.RLock()
+	if alert := m.activeAlerts[criticalID]; alert == nil || alert.Level != AlertLevelCritical {
+		t.Errorf("expected critical certificate alert, got %+v", alert)
+	}
+	if alert := m.activeAlerts[warningID]; alert == nil || alert.Level != AlertLevelWarning {
+		t.Errorf("expected warning certificate alert, got %+v", alert)
+	}
+	if _, ok := m.activeAlerts[kernelID]; !ok {
+		t.Error("expected kernel outdated alert")
+	}
+	if len(m.activeAlerts) != 3 {
+		t.Errorf("expected 3 alerts, got %d", len(m.activeAlerts))
+	}
+	m.mu.RUnlock()
+
+	// A PBS instance with the same name must not clear the PVE alerts
+	m.CheckNodeMaintenance("pbs", "lab", nil)
+	m.mu.RLock()
+	if len(m.activeAlerts) != 3 {
+		t.Errorf("expected PVE alerts to survive a PBS check, got %d", len(m.activeAlerts))
+	}
+	m.mu.RUnlock()
+
+	// Certificate renewed and node rebooted
+	entries[0].KernelOutdated = false
+	entries[0].Certificates = entries[0].Certificates[2:]
+	m.CheckNodeMaintenance("pve", "lab", entries)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.activeAlerts) != 0 {
+		t.Errorf("expected all maintenance alerts to clear, got %d", len(m.activeAlerts))
+	}
+}
@@ -0,0 +1,65 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pulse's own HTTPS certificate alert
+const (
+	pulseCertificateAlertType = "pulse-certificate"
+	pulseCertificateAlertID   = pulseCertificateAlertType
+
+	pulseCertificateWarningWindow  = 14 * 24 * time.Hour
+	pulseCertificateCriticalWindow = 3 * 24 * time.Hour
+)
+
+// CheckPulseCertificate raises a warning when the certificate Pulse serves expires within 14 days
+// and a critical alert within 3 days or once expired, so a failing renewal is noticed before
+// browsers and agents start rejecting the connection.
+func (m *Manager) CheckPulseCertificate(subject string, notAfter time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	remaining := notAfter.Sub(now)
+	if !m.config.Enabled || remaining > pulseCertificateWarningWindow {
+		if _, exists := m.activeAlerts[pulseCertificateAlertID]; exists {
+			m.clearAlertNoLock(pulseCertificateAlertID)
+		}
+		return
+	}
+
+	level := AlertLevelWarning
+	message := fmt.Sprintf("Pulse HTTPS certificate for %s expires in %s (%s)",
+		subject, formatCertificateRemaining(remaining), notAfter.UTC().Format(time.RFC3339))
+	if remaining <= pulseCertificateCriticalWindow {
+		level = AlertLevelCritical
+	}
+	if remaining <= 0 {
+		message = fmt.Sprintf("Pulse HTTPS certificate for %s expired on %s", subject, notAfter.UTC().Format(time.RFC3339))
+	}
+
+	m.raiseStateAlertLocked(&Alert{
+		ID:           pulseCertificateAlertID,
+		Type:         pulseCertificateAlertType,
+		Level:        level,
+		ResourceID:   "pulse",
+		ResourceName: "Pulse HTTPS certificate",
+		Instance:     "pulse",
+		Message:      message,
+		Value:        remaining.Hours() / 24,
+		Threshold:    pulseCertificateWarningWindow.Hours() / 24,
+		Metadata: map[string]interface{}{
+			"subject":  subject,
+			"notAfter": notAfter.UTC().Format(time.RFC3339),
+		},
+	}, now)
+}
+
+func formatCertificateRemaining(remaining time.Duration) string {
+	if remaining < 24*time.Hour {
+		return fmt.Sprintf("%d hours", int(remaining.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(remaining.Hours()/24))
+}
@@ -0,0 +1,45 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckPulseCertificate(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.mu.Unlock()
+
+	level := func() (AlertLevel, bool) {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		alert, ok := m.activeAlerts[pulseCertificateAlertID]
+		if !ok {
+			return "", false
+		}
+		return alert.Level, true
+	}
+
+	m.CheckPulseCertificate("pulse.example.com", time.Now().Add(60*24*time.Hour))
+	if _, ok := level(); ok {
+		t.Fatal("expected no alert for a certificate with 60 days left")
+	}
+
+	m.CheckPulseCertificate("pulse.example.com", time.Now().Add(10*24*time.Hour))
+	if got, ok := level(); !ok || got != AlertLevelWarning {
+		t.Fatalf("10 days left = %v %v, want warning", got, ok)
+	}
+
+	m.CheckPulseCertificate("pulse.example.com", time.Now().Add(-time.Hour))
+	if got, ok := level(); !ok || got != AlertLevelCritical {
+		t.Fatalf("expired = %v %v, want critical", got, ok)
+	}
+
+	m.CheckPulseCertificate("pulse.example.com", time.Now().Add(90*24*time.Hour))
+	if _, ok := level(); ok {
+		t.Fatal("expected the alert to clear after renewal")
+	}
+}
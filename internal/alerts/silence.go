@@ -0,0 +1,205 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const silencesFile = "silences.json"
+
+// MaxSilenceDuration caps how long a single silence can suppress notifications
+const MaxSilenceDuration = 7 * 24 * time.Hour
+
+// Silence suppresses notifications for alerts on a resource until it expires.
+// Alerts are still raised and visible; only notifications and escalations are held back.
+type Silence struct {
+	ID        string    `json:"id"`
+	Resource  string    `json:"resource"` // Resource ID, resource name or node name
+	Until     time.Time `json:"until"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Matches reports whether the silence covers the alert
+func (s Silence) Matches(alert *Alert) bool {
+	if alert == nil || s.Resource == "" {
+		return false
+	}
+	return strings.EqualFold(s.Resource, alert.ResourceID) ||
+		strings.EqualFold(s.Resource, alert.ResourceName) ||
+		strings.EqualFold(s.Resource, alert.Node)
+}
+
+// SilenceResource suppresses notifications for a resource for the given duration
+func (m *Manager) SilenceResource(resource string, duration time.Duration, user, reason string) (Silence, error) {
+	resource = strings.TrimSpace(resource)
+	if resource == "" {
+		return Silence{}, fmt.Errorf("resource is required")
+	}
+	if duration <= 0 {
+		return Silence{}, fmt.Errorf("silence duration must be positive")
+	}
+	if duration > MaxSilenceDuration {
+		return Silence{}, fmt.Errorf("silence duration cannot exceed %s", MaxSilenceDuration)
+	}
+
+	now := time.Now()
+	silence := Silence{
+		ID:        uuid.NewString(),
+		Resource:  resource,
+		Until:     now.Add(duration),
+		CreatedBy: user,
+		CreatedAt: now,
+		Reason:    reason,
+	}
+
+	m.silenceMu.Lock()
+	m.pruneSilencesLocked(now)
+	m.silences[silence.ID] = silence
+	m.silenceMu.Unlock()
+	m.persistSilences()
+
+	log.Info().
+		Str("silenceID", silence.ID).
+		Str("resource", resource).
+		Str("user", user).
+		Time("until", silence.Until).
+		Msg("Alert notifications silenced")
+
+	return silence, nil
+}
+
+// RemoveSilence deletes a silence before it expires
+func (m *Manager) RemoveSilence(id string) error {
+	m.silenceMu.Lock()
+	if _, exists := m.silences[id]; !exists {
+		m.silenceMu.Unlock()
+		return fmt.Errorf("silence not found: %s", id)
+	}
+	delete(m.silences, id)
+	m.silenceMu.Unlock()
+	m.persistSilences()
+
+	log.Info().Str("silenceID", id).Msg("Alert silence removed")
+	return nil
+}
+
+// GetSilences returns the active silences ordered by expiry
+func (m *Manager) GetSilences() []Silence {
+	m.silenceMu.Lock()
+	defer m.silenceMu.Unlock()
+
+	m.pruneSilencesLocked(time.Now())
+	silences := make([]Silence, 0, len(m.silences))
+	for _, silence := range m.silences {
+		silences = append(silences, silence)
+	}
+	sort.Slice(silences, func(i, j int) bool {
+		return silences[i].Until.Before(silences[j].Until)
+	})
+	return silences
+}
+
+// silenceForAlert returns an active silence covering the alert
+func (m *Manager) silenceForAlert(alert *Alert, now time.Time) (Silence, bool) {
+	m.silenceMu.RLock()
+	defer m.silenceMu.RUnlock()
+
+	for _, silence := range m.silences {
+		if now.Before(silence.Until) && silence.Matches(alert) {
+			return silence, true
+		}
+	}
+	return Silence{}, false
+}
+
+// pruneSilencesLocked drops expired silences. Callers must hold m.silenceMu.
+func (m *Manager) pruneSilencesLocked(now time.Time) {
+	for id, silence := range m.silences {
+		if !now.Before(silence.Until) {
+			delete(m.silences, id)
+		}
+	}
+}
+
+func silencesPath() string {
+	return filepath.Join(utils.GetDataDir(), "alerts", silencesFile)
+}
+
+// persistSilences saves silences right after a change so they survive a restart
+func (m *Manager) persistSilences() {
+	if m.readOnly.Load() {
+		return
+	}
+	if err := m.SaveSilences(); err != nil {
+		log.Error().Err(err).Msg("Failed to save alert silences")
+	}
+}
+
+// SaveSilences persists the active silences to disk
+func (m *Manager) SaveSilences() error {
+	m.silenceMu.RLock()
+	silences := make([]Silence, 0, len(m.silences))
+	for _, silence := range m.silences {
+		silences = append(silences, silence)
+	}
+	m.silenceMu.RUnlock()
+
+	data, err := json.MarshalIndent(silences, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal silences: %w", err)
+	}
+
+	path := silencesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create alerts directory: %w", err)
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write silences: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("failed to rename silences file: %w", err)
+	}
+	return nil
+}
+
+// LoadSilences restores silences from disk, dropping those that expired while Pulse was down
+func (m *Manager) LoadSilences() error {
+	data, err := os.ReadFile(silencesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read silences: %w", err)
+	}
+
+	var silences []Silence
+	if err := json.Unmarshal(data, &silences); err != nil {
+		return fmt.Errorf("failed to unmarshal silences: %w", err)
+	}
+
+	now := time.Now()
+	m.silenceMu.Lock()
+	for _, silence := range silences {
+		if silence.ID == "" || silence.Resource == "" || !now.Before(silence.Until) {
+			continue
+		}
+		m.silences[silence.ID] = silence
+	}
+	restored := len(m.silences)
+	m.silenceMu.Unlock()
+
+	log.Info().Int("count", restored).Msg("Loaded alert silences")
+	return nil
+}
@@ -0,0 +1,102 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSilenceSuppressesDispatch(t *testing.T) {
+	t.Setenv("PULSE_DATA_DIR", t.TempDir())
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	dispatched := 0
+	m.SetAlertCallback(func(alert *Alert) { dispatched++ })
+
+	m.mu.Lock()
+	m.config.ActivationState = ActivationActive
+	m.config.Schedule.QuietHours.Enabled = false
+	m.mu.Unlock()
+
+	alert := &Alert{ID: "pve1-qemu-101-cpu", ResourceID: "pve1-qemu-101", ResourceName: "web01", Node: "pve1", Level: AlertLevelWarning}
+
+	silence, err := m.SilenceResource("WEB01", 2*time.Hour, "slack:alice", "maintenance")
+	if err != nil {
+		t.Fatalf("SilenceResource: %v", err)
+	}
+	if m.dispatchAlert(alert, false) || dispatched != 0 {
+		t.Fatalf("expected silenced alert not to be dispatched")
+	}
+
+	other := &Alert{ID: "pve2-qemu-200-cpu", ResourceID: "pve2-qemu-200", ResourceName: "db01", Node: "pve2", Level: AlertLevelWarning}
+	if !m.dispatchAlert(other, false) || dispatched != 1 {
+		t.Fatalf("expected unrelated alert to be dispatched")
+	}
+
+	if silences := m.GetSilences(); len(silences) != 1 || silences[0].CreatedBy != "slack:alice" {
+		t.Fatalf("unexpected silences %+v", silences)
+	}
+	if err := m.RemoveSilence(silence.ID); err != nil {
+		t.Fatalf("RemoveSilence: %v", err)
+	}
+	if !m.dispatchAlert(alert, false) || dispatched != 2 {
+		t.Fatalf("expected alert to be dispatched after silence removal")
+	}
+}
+
+func TestSilenceValidationAndExpiry(t *testing.T) {
+	t.Setenv("PULSE_DATA_DIR", t.TempDir())
+	m := NewManager()
+
+	if _, err := m.SilenceResource("", time.Hour, "u", ""); err == nil {
+		t.Fatalf("expected error for empty resource")
+	}
+	if _, err := m.SilenceResource("web01", 0, "u", ""); err == nil {
+		t.Fatalf("expected error for zero duration")
+	}
+	if _, err := m.SilenceResource("web01", MaxSilenceDuration+time.Hour, "u", ""); err == nil {
+		t.Fatalf("expected error for excessive duration")
+	}
+
+	silence, err := m.SilenceResource("web01", time.Hour, "u", "")
+	if err != nil {
+		t.Fatalf("SilenceResource: %v", err)
+	}
+	m.silenceMu.Lock()
+	silence.Until = time.Now().Add(-time.Second)
+	m.silences[silence.ID] = silence
+	m.silenceMu.Unlock()
+
+	if _, silenced := m.silenceForAlert(&Alert{ResourceName: "web01"}, time.Now()); silenced {
+		t.Fatalf("expected expired silence to be ignored")
+	}
+	if silences := m.GetSilences(); len(silences) != 0 {
+		t.Fatalf("expected expired silence to be pruned, got %+v", silences)
+	}
+}
+
+func TestSilencesSurviveRestart(t *testing.T) {
+	t.Setenv("PULSE_DATA_DIR", t.TempDir())
+	m := NewManager()
+
+	kept, err := m.SilenceResource("web01", time.Hour, "slack:alice", "maintenance")
+	if err != nil {
+		t.Fatalf("SilenceResource: %v", err)
+	}
+	removed, err := m.SilenceResource("db01", time.Hour, "slack:alice", "")
+	if err != nil {
+		t.Fatalf("SilenceResource: %v", err)
+	}
+	if err := m.RemoveSilence(removed.ID); err != nil {
+		t.Fatalf("RemoveSilence: %v", err)
+	}
+
+	restarted := NewManager()
+	silences := restarted.GetSilences()
+	if len(silences) != 1 || silences[0].ID != kept.ID || silences[0].CreatedBy != "slack:alice" || silences[0].Reason != "maintenance" {
+		t.Fatalf("expected only the remaining silence to be restored, got %+v", silences)
+	}
+	if _, silenced := restarted.silenceForAlert(&Alert{ResourceName: "web01"}, time.Now()); !silenced {
+		t.Fatalf("expected restored silence to apply")
+	}
+}
@@ -0,0 +1,124 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+const taskFailedAlertType = "task-failed"
+
+// defaultTaskAlertTypes are the task types that raise alerts unless TaskDefaults.Types says otherwise
+var defaultTaskAlertTypes = map[string]bool{
+	"qmigrate":    true, // VM migration
+	"vzmigrate":   true, // Container migration
+	"qmclone":     true,
+	"vzclone":     true,
+	"qmmove":      true, // VM disk move
+	"move_volume": true, // Container volume move
+	"qmrestore":   true,
+	"vzrestore":   true,
+	"vzdump":      true,
+	"hamigrate":   true,
+	"harelocate":  true,
+}
+
+// DefaultTaskAlertTypes returns the task types that alert by default
+func DefaultTaskAlertTypes() map[string]bool {
+	types := make(map[string]bool, len(defaultTaskAlertTypes))
+	for taskType, enabled := range defaultTaskAlertTypes {
+		types[taskType] = enabled
+	}
+	return types
+}
+
+// taskAlertEnabled reports whether failures of a task type should alert (must be called with lock held)
+func (m *Manager) taskAlertEnabled(taskType string) bool {
+	if !m.config.Enabled || !m.config.TaskDefaults.Enabled {
+		return false
+	}
+	if enabled, ok := m.config.TaskDefaults.Types[taskType]; ok {
+		return enabled
+	}
+	return defaultTaskAlertTypes[taskType]
+}
+
+// taskAlertID groups failures by instance, node, type and target so a retry that succeeds clears
+// the alert and repeated failures refresh it instead of piling up
+func taskAlertID(task models.ClusterTask) string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s", taskFailedAlertType, sanitizeAlertKey(task.Instance), sanitizeAlertKey(task.Node),
+		sanitizeAlertKey(task.Type), sanitizeAlertKey(task.Target))
+}
+
+// CheckTasks evaluates newly finished tasks. Failed tasks of an enabled type raise a task-failed
+// alert; a later successful run of the same task type on the same target clears it. Task alerts
+// older than TaskDefaults.AutoResolveHours are resolved automatically.
+func (m *Manager) CheckTasks(tasks []models.ClusterTask) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, task := range tasks {
+		if !task.Finished() {
+			continue
+		}
+		alertID := taskAlertID(task)
+
+		if task.State != models.TaskStateFailed {
+			if _, exists := m.activeAlerts[alertID]; exists {
+				m.clearAlertNoLock(alertID)
+			}
+			continue
+		}
+		if !m.taskAlertEnabled(task.Type) {
+			continue
+		}
+
+		target := task.Target
+		if target == "" {
+			target = task.Node
+		}
+		m.raiseStateAlertLocked(&Alert{
+			ID:           alertID,
+			Type:         taskFailedAlertType,
+			Level:        AlertLevelWarning,
+			ResourceID:   task.ID,
+			ResourceName: fmt.Sprintf("%s %s", task.Type, target),
+			Node:         task.Node,
+			Instance:     task.Instance,
+			Message:      fmt.Sprintf("Task %s for %s on %s failed: %s", task.Type, target, task.Node, task.Status),
+			Metadata: map[string]interface{}{
+				"upid":     task.UPID,
+				"taskType": task.Type,
+				"target":   task.Target,
+				"vmid":     task.VMID,
+				"user":     task.User,
+				"status":   task.Status,
+			},
+		}, now)
+	}
+
+	maxAge := time.Duration(m.config.TaskDefaults.AutoResolveHours) * time.Hour
+	if maxAge <= 0 {
+		return
+	}
+	for alertID, alert := range m.activeAlerts {
+		if alert != nil && alert.Type == taskFailedAlertType && now.Sub(alert.LastSeen) > maxAge {
+			m.clearAlertNoLock(alertID)
+		}
+	}
+}
+
+// clearDisabledTaskAlertsLocked clears task alerts whose type no longer alerts (must be called with lock held)
+func (m *Manager) clearDisabledTaskAlertsLocked() {
+	for alertID, alert := range m.activeAlerts {
+		if alert == nil || alert.Type != taskFailedAlertType {
+			continue
+		}
+		taskType, _ := alert.Metadata["taskType"].(string)
+		if !m.taskAlertEnabled(taskType) {
+			m.clearAlertNoLock(alertID)
+		}
+	}
+}
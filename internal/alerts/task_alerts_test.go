@@ -0,0 +1,76 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func TestCheckTasksRaisesAndClearsAlerts(t *testing.T) {
+	m := NewManager()
+	m.ClearActiveAlerts()
+
+	m.mu.Lock()
+	m.config.Enabled = true
+	m.config.TaskDefaults = TaskAlertConfig{
+		Enabled:          true,
+		Types:            map[string]bool{"vzdump": false, "aptupdate": true},
+		AutoResolveHours: 24,
+	}
+	m.mu.Unlock()
+
+	failedMigration := models.ClusterTask{
+		ID: "pve-UPID:1", UPID: "UPID:1", Instance: "pve", Node: "pve1", Type: "qmigrate",
+		Target: "101", VMID: 101, Status: "migration aborted", State: models.TaskStateFailed,
+	}
+	failedBackup := models.ClusterTask{
+		ID: "pve-UPID:2", UPID: "UPID:2", Instance: "pve", Node: "pve1", Type: "vzdump",
+		Target: "102", Status: "job errors", State: models.TaskStateFailed,
+	}
+	failedAptUpdate := models.ClusterTask{
+		ID: "pve-UPID:3", UPID: "UPID:3", Instance: "pve", Node: "pve1", Type: "aptupdate",
+		Status: "command failed", State: models.TaskStateFailed,
+	}
+	failedStart := models.ClusterTask{
+		ID: "pve-UPID:4", UPID: "UPID:4", Instance: "pve", Node: "pve1", Type: "qmstart",
+		Target: "103", Status: "start failed", State: models.TaskStateFailed,
+	}
+
+	m.CheckTasks([]models.ClusterTask{failedMigration, failedBackup, failedAptUpdate, failedStart})
+
+	migrationID := taskAlertID(failedMigration)
+	m.mu.RLock()
+	if _, ok := m.activeAlerts[migrationID]; !ok {
+		t.Errorf("expected alert %s for failed migration", migrationID)
+	}
+	if _, ok := m.activeAlerts[taskAlertID(failedAptUpdate)]; !ok {
+		t.Error("expected explicitly enabled task type to alert")
+	}
+	if len(m.activeAlerts) != 2 {
+		t.Errorf("expected 2 alerts (vzdump disabled, qmstart not a default type), got %d", len(m.activeAlerts))
+	}
+	m.mu.RUnlock()
+
+	// A successful retry of the same migration clears the alert
+	retry := failedMigration
+	retry.UPID, retry.ID = "UPID:5", "pve-UPID:5"
+	retry.Status, retry.State = "OK", models.TaskStateOK
+	m.CheckTasks([]models.ClusterTask{retry})
+
+	m.mu.Lock()
+	if _, ok := m.activeAlerts[migrationID]; ok {
+		t.Error("expected successful retry to clear migration alert")
+	}
+	// Age the remaining alert past the auto-resolve window
+	m.activeAlerts[taskAlertID(failedAptUpdate)].LastSeen = time.Now().Add(-25 * time.Hour)
+	m.mu.Unlock()
+
+	m.CheckTasks(nil)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.activeAlerts) != 0 {
+		t.Errorf("expected stale task alert to auto-resolve, got %d alerts", len(m.activeAlerts))
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -9,9 +10,11 @@ import (
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/config"
 	"github.com/RouXx67/PulseUp/internal/mock"
 	"github.com/RouXx67/PulseUp/internal/models"
 	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/tenancy"
 	"github.com/RouXx67/PulseUp/internal/utils"
 	"github.com/RouXx67/PulseUp/internal/websocket"
 	"github.com/rs/zerolog/log"
@@ -19,13 +22,15 @@ import (
 
 // AlertHandlers handles alert-related HTTP endpoints
 type AlertHandlers struct {
+	config  *config.Config
 	monitor *monitoring.Monitor
 	wsHub   *websocket.Hub
 }
 
 // NewAlertHandlers creates new alert handlers
-func NewAlertHandlers(monitor *monitoring.Monitor, wsHub *websocket.Hub) *AlertHandlers {
+func NewAlertHandlers(cfg *config.Config, monitor *monitoring.Monitor, wsHub *websocket.Hub) *AlertHandlers {
 	return &AlertHandlers{
+		config:  cfg,
 		monitor: monitor,
 		wsHub:   wsHub,
 	}
@@ -64,7 +69,7 @@ func (h *AlertHandlers) GetAlertConfig(w http.ResponseWriter, r *http.Request) {
 	config := h.monitor.GetAlertManager().GetConfig()
 
 	if err := utils.WriteJSONResponse(w, config); err != nil {
-		log.Error().Err(err).Msg("Failed to write alert config response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write alert config response")
 	}
 }
 
@@ -76,6 +81,11 @@ func (h *AlertHandlers) UpdateAlertConfig(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if err := config.Schedule.Escalation.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid escalation configuration: %v", err), http.StatusBadRequest)
+		return
+	}
+
 	h.monitor.GetAlertManager().UpdateConfig(config)
 
 	// Update notification manager with schedule settings
@@ -95,14 +105,93 @@ func (h *AlertHandlers) UpdateAlertConfig(w http.ResponseWriter, r *http.Request
 	// Save to persistent storage
 	if err := h.monitor.GetConfigPersistence().SaveAlertConfig(config); err != nil {
 		// Log error but don't fail the request
-		log.Error().Err(err).Msg("Failed to save alert configuration")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save alert configuration")
 	}
 
 	if err := utils.WriteJSONResponse(w, map[string]interface{}{
 		"success": true,
 		"message": "Alert configuration updated successfully",
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to write alert config update response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write alert config update response")
+	}
+}
+
+// GetOnCall returns who is currently on call for each schedule
+func (h *AlertHandlers) GetOnCall(w http.ResponseWriter, r *http.Request) {
+	escalation := h.monitor.GetAlertManager().GetConfig().Schedule.Escalation
+	now := time.Now()
+
+	type onCallEntry struct {
+		ScheduleID   string `json:"scheduleId"`
+		ScheduleName string `json:"scheduleName"`
+		Primary      string `json:"primary"`
+		Secondary    string `json:"secondary,omitempty"`
+	}
+
+	entries := make([]onCallEntry, 0, len(escalation.Schedules))
+	for _, schedule := range escalation.Schedules {
+		entry := onCallEntry{
+			ScheduleID:   schedule.ID,
+			ScheduleName: schedule.Name,
+			Primary:      schedule.OnCall(now, 0),
+		}
+		if len(schedule.Participants) > 1 {
+			entry.Secondary = schedule.OnCall(now, 1)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := utils.WriteJSONResponse(w, entries); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write on-call response")
+	}
+}
+
+// GetSilences returns active notification silences
+func (h *AlertHandlers) GetSilences(w http.ResponseWriter, r *http.Request) {
+	if err := utils.WriteJSONResponse(w, h.monitor.GetAlertManager().GetSilences()); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write silences response")
+	}
+}
+
+// CreateSilence silences notifications for a resource
+func (h *AlertHandlers) CreateSilence(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Resource string `json:"resource"`
+		Duration string `json:"duration"` // Go duration, e.g. "2h"
+		Reason   string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid duration: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	silence, err := h.monitor.GetAlertManager().SilenceResource(req.Resource, duration, "admin", req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := utils.WriteJSONResponse(w, silence); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write silence response")
+	}
+}
+
+// DeleteSilence removes a silence before it expires
+func (h *AlertHandlers) DeleteSilence(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/alerts/silences/")
+	if err := h.monitor.GetAlertManager().RemoveSilence(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err := utils.WriteJSONResponse(w, map[string]bool{"success": true}); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write silence deletion response")
 	}
 }
 
@@ -119,7 +208,7 @@ func (h *AlertHandlers) ActivateAlerts(w http.ResponseWriter, r *http.Request) {
 			"state":          string(config.ActivationState),
 			"activationTime": config.ActivationTime,
 		}); err != nil {
-			log.Error().Err(err).Msg("Failed to write activate response")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write activate response")
 		}
 		return
 	}
@@ -134,7 +223,7 @@ func (h *AlertHandlers) ActivateAlerts(w http.ResponseWriter, r *http.Request) {
 
 	// Save to persistent storage
 	if err := h.monitor.GetConfigPersistence().SaveAlertConfig(config); err != nil {
-		log.Error().Err(err).Msg("Failed to save alert configuration after activation")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save alert configuration after activation")
 		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
 		return
 	}
@@ -151,12 +240,12 @@ func (h *AlertHandlers) ActivateAlerts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if criticalCount > 0 {
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Int("criticalAlerts", criticalCount).
 			Msg("Sent notifications for existing critical alerts after activation")
 	}
 
-	log.Info().Msg("Alert notifications activated")
+	log.Info().Ctx(r.Context()).Msg("Alert notifications activated")
 
 	if err := utils.WriteJSONResponse(w, map[string]interface{}{
 		"success":        true,
@@ -164,16 +253,26 @@ func (h *AlertHandlers) ActivateAlerts(w http.ResponseWriter, r *http.Request) {
 		"state":          string(config.ActivationState),
 		"activationTime": config.ActivationTime,
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to write activate response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write activate response")
 	}
 }
 
 // GetActiveAlerts returns all active alerts
 func (h *AlertHandlers) GetActiveAlerts(w http.ResponseWriter, r *http.Request) {
 	alerts := h.monitor.GetAlertManager().GetActiveAlerts()
+	if scope := requestTenantScope(h.config, r); scope != nil {
+		guests := scope.Guests(h.monitor.GetState())
+		visible := alerts[:0]
+		for _, alert := range alerts {
+			if scope.AllowsAlert(alert.Instance, alert.ResourceID, guests) {
+				visible = append(visible, alert)
+			}
+		}
+		alerts = visible
+	}
 
 	if err := utils.WriteJSONResponse(w, alerts); err != nil {
-		log.Error().Err(err).Msg("Failed to write active alerts response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write active alerts response")
 	}
 }
 
@@ -186,14 +285,14 @@ func (h *AlertHandlers) GetAlertHistory(w http.ResponseWriter, r *http.Request)
 		l, err := strconv.Atoi(limitStr)
 		switch {
 		case err != nil:
-			log.Warn().Str("limit", limitStr).Msg("Invalid limit parameter, using default")
+			log.Warn().Ctx(r.Context()).Str("limit", limitStr).Msg("Invalid limit parameter, using default")
 		case l < 0:
 			http.Error(w, "limit must be non-negative", http.StatusBadRequest)
 			return
 		case l == 0:
 			limit = 0
 		case l > 10000:
-			log.Warn().Int("limit", l).Msg("Limit exceeds maximum, capping at 10000")
+			log.Warn().Ctx(r.Context()).Int("limit", l).Msg("Limit exceeds maximum, capping at 10000")
 			limit = 10000
 		default:
 			limit = l
@@ -209,7 +308,7 @@ func (h *AlertHandlers) GetAlertHistory(w http.ResponseWriter, r *http.Request)
 			}
 			offset = o
 		} else {
-			log.Warn().Str("offset", offsetStr).Msg("Invalid offset parameter, ignoring")
+			log.Warn().Ctx(r.Context()).Str("offset", offsetStr).Msg("Invalid offset parameter, ignoring")
 		}
 	}
 
@@ -244,7 +343,7 @@ func (h *AlertHandlers) GetAlertHistory(w http.ResponseWriter, r *http.Request)
 		severity = ""
 	case "warning", "critical":
 	default:
-		log.Warn().Str("severity", severity).Msg("Invalid severity filter, ignoring")
+		log.Warn().Ctx(r.Context()).Str("severity", severity).Msg("Invalid severity filter, ignoring")
 		severity = ""
 	}
 
@@ -294,7 +393,7 @@ func (h *AlertHandlers) GetAlertHistory(w http.ResponseWriter, r *http.Request)
 
 	// Check if mock mode is enabled
 	mockEnabled := mock.IsMockEnabled()
-	log.Debug().Bool("mockEnabled", mockEnabled).Msg("GetAlertHistory: mock mode status")
+	log.Debug().Ctx(r.Context()).Bool("mockEnabled", mockEnabled).Msg("GetAlertHistory: mock mode status")
 
 	fetchLimit := limit
 	if fetchLimit > 0 && offset > 0 {
@@ -311,7 +410,7 @@ func (h *AlertHandlers) GetAlertHistory(w http.ResponseWriter, r *http.Request)
 		}
 		filtered = trimMockAlerts(filtered)
 		if err := utils.WriteJSONResponse(w, filtered); err != nil {
-			log.Error().Err(err).Msg("Failed to write mock alert history response")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write mock alert history response")
 		}
 		return
 	}
@@ -329,16 +428,23 @@ func (h *AlertHandlers) GetAlertHistory(w http.ResponseWriter, r *http.Request)
 		history = manager.GetAlertHistory(fetchLimit)
 	}
 
+	scope := requestTenantScope(h.config, r)
+	var guests tenancy.Guests
+	if scope != nil {
+		guests = scope.Guests(h.monitor.GetState())
+	}
+
 	filtered := make([]alerts.Alert, 0, len(history))
 	for _, alert := range history {
-		if matchesFilters(alert.StartTime, string(alert.Level), alert.ResourceID) {
+		if matchesFilters(alert.StartTime, string(alert.Level), alert.ResourceID) &&
+			scope.AllowsAlert(alert.Instance, alert.ResourceID, guests) {
 			filtered = append(filtered, alert)
 		}
 	}
 	filtered = trimAlerts(filtered)
 
 	if err := utils.WriteJSONResponse(w, filtered); err != nil {
-		log.Error().Err(err).Msg("Failed to write alert history response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write alert history response")
 	}
 }
 
@@ -350,7 +456,7 @@ func (h *AlertHandlers) ClearAlertHistory(w http.ResponseWriter, r *http.Request
 	}
 
 	if err := utils.WriteJSONResponse(w, map[string]string{"status": "success", "message": "Alert history cleared"}); err != nil {
-		log.Error().Err(err).Msg("Failed to write alert history clear confirmation")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write alert history clear confirmation")
 	}
 }
 
@@ -361,7 +467,7 @@ func (h *AlertHandlers) UnacknowledgeAlert(w http.ResponseWriter, r *http.Reques
 
 	const suffix = "/unacknowledge"
 	if !strings.HasSuffix(path, suffix) {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Msg("Path does not end with /unacknowledge")
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
@@ -372,13 +478,13 @@ func (h *AlertHandlers) UnacknowledgeAlert(w http.ResponseWriter, r *http.Reques
 	encodedID := strings.TrimSuffix(path, suffix)
 	alertID, err := url.PathUnescape(encodedID)
 	if err != nil {
-		log.Error().Err(err).Str("encodedID", encodedID).Msg("Failed to decode alert ID")
+		log.Error().Ctx(r.Context()).Err(err).Str("encodedID", encodedID).Msg("Failed to decode alert ID")
 		http.Error(w, "Invalid alert ID", http.StatusBadRequest)
 		return
 	}
 
 	if !validateAlertID(alertID) {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Str("alertID", alertID).
 			Msg("Invalid alert ID")
@@ -387,13 +493,13 @@ func (h *AlertHandlers) UnacknowledgeAlert(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Log the unacknowledge attempt
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("alertID", alertID).
 		Str("path", r.URL.Path).
 		Msg("Attempting to unacknowledge alert")
 
 	if err := h.monitor.GetAlertManager().UnacknowledgeAlert(alertID); err != nil {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Err(err).
 			Str("alertID", alertID).
 			Msg("Failed to unacknowledge alert")
@@ -403,13 +509,13 @@ func (h *AlertHandlers) UnacknowledgeAlert(w http.ResponseWriter, r *http.Reques
 
 	h.monitor.SyncAlertState()
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("alertID", alertID).
 		Msg("Alert unacknowledged successfully")
 
 	// Send response immediately
 	if err := utils.WriteJSONResponse(w, map[string]bool{"success": true}); err != nil {
-		log.Error().Err(err).Str("alertID", alertID).Msg("Failed to write unacknowledge response")
+		log.Error().Ctx(r.Context()).Err(err).Str("alertID", alertID).Msg("Failed to write unacknowledge response")
 	}
 
 	// Broadcast updated state to all WebSocket clients after response
@@ -418,7 +524,7 @@ func (h *AlertHandlers) UnacknowledgeAlert(w http.ResponseWriter, r *http.Reques
 		go func() {
 			state := h.monitor.GetState()
 			h.wsHub.BroadcastState(state.ToFrontend())
-			log.Debug().Msg("Broadcasted state after alert unacknowledgment")
+			log.Debug().Ctx(r.Context()).Msg("Broadcasted state after alert unacknowledgment")
 		}()
 	}
 }
@@ -432,7 +538,7 @@ func (h *AlertHandlers) AcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 
 	const suffix = "/acknowledge"
 	if !strings.HasSuffix(path, suffix) {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Msg("Path does not end with /acknowledge")
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
@@ -443,13 +549,13 @@ func (h *AlertHandlers) AcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 	encodedID := strings.TrimSuffix(path, suffix)
 	alertID, err := url.PathUnescape(encodedID)
 	if err != nil {
-		log.Error().Err(err).Str("encodedID", encodedID).Msg("Failed to decode alert ID")
+		log.Error().Ctx(r.Context()).Err(err).Str("encodedID", encodedID).Msg("Failed to decode alert ID")
 		http.Error(w, "Invalid alert ID", http.StatusBadRequest)
 		return
 	}
 
 	if !validateAlertID(alertID) {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Str("alertID", alertID).
 			Msg("Invalid alert ID")
@@ -458,7 +564,7 @@ func (h *AlertHandlers) AcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Log the acknowledge attempt
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("alertID", alertID).
 		Str("path", r.URL.Path).
 		Msg("Attempting to acknowledge alert")
@@ -466,12 +572,12 @@ func (h *AlertHandlers) AcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 	// In a real implementation, you'd get the user from authentication
 	user := "admin"
 
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("alertID", alertID).
 		Msg("About to call AcknowledgeAlert on manager")
 
 	if err := h.monitor.GetAlertManager().AcknowledgeAlert(alertID, user); err != nil {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Err(err).
 			Str("alertID", alertID).
 			Msg("Failed to acknowledge alert")
@@ -481,14 +587,14 @@ func (h *AlertHandlers) AcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 
 	h.monitor.SyncAlertState()
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("alertID", alertID).
 		Str("user", user).
 		Msg("Alert acknowledged successfully")
 
 	// Send response immediately
 	if err := utils.WriteJSONResponse(w, map[string]bool{"success": true}); err != nil {
-		log.Error().Err(err).Str("alertID", alertID).Msg("Failed to write acknowledge response")
+		log.Error().Ctx(r.Context()).Err(err).Str("alertID", alertID).Msg("Failed to write acknowledge response")
 	}
 
 	// Broadcast updated state to all WebSocket clients after response
@@ -497,7 +603,7 @@ func (h *AlertHandlers) AcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 		go func() {
 			state := h.monitor.GetState()
 			h.wsHub.BroadcastState(state.ToFrontend())
-			log.Debug().Msg("Broadcasted state after alert acknowledgment")
+			log.Debug().Ctx(r.Context()).Msg("Broadcasted state after alert acknowledgment")
 		}()
 	}
 }
@@ -511,7 +617,7 @@ func (h *AlertHandlers) ClearAlert(w http.ResponseWriter, r *http.Request) {
 
 	const suffix = "/clear"
 	if !strings.HasSuffix(path, suffix) {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Msg("Path does not end with /clear")
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
@@ -522,13 +628,13 @@ func (h *AlertHandlers) ClearAlert(w http.ResponseWriter, r *http.Request) {
 	encodedID := strings.TrimSuffix(path, suffix)
 	alertID, err := url.PathUnescape(encodedID)
 	if err != nil {
-		log.Error().Err(err).Str("encodedID", encodedID).Msg("Failed to decode alert ID")
+		log.Error().Ctx(r.Context()).Err(err).Str("encodedID", encodedID).Msg("Failed to decode alert ID")
 		http.Error(w, "Invalid alert ID", http.StatusBadRequest)
 		return
 	}
 
 	if !validateAlertID(alertID) {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Str("alertID", alertID).
 			Msg("Invalid alert ID")
@@ -541,7 +647,7 @@ func (h *AlertHandlers) ClearAlert(w http.ResponseWriter, r *http.Request) {
 
 	// Send response immediately
 	if err := utils.WriteJSONResponse(w, map[string]bool{"success": true}); err != nil {
-		log.Error().Err(err).Str("alertID", alertID).Msg("Failed to write clear alert response")
+		log.Error().Ctx(r.Context()).Err(err).Str("alertID", alertID).Msg("Failed to write clear alert response")
 	}
 
 	// Broadcast updated state to all WebSocket clients after response
@@ -550,7 +656,7 @@ func (h *AlertHandlers) ClearAlert(w http.ResponseWriter, r *http.Request) {
 		go func() {
 			state := h.monitor.GetState()
 			h.wsHub.BroadcastState(state.ToFrontend())
-			log.Debug().Msg("Broadcasted state after alert clear")
+			log.Debug().Ctx(r.Context()).Msg("Broadcasted state after alert clear")
 		}()
 	}
 }
@@ -603,7 +709,7 @@ func (h *AlertHandlers) BulkAcknowledgeAlerts(w http.ResponseWriter, r *http.Req
 	if err := utils.WriteJSONResponse(w, map[string]interface{}{
 		"results": results,
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to write bulk acknowledge response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write bulk acknowledge response")
 	}
 
 	// Broadcast updated state to all WebSocket clients if any alerts were acknowledged
@@ -612,7 +718,7 @@ func (h *AlertHandlers) BulkAcknowledgeAlerts(w http.ResponseWriter, r *http.Req
 		go func() {
 			state := h.monitor.GetState()
 			h.wsHub.BroadcastState(state.ToFrontend())
-			log.Debug().Msg("Broadcasted state after bulk alert acknowledgment")
+			log.Debug().Ctx(r.Context()).Msg("Broadcasted state after bulk alert acknowledgment")
 		}()
 	}
 }
@@ -655,7 +761,7 @@ func (h *AlertHandlers) BulkClearAlerts(w http.ResponseWriter, r *http.Request)
 	if err := utils.WriteJSONResponse(w, map[string]interface{}{
 		"results": results,
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to write bulk clear response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write bulk clear response")
 	}
 
 	// Broadcast updated state to all WebSocket clients after response
@@ -664,7 +770,7 @@ func (h *AlertHandlers) BulkClearAlerts(w http.ResponseWriter, r *http.Request)
 		go func() {
 			state := h.monitor.GetState()
 			h.wsHub.BroadcastState(state.ToFrontend())
-			log.Debug().Msg("Broadcasted state after bulk alert clear")
+			log.Debug().Ctx(r.Context()).Msg("Broadcasted state after bulk alert clear")
 		}()
 	}
 }
@@ -673,7 +779,7 @@ func (h *AlertHandlers) BulkClearAlerts(w http.ResponseWriter, r *http.Request)
 func (h *AlertHandlers) HandleAlerts(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/alerts/")
 
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("originalPath", r.URL.Path).
 		Str("trimmedPath", path).
 		Str("method", r.Method).
@@ -684,6 +790,14 @@ func (h *AlertHandlers) HandleAlerts(w http.ResponseWriter, r *http.Request) {
 		h.GetAlertConfig(w, r)
 	case path == "config" && r.Method == http.MethodPut:
 		h.UpdateAlertConfig(w, r)
+	case path == "oncall" && r.Method == http.MethodGet:
+		h.GetOnCall(w, r)
+	case path == "silences" && r.Method == http.MethodGet:
+		h.GetSilences(w, r)
+	case path == "silences" && r.Method == http.MethodPost:
+		h.CreateSilence(w, r)
+	case strings.HasPrefix(path, "silences/") && r.Method == http.MethodDelete:
+		h.DeleteSilence(w, r)
 	case path == "activate" && r.Method == http.MethodPost:
 		h.ActivateAlerts(w, r)
 	case path == "active" && r.Method == http.MethodGet:
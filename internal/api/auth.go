@@ -73,7 +73,7 @@ func getCookieSettings(r *http.Request) (secure bool, sameSite http.SameSite) {
 
 	// Debug logging for Cloudflare tunnel issues
 	if isProxied {
-		log.Debug().
+		log.Debug().Ctx(r.Context()).
 			Bool("proxied", isProxied).
 			Bool("secure", isSecure).
 			Str("cf_ray", r.Header.Get("CF-Ray")).
@@ -126,7 +126,7 @@ func CheckProxyAuth(cfg *config.Config, r *http.Request) (bool, string, bool) {
 	// Validate proxy secret header
 	proxySecret := r.Header.Get("X-Proxy-Secret")
 	if proxySecret != cfg.ProxyAuthSecret {
-		log.Debug().
+		log.Debug().Ctx(r.Context()).
 			Str("provided_secret", proxySecret[:min(8, len(proxySecret))]+"...").
 			Msg("Invalid proxy secret")
 		return false, "", false
@@ -137,7 +137,7 @@ func CheckProxyAuth(cfg *config.Config, r *http.Request) (bool, string, bool) {
 	if cfg.ProxyAuthUserHeader != "" {
 		username = r.Header.Get(cfg.ProxyAuthUserHeader)
 		if username == "" {
-			log.Debug().Str("header", cfg.ProxyAuthUserHeader).Msg("Proxy auth user header not found")
+			log.Debug().Ctx(r.Context()).Str("header", cfg.ProxyAuthUserHeader).Msg("Proxy auth user header not found")
 			return false, "", false
 		}
 	}
@@ -160,14 +160,14 @@ func CheckProxyAuth(cfg *config.Config, r *http.Request) (bool, string, bool) {
 					break
 				}
 			}
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Str("roles", roles).
 				Bool("is_admin", isAdmin).
 				Msg("Proxy auth roles checked")
 		}
 	}
 
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("user", username).
 		Bool("is_admin", isAdmin).
 		Msg("Proxy authentication successful")
@@ -221,9 +221,9 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 	// If no auth is configured at all, allow access unless OIDC is enabled
 	if cfg.AuthUser == "" && cfg.AuthPass == "" && !cfg.HasAPITokens() && cfg.ProxyAuthSecret == "" {
 		if cfg.OIDC != nil && cfg.OIDC.Enabled {
-			log.Debug().Msg("OIDC enabled without local credentials, authentication required")
+			log.Debug().Ctx(r.Context()).Msg("OIDC enabled without local credentials, authentication required")
 		} else {
-			log.Debug().Msg("No auth configured, allowing access")
+			log.Debug().Ctx(r.Context()).Msg("No auth configured, allowing access")
 			return true
 		}
 	}
@@ -269,7 +269,7 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 			}
 			for _, path := range allowedPaths {
 				if r.URL.Path == path || strings.HasPrefix(r.URL.Path, path+"/") {
-					log.Debug().Str("path", r.URL.Path).Msg("Allowing read-only access in API-only mode")
+					log.Debug().Ctx(r.Context()).Str("path", r.URL.Path).Msg("Allowing read-only access in API-only mode")
 					return true
 				}
 			}
@@ -283,7 +283,7 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 		return false
 	}
 
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("configured_user", cfg.AuthUser).
 		Bool("has_pass", cfg.AuthPass != "").
 		Bool("has_token", cfg.HasAPITokens()).
@@ -324,14 +324,14 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 			return true
 		} else {
 			// Debug logging for failed session validation
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Str("session_token", cookie.Value[:8]+"...").
 				Str("path", r.URL.Path).
 				Msg("Session validation failed - token not found or expired")
 		}
 	} else if err != nil {
 		// Debug logging when no session cookie found
-		log.Debug().
+		log.Debug().Ctx(r.Context()).
 			Err(err).
 			Str("path", r.URL.Path).
 			Bool("has_cf_headers", r.Header.Get("CF-Ray") != "").
@@ -341,7 +341,7 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 	// Check basic auth
 	if cfg.AuthUser != "" && cfg.AuthPass != "" {
 		auth := r.Header.Get("Authorization")
-		log.Debug().Str("auth_header", auth).Str("url", r.URL.Path).Msg("Checking auth")
+		log.Debug().Ctx(r.Context()).Str("auth_header", auth).Str("url", r.URL.Path).Msg("Checking auth")
 		if auth != "" {
 			const prefix = "Basic "
 			if strings.HasPrefix(auth, prefix) {
@@ -356,7 +356,7 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 						if r.URL.Path == "/api/login" {
 							// Check rate limiting for auth attempts
 							if !authLimiter.Allow(clientIP) {
-								log.Warn().Str("ip", clientIP).Msg("Rate limit exceeded for auth")
+								log.Warn().Ctx(r.Context()).Str("ip", clientIP).Msg("Rate limit exceeded for auth")
 								LogAuditEvent("login", parts[0], clientIP, r.URL.Path, false, "Rate limited")
 								if w != nil {
 									http.Error(w, "Too many authentication attempts", http.StatusTooManyRequests)
@@ -380,7 +380,7 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 								remainingMinutes = 1
 							}
 
-							log.Warn().Str("user", parts[0]).Str("ip", clientIP).Msg("Account locked out")
+							log.Warn().Ctx(r.Context()).Str("user", parts[0]).Str("ip", clientIP).Msg("Account locked out")
 							LogAuditEvent("login", parts[0], clientIP, r.URL.Path, false, "Account locked")
 							if w != nil {
 								w.Header().Set("Content-Type", "application/json")
@@ -397,7 +397,7 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 						// Config always has hashed password now (auto-hashed on load)
 						passMatch := internalauth.CheckPasswordHash(parts[1], cfg.AuthPass)
 
-						log.Debug().
+						log.Debug().Ctx(r.Context()).
 							Str("provided_user", parts[0]).
 							Str("expected_user", cfg.AuthUser).
 							Bool("user_match", userMatch).
@@ -441,7 +441,7 @@ func CheckAuth(cfg *config.Config, w http.ResponseWriter, r *http.Request) bool
 									sameSiteName = "Strict"
 								}
 
-								log.Debug().
+								log.Debug().Ctx(r.Context()).
 									Bool("secure", isSecure).
 									Str("same_site", sameSiteName).
 									Str("token", token[:8]+"...").
@@ -517,7 +517,7 @@ func RequireAuth(cfg *config.Config, handler http.HandlerFunc) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Dev mode bypass for all auth (disabled by default)
 		if os.Getenv("ALLOW_ADMIN_BYPASS") == "1" {
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Str("path", r.URL.Path).
 				Msg("Auth bypass enabled for dev mode")
 			handler(w, r)
@@ -530,7 +530,7 @@ func RequireAuth(cfg *config.Config, handler http.HandlerFunc) http.HandlerFunc
 		}
 
 		// Log the failed attempt
-		log.Warn().
+		log.Warn().Ctx(r.Context()).
 			Str("ip", r.RemoteAddr).
 			Str("path", r.URL.Path).
 			Str("method", r.Method).
@@ -556,12 +556,12 @@ func RequireAdmin(cfg *config.Config, handler http.HandlerFunc) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Dev mode bypass for admin endpoints (disabled by default)
 		bypassValue := os.Getenv("ALLOW_ADMIN_BYPASS")
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Str("bypass_value", bypassValue).
 			Str("path", r.URL.Path).
 			Msg("=== CHECKING ADMIN BYPASS ===")
 		if bypassValue == "1" {
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Str("path", r.URL.Path).
 				Msg("Admin bypass enabled for dev mode")
 			handler(w, r)
@@ -571,7 +571,7 @@ func RequireAdmin(cfg *config.Config, handler http.HandlerFunc) http.HandlerFunc
 		// First check if user is authenticated
 		if !CheckAuth(cfg, w, r) {
 			// Log the failed attempt
-			log.Warn().
+			log.Warn().Ctx(r.Context()).
 				Str("ip", r.RemoteAddr).
 				Str("path", r.URL.Path).
 				Str("method", r.Method).
@@ -593,7 +593,7 @@ func RequireAdmin(cfg *config.Config, handler http.HandlerFunc) http.HandlerFunc
 			if valid, username, isAdmin := CheckProxyAuth(cfg, r); valid {
 				if !isAdmin {
 					// User is authenticated but not an admin
-					log.Warn().
+					log.Warn().Ctx(r.Context()).
 						Str("ip", r.RemoteAddr).
 						Str("path", r.URL.Path).
 						Str("method", r.Method).
@@ -613,6 +613,25 @@ func RequireAdmin(cfg *config.Config, handler http.HandlerFunc) http.HandlerFunc
 			}
 		}
 
+		// Users restricted to tenant scopes never get admin access
+		if scope := requestTenantScope(cfg, r); scope != nil {
+			log.Warn().Ctx(r.Context()).
+				Str("ip", r.RemoteAddr).
+				Str("path", r.URL.Path).
+				Str("method", r.Method).
+				Str("scope", scope.Key()).
+				Msg("Tenant scoped user attempted to access admin endpoint")
+
+			if strings.HasPrefix(r.URL.Path, "/api/") || strings.Contains(r.Header.Get("Accept"), "application/json") {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error":"Admin privileges required"}`))
+			} else {
+				http.Error(w, "Admin privileges required", http.StatusForbidden)
+			}
+			return
+		}
+
 		// User is authenticated and has admin privileges (or not using proxy auth)
 		handler(w, r)
 	}
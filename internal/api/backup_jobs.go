@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/rs/zerolog/log"
+)
+
+// maxBackupJobGuests caps how many guests a single backup request may include
+const maxBackupJobGuests = 500
+
+// BackupJobHandlers starts vzdump backups and manages automatic backup remediation
+type BackupJobHandlers struct {
+	config      *config.Config
+	monitor     *monitoring.Monitor
+	persistence *config.ConfigPersistence
+}
+
+type backupJobRequest struct {
+	Guests    []string `json:"guests"`
+	Storage   string   `json:"storage,omitempty"`
+	Mode      string   `json:"mode,omitempty"`
+	Compress  string   `json:"compress,omitempty"`
+	Notes     string   `json:"notes,omitempty"`
+	Protected bool     `json:"protected,omitempty"`
+}
+
+// NewBackupJobHandlers creates backup job handlers
+func NewBackupJobHandlers(cfg *config.Config, m *monitoring.Monitor, persistence *config.ConfigPersistence) *BackupJobHandlers {
+	return &BackupJobHandlers{config: cfg, monitor: m, persistence: persistence}
+}
+
+// SetMonitor updates the monitor reference for backup job handlers.
+func (h *BackupJobHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+}
+
+// HandleBackupJobs serves /api/backups/jobs
+func (h *BackupJobHandlers) HandleBackupJobs(w http.ResponseWriter, r *http.Request) {
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if err := utils.WriteJSONResponse(w, h.monitor.GetBackupJobs()); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write backup jobs response")
+		}
+	case http.MethodPost:
+		h.startBackupJobs(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *BackupJobHandlers) startBackupJobs(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024)
+	var req backupJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Guests) == 0 {
+		http.Error(w, "At least one guest is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Guests) > maxBackupJobGuests {
+		http.Error(w, fmt.Sprintf("At most %d guests can be backed up per request", maxBackupJobGuests), http.StatusBadRequest)
+		return
+	}
+
+	opts := proxmox.BackupOptions{
+		Storage:   strings.TrimSpace(req.Storage),
+		Mode:      proxmox.BackupMode(strings.ToLower(strings.TrimSpace(req.Mode))),
+		Compress:  strings.ToLower(strings.TrimSpace(req.Compress)),
+		Notes:     strings.TrimSpace(req.Notes),
+		Protected: req.Protected,
+	}
+	if err := opts.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user := requestUsername(h.config, w, r)
+	jobs, err := h.monitor.StartBackupJobs(req.Guests, opts, monitoring.BackupJobTriggerManual, user)
+
+	details := fmt.Sprintf("guests=%s storage=%s mode=%s", strings.Join(req.Guests, ","), opts.Storage, opts.Mode)
+	if err != nil {
+		details += " error=" + err.Error()
+	}
+	LogAuditEvent("backup_job_start", user, GetClientIP(r), r.URL.Path, err == nil, details)
+
+	if err != nil {
+		code := http.StatusBadGateway
+		if len(jobs) == 0 {
+			code = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(jobs); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write backup job response")
+	}
+}
+
+// HandleBackupRemediation serves /api/backups/remediation
+func (h *BackupJobHandlers) HandleBackupRemediation(w http.ResponseWriter, r *http.Request) {
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if err := utils.WriteJSONResponse(w, h.monitor.GetBackupRemediationConfig()); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write backup remediation response")
+		}
+	case http.MethodPut:
+		r.Body = http.MaxBytesReader(w, r.Body, 64*1024)
+		var cfg config.BackupRemediationConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		cfg = config.NormalizeBackupRemediationConfig(cfg)
+		if err := cfg.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h.persistence.SaveBackupRemediationConfig(cfg); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save backup remediation configuration")
+			http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+			return
+		}
+		h.monitor.SetBackupRemediationConfig(cfg)
+
+		LogAuditEvent("backup_remediation_config", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true,
+			fmt.Sprintf("enabled=%t storage=%s maxConcurrentPerNode=%d", cfg.Enabled, cfg.Storage, cfg.MaxConcurrentPerNode))
+
+		if err := utils.WriteJSONResponse(w, h.monitor.GetBackupRemediationConfig()); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write backup remediation response")
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/chatops"
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/RouXx67/PulseUp/internal/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// ChatOpsHandlers serves inbound chat command endpoints and their configuration
+type ChatOpsHandlers struct {
+	service     *chatops.Service
+	persistence *config.ConfigPersistence
+}
+
+// NewChatOpsHandlers creates chat-ops handlers and applies the saved configuration
+func NewChatOpsHandlers(monitor *monitoring.Monitor, wsHub *websocket.Hub, persistence *config.ConfigPersistence) *ChatOpsHandlers {
+	h := &ChatOpsHandlers{
+		service:     chatops.NewService(&monitorChatOpsBackend{monitor: monitor, wsHub: wsHub}),
+		persistence: persistence,
+	}
+
+	if cfg, err := persistence.LoadChatOpsConfig(); err == nil {
+		h.service.SetConfig(*cfg)
+	} else {
+		log.Warn().Err(err).Msg("Failed to load chat-ops configuration")
+	}
+
+	return h
+}
+
+// HandleChatOps routes /api/chatops/ requests
+func (h *ChatOpsHandlers) HandleChatOps(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/chatops")
+
+	switch {
+	case path == "/slack":
+		h.service.HandleSlack(w, r)
+	case path == "/discord":
+		h.service.HandleDiscord(w, r)
+	case path == "/telegram":
+		h.service.HandleTelegramWebhook(w, r)
+	case path == "/config" && r.Method == http.MethodGet:
+		h.GetConfig(w, r)
+	case path == "/config" && r.Method == http.MethodPut:
+		h.UpdateConfig(w, r)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetConfig returns the chat-ops configuration without secrets
+func (h *ChatOpsHandlers) GetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.service.GetConfig()
+
+	// For security, don't return tokens or signing secrets
+	cfg.Telegram.BotToken = ""
+	cfg.Telegram.WebhookSecret = ""
+	cfg.Slack.SigningSecret = ""
+
+	if err := utils.WriteJSONResponse(w, cfg); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write chat-ops configuration response")
+	}
+}
+
+// UpdateConfig updates the chat-ops configuration. Empty secrets keep the saved values.
+func (h *ChatOpsHandlers) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	var cfg config.ChatOpsConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	existing := h.service.GetConfig()
+	if cfg.Telegram.BotToken == "" {
+		cfg.Telegram.BotToken = existing.Telegram.BotToken
+	}
+	if cfg.Telegram.WebhookSecret == "" {
+		cfg.Telegram.WebhookSecret = existing.Telegram.WebhookSecret
+	}
+	if cfg.Slack.SigningSecret == "" {
+		cfg.Slack.SigningSecret = existing.Slack.SigningSecret
+	}
+
+	cfg = config.NormalizeChatOpsConfig(cfg)
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.persistence.SaveChatOpsConfig(cfg); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save chat-ops configuration")
+		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+		return
+	}
+	h.service.SetConfig(cfg)
+
+	if err := utils.WriteJSONResponse(w, map[string]string{"status": "success"}); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write chat-ops update response")
+	}
+}
+
+// monitorChatOpsBackend executes chat commands against the live monitor
+type monitorChatOpsBackend struct {
+	monitor *monitoring.Monitor
+	wsHub   *websocket.Hub
+}
+
+func (b *monitorChatOpsBackend) AcknowledgeAlert(alertID, user string) error {
+	if err := b.monitor.GetAlertManager().AcknowledgeAlert(alertID, user); err != nil {
+		return err
+	}
+	b.monitor.SyncAlertState()
+	if b.wsHub != nil {
+		go func() {
+			state := b.monitor.GetState()
+			b.wsHub.BroadcastState(state.ToFrontend())
+		}()
+	}
+	return nil
+}
+
+func (b *monitorChatOpsBackend) SilenceResource(resource string, duration time.Duration, user, reason string) (alerts.Silence, error) {
+	return b.monitor.GetAlertManager().SilenceResource(resource, duration, user, reason)
+}
+
+func (b *monitorChatOpsBackend) ActiveAlerts() []alerts.Alert {
+	return b.monitor.GetAlertManager().GetActiveAlerts()
+}
+
+func (b *monitorChatOpsBackend) GuestStatus(query string) (chatops.GuestStatus, bool) {
+	state := b.monitor.GetState()
+	vmid, _ := strconv.Atoi(query)
+	matches := func(id, name string, guestVMID int) bool {
+		return id == query || strings.EqualFold(name, query) || (vmid > 0 && guestVMID == vmid)
+	}
+
+	for _, vm := range state.VMs {
+		if !vm.Template && matches(vm.ID, vm.Name, vm.VMID) {
+			return chatops.GuestStatus{
+				ID: vm.ID, VMID: vm.VMID, Name: vm.Name, Type: "VM", Node: vm.Node, Instance: vm.Instance,
+				Status: vm.Status, CPUPercent: vm.CPU * 100, MemoryUsage: vm.Memory.Usage, DiskUsage: vm.Disk.Usage,
+				Uptime: time.Duration(vm.Uptime) * time.Second,
+			}, true
+		}
+	}
+	for _, ct := range state.Containers {
+		if !ct.Template && matches(ct.ID, ct.Name, ct.VMID) {
+			return chatops.GuestStatus{
+				ID: ct.ID, VMID: ct.VMID, Name: ct.Name, Type: "CT", Node: ct.Node, Instance: ct.Instance,
+				Status: ct.Status, CPUPercent: ct.CPU * 100, MemoryUsage: ct.Memory.Usage, DiskUsage: ct.Disk.Usage,
+				Uptime: time.Duration(ct.Uptime) * time.Second,
+			}, true
+		}
+	}
+	return chatops.GuestStatus{}, false
+}
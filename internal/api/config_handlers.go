@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -62,6 +63,7 @@ type ConfigHandlers struct {
 	lastClusterDetection     map[string]time.Time
 	recentAutoRegistered     map[string]time.Time
 	recentAutoRegMutex       sync.Mutex
+	nodesMu                  sync.RWMutex // Guards the node lists in config against concurrent changes from the API and discovery
 }
 
 // NewConfigHandlers creates a new ConfigHandlers instance
@@ -188,6 +190,9 @@ func (h *ConfigHandlers) isRecentlyAutoRegistered(nodeType, nodeName string) boo
 }
 
 func (h *ConfigHandlers) findInstanceNameByHost(nodeType, host string) string {
+	h.nodesMu.RLock()
+	defer h.nodesMu.RUnlock()
+
 	switch nodeType {
 	case "pve":
 		for _, node := range h.config.PVEInstances {
@@ -335,6 +340,7 @@ type NodeConfigRequest struct {
 	MonitorStorage       bool   `json:"monitorStorage,omitempty"`       // PVE only
 	MonitorBackups       bool   `json:"monitorBackups,omitempty"`       // PVE only
 	MonitorPhysicalDisks *bool  `json:"monitorPhysicalDisks,omitempty"` // PVE only (nil = enabled by default)
+	PollIntervalSeconds  *int   `json:"pollIntervalSeconds,omitempty"`  // PVE only (0 = adaptive, nil = unchanged)
 	MonitorDatastores    bool   `json:"monitorDatastores,omitempty"`    // PBS only
 	MonitorSyncJobs      bool   `json:"monitorSyncJobs,omitempty"`      // PBS only
 	MonitorVerifyJobs    bool   `json:"monitorVerifyJobs,omitempty"`    // PBS only
@@ -344,38 +350,59 @@ type NodeConfigRequest struct {
 	MonitorQueues        bool   `json:"monitorQueues,omitempty"`        // PMG only
 	MonitorQuarantine    bool   `json:"monitorQuarantine,omitempty"`    // PMG only
 	MonitorDomainStats   bool   `json:"monitorDomainStats,omitempty"`   // PMG only
+
+	Filters *config.PVEResourceFilter `json:"filters,omitempty"` // PVE only (nil = unchanged)
+}
+
+// validatePVEPollingRequest checks a PVE node's poll interval and resource filters
+func validatePVEPollingRequest(req *NodeConfigRequest) error {
+	var pve config.PVEInstance
+	applyPVEPollingRequest(&pve, req)
+	return pve.ValidatePollingOptions()
+}
+
+// applyPVEPollingRequest copies the poll interval and resource filters set in req
+func applyPVEPollingRequest(pve *config.PVEInstance, req *NodeConfigRequest) {
+	if req.PollIntervalSeconds != nil {
+		pve.PollIntervalSeconds = *req.PollIntervalSeconds
+	}
+	if req.Filters != nil {
+		pve.Filters = *req.Filters
+	}
 }
 
 // NodeResponse represents a node in API responses
 type NodeResponse struct {
-	ID                   string                   `json:"id"`
-	Type                 string                   `json:"type"`
-	Name                 string                   `json:"name"`
-	Host                 string                   `json:"host"`
-	User                 string                   `json:"user,omitempty"`
-	HasPassword          bool                     `json:"hasPassword"`
-	TokenName            string                   `json:"tokenName,omitempty"`
-	HasToken             bool                     `json:"hasToken"`
-	Fingerprint          string                   `json:"fingerprint,omitempty"`
-	VerifySSL            bool                     `json:"verifySSL"`
-	MonitorVMs           bool                     `json:"monitorVMs,omitempty"`
-	MonitorContainers    bool                     `json:"monitorContainers,omitempty"`
-	MonitorStorage       bool                     `json:"monitorStorage,omitempty"`
-	MonitorBackups       bool                     `json:"monitorBackups,omitempty"`
-	MonitorPhysicalDisks *bool                    `json:"monitorPhysicalDisks,omitempty"`
-	MonitorDatastores    bool                     `json:"monitorDatastores,omitempty"`
-	MonitorSyncJobs      bool                     `json:"monitorSyncJobs,omitempty"`
-	MonitorVerifyJobs    bool                     `json:"monitorVerifyJobs,omitempty"`
-	MonitorPruneJobs     bool                     `json:"monitorPruneJobs,omitempty"`
-	MonitorGarbageJobs   bool                     `json:"monitorGarbageJobs,omitempty"`
-	MonitorMailStats     bool                     `json:"monitorMailStats,omitempty"`
-	MonitorQueues        bool                     `json:"monitorQueues,omitempty"`
-	MonitorQuarantine    bool                     `json:"monitorQuarantine,omitempty"`
-	MonitorDomainStats   bool                     `json:"monitorDomainStats,omitempty"`
-	Status               string                   `json:"status"` // "connected", "disconnected", "error"
-	IsCluster            bool                     `json:"isCluster,omitempty"`
-	ClusterName          string                   `json:"clusterName,omitempty"`
-	ClusterEndpoints     []config.ClusterEndpoint `json:"clusterEndpoints,omitempty"`
+	ID                   string                    `json:"id"`
+	Type                 string                    `json:"type"`
+	Name                 string                    `json:"name"`
+	Host                 string                    `json:"host"`
+	User                 string                    `json:"user,omitempty"`
+	HasPassword          bool                      `json:"hasPassword"`
+	TokenName            string                    `json:"tokenName,omitempty"`
+	HasToken             bool                      `json:"hasToken"`
+	Fingerprint          string                    `json:"fingerprint,omitempty"`
+	VerifySSL            bool                      `json:"verifySSL"`
+	MonitorVMs           bool                      `json:"monitorVMs,omitempty"`
+	MonitorContainers    bool                      `json:"monitorContainers,omitempty"`
+	MonitorStorage       bool                      `json:"monitorStorage,omitempty"`
+	MonitorBackups       bool                      `json:"monitorBackups,omitempty"`
+	MonitorPhysicalDisks *bool                     `json:"monitorPhysicalDisks,omitempty"`
+	PollIntervalSeconds  int                       `json:"pollIntervalSeconds,omitempty"`
+	Filters              *config.PVEResourceFilter `json:"filters,omitempty"`
+	MonitorDatastores    bool                      `json:"monitorDatastores,omitempty"`
+	MonitorSyncJobs      bool                      `json:"monitorSyncJobs,omitempty"`
+	MonitorVerifyJobs    bool                      `json:"monitorVerifyJobs,omitempty"`
+	MonitorPruneJobs     bool                      `json:"monitorPruneJobs,omitempty"`
+	MonitorGarbageJobs   bool                      `json:"monitorGarbageJobs,omitempty"`
+	MonitorMailStats     bool                      `json:"monitorMailStats,omitempty"`
+	MonitorQueues        bool                      `json:"monitorQueues,omitempty"`
+	MonitorQuarantine    bool                      `json:"monitorQuarantine,omitempty"`
+	MonitorDomainStats   bool                      `json:"monitorDomainStats,omitempty"`
+	Status               string                    `json:"status"` // "connected", "disconnected", "error"
+	IsCluster            bool                      `json:"isCluster,omitempty"`
+	ClusterName          string                    `json:"clusterName,omitempty"`
+	ClusterEndpoints     []config.ClusterEndpoint  `json:"clusterEndpoints,omitempty"`
 }
 
 // deriveSchemeAndPort infers the scheme (without ://) and port from a base host URL.
@@ -681,6 +708,10 @@ func detectPVECluster(clientConfig proxmox.ClientConfig, nodeName string) (isClu
 
 // GetAllNodesForAPI returns all configured nodes for API responses
 func (h *ConfigHandlers) GetAllNodesForAPI() []NodeResponse {
+	// Cluster metadata may be refreshed and saved while listing
+	h.nodesMu.Lock()
+	defer h.nodesMu.Unlock()
+
 	nodes := []NodeResponse{}
 
 	// Add PVE nodes
@@ -704,11 +735,16 @@ func (h *ConfigHandlers) GetAllNodesForAPI() []NodeResponse {
 			MonitorStorage:       pve.MonitorStorage,
 			MonitorBackups:       pve.MonitorBackups,
 			MonitorPhysicalDisks: pve.MonitorPhysicalDisks,
+			PollIntervalSeconds:  pve.PollIntervalSeconds,
 			Status:               h.getNodeStatus("pve", pve.Name),
 			IsCluster:            pve.IsCluster,
 			ClusterName:          pve.ClusterName,
 			ClusterEndpoints:     pve.ClusterEndpoints,
 		}
+		if !pve.Filters.IsEmpty() {
+			filters := pve.Filters
+			node.Filters = &filters
+		}
 		nodes = append(nodes, node)
 	}
 
@@ -955,20 +991,14 @@ func extractHostAndPort(hostStr string) (string, string, error) {
 
 // HandleAddNode adds a new node
 func (h *ConfigHandlers) HandleAddNode(w http.ResponseWriter, r *http.Request) {
-	// Prevent node modifications in mock mode
-	if mock.IsMockEnabled() {
-		http.Error(w, "Cannot modify nodes in mock mode. Please disable mock mode first: /opt/pulse/scripts/toggle-mock.sh off", http.StatusForbidden)
-		return
-	}
-
 	var req NodeConfigRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Error().Err(err).Msg("Failed to decode add node request")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to decode add node request")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("type", req.Type).
 		Str("name", req.Name).
 		Str("host", req.Host).
@@ -977,27 +1007,46 @@ func (h *ConfigHandlers) HandleAddNode(w http.ResponseWriter, r *http.Request) {
 		Bool("hasTokenValue", req.TokenValue != "").
 		Msg("Add node request received")
 
+	if err := h.addNode(req); err != nil {
+		writeNodeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// addNode validates a node request, adds the node to the configuration, saves it and reloads the
+// monitor. Errors are *APIError values carrying the HTTP status for the add-node endpoint.
+func (h *ConfigHandlers) addNode(req NodeConfigRequest) error {
+	// Prevent node modifications in mock mode
+	if mock.IsMockEnabled() {
+		return NewAPIError(http.StatusForbidden, "mock_mode", "Cannot modify nodes in mock mode. Please disable mock mode first: /opt/pulse/scripts/toggle-mock.sh off")
+	}
+
 	// Validate required fields
 	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
-		return
+		return NewAPIError(http.StatusBadRequest, "validation_error", "Name is required")
 	}
 
 	if req.Type == "" {
-		http.Error(w, "Type is required", http.StatusBadRequest)
-		return
+		return NewAPIError(http.StatusBadRequest, "validation_error", "Type is required")
 	}
 
 	if req.Host == "" {
-		http.Error(w, "Host is required", http.StatusBadRequest)
-		return
+		return NewAPIError(http.StatusBadRequest, "validation_error", "Host is required")
+	}
+
+	if req.Type == "pve" {
+		if err := validatePVEPollingRequest(&req); err != nil {
+			return NewAPIError(http.StatusBadRequest, "validation_error", err.Error())
+		}
 	}
 
 	// Validate host format (IP address or hostname with optional port)
 	host, port, err := extractHostAndPort(req.Host)
 	if err != nil {
-		http.Error(w, "Invalid host format", http.StatusBadRequest)
-		return
+		return NewAPIError(http.StatusBadRequest, "validation_error", "Invalid host format")
 	}
 
 	// If it looks like an IP address, validate it strictly
@@ -1005,66 +1054,58 @@ func (h *ConfigHandlers) HandleAddNode(w http.ResponseWriter, r *http.Request) {
 	if len(host) > 0 && (host[0] >= '0' && host[0] <= '9') {
 		// Likely an IP address, validate strictly
 		if !validateIPAddress(host) {
-			http.Error(w, "Invalid IP address", http.StatusBadRequest)
-			return
+			return NewAPIError(http.StatusBadRequest, "validation_error", "Invalid IP address")
 		}
 	} else if strings.Contains(host, ":") && strings.Contains(host, "[") {
 		// IPv6 address with brackets
 		ipv6 := strings.TrimPrefix(strings.TrimSuffix(host, "]"), "[")
 		if !validateIPAddress(ipv6) {
-			http.Error(w, "Invalid IPv6 address", http.StatusBadRequest)
-			return
+			return NewAPIError(http.StatusBadRequest, "validation_error", "Invalid IPv6 address")
 		}
 	} else if req.Type == "pbs" {
 		// Validate as hostname - no spaces or special characters
 		if strings.ContainsAny(host, " /\\<>|\"'`;") {
-			http.Error(w, "Invalid hostname", http.StatusBadRequest)
-			return
+			return NewAPIError(http.StatusBadRequest, "validation_error", "Invalid hostname")
 		}
 	}
 
 	// Validate port if provided
 	if port != "" && !validatePort(port) {
-		http.Error(w, "Invalid port number", http.StatusBadRequest)
-		return
+		return NewAPIError(http.StatusBadRequest, "validation_error", "Invalid port number")
 	}
 
 	if req.Type != "pve" && req.Type != "pbs" && req.Type != "pmg" {
-		http.Error(w, "Invalid node type", http.StatusBadRequest)
-		return
+		return NewAPIError(http.StatusBadRequest, "validation_error", "Invalid node type")
 	}
 
 	// Check for authentication
 	hasAuth := (req.User != "" && req.Password != "") || (req.TokenName != "" && req.TokenValue != "")
 	if !hasAuth {
-		http.Error(w, "Authentication credentials required", http.StatusBadRequest)
-		return
+		return NewAPIError(http.StatusBadRequest, "validation_error", "Authentication credentials required")
 	}
 
-	// Check for duplicate nodes by name
-	switch req.Type {
-	case "pve":
-		for _, node := range h.config.PVEInstances {
-			if node.Name == req.Name {
-				http.Error(w, "A node with this name already exists", http.StatusConflict)
-				return
-			}
-		}
-	case "pbs":
-		for _, node := range h.config.PBSInstances {
-			if node.Name == req.Name {
-				http.Error(w, "A node with this name already exists", http.StatusConflict)
-				return
-			}
-		}
-	case "pmg":
-		for _, node := range h.config.PMGInstances {
-			if node.Name == req.Name {
-				http.Error(w, "A node with this name already exists", http.StatusConflict)
-				return
-			}
+	h.nodesMu.Lock()
+	err = h.appendNodeLocked(req)
+	h.nodesMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Reload monitor with new configuration
+	if h.reloadFunc != nil {
+		if err := h.reloadFunc(); err != nil {
+			log.Error().Err(err).Msg("Failed to reload monitor")
+			return NewAPIError(http.StatusInternalServerError, "reload_failed", "Configuration saved but failed to apply changes")
 		}
 	}
+	return nil
+}
+
+// appendNodeLocked adds a validated node and saves the node configuration. Callers must hold h.nodesMu.
+func (h *ConfigHandlers) appendNodeLocked(req NodeConfigRequest) error {
+	if h.nodeNameTakenLocked(req.Type, req.Name) {
+		return NewAPIError(http.StatusConflict, "node_exists", "A node with this name already exists")
+	}
 
 	// Add to appropriate list
 	if req.Type == "pve" {
@@ -1129,6 +1170,7 @@ func (h *ConfigHandlers) HandleAddNode(w http.ResponseWriter, r *http.Request) {
 			ClusterName:          clusterName,
 			ClusterEndpoints:     clusterEndpoints,
 		}
+		applyPVEPollingRequest(&pve, &req)
 		h.config.PVEInstances = append(h.config.PVEInstances, pve)
 
 		if isCluster {
@@ -1252,33 +1294,65 @@ func (h *ConfigHandlers) HandleAddNode(w http.ResponseWriter, r *http.Request) {
 	// Save configuration to disk using our persistence instance
 	if err := h.persistence.SaveNodesConfig(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances); err != nil {
 		log.Error().Err(err).Msg("Failed to save nodes configuration")
-		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
-		return
+		return NewAPIError(http.StatusInternalServerError, "save_failed", "Failed to save configuration")
 	}
+	return nil
+}
 
-	// Reload monitor with new configuration
-	if h.reloadFunc != nil {
-		if err := h.reloadFunc(); err != nil {
-			log.Error().Err(err).Msg("Failed to reload monitor")
-			http.Error(w, "Configuration saved but failed to apply changes", http.StatusInternalServerError)
-			return
+// nodeNameTakenLocked reports whether a node of the type already uses the name. Callers must hold h.nodesMu.
+func (h *ConfigHandlers) nodeNameTakenLocked(nodeType, name string) bool {
+	switch nodeType {
+	case "pve":
+		for _, node := range h.config.PVEInstances {
+			if node.Name == name {
+				return true
+			}
+		}
+	case "pbs":
+		for _, node := range h.config.PBSInstances {
+			if node.Name == name {
+				return true
+			}
+		}
+	case "pmg":
+		for _, node := range h.config.PMGInstances {
+			if node.Name == name {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+// nodeInstances returns copies of the configured node lists
+func (h *ConfigHandlers) nodeInstances() ([]config.PVEInstance, []config.PBSInstance, []config.PMGInstance) {
+	h.nodesMu.RLock()
+	defer h.nodesMu.RUnlock()
+	return append([]config.PVEInstance(nil), h.config.PVEInstances...),
+		append([]config.PBSInstance(nil), h.config.PBSInstances...),
+		append([]config.PMGInstance(nil), h.config.PMGInstances...)
+}
+
+// writeNodeError writes an error from addNode or testNodeConnection as a plain text response
+func writeNodeError(w http.ResponseWriter, err error) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		http.Error(w, apiErr.ErrorMessage, apiErr.StatusCode)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
 
 // HandleTestConnection tests a node connection without saving
 func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Request) {
 	var req NodeConfigRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Error().Err(err).Msg("Failed to decode test connection request")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to decode test connection request")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("type", req.Type).
 		Str("name", req.Name).
 		Str("host", req.Host).
@@ -1287,6 +1361,19 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 		Bool("hasTokenValue", req.TokenValue != "").
 		Msg("Test connection request received")
 
+	response, err := h.testNodeConnection(r.Context(), req)
+	if err != nil {
+		writeNodeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// testNodeConnection connects to a node described by a request without saving it and returns the
+// test-connection response. Errors are *APIError values carrying the HTTP status.
+func (h *ConfigHandlers) testNodeConnection(ctx context.Context, req NodeConfigRequest) (map[string]interface{}, error) {
 	// Parse token format if needed
 	user := req.User
 	tokenName := req.TokenName
@@ -1307,15 +1394,14 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("parsedUser", user).
 		Str("parsedTokenName", tokenName).
 		Msg("Parsed authentication details")
 
 	// Validate request
 	if req.Host == "" {
-		http.Error(w, "Host is required", http.StatusBadRequest)
-		return
+		return nil, NewAPIError(http.StatusBadRequest, "validation_error", "Host is required")
 	}
 
 	// Auto-generate name if not provided for test
@@ -1336,15 +1422,13 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 	}
 
 	if req.Type != "pve" && req.Type != "pbs" && req.Type != "pmg" {
-		http.Error(w, "Invalid node type", http.StatusBadRequest)
-		return
+		return nil, NewAPIError(http.StatusBadRequest, "validation_error", "Invalid node type")
 	}
 
 	// Check for authentication
 	hasAuth := (user != "" && req.Password != "") || (tokenName != "" && req.TokenValue != "")
 	if !hasAuth {
-		http.Error(w, "Authentication credentials required", http.StatusBadRequest)
-		return
+		return nil, NewAPIError(http.StatusBadRequest, "validation_error", "Authentication credentials required")
 	}
 
 	// Test connection based on type
@@ -1382,18 +1466,16 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 
 		tempClient, err := proxmox.NewClient(clientConfig)
 		if err != nil {
-			http.Error(w, sanitizeErrorMessage(err, "create_client"), http.StatusBadRequest)
-			return
+			return nil, NewAPIError(http.StatusBadRequest, "connection_failed", sanitizeErrorMessage(err, "create_client"))
 		}
 
 		// Try to get nodes to test connection
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
 		nodes, err := tempClient.GetNodes(ctx)
 		if err != nil {
-			http.Error(w, sanitizeErrorMessage(err, "connection"), http.StatusBadRequest)
-			return
+			return nil, NewAPIError(http.StatusBadRequest, "connection_failed", sanitizeErrorMessage(err, "connection"))
 		}
 
 		isCluster, _, clusterEndpoints := detectPVECluster(clientConfig, req.Name)
@@ -1409,8 +1491,7 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 			response["clusterNodeCount"] = len(clusterEndpoints)
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+		return response, nil
 	} else if req.Type == "pbs" {
 		// Ensure host has protocol for PBS
 		host := req.Host
@@ -1434,7 +1515,7 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 			}
 		}
 
-		log.Info().
+		log.Info().Ctx(ctx).
 			Str("processedHost", host).
 			Msg("PBS host after port processing")
 
@@ -1470,18 +1551,16 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 
 		tempClient, err := pbs.NewClient(clientConfig)
 		if err != nil {
-			http.Error(w, sanitizeErrorMessage(err, "create_client"), http.StatusBadRequest)
-			return
+			return nil, NewAPIError(http.StatusBadRequest, "connection_failed", sanitizeErrorMessage(err, "create_client"))
 		}
 
 		// Try to get datastores to test connection
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
 		datastores, err := tempClient.GetDatastores(ctx)
 		if err != nil {
-			http.Error(w, sanitizeErrorMessage(err, "connection"), http.StatusBadRequest)
-			return
+			return nil, NewAPIError(http.StatusBadRequest, "connection_failed", sanitizeErrorMessage(err, "connection"))
 		}
 
 		response := map[string]interface{}{
@@ -1490,8 +1569,7 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 			"datastoreCount": len(datastores),
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+		return response, nil
 	} else {
 		host := req.Host
 		if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
@@ -1525,17 +1603,15 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 
 		tempClient, err := pmg.NewClient(clientConfig)
 		if err != nil {
-			http.Error(w, sanitizeErrorMessage(err, "create_client"), http.StatusBadRequest)
-			return
+			return nil, NewAPIError(http.StatusBadRequest, "connection_failed", sanitizeErrorMessage(err, "create_client"))
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
 		version, err := tempClient.GetVersion(ctx)
 		if err != nil {
-			http.Error(w, sanitizeErrorMessage(err, "connection"), http.StatusBadRequest)
-			return
+			return nil, NewAPIError(http.StatusBadRequest, "connection_failed", sanitizeErrorMessage(err, "connection"))
 		}
 
 		versionLabel := ""
@@ -1565,8 +1641,7 @@ func (h *ConfigHandlers) HandleTestConnection(w http.ResponseWriter, r *http.Req
 			}
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(response)
+		return response, nil
 	}
 }
 
@@ -1604,6 +1679,15 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if nodeType == "pve" {
+		if err := validatePVEPollingRequest(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.nodesMu.Lock()
+
 	// Update the node
 	if nodeType == "pve" && index < len(h.config.PVEInstances) {
 		pve := &h.config.PVEInstances[index]
@@ -1670,6 +1754,7 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 		pve.MonitorStorage = req.MonitorStorage
 		pve.MonitorBackups = req.MonitorBackups
 		pve.MonitorPhysicalDisks = req.MonitorPhysicalDisks
+		applyPVEPollingRequest(pve, &req)
 	} else if nodeType == "pbs" && index < len(h.config.PBSInstances) {
 		pbs := &h.config.PBSInstances[index]
 		pbs.Name = req.Name
@@ -1806,13 +1891,16 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 		pmgInst.MonitorQuarantine = req.MonitorQuarantine
 		pmgInst.MonitorDomainStats = req.MonitorDomainStats
 	} else {
+		h.nodesMu.Unlock()
 		http.Error(w, "Node not found", http.StatusNotFound)
 		return
 	}
 
 	// Save configuration to disk using our persistence instance
-	if err := h.persistence.SaveNodesConfig(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances); err != nil {
-		log.Error().Err(err).Msg("Failed to save nodes configuration")
+	err := h.persistence.SaveNodesConfig(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances)
+	h.nodesMu.Unlock()
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save nodes configuration")
 		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
 		return
 	}
@@ -1835,7 +1923,7 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 				// Check if there are overrides for this PBS node
 				if alertConfig.Overrides != nil {
 					if _, exists := alertConfig.Overrides[monitoringID]; exists {
-						log.Debug().
+						log.Debug().Ctx(r.Context()).
 							Str("nodeID", nodeID).
 							Str("monitoringID", monitoringID).
 							Str("pbsName", pbsName).
@@ -1846,7 +1934,7 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 
 			// Apply the alert configuration to preserve all overrides
 			h.monitor.GetAlertManager().UpdateConfig(*alertConfig)
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Str("nodeID", nodeID).
 				Str("nodeType", nodeType).
 				Msg("Preserved alert overrides after node update")
@@ -1856,7 +1944,7 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 	// Reload monitor with new configuration
 	if h.reloadFunc != nil {
 		if err := h.reloadFunc(); err != nil {
-			log.Error().Err(err).Msg("Failed to reload monitor")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload monitor")
 			http.Error(w, "Configuration saved but failed to apply changes", http.StatusInternalServerError)
 			return
 		}
@@ -1864,7 +1952,7 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 
 	// Trigger discovery refresh after adding node
 	if h.monitor != nil && h.monitor.GetDiscoveryService() != nil {
-		log.Info().Msg("Triggering discovery refresh after adding node")
+		log.Info().Ctx(r.Context()).Msg("Triggering discovery refresh after adding node")
 		h.monitor.GetDiscoveryService().ForceRefresh()
 
 		// Broadcast discovery update via WebSocket
@@ -1883,7 +1971,7 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 						},
 						Timestamp: time.Now().Format(time.RFC3339),
 					})
-					log.Info().Msg("Broadcasted discovery update after adding node")
+					log.Info().Ctx(r.Context()).Msg("Broadcasted discovery update after adding node")
 				}
 			}()
 		}
@@ -1895,7 +1983,7 @@ func (h *ConfigHandlers) HandleUpdateNode(w http.ResponseWriter, r *http.Request
 
 // HandleDeleteNode deletes a node
 func (h *ConfigHandlers) HandleDeleteNode(w http.ResponseWriter, r *http.Request) {
-	log.Info().Msg("HandleDeleteNode called")
+	log.Info().Ctx(r.Context()).Msg("HandleDeleteNode called")
 
 	// Prevent node modifications in mock mode
 	if mock.IsMockEnabled() {
@@ -1923,7 +2011,7 @@ func (h *ConfigHandlers) HandleDeleteNode(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("nodeID", nodeID).
 		Str("nodeType", nodeType).
 		Int("index", index).
@@ -1934,21 +2022,23 @@ func (h *ConfigHandlers) HandleDeleteNode(w http.ResponseWriter, r *http.Request
 
 	var deletedNodeHost string
 
+	h.nodesMu.Lock()
+
 	// Delete the node
 	if nodeType == "pve" && index < len(h.config.PVEInstances) {
 		deletedNodeHost = h.config.PVEInstances[index].Host
-		log.Info().Str("nodeID", nodeID).Int("index", index).Msg("Deleting PVE node")
+		log.Info().Ctx(r.Context()).Str("nodeID", nodeID).Int("index", index).Msg("Deleting PVE node")
 		h.config.PVEInstances = append(h.config.PVEInstances[:index], h.config.PVEInstances[index+1:]...)
 	} else if nodeType == "pbs" && index < len(h.config.PBSInstances) {
 		deletedNodeHost = h.config.PBSInstances[index].Host
-		log.Info().Str("nodeID", nodeID).Int("index", index).Msg("Deleting PBS node")
+		log.Info().Ctx(r.Context()).Str("nodeID", nodeID).Int("index", index).Msg("Deleting PBS node")
 		h.config.PBSInstances = append(h.config.PBSInstances[:index], h.config.PBSInstances[index+1:]...)
 	} else if nodeType == "pmg" && index < len(h.config.PMGInstances) {
 		deletedNodeHost = h.config.PMGInstances[index].Host
-		log.Info().Str("nodeID", nodeID).Int("index", index).Msg("Deleting PMG node")
+		log.Info().Ctx(r.Context()).Str("nodeID", nodeID).Int("index", index).Msg("Deleting PMG node")
 		h.config.PMGInstances = append(h.config.PMGInstances[:index], h.config.PMGInstances[index+1:]...)
 	} else {
-		log.Warn().
+		log.Warn().Ctx(r.Context()).
 			Str("nodeID", nodeID).
 			Str("nodeType", nodeType).
 			Int("index", index).
@@ -1956,13 +2046,16 @@ func (h *ConfigHandlers) HandleDeleteNode(w http.ResponseWriter, r *http.Request
 			Int("pbsCount", len(h.config.PBSInstances)).
 			Int("pmgCount", len(h.config.PMGInstances)).
 			Msg("Node not found for deletion")
+		h.nodesMu.Unlock()
 		http.Error(w, "Node not found", http.StatusNotFound)
 		return
 	}
 
 	// Save configuration to disk using our persistence instance
-	if err := h.persistence.SaveNodesConfigAllowEmpty(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances); err != nil {
-		log.Error().Err(err).Msg("Failed to save nodes configuration")
+	err := h.persistence.SaveNodesConfigAllowEmpty(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances)
+	h.nodesMu.Unlock()
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save nodes configuration")
 		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
 		return
 	}
@@ -1976,7 +2069,7 @@ func (h *ConfigHandlers) HandleDeleteNode(w http.ResponseWriter, r *http.Request
 	// Reload monitor with new configuration
 	if h.reloadFunc != nil {
 		if err := h.reloadFunc(); err != nil {
-			log.Error().Err(err).Msg("Failed to reload monitor")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload monitor")
 			http.Error(w, "Configuration saved but failed to apply changes", http.StatusInternalServerError)
 			return
 		}
@@ -1992,7 +2085,7 @@ func (h *ConfigHandlers) HandleDeleteNode(w http.ResponseWriter, r *http.Request
 			},
 			Timestamp: time.Now().Format(time.RFC3339),
 		})
-		log.Info().Msg("Broadcasted node deletion event")
+		log.Info().Ctx(r.Context()).Msg("Broadcasted node deletion event")
 
 		// Trigger a full discovery scan in the background to update the discovery cache
 		// This ensures the next time discovery modal is opened, it shows fresh results
@@ -2003,7 +2096,7 @@ func (h *ConfigHandlers) HandleDeleteNode(w http.ResponseWriter, r *http.Request
 			// Trigger full discovery refresh
 			if h.monitor != nil && h.monitor.GetDiscoveryService() != nil {
 				h.monitor.GetDiscoveryService().ForceRefresh()
-				log.Info().Msg("Triggered background discovery refresh after node deletion")
+				log.Info().Ctx(r.Context()).Msg("Triggered background discovery refresh after node deletion")
 			}
 		}()
 	}
@@ -2516,7 +2609,7 @@ func (h *ConfigHandlers) HandleGetSystemSettings(w http.ResponseWriter, r *http.
 	// Load settings from persistence to get all fields including theme
 	persistedSettings, err := h.persistence.LoadSystemSettings()
 	if err != nil {
-		log.Warn().Err(err).Msg("Failed to load persisted system settings")
+		log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to load persisted system settings")
 		persistedSettings = config.DefaultSystemSettings()
 	}
 	if persistedSettings == nil {
@@ -2535,6 +2628,12 @@ func (h *ConfigHandlers) HandleGetSystemSettings(w http.ResponseWriter, r *http.
 	settings.AutoUpdateEnabled = h.config.AutoUpdateEnabled
 	settings.AutoUpdateCheckInterval = int(h.config.AutoUpdateCheckInterval.Hours())
 	settings.AutoUpdateTime = h.config.AutoUpdateTime
+	settings.AutoUpdateWindowEnd = h.config.AutoUpdateWindowEnd
+	autoUpdateRollback := h.config.AutoUpdateRollback
+	settings.AutoUpdateRollback = &autoUpdateRollback
+	autoUpdateRolloutHours := h.config.AutoUpdateRolloutHours
+	settings.AutoUpdateRolloutHours = &autoUpdateRolloutHours
+	settings.UpdateSource = h.config.UpdateSource
 	settings.LogLevel = h.config.LogLevel
 	settings.DiscoveryEnabled = h.config.DiscoveryEnabled
 	settings.DiscoverySubnet = h.config.DiscoverySubnet
@@ -2679,11 +2778,11 @@ func (h *ConfigHandlers) HandleUpdateSystemSettingsOLD(w http.ResponseWriter, r
 
 	// Trigger a monitor reload if intervals changed
 	if needsReload && h.reloadFunc != nil {
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Int("pbsInterval", settings.PBSPollingInterval).
 			Msg("Triggering monitor reload for new PBS polling interval")
 		if err := h.reloadFunc(); err != nil {
-			log.Error().Err(err).Msg("Failed to reload monitor with new polling intervals")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload monitor with new polling intervals")
 			// Don't fail the request, the setting was saved
 		}
 	}
@@ -2712,18 +2811,30 @@ func (h *ConfigHandlers) HandleUpdateSystemSettingsOLD(w http.ResponseWriter, r
 	if settings.AutoUpdateTime != "" {
 		h.config.AutoUpdateTime = settings.AutoUpdateTime
 	}
+	if settings.AutoUpdateWindowEnd != "" {
+		h.config.AutoUpdateWindowEnd = settings.AutoUpdateWindowEnd
+	}
+	if settings.AutoUpdateRollback != nil {
+		h.config.AutoUpdateRollback = *settings.AutoUpdateRollback
+	}
+	if settings.AutoUpdateRolloutHours != nil {
+		h.config.AutoUpdateRolloutHours = *settings.AutoUpdateRolloutHours
+	}
+	if settings.UpdateSource != "" {
+		h.config.UpdateSource = settings.UpdateSource
+	}
 	settings.DiscoveryConfig = config.CloneDiscoveryConfig(h.config.Discovery)
 
 	// Save settings to persistence
 	if err := h.persistence.SaveSystemSettings(settings); err != nil {
-		log.Error().Err(err).Msg("Failed to persist system settings")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to persist system settings")
 		// Continue anyway - settings are applied in memory
 	} else if h.reloadSystemSettingsFunc != nil {
 		// Reload cached system settings after successful save
 		h.reloadSystemSettingsFunc()
 	}
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Int("pbsPollingInterval", settings.PBSPollingInterval).
 		Int("backendPort", settings.BackendPort).
 		Int("frontendPort", settings.FrontendPort).
@@ -2732,10 +2843,10 @@ func (h *ConfigHandlers) HandleUpdateSystemSettingsOLD(w http.ResponseWriter, r
 	// Trigger monitor reload to apply new settings
 	if h.reloadFunc != nil {
 		if err := h.reloadFunc(); err != nil {
-			log.Error().Err(err).Msg("Failed to reload monitor after system settings update")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload monitor after system settings update")
 			// Continue anyway - settings are saved
 		} else {
-			log.Info().
+			log.Info().Ctx(r.Context()).
 				Int("pbsPollingInterval", settings.PBSPollingInterval).
 				Msg("Monitor reloaded with new PBS polling interval")
 		}
@@ -2768,7 +2879,7 @@ type ImportConfigRequest struct {
 func (h *ConfigHandlers) HandleExportConfig(w http.ResponseWriter, r *http.Request) {
 	var req ExportConfigRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Error().Err(err).Msg("Failed to decode export request")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to decode export request")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
@@ -2787,12 +2898,12 @@ func (h *ConfigHandlers) HandleExportConfig(w http.ResponseWriter, r *http.Reque
 	// Export configuration
 	exportedData, err := h.persistence.ExportConfig(req.Passphrase)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to export configuration")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to export configuration")
 		http.Error(w, "Failed to export configuration", http.StatusInternalServerError)
 		return
 	}
 
-	log.Info().Msg("Configuration exported successfully")
+	log.Info().Ctx(r.Context()).Msg("Configuration exported successfully")
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -2805,7 +2916,7 @@ func (h *ConfigHandlers) HandleExportConfig(w http.ResponseWriter, r *http.Reque
 func (h *ConfigHandlers) HandleImportConfig(w http.ResponseWriter, r *http.Request) {
 	var req ImportConfigRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Error().Err(err).Msg("Failed to decode import request")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to decode import request")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
@@ -2822,7 +2933,7 @@ func (h *ConfigHandlers) HandleImportConfig(w http.ResponseWriter, r *http.Reque
 
 	// Import configuration
 	if err := h.persistence.ImportConfig(req.Data, req.Passphrase); err != nil {
-		log.Error().Err(err).Msg("Failed to import configuration")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to import configuration")
 		http.Error(w, "Failed to import configuration: "+err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -2830,7 +2941,7 @@ func (h *ConfigHandlers) HandleImportConfig(w http.ResponseWriter, r *http.Reque
 	// Reload configuration from disk
 	newConfig, err := config.Load()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to reload configuration after import")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload configuration after import")
 		http.Error(w, "Configuration imported but failed to reload", http.StatusInternalServerError)
 		return
 	}
@@ -2841,7 +2952,7 @@ func (h *ConfigHandlers) HandleImportConfig(w http.ResponseWriter, r *http.Reque
 	// Reload monitor with new configuration
 	if h.reloadFunc != nil {
 		if err := h.reloadFunc(); err != nil {
-			log.Error().Err(err).Msg("Failed to reload monitor after import")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload monitor after import")
 			http.Error(w, "Configuration imported but failed to apply changes", http.StatusInternalServerError)
 			return
 		}
@@ -2853,9 +2964,9 @@ func (h *ConfigHandlers) HandleImportConfig(w http.ResponseWriter, r *http.Reque
 		// Reload alert configuration
 		if alertConfig, err := h.persistence.LoadAlertConfig(); err == nil {
 			h.monitor.GetAlertManager().UpdateConfig(*alertConfig)
-			log.Info().Msg("Reloaded alert configuration after import")
+			log.Info().Ctx(r.Context()).Msg("Reloaded alert configuration after import")
 		} else {
-			log.Warn().Err(err).Msg("Failed to reload alert configuration after import")
+			log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to reload alert configuration after import")
 		}
 
 		// Reload webhook configuration
@@ -2865,37 +2976,37 @@ func (h *ConfigHandlers) HandleImportConfig(w http.ResponseWriter, r *http.Reque
 			// Get current webhooks to clear them
 			for _, webhook := range notificationMgr.GetWebhooks() {
 				if err := notificationMgr.DeleteWebhook(webhook.ID); err != nil {
-					log.Warn().Err(err).Str("webhook", webhook.ID).Msg("Failed to delete existing webhook during reload")
+					log.Warn().Ctx(r.Context()).Err(err).Str("webhook", webhook.ID).Msg("Failed to delete existing webhook during reload")
 				}
 			}
 			// Add imported webhooks
 			for _, webhook := range webhooks {
 				notificationMgr.AddWebhook(webhook)
 			}
-			log.Info().Int("count", len(webhooks)).Msg("Reloaded webhook configuration after import")
+			log.Info().Ctx(r.Context()).Int("count", len(webhooks)).Msg("Reloaded webhook configuration after import")
 		} else {
-			log.Warn().Err(err).Msg("Failed to reload webhook configuration after import")
+			log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to reload webhook configuration after import")
 		}
 
 		// Reload email configuration
 		if emailConfig, err := h.persistence.LoadEmailConfig(); err == nil {
 			h.monitor.GetNotificationManager().SetEmailConfig(*emailConfig)
-			log.Info().Msg("Reloaded email configuration after import")
+			log.Info().Ctx(r.Context()).Msg("Reloaded email configuration after import")
 		} else {
-			log.Warn().Err(err).Msg("Failed to reload email configuration after import")
+			log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to reload email configuration after import")
 		}
 	}
 
 	// Reload guest metadata from disk
 	if h.guestMetadataHandler != nil {
 		if err := h.guestMetadataHandler.Reload(); err != nil {
-			log.Warn().Err(err).Msg("Failed to reload guest metadata after import")
+			log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to reload guest metadata after import")
 		} else {
-			log.Info().Msg("Reloaded guest metadata after import")
+			log.Info().Ctx(r.Context()).Msg("Reloaded guest metadata after import")
 		}
 	}
 
-	log.Info().Msg("Configuration imported successfully")
+	log.Info().Ctx(r.Context()).Msg("Configuration imported successfully")
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -2987,11 +3098,11 @@ func (h *ConfigHandlers) HandleDiscoverServers(w http.ResponseWriter, r *http.Re
 			subnet = "auto"
 		}
 
-		log.Info().Str("subnet", subnet).Msg("Starting manual discovery scan")
+		log.Info().Ctx(r.Context()).Str("subnet", subnet).Msg("Starting manual discovery scan")
 
 		scanner, buildErr := discoveryinternal.BuildScanner(h.config.Discovery)
 		if buildErr != nil {
-			log.Warn().Err(buildErr).Msg("Falling back to default scanner for manual discovery")
+			log.Warn().Ctx(r.Context()).Err(buildErr).Msg("Falling back to default scanner for manual discovery")
 			scanner = pkgdiscovery.NewScanner()
 		}
 		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
@@ -2999,13 +3110,13 @@ func (h *ConfigHandlers) HandleDiscoverServers(w http.ResponseWriter, r *http.Re
 
 		result, err := scanner.DiscoverServers(ctx, subnet)
 		if err != nil {
-			log.Error().Err(err).Msg("Discovery failed")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Discovery failed")
 			http.Error(w, fmt.Sprintf("Discovery failed: %v", err), http.StatusInternalServerError)
 			return
 		}
 
 		if result.Environment != nil {
-			log.Info().
+			log.Info().Ctx(r.Context()).
 				Str("environment", result.Environment.Type).
 				Float64("confidence", result.Environment.Confidence).
 				Int("phases", len(result.Environment.Phases)).
@@ -3056,7 +3167,7 @@ func (h *ConfigHandlers) HandleSetupScript(w http.ResponseWriter, r *http.Reques
 		} else {
 			serverHost = "https://YOUR_PBS_HOST:8007"
 		}
-		log.Warn().
+		log.Warn().Ctx(r.Context()).
 			Str("type", serverType).
 			Msg("No host parameter provided, using placeholder. Auto-registration will fail.")
 	}
@@ -3070,7 +3181,7 @@ func (h *ConfigHandlers) HandleSetupScript(w http.ResponseWriter, r *http.Reques
 		pulseURL = fmt.Sprintf("%s://%s", scheme, r.Host)
 	}
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("type", serverType).
 		Str("host", serverHost).
 		Bool("has_auth", h.config.AuthUser != "" || h.config.AuthPass != "" || h.config.HasAPITokens()).
@@ -3129,7 +3240,7 @@ func (h *ConfigHandlers) HandleSetupScript(w http.ResponseWriter, r *http.Reques
 	tokenName := fmt.Sprintf("pulse-%s-%d", pulseIP, timestamp)
 
 	// Log the token name for debugging
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("pulseURL", pulseURL).
 		Str("pulseIP", pulseIP).
 		Str("tokenName", tokenName).
@@ -4778,7 +4889,7 @@ func (h *ConfigHandlers) HandleSetupScriptURL(w http.ResponseWriter, r *http.Req
 	}
 	h.codeMutex.Unlock()
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("token_hash", tokenHash[:8]+"...").
 		Time("expiry", expiry).
 		Str("type", req.Type).
@@ -4843,7 +4954,7 @@ func (h *ConfigHandlers) HandleGetMockMode(w http.ResponseWriter, r *http.Reques
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(status); err != nil {
-		log.Error().Err(err).Msg("Failed to encode mock mode status")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode mock mode status")
 	}
 }
 
@@ -4863,7 +4974,7 @@ type mockModeRequest struct {
 func (h *ConfigHandlers) HandleUpdateMockMode(w http.ResponseWriter, r *http.Request) {
 	var req mockModeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Error().Err(err).Msg("Failed to decode mock mode request")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to decode mock mode request")
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
@@ -4924,7 +5035,7 @@ func (h *ConfigHandlers) HandleUpdateMockMode(w http.ResponseWriter, r *http.Req
 		Config:  mock.GetConfig(),
 	}
 	if err := json.NewEncoder(w).Encode(status); err != nil {
-		log.Error().Err(err).Msg("Failed to encode mock mode response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode mock mode response")
 	}
 }
 
@@ -4954,13 +5065,13 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 	var req AutoRegisterRequest
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to read request body")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to read request body")
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
 
 	if err := json.Unmarshal(body, &req); err != nil {
-		log.Error().Err(err).Str("body", string(body)).Msg("Failed to parse auto-register request")
+		log.Error().Ctx(r.Context()).Err(err).Str("body", string(body)).Msg("Failed to parse auto-register request")
 		http.Error(w, "Invalid request format", http.StatusBadRequest)
 		return
 	}
@@ -4974,7 +5085,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 		authCode = req.AuthToken
 	}
 
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("authToken", req.AuthToken).
 		Str("authCode", authCode).
 		Bool("hasConfigToken", h.config.HasAPITokens()).
@@ -4987,7 +5098,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 			if _, ok := h.config.ValidateAPIToken(authCode); ok {
 				authenticated = true
 				matchedAPIToken = true
-				log.Info().
+				log.Info().Ctx(r.Context()).
 					Str("type", req.Type).
 					Str("host", req.Host).
 					Msg("Auto-register authenticated via direct API token")
@@ -4997,13 +5108,13 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 		if !matchedAPIToken {
 			// Not the API token, check if it's a temporary setup code
 			codeHash := internalauth.HashAPIToken(authCode)
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Str("authCode", authCode).
 				Str("codeHash", codeHash[:8]+"...").
 				Msg("Checking auth token as setup code")
 			h.codeMutex.Lock()
 			setupCode, exists := h.setupCodes[codeHash]
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Bool("exists", exists).
 				Int("totalCodes", len(h.setupCodes)).
 				Msg("Setup code lookup result")
@@ -5021,23 +5132,23 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 					}
 					h.recentSetupTokens[codeHash] = graceExpiry
 					authenticated = true
-					log.Info().
+					log.Info().Ctx(r.Context()).
 						Str("type", req.Type).
 						Str("host", req.Host).
 						Bool("via_authToken", req.AuthToken != "").
 						Msg("Auto-register authenticated via setup code/token")
 				} else {
-					log.Warn().
+					log.Warn().Ctx(r.Context()).
 						Str("expected_type", setupCode.NodeType).
 						Str("got_type", req.Type).
 						Msg("Setup code validation failed - type mismatch")
 				}
 			} else if exists && setupCode.Used {
-				log.Warn().Msg("Setup code already used")
+				log.Warn().Ctx(r.Context()).Msg("Setup code already used")
 			} else if exists {
-				log.Warn().Msg("Setup code expired")
+				log.Warn().Ctx(r.Context()).Msg("Setup code expired")
 			} else {
-				log.Warn().Msg("Invalid setup code/token - not in setup codes map")
+				log.Warn().Ctx(r.Context()).Msg("Invalid setup code/token - not in setup codes map")
 			}
 			h.codeMutex.Unlock()
 		}
@@ -5048,7 +5159,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 		apiToken := r.Header.Get("X-API-Token")
 		if _, ok := h.config.ValidateAPIToken(apiToken); ok {
 			authenticated = true
-			log.Info().Msg("Auto-register authenticated via API token")
+			log.Info().Ctx(r.Context()).Msg("Auto-register authenticated via API token")
 		}
 	}
 
@@ -5056,12 +5167,12 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 	// BUT: Always allow if a valid setup code/auth token was provided (even if expired/used)
 	// This ensures the error message is accurate
 	if !authenticated && h.config.HasAPITokens() && authCode == "" {
-		log.Warn().Str("ip", r.RemoteAddr).Msg("Unauthorized auto-register attempt - no authentication provided")
+		log.Warn().Ctx(r.Context()).Str("ip", r.RemoteAddr).Msg("Unauthorized auto-register attempt - no authentication provided")
 		http.Error(w, "Pulse requires authentication", http.StatusUnauthorized)
 		return
 	} else if !authenticated && h.config.HasAPITokens() {
 		// Had a code but it didn't validate
-		log.Warn().Str("ip", r.RemoteAddr).Msg("Unauthorized auto-register attempt - invalid or expired setup code")
+		log.Warn().Ctx(r.Context()).Str("ip", r.RemoteAddr).Msg("Unauthorized auto-register attempt - invalid or expired setup code")
 		http.Error(w, "Invalid or expired setup code", http.StatusUnauthorized)
 		return
 	}
@@ -5071,11 +5182,11 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
 		clientIP = forwarded
 	}
-	log.Info().Str("clientIP", clientIP).Msg("Auto-register request from")
+	log.Info().Ctx(r.Context()).Str("clientIP", clientIP).Msg("Auto-register request from")
 
 	// Registration token validation removed - feature deprecated
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("type", req.Type).
 		Str("host", req.Host).
 		Str("tokenId", req.TokenID).
@@ -5087,7 +5198,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 	if req.RequestToken {
 		// New secure mode - generate token on Pulse side
 		if req.Type == "" || req.Host == "" || req.Username == "" || req.Password == "" {
-			log.Error().
+			log.Error().Ctx(r.Context()).
 				Str("type", req.Type).
 				Str("host", req.Host).
 				Bool("hasUsername", req.Username != "").
@@ -5103,7 +5214,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 
 	// Legacy mode - validate old required fields
 	if req.Type == "" || req.Host == "" || req.TokenID == "" || req.TokenValue == "" {
-		log.Error().
+		log.Error().Ctx(r.Context()).
 			Str("type", req.Type).
 			Str("host", req.Host).
 			Str("tokenId", req.TokenID).
@@ -5163,6 +5274,8 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 		MonitorGarbageJobs: false,
 	}
 
+	h.nodesMu.Lock()
+
 	// Check if a node with this host already exists
 	existingIndex := -1
 	if req.Type == "pve" {
@@ -5206,7 +5319,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 					instance.IsCluster = true
 					instance.ClusterName = clusterName
 					instance.ClusterEndpoints = clusterEndpoints
-					log.Info().
+					log.Info().Ctx(r.Context()).
 						Str("cluster", clusterName).
 						Int("endpoints", len(clusterEndpoints)).
 						Msg("Detected Proxmox cluster during auto-registration update")
@@ -5222,7 +5335,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 			instance.TokenValue = nodeConfig.TokenValue
 			// Keep other settings as they were
 		}
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Str("host", req.Host).
 			Str("type", req.Type).
 			Str("tokenName", nodeConfig.TokenName).
@@ -5258,7 +5371,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 			h.config.PVEInstances = append(h.config.PVEInstances, newInstance)
 
 			if isCluster {
-				log.Info().
+				log.Info().Ctx(r.Context()).
 					Str("cluster", clusterName).
 					Int("endpoints", len(clusterEndpoints)).
 					Msg("Added Proxmox cluster via auto-registration")
@@ -5279,13 +5392,13 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 			}
 			h.config.PBSInstances = append(h.config.PBSInstances, newInstance)
 		}
-		log.Info().Str("host", req.Host).Str("type", req.Type).Msg("Added new node via auto-registration")
+		log.Info().Ctx(r.Context()).Str("host", req.Host).Str("type", req.Type).Msg("Added new node via auto-registration")
 	}
 
 	// Log what we're about to save
 	if req.Type == "pve" && len(h.config.PVEInstances) > 0 {
 		lastNode := h.config.PVEInstances[len(h.config.PVEInstances)-1]
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Str("name", lastNode.Name).
 			Str("host", lastNode.Host).
 			Str("tokenName", lastNode.TokenName).
@@ -5294,13 +5407,15 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Save configuration
-	if err := h.persistence.SaveNodesConfig(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances); err != nil {
-		log.Error().Err(err).Msg("Failed to save auto-registered node")
+	err = h.persistence.SaveNodesConfig(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances)
+	h.nodesMu.Unlock()
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save auto-registered node")
 		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
 		return
 	}
 
-	log.Info().Msg("Configuration saved successfully")
+	log.Info().Ctx(r.Context()).Msg("Configuration saved successfully")
 
 	actualName := h.findInstanceNameByHost(req.Type, host)
 	if actualName == "" {
@@ -5316,20 +5431,20 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 
 	// Reload monitor to pick up new configuration
 	if h.reloadFunc != nil {
-		log.Info().Msg("Reloading monitor after auto-registration")
+		log.Info().Ctx(r.Context()).Msg("Reloading monitor after auto-registration")
 		go func() {
 			// Run reload in background to avoid blocking the response
 			if err := h.reloadFunc(); err != nil {
-				log.Error().Err(err).Msg("Failed to reload monitor after auto-registration")
+				log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload monitor after auto-registration")
 			} else {
-				log.Info().Msg("Monitor reloaded successfully after auto-registration")
+				log.Info().Ctx(r.Context()).Msg("Monitor reloaded successfully after auto-registration")
 			}
 		}()
 	}
 
 	// Trigger a discovery refresh to remove the node from discovered list
 	if h.monitor != nil && h.monitor.GetDiscoveryService() != nil {
-		log.Info().Msg("Triggering discovery refresh after auto-registration")
+		log.Info().Ctx(r.Context()).Msg("Triggering discovery refresh after auto-registration")
 		h.monitor.GetDiscoveryService().ForceRefresh()
 	}
 
@@ -5369,18 +5484,18 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 					},
 					Timestamp: time.Now().Format(time.RFC3339),
 				})
-				log.Info().Msg("Broadcasted discovery update after auto-registration")
+				log.Info().Ctx(r.Context()).Msg("Broadcasted discovery update after auto-registration")
 			}
 		}
 
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Str("host", req.Host).
 			Str("name", req.ServerName).
 			Str("type", "node_auto_registered").
 			Msg("Broadcasted auto-registration success via WebSocket")
 	} else {
 		fmt.Println("[AUTO-REGISTER] ERROR: WebSocket hub is nil!")
-		log.Warn().Msg("WebSocket hub is nil, cannot broadcast auto-registration")
+		log.Warn().Ctx(r.Context()).Msg("WebSocket hub is nil, cannot broadcast auto-registration")
 	}
 
 	// Send success response
@@ -5394,7 +5509,7 @@ func (h *ConfigHandlers) HandleAutoRegister(w http.ResponseWriter, r *http.Reque
 
 // handleSecureAutoRegister handles the new secure registration flow where Pulse generates the token
 func (h *ConfigHandlers) handleSecureAutoRegister(w http.ResponseWriter, r *http.Request, req *AutoRegisterRequest, clientIP string) {
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("type", req.Type).
 		Str("host", req.Host).
 		Str("username", req.Username).
@@ -5411,7 +5526,7 @@ func (h *ConfigHandlers) handleSecureAutoRegister(w http.ResponseWriter, r *http
 	// Generate a secure random token value
 	tokenBytes := make([]byte, 16)
 	if _, err := rand.Read(tokenBytes); err != nil {
-		log.Error().Err(err).Msg("Failed to generate secure token")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to generate secure token")
 		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
 		return
 	}
@@ -5468,7 +5583,7 @@ func (h *ConfigHandlers) handleSecureAutoRegister(w http.ResponseWriter, r *http
 	}
 
 	if createErr != nil {
-		log.Error().Err(createErr).Msg("Failed to create token on remote server")
+		log.Error().Ctx(r.Context()).Err(createErr).Msg("Failed to create token on remote server")
 		http.Error(w, "Failed to create token on remote server", http.StatusInternalServerError)
 		return
 	}
@@ -5486,6 +5601,7 @@ func (h *ConfigHandlers) handleSecureAutoRegister(w http.ResponseWriter, r *http
 	}
 
 	// Add the node to configuration
+	h.nodesMu.Lock()
 	if req.Type == "pve" {
 		pveNode := config.PVEInstance{
 			Name:              serverName,
@@ -5516,8 +5632,10 @@ func (h *ConfigHandlers) handleSecureAutoRegister(w http.ResponseWriter, r *http
 	}
 
 	// Save configuration
-	if err := h.persistence.SaveNodesConfig(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances); err != nil {
-		log.Error().Err(err).Msg("Failed to save auto-registered node")
+	err := h.persistence.SaveNodesConfig(h.config.PVEInstances, h.config.PBSInstances, h.config.PMGInstances)
+	h.nodesMu.Unlock()
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save auto-registered node")
 		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
 		return
 	}
@@ -5532,7 +5650,7 @@ func (h *ConfigHandlers) handleSecureAutoRegister(w http.ResponseWriter, r *http
 	if h.reloadFunc != nil {
 		go func() {
 			if err := h.reloadFunc(); err != nil {
-				log.Error().Err(err).Msg("Failed to reload monitor after auto-registration")
+				log.Error().Ctx(r.Context()).Err(err).Msg("Failed to reload monitor after auto-registration")
 			}
 		}()
 	}
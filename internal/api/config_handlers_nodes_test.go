@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	pkgdiscovery "github.com/RouXx67/PulseUp/pkg/discovery"
+)
+
+func TestAddNodeReportsStatusAndRejectsDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", dir)
+	cfg := &config.Config{DataPath: dir}
+	h := &ConfigHandlers{config: cfg, persistence: config.NewConfigPersistence(dir)}
+
+	req := NodeConfigRequest{
+		Type:       "pbs",
+		Name:       "pbs1",
+		Host:       "https://10.0.0.8:8007",
+		TokenName:  "pulse@pbs!monitor",
+		TokenValue: "secret",
+	}
+
+	invalid := req
+	invalid.Host = ""
+	var apiErr *APIError
+	if err := h.addNode(invalid); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a bad request error, got %v", err)
+	}
+
+	if err := h.addNode(req); err != nil {
+		t.Fatalf("addNode: %v", err)
+	}
+	if len(cfg.PBSInstances) != 1 || cfg.PBSInstances[0].Host != "https://10.0.0.8:8007" {
+		t.Fatalf("unexpected PBS instances %+v", cfg.PBSInstances)
+	}
+
+	if err := h.addNode(req); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("expected a conflict for a duplicate name, got %v", err)
+	}
+
+	onboarding := &DiscoveryOnboardingHandlers{config: cfg, configHandlers: h}
+	server := pkgdiscovery.DiscoveredServer{IP: "10.0.0.9", Port: 8007, Type: "pbs", Hostname: "pbs1.lan"}
+	if name := onboarding.onboardingNodeName(server); name != "pbs1-2" {
+		t.Fatalf("expected a free node name, got %q", name)
+	}
+	if !onboarding.isConfigured(pkgdiscovery.DiscoveredServer{IP: "10.0.0.8", Port: 8007, Type: "pbs"}) {
+		t.Fatal("expected the added node to be reported as configured")
+	}
+}
@@ -33,7 +33,7 @@ func DemoModeMiddleware(cfg *config.Config, next http.Handler) http.Handler {
 		}
 
 		// Block all modification requests (POST, PUT, DELETE, PATCH)
-		log.Warn().
+		log.Warn().Ctx(r.Context()).
 			Str("method", r.Method).
 			Str("path", r.URL.Path).
 			Str("remote", r.RemoteAddr).
@@ -425,7 +425,7 @@ func (r *Router) computeDiagnostics(ctx context.Context) DiagnosticsInfo {
 				if clusterStatus, err := client.GetClusterStatus(ctx); err == nil {
 					nodeDiag.ClusterInfo = &ClusterInfo{Nodes: len(clusterStatus)}
 				} else {
-					log.Debug().Str("node", node.Name).Msg("Cluster status not available (likely standalone node)")
+					log.Debug().Ctx(ctx).Str("node", node.Name).Msg("Cluster status not available (likely standalone node)")
 					nodeDiag.ClusterInfo = &ClusterInfo{Nodes: 1}
 				}
 
@@ -1115,7 +1115,7 @@ func (r *Router) checkVMDiskMonitoring(ctx context.Context, client *proxmox.Clie
 		vms, err := client.GetVMs(vmCtx, node.Node)
 		cancel()
 		if err != nil {
-			log.Debug().Err(err).Str("node", node.Node).Msg("Failed to get VMs from node")
+			log.Debug().Ctx(ctx).Err(err).Str("node", node.Node).Msg("Failed to get VMs from node")
 			continue
 		}
 		nodeVMMap[node.Node] = vms
@@ -0,0 +1,355 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/discovery"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	pkgdiscovery "github.com/RouXx67/PulseUp/pkg/discovery"
+	"github.com/rs/zerolog/log"
+)
+
+// discoveryOnboardingUser is recorded in the audit trail for nodes added without an approval
+const discoveryOnboardingUser = "discovery"
+
+// DiscoveryOnboardingHandlers manage credential profiles, sweeps and the approval queue for
+// servers found by discovery
+type DiscoveryOnboardingHandlers struct {
+	config         *config.Config
+	monitor        *monitoring.Monitor
+	persistence    *config.ConfigPersistence
+	configHandlers *ConfigHandlers
+}
+
+// DiscoveryOnboardingStatus is the approval queue together with servers reported missing
+type DiscoveryOnboardingStatus struct {
+	Candidates []discovery.Candidate     `json:"candidates"`
+	Missing    []discovery.MissingServer `json:"missing"`
+}
+
+// NewDiscoveryOnboardingHandlers creates discovery onboarding handlers and connects the
+// monitor's onboarder to the node configuration
+func NewDiscoveryOnboardingHandlers(cfg *config.Config, m *monitoring.Monitor, persistence *config.ConfigPersistence, configHandlers *ConfigHandlers) *DiscoveryOnboardingHandlers {
+	h := &DiscoveryOnboardingHandlers{
+		config:         cfg,
+		persistence:    persistence,
+		configHandlers: configHandlers,
+	}
+	h.SetMonitor(m)
+	return h
+}
+
+// SetMonitor updates the monitor reference and installs the onboarding callbacks on it.
+func (h *DiscoveryOnboardingHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+	if m == nil {
+		return
+	}
+	if onboarder := m.GetDiscoveryOnboarder(); onboarder != nil {
+		onboarder.SetHandlers(h.onboardServer, h.isConfigured)
+	}
+}
+
+// HandleOnboarding routes /api/discovery/onboarding requests
+func (h *DiscoveryOnboardingHandlers) HandleOnboarding(w http.ResponseWriter, r *http.Request) {
+	if h.monitor == nil || h.monitor.GetDiscoveryOnboarder() == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/discovery/onboarding")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		h.GetConfig(w, r)
+	case path == "" && r.Method == http.MethodPut:
+		h.UpdateConfig(w, r)
+	case path == "/candidates" && r.Method == http.MethodGet:
+		h.GetStatus(w, r)
+	case strings.HasPrefix(path, "/candidates/") && r.Method == http.MethodPost:
+		h.HandleCandidateAction(w, r, strings.TrimPrefix(path, "/candidates/"))
+	case strings.HasPrefix(path, "/sweeps/") && strings.HasSuffix(path, "/run") && r.Method == http.MethodPost:
+		h.RunSweep(w, r, strings.TrimSuffix(strings.TrimPrefix(path, "/sweeps/"), "/run"))
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetConfig returns the onboarding configuration with stored token values blanked
+func (h *DiscoveryOnboardingHandlers) GetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg := h.monitor.GetDiscoveryOnboarder().Config()
+	if err := utils.WriteJSONResponse(w, redactDiscoveryOnboardingConfig(cfg)); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write discovery onboarding configuration response")
+	}
+}
+
+// UpdateConfig validates, saves and applies the onboarding configuration
+func (h *DiscoveryOnboardingHandlers) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024)
+	var cfg config.DiscoveryOnboardingConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	cfg = config.NormalizeDiscoveryOnboardingConfig(cfg)
+
+	// If a token value is empty, preserve the existing token of the profile
+	onboarder := h.monitor.GetDiscoveryOnboarder()
+	existing := onboarder.Config()
+	for i := range cfg.Profiles {
+		if cfg.Profiles[i].TokenValue == "" {
+			if profile, ok := existing.Profile(cfg.Profiles[i].Type); ok && profile.TokenName == cfg.Profiles[i].TokenName {
+				cfg.Profiles[i].TokenValue = profile.TokenValue
+			}
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.persistence.SaveDiscoveryOnboardingConfig(cfg); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save discovery onboarding configuration")
+		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+		return
+	}
+	onboarder.SetConfig(cfg)
+
+	LogAuditEvent("discovery_onboarding_config", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true,
+		fmt.Sprintf("enabled=%t mode=%s profiles=%d sweeps=%d", cfg.Enabled, cfg.Mode, len(cfg.Profiles), len(cfg.Sweeps)))
+
+	if err := utils.WriteJSONResponse(w, redactDiscoveryOnboardingConfig(onboarder.Config())); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write discovery onboarding configuration response")
+	}
+}
+
+// GetStatus returns the onboarding candidates and the servers reported missing
+func (h *DiscoveryOnboardingHandlers) GetStatus(w http.ResponseWriter, r *http.Request) {
+	onboarder := h.monitor.GetDiscoveryOnboarder()
+	status := DiscoveryOnboardingStatus{
+		Candidates: onboarder.Candidates(),
+		Missing:    onboarder.Missing(),
+	}
+	if status.Missing == nil {
+		status.Missing = []discovery.MissingServer{}
+	}
+	if err := utils.WriteJSONResponse(w, status); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write discovery onboarding status response")
+	}
+}
+
+// HandleCandidateAction serves POST /api/discovery/onboarding/candidates/{id}/{approve|reject|forget}
+func (h *DiscoveryOnboardingHandlers) HandleCandidateAction(w http.ResponseWriter, r *http.Request, rest string) {
+	parts := strings.Split(rest, "/")
+	if len(parts) != 2 || parts[0] == "" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	id, action := parts[0], parts[1]
+	onboarder := h.monitor.GetDiscoveryOnboarder()
+	user := requestUsername(h.config, w, r)
+
+	var candidate discovery.Candidate
+	var err error
+	switch action {
+	case "approve":
+		candidate, err = onboarder.Approve(r.Context(), id, user)
+	case "reject":
+		candidate, err = onboarder.Reject(id)
+		LogAuditEvent("discovery_onboarding_reject", user, GetClientIP(r), r.URL.Path, err == nil, "candidate="+id)
+	case "forget":
+		if !onboarder.Forget(id) {
+			http.Error(w, "Candidate not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		status := http.StatusBadRequest
+		if candidate.ID == "" {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, candidate); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write discovery onboarding candidate response")
+	}
+}
+
+// RunSweep starts a configured sweep immediately
+func (h *DiscoveryOnboardingHandlers) RunSweep(w http.ResponseWriter, r *http.Request, name string) {
+	name, err := url.PathUnescape(name)
+	if err != nil || name == "" {
+		http.Error(w, "Invalid sweep name", http.StatusBadRequest)
+		return
+	}
+	// The sweep outlives the request
+	if err := h.monitor.GetDiscoveryOnboarder().RunSweep(context.Background(), name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	LogAuditEvent("discovery_sweep_run", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true, "sweep="+name)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// onboardServer validates a discovered server and adds it with the same connection test and
+// add-node code as the node endpoints, so onboarded nodes get exactly the same checks and defaults
+func (h *DiscoveryOnboardingHandlers) onboardServer(ctx context.Context, server pkgdiscovery.DiscoveredServer, profile config.DiscoveryCredentialProfile, approvedBy string) (string, error) {
+	user := approvedBy
+	if user == "" {
+		user = discoveryOnboardingUser
+	}
+	host := net.JoinHostPort(server.IP, fmt.Sprint(server.Port))
+	name := h.onboardingNodeName(server)
+
+	err := h.addDiscoveredNode(ctx, server, profile, name)
+	LogAuditEvent("discovery_node_onboarded", user, "", "/api/discovery/onboarding", err == nil,
+		fmt.Sprintf("type=%s host=%s node=%s approved=%t", server.Type, host, name, approvedBy != ""))
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func (h *DiscoveryOnboardingHandlers) addDiscoveredNode(ctx context.Context, server pkgdiscovery.DiscoveredServer, profile config.DiscoveryCredentialProfile, name string) error {
+	if h.configHandlers == nil {
+		return fmt.Errorf("node configuration is not available")
+	}
+
+	req := NodeConfigRequest{
+		Type:       server.Type,
+		Name:       name,
+		Host:       "https://" + net.JoinHostPort(server.IP, fmt.Sprint(server.Port)),
+		TokenName:  profile.TokenName,
+		TokenValue: profile.TokenValue,
+		VerifySSL:  profile.VerifySSL,
+	}
+	switch server.Type {
+	case "pve":
+		req.MonitorVMs = true
+		req.MonitorContainers = true
+		req.MonitorStorage = true
+		req.MonitorBackups = true
+	case "pbs":
+		req.MonitorDatastores = true
+		req.MonitorSyncJobs = true
+		req.MonitorVerifyJobs = true
+		req.MonitorPruneJobs = true
+		req.MonitorGarbageJobs = true
+	}
+
+	// Secret references stay in the stored node; the connection test needs the resolved token
+	testReq := req
+	resolved, err := config.DefaultCredentialResolver().ResolveReference(profile.TokenValue, "discovery."+profile.Type+".token_value")
+	if err != nil {
+		return fmt.Errorf("resolve %s token: %w", profile.Type, err)
+	}
+	testReq.TokenValue = resolved
+
+	if _, err := h.configHandlers.testNodeConnection(ctx, testReq); err != nil {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	if err := h.configHandlers.addNode(req); err != nil {
+		return fmt.Errorf("add node: %w", err)
+	}
+	return nil
+}
+
+// onboardingNodeName derives a unique node name from the server's hostname or address
+func (h *DiscoveryOnboardingHandlers) onboardingNodeName(server pkgdiscovery.DiscoveredServer) string {
+	base := strings.TrimSuffix(strings.ToLower(server.Hostname), ".")
+	if idx := strings.Index(base, "."); idx > 0 {
+		base = base[:idx]
+	}
+	if base == "" {
+		base = strings.NewReplacer(".", "-", ":", "-").Replace(server.IP)
+	}
+
+	name := base
+	for i := 2; h.nodeNameTaken(server.Type, name); i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	return name
+}
+
+func (h *DiscoveryOnboardingHandlers) nodeNameTaken(nodeType, name string) bool {
+	if h.configHandlers == nil {
+		return false
+	}
+	h.configHandlers.nodesMu.RLock()
+	defer h.configHandlers.nodesMu.RUnlock()
+	return h.configHandlers.nodeNameTakenLocked(nodeType, name)
+}
+
+// isConfigured reports whether a discovered server is already a node or a known cluster member
+func (h *DiscoveryOnboardingHandlers) isConfigured(server pkgdiscovery.DiscoveredServer) bool {
+	matches := func(host string) bool {
+		hostname := host
+		if parsed, err := url.Parse(host); err == nil && parsed.Hostname() != "" {
+			hostname = parsed.Hostname()
+		} else if hostPart, _, err := net.SplitHostPort(host); err == nil {
+			hostname = hostPart
+		}
+		hostname = strings.ToLower(hostname)
+		return hostname == server.IP || (server.Hostname != "" && hostname == strings.ToLower(server.Hostname))
+	}
+
+	if h.configHandlers == nil {
+		return false
+	}
+	pveInstances, pbsInstances, pmgInstances := h.configHandlers.nodeInstances()
+
+	switch server.Type {
+	case "pve":
+		for _, node := range pveInstances {
+			if matches(node.Host) {
+				return true
+			}
+			for _, endpoint := range node.ClusterEndpoints {
+				if endpoint.IP == server.IP || matches(endpoint.Host) {
+					return true
+				}
+			}
+		}
+	case "pbs":
+		for _, node := range pbsInstances {
+			if matches(node.Host) {
+				return true
+			}
+		}
+	case "pmg":
+		for _, node := range pmgInstances {
+			if matches(node.Host) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactDiscoveryOnboardingConfig blanks stored token values; secret references are shown as-is
+func redactDiscoveryOnboardingConfig(cfg config.DiscoveryOnboardingConfig) config.DiscoveryOnboardingConfig {
+	profiles := make([]config.DiscoveryCredentialProfile, len(cfg.Profiles))
+	for i, profile := range cfg.Profiles {
+		if !config.IsSecretReference(profile.TokenValue) {
+			profile.TokenValue = ""
+		}
+		profiles[i] = profile
+	}
+	cfg.Profiles = profiles
+	return cfg
+}
@@ -62,7 +62,7 @@ func (h *DockerAgentHandlers) HandleReport(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	log.Debug().
+	log.Debug().Ctx(r.Context()).
 		Str("dockerHost", host.Hostname).
 		Int("containers", len(host.Containers)).
 		Msg("Docker agent report processed")
@@ -89,7 +89,7 @@ func (h *DockerAgentHandlers) HandleReport(w http.ResponseWriter, r *http.Reques
 	}
 
 	if err := utils.WriteJSONResponse(w, response); err != nil {
-		log.Error().Err(err).Msg("Failed to serialize docker agent response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker agent response")
 	}
 }
 
@@ -169,7 +169,7 @@ func (h *DockerAgentHandlers) HandleCommandAck(w http.ResponseWriter, r *http.Re
 
 	if shouldRemove {
 		if _, removeErr := h.monitor.RemoveDockerHost(hostID); removeErr != nil {
-			log.Error().Err(removeErr).Str("dockerHostID", hostID).Str("commandID", commandID).Msg("Failed to remove docker host after command completion")
+			log.Error().Ctx(r.Context()).Err(removeErr).Str("dockerHostID", hostID).Str("commandID", commandID).Msg("Failed to remove docker host after command completion")
 		}
 	}
 
@@ -180,7 +180,7 @@ func (h *DockerAgentHandlers) HandleCommandAck(w http.ResponseWriter, r *http.Re
 		"hostId":  hostID,
 		"command": commandStatus,
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to serialize docker command acknowledgement response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker command acknowledgement response")
 	}
 }
 
@@ -225,7 +225,7 @@ func (h *DockerAgentHandlers) HandleDeleteHost(w http.ResponseWriter, r *http.Re
 			"hostId":  host.ID,
 			"message": "Docker host hidden",
 		}); err != nil {
-			log.Error().Err(err).Msg("Failed to serialize docker host operation response")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker host operation response")
 		}
 		return
 	}
@@ -237,7 +237,7 @@ func (h *DockerAgentHandlers) HandleDeleteHost(w http.ResponseWriter, r *http.Re
 				"hostId":  hostID,
 				"message": "Docker host already removed",
 			}); err != nil {
-				log.Error().Err(err).Msg("Failed to serialize docker host operation response")
+				log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker host operation response")
 			}
 			return
 		}
@@ -261,7 +261,7 @@ func (h *DockerAgentHandlers) HandleDeleteHost(w http.ResponseWriter, r *http.Re
 			"command": command,
 			"message": "Stop command queued",
 		}); err != nil {
-			log.Error().Err(err).Msg("Failed to serialize docker host stop command response")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker host stop command response")
 		}
 		return
 	}
@@ -279,7 +279,7 @@ func (h *DockerAgentHandlers) HandleDeleteHost(w http.ResponseWriter, r *http.Re
 		"hostId":  host.ID,
 		"message": "Docker host removed",
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to serialize docker host operation response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker host operation response")
 	}
 }
 
@@ -307,7 +307,7 @@ func (h *DockerAgentHandlers) HandleAllowReenroll(w http.ResponseWriter, r *http
 		"success": true,
 		"hostId":  hostID,
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to serialize docker host allow reenroll response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker host allow reenroll response")
 	}
 }
 
@@ -339,7 +339,7 @@ func (h *DockerAgentHandlers) HandleUnhideHost(w http.ResponseWriter, r *http.Re
 		"hostId":  host.ID,
 		"message": "Docker host unhidden",
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to serialize docker host unhide response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker host unhide response")
 	}
 }
 
@@ -371,6 +371,6 @@ func (h *DockerAgentHandlers) HandleMarkPendingUninstall(w http.ResponseWriter,
 		"hostId":  host.ID,
 		"message": "Docker host marked as pending uninstall",
 	}); err != nil {
-		log.Error().Err(err).Msg("Failed to serialize docker host pending uninstall response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to serialize docker host pending uninstall response")
 	}
 }
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/federation"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/updates"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	minFederationStreamInterval = 5 * time.Second
+	maxFederationStreamInterval = 5 * time.Minute
+)
+
+// FederationHandlers serve both sides of federation: downstream instances export their state
+// to a central instance, which aggregates the sites it is configured with
+type FederationHandlers struct {
+	config      *config.Config
+	monitor     *monitoring.Monitor
+	persistence *config.ConfigPersistence
+}
+
+// NewFederationHandlers creates federation handlers
+func NewFederationHandlers(cfg *config.Config, m *monitoring.Monitor, persistence *config.ConfigPersistence) *FederationHandlers {
+	return &FederationHandlers{config: cfg, monitor: m, persistence: persistence}
+}
+
+// SetMonitor updates the monitor reference for federation handlers.
+func (h *FederationHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+}
+
+// envelope builds the snapshot exported to a central instance, limited to the caller's tenant scope
+func (h *FederationHandlers) envelope(r *http.Request) federation.Envelope {
+	envelope := federation.Envelope{
+		GeneratedAt: time.Now().UTC(),
+		State:       requestTenantScope(h.config, r).FilterSnapshot(h.monitor.GetState()),
+	}
+	envelope.Hostname, _ = os.Hostname()
+	if info, err := updates.GetCurrentVersion(); err == nil {
+		envelope.PulseVersion = info.Version
+	}
+	return envelope
+}
+
+// HandleSnapshot serves GET /api/federation/snapshot
+func (h *FederationHandlers) HandleSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, h.envelope(r)); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write federation snapshot")
+	}
+}
+
+// HandleStream serves GET /api/federation/stream, writing one snapshot per line every interval
+// seconds until the central instance disconnects
+func (h *FederationHandlers) HandleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	interval := time.Duration(config.DefaultFederationIntervalSeconds) * time.Second
+	if value := r.URL.Query().Get("interval"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Invalid interval", http.StatusBadRequest)
+			return
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+	if interval < minFederationStreamInterval {
+		interval = minFederationStreamInterval
+	}
+	if interval > maxFederationStreamInterval {
+		interval = maxFederationStreamInterval
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	encoder := json.NewEncoder(w)
+	send := func() bool {
+		// The server's write timeout would otherwise end the stream
+		_ = controller.SetWriteDeadline(time.Now().Add(interval + 30*time.Second))
+		if err := encoder.Encode(h.envelope(r)); err != nil {
+			return false
+		}
+		return controller.Flush() == nil
+	}
+
+	log.Info().Ctx(r.Context()).
+		Str("client", GetClientIP(r)).
+		Dur("interval", interval).
+		Msg("Federation stream opened")
+	defer log.Info().Ctx(r.Context()).Str("client", GetClientIP(r)).Msg("Federation stream closed")
+
+	if !send() {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if !send() {
+				return
+			}
+		}
+	}
+}
+
+// HandleAck serves POST /api/federation/ack, letting a central instance acknowledge an alert.
+// Callers bound to a tenant scope may only acknowledge alerts within it.
+func (h *FederationHandlers) HandleAck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 16*1024)
+	var req federation.AckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AlertID == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if scope := requestTenantScope(h.config, r); scope != nil {
+		state := h.monitor.GetState()
+		guests := scope.Guests(state)
+		allowed := false
+		for _, alert := range state.ActiveAlerts {
+			if alert.ID == req.AlertID {
+				allowed = scope.AllowsAlert(alert.Instance, alert.ResourceID, guests)
+				break
+			}
+		}
+		if !allowed {
+			http.Error(w, "Alert not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	user := requestUsername(h.config, w, r)
+	if req.User != "" {
+		user = req.User + " (via " + user + ")"
+	}
+
+	manager := h.monitor.GetAlertManager()
+	var err error
+	if req.Acknowledged {
+		err = manager.AcknowledgeAlert(req.AlertID, user)
+	} else {
+		err = manager.UnacknowledgeAlert(req.AlertID)
+	}
+	LogAuditEvent("federation_alert_ack", user, GetClientIP(r), r.URL.Path, err == nil,
+		fmt.Sprintf("alert=%s acknowledged=%t", req.AlertID, req.Acknowledged))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, map[string]bool{"success": true}); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write federation ack response")
+	}
+}
+
+// HandleSites serves /api/federation/sites on the central instance
+func (h *FederationHandlers) HandleSites(w http.ResponseWriter, r *http.Request) {
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if err := utils.WriteJSONResponse(w, redactFederationConfig(h.monitor.GetFederationConfig())); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write federation sites response")
+		}
+	case http.MethodPut:
+		r.Body = http.MaxBytesReader(w, r.Body, 256*1024)
+		var cfg config.FederationConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		cfg = config.NormalizeFederationConfig(cfg)
+
+		// If a token is empty, preserve the existing token of the site
+		existing := h.monitor.GetFederationConfig()
+		for i := range cfg.Sites {
+			if cfg.Sites[i].Token == "" {
+				if site, ok := existing.Site(cfg.Sites[i].ID); ok {
+					cfg.Sites[i].Token = site.Token
+				}
+			}
+		}
+		if err := cfg.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h.persistence.SaveFederationConfig(cfg); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save federation configuration")
+			http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+			return
+		}
+		h.monitor.SetFederationConfig(cfg)
+
+		LogAuditEvent("federation_config", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true,
+			fmt.Sprintf("enabled=%t sites=%d", cfg.Enabled, len(cfg.Sites)))
+
+		if err := utils.WriteJSONResponse(w, redactFederationConfig(h.monitor.GetFederationConfig())); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write federation sites response")
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleStatus serves GET /api/federation/status with the health of every site
+func (h *FederationHandlers) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, h.monitor.GetFederationSites()); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write federation status response")
+	}
+}
+
+// HandleState serves GET /api/federation/state, the global view across all sites
+func (h *FederationHandlers) HandleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+	response := models.FederatedStateFrontend{
+		Sites: h.monitor.GetFederationSites(),
+		State: h.monitor.GetFederatedState().ToFrontend(),
+	}
+	if err := utils.WriteJSONResponse(w, response); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write federated state response")
+	}
+}
+
+// HandleAlertAck serves POST /api/federation/alerts/ack and forwards the acknowledgement of a
+// site's alert to its downstream instance
+func (h *FederationHandlers) HandleAlertAck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 16*1024)
+	var req federation.AckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AlertID == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := requestUsername(h.config, w, r)
+	err := h.monitor.AcknowledgeFederatedAlert(r.Context(), req.AlertID, req.Acknowledged, user)
+	LogAuditEvent("federation_alert_ack", user, GetClientIP(r), r.URL.Path, err == nil,
+		fmt.Sprintf("alert=%s acknowledged=%t", req.AlertID, req.Acknowledged))
+	if err != nil {
+		log.Warn().Ctx(r.Context()).Err(err).Str("alert", req.AlertID).Msg("Failed to forward alert acknowledgement")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, map[string]bool{"success": true}); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write federation ack response")
+	}
+}
+
+// redactFederationConfig blanks stored site tokens; secret references are shown as-is
+func redactFederationConfig(cfg config.FederationConfig) config.FederationConfig {
+	sites := make([]config.FederationSite, len(cfg.Sites))
+	for i, site := range cfg.Sites {
+		if !config.IsSecretReference(site.Token) {
+			site.Token = ""
+		}
+		sites[i] = site
+	}
+	cfg.Sites = sites
+	return cfg
+}
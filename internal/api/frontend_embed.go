@@ -41,7 +41,7 @@ func getFrontendDevProxy() (*httputil.ReverseProxy, error) {
 
 		proxy := httputil.NewSingleHostReverseProxy(target)
 		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-			log.Error().Err(err).Str("path", r.URL.Path).Msg("Frontend dev proxy error")
+			log.Error().Ctx(r.Context()).Err(err).Str("path", r.URL.Path).Msg("Frontend dev proxy error")
 			w.WriteHeader(http.StatusBadGateway)
 		}
 		devProxy = proxy
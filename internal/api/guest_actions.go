@@ -0,0 +1,235 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/websocket"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/rs/zerolog/log"
+)
+
+// maxGuestShutdownTimeout caps the shutdown timeout accepted from clients (seconds)
+const maxGuestShutdownTimeout = 3600
+
+// GuestActionHandlers runs power, lifecycle and snapshot actions on VMs and containers
+type GuestActionHandlers struct {
+	config  *config.Config
+	monitor *monitoring.Monitor
+	wsHub   *websocket.Hub
+}
+
+type guestActionRequest struct {
+	Action     string `json:"action"`
+	TargetNode string `json:"targetNode,omitempty"`
+	Online     bool   `json:"online,omitempty"`
+	Timeout    int    `json:"timeout,omitempty"`
+	ForceStop  bool   `json:"forceStop,omitempty"`
+}
+
+// NewGuestActionHandlers creates guest action handlers
+func NewGuestActionHandlers(cfg *config.Config, m *monitoring.Monitor, hub *websocket.Hub) *GuestActionHandlers {
+	return &GuestActionHandlers{config: cfg, monitor: m, wsHub: hub}
+}
+
+// SetMonitor updates the monitor reference for guest action handlers.
+func (h *GuestActionHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+}
+
+type guestSnapshotRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	VMState     bool   `json:"vmState,omitempty"`
+}
+
+// HandleGuestActions routes /api/guests/{id}/actions and /api/guests/{id}/snapshots[/{name}[/rollback]]
+func (h *GuestActionHandlers) HandleGuestActions(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/guests/"), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	guestID := parts[0]
+
+	switch {
+	case len(parts) == 2 && parts[1] == "actions":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.handleGuestAction(w, r, guestID)
+	case len(parts) == 2 && parts[1] == "snapshots":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.handleCreateSnapshot(w, r, guestID)
+	case len(parts) == 3 && parts[1] == "snapshots" && parts[2] != "":
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.runTask(w, r, "guest_snapshot_delete", guestID, "snapshot="+parts[2], func(onUpdate func(monitoring.GuestActionStatus)) (monitoring.GuestActionStatus, error) {
+			return h.monitor.DeleteGuestSnapshot(guestID, parts[2], h.requestUser(w, r), onUpdate)
+		})
+	case len(parts) == 4 && parts[1] == "snapshots" && parts[2] != "" && parts[3] == "rollback":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.runTask(w, r, "guest_snapshot_rollback", guestID, "snapshot="+parts[2], func(onUpdate func(monitoring.GuestActionStatus)) (monitoring.GuestActionStatus, error) {
+			return h.monitor.RollbackGuestSnapshot(guestID, parts[2], h.requestUser(w, r), onUpdate)
+		})
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+func (h *GuestActionHandlers) handleGuestAction(w http.ResponseWriter, r *http.Request, guestID string) {
+	r.Body = http.MaxBytesReader(w, r.Body, 16*1024)
+	var req guestActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	action, err := proxmox.ParseGuestAction(req.Action)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Timeout < 0 || req.Timeout > maxGuestShutdownTimeout {
+		http.Error(w, fmt.Sprintf("timeout must be between 0 and %d seconds", maxGuestShutdownTimeout), http.StatusBadRequest)
+		return
+	}
+	if action == proxmox.GuestActionMigrate && strings.TrimSpace(req.TargetNode) == "" {
+		http.Error(w, "targetNode is required for migrate", http.StatusBadRequest)
+		return
+	}
+
+	opts := proxmox.GuestActionOptions{
+		TargetNode: strings.TrimSpace(req.TargetNode),
+		Online:     req.Online,
+		Timeout:    req.Timeout,
+		ForceStop:  req.ForceStop,
+	}
+
+	details := "action=" + string(action)
+	if opts.TargetNode != "" {
+		details += " target=" + opts.TargetNode
+	}
+	h.runTask(w, r, "guest_action", guestID, details, func(onUpdate func(monitoring.GuestActionStatus)) (monitoring.GuestActionStatus, error) {
+		return h.monitor.StartGuestAction(guestID, action, opts, h.requestUser(w, r), onUpdate)
+	})
+}
+
+func (h *GuestActionHandlers) handleCreateSnapshot(w http.ResponseWriter, r *http.Request, guestID string) {
+	r.Body = http.MaxBytesReader(w, r.Body, 16*1024)
+	var req guestSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := proxmox.ValidateSnapshotName(req.Name); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.runTask(w, r, "guest_snapshot_create", guestID, "snapshot="+req.Name, func(onUpdate func(monitoring.GuestActionStatus)) (monitoring.GuestActionStatus, error) {
+		return h.monitor.SnapshotGuest(guestID, req.Name, strings.TrimSpace(req.Description), req.VMState, h.requestUser(w, r), onUpdate)
+	})
+}
+
+// runTask starts a guest task, audit-logs the request and its outcome and streams progress over WebSocket
+func (h *GuestActionHandlers) runTask(w http.ResponseWriter, r *http.Request, event, guestID, details string, start func(onUpdate func(monitoring.GuestActionStatus)) (monitoring.GuestActionStatus, error)) {
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	user := h.requestUser(w, r)
+	clientIP := GetClientIP(r)
+	path := r.URL.Path
+	auditDetails := func(status monitoring.GuestActionStatus) string {
+		result := fmt.Sprintf("guest=%s %s", guestID, details)
+		if status.UPID != "" {
+			result += " upid=" + status.UPID
+		}
+		if status.Error != "" {
+			result += " error=" + status.Error
+		}
+		return result
+	}
+
+	status, err := start(func(update monitoring.GuestActionStatus) {
+		// Running updates only carry new task log lines; just the outcome is audit-logged
+		if update.FinishedAt != nil {
+			LogAuditEvent(event+"_completed", user, clientIP, path, update.Status == monitoring.GuestActionStatusCompleted, auditDetails(update))
+		}
+		h.broadcast(update)
+	})
+	if err != nil {
+		status.Error = err.Error()
+		LogAuditEvent(event, user, clientIP, path, false, auditDetails(status))
+		log.Warn().Ctx(r.Context()).Err(err).Str("guest", guestID).Str("event", event).Msg("Guest task failed to start")
+
+		code := http.StatusBadGateway
+		if status.ID == "" {
+			// The guest or its client could not be resolved
+			code = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	LogAuditEvent(event, user, clientIP, path, true, auditDetails(status))
+	h.broadcast(status)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write guest action response")
+	}
+}
+
+func (h *GuestActionHandlers) broadcast(status monitoring.GuestActionStatus) {
+	if h.wsHub == nil {
+		return
+	}
+	h.wsHub.BroadcastMessage(websocket.Message{
+		Type:      "guestAction",
+		Data:      status,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// requestUser identifies the caller for audit logging
+func (h *GuestActionHandlers) requestUser(w http.ResponseWriter, r *http.Request) string {
+	return requestUsername(h.config, w, r)
+}
+
+// requestUsername resolves the authenticated user, session or API token behind a request
+func requestUsername(cfg *config.Config, w http.ResponseWriter, r *http.Request) string {
+	if user := w.Header().Get("X-Authenticated-User"); user != "" {
+		return user
+	}
+	if cookie, err := r.Cookie("pulse_session"); err == nil && cookie.Value != "" {
+		if user := GetSessionUsername(cookie.Value); user != "" {
+			return user
+		}
+	}
+	if record := getAPITokenRecordFromRequest(r); record != nil {
+		return "token:" + record.Name
+	}
+	if cfg != nil {
+		return cfg.AuthUser
+	}
+	return ""
+}
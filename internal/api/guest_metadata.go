@@ -118,7 +118,7 @@ func (h *GuestMetadataHandler) HandleUpdateMetadata(w http.ResponseWriter, r *ht
 	}
 
 	if err := h.store.Set(guestID, &meta); err != nil {
-		log.Error().Err(err).Str("guestID", guestID).Msg("Failed to save guest metadata")
+		log.Error().Ctx(r.Context()).Err(err).Str("guestID", guestID).Msg("Failed to save guest metadata")
 		// Provide more specific error message
 		errMsg := "Failed to save metadata"
 		if strings.Contains(err.Error(), "permission") {
@@ -130,7 +130,7 @@ func (h *GuestMetadataHandler) HandleUpdateMetadata(w http.ResponseWriter, r *ht
 		return
 	}
 
-	log.Info().Str("guestID", guestID).Str("url", meta.CustomURL).Msg("Updated guest metadata")
+	log.Info().Ctx(r.Context()).Str("guestID", guestID).Str("url", meta.CustomURL).Msg("Updated guest metadata")
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(&meta)
@@ -150,12 +150,12 @@ func (h *GuestMetadataHandler) HandleDeleteMetadata(w http.ResponseWriter, r *ht
 	}
 
 	if err := h.store.Delete(guestID); err != nil {
-		log.Error().Err(err).Str("guestID", guestID).Msg("Failed to delete guest metadata")
+		log.Error().Ctx(r.Context()).Err(err).Str("guestID", guestID).Msg("Failed to delete guest metadata")
 		http.Error(w, "Failed to delete metadata", http.StatusInternalServerError)
 		return
 	}
 
-	log.Info().Str("guestID", guestID).Msg("Deleted guest metadata")
+	log.Info().Ctx(r.Context()).Str("guestID", guestID).Msg("Deleted guest metadata")
 
 	w.WriteHeader(http.StatusNoContent)
 }
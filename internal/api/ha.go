@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/ha"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// HAHandlers serve the requests the two instances of an HA pair make to each other. They are
+// authenticated with the shared HA secret instead of user credentials.
+type HAHandlers struct {
+	monitor *monitoring.Monitor
+	node    *ha.Node
+}
+
+// NewHAHandlers creates HA handlers; they answer 404 until a node is set
+func NewHAHandlers(m *monitoring.Monitor) *HAHandlers {
+	return &HAHandlers{monitor: m}
+}
+
+// SetMonitor updates the monitor reference for HA handlers.
+func (h *HAHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+}
+
+// SetNode enables the HA endpoints
+func (h *HAHandlers) SetNode(node *ha.Node) {
+	h.node = node
+}
+
+// Status returns the HA state, or nil when HA is disabled
+func (h *HAHandlers) Status() *ha.Status {
+	if h == nil || h.node == nil {
+		return nil
+	}
+	status := h.node.Status()
+	return &status
+}
+
+func (h *HAHandlers) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if h.node == nil {
+		http.NotFound(w, r)
+		return false
+	}
+	if !h.node.Authorized(r) {
+		LogAuditEvent("ha_auth_failed", "", GetClientIP(r), r.URL.Path, false, "Invalid HA secret")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// HandleLease serves POST /api/ha/lease, where the peer asks for or renews the lease
+func (h *HAHandlers) HandleLease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+	peer := h.node.Peer()
+	if peer == nil {
+		http.Error(w, "This instance does not use the peer lock", http.StatusNotFound)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 16*1024)
+	var req ha.LeaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Holder == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, peer.Grant(req)); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write HA lease response")
+	}
+}
+
+// HandleReplicate serves GET /api/ha/replicate on the leader with an archive of its data
+// directory, including the active alerts it just saved
+func (h *HAHandlers) HandleReplicate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorize(w, r) {
+		return
+	}
+	if !h.node.IsLeader() {
+		http.Error(w, "This instance is not the HA leader", http.StatusConflict)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Large data directories can take longer than the server's write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(5 * time.Minute))
+	w.Header().Set("Content-Type", "application/gzip")
+	if _, err := h.monitor.WriteInstanceBackup(w); err != nil {
+		// The standby rejects the truncated archive
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write HA replication archive")
+	}
+}
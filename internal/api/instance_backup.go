@@ -0,0 +1,209 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/RouXx67/PulseUp/internal/backup"
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// maxRestoreUploadSize caps the size of an uploaded backup archive
+const maxRestoreUploadSize = 4 << 30
+
+// InstanceBackupHandlers create, restore and schedule backups of the whole data directory
+type InstanceBackupHandlers struct {
+	config      *config.Config
+	monitor     *monitoring.Monitor
+	persistence *config.ConfigPersistence
+}
+
+// NewInstanceBackupHandlers creates instance backup handlers
+func NewInstanceBackupHandlers(cfg *config.Config, m *monitoring.Monitor, persistence *config.ConfigPersistence) *InstanceBackupHandlers {
+	return &InstanceBackupHandlers{config: cfg, monitor: m, persistence: persistence}
+}
+
+// SetMonitor updates the monitor reference for instance backup handlers.
+func (h *InstanceBackupHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+}
+
+type restoreResponse struct {
+	Success         bool     `json:"success"`
+	CreatedAt       string   `json:"createdAt"`
+	PulseVersion    string   `json:"pulseVersion,omitempty"`
+	Hostname        string   `json:"hostname,omitempty"`
+	Files           int      `json:"files"`
+	TotalSize       int64    `json:"totalSize"`
+	Runtime         []string `json:"runtime,omitempty"`
+	RestartRequired bool     `json:"restartRequired"`
+	Message         string   `json:"message"`
+}
+
+// HandleCreate serves POST /api/system/backup and streams a new archive as a download
+func (h *InstanceBackupHandlers) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Build the archive in a temporary file so a failure can still be reported as an error
+	tmp, err := os.CreateTemp("", "pulse-backup-*.tar.gz")
+	if err != nil {
+		http.Error(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	manifest, err := h.monitor.WriteInstanceBackup(tmp)
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to create instance backup")
+		LogAuditEvent("instance_backup_create", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, false, err.Error())
+		http.Error(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+	size, err := tmp.Seek(0, io.SeekEnd)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		http.Error(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+
+	LogAuditEvent("instance_backup_create", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true,
+		fmt.Sprintf("files=%d size=%d", len(manifest.Files), size))
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", backup.ArchiveName(manifest.CreatedAt)))
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	if _, err := io.Copy(w, tmp); err != nil {
+		log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to send instance backup")
+	}
+}
+
+// HandleRestore serves POST /api/system/backup/restore. The uploaded archive is verified and
+// staged; it replaces the data directory when Pulse next starts.
+func (h *InstanceBackupHandlers) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRestoreUploadSize)
+	manifest, err := backup.Stage(r.Body, h.config.DataPath)
+	if err != nil {
+		log.Warn().Ctx(r.Context()).Err(err).Msg("Rejected instance backup restore")
+		LogAuditEvent("instance_backup_restore", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, false, err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	LogAuditEvent("instance_backup_restore", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true,
+		fmt.Sprintf("createdAt=%s files=%d", manifest.CreatedAt.Format("2006-01-02T15:04:05Z"), len(manifest.Files)))
+
+	response := restoreResponse{
+		Success:         true,
+		CreatedAt:       manifest.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		PulseVersion:    manifest.PulseVersion,
+		Hostname:        manifest.Hostname,
+		Files:           len(manifest.Files),
+		TotalSize:       manifest.TotalSize(),
+		Runtime:         manifest.RuntimeNames(),
+		RestartRequired: true,
+		Message:         "Backup verified. Restart Pulse to apply the restore.",
+	}
+	if err := utils.WriteJSONResponse(w, response); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write restore response")
+	}
+}
+
+// HandleRun serves POST /api/system/backup/run and stores a backup on the configured target now
+func (h *InstanceBackupHandlers) HandleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	name, err := h.monitor.RunInstanceBackup(r.Context())
+	LogAuditEvent("instance_backup_run", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, err == nil, name)
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Instance backup failed")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, map[string]string{"archive": name}); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write backup run response")
+	}
+}
+
+// HandleSchedule serves /api/system/backup/schedule
+func (h *InstanceBackupHandlers) HandleSchedule(w http.ResponseWriter, r *http.Request) {
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if err := utils.WriteJSONResponse(w, redactInstanceBackupConfig(h.monitor.GetInstanceBackupConfig())); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write instance backup schedule response")
+		}
+	case http.MethodPut:
+		r.Body = http.MaxBytesReader(w, r.Body, 64*1024)
+		var cfg config.InstanceBackupConfig
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		// If the secret is empty, preserve the existing secret
+		if cfg.S3.SecretAccessKey == "" {
+			cfg.S3.SecretAccessKey = h.monitor.GetInstanceBackupConfig().S3.SecretAccessKey
+		}
+		cfg = config.NormalizeInstanceBackupConfig(cfg)
+		if err := cfg.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h.persistence.SaveInstanceBackupConfig(cfg); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save instance backup configuration")
+			http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+			return
+		}
+		h.monitor.SetInstanceBackupConfig(cfg)
+
+		LogAuditEvent("instance_backup_schedule", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true,
+			fmt.Sprintf("enabled=%t target=%s intervalHours=%d retain=%d", cfg.Enabled, cfg.Target, cfg.IntervalHours, cfg.Retain))
+
+		if err := utils.WriteJSONResponse(w, redactInstanceBackupConfig(h.monitor.GetInstanceBackupConfig())); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write instance backup schedule response")
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// redactInstanceBackupConfig blanks a stored S3 secret; secret references are shown as-is
+func redactInstanceBackupConfig(cfg config.InstanceBackupConfig) config.InstanceBackupConfig {
+	if !config.IsSecretReference(cfg.S3.SecretAccessKey) {
+		cfg.S3.SecretAccessKey = ""
+	}
+	return cfg
+}
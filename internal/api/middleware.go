@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/logging"
+	"github.com/RouXx67/PulseUp/internal/tracing"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // APIError represents a structured API error response
@@ -57,15 +59,20 @@ func ErrorHandler(next http.Handler) http.Handler {
 		routeLabel := normalizeRoute(r.URL.Path)
 		method := r.Method
 
+		ctxWithSpan, span := tracing.StartServer(r, routeLabel)
+		span.SetAttributes(attribute.String("pulse.request_id", requestID))
+		r = r.WithContext(ctxWithSpan)
+
 		defer func() {
 			elapsed := time.Since(start)
 			recordAPIRequest(method, routeLabel, rw.StatusCode(), elapsed)
+			tracing.EndServer(span, rw.StatusCode())
 		}()
 
 		// Recover from panics
 		defer func() {
 			if err := recover(); err != nil {
-				log.Error().
+				log.Error().Ctx(r.Context()).
 					Interface("error", err).
 					Str("path", r.URL.Path).
 					Str("method", r.Method).
@@ -83,7 +90,7 @@ func ErrorHandler(next http.Handler) http.Handler {
 
 		// Log errors (4xx and 5xx)
 		if rw.statusCode >= 400 {
-			log.Warn().
+			log.Warn().Ctx(r.Context()).
 				Str("path", r.URL.Path).
 				Str("method", r.Method).
 				Int("status", rw.statusCode).
@@ -121,7 +128,7 @@ func JSONHandler(handler func(w http.ResponseWriter, r *http.Request) error) htt
 			}
 
 			// Generic error
-			log.Error().Err(err).
+			log.Error().Ctx(r.Context()).Err(err).
 				Str("path", r.URL.Path).
 				Str("method", r.Method).
 				Msg("Handler error")
@@ -188,6 +195,21 @@ func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return hijacker.Hijack()
 }
 
+// Flush implements http.Flusher so streaming responses reach the client
+func (rw *responseWriter) Flush() {
+	if !rw.written {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // NewAPIError creates a new API error
 func NewAPIError(statusCode int, code, message string) error {
 	return &APIError{
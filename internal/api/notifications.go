@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/RouXx67/PulseUp/internal/alerts"
 	"github.com/RouXx67/PulseUp/internal/monitoring"
 	"github.com/RouXx67/PulseUp/internal/notifications"
 	"github.com/RouXx67/PulseUp/internal/utils"
@@ -51,49 +52,127 @@ func (h *NotificationHandlers) UpdateEmailConfig(w http.ResponseWriter, r *http.
 	}
 
 	// NEVER log the body as it contains passwords
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Msg("Received email config update")
 
-	var config notifications.EmailConfig
-	if err := json.Unmarshal(body, &config); err != nil {
-		log.Error().Err(err).Msg("Failed to parse email config") // Don't log body with passwords
+	config, err := mergeEmailConfigUpdate(body, h.monitor.GetNotificationManager().GetEmailConfig())
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to parse email config") // Don't log body with passwords
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// If password is empty, preserve the existing password
-	if config.Password == "" {
-		existingConfig := h.monitor.GetNotificationManager().GetEmailConfig()
-		config.Password = existingConfig.Password
-	}
-
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Bool("enabled", config.Enabled).
 		Str("smtp", config.SMTPHost).
 		Str("from", config.From).
 		Int("toCount", len(config.To)).
 		Bool("hasPassword", config.Password != "").
+		Bool("customTemplates", !config.Templates.IsEmpty()).
 		Msg("Parsed email config")
 
+	if err := h.monitor.GetNotificationManager().ValidateEmailTemplates(config.Templates); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid email template: %v", err), http.StatusBadRequest)
+		return
+	}
+
 	h.monitor.GetNotificationManager().SetEmailConfig(config)
 
 	// Save to persistent storage
 	if err := h.monitor.GetConfigPersistence().SaveEmailConfig(config); err != nil {
 		// Log error but don't fail the request
-		log.Error().Err(err).Msg("Failed to save email configuration")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save email configuration")
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
 }
 
+// mergeEmailConfigUpdate parses an email config update. An empty password keeps the stored one
+// and omitted templates keep the stored templates, since the settings form sends neither back.
+func mergeEmailConfigUpdate(body []byte, existing notifications.EmailConfig) (notifications.EmailConfig, error) {
+	var config notifications.EmailConfig
+	if err := json.Unmarshal(body, &config); err != nil {
+		return config, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return config, err
+	}
+
+	if config.Password == "" {
+		config.Password = existing.Password
+	}
+	if _, ok := fields["templates"]; !ok {
+		config.Templates = existing.Templates
+	}
+	return config, nil
+}
+
+// GetEmailTemplateDefaults returns the built-in email templates
+func (h *NotificationHandlers) GetEmailTemplateDefaults(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications.DefaultEmailTemplates())
+}
+
+// PreviewEmailTemplate renders email templates against sample alerts or a real active alert
+func (h *NotificationHandlers) PreviewEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Templates *notifications.EmailTemplates `json:"templates,omitempty"` // Defaults to the saved templates
+		AlertID   string                        `json:"alertId,omitempty"`   // Render against an active alert instead of samples
+		Grouped   bool                          `json:"grouped"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1024*1024)).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manager := h.monitor.GetNotificationManager()
+	templates := manager.GetEmailConfig().Templates
+	if req.Templates != nil {
+		templates = *req.Templates
+	}
+
+	alertList := notifications.SampleEmailAlerts()
+	if req.AlertID != "" {
+		alertList = nil
+		for _, alert := range h.monitor.GetAlertManager().GetActiveAlerts() {
+			if alert.ID == req.AlertID {
+				alertList = []*alerts.Alert{alert.Clone()}
+				break
+			}
+		}
+		if alertList == nil {
+			http.Error(w, "Alert not found", http.StatusNotFound)
+			return
+		}
+	} else if !req.Grouped {
+		alertList = alertList[:1]
+	}
+
+	subject, htmlBody, textBody, err := manager.RenderEmail(templates, alertList, !req.Grouped)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to render email template: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := utils.WriteJSONResponse(w, map[string]string{
+		"subject": subject,
+		"html":    htmlBody,
+		"text":    textBody,
+	}); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write email preview response")
+	}
+}
+
 // GetAppriseConfig returns the current Apprise configuration.
 func (h *NotificationHandlers) GetAppriseConfig(w http.ResponseWriter, r *http.Request) {
 	config := h.monitor.GetNotificationManager().GetAppriseConfig()
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(config); err != nil {
-		log.Error().Err(err).Msg("Failed to encode Apprise configuration response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode Apprise configuration response")
 	}
 }
 
@@ -111,7 +190,7 @@ func (h *NotificationHandlers) UpdateAppriseConfig(w http.ResponseWriter, r *htt
 		return
 	}
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Bool("enabled", config.Enabled).
 		Str("mode", string(config.Mode)).
 		Int("targetCount", len(config.Targets)).
@@ -127,14 +206,165 @@ func (h *NotificationHandlers) UpdateAppriseConfig(w http.ResponseWriter, r *htt
 	h.monitor.GetNotificationManager().SetAppriseConfig(config)
 
 	if err := h.monitor.GetConfigPersistence().SaveAppriseConfig(config); err != nil {
-		log.Error().Err(err).Msg("Failed to save Apprise configuration")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save Apprise configuration")
 	}
 
 	normalized := h.monitor.GetNotificationManager().GetAppriseConfig()
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(normalized); err != nil {
-		log.Error().Err(err).Msg("Failed to encode Apprise configuration response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode Apprise configuration response")
+	}
+}
+
+// GetSyslogConfig returns the current syslog configuration.
+func (h *NotificationHandlers) GetSyslogConfig(w http.ResponseWriter, r *http.Request) {
+	config := h.monitor.GetNotificationManager().GetSyslogConfig()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(config); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode syslog configuration response")
+	}
+}
+
+// UpdateSyslogConfig updates the syslog configuration.
+func (h *NotificationHandlers) UpdateSyslogConfig(w http.ResponseWriter, r *http.Request) {
+	var config notifications.SyslogConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := notifications.ValidateSyslogConfig(config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Info().Ctx(r.Context()).
+		Bool("enabled", config.Enabled).
+		Str("protocol", string(config.Protocol)).
+		Str("host", config.Host).
+		Int("port", config.Port).
+		Msg("Parsed syslog configuration update")
+
+	h.monitor.GetNotificationManager().SetSyslogConfig(config)
+
+	if err := h.monitor.GetConfigPersistence().SaveSyslogConfig(config); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save syslog configuration")
+	}
+
+	normalized := h.monitor.GetNotificationManager().GetSyslogConfig()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(normalized); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode syslog configuration response")
+	}
+}
+
+// TestSyslog sends a test message using the provided or saved syslog configuration.
+func (h *NotificationHandlers) TestSyslog(w http.ResponseWriter, r *http.Request) {
+	config := h.monitor.GetNotificationManager().GetSyslogConfig()
+	if r.ContentLength != 0 {
+		var provided notifications.SyslogConfig
+		if err := json.NewDecoder(r.Body).Decode(&provided); err != nil && err != io.EOF {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err == nil {
+			config = provided
+		}
+	}
+
+	if err := h.monitor.GetNotificationManager().SendTestSyslog(config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "Test syslog message sent"})
+}
+
+// GetSNMPConfig returns the current SNMP trap configuration.
+func (h *NotificationHandlers) GetSNMPConfig(w http.ResponseWriter, r *http.Request) {
+	config := h.monitor.GetNotificationManager().GetSNMPConfig()
+
+	// For security, don't return the community or passphrases
+	config.Community = ""
+	config.AuthPassphrase = ""
+	config.PrivPassphrase = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(config); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode SNMP configuration response")
+	}
+}
+
+// UpdateSNMPConfig updates the SNMP trap configuration.
+func (h *NotificationHandlers) UpdateSNMPConfig(w http.ResponseWriter, r *http.Request) {
+	var config notifications.SNMPConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.preserveSNMPSecrets(&config)
+
+	if err := notifications.ValidateSNMPConfig(config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Info().Ctx(r.Context()).
+		Bool("enabled", config.Enabled).
+		Str("host", config.Host).
+		Int("port", config.Port).
+		Str("version", config.Version).
+		Msg("Parsed SNMP configuration update")
+
+	h.monitor.GetNotificationManager().SetSNMPConfig(config)
+
+	if err := h.monitor.GetConfigPersistence().SaveSNMPConfig(config); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save SNMP configuration")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// TestSNMP sends a test trap using the provided or saved SNMP configuration.
+func (h *NotificationHandlers) TestSNMP(w http.ResponseWriter, r *http.Request) {
+	config := h.monitor.GetNotificationManager().GetSNMPConfig()
+	if r.ContentLength != 0 {
+		var provided notifications.SNMPConfig
+		if err := json.NewDecoder(r.Body).Decode(&provided); err != nil && err != io.EOF {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err == nil {
+			h.preserveSNMPSecrets(&provided)
+			config = provided
+		}
+	}
+
+	if err := h.monitor.GetNotificationManager().SendTestSNMP(config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success", "message": "Test SNMP trap sent"})
+}
+
+// preserveSNMPSecrets keeps saved secrets when the client leaves them empty,
+// since GetSNMPConfig never returns them
+func (h *NotificationHandlers) preserveSNMPSecrets(config *notifications.SNMPConfig) {
+	existing := h.monitor.GetNotificationManager().GetSNMPConfig()
+	if config.Community == "" {
+		config.Community = existing.Community
+	}
+	if config.AuthPassphrase == "" {
+		config.AuthPassphrase = existing.AuthPassphrase
+	}
+	if config.PrivPassphrase == "" {
+		config.PrivPassphrase = existing.PrivPassphrase
 	}
 }
 
@@ -177,19 +407,19 @@ func (h *NotificationHandlers) CreateWebhook(w http.ResponseWriter, r *http.Requ
 	// Save webhooks to persistent storage with all fields
 	webhooks := h.monitor.GetNotificationManager().GetWebhooks()
 	if err := h.monitor.GetConfigPersistence().SaveWebhooks(webhooks); err != nil {
-		log.Error().Err(err).Msg("Failed to save webhooks")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save webhooks")
 	}
 
 	// Return the full webhook data including any extra fields like 'service'
 	var responseData map[string]interface{}
 	if err := json.Unmarshal(bodyBytes, &responseData); err != nil {
-		log.Warn().Err(err).Msg("Failed to unmarshal webhook response data")
+		log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to unmarshal webhook response data")
 		responseData = make(map[string]interface{})
 	}
 	responseData["id"] = webhook.ID
 
 	if err := utils.WriteJSONResponse(w, responseData); err != nil {
-		log.Error().Err(err).Msg("Failed to write webhook creation response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write webhook creation response")
 	}
 }
 
@@ -233,19 +463,19 @@ func (h *NotificationHandlers) UpdateWebhook(w http.ResponseWriter, r *http.Requ
 	// Save webhooks to persistent storage
 	webhooks := h.monitor.GetNotificationManager().GetWebhooks()
 	if err := h.monitor.GetConfigPersistence().SaveWebhooks(webhooks); err != nil {
-		log.Error().Err(err).Msg("Failed to save webhooks")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save webhooks")
 	}
 
 	// Return the full webhook data including any extra fields like 'service'
 	var responseData map[string]interface{}
 	if err := json.Unmarshal(bodyBytes, &responseData); err != nil {
-		log.Warn().Err(err).Msg("Failed to unmarshal webhook response data")
+		log.Warn().Ctx(r.Context()).Err(err).Msg("Failed to unmarshal webhook response data")
 		responseData = make(map[string]interface{})
 	}
 	responseData["id"] = webhookID
 
 	if err := utils.WriteJSONResponse(w, responseData); err != nil {
-		log.Error().Err(err).Str("webhookID", webhookID).Msg("Failed to write webhook update response")
+		log.Error().Ctx(r.Context()).Err(err).Str("webhookID", webhookID).Msg("Failed to write webhook update response")
 	}
 }
 
@@ -269,11 +499,11 @@ func (h *NotificationHandlers) DeleteWebhook(w http.ResponseWriter, r *http.Requ
 	// Save webhooks to persistent storage
 	webhooks := h.monitor.GetNotificationManager().GetWebhooks()
 	if err := h.monitor.GetConfigPersistence().SaveWebhooks(webhooks); err != nil {
-		log.Error().Err(err).Msg("Failed to save webhooks")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save webhooks")
 	}
 
 	if err := utils.WriteJSONResponse(w, map[string]string{"status": "success"}); err != nil {
-		log.Error().Err(err).Str("webhookID", webhookID).Msg("Failed to write webhook deletion response")
+		log.Error().Ctx(r.Context()).Err(err).Str("webhookID", webhookID).Msg("Failed to write webhook deletion response")
 	}
 }
 
@@ -287,7 +517,7 @@ func (h *NotificationHandlers) TestNotification(w http.ResponseWriter, r *http.R
 	}
 
 	// NEVER log the body as it contains passwords
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Msg("Test notification request received")
 
 	var req struct {
@@ -324,7 +554,7 @@ func (h *NotificationHandlers) TestNotification(w http.ResponseWriter, r *http.R
 
 	// Handle webhook testing
 	if req.Method == "webhook" && req.WebhookID != "" {
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Str("webhookId", req.WebhookID).
 			Msg("Testing specific webhook")
 
@@ -356,7 +586,7 @@ func (h *NotificationHandlers) TestNotification(w http.ResponseWriter, r *http.R
 			req.Config.Password = savedConfig.Password
 		}
 
-		log.Info().
+		log.Info().Ctx(r.Context()).
 			Bool("enabled", req.Config.Enabled).
 			Str("smtp", req.Config.SMTPHost).
 			Str("from", req.Config.From).
@@ -451,7 +681,7 @@ func (h *NotificationHandlers) TestWebhook(w http.ResponseWriter, r *http.Reques
 		webhook.WebhookConfig.Service = serviceCheck.Service
 	}
 
-	log.Info().
+	log.Info().Ctx(r.Context()).
 		Str("service", webhook.Service).
 		Str("url", webhook.URL).
 		Str("name", webhook.Name).
@@ -473,7 +703,7 @@ func (h *NotificationHandlers) TestWebhook(w http.ResponseWriter, r *http.Reques
 						webhook.Headers[k] = v
 					}
 				}
-				log.Info().Str("service", webhook.Service).Msg("Found template for service")
+				log.Info().Ctx(r.Context()).Str("service", webhook.Service).Msg("Found template for service")
 				break
 			}
 		}
@@ -530,10 +760,26 @@ func (h *NotificationHandlers) HandleNotifications(w http.ResponseWriter, r *htt
 		h.GetEmailConfig(w, r)
 	case path == "/email" && r.Method == http.MethodPut:
 		h.UpdateEmailConfig(w, r)
+	case path == "/email/preview" && r.Method == http.MethodPost:
+		h.PreviewEmailTemplate(w, r)
+	case path == "/email/template-defaults" && r.Method == http.MethodGet:
+		h.GetEmailTemplateDefaults(w, r)
 	case path == "/apprise" && r.Method == http.MethodGet:
 		h.GetAppriseConfig(w, r)
 	case path == "/apprise" && r.Method == http.MethodPut:
 		h.UpdateAppriseConfig(w, r)
+	case path == "/syslog" && r.Method == http.MethodGet:
+		h.GetSyslogConfig(w, r)
+	case path == "/syslog" && r.Method == http.MethodPut:
+		h.UpdateSyslogConfig(w, r)
+	case path == "/syslog/test" && r.Method == http.MethodPost:
+		h.TestSyslog(w, r)
+	case path == "/snmp" && r.Method == http.MethodGet:
+		h.GetSNMPConfig(w, r)
+	case path == "/snmp" && r.Method == http.MethodPut:
+		h.UpdateSNMPConfig(w, r)
+	case path == "/snmp/test" && r.Method == http.MethodPost:
+		h.TestSNMP(w, r)
 	case path == "/webhooks" && r.Method == http.MethodGet:
 		h.GetWebhooks(w, r)
 	case path == "/webhooks" && r.Method == http.MethodPost:
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/notifications"
+)
+
+func TestMergeEmailConfigUpdateKeepsTemplatesAndPassword(t *testing.T) {
+	existing := notifications.EmailConfig{
+		Password: "stored",
+		Templates: notifications.EmailTemplates{
+			Single: notifications.EmailTemplateSet{Subject: "[{{.Level}}] {{.ResourceName}}"},
+		},
+	}
+
+	// The settings form sends neither the password nor the templates
+	config, err := mergeEmailConfigUpdate([]byte(`{"enabled":true,"server":"smtp.example.com","port":587}`), existing)
+	if err != nil {
+		t.Fatalf("mergeEmailConfigUpdate: %v", err)
+	}
+	if config.Password != "stored" || config.Templates != existing.Templates || config.SMTPHost != "smtp.example.com" {
+		t.Fatalf("unexpected merged config %+v", config)
+	}
+
+	config, err = mergeEmailConfigUpdate([]byte(`{"enabled":true,"templates":{}}`), existing)
+	if err != nil {
+		t.Fatalf("mergeEmailConfigUpdate: %v", err)
+	}
+	if !config.Templates.IsEmpty() {
+		t.Fatalf("expected explicitly cleared templates to be reset, got %+v", config.Templates)
+	}
+}
@@ -45,7 +45,7 @@ func NewOIDCService(ctx context.Context, cfg *config.OIDCConfig) (*OIDCService,
 	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	log.Debug().
+	log.Debug().Ctx(ctx).
 		Str("issuer", cfg.IssuerURL).
 		Str("redirect_url", cfg.RedirectURL).
 		Strs("scopes", cfg.Scopes).
@@ -56,7 +56,7 @@ func NewOIDCService(ctx context.Context, cfg *config.OIDCConfig) (*OIDCService,
 		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
 	}
 
-	log.Debug().
+	log.Debug().Ctx(ctx).
 		Str("issuer", cfg.IssuerURL).
 		Str("auth_endpoint", provider.Endpoint().AuthURL).
 		Str("token_endpoint", provider.Endpoint().TokenURL).
@@ -21,6 +21,7 @@ import (
 	"github.com/RouXx67/PulseUp/internal/auth"
 	"github.com/RouXx67/PulseUp/internal/config"
 	"github.com/RouXx67/PulseUp/internal/dockeragent"
+	"github.com/RouXx67/PulseUp/internal/ha"
 	"github.com/RouXx67/PulseUp/internal/models"
 	"github.com/RouXx67/PulseUp/internal/monitoring"
 	"github.com/RouXx67/PulseUp/internal/tempproxy"
@@ -38,7 +39,16 @@ type Router struct {
 	alertHandlers         *AlertHandlers
 	configHandlers        *ConfigHandlers
 	notificationHandlers  *NotificationHandlers
+	chatOpsHandlers       *ChatOpsHandlers
 	dockerAgentHandlers   *DockerAgentHandlers
+	guestActionHandlers   *GuestActionHandlers
+	retentionHandlers     *SnapshotRetentionHandlers
+	backupJobHandlers     *BackupJobHandlers
+	systemBackupHandlers  *InstanceBackupHandlers
+	federationHandlers    *FederationHandlers
+	onboardingHandlers    *DiscoveryOnboardingHandlers
+	haHandlers            *HAHandlers
+	tenantHandlers        *TenantScopeHandlers
 	systemSettingsHandler *SystemSettingsHandler
 	wsHub                 *websocket.Hub
 	reloadFunc            func() error
@@ -81,6 +91,9 @@ func NewRouter(cfg *config.Config, monitor *monitoring.Monitor, wsHub *websocket
 	}
 
 	r.setupRoutes()
+	if wsHub != nil {
+		wsHub.SetTenantScoping(r.websocketScope, r.filterWebSocketMessage)
+	}
 
 	// Start forwarding update progress to WebSocket
 	go r.forwardUpdateProgress()
@@ -114,7 +127,7 @@ func NewRouter(cfg *config.Config, monitor *monitoring.Monitor, wsHub *websocket
 // setupRoutes configures all routes
 func (r *Router) setupRoutes() {
 	// Create handlers
-	r.alertHandlers = NewAlertHandlers(r.monitor, r.wsHub)
+	r.alertHandlers = NewAlertHandlers(r.config, r.monitor, r.wsHub)
 	r.notificationHandlers = NewNotificationHandlers(r.monitor)
 	guestMetadataHandler := NewGuestMetadataHandler(r.config.DataPath)
 	r.configHandlers = NewConfigHandlers(r.config, r.monitor, r.reloadFunc, r.wsHub, guestMetadataHandler, r.reloadSystemSettings)
@@ -145,6 +158,10 @@ func (r *Router) setupRoutes() {
 	r.mux.HandleFunc("/api/backups/pve", r.handleBackupsPVE)
 	r.mux.HandleFunc("/api/backups/pbs", r.handleBackupsPBS)
 	r.mux.HandleFunc("/api/snapshots", r.handleSnapshots)
+	r.mux.HandleFunc("/api/patch-status", r.handlePatchStatus)
+	r.mux.HandleFunc("/api/tasks", r.handleTasks)
+	r.mux.HandleFunc("/api/forecast", r.handleForecast)
+	r.mux.HandleFunc("/api/recommendations", r.handleRecommendations)
 
 	// Guest metadata routes
 	r.mux.HandleFunc("/api/guests/metadata", guestMetadataHandler.HandleGetMetadata)
@@ -161,6 +178,39 @@ func (r *Router) setupRoutes() {
 		}
 	})
 
+	// Guest power and lifecycle actions
+	r.guestActionHandlers = NewGuestActionHandlers(r.config, r.monitor, r.wsHub)
+	r.mux.HandleFunc("/api/guests/", RequireAdmin(r.config, r.guestActionHandlers.HandleGuestActions))
+	r.retentionHandlers = NewSnapshotRetentionHandlers(r.monitor, r.persistence)
+	r.mux.HandleFunc("/api/snapshots/retention", RequireAdmin(r.config, r.retentionHandlers.HandleSnapshotRetention))
+	r.mux.HandleFunc("/api/snapshots/retention/", RequireAdmin(r.config, r.retentionHandlers.HandleSnapshotRetention))
+	r.backupJobHandlers = NewBackupJobHandlers(r.config, r.monitor, r.persistence)
+	r.mux.HandleFunc("/api/backups/jobs", RequireAdmin(r.config, r.backupJobHandlers.HandleBackupJobs))
+	r.mux.HandleFunc("/api/backups/remediation", RequireAdmin(r.config, r.backupJobHandlers.HandleBackupRemediation))
+	r.systemBackupHandlers = NewInstanceBackupHandlers(r.config, r.monitor, r.persistence)
+	r.mux.HandleFunc("/api/system/backup", r.exportLimiter.Middleware(RequireAdmin(r.config, r.systemBackupHandlers.HandleCreate)))
+	r.mux.HandleFunc("/api/system/backup/restore", r.exportLimiter.Middleware(RequireAdmin(r.config, r.systemBackupHandlers.HandleRestore)))
+	r.mux.HandleFunc("/api/system/backup/run", RequireAdmin(r.config, r.systemBackupHandlers.HandleRun))
+	r.mux.HandleFunc("/api/system/backup/schedule", RequireAdmin(r.config, r.systemBackupHandlers.HandleSchedule))
+
+	// Federation: downstream export and central aggregation
+	r.federationHandlers = NewFederationHandlers(r.config, r.monitor, r.persistence)
+	r.mux.HandleFunc("/api/federation/snapshot", r.federationHandlers.HandleSnapshot)
+	r.mux.HandleFunc("/api/federation/stream", r.federationHandlers.HandleStream)
+	r.mux.HandleFunc("/api/federation/ack", r.federationHandlers.HandleAck)
+	r.mux.HandleFunc("/api/federation/sites", RequireAdmin(r.config, r.federationHandlers.HandleSites))
+	r.mux.HandleFunc("/api/federation/status", r.federationHandlers.HandleStatus)
+	r.mux.HandleFunc("/api/federation/state", r.federationHandlers.HandleState)
+	r.mux.HandleFunc("/api/federation/alerts/ack", r.federationHandlers.HandleAlertAck)
+
+	// High availability peer endpoints, authenticated with the shared HA secret
+	r.haHandlers = NewHAHandlers(r.monitor)
+	r.mux.HandleFunc("/api/ha/lease", r.haHandlers.HandleLease)
+	r.mux.HandleFunc("/api/ha/replicate", r.haHandlers.HandleReplicate)
+	r.tenantHandlers = NewTenantScopeHandlers(r.config, r.monitor, r.persistence)
+	r.mux.HandleFunc("/api/tenant-scopes", RequireAdmin(r.config, r.tenantHandlers.HandleTenantScopes))
+	r.mux.HandleFunc("/api/tenant-scopes/me", r.tenantHandlers.HandleCurrentScope)
+
 	// Update routes
 	r.mux.HandleFunc("/api/updates/check", updateHandlers.HandleCheckUpdates)
 	r.mux.HandleFunc("/api/updates/apply", updateHandlers.HandleApplyUpdate)
@@ -816,6 +866,11 @@ func (r *Router) setupRoutes() {
 	// Discovery endpoint
 	r.mux.HandleFunc("/api/discover", RequireAuth(r.config, r.configHandlers.HandleDiscoverServers))
 
+	// Discovery onboarding: credential profiles, scheduled sweeps and the approval queue
+	r.onboardingHandlers = NewDiscoveryOnboardingHandlers(r.config, r.monitor, r.persistence, r.configHandlers)
+	r.mux.HandleFunc("/api/discovery/onboarding", RequireAdmin(r.config, r.onboardingHandlers.HandleOnboarding))
+	r.mux.HandleFunc("/api/discovery/onboarding/", RequireAdmin(r.config, r.onboardingHandlers.HandleOnboarding))
+
 	// Test endpoint for WebSocket notifications
 	r.mux.HandleFunc("/api/test-notification", func(w http.ResponseWriter, req *http.Request) {
 		if req.Method != http.MethodPost {
@@ -846,6 +901,10 @@ func (r *Router) setupRoutes() {
 	// Notification routes
 	r.mux.HandleFunc("/api/notifications/", r.notificationHandlers.HandleNotifications)
 
+	// Inbound chat command routes (platform endpoints verify their own signatures)
+	r.chatOpsHandlers = NewChatOpsHandlers(r.monitor, r.wsHub, r.persistence)
+	r.mux.HandleFunc("/api/chatops/", r.chatOpsHandlers.HandleChatOps)
+
 	// Settings routes
 	r.mux.HandleFunc("/api/settings", getSettings)
 	r.mux.HandleFunc("/api/settings/update", updateSettings)
@@ -1043,6 +1102,30 @@ func (r *Router) SetMonitor(m *monitoring.Monitor) {
 	if r.systemSettingsHandler != nil {
 		r.systemSettingsHandler.SetMonitor(m)
 	}
+	if r.guestActionHandlers != nil {
+		r.guestActionHandlers.SetMonitor(m)
+	}
+	if r.retentionHandlers != nil {
+		r.retentionHandlers.SetMonitor(m)
+	}
+	if r.backupJobHandlers != nil {
+		r.backupJobHandlers.SetMonitor(m)
+	}
+	if r.systemBackupHandlers != nil {
+		r.systemBackupHandlers.SetMonitor(m)
+	}
+	if r.federationHandlers != nil {
+		r.federationHandlers.SetMonitor(m)
+	}
+	if r.onboardingHandlers != nil {
+		r.onboardingHandlers.SetMonitor(m)
+	}
+	if r.haHandlers != nil {
+		r.haHandlers.SetMonitor(m)
+	}
+	if r.tenantHandlers != nil {
+		r.tenantHandlers.SetMonitor(m)
+	}
 	if m != nil {
 		if url := strings.TrimSpace(r.config.PublicURL); url != "" {
 			if mgr := m.GetNotificationManager(); mgr != nil {
@@ -1052,6 +1135,11 @@ func (r *Router) SetMonitor(m *monitoring.Monitor) {
 	}
 }
 
+// SetHA enables the high availability peer endpoints and reports the node's role in /api/health
+func (r *Router) SetHA(node *ha.Node) {
+	r.haHandlers.SetNode(node)
+}
+
 // SetConfig refreshes the configuration reference used by the router and dependent handlers.
 func (r *Router) SetConfig(cfg *config.Config) {
 	if cfg == nil {
@@ -1202,6 +1290,11 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				"/api/install/pulse-sensor-proxy",      // Temperature proxy binary fallback
 				"/api/install/install-docker.sh",       // Docker turnkey installer
 				"/api/system/proxy-public-key",         // Temperature proxy public key for setup script
+				"/api/chatops/slack",                   // Verified with the Slack signing secret
+				"/api/chatops/discord",                 // Verified with the Discord application public key
+				"/api/chatops/telegram",                // Verified with the Telegram webhook secret token
+				"/api/ha/lease",                        // Verified with the HA shared secret
+				"/api/ha/replicate",                    // Verified with the HA shared secret
 			}
 
 			// Also allow static assets without auth (JS, CSS, etc)
@@ -1288,6 +1381,19 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				return
 			}
 		}
+		// Tenant scoped users are limited to read endpoints that filter by scope
+		if !tenantScopeAllowsRequest(r.config, req) {
+			log.Warn().
+				Str("ip", req.RemoteAddr).
+				Str("path", req.URL.Path).
+				Str("method", req.Method).
+				Msg("Tenant scoped user attempted to access unscoped endpoint")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Not available for tenant scoped users"}`))
+			return
+		}
+
 		// Check CSRF for state-changing requests
 		// CSRF is only needed when using session-based auth
 		// Only skip CSRF for initial setup when no auth is configured
@@ -1624,6 +1730,7 @@ func (r *Router) handleHealth(w http.ResponseWriter, req *http.Request) {
 		RecommendProxyUpgrade:       recommendProxy,
 		ProxyInstallScriptAvailable: true, // Install script is always available
 		DevModeSSH:                  devModeSSH,
+		HA:                          r.haHandlers.Status(),
 	}
 
 	if err := utils.WriteJSONResponse(w, response); err != nil {
@@ -2234,7 +2341,7 @@ func (r *Router) handleState(w http.ResponseWriter, req *http.Request) {
 	log.Debug().Msg("[DEBUG] handleState: Before GetState")
 	state := r.monitor.GetState()
 	log.Debug().Msg("[DEBUG] handleState: After GetState, before ToFrontend")
-	frontendState := state.ToFrontend()
+	frontendState := requestTenantScope(r.config, req).FilterFrontend(state.ToFrontend())
 
 	log.Debug().Msg("[DEBUG] handleState: Before WriteJSONResponse")
 	if err := utils.WriteJSONResponse(w, frontendState); err != nil {
@@ -2715,6 +2822,17 @@ func (r *Router) handleBackups(w http.ResponseWriter, req *http.Request) {
 
 	// Get current state
 	state := r.monitor.GetState()
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		guests := scope.Guests(state)
+		state.PVEBackups = scope.FilterPVEBackups(state.PVEBackups, guests)
+		state.PBSBackups = scope.FilterPBSBackups(state.PBSBackups, guests)
+		state.PMGBackups = scope.FilterPMGBackups(state.PMGBackups)
+		state.Backups = models.Backups{
+			PVE: scope.FilterPVEBackups(state.Backups.PVE, guests),
+			PBS: scope.FilterPBSBackups(state.Backups.PBS, guests),
+			PMG: scope.FilterPMGBackups(state.Backups.PMG),
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(struct {
@@ -2745,6 +2863,9 @@ func (r *Router) handleBackupsPVE(w http.ResponseWriter, req *http.Request) {
 
 	// Get state and extract PVE backups
 	state := r.monitor.GetState()
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		state.PVEBackups = scope.FilterPVEBackups(state.PVEBackups, scope.Guests(state))
+	}
 
 	// Return PVE backup data in expected format
 	backups := state.PVEBackups.StorageBackups
@@ -2779,6 +2900,15 @@ func (r *Router) handleBackupsPBS(w http.ResponseWriter, req *http.Request) {
 	if instances == nil {
 		instances = []models.PBSInstance{}
 	}
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		visible := []models.PBSInstance{}
+		for _, instance := range instances {
+			if scope.AllowsInstance(instance.Name) {
+				visible = append(visible, instance)
+			}
+		}
+		instances = visible
+	}
 
 	pbsData := map[string]interface{}{
 		"instances": instances,
@@ -2801,6 +2931,9 @@ func (r *Router) handleSnapshots(w http.ResponseWriter, req *http.Request) {
 
 	// Get state and extract guest snapshots
 	state := r.monitor.GetState()
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		state.PVEBackups = scope.FilterPVEBackups(state.PVEBackups, scope.Guests(state))
+	}
 
 	// Return snapshot data
 	snaps := state.PVEBackups.GuestSnapshots
@@ -2820,6 +2953,211 @@ func (r *Router) handleSnapshots(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// handlePatchStatus returns pending updates, reboot, subscription and certificate status across all nodes
+func (r *Router) handlePatchStatus(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := req.URL.Query()
+	platform := strings.ToLower(strings.TrimSpace(query.Get("platform")))
+	switch platform {
+	case "", "pve", "pbs", "pmg":
+	default:
+		http.Error(w, "platform must be pve, pbs or pmg", http.StatusBadRequest)
+		return
+	}
+
+	status := r.monitor.GetPatchStatus(platform, strings.TrimSpace(query.Get("instance")))
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		var entries []models.NodeMaintenance
+		for _, entry := range status.Entries {
+			if scope.AllowsInstance(entry.Instance) {
+				entries = append(entries, entry)
+			}
+		}
+		status = models.SummarizePatchStatus(entries, r.monitor.GetAlertManager().GetConfig().MaintenanceDefaults.CertificateWarningDays)
+	}
+	if err := utils.WriteJSONResponse(w, status); err != nil {
+		log.Error().Err(err).Msg("Failed to write patch status response")
+	}
+}
+
+// handleTasks returns the collected PVE task history, newest first
+func (r *Router) handleTasks(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := req.URL.Query()
+	filter := monitoring.TaskFilter{
+		Instance: strings.TrimSpace(query.Get("instance")),
+		Node:     strings.TrimSpace(query.Get("node")),
+		Type:     strings.TrimSpace(query.Get("type")),
+		State:    strings.ToLower(strings.TrimSpace(query.Get("status"))),
+		User:     strings.TrimSpace(query.Get("user")),
+		Limit:    200,
+	}
+	switch filter.State {
+	case "", models.TaskStateRunning, models.TaskStateOK, models.TaskStateWarning, models.TaskStateFailed:
+	default:
+		http.Error(w, "status must be running, ok, warning or failed", http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("vmid"); value != "" {
+		vmid, err := strconv.Atoi(value)
+		if err != nil || vmid <= 0 {
+			http.Error(w, "Invalid vmid", http.StatusBadRequest)
+			return
+		}
+		filter.VMID = vmid
+	}
+	if value := query.Get("since"); value != "" {
+		since, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || since < 0 {
+			http.Error(w, "since must be a Unix timestamp", http.StatusBadRequest)
+			return
+		}
+		filter.Since = time.Unix(since, 0)
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	var tasks []models.ClusterTask
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		// Apply the scope before the limit so scoped users get a full page
+		limit := filter.Limit
+		filter.Limit = 0
+		guests := scope.Guests(r.monitor.GetState())
+		tasks = []models.ClusterTask{}
+		for _, task := range r.monitor.GetTasks(filter) {
+			if scope.AllowsTask(task, guests) {
+				tasks = append(tasks, task)
+			}
+		}
+		if len(tasks) > limit {
+			tasks = tasks[:limit]
+		}
+	} else {
+		tasks = r.monitor.GetTasks(filter)
+	}
+	if err := utils.WriteJSONResponse(w, map[string]interface{}{"tasks": tasks}); err != nil {
+		log.Error().Err(err).Msg("Failed to write tasks response")
+	}
+}
+
+// handleForecast returns capacity forecasts, soonest projected exhaustion first
+func (r *Router) handleForecast(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := req.URL.Query()
+	filter := monitoring.ForecastFilter{
+		Kind:     strings.TrimSpace(query.Get("kind")),
+		Instance: strings.TrimSpace(query.Get("instance")),
+	}
+	switch filter.Kind {
+	case "", models.ForecastNodeMemory, models.ForecastStorage, models.ForecastPBSDatastore, models.ForecastCephPool:
+	default:
+		http.Error(w, "kind must be node-memory, storage, pbs-datastore or ceph-pool", http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days <= 0 {
+			http.Error(w, "Invalid days", http.StatusBadRequest)
+			return
+		}
+		filter.WithinDays = days
+	}
+
+	forecasts := r.monitor.GetCapacityForecasts(filter)
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		visible := []models.CapacityForecast{}
+		for _, forecast := range forecasts {
+			if scope.AllowsInstance(forecast.Instance) {
+				visible = append(visible, forecast)
+			}
+		}
+		forecasts = visible
+	}
+	if err := utils.WriteJSONResponse(w, map[string]interface{}{"forecasts": forecasts}); err != nil {
+		log.Error().Err(err).Msg("Failed to write forecast response")
+	}
+}
+
+// handleRecommendations returns guest rightsizing recommendations with reclaimable resources per node
+func (r *Router) handleRecommendations(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := req.URL.Query()
+	filter := monitoring.RightsizingFilter{
+		Days:     monitoring.DefaultRightsizingDays,
+		Kind:     strings.TrimSpace(query.Get("kind")),
+		Instance: strings.TrimSpace(query.Get("instance")),
+		Node:     strings.TrimSpace(query.Get("node")),
+	}
+	switch filter.Kind {
+	case "", models.RightsizingOversized, models.RightsizingUndersized, models.RightsizingIdle:
+	default:
+		http.Error(w, "kind must be oversized, undersized or idle", http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 7 || days > 30 {
+			http.Error(w, "days must be between 7 and 30", http.StatusBadRequest)
+			return
+		}
+		filter.Days = days
+	}
+
+	report := r.monitor.GetRightsizingReport(filter)
+	if scope := requestTenantScope(r.config, req); scope != nil {
+		guests := scope.Guests(r.monitor.GetState())
+		visible := []models.GuestRecommendation{}
+		for _, recommendation := range report.Recommendations {
+			if scope.AllowsAlert(recommendation.Instance, recommendation.GuestID, guests) {
+				visible = append(visible, recommendation)
+			}
+		}
+		report.Recommendations = visible
+		report.Nodes = monitoring.SummarizeRightsizing(visible)
+	}
+	if err := utils.WriteJSONResponse(w, report); err != nil {
+		log.Error().Err(err).Msg("Failed to write recommendations response")
+	}
+}
+
 // handleWebSocket handles WebSocket connections
 func (r *Router) handleWebSocket(w http.ResponseWriter, req *http.Request) {
 	r.wsHub.HandleWebSocket(w, req)
@@ -3081,6 +3419,11 @@ func (r *Router) handleSocketIO(w http.ResponseWriter, req *http.Request) {
 	http.Redirect(w, req, "/ws", http.StatusFound)
 }
 
+// StartUpdateTasks applies the automatic update policy until ctx is cancelled
+func (r *Router) StartUpdateTasks(ctx context.Context) {
+	go r.updateManager.RunAutoUpdates(ctx)
+}
+
 // forwardUpdateProgress forwards update progress to WebSocket clients
 func (r *Router) forwardUpdateProgress() {
 	progressChan := r.updateManager.GetProgressChannel()
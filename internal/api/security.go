@@ -64,7 +64,7 @@ func CheckCSRF(w http.ResponseWriter, r *http.Request) bool {
 	// This handles the case where the server restarted and lost CSRF tokens
 	if csrfToken == "" {
 		// No CSRF token provided - this is definitely invalid
-		log.Warn().
+		log.Warn().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Str("session", cookie.Value[:8]+"...").
 			Msg("Missing CSRF token")
@@ -103,14 +103,14 @@ func CheckCSRF(w http.ResponseWriter, r *http.Request) bool {
 				MaxAge:   86400, // 24 hours
 			})
 			// For this request, we'll be lenient and allow it through
-			log.Debug().
+			log.Debug().Ctx(r.Context()).
 				Str("path", r.URL.Path).
 				Str("session", cookie.Value[:8]+"...").
 				Msg("Regenerated CSRF token after server restart")
 			return true
 		}
 
-		log.Warn().
+		log.Warn().Ctx(r.Context()).
 			Str("path", r.URL.Path).
 			Str("session", cookie.Value[:8]+"...").
 			Str("provided_token", csrfToken[:8]+"...").
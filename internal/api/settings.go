@@ -70,7 +70,7 @@ func getSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := utils.WriteJSONResponse(w, response); err != nil {
-		log.Error().Err(err).Msg("Failed to write settings response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write settings response")
 	}
 }
 
@@ -89,7 +89,7 @@ func updateSettings(w http.ResponseWriter, r *http.Request) {
 
 	// Validate settings
 	if err := update.Settings.Validate(); err != nil {
-		log.Error().Err(err).Msg("Settings validation failed")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Settings validation failed")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -112,14 +112,14 @@ func updateSettings(w http.ResponseWriter, r *http.Request) {
 			"valid":   true,
 			"message": "Configuration is valid",
 		}); err != nil {
-			log.Error().Err(err).Msg("Failed to write validation response")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write validation response")
 		}
 		return
 	}
 
 	// Save configuration to file
 	if err := saveSettings(update.Settings); err != nil {
-		log.Error().Err(err).Msg("Failed to save settings")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save settings")
 		http.Error(w, "Failed to save settings", http.StatusInternalServerError)
 		return
 	}
@@ -135,18 +135,18 @@ func updateSettings(w http.ResponseWriter, r *http.Request) {
 	if update.RestartNow {
 		// Schedule a graceful restart after response is sent
 		go func() {
-			log.Info().Msg("Scheduling graceful restart due to configuration change")
+			log.Info().Ctx(r.Context()).Msg("Scheduling graceful restart due to configuration change")
 			// Use a more graceful shutdown mechanism
 			// The systemd service will handle the restart
 			time.Sleep(1 * time.Second) // Give time for response to be sent
-			log.Info().Msg("Initiating graceful shutdown")
+			log.Info().Ctx(r.Context()).Msg("Initiating graceful shutdown")
 			os.Exit(0)
 		}()
 		response["restarting"] = true
 	}
 
 	if err := utils.WriteJSONResponse(w, response); err != nil {
-		log.Error().Err(err).Msg("Failed to write settings update response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write settings update response")
 	}
 }
 
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// snapshotRetentionRunTimeout bounds a manual retention run
+const snapshotRetentionRunTimeout = 30 * time.Minute
+
+// SnapshotRetentionHandlers manages snapshot retention policies and runs
+type SnapshotRetentionHandlers struct {
+	monitor     *monitoring.Monitor
+	persistence *config.ConfigPersistence
+}
+
+// NewSnapshotRetentionHandlers creates snapshot retention handlers
+func NewSnapshotRetentionHandlers(m *monitoring.Monitor, persistence *config.ConfigPersistence) *SnapshotRetentionHandlers {
+	return &SnapshotRetentionHandlers{monitor: m, persistence: persistence}
+}
+
+// SetMonitor updates the monitor reference for snapshot retention handlers.
+func (h *SnapshotRetentionHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+}
+
+// HandleSnapshotRetention routes /api/snapshots/retention requests
+func (h *SnapshotRetentionHandlers) HandleSnapshotRetention(w http.ResponseWriter, r *http.Request) {
+	if h.monitor == nil {
+		http.Error(w, "Monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/snapshots/retention")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		h.GetConfig(w, r)
+	case path == "" && r.Method == http.MethodPut:
+		h.UpdateConfig(w, r)
+	case path == "/preview" && r.Method == http.MethodPost:
+		h.Preview(w, r)
+	case path == "/run" && r.Method == http.MethodPost:
+		h.Run(w, r)
+	case path == "/history" && r.Method == http.MethodGet:
+		h.GetHistory(w, r)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// GetConfig returns the retention configuration
+func (h *SnapshotRetentionHandlers) GetConfig(w http.ResponseWriter, r *http.Request) {
+	if err := utils.WriteJSONResponse(w, h.monitor.GetSnapshotRetentionConfig()); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write snapshot retention configuration response")
+	}
+}
+
+// UpdateConfig validates, saves and applies the retention configuration
+func (h *SnapshotRetentionHandlers) UpdateConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, ok := decodeSnapshotRetentionConfig(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.persistence.SaveSnapshotRetentionConfig(cfg); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save snapshot retention configuration")
+		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+		return
+	}
+	h.monitor.SetSnapshotRetentionConfig(cfg)
+
+	LogAuditEvent("snapshot_retention_config", "", GetClientIP(r), r.URL.Path, true,
+		fmt.Sprintf("enabled=%t dryRun=%t policies=%d", cfg.Enabled, cfg.DryRun, len(cfg.Policies)))
+
+	if err := utils.WriteJSONResponse(w, h.monitor.GetSnapshotRetentionConfig()); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write snapshot retention configuration response")
+	}
+}
+
+// Preview shows what the posted configuration (or the saved one, if the body is empty)
+// would delete right now
+func (h *SnapshotRetentionHandlers) Preview(w http.ResponseWriter, r *http.Request) {
+	cfg := h.monitor.GetSnapshotRetentionConfig()
+	if r.ContentLength != 0 {
+		decoded, ok := decodeSnapshotRetentionConfig(w, r)
+		if !ok {
+			return
+		}
+		cfg = decoded
+	}
+
+	if err := utils.WriteJSONResponse(w, h.monitor.PreviewSnapshotRetention(cfg)); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write snapshot retention preview response")
+	}
+}
+
+// Run enforces the saved policies immediately. Dry-run mode is respected.
+func (h *SnapshotRetentionHandlers) Run(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotRetentionRunTimeout)
+	defer cancel()
+
+	run := h.monitor.EnforceSnapshotRetention(ctx, monitoring.SnapshotRetentionTriggerManual)
+	LogAuditEvent("snapshot_retention_run", "", GetClientIP(r), r.URL.Path, run.Failed == 0,
+		fmt.Sprintf("dryRun=%t planned=%d deleted=%d failed=%d", run.DryRun, run.Planned, run.Deleted, run.Failed))
+
+	if err := utils.WriteJSONResponse(w, run); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write snapshot retention run response")
+	}
+}
+
+// GetHistory returns recorded retention runs, newest first
+func (h *SnapshotRetentionHandlers) GetHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := h.monitor.GetSnapshotRetentionHistory()
+	if err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to read snapshot retention history")
+		http.Error(w, "Failed to read history", http.StatusInternalServerError)
+		return
+	}
+	if err := utils.WriteJSONResponse(w, history); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write snapshot retention history response")
+	}
+}
+
+func decodeSnapshotRetentionConfig(w http.ResponseWriter, r *http.Request) (config.SnapshotRetentionConfig, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, 256*1024)
+	var cfg config.SnapshotRetentionConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return cfg, false
+	}
+
+	cfg = config.NormalizeSnapshotRetentionConfig(cfg)
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return cfg, false
+	}
+	return cfg, true
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/RouXx67/PulseUp/internal/config"
 	"github.com/RouXx67/PulseUp/internal/discovery"
+	"github.com/RouXx67/PulseUp/internal/updates"
 	"github.com/RouXx67/PulseUp/internal/utils"
 	"github.com/RouXx67/PulseUp/internal/websocket"
 	"github.com/rs/zerolog/log"
@@ -181,6 +182,48 @@ func validateSystemSettings(settings *config.SystemSettings, rawRequest map[stri
 		}
 	}
 
+	if val, ok := rawRequest["autoUpdateRollback"]; ok {
+		if _, ok := val.(bool); !ok {
+			return fmt.Errorf("autoUpdateRollback must be a boolean")
+		}
+	}
+
+	// Validate the automatic update window ("HH:MM", local time)
+	for _, key := range []string{"autoUpdateTime", "autoUpdateWindowEnd"} {
+		if val, ok := rawRequest[key]; ok {
+			str, ok := val.(string)
+			if !ok {
+				return fmt.Errorf("%s must be a string", key)
+			}
+			if str != "" {
+				if _, err := updates.ParseWindowTime(str); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+			}
+		}
+	}
+
+	// Validate staged rollout period (max 7 days)
+	if val, ok := rawRequest["autoUpdateRolloutHours"]; ok {
+		if hours, ok := val.(float64); ok {
+			if hours < 0 || hours > 168 {
+				return fmt.Errorf("auto-update rollout period must be between 0 and 168 hours")
+			}
+		} else {
+			return fmt.Errorf("auto-update rollout period must be a number")
+		}
+	}
+
+	if val, ok := rawRequest["updateSource"]; ok {
+		source, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("updateSource must be a string")
+		}
+		if err := updates.ValidateUpdateSource(source); err != nil {
+			return err
+		}
+	}
+
 	// Validate auto-update check interval (min 1 hour, max 7 days)
 	if val, ok := rawRequest["autoUpdateCheckInterval"]; ok {
 		if interval, ok := val.(float64); ok {
@@ -349,7 +392,7 @@ func (h *SystemSettingsHandler) HandleGetSystemSettings(w http.ResponseWriter, r
 
 	settings, err := h.persistence.LoadSystemSettings()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to load system settings")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to load system settings")
 		settings = config.DefaultSystemSettings()
 	}
 	if settings == nil {
@@ -358,7 +401,7 @@ func (h *SystemSettingsHandler) HandleGetSystemSettings(w http.ResponseWriter, r
 
 	// Log loaded settings for debugging
 	if settings != nil {
-		log.Debug().
+		log.Debug().Ctx(r.Context()).
 			Str("theme", settings.Theme).
 			Msg("Loaded system settings for API response")
 
@@ -379,7 +422,7 @@ func (h *SystemSettingsHandler) HandleGetSystemSettings(w http.ResponseWriter, r
 	}
 
 	if err := utils.WriteJSONResponse(w, response); err != nil {
-		log.Error().Err(err).Msg("Failed to write system settings response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write system settings response")
 	}
 }
 
@@ -400,7 +443,7 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 		if valid, username, isAdmin := CheckProxyAuth(h.config, r); valid {
 			if !isAdmin {
 				// User is authenticated but not an admin
-				log.Warn().
+				log.Warn().Ctx(r.Context()).
 					Str("ip", r.RemoteAddr).
 					Str("path", r.URL.Path).
 					Str("method", r.Method).
@@ -417,7 +460,7 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 	// Load existing settings first to preserve fields not in the request
 	existingSettings, err := h.persistence.LoadSystemSettings()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to load existing settings")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to load existing settings")
 		existingSettings = config.DefaultSystemSettings()
 	}
 	if existingSettings == nil {
@@ -488,6 +531,16 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 	if updates.AutoUpdateTime != "" {
 		settings.AutoUpdateTime = updates.AutoUpdateTime
 	}
+	if updates.AutoUpdateWindowEnd != "" {
+		settings.AutoUpdateWindowEnd = updates.AutoUpdateWindowEnd
+	}
+	if _, ok := rawRequest["autoUpdateRolloutHours"]; ok {
+		settings.AutoUpdateRolloutHours = updates.AutoUpdateRolloutHours
+	}
+	// Allow clearing the update source to return to GitHub
+	if _, ok := rawRequest["updateSource"]; ok {
+		settings.UpdateSource = strings.TrimSpace(updates.UpdateSource)
+	}
 	if updates.Theme != "" {
 		settings.Theme = updates.Theme
 	}
@@ -546,6 +599,9 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 	if _, ok := rawRequest["backupPollingEnabled"]; ok {
 		settings.BackupPollingEnabled = updates.BackupPollingEnabled
 	}
+	if _, ok := rawRequest["autoUpdateRollback"]; ok {
+		settings.AutoUpdateRollback = updates.AutoUpdateRollback
+	}
 
 	// Update the config
 	// Note: PVE polling is hardcoded to 10s
@@ -580,6 +636,18 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 	if settings.AutoUpdateTime != "" {
 		h.config.AutoUpdateTime = settings.AutoUpdateTime
 	}
+	if settings.AutoUpdateWindowEnd != "" {
+		h.config.AutoUpdateWindowEnd = settings.AutoUpdateWindowEnd
+	}
+	if settings.AutoUpdateRollback != nil {
+		h.config.AutoUpdateRollback = *settings.AutoUpdateRollback
+	}
+	if settings.AutoUpdateRolloutHours != nil {
+		h.config.AutoUpdateRolloutHours = *settings.AutoUpdateRolloutHours
+	}
+	if !h.config.EnvOverrides["updateSource"] {
+		h.config.UpdateSource = settings.UpdateSource
+	}
 
 	// Validate theme if provided
 	if settings.Theme != "" && settings.Theme != "light" && settings.Theme != "dark" {
@@ -604,11 +672,11 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 				subnet = "auto"
 			}
 			h.monitor.StartDiscoveryService(context.Background(), h.wsHub, subnet)
-			log.Info().Msg("Discovery service started via settings update")
+			log.Info().Ctx(r.Context()).Msg("Discovery service started via settings update")
 		} else if !settings.DiscoveryEnabled && prevDiscoveryEnabled {
 			// Discovery was just disabled, stop the service
 			h.monitor.StopDiscoveryService()
-			log.Info().Msg("Discovery service stopped via settings update")
+			log.Info().Ctx(r.Context()).Msg("Discovery service stopped via settings update")
 		} else if settings.DiscoveryEnabled && settings.DiscoverySubnet != "" {
 			// Subnet changed while discovery is enabled, update it
 			if svc := h.monitor.GetDiscoveryService(); svc != nil {
@@ -617,7 +685,7 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 		}
 		if discoveryConfigUpdated && settings.DiscoveryEnabled {
 			if svc := h.monitor.GetDiscoveryService(); svc != nil {
-				log.Info().Msg("Discovery configuration changed; triggering refresh")
+				log.Info().Ctx(r.Context()).Msg("Discovery configuration changed; triggering refresh")
 				svc.ForceRefresh()
 			}
 		}
@@ -625,7 +693,7 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 
 	// Save to persistence
 	if err := h.persistence.SaveSystemSettings(settings); err != nil {
-		log.Error().Err(err).Msg("Failed to save system settings")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save system settings")
 		http.Error(w, "Failed to save settings", http.StatusInternalServerError)
 		return
 	}
@@ -635,7 +703,7 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 		h.reloadSystemSettingsFunc()
 	}
 
-	log.Info().Msg("System settings updated")
+	log.Info().Ctx(r.Context()).Msg("System settings updated")
 
 	// Broadcast theme change to all connected clients if theme was updated
 	if settings.Theme != "" && h.wsHub != nil {
@@ -645,11 +713,11 @@ func (h *SystemSettingsHandler) HandleUpdateSystemSettings(w http.ResponseWriter
 				"theme": settings.Theme,
 			},
 		})
-		log.Debug().Str("theme", settings.Theme).Msg("Broadcasting theme change to WebSocket clients")
+		log.Debug().Ctx(r.Context()).Str("theme", settings.Theme).Msg("Broadcasting theme change to WebSocket clients")
 	}
 
 	if err := utils.WriteJSONResponse(w, map[string]bool{"success": true}); err != nil {
-		log.Error().Err(err).Msg("Failed to write system settings update response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write system settings update response")
 	}
 }
 
@@ -670,11 +738,11 @@ func (h *SystemSettingsHandler) HandleSSHConfig(w http.ResponseWriter, r *http.R
 		// Check if body was too large
 		var maxBytesErr *http.MaxBytesError
 		if errors.As(err, &maxBytesErr) {
-			log.Warn().Msg("SSH config request body too large")
+			log.Warn().Ctx(r.Context()).Msg("SSH config request body too large")
 			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
 			return
 		}
-		log.Error().Err(err).Msg("Failed to read SSH config from request")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to read SSH config from request")
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
@@ -682,7 +750,7 @@ func (h *SystemSettingsHandler) HandleSSHConfig(w http.ResponseWriter, r *http.R
 	// Basic validation: ensure it looks like SSH config
 	configStr := string(sshConfig)
 	if len(configStr) == 0 {
-		log.Error().Msg("Empty SSH config received")
+		log.Error().Ctx(r.Context()).Msg("Empty SSH config received")
 		http.Error(w, "Empty SSH config", http.StatusBadRequest)
 		return
 	}
@@ -724,7 +792,7 @@ func (h *SystemSettingsHandler) HandleSSHConfig(w http.ResponseWriter, r *http.R
 
 		directive := strings.ToLower(fields[0])
 		if !allowedDirectives[directive] {
-			log.Warn().
+			log.Warn().Ctx(r.Context()).
 				Str("directive", fields[0]).
 				Int("line", lineNum).
 				Msg("Rejected SSH config with forbidden directive")
@@ -734,7 +802,7 @@ func (h *SystemSettingsHandler) HandleSSHConfig(w http.ResponseWriter, r *http.R
 	}
 
 	if err := scanner.Err(); err != nil {
-		log.Error().Err(err).Msg("Failed to parse SSH config")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to parse SSH config")
 		http.Error(w, "Invalid SSH config format", http.StatusBadRequest)
 		return
 	}
@@ -748,7 +816,7 @@ func (h *SystemSettingsHandler) HandleSSHConfig(w http.ResponseWriter, r *http.R
 	// Create .ssh directory if it doesn't exist
 	sshDir := filepath.Join(homeDir, ".ssh")
 	if err := os.MkdirAll(sshDir, 0700); err != nil {
-		log.Error().Err(err).Str("dir", sshDir).Msg("Failed to create .ssh directory")
+		log.Error().Ctx(r.Context()).Err(err).Str("dir", sshDir).Msg("Failed to create .ssh directory")
 		http.Error(w, "Failed to create SSH directory", http.StatusInternalServerError)
 		return
 	}
@@ -756,15 +824,15 @@ func (h *SystemSettingsHandler) HandleSSHConfig(w http.ResponseWriter, r *http.R
 	// Write SSH config file
 	configPath := filepath.Join(sshDir, "config")
 	if err := os.WriteFile(configPath, sshConfig, 0600); err != nil {
-		log.Error().Err(err).Str("path", configPath).Msg("Failed to write SSH config")
+		log.Error().Ctx(r.Context()).Err(err).Str("path", configPath).Msg("Failed to write SSH config")
 		http.Error(w, "Failed to write SSH config", http.StatusInternalServerError)
 		return
 	}
 
-	log.Info().Str("path", configPath).Int("size", len(sshConfig)).Msg("SSH config written successfully")
+	log.Info().Ctx(r.Context()).Str("path", configPath).Int("size", len(sshConfig)).Msg("SSH config written successfully")
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]bool{"success": true}); err != nil {
-		log.Error().Err(err).Msg("Failed to encode success response")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode success response")
 	}
 }
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/internal/monitoring"
+	"github.com/RouXx67/PulseUp/internal/tenancy"
+	"github.com/RouXx67/PulseUp/internal/utils"
+	"github.com/RouXx67/PulseUp/internal/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// requestTenantScope returns the tenant scope restricting a request, or nil when the caller
+// is bound to no scope. Proxy users and roles, OIDC session users and API tokens can be bound.
+func requestTenantScope(cfg *config.Config, r *http.Request) *tenancy.Scope {
+	if cfg == nil || len(cfg.TenantScopes.Scopes) == 0 {
+		return nil
+	}
+
+	var username string
+	var roles []string
+	if cfg.ProxyAuthSecret != "" {
+		if valid, proxyUser, _ := CheckProxyAuth(cfg, r); valid {
+			username = proxyUser
+			roles = proxyAuthRoles(cfg, r)
+		}
+	}
+	if username == "" {
+		if cookie, err := r.Cookie("pulse_session"); err == nil && cookie.Value != "" {
+			username = GetSessionUsername(cookie.Value)
+		}
+	}
+
+	var tokenID, tokenName string
+	if record := getAPITokenRecordFromRequest(r); record != nil {
+		tokenID, tokenName = record.ID, record.Name
+	}
+
+	return tenancy.New(cfg.TenantScopes.Matching(username, roles, tokenID, tokenName))
+}
+
+// tenantScopedPaths are the read endpoints that filter their responses by tenant scope.
+// Entries ending in "/" match by prefix.
+var tenantScopedPaths = []string{
+	"/api/health",
+	"/api/version",
+	"/api/server/info",
+	"/api/security/status",
+	"/api/state",
+	"/api/alerts/active",
+	"/api/alerts/history",
+	"/api/backups",
+	"/api/backups/pve",
+	"/api/backups/pbs",
+	"/api/snapshots",
+	"/api/patch-status",
+	"/api/tasks",
+	"/api/forecast",
+	"/api/recommendations",
+	"/api/tenant-scopes/me",
+	"/api/federation/snapshot",
+	"/api/federation/stream",
+	"/ws",
+}
+
+// tenantScopedWritePaths are the write endpoints that check the caller's tenant scope themselves
+var tenantScopedWritePaths = []string{
+	"/api/federation/ack",
+}
+
+// tenantScopeAllowsRequest reports whether a request may proceed. Callers bound to a tenant
+// scope are limited to the endpoints that filter or check by scope; the frontend itself and
+// logging out stay available.
+func tenantScopeAllowsRequest(cfg *config.Config, r *http.Request) bool {
+	path := r.URL.Path
+	if !strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/ws") &&
+		!strings.HasPrefix(path, "/socket.io/") && !strings.HasPrefix(path, "/download/") &&
+		path != "/simple-stats" && path != "/install-docker-agent.sh" {
+		return true
+	}
+	if path == "/api/logout" || requestTenantScope(cfg, r) == nil {
+		return true
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		for _, allowed := range tenantScopedWritePaths {
+			if path == allowed {
+				return true
+			}
+		}
+		return false
+	}
+	for _, allowed := range tenantScopedPaths {
+		if path == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(path, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyAuthRoles returns the roles sent by the authenticating proxy
+func proxyAuthRoles(cfg *config.Config, r *http.Request) []string {
+	if cfg.ProxyAuthRoleHeader == "" {
+		return nil
+	}
+	header := r.Header.Get(cfg.ProxyAuthRoleHeader)
+	if header == "" {
+		return nil
+	}
+	separator := cfg.ProxyAuthRoleSeparator
+	if separator == "" {
+		separator = "|"
+	}
+	var roles []string
+	for _, role := range strings.Split(header, separator) {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// TenantScopeHandlers manages tenant scopes
+type TenantScopeHandlers struct {
+	config      *config.Config
+	monitor     *monitoring.Monitor
+	persistence *config.ConfigPersistence
+}
+
+// NewTenantScopeHandlers creates tenant scope handlers
+func NewTenantScopeHandlers(cfg *config.Config, m *monitoring.Monitor, persistence *config.ConfigPersistence) *TenantScopeHandlers {
+	return &TenantScopeHandlers{config: cfg, monitor: m, persistence: persistence}
+}
+
+// SetMonitor updates the monitor reference for tenant scope handlers.
+func (h *TenantScopeHandlers) SetMonitor(m *monitoring.Monitor) {
+	h.monitor = m
+}
+
+// HandleTenantScopes routes /api/tenant-scopes requests
+func (h *TenantScopeHandlers) HandleTenantScopes(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if err := utils.WriteJSONResponse(w, h.config.TenantScopes); err != nil {
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write tenant scopes response")
+		}
+	case http.MethodPut:
+		h.UpdateTenantScopes(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// UpdateTenantScopes validates, saves and applies the tenant scopes
+func (h *TenantScopeHandlers) UpdateTenantScopes(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 256*1024)
+	var cfg config.TenantScopesConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	cfg = config.NormalizeTenantScopesConfig(cfg)
+	if err := cfg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.persistence.SaveTenantScopesConfig(cfg); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to save tenant scopes")
+		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+		return
+	}
+	h.config.TenantScopes = cfg
+	if h.monitor != nil {
+		h.monitor.SetTenantScopesConfig(cfg)
+	}
+
+	LogAuditEvent("tenant_scopes_config", requestUsername(h.config, w, r), GetClientIP(r), r.URL.Path, true,
+		fmt.Sprintf("scopes=%d", len(cfg.Scopes)))
+
+	if err := utils.WriteJSONResponse(w, cfg); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write tenant scopes response")
+	}
+}
+
+// HandleCurrentScope returns the tenant scopes restricting the caller, if any
+func (h *TenantScopeHandlers) HandleCurrentScope(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	scope := requestTenantScope(h.config, r)
+	response := struct {
+		Restricted bool                 `json:"restricted"`
+		Scopes     []config.TenantScope `json:"scopes"`
+	}{
+		Restricted: scope != nil,
+		Scopes:     []config.TenantScope{},
+	}
+	for _, tenantScope := range h.config.TenantScopes.ByID(scope.IDs()) {
+		// Bindings and notification targets are admin configuration
+		response.Scopes = append(response.Scopes, config.TenantScope{
+			ID:        tenantScope.ID,
+			Name:      tenantScope.Name,
+			Pools:     tenantScope.Pools,
+			Tags:      tenantScope.Tags,
+			Instances: tenantScope.Instances,
+		})
+	}
+
+	if err := utils.WriteJSONResponse(w, response); err != nil {
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to write current tenant scope response")
+	}
+}
+
+// websocketScope resolves the tenant scope key of a new WebSocket client
+func (r *Router) websocketScope(req *http.Request) string {
+	return requestTenantScope(r.config, req).Key()
+}
+
+// filterWebSocketMessage limits a broadcast to what a tenant scope may see. Messages that
+// cannot be attributed to a scope are dropped for scoped clients.
+func (r *Router) filterWebSocketMessage(key string, msg websocket.Message) (websocket.Message, bool) {
+	scope := tenancy.New(r.config.TenantScopes.ByID(tenancy.ParseKey(key)))
+	if scope == nil {
+		// The client's scopes were removed; it gets nothing until it reconnects
+		return msg, false
+	}
+
+	switch data := msg.Data.(type) {
+	case models.StateFrontend:
+		msg.Data = scope.FilterFrontend(data)
+		return msg, true
+	case models.StateSnapshot:
+		msg.Data = scope.FilterFrontend(data.ToFrontend())
+		return msg, true
+	case alerts.Alert:
+		if r.monitor == nil {
+			return msg, false
+		}
+		return msg, scope.AllowsAlert(data.Instance, data.ResourceID, scope.Guests(r.monitor.GetState()))
+	}
+
+	switch msg.Type {
+	case "ping", "welcome", "alertResolved":
+		return msg, true
+	}
+	return msg, false
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+func TestTenantScopeAllowsRequest(t *testing.T) {
+	cfg := &config.Config{TenantScopes: config.TenantScopesConfig{Scopes: []config.TenantScope{
+		{ID: "team-a", Name: "Team A", Pools: []string{"team-a"}, Tokens: []string{"team-a-dashboard"}},
+	}}}
+
+	request := func(method, path string, token *config.APITokenRecord) *http.Request {
+		req := httptest.NewRequest(method, path, nil)
+		attachAPITokenRecord(req, token)
+		return req
+	}
+	scopedToken := &config.APITokenRecord{ID: "1", Name: "team-a-dashboard"}
+	adminToken := &config.APITokenRecord{ID: "2", Name: "automation"}
+
+	if scope := requestTenantScope(cfg, request(http.MethodGet, "/api/state", scopedToken)); scope == nil || scope.Key() != "team-a" {
+		t.Fatalf("expected token to resolve to team-a scope, got %v", scope)
+	}
+
+	cases := []struct {
+		method string
+		path   string
+		token  *config.APITokenRecord
+		want   bool
+	}{
+		{http.MethodGet, "/api/state", scopedToken, true},
+		{http.MethodGet, "/api/alerts/history", scopedToken, true},
+		{http.MethodGet, "/assets/index.js", scopedToken, true},
+		{http.MethodPost, "/api/logout", scopedToken, true},
+		{http.MethodGet, "/api/config/nodes", scopedToken, false},
+		{http.MethodGet, "/api/charts", scopedToken, false},
+		{http.MethodPost, "/api/alerts/bulk/clear", scopedToken, false},
+		{http.MethodPut, "/api/tenant-scopes", scopedToken, false},
+		{http.MethodGet, "/api/federation/stream", scopedToken, true},
+		{http.MethodPost, "/api/federation/ack", scopedToken, true},
+		{http.MethodGet, "/api/federation/state", scopedToken, false},
+		{http.MethodGet, "/api/config/nodes", adminToken, true},
+		{http.MethodPost, "/api/alerts/bulk/clear", nil, true},
+	}
+	for _, tc := range cases {
+		if got := tenantScopeAllowsRequest(cfg, request(tc.method, tc.path, tc.token)); got != tc.want {
+			t.Errorf("%s %s (token %v) allowed = %t, want %t", tc.method, tc.path, tc.token != nil, got, tc.want)
+		}
+	}
+}
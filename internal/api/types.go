@@ -3,6 +3,7 @@ package api
 import (
 	"time"
 
+	"github.com/RouXx67/PulseUp/internal/ha"
 	"github.com/RouXx67/PulseUp/internal/models"
 	"github.com/RouXx67/PulseUp/internal/types"
 )
@@ -18,6 +19,9 @@ type HealthResponse struct {
 	RecommendProxyUpgrade       bool    `json:"recommendProxyUpgrade,omitempty"`
 	ProxyInstallScriptAvailable bool    `json:"proxyInstallScriptAvailable,omitempty"`
 	DevModeSSH                  bool    `json:"devModeSSH,omitempty"` // DEV/TEST ONLY: SSH keys allowed in containers
+
+	// Leadership of this instance when high availability is enabled
+	HA *ha.Status `json:"ha,omitempty"`
 }
 
 // VersionResponse represents version information
@@ -26,6 +26,9 @@ func NewUpdateHandlers(manager *updates.Manager, dataDir string) *UpdateHandlers
 		// Continue without history - handlers will check for nil
 	}
 
+	// Record in-app installs, verification and rollbacks alongside adapter updates
+	manager.SetHistory(history)
+
 	// Initialize updater registry
 	registry := updates.NewUpdaterRegistry()
 
@@ -56,14 +59,14 @@ func (h *UpdateHandlers) HandleCheckUpdates(w http.ResponseWriter, r *http.Reque
 
 	info, err := h.manager.CheckForUpdatesWithChannel(ctx, channel)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to check for updates")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to check for updates")
 		http.Error(w, "Failed to check for updates", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(info); err != nil {
-		log.Error().Err(err).Msg("Failed to encode update info")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode update info")
 	}
 }
 
@@ -92,7 +95,7 @@ func (h *UpdateHandlers) HandleApplyUpdate(w http.ResponseWriter, r *http.Reques
 	go func() {
 		ctx := context.Background()
 		if err := h.manager.ApplyUpdate(ctx, req.DownloadURL); err != nil {
-			log.Error().Err(err).Msg("Failed to apply update")
+			log.Error().Ctx(r.Context()).Err(err).Msg("Failed to apply update")
 		}
 	}()
 
@@ -115,7 +118,7 @@ func (h *UpdateHandlers) HandleUpdateStatus(w http.ResponseWriter, r *http.Reque
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(status); err != nil {
-		log.Error().Err(err).Msg("Failed to encode update status")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to encode update status")
 	}
 }
 
@@ -153,7 +156,7 @@ func (h *UpdateHandlers) HandleGetUpdatePlan(w http.ResponseWriter, r *http.Requ
 		Channel: r.URL.Query().Get("channel"),
 	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to prepare update plan")
+		log.Error().Ctx(r.Context()).Err(err).Msg("Failed to prepare update plan")
 		http.Error(w, "Failed to prepare update plan", http.StatusInternalServerError)
 		return
 	}
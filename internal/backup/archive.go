@@ -0,0 +1,334 @@
+// Package backup creates and restores archives of the whole Pulse data directory.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatVersion is the archive layout written by Create
+	FormatVersion = 1
+	// ManifestName is the archive entry listing every other entry with its checksum. It is
+	// written last so the checksums cover exactly what was archived.
+	ManifestName = "MANIFEST.json"
+	// LocalDirName is the default local backup target inside the data directory; it is never
+	// archived or replaced by a restore
+	LocalDirName = "backups"
+
+	dataPrefix    = "data/"
+	runtimePrefix = "runtime/"
+)
+
+// Manifest describes an archive
+type Manifest struct {
+	Format       int         `json:"format"`
+	PulseVersion string      `json:"pulseVersion,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	Hostname     string      `json:"hostname,omitempty"`
+	Files        []FileEntry `json:"files"`
+}
+
+// FileEntry is one archived file. Paths start with data/ for files from the data directory and
+// runtime/ for in-memory state.
+type FileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Mode   uint32 `json:"mode"`
+	SHA256 string `json:"sha256"`
+}
+
+// TotalSize returns the uncompressed size of all files
+func (m *Manifest) TotalSize() int64 {
+	var total int64
+	for _, file := range m.Files {
+		total += file.Size
+	}
+	return total
+}
+
+// RuntimeNames returns the names of the in-memory state snapshots in the archive
+func (m *Manifest) RuntimeNames() []string {
+	var names []string
+	for _, file := range m.Files {
+		if strings.HasPrefix(file.Path, runtimePrefix) {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(file.Path, runtimePrefix), ".json"))
+		}
+	}
+	return names
+}
+
+// Options controls what Create archives
+type Options struct {
+	DataDir      string
+	PulseVersion string
+	// Exclude lists paths relative to DataDir that are left out, e.g. a local backup target
+	Exclude []string
+	// Flush persists in-memory state to the data directory before files are read
+	Flush []func() error
+	// Runtime adds state that only exists in memory, stored as runtime/<name>.json
+	Runtime map[string]func() ([]byte, error)
+}
+
+// Create writes a gzip-compressed tar archive of the data directory to w. Files are read one at a
+// time while Pulse keeps running; each is captured as a whole because Pulse replaces its files
+// atomically.
+func Create(w io.Writer, opts Options) (*Manifest, error) {
+	if opts.DataDir == "" {
+		return nil, fmt.Errorf("data directory is required")
+	}
+	for _, flush := range opts.Flush {
+		if err := flush(); err != nil {
+			return nil, fmt.Errorf("failed to persist state before backup: %w", err)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	manifest := &Manifest{
+		Format:       FormatVersion,
+		PulseVersion: opts.PulseVersion,
+		CreatedAt:    time.Now().UTC(),
+		Hostname:     hostname,
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	excluded := append([]string{LocalDirName}, opts.Exclude...)
+	err := filepath.WalkDir(opts.DataDir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(opts.DataDir, filePath)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if skipPath(rel, excluded) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Removed while the backup was running
+				return nil
+			}
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return writeEntry(tw, manifest, dataPrefix+rel, data, info.Mode().Perm(), info.ModTime())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive data directory: %w", err)
+	}
+
+	names := make([]string, 0, len(opts.Runtime))
+	for name := range opts.Runtime {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := opts.Runtime[name]()
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %w", name, err)
+		}
+		if err := writeEntry(tw, manifest, runtimePrefix+name+".json", data, 0600, manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: ManifestName, Mode: 0600, Size: int64(len(manifestData)), ModTime: manifest.CreatedAt}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(manifestData); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// skipPath reports whether a data directory path is left out of archives and restores: excluded
+// paths, restore bookkeeping, transaction staging, the data directory lock and temporary files
+func skipPath(rel string, excluded []string) bool {
+	for _, ex := range excluded {
+		ex = strings.Trim(filepath.ToSlash(ex), "/")
+		if ex != "" && (rel == ex || strings.HasPrefix(rel, ex+"/")) {
+			return true
+		}
+	}
+	top := strings.SplitN(rel, "/", 2)[0]
+	return top == PendingDirName || top == RuntimeRestoreDirName || top == SyncDirName || top == dataDirLockName ||
+		strings.HasPrefix(top, preRestorePrefix) || strings.HasPrefix(top, ".import-staging-") ||
+		strings.HasSuffix(rel, ".tmp")
+}
+
+func writeEntry(tw *tar.Writer, manifest *Manifest, name string, data []byte, mode os.FileMode, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: int64(mode), Size: int64(len(data)), ModTime: modTime}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	manifest.Files = append(manifest.Files, FileEntry{
+		Path:   name,
+		Size:   int64(len(data)),
+		Mode:   uint32(mode),
+		SHA256: hex.EncodeToString(sum[:]),
+	})
+	return nil
+}
+
+// Verify reads a whole archive and checks every entry against the manifest
+func Verify(r io.Reader) (*Manifest, error) {
+	return extract(r, "")
+}
+
+// extract reads an archive, writing its entries below dir unless dir is empty, and verifies the
+// checksums and completeness of every entry once the manifest has been read
+func extract(r io.Reader, dir string) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a Pulse backup archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	type actual struct {
+		size int64
+		sum  string
+	}
+	seen := make(map[string]actual)
+	var manifest *Manifest
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected archive entry %s", header.Name)
+		}
+
+		if header.Name == ManifestName {
+			var m Manifest
+			if err := json.NewDecoder(io.LimitReader(tr, 64<<20)).Decode(&m); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			manifest = &m
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if name != header.Name || strings.Contains(name, "..") ||
+			!(strings.HasPrefix(name, dataPrefix) || strings.HasPrefix(name, runtimePrefix)) {
+			return nil, fmt.Errorf("unsafe archive entry %q", header.Name)
+		}
+		if _, dup := seen[name]; dup {
+			return nil, fmt.Errorf("duplicate archive entry %s", name)
+		}
+
+		hash := sha256.New()
+		var out io.Writer = hash
+		var file *os.File
+		if dir != "" {
+			target := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return nil, err
+			}
+			file, err = os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm()|0600)
+			if err != nil {
+				return nil, err
+			}
+			out = io.MultiWriter(hash, file)
+		}
+		size, err := io.Copy(out, tr)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		seen[name] = actual{size: size, sum: hex.EncodeToString(hash.Sum(nil))}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("archive has no %s", ManifestName)
+	}
+	if manifest.Format < 1 || manifest.Format > FormatVersion {
+		return nil, fmt.Errorf("unsupported backup format %d (this version reads up to %d)", manifest.Format, FormatVersion)
+	}
+	for _, file := range manifest.Files {
+		got, ok := seen[file.Path]
+		if !ok {
+			return nil, fmt.Errorf("archive is missing %s", file.Path)
+		}
+		if got.size != file.Size || got.sum != file.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s", file.Path)
+		}
+		delete(seen, file.Path)
+	}
+	if len(seen) > 0 {
+		extra := make([]string, 0, len(seen))
+		for name := range seen {
+			extra = append(extra, name)
+		}
+		sort.Strings(extra)
+		return nil, fmt.Errorf("archive entries not in the manifest: %s", strings.Join(extra, ", "))
+	}
+	return manifest, nil
+}
+
+// ReadManifest reads the manifest of a staged restore
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,291 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/crypto"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeKey writes a new random key file
+func writeKey(t *testing.T, path string) {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, base64.StdEncoding.EncodeToString(key))
+}
+
+func newDataDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "system.json"), `{"pollingInterval":10}`)
+	writeFile(t, filepath.Join(dir, "alerts", "history.json"), `[]`)
+	writeFile(t, filepath.Join(dir, "backups", "old.tar.gz"), "not archived")
+	writeFile(t, filepath.Join(dir, "system.json.tmp"), "partial")
+	return dir
+}
+
+func TestCreateAndVerify(t *testing.T) {
+	dir := newDataDir(t)
+
+	flushed := false
+	var buf bytes.Buffer
+	manifest, err := Create(&buf, Options{
+		DataDir:      dir,
+		PulseVersion: "v4.99.0",
+		Flush: []func() error{func() error {
+			flushed = true
+			return nil
+		}},
+		Runtime: map[string]func() ([]byte, error){
+			"metrics-history": func() ([]byte, error) { return []byte(`{}`), nil },
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !flushed {
+		t.Fatal("flush hook was not called")
+	}
+
+	paths := make([]string, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	want := "data/alerts/history.json,data/system.json,runtime/metrics-history.json"
+	if got := strings.Join(paths, ","); got != want {
+		t.Fatalf("archived %s, want %s", got, want)
+	}
+
+	verified, err := Verify(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if verified.PulseVersion != "v4.99.0" || len(verified.Files) != 3 {
+		t.Fatalf("unexpected manifest: %+v", verified)
+	}
+	if names := verified.RuntimeNames(); len(names) != 1 || names[0] != "metrics-history" {
+		t.Fatalf("RuntimeNames = %v", names)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	dir := newDataDir(t)
+	var buf bytes.Buffer
+	if _, err := Create(&buf, Options{DataDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the archive with one file's content changed
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var out bytes.Buffer
+	gzw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gzw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		if header.Name == "data/system.json" {
+			data = []byte(`{"pollingInterval":99}`)
+			header.Size = int64(len(data))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	gzw.Close()
+
+	if _, err := Verify(&out); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestStageAndApplyPending(t *testing.T) {
+	source := newDataDir(t)
+	var buf bytes.Buffer
+	if _, err := Create(&buf, Options{
+		DataDir: source,
+		Runtime: map[string]func() ([]byte, error){
+			"docker-hosts": func() ([]byte, error) { return []byte(`[]`), nil },
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	target := t.TempDir()
+	writeFile(t, filepath.Join(target, "system.json"), `{"pollingInterval":30}`)
+	writeFile(t, filepath.Join(target, "stale.json"), `{}`)
+	writeFile(t, filepath.Join(target, "backups", "keep.tar.gz"), "keep")
+
+	if _, err := Stage(&buf, target); err != nil {
+		t.Fatalf("Stage: %v", err)
+	}
+	// Nothing is replaced until the restore is applied
+	if data, _ := os.ReadFile(filepath.Join(target, "system.json")); string(data) != `{"pollingInterval":30}` {
+		t.Fatalf("Stage modified the data directory: %s", data)
+	}
+
+	manifest, err := ApplyPending(target)
+	if err != nil || manifest == nil {
+		t.Fatalf("ApplyPending = %v, %v", manifest, err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(target, "system.json")); string(data) != `{"pollingInterval":10}` {
+		t.Fatalf("system.json not restored: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(target, "stale.json")); !os.IsNotExist(err) {
+		t.Fatal("files absent from the backup should be moved aside")
+	}
+	if _, err := os.Stat(filepath.Join(target, "backups", "keep.tar.gz")); err != nil {
+		t.Fatal("local backups must survive a restore")
+	}
+	if _, err := os.Stat(filepath.Join(target, RuntimeRestoreDirName, "docker-hosts.json")); err != nil {
+		t.Fatal("runtime state not handed to the monitor")
+	}
+	if _, err := os.Stat(filepath.Join(target, PendingDirName)); !os.IsNotExist(err) {
+		t.Fatal("pending restore not cleaned up")
+	}
+
+	previous, _ := filepath.Glob(filepath.Join(target, preRestorePrefix+"*", "stale.json"))
+	if len(previous) != 1 {
+		t.Fatal("previous files not kept in a pre-restore directory")
+	}
+
+	// Nothing pending
+	if manifest, err := ApplyPending(target); manifest != nil || err != nil {
+		t.Fatalf("second ApplyPending = %v, %v", manifest, err)
+	}
+}
+
+func TestStageRejectsUnreadableConfig(t *testing.T) {
+	t.Run("invalid json", func(t *testing.T) {
+		source := t.TempDir()
+		writeFile(t, filepath.Join(source, "system.json"), `{"pollingInterval":`)
+		var buf bytes.Buffer
+		if _, err := Create(&buf, Options{DataDir: source}); err != nil {
+			t.Fatal(err)
+		}
+
+		target := t.TempDir()
+		if _, err := Stage(&buf, target); err == nil || !strings.Contains(err.Error(), "not valid JSON") {
+			t.Fatalf("expected JSON error, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(target, PendingDirName)); !os.IsNotExist(err) {
+			t.Fatal("failed stage left a pending restore")
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		source := t.TempDir()
+		writeKey(t, filepath.Join(source, ".encryption.key"))
+		cm, err := crypto.OpenKeyFile(filepath.Join(source, ".encryption.key"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		encrypted, err := cm.Encrypt([]byte(`{"nodes":[]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(source, "nodes.enc"), string(encrypted))
+
+		// Replace the key so the archived file no longer decrypts
+		writeKey(t, filepath.Join(source, ".encryption.key"))
+
+		var buf bytes.Buffer
+		if _, err := Create(&buf, Options{DataDir: source}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Stage(&buf, t.TempDir()); err == nil || !strings.Contains(err.Error(), "nodes.enc") {
+			t.Fatalf("expected decryption error, got %v", err)
+		}
+	})
+}
+
+func TestLocalTargetPrune(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(t.TempDir(), "archive.tar.gz")
+	writeFile(t, source, "archive")
+
+	target := &LocalTarget{Dir: dir}
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		if err := target.Upload(ctx, ArchiveName(start.Add(time.Duration(i)*time.Hour)), source); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(dir, "notes.txt"), "unrelated")
+
+	deleted, err := Prune(ctx, target, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || deleted[0] != ArchiveName(start) {
+		t.Fatalf("deleted %v", deleted)
+	}
+	latest, err := LatestTime(ctx, target)
+	if err != nil || !latest.Equal(start.Add(3*time.Hour)) {
+		t.Fatalf("LatestTime = %v, %v", latest, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Fatal("prune removed a file that is not a backup")
+	}
+}
+
+func TestStageNeverRunsArchivedKeyWrapPlugin(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "executed")
+	script := filepath.Join(t.TempDir(), "plugin.sh")
+	writeFile(t, script, "#!/bin/sh\ntouch "+marker+"\nexit 1\n")
+	if err := os.Chmod(script, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	source := t.TempDir()
+	writeFile(t, filepath.Join(source, ".encryption.key"),
+		`{"format":1,"active":2,"wrap":{"method":"plugin","params":{"command":"`+script+`"}},"keys":[{"version":2,"key":"AAAA"}]}`)
+	writeFile(t, filepath.Join(source, "nodes.enc"), "ciphertext")
+
+	var buf bytes.Buffer
+	if _, err := Create(&buf, Options{DataDir: source}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Stage(&buf, t.TempDir()); err != nil {
+		t.Fatalf("Stage: %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatal("verifying the archive executed the archived key wrap plugin")
+	}
+}
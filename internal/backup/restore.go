@@ -0,0 +1,204 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/crypto"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// PendingDirName holds a verified restore inside the data directory until it is applied
+	PendingDirName = ".restore-pending"
+	// RuntimeRestoreDirName receives the in-memory state of a restored archive; the monitor
+	// loads and removes it on startup
+	RuntimeRestoreDirName = ".restore-runtime"
+
+	preRestorePrefix = ".pre-restore-"
+
+	// dataDirLockName is locked by the running server (config.LockDataDirShared) and must stay in place
+	dataDirLockName = ".pulse.lock"
+)
+
+// Stage extracts an archive into the data directory's pending restore area and verifies it:
+// every checksum must match, JSON files must parse and encrypted files must decrypt with the
+// archived keyring. Nothing outside the pending area is modified; ApplyPending swaps it in.
+func Stage(r io.Reader, dataDir string) (*Manifest, error) {
+	pending := filepath.Join(dataDir, PendingDirName)
+	if err := os.RemoveAll(pending); err != nil {
+		return nil, fmt.Errorf("failed to clear previous pending restore: %w", err)
+	}
+	if err := os.MkdirAll(pending, 0700); err != nil {
+		return nil, err
+	}
+
+	manifest, err := extract(r, pending)
+	if err == nil {
+		err = verifyStaged(filepath.Join(pending, "data"))
+	}
+	if err != nil {
+		_ = os.RemoveAll(pending)
+		return nil, fmt.Errorf("backup verification failed: %w", err)
+	}
+
+	// The manifest marks the staged restore as complete
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		_ = os.RemoveAll(pending)
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(pending, ManifestName), data, 0600); err != nil {
+		_ = os.RemoveAll(pending)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// verifyStaged checks that the restored configuration is readable
+func verifyStaged(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("archive contains no data directory files")
+		}
+		return err
+	}
+
+	var cm *crypto.CryptoManager
+	keyPath := filepath.Join(dir, ".encryption.key")
+	if _, err := os.Stat(keyPath); err == nil {
+		// A key wrapper is never built from archive contents: a plugin-wrapped keyring names a
+		// command that would run during verification
+		method, err := crypto.KeyFileWrapMethod(keyPath)
+		if err != nil {
+			return fmt.Errorf("archived encryption key is unusable: %w", err)
+		}
+		if method != "" {
+			log.Warn().Str("method", method).Msg("Archived keyring is wrapped; encrypted files are not checked")
+		} else if cm, err = crypto.OpenKeyFile(keyPath); err != nil {
+			return fmt.Errorf("archived encryption key is unusable: %w", err)
+		}
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, ".json"):
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+			if len(data) > 0 && !json.Valid(data) {
+				return fmt.Errorf("%s is not valid JSON", name)
+			}
+		case strings.HasSuffix(name, ".enc") && cm != nil:
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				return err
+			}
+			if _, err := cm.Decrypt(data); err != nil {
+				return fmt.Errorf("%s cannot be decrypted with the archived key: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyPending swaps a staged restore into the data directory. The current files are moved to a
+// .pre-restore-<timestamp> directory so the restore can be undone by hand, and a failed swap is
+// rolled back. It must run before anything reads the data directory, and returns nil when no
+// restore is pending.
+func ApplyPending(dataDir string) (*Manifest, error) {
+	pending := filepath.Join(dataDir, PendingDirName)
+	manifest, err := ReadManifest(pending)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if _, statErr := os.Stat(pending); statErr == nil {
+				// Staging never completed
+				_ = os.RemoveAll(pending)
+			}
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read pending restore: %w", err)
+	}
+
+	preRestore := filepath.Join(dataDir, preRestorePrefix+time.Now().UTC().Format("20060102-150405"))
+	if err := os.MkdirAll(preRestore, 0700); err != nil {
+		return nil, err
+	}
+
+	current, err := os.ReadDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	type move struct{ from, to string }
+	var done []move
+	rename := func(from, to string) error {
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		done = append(done, move{from, to})
+		return nil
+	}
+	rollback := func(cause error) (*Manifest, error) {
+		for i := len(done) - 1; i >= 0; i-- {
+			if err := os.Rename(done[i].to, done[i].from); err != nil {
+				log.Error().Err(err).Str("path", done[i].from).Msg("Failed to roll back restore")
+			}
+		}
+		return nil, fmt.Errorf("failed to apply restore, previous files kept: %w", cause)
+	}
+
+	for _, entry := range current {
+		name := entry.Name()
+		if skipPath(name, nil) || name == LocalDirName {
+			continue
+		}
+		if err := rename(filepath.Join(dataDir, name), filepath.Join(preRestore, name)); err != nil {
+			return rollback(err)
+		}
+	}
+
+	staged, err := os.ReadDir(filepath.Join(pending, "data"))
+	if err != nil {
+		return rollback(err)
+	}
+	for _, entry := range staged {
+		name := entry.Name()
+		if name == LocalDirName {
+			continue
+		}
+		if err := rename(filepath.Join(pending, "data", name), filepath.Join(dataDir, name)); err != nil {
+			return rollback(err)
+		}
+	}
+
+	runtimeDir := filepath.Join(dataDir, RuntimeRestoreDirName)
+	_ = os.RemoveAll(runtimeDir)
+	if _, err := os.Stat(filepath.Join(pending, "runtime")); err == nil {
+		if err := rename(filepath.Join(pending, "runtime"), runtimeDir); err != nil {
+			return rollback(err)
+		}
+	}
+
+	if err := os.RemoveAll(pending); err != nil {
+		log.Warn().Err(err).Msg("Failed to remove pending restore directory")
+	}
+
+	log.Info().
+		Time("backupCreatedAt", manifest.CreatedAt).
+		Str("backupVersion", manifest.PulseVersion).
+		Str("previousFiles", preRestore).
+		Msg("Restored data directory from backup")
+	return manifest, nil
+}
@@ -0,0 +1,92 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SyncDirName receives an archive being synced into the data directory
+const SyncDirName = ".sync-staging"
+
+// Sync brings a running standby's data directory in line with an archive of the leader's. The
+// archive is verified like a restore, then each file whose content differs is replaced
+// atomically; files the archive lacks are kept. Runtime snapshots are placed where the monitor
+// loads them when it starts. Paths in exclude, relative to dataDir, are left untouched. It
+// returns the replaced paths.
+func Sync(r io.Reader, dataDir string, exclude []string) ([]string, error) {
+	staging := filepath.Join(dataDir, SyncDirName)
+	if err := os.RemoveAll(staging); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(staging, 0700); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	if _, err := extract(r, staging); err != nil {
+		return nil, err
+	}
+	stagedData := filepath.Join(staging, "data")
+	if err := verifyStaged(stagedData); err != nil {
+		return nil, fmt.Errorf("archive verification failed: %w", err)
+	}
+
+	var changed []string
+	err := filepath.WalkDir(stagedData, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(stagedData, filePath)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if skipPath(rel, append([]string{LocalDirName}, exclude...)) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		target := filepath.Join(dataDir, filepath.FromSlash(rel))
+		incoming, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		if current, err := os.ReadFile(target); err == nil && bytes.Equal(current, incoming) {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		if err := os.Rename(filePath, target); err != nil {
+			return err
+		}
+		changed = append(changed, rel)
+		return nil
+	})
+	if err != nil {
+		return changed, fmt.Errorf("failed to sync data directory: %w", err)
+	}
+
+	runtimeDir := filepath.Join(dataDir, RuntimeRestoreDirName)
+	if _, err := os.Stat(filepath.Join(staging, "runtime")); err == nil {
+		if err := os.RemoveAll(runtimeDir); err != nil {
+			return changed, err
+		}
+		if err := os.Rename(filepath.Join(staging, "runtime"), runtimeDir); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSync(t *testing.T) {
+	source := newDataDir(t)
+	writeFile(t, filepath.Join(source, ".env"), "PULSE_HA_NODE_ID=leader")
+	var buf bytes.Buffer
+	if _, err := Create(&buf, Options{
+		DataDir: source,
+		Runtime: map[string]func() ([]byte, error){
+			"metrics-history": func() ([]byte, error) { return []byte(`{}`), nil },
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	target := t.TempDir()
+	writeFile(t, filepath.Join(target, "system.json"), `{"pollingInterval":30}`)
+	writeFile(t, filepath.Join(target, "alerts", "history.json"), `[]`)
+	writeFile(t, filepath.Join(target, "local.json"), `{}`)
+	writeFile(t, filepath.Join(target, ".env"), "PULSE_HA_NODE_ID=standby")
+
+	changed, err := Sync(&buf, target, []string{".env"})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := strings.Join(changed, ","); got != "system.json" {
+		t.Fatalf("changed = %s, want only system.json", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, "system.json")); string(data) != `{"pollingInterval":10}` {
+		t.Fatalf("system.json not synced: %s", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, ".env")); string(data) != "PULSE_HA_NODE_ID=standby" {
+		t.Fatalf("excluded file was replaced: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(target, "local.json")); err != nil {
+		t.Fatal("files absent from the archive should be kept")
+	}
+	if _, err := os.Stat(filepath.Join(target, RuntimeRestoreDirName, "metrics-history.json")); err != nil {
+		t.Fatal("runtime state not handed to the monitor")
+	}
+	if _, err := os.Stat(filepath.Join(target, SyncDirName)); !os.IsNotExist(err) {
+		t.Fatal("sync staging not cleaned up")
+	}
+}
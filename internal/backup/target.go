@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	archivePrefix     = "pulse-backup-"
+	archiveSuffix     = ".tar.gz"
+	archiveTimeLayout = "20060102T150405Z"
+)
+
+// Target stores backup archives
+type Target interface {
+	Name() string
+	// Upload stores the archive at path under name
+	Upload(ctx context.Context, name, path string) error
+	// List returns the names of stored archives
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// ArchiveName returns the file name for an archive created at t
+func ArchiveName(t time.Time) string {
+	return archivePrefix + t.UTC().Format(archiveTimeLayout) + archiveSuffix
+}
+
+// ArchiveTime parses the creation time from an archive name
+func ArchiveTime(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, archivePrefix) || !strings.HasSuffix(name, archiveSuffix) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(archiveTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, archivePrefix), archiveSuffix))
+	return t, err == nil
+}
+
+// archives returns the archive names on a target, oldest first
+func archives(ctx context.Context, target Target) ([]string, error) {
+	names, err := target.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, name := range names {
+		if _, ok := ArchiveTime(name); ok {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// LatestTime returns when the newest archive on the target was created, or the zero time
+func LatestTime(ctx context.Context, target Target) (time.Time, error) {
+	names, err := archives(ctx, target)
+	if err != nil || len(names) == 0 {
+		return time.Time{}, err
+	}
+	t, _ := ArchiveTime(names[len(names)-1])
+	return t, nil
+}
+
+// Prune deletes the oldest archives so that at most retain remain, returning the deleted names
+func Prune(ctx context.Context, target Target, retain int) ([]string, error) {
+	if retain <= 0 {
+		return nil, nil
+	}
+	names, err := archives(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	for len(names) > retain {
+		if err := target.Delete(ctx, names[0]); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, names[0])
+		names = names[1:]
+	}
+	return deleted, nil
+}
+
+// LocalTarget stores archives in a directory
+type LocalTarget struct {
+	Dir string
+}
+
+// Name describes the target
+func (l *LocalTarget) Name() string {
+	return l.Dir
+}
+
+// Upload copies the archive into the directory
+func (l *LocalTarget) Upload(ctx context.Context, name, path string) error {
+	if err := os.MkdirAll(l.Dir, 0700); err != nil {
+		return err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	target := filepath.Join(l.Dir, name)
+	tmp := target + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// List returns the files in the directory
+func (l *LocalTarget) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Delete removes an archive
+func (l *LocalTarget) Delete(ctx context.Context, name string) error {
+	if strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid archive name %q", name)
+	}
+	return os.Remove(filepath.Join(l.Dir, name))
+}
@@ -0,0 +1,322 @@
+package chatops
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+type fakeBackend struct {
+	mu       sync.Mutex
+	acked    map[string]string
+	silenced []alerts.Silence
+	active   []alerts.Alert
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		acked: make(map[string]string),
+		active: []alerts.Alert{
+			{ID: "pve1-qemu-101-cpu", Level: alerts.AlertLevelWarning, ResourceName: "web01", Message: "CPU at 85%"},
+			{ID: "pve1-qemu-102-memory", Level: alerts.AlertLevelCritical, ResourceName: "db01", Message: "Memory at 97%"},
+		},
+	}
+}
+
+func (b *fakeBackend) AcknowledgeAlert(alertID, user string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, alert := range b.active {
+		if alert.ID == alertID {
+			b.acked[alertID] = user
+			return nil
+		}
+	}
+	return fmt.Errorf("alert not found: %s", alertID)
+}
+
+func (b *fakeBackend) SilenceResource(resource string, duration time.Duration, user, reason string) (alerts.Silence, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	silence := alerts.Silence{ID: "s1", Resource: resource, Until: time.Now().Add(duration), CreatedBy: user, Reason: reason}
+	b.silenced = append(b.silenced, silence)
+	return silence, nil
+}
+
+func (b *fakeBackend) ActiveAlerts() []alerts.Alert {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]alerts.Alert(nil), b.active...)
+}
+
+func (b *fakeBackend) GuestStatus(query string) (GuestStatus, bool) {
+	if query != "web01" && query != "101" {
+		return GuestStatus{}, false
+	}
+	return GuestStatus{Name: "web01", VMID: 101, Type: "VM", Node: "pve1", Status: "running", CPUPercent: 12.5, Uptime: 26 * time.Hour}, true
+}
+
+func TestParseCommand(t *testing.T) {
+	cases := map[string]Command{
+		"/ack abc":                  {Name: "ack", Args: []string{"abc"}},
+		"/ack@PulseBot abc":         {Name: "ack", Args: []string{"abc"}},
+		"/pulse silence web01 2h":   {Name: "silence", Args: []string{"web01", "2h"}},
+		"  /ALERTS  ":               {Name: "alerts", Args: []string{}},
+		"/pulse":                    {Name: "help"},
+		"/status   web01   ":        {Name: "status", Args: []string{"web01"}},
+		"/silence db01 1d patching": {Name: "silence", Args: []string{"db01", "1d", "patching"}},
+	}
+	for text, want := range cases {
+		got, ok := ParseCommand(text)
+		if !ok || got.Name != want.Name || strings.Join(got.Args, " ") != strings.Join(want.Args, " ") {
+			t.Errorf("ParseCommand(%q) = %+v, %v; want %+v", text, got, ok, want)
+		}
+	}
+	if _, ok := ParseCommand("   "); ok {
+		t.Errorf("expected empty text to be rejected")
+	}
+}
+
+func TestExecuteCommands(t *testing.T) {
+	backend := newFakeBackend()
+	caller := Caller{Platform: PlatformSlack, UserID: "U1", Username: "alice"}
+
+	reply := Execute(backend, caller, Command{Name: "ack", Args: []string{"pve1-qemu-101-cpu"}})
+	if !strings.HasPrefix(reply, "Acknowledged") || backend.acked["pve1-qemu-101-cpu"] != "slack:alice" {
+		t.Fatalf("unexpected ack result %q, acked=%v", reply, backend.acked)
+	}
+	if reply := Execute(backend, caller, Command{Name: "ack", Args: []string{"missing"}}); !strings.Contains(reply, "Could not acknowledge") {
+		t.Fatalf("expected ack failure, got %q", reply)
+	}
+
+	reply = Execute(backend, caller, Command{Name: "silence", Args: []string{"web01", "1d", "kernel", "update"}})
+	if !strings.HasPrefix(reply, "Silenced notifications for web01") || len(backend.silenced) != 1 {
+		t.Fatalf("unexpected silence result %q", reply)
+	}
+	if got := backend.silenced[0]; got.Reason != "kernel update" || time.Until(got.Until) < 23*time.Hour {
+		t.Fatalf("unexpected silence %+v", got)
+	}
+	if reply := Execute(backend, caller, Command{Name: "silence", Args: []string{"web01", "soon"}}); !strings.HasPrefix(reply, "Invalid duration") {
+		t.Fatalf("expected invalid duration reply, got %q", reply)
+	}
+
+	reply = Execute(backend, caller, Command{Name: "alerts"})
+	if !strings.HasPrefix(reply, "2 active alerts") || strings.Index(reply, "db01") > strings.Index(reply, "web01") {
+		t.Fatalf("expected critical alerts listed first, got %q", reply)
+	}
+
+	reply = Execute(backend, caller, Command{Name: "status", Args: []string{"101"}})
+	if !strings.Contains(reply, "web01 (VM 101) on pve1: running") || !strings.Contains(reply, "Uptime 1d 2h") {
+		t.Fatalf("unexpected status reply %q", reply)
+	}
+}
+
+func TestIsAllowedMatchesUserIDsOnly(t *testing.T) {
+	allowed := []string{"U1", "alice"}
+	if !isAllowed(allowed, Caller{UserID: "U1", Username: "bob"}) {
+		t.Fatalf("expected allowlisted user ID to be allowed")
+	}
+	if isAllowed(allowed, Caller{UserID: "U2", Username: "alice"}) {
+		t.Fatalf("expected a matching username with another user ID to be rejected")
+	}
+	if isAllowed(allowed, Caller{Username: "alice"}) {
+		t.Fatalf("expected a caller without a user ID to be rejected")
+	}
+}
+
+func newTestService(cfg config.ChatOpsConfig) (*Service, *fakeBackend) {
+	backend := newFakeBackend()
+	service := NewService(backend)
+	service.config = config.NormalizeChatOpsConfig(cfg)
+	return service, backend
+}
+
+func signSlack(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHandleSlack(t *testing.T) {
+	service, backend := newTestService(config.ChatOpsConfig{
+		Enabled: true,
+		Slack:   config.SlackChatOpsConfig{Enabled: true, SigningSecret: "shh", AllowedUsers: []string{"U1"}},
+	})
+
+	send := func(userID, timestamp, signature string) *httptest.ResponseRecorder {
+		form := url.Values{"command": {"/ack"}, "text": {"pve1-qemu-101-cpu"}, "user_id": {userID}, "user_name": {"alice"}}
+		body := form.Encode()
+		if signature == "" {
+			signature = signSlack("shh", timestamp, body)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/chatops/slack", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", signature)
+		rec := httptest.NewRecorder()
+		service.HandleSlack(rec, req)
+		return rec
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if rec := send("U1", now, "v0=deadbeef"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected bad signature to be rejected, got %d", rec.Code)
+	}
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	if rec := send("U1", stale, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected stale timestamp to be rejected, got %d", rec.Code)
+	}
+
+	rec := send("U2", now, "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "not allowed") || len(backend.acked) != 0 {
+		t.Fatalf("expected user outside allowlist to be refused, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = send("U1", now, "")
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !strings.HasPrefix(resp["text"], "Acknowledged") || backend.acked["pve1-qemu-101-cpu"] != "slack:alice" {
+		t.Fatalf("expected alert to be acknowledged, got %q", resp["text"])
+	}
+}
+
+func TestHandleDiscord(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	service, backend := newTestService(config.ChatOpsConfig{
+		Enabled: true,
+		Discord: config.DiscordChatOpsConfig{Enabled: true, PublicKey: hex.EncodeToString(publicKey), AllowedUsers: []string{"42"}},
+	})
+
+	send := func(body string, sign bool) *httptest.ResponseRecorder {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		signature := ed25519.Sign(privateKey, []byte(timestamp+body))
+		if !sign {
+			signature[0] ^= 0xff
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/chatops/discord", strings.NewReader(body))
+		req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+		req.Header.Set("X-Signature-Timestamp", timestamp)
+		rec := httptest.NewRecorder()
+		service.HandleDiscord(rec, req)
+		return rec
+	}
+
+	if rec := send(`{"type":1}`, false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected invalid signature to be rejected, got %d", rec.Code)
+	}
+	if rec := send(`{"type":1}`, true); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"type":1}` {
+		t.Fatalf("expected PONG, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec := send(`{"type":2,"member":{"user":{"id":"42","username":"bob"}},"data":{"name":"silence","options":[{"name":"resource","value":"db01"},{"name":"duration","value":"2h"}]}}`, true)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Silenced notifications for db01") {
+		t.Fatalf("unexpected silence response %d %s", rec.Code, rec.Body.String())
+	}
+	if len(backend.silenced) != 1 || backend.silenced[0].CreatedBy != "discord:bob" {
+		t.Fatalf("expected silence recorded for discord:bob, got %+v", backend.silenced)
+	}
+}
+
+func TestHandleTelegramWebhook(t *testing.T) {
+	service, backend := newTestService(config.ChatOpsConfig{
+		Enabled: true,
+		Telegram: config.TelegramChatOpsConfig{
+			Enabled:       true,
+			BotToken:      "123:abc",
+			Mode:          config.TelegramModeWebhook,
+			WebhookSecret: "0123456789abcdef",
+			AllowedUsers:  []string{"99"},
+		},
+	})
+
+	send := func(secret string) *httptest.ResponseRecorder {
+		body := `{"update_id":1,"message":{"message_id":7,"from":{"id":99,"username":"Carol"},"chat":{"id":-100},"text":"/ack@PulseBot pve1-qemu-102-memory"}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/chatops/telegram", strings.NewReader(body))
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+		rec := httptest.NewRecorder()
+		service.HandleTelegramWebhook(rec, req)
+		return rec
+	}
+
+	if rec := send("wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected wrong secret to be rejected, got %d", rec.Code)
+	}
+
+	rec := send("0123456789abcdef")
+	var reply telegramReply
+	if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("decode reply: %v", err)
+	}
+	if reply.Method != "sendMessage" || reply.ChatID != -100 || reply.ReplyToMessageID != 7 || !strings.HasPrefix(reply.Text, "Acknowledged") {
+		t.Fatalf("unexpected reply %+v", reply)
+	}
+	if backend.acked["pve1-qemu-102-memory"] != "telegram:Carol" {
+		t.Fatalf("expected acknowledgement by telegram:Carol, got %v", backend.acked)
+	}
+}
+
+func TestTelegramPolling(t *testing.T) {
+	sent := make(chan telegramReply, 1)
+	var served sync.Once
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			updates := "[]"
+			served.Do(func() {
+				updates = `[{"update_id":5,"message":{"message_id":1,"from":{"id":99},"chat":{"id":99},"text":"/alerts"}}]`
+			})
+			if updates == "[]" {
+				// Emulate long polling without holding the test open
+				select {
+				case <-r.Context().Done():
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+			fmt.Fprintf(w, `{"ok":true,"result":%s}`, updates)
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			var reply telegramReply
+			json.NewDecoder(r.Body).Decode(&reply)
+			sent <- reply
+			fmt.Fprint(w, `{"ok":true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	service := NewService(newFakeBackend())
+	service.telegramAPIURL = api.URL
+	service.SetConfig(config.ChatOpsConfig{
+		Enabled:  true,
+		Telegram: config.TelegramChatOpsConfig{Enabled: true, BotToken: "123:abc", AllowedUsers: []string{"99"}},
+	})
+	defer service.Stop()
+
+	select {
+	case reply := <-sent:
+		if reply.ChatID != 99 || !strings.HasPrefix(reply.Text, "2 active alerts") {
+			t.Fatalf("unexpected reply %+v", reply)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for polled command reply")
+	}
+}
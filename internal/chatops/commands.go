@@ -0,0 +1,230 @@
+package chatops
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+)
+
+// maxListedAlerts limits the /alerts reply so it fits in a single chat message
+const maxListedAlerts = 20
+
+// Backend performs the alert operations requested by chat commands.
+type Backend interface {
+	AcknowledgeAlert(alertID, user string) error
+	SilenceResource(resource string, duration time.Duration, user, reason string) (alerts.Silence, error)
+	ActiveAlerts() []alerts.Alert
+	GuestStatus(query string) (GuestStatus, bool)
+}
+
+// GuestStatus summarises a VM or container for the /status command.
+type GuestStatus struct {
+	ID          string
+	VMID        int
+	Name        string
+	Type        string
+	Node        string
+	Instance    string
+	Status      string
+	CPUPercent  float64
+	MemoryUsage float64
+	DiskUsage   float64
+	Uptime      time.Duration
+}
+
+// Command is a parsed chat command.
+type Command struct {
+	Name string
+	Args []string
+}
+
+// Caller identifies the chat user running a command.
+type Caller struct {
+	Platform string
+	UserID   string
+	Username string
+}
+
+// String returns the identity recorded for acknowledgements and silences.
+func (c Caller) String() string {
+	name := c.Username
+	if name == "" {
+		name = c.UserID
+	}
+	return c.Platform + ":" + name
+}
+
+// ParseCommand parses "/ack <id>" style text. A leading "pulse" word is accepted so a
+// single Slack slash command ("/pulse ack <id>") can route every command, and Telegram
+// "@botname" suffixes are removed.
+func ParseCommand(text string) (Command, bool) {
+	fields := strings.Fields(strings.TrimSpace(text))
+	if len(fields) == 0 {
+		return Command{}, false
+	}
+
+	name := strings.TrimPrefix(fields[0], "/")
+	if at := strings.Index(name, "@"); at >= 0 {
+		name = name[:at]
+	}
+	name = strings.ToLower(name)
+	args := fields[1:]
+
+	if name == "pulse" {
+		if len(args) == 0 {
+			return Command{Name: "help"}, true
+		}
+		name = strings.ToLower(strings.TrimPrefix(args[0], "/"))
+		args = args[1:]
+	}
+	if name == "" {
+		return Command{}, false
+	}
+	return Command{Name: name, Args: args}, true
+}
+
+// Execute runs a command for an allowed caller and returns the reply text.
+func Execute(backend Backend, caller Caller, cmd Command) string {
+	switch cmd.Name {
+	case "ack", "acknowledge":
+		return executeAck(backend, caller, cmd.Args)
+	case "silence", "mute":
+		return executeSilence(backend, caller, cmd.Args)
+	case "alerts":
+		return executeAlerts(backend)
+	case "status":
+		return executeStatus(backend, cmd.Args)
+	case "help", "start":
+		return helpText
+	default:
+		return fmt.Sprintf("Unknown command %q.\n\n%s", cmd.Name, helpText)
+	}
+}
+
+const helpText = `Pulse commands:
+/alerts - list active alerts
+/ack <alert-id> - acknowledge an alert
+/silence <resource> <duration> [reason] - silence notifications for a resource (e.g. 2h, 30m, 1d)
+/status <guest> - show a VM or container by name or VMID`
+
+func executeAck(backend Backend, caller Caller, args []string) string {
+	if len(args) != 1 {
+		return "Usage: /ack <alert-id>"
+	}
+	if err := backend.AcknowledgeAlert(args[0], caller.String()); err != nil {
+		return fmt.Sprintf("Could not acknowledge %s: %v", args[0], err)
+	}
+	return fmt.Sprintf("Acknowledged %s (by %s)", args[0], caller.String())
+}
+
+func executeSilence(backend Backend, caller Caller, args []string) string {
+	if len(args) < 2 {
+		return "Usage: /silence <resource> <duration> [reason]"
+	}
+	duration, err := ParseDuration(args[1])
+	if err != nil {
+		return fmt.Sprintf("Invalid duration %q: use values like 30m, 2h or 1d", args[1])
+	}
+
+	silence, err := backend.SilenceResource(args[0], duration, caller.String(), strings.Join(args[2:], " "))
+	if err != nil {
+		return fmt.Sprintf("Could not silence %s: %v", args[0], err)
+	}
+	return fmt.Sprintf("Silenced notifications for %s until %s (by %s)",
+		silence.Resource, silence.Until.UTC().Format("2006-01-02 15:04 MST"), caller.String())
+}
+
+func executeAlerts(backend Backend) string {
+	active := backend.ActiveAlerts()
+	if len(active) == 0 {
+		return "No active alerts."
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].Level != active[j].Level {
+			return active[i].Level == alerts.AlertLevelCritical
+		}
+		return active[i].StartTime.Before(active[j].StartTime)
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d active alert%s:", len(active), plural(len(active)))
+	for i, alert := range active {
+		if i == maxListedAlerts {
+			fmt.Fprintf(&b, "\n...and %d more", len(active)-maxListedAlerts)
+			break
+		}
+		ack := ""
+		if alert.Acknowledged {
+			ack = " [acked]"
+		}
+		fmt.Fprintf(&b, "\n[%s] %s: %s%s\n  id: %s",
+			strings.ToUpper(string(alert.Level)), alert.ResourceName, alert.Message, ack, alert.ID)
+	}
+	return b.String()
+}
+
+func executeStatus(backend Backend, args []string) string {
+	if len(args) != 1 {
+		return "Usage: /status <guest>"
+	}
+	guest, ok := backend.GuestStatus(args[0])
+	if !ok {
+		return fmt.Sprintf("No VM or container matches %q", args[0])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s %d) on %s: %s", guest.Name, guest.Type, guest.VMID, guest.Node, guest.Status)
+	if guest.Status == "running" {
+		fmt.Fprintf(&b, "\nCPU %.1f%% | Memory %.1f%% | Disk %.1f%% | Uptime %s",
+			guest.CPUPercent, guest.MemoryUsage, guest.DiskUsage, formatUptime(guest.Uptime))
+	}
+	return b.String()
+}
+
+// ParseDuration parses Go durations plus a "d" (day) suffix.
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(value, "d"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(value)
+}
+
+// isAllowed reports whether the caller's user ID appears in the allowlist.
+// Usernames are never matched since users can change them to impersonate someone else.
+func isAllowed(allowed []string, caller Caller) bool {
+	if caller.UserID == "" {
+		return false
+	}
+	for _, entry := range allowed {
+		if entry == caller.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(d.Minutes())%60)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,125 @@
+package chatops
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Discord interaction and response types
+const (
+	discordInteractionPing               = 1
+	discordInteractionApplicationCommand = 2
+
+	discordResponsePong                     = 1
+	discordResponseChannelMessageWithSource = 4
+)
+
+type discordUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+type discordInteraction struct {
+	Type   int `json:"type"`
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member,omitempty"`
+	User *discordUser `json:"user,omitempty"`
+	Data struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string      `json:"name"`
+			Value interface{} `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+// HandleDiscord handles Discord application command interactions.
+func (s *Service) HandleDiscord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg := s.GetConfig()
+	if !cfg.Enabled || !cfg.Discord.Enabled {
+		http.Error(w, "Discord commands are disabled", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	// Discord requires rejecting invalid signatures with 401, including its own probe requests
+	if !VerifyDiscordSignature(cfg.Discord.PublicKey, r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		http.Error(w, "Invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction discordInteraction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, "Invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch interaction.Type {
+	case discordInteractionPing:
+		json.NewEncoder(w).Encode(map[string]int{"type": discordResponsePong})
+		return
+	case discordInteractionApplicationCommand:
+	default:
+		http.Error(w, "Unsupported interaction type", http.StatusBadRequest)
+		return
+	}
+
+	caller := Caller{Platform: PlatformDiscord}
+	if interaction.Member != nil {
+		caller.UserID = interaction.Member.User.ID
+		caller.Username = interaction.Member.User.Username
+	} else if interaction.User != nil {
+		caller.UserID = interaction.User.ID
+		caller.Username = interaction.User.Username
+	}
+
+	// Options are passed positionally in the order they were registered
+	text := "/" + interaction.Data.Name
+	for _, option := range interaction.Data.Options {
+		text += " " + fmt.Sprint(option.Value)
+	}
+
+	reply, ok := s.run(cfg.Discord.AllowedUsers, caller, text)
+	if !ok {
+		reply = helpText
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type": discordResponseChannelMessageWithSource,
+		"data": map[string]string{"content": reply},
+	})
+}
+
+// VerifyDiscordSignature checks the Ed25519 signature Discord sends with each interaction.
+func VerifyDiscordSignature(publicKeyHex, signatureHex, timestamp string, body []byte) bool {
+	publicKey, err := hex.DecodeString(publicKeyHex)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	signature, err := hex.DecodeString(signatureHex)
+	if err != nil || len(signature) != ed25519.SignatureSize || timestamp == "" {
+		return false
+	}
+
+	message := make([]byte, 0, len(timestamp)+len(body))
+	message = append(message, timestamp...)
+	message = append(message, body...)
+	return ed25519.Verify(ed25519.PublicKey(publicKey), message, signature)
+}
@@ -0,0 +1,106 @@
+// Package chatops handles inbound chat commands from Telegram, Slack and Discord.
+package chatops
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// Platform names
+const (
+	PlatformTelegram = "telegram"
+	PlatformSlack    = "slack"
+	PlatformDiscord  = "discord"
+)
+
+// maxRequestBody bounds inbound webhook payloads
+const maxRequestBody = 64 * 1024
+
+// Service verifies inbound chat requests, checks the user allowlist and runs commands.
+type Service struct {
+	mu      sync.RWMutex
+	config  config.ChatOpsConfig
+	backend Backend
+
+	client         *http.Client
+	telegramAPIURL string
+	stopPolling    context.CancelFunc
+	pollingDone    chan struct{}
+}
+
+// NewService creates a chat-ops service for the given backend.
+func NewService(backend Backend) *Service {
+	return &Service{
+		backend:        backend,
+		config:         config.NormalizeChatOpsConfig(config.ChatOpsConfig{}),
+		client:         &http.Client{Timeout: 45 * time.Second},
+		telegramAPIURL: "https://api.telegram.org",
+	}
+}
+
+// SetConfig applies a new configuration and starts or stops Telegram long polling.
+func (s *Service) SetConfig(cfg config.ChatOpsConfig) {
+	cfg = config.NormalizeChatOpsConfig(cfg)
+
+	s.stopTelegramPolling()
+
+	s.mu.Lock()
+	s.config = cfg
+	s.mu.Unlock()
+
+	if cfg.Enabled && cfg.Telegram.Enabled && cfg.Telegram.Mode == config.TelegramModePolling && cfg.Telegram.BotToken != "" {
+		s.startTelegramPolling(cfg.Telegram)
+	}
+
+	log.Info().
+		Bool("enabled", cfg.Enabled).
+		Bool("telegram", cfg.Telegram.Enabled).
+		Str("telegramMode", cfg.Telegram.Mode).
+		Bool("slack", cfg.Slack.Enabled).
+		Bool("discord", cfg.Discord.Enabled).
+		Msg("Chat-ops configuration applied")
+}
+
+// GetConfig returns the active configuration.
+func (s *Service) GetConfig() config.ChatOpsConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// Stop shuts down background polling.
+func (s *Service) Stop() {
+	s.stopTelegramPolling()
+}
+
+// run checks the allowlist and executes a command text on behalf of a caller.
+func (s *Service) run(allowed []string, caller Caller, text string) (string, bool) {
+	cmd, ok := ParseCommand(text)
+	if !ok {
+		return "", false
+	}
+
+	if !isAllowed(allowed, caller) {
+		log.Warn().
+			Str("platform", caller.Platform).
+			Str("userID", caller.UserID).
+			Str("username", caller.Username).
+			Str("command", cmd.Name).
+			Msg("Rejected chat command from user not in allowlist")
+		return "You are not allowed to run Pulse commands.", true
+	}
+
+	log.Info().
+		Str("platform", caller.Platform).
+		Str("user", caller.String()).
+		Str("command", cmd.Name).
+		Strs("args", cmd.Args).
+		Msg("Running chat command")
+
+	return Execute(s.backend, caller, cmd), true
+}
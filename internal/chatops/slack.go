@@ -0,0 +1,87 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slackMaxSkew rejects replayed requests, as recommended by Slack
+const slackMaxSkew = 5 * time.Minute
+
+// HandleSlack handles Slack slash command requests.
+func (s *Service) HandleSlack(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg := s.GetConfig()
+	if !cfg.Enabled || !cfg.Slack.Enabled {
+		http.Error(w, "Slack commands are disabled", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	if !VerifySlackSignature(cfg.Slack.SigningSecret, r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body, time.Now()) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Invalid form body", http.StatusBadRequest)
+		return
+	}
+
+	caller := Caller{
+		Platform: PlatformSlack,
+		UserID:   form.Get("user_id"),
+		Username: form.Get("user_name"),
+	}
+	text := strings.TrimSpace(form.Get("command") + " " + form.Get("text"))
+
+	reply, ok := s.run(cfg.Slack.AllowedUsers, caller, text)
+	if !ok {
+		reply = helpText
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"response_type": "in_channel",
+		"text":          reply,
+	})
+}
+
+// VerifySlackSignature checks the v0 request signature Slack sends with each request.
+func VerifySlackSignature(secret, timestamp, signature string, body []byte, now time.Time) bool {
+	if secret == "" || timestamp == "" || !strings.HasPrefix(signature, "v0=") {
+		return false
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if math.Abs(now.Sub(time.Unix(ts, 0)).Seconds()) > slackMaxSkew.Seconds() {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
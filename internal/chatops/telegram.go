@@ -0,0 +1,230 @@
+package chatops
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+// Telegram long polling settings
+const (
+	telegramPollTimeout  = 30 // seconds the Bot API holds getUpdates open
+	telegramRetryBackoff = 5 * time.Second
+)
+
+type telegramUpdate struct {
+	UpdateID int64            `json:"update_id"`
+	Message  *telegramMessage `json:"message,omitempty"`
+}
+
+type telegramMessage struct {
+	MessageID int64 `json:"message_id"`
+	From      *struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"from,omitempty"`
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+// telegramReply is a sendMessage call, returned inline for webhooks or posted when polling
+type telegramReply struct {
+	Method           string `json:"method,omitempty"`
+	ChatID           int64  `json:"chat_id"`
+	Text             string `json:"text"`
+	ReplyToMessageID int64  `json:"reply_to_message_id,omitempty"`
+}
+
+// HandleTelegramWebhook handles updates pushed by the Telegram Bot API.
+func (s *Service) HandleTelegramWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg := s.GetConfig()
+	if !cfg.Enabled || !cfg.Telegram.Enabled || cfg.Telegram.Mode != config.TelegramModeWebhook {
+		http.Error(w, "Telegram webhook is disabled", http.StatusNotFound)
+		return
+	}
+
+	secret := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+	if cfg.Telegram.WebhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(cfg.Telegram.WebhookSecret)) != 1 {
+		http.Error(w, "Invalid secret token", http.StatusUnauthorized)
+		return
+	}
+
+	var update telegramUpdate
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestBody)).Decode(&update); err != nil {
+		http.Error(w, "Invalid update", http.StatusBadRequest)
+		return
+	}
+
+	reply, ok := s.handleTelegramUpdate(cfg.Telegram, update)
+	if !ok {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Replying with a method call saves a round trip to the Bot API
+	reply.Method = "sendMessage"
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// handleTelegramUpdate runs the command in an update and returns the reply to send.
+func (s *Service) handleTelegramUpdate(cfg config.TelegramChatOpsConfig, update telegramUpdate) (telegramReply, bool) {
+	msg := update.Message
+	if msg == nil || msg.From == nil || !strings.HasPrefix(msg.Text, "/") {
+		return telegramReply{}, false
+	}
+
+	caller := Caller{
+		Platform: PlatformTelegram,
+		UserID:   strconv.FormatInt(msg.From.ID, 10),
+		Username: msg.From.Username,
+	}
+	text, ok := s.run(cfg.AllowedUsers, caller, msg.Text)
+	if !ok {
+		return telegramReply{}, false
+	}
+
+	return telegramReply{
+		ChatID:           msg.Chat.ID,
+		Text:             text,
+		ReplyToMessageID: msg.MessageID,
+	}, true
+}
+
+func (s *Service) startTelegramPolling(cfg config.TelegramChatOpsConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	s.mu.Lock()
+	s.stopPolling = cancel
+	s.pollingDone = done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		s.pollTelegram(ctx, cfg)
+	}()
+	log.Info().Msg("Started Telegram chat command polling")
+}
+
+func (s *Service) stopTelegramPolling() {
+	s.mu.Lock()
+	cancel := s.stopPolling
+	done := s.pollingDone
+	s.stopPolling = nil
+	s.pollingDone = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	log.Info().Msg("Stopped Telegram chat command polling")
+}
+
+func (s *Service) pollTelegram(ctx context.Context, cfg config.TelegramChatOpsConfig) {
+	var offset int64
+	for {
+		updates, err := s.getTelegramUpdates(ctx, cfg.BotToken, offset)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to fetch Telegram updates")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(telegramRetryBackoff):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			reply, ok := s.handleTelegramUpdate(cfg, update)
+			if !ok {
+				continue
+			}
+			if err := s.sendTelegramMessage(ctx, cfg.BotToken, reply); err != nil {
+				log.Warn().Err(err).Int64("chatID", reply.ChatID).Msg("Failed to send Telegram reply")
+			}
+		}
+	}
+}
+
+func (s *Service) getTelegramUpdates(ctx context.Context, token string, offset int64) ([]telegramUpdate, error) {
+	params := url.Values{}
+	params.Set("timeout", strconv.Itoa(telegramPollTimeout))
+	params.Set("offset", strconv.FormatInt(offset, 10))
+	params.Set("allowed_updates", `["message"]`)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.telegramMethodURL(token, "getUpdates")+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool             `json:"ok"`
+		Description string           `json:"description"`
+		Result      []telegramUpdate `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid getUpdates response: %w", err)
+	}
+	if !result.OK {
+		// 409 means a webhook is still registered for the bot
+		return nil, fmt.Errorf("getUpdates failed (status %d): %s", resp.StatusCode, result.Description)
+	}
+	return result.Result, nil
+}
+
+func (s *Service) sendTelegramMessage(ctx context.Context, token string, reply telegramReply) error {
+	payload, err := json.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.telegramMethodURL(token, "sendMessage"), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("sendMessage returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (s *Service) telegramMethodURL(token, method string) string {
+	return s.telegramAPIURL + "/bot" + token + "/" + method
+}
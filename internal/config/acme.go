@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ACMEChallengeHTTP01 proves control of a domain by serving a token over HTTP on port 80
+	ACMEChallengeHTTP01 = "http-01"
+	// ACMEChallengeDNS01 proves control of a domain with a TXT record created by a DNS provider
+	ACMEChallengeDNS01 = "dns-01"
+
+	// DefaultACMEDirectoryURL is the Let's Encrypt production directory
+	DefaultACMEDirectoryURL = "https://acme-v02.api.letsencrypt.org/directory"
+	// DefaultACMERenewBefore renews certificates 30 days before they expire
+	DefaultACMERenewBefore = 30 * 24 * time.Hour
+	// DefaultACMEDNSPropagationTimeout bounds the wait for a challenge TXT record to be visible
+	DefaultACMEDNSPropagationTimeout = 2 * time.Minute
+
+	acmeDNSOptionPrefix = "ACME_DNS_"
+)
+
+// ACMEConfig configures automatic certificates from an ACME certificate authority. It is read
+// from ACME_* environment variables.
+type ACMEConfig struct {
+	Enabled      bool
+	Email        string
+	DirectoryURL string
+	// Domains defaults to the host of the public URL
+	Domains     []string
+	Challenge   string
+	HTTPAddress string
+	// DNSProvider names a registered DNS-01 provider; DNSOptions holds its ACME_DNS_<KEY>
+	// settings keyed by lowercase <key>
+	DNSProvider           string
+	DNSOptions            map[string]string
+	DNSPropagationTimeout time.Duration
+	// CAFile is a PEM bundle trusted for the directory, e.g. for a local test CA
+	CAFile      string
+	RenewBefore time.Duration
+}
+
+// LoadACMEConfigFromEnv reads the ACME_* environment variables. publicURL supplies the default
+// domain.
+func LoadACMEConfigFromEnv(publicURL string) (ACMEConfig, error) {
+	cfg := ACMEConfig{
+		DirectoryURL:          DefaultACMEDirectoryURL,
+		Challenge:             ACMEChallengeHTTP01,
+		HTTPAddress:           ":80",
+		DNSOptions:            make(map[string]string),
+		DNSPropagationTimeout: DefaultACMEDNSPropagationTimeout,
+		RenewBefore:           DefaultACMERenewBefore,
+	}
+	if value := strings.TrimSpace(os.Getenv("ACME_ENABLED")); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid ACME_ENABLED %q", value)
+		}
+		cfg.Enabled = enabled
+	}
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+
+	cfg.Email = strings.TrimSpace(os.Getenv("ACME_EMAIL"))
+	if value := strings.TrimSpace(os.Getenv("ACME_DIRECTORY_URL")); value != "" {
+		cfg.DirectoryURL = value
+	}
+	for _, domain := range strings.Split(os.Getenv("ACME_DOMAINS"), ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			cfg.Domains = append(cfg.Domains, domain)
+		}
+	}
+	if len(cfg.Domains) == 0 && publicURL != "" {
+		if u, err := url.Parse(publicURL); err == nil && u.Hostname() != "" && net.ParseIP(u.Hostname()) == nil {
+			cfg.Domains = []string{strings.ToLower(u.Hostname())}
+		}
+	}
+	if value := strings.ToLower(strings.TrimSpace(os.Getenv("ACME_CHALLENGE"))); value != "" {
+		cfg.Challenge = value
+	}
+	if value, ok := os.LookupEnv("ACME_HTTP_ADDR"); ok {
+		cfg.HTTPAddress = strings.TrimSpace(value)
+	}
+	cfg.DNSProvider = strings.ToLower(strings.TrimSpace(os.Getenv("ACME_DNS_PROVIDER")))
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, acmeDNSOptionPrefix) || key == "ACME_DNS_PROVIDER" || key == "ACME_DNS_PROPAGATION_TIMEOUT" {
+			continue
+		}
+		cfg.DNSOptions[strings.ToLower(strings.TrimPrefix(key, acmeDNSOptionPrefix))] = strings.TrimSpace(value)
+	}
+	cfg.CAFile = strings.TrimSpace(os.Getenv("ACME_CA_FILE"))
+	for name, target := range map[string]*time.Duration{
+		"ACME_RENEW_BEFORE":            &cfg.RenewBefore,
+		"ACME_DNS_PROPAGATION_TIMEOUT": &cfg.DNSPropagationTimeout,
+	} {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			return cfg, fmt.Errorf("invalid %s %q", name, value)
+		}
+		*target = duration
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate returns an error for an unusable ACME setup
+func (c ACMEConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Domains) == 0 {
+		return fmt.Errorf("ACME_DOMAINS is required when PULSE_PUBLIC_URL does not name a domain")
+	}
+	if u, err := url.Parse(c.DirectoryURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("ACME_DIRECTORY_URL must be an https URL")
+	}
+	switch c.Challenge {
+	case ACMEChallengeHTTP01:
+		if c.HTTPAddress == "" {
+			return fmt.Errorf("ACME_HTTP_ADDR is required for the http-01 challenge")
+		}
+		for _, domain := range c.Domains {
+			if strings.HasPrefix(domain, "*.") {
+				return fmt.Errorf("wildcard domain %s requires the dns-01 challenge", domain)
+			}
+		}
+	case ACMEChallengeDNS01:
+		if c.DNSProvider == "" {
+			return fmt.Errorf("ACME_DNS_PROVIDER is required for the dns-01 challenge")
+		}
+	default:
+		return fmt.Errorf("ACME_CHALLENGE must be %q or %q", ACMEChallengeHTTP01, ACMEChallengeDNS01)
+	}
+	return nil
+}
+
+// ACMECertPaths returns where ACME certificates are kept inside the data directory
+func ACMECertPaths(dataDir string) (certFile, keyFile string) {
+	dir := filepath.Join(dataDir, "acme")
+	return filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadACMEConfigFromEnv(t *testing.T) {
+	t.Setenv("ACME_ENABLED", "false")
+	if cfg, err := LoadACMEConfigFromEnv("https://pulse.example.com"); err != nil || cfg.Enabled {
+		t.Fatalf("disabled = %+v, %v", cfg, err)
+	}
+
+	t.Setenv("ACME_ENABLED", "true")
+	cfg, err := LoadACMEConfigFromEnv("https://Pulse.Example.com:7655/")
+	if err != nil {
+		t.Fatalf("LoadACMEConfigFromEnv: %v", err)
+	}
+	if len(cfg.Domains) != 1 || cfg.Domains[0] != "pulse.example.com" || cfg.Challenge != ACMEChallengeHTTP01 ||
+		cfg.DirectoryURL != DefaultACMEDirectoryURL || cfg.RenewBefore != DefaultACMERenewBefore {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+	if _, err := LoadACMEConfigFromEnv("https://192.168.1.10:7655"); err == nil {
+		t.Fatal("expected an IP public URL without ACME_DOMAINS to be rejected")
+	}
+
+	t.Setenv("ACME_DOMAINS", "*.example.com, example.com")
+	if _, err := LoadACMEConfigFromEnv(""); err == nil {
+		t.Fatal("expected a wildcard domain to require dns-01")
+	}
+	t.Setenv("ACME_CHALLENGE", "dns-01")
+	if _, err := LoadACMEConfigFromEnv(""); err == nil {
+		t.Fatal("expected dns-01 to require ACME_DNS_PROVIDER")
+	}
+	t.Setenv("ACME_DNS_PROVIDER", "Exec")
+	t.Setenv("ACME_DNS_EXEC_PATH", "/usr/local/bin/dns-hook")
+	t.Setenv("ACME_DNS_PROPAGATION_TIMEOUT", "30s")
+	cfg, err = LoadACMEConfigFromEnv("")
+	if err != nil {
+		t.Fatalf("LoadACMEConfigFromEnv: %v", err)
+	}
+	if cfg.DNSProvider != "exec" || cfg.DNSOptions["exec_path"] != "/usr/local/bin/dns-hook" ||
+		cfg.DNSPropagationTimeout != 30*time.Second || len(cfg.Domains) != 2 {
+		t.Fatalf("unexpected dns-01 config: %+v", cfg)
+	}
+	if _, ok := cfg.DNSOptions["provider"]; ok {
+		t.Fatal("ACME_DNS_PROVIDER must not be passed as a provider option")
+	}
+
+	t.Setenv("ACME_DIRECTORY_URL", "http://localhost:14000/dir")
+	if _, err := LoadACMEConfigFromEnv(""); err == nil {
+		t.Fatal("expected a plain http directory to be rejected")
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultBackupRemediationConcurrency is how many remediation backups may run per node
+	DefaultBackupRemediationConcurrency = 1
+	// DefaultBackupRemediationCooldownHours is the minimum gap between remediation attempts for a guest
+	DefaultBackupRemediationCooldownHours = 12
+	maxBackupRemediationConcurrency       = 8
+)
+
+// BackupRemediationConfig starts a vzdump backup automatically when a guest's
+// backup-age alert reaches the critical threshold.
+type BackupRemediationConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Storage  string `json:"storage,omitempty"`  // Empty uses the node's vzdump.conf default
+	Mode     string `json:"mode,omitempty"`     // snapshot, suspend or stop
+	Compress string `json:"compress,omitempty"` // 0, gzip, lzo or zstd
+	// MaxConcurrentPerNode caps remediation backups running at once on a single node
+	MaxConcurrentPerNode int `json:"maxConcurrentPerNode"`
+	CooldownHours        int `json:"cooldownHours"`
+	// Exclusions lists guest IDs that are never backed up automatically
+	Exclusions []string `json:"exclusions,omitempty"`
+}
+
+// NormalizeBackupRemediationConfig cleans remediation values and applies defaults.
+func NormalizeBackupRemediationConfig(cfg BackupRemediationConfig) BackupRemediationConfig {
+	normalized := cfg
+	normalized.Storage = strings.TrimSpace(normalized.Storage)
+	normalized.Mode = strings.ToLower(strings.TrimSpace(normalized.Mode))
+	normalized.Compress = strings.ToLower(strings.TrimSpace(normalized.Compress))
+	if normalized.MaxConcurrentPerNode <= 0 {
+		normalized.MaxConcurrentPerNode = DefaultBackupRemediationConcurrency
+	}
+	if normalized.CooldownHours <= 0 {
+		normalized.CooldownHours = DefaultBackupRemediationCooldownHours
+	}
+	normalized.Exclusions = normalizeStringList(normalized.Exclusions, false)
+	return normalized
+}
+
+// Validate returns an error for settings vzdump would reject.
+func (c BackupRemediationConfig) Validate() error {
+	switch c.Mode {
+	case "", "snapshot", "suspend", "stop":
+	default:
+		return fmt.Errorf("unsupported backup mode %q", c.Mode)
+	}
+	switch c.Compress {
+	case "", "0", "gzip", "lzo", "zstd":
+	default:
+		return fmt.Errorf("unsupported compression %q", c.Compress)
+	}
+	if c.MaxConcurrentPerNode > maxBackupRemediationConcurrency {
+		return fmt.Errorf("maxConcurrentPerNode must be at most %d", maxBackupRemediationConcurrency)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Telegram inbound modes
+const (
+	TelegramModePolling = "polling"
+	TelegramModeWebhook = "webhook"
+)
+
+// ChatOpsConfig configures inbound chat commands (acknowledge, silence and query alerts).
+// Each platform must list the chat users allowed to run commands; an empty allowlist
+// rejects everyone.
+type ChatOpsConfig struct {
+	Enabled  bool                  `json:"enabled"`
+	Telegram TelegramChatOpsConfig `json:"telegram"`
+	Slack    SlackChatOpsConfig    `json:"slack"`
+	Discord  DiscordChatOpsConfig  `json:"discord"`
+}
+
+// TelegramChatOpsConfig receives bot commands via long polling or a webhook.
+type TelegramChatOpsConfig struct {
+	Enabled       bool     `json:"enabled"`
+	BotToken      string   `json:"botToken,omitempty"`
+	Mode          string   `json:"mode"`                    // polling or webhook
+	WebhookSecret string   `json:"webhookSecret,omitempty"` // Compared with X-Telegram-Bot-Api-Secret-Token
+	AllowedUsers  []string `json:"allowedUsers"`            // Telegram user IDs
+}
+
+// SlackChatOpsConfig receives slash commands verified with the app signing secret.
+type SlackChatOpsConfig struct {
+	Enabled       bool     `json:"enabled"`
+	SigningSecret string   `json:"signingSecret,omitempty"`
+	AllowedUsers  []string `json:"allowedUsers"` // Slack user IDs
+}
+
+// DiscordChatOpsConfig receives application command interactions verified with the app public key.
+type DiscordChatOpsConfig struct {
+	Enabled      bool     `json:"enabled"`
+	PublicKey    string   `json:"publicKey,omitempty"` // Hex encoded Ed25519 application public key
+	AllowedUsers []string `json:"allowedUsers"`        // Discord user IDs
+}
+
+// NormalizeChatOpsConfig cleans chat-ops values and applies defaults.
+func NormalizeChatOpsConfig(cfg ChatOpsConfig) ChatOpsConfig {
+	normalized := cfg
+
+	normalized.Telegram.BotToken = strings.TrimSpace(normalized.Telegram.BotToken)
+	normalized.Telegram.WebhookSecret = strings.TrimSpace(normalized.Telegram.WebhookSecret)
+	if strings.ToLower(strings.TrimSpace(normalized.Telegram.Mode)) == TelegramModeWebhook {
+		normalized.Telegram.Mode = TelegramModeWebhook
+	} else {
+		normalized.Telegram.Mode = TelegramModePolling
+	}
+	normalized.Telegram.AllowedUsers = normalizeChatUsers(normalized.Telegram.AllowedUsers)
+
+	normalized.Slack.SigningSecret = strings.TrimSpace(normalized.Slack.SigningSecret)
+	normalized.Slack.AllowedUsers = normalizeChatUsers(normalized.Slack.AllowedUsers)
+
+	normalized.Discord.PublicKey = strings.ToLower(strings.TrimSpace(normalized.Discord.PublicKey))
+	normalized.Discord.AllowedUsers = normalizeChatUsers(normalized.Discord.AllowedUsers)
+
+	return normalized
+}
+
+// Validate returns an error if an enabled platform is missing the credentials it needs.
+func (c ChatOpsConfig) Validate() error {
+	if c.Telegram.Enabled {
+		if c.Telegram.BotToken == "" {
+			return fmt.Errorf("telegram bot token is required")
+		}
+		if c.Telegram.Mode == TelegramModeWebhook && len(c.Telegram.WebhookSecret) < 16 {
+			return fmt.Errorf("telegram webhook secret must be at least 16 characters")
+		}
+	}
+	if c.Slack.Enabled && c.Slack.SigningSecret == "" {
+		return fmt.Errorf("slack signing secret is required")
+	}
+	if c.Discord.Enabled {
+		key, err := hex.DecodeString(c.Discord.PublicKey)
+		if err != nil || len(key) != 32 {
+			return fmt.Errorf("discord public key must be a 32-byte hex encoded Ed25519 key")
+		}
+	}
+	return nil
+}
+
+func normalizeChatUsers(users []string) []string {
+	normalized := make([]string, 0, len(users))
+	seen := make(map[string]bool, len(users))
+	for _, user := range users {
+		user = strings.TrimPrefix(strings.TrimSpace(user), "@")
+		if user == "" || seen[strings.ToLower(user)] {
+			continue
+		}
+		seen[strings.ToLower(user)] = true
+		normalized = append(normalized, user)
+	}
+	return normalized
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"strings"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/RouXx67/PulseUp/pkg/pbs"
 	"github.com/RouXx67/PulseUp/pkg/pmg"
 	"github.com/RouXx67/PulseUp/pkg/proxmox"
@@ -18,9 +20,9 @@ func CreateProxmoxConfig(node *PVEInstance) proxmox.ClientConfig {
 	return proxmox.ClientConfig{
 		Host:        node.Host,
 		User:        user,
-		Password:    node.Password,
+		Password:    resolveClientSecret(node.Password, node.Name+".password"),
 		TokenName:   node.TokenName,
-		TokenValue:  node.TokenValue,
+		TokenValue:  resolveClientSecret(node.TokenValue, node.Name+".token_value"),
 		VerifySSL:   node.VerifySSL,
 		Fingerprint: node.Fingerprint,
 	}
@@ -31,9 +33,9 @@ func CreatePBSConfig(node *PBSInstance) pbs.ClientConfig {
 	return pbs.ClientConfig{
 		Host:        node.Host,
 		User:        node.User,
-		Password:    node.Password,
+		Password:    resolveClientSecret(node.Password, node.Name+".password"),
 		TokenName:   node.TokenName,
-		TokenValue:  node.TokenValue,
+		TokenValue:  resolveClientSecret(node.TokenValue, node.Name+".token_value"),
 		VerifySSL:   node.VerifySSL,
 		Fingerprint: node.Fingerprint,
 	}
@@ -44,9 +46,9 @@ func CreatePMGConfig(node *PMGInstance) pmg.ClientConfig {
 	return pmg.ClientConfig{
 		Host:        node.Host,
 		User:        node.User,
-		Password:    node.Password,
+		Password:    resolveClientSecret(node.Password, node.Name+".password"),
 		TokenName:   node.TokenName,
-		TokenValue:  node.TokenValue,
+		TokenValue:  resolveClientSecret(node.TokenValue, node.Name+".token_value"),
 		VerifySSL:   node.VerifySSL,
 		Fingerprint: node.Fingerprint,
 	}
@@ -61,9 +63,9 @@ func CreateProxmoxConfigFromFields(host, user, password, tokenName, tokenValue,
 	return proxmox.ClientConfig{
 		Host:        host,
 		User:        user,
-		Password:    password,
+		Password:    resolveClientSecret(password, host+".password"),
 		TokenName:   tokenName,
-		TokenValue:  tokenValue,
+		TokenValue:  resolveClientSecret(tokenValue, host+".token_value"),
 		VerifySSL:   verifySSL,
 		Fingerprint: fingerprint,
 	}
@@ -74,9 +76,9 @@ func CreatePBSConfigFromFields(host, user, password, tokenName, tokenValue, fing
 	return pbs.ClientConfig{
 		Host:        host,
 		User:        user,
-		Password:    password,
+		Password:    resolveClientSecret(password, host+".password"),
 		TokenName:   tokenName,
-		TokenValue:  tokenValue,
+		TokenValue:  resolveClientSecret(tokenValue, host+".token_value"),
 		VerifySSL:   verifySSL,
 		Fingerprint: fingerprint,
 	}
@@ -87,10 +89,23 @@ func CreatePMGConfigFromFields(host, user, password, tokenName, tokenValue, fing
 	return pmg.ClientConfig{
 		Host:        host,
 		User:        user,
-		Password:    password,
+		Password:    resolveClientSecret(password, host+".password"),
 		TokenName:   tokenName,
-		TokenValue:  tokenValue,
+		TokenValue:  resolveClientSecret(tokenValue, host+".token_value"),
 		VerifySSL:   verifySSL,
 		Fingerprint: fingerprint,
 	}
 }
+
+// resolveClientSecret resolves a secret backend reference (vault://, sops://, systemd-creds://) for a
+// client. Node credentials come from the API, so environment and file references are not expanded.
+// References stay in the stored configuration; a failure leaves the credential empty so the node
+// shows as disconnected.
+func resolveClientSecret(value, field string) string {
+	resolved, err := DefaultCredentialResolver().ResolveReference(value, field)
+	if err != nil {
+		log.Error().Err(err).Str("field", field).Msg("Failed to resolve node credential")
+		return ""
+	}
+	return resolved
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 	"github.com/RouXx67/PulseUp/internal/auth"
+	"github.com/RouXx67/PulseUp/internal/crypto"
 	"github.com/RouXx67/PulseUp/internal/logging"
 	"github.com/rs/zerolog/log"
 )
@@ -119,22 +120,38 @@ type Config struct {
 
 	// OIDC configuration
 	OIDC *OIDCConfig `json:"-"`
+
+	// Tenant scopes restricting users, proxy roles and API tokens to pools, tags or instances
+	TenantScopes TenantScopesConfig `json:"-"`
 	// HTTPS/TLS settings
 	HTTPSEnabled bool   `envconfig:"HTTPS_ENABLED" default:"false"`
 	TLSCertFile  string `envconfig:"TLS_CERT_FILE" default:""`
 	TLSKeyFile   string `envconfig:"TLS_KEY_FILE" default:""`
 
+	// Automatic certificates, from ACME_* environment variables
+	ACME ACMEConfig `json:"-"`
+
 	// Update settings
 	UpdateChannel           string        `envconfig:"UPDATE_CHANNEL" default:"stable"`
 	AutoUpdateEnabled       bool          `envconfig:"AUTO_UPDATE_ENABLED" default:"false"`
 	AutoUpdateCheckInterval time.Duration `envconfig:"AUTO_UPDATE_CHECK_INTERVAL" default:"24h"`
 	AutoUpdateTime          string        `envconfig:"AUTO_UPDATE_TIME" default:"03:00"`
+	AutoUpdateWindowEnd     string        `envconfig:"AUTO_UPDATE_WINDOW_END" default:"05:00"` // Automatic installs run between AutoUpdateTime and this time
+	AutoUpdateRollback      bool          `envconfig:"AUTO_UPDATE_ROLLBACK" default:"true"`    // Restore the previous version when the health check fails
+	AutoUpdateRolloutHours  int           `envconfig:"AUTO_UPDATE_ROLLOUT_HOURS" default:"24"` // Spread automatic installs over this many hours after release
+	UpdateSource            string        `envconfig:"UPDATE_SOURCE"`                          // Release mirror base URL; empty uses GitHub
 
 	// Discovery settings
 	DiscoveryEnabled bool            `envconfig:"DISCOVERY_ENABLED" default:"false"`
 	DiscoverySubnet  string          `envconfig:"DISCOVERY_SUBNET" default:"auto"`
 	Discovery        DiscoveryConfig `json:"discoveryConfig"`
 
+	// Active/passive high availability, from PULSE_HA_* environment variables
+	HA HAConfig `json:"-"`
+
+	// OpenTelemetry tracing, from PULSE_TRACING_* environment variables
+	Tracing TracingConfig `json:"-"`
+
 	// Deprecated - for backward compatibility
 	Port  int  `envconfig:"PORT"` // Maps to BackendPort
 	Debug bool `envconfig:"DEBUG" default:"false"`
@@ -388,6 +405,10 @@ type PVEInstance struct {
 	MonitorPhysicalDisks       *bool // Monitor physical disks (nil = enabled by default, can be explicitly disabled)
 	PhysicalDiskPollingMinutes int   // How often to poll physical disks (0 = use default)
 
+	// Polling scope
+	PollIntervalSeconds int               // Fixed poll interval overriding the adaptive scheduler (0 = scheduler decides)
+	Filters             PVEResourceFilter // Nodes, guests and storage left out of monitoring
+
 	// Cluster support
 	IsCluster        bool              // True if this is a cluster
 	ClusterName      string            // Cluster name if applicable
@@ -513,6 +534,8 @@ func Load() (*Config, error) {
 		PMGPollingInterval:    60 * time.Second, // Default PMG polling (aggregated stats)
 		DiscoveryEnabled:      false,
 		DiscoverySubnet:       "auto",
+		AutoUpdateRollback:     true,
+		AutoUpdateRolloutHours: 24,
 		EnvOverrides:          make(map[string]bool),
 	OIDC:                  NewOIDCConfig(),
 	}
@@ -521,6 +544,9 @@ func Load() (*Config, error) {
 
 	// Initialize persistence
 	persistence := NewConfigPersistence(dataDir)
+	if err := persistence.EncryptionError(); errors.Is(err, crypto.ErrMasterKeyLocked) {
+		return nil, fmt.Errorf("cannot unlock encryption keyring: %w", err)
+	}
 	if persistence != nil {
 		// Store global persistence for saving
 		globalPersistence = persistence
@@ -579,6 +605,16 @@ func Load() (*Config, error) {
 			if systemSettings.AutoUpdateTime != "" {
 				cfg.AutoUpdateTime = systemSettings.AutoUpdateTime
 			}
+			if systemSettings.AutoUpdateWindowEnd != "" {
+				cfg.AutoUpdateWindowEnd = systemSettings.AutoUpdateWindowEnd
+			}
+			if systemSettings.AutoUpdateRollback != nil {
+				cfg.AutoUpdateRollback = *systemSettings.AutoUpdateRollback
+			}
+			if systemSettings.AutoUpdateRolloutHours != nil {
+				cfg.AutoUpdateRolloutHours = *systemSettings.AutoUpdateRolloutHours
+			}
+			cfg.UpdateSource = systemSettings.UpdateSource
 			if systemSettings.AllowedOrigins != "" {
 				cfg.AllowedOrigins = systemSettings.AllowedOrigins
 			}
@@ -625,6 +661,13 @@ func Load() (*Config, error) {
 		log.Warn().Err(err).Msg("Failed to load API tokens from persistence")
 	}
 
+	// Load tenant scopes
+	if scopes, err := persistence.LoadTenantScopesConfig(); err == nil {
+		cfg.TenantScopes = *scopes
+	} else {
+		log.Warn().Err(err).Msg("Failed to load tenant scopes from persistence")
+	}
+
 	// Ensure PBS polling interval has default if not set
 	// Note: PVE polling is hardcoded to 10s in monitor.go
 	if cfg.PBSPollingInterval == 0 {
@@ -1054,6 +1097,11 @@ func Load() (*Config, error) {
 		cfg.EnvOverrides["logLevel"] = true
 		log.Info().Str("level", logLevel).Msg("Log level overridden by LOG_LEVEL env var")
 	}
+	if updateSource := strings.TrimSpace(os.Getenv("UPDATE_SOURCE")); updateSource != "" {
+		cfg.UpdateSource = updateSource
+		cfg.EnvOverrides["updateSource"] = true
+		log.Info().Str("source", updateSource).Msg("Update source overridden by UPDATE_SOURCE env var")
+	}
 	if logFormat := os.Getenv("LOG_FORMAT"); logFormat != "" {
 		cfg.LogFormat = logFormat
 		cfg.EnvOverrides["logFormat"] = true
@@ -1089,6 +1137,31 @@ func Load() (*Config, error) {
 		}
 	}
 
+	haConfig, err := LoadHAConfigFromEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "load config: invalid high availability settings")
+	}
+	cfg.HA = haConfig
+
+	acmeConfig, err := LoadACMEConfigFromEnv(cfg.PublicURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "load config: invalid ACME settings")
+	}
+	cfg.ACME = acmeConfig
+
+	tracingConfig, err := LoadTracingConfigFromEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "load config: invalid tracing settings")
+	}
+	cfg.Tracing = tracingConfig
+	if cfg.ACME.Enabled {
+		// ACME keeps the certificate in the configured files, or in the data directory
+		cfg.HTTPSEnabled = true
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			cfg.TLSCertFile, cfg.TLSKeyFile = ACMECertPaths(dataDir)
+		}
+	}
+
 	cfg.OIDC.ApplyDefaults(cfg.PublicURL)
 
 	// Initialize logging with configuration values
@@ -1119,12 +1192,18 @@ func SaveConfig(cfg *Config) error {
 
 	// Save system configuration
 	adaptiveEnabled := cfg.AdaptivePollingEnabled
+	autoUpdateRollback := cfg.AutoUpdateRollback
+	autoUpdateRolloutHours := cfg.AutoUpdateRolloutHours
 	systemSettings := SystemSettings{
 		// Note: PVE polling is hardcoded to 10s
 		UpdateChannel:                 cfg.UpdateChannel,
 		AutoUpdateEnabled:             cfg.AutoUpdateEnabled,
 		AutoUpdateCheckInterval:       int(cfg.AutoUpdateCheckInterval.Hours()),
 		AutoUpdateTime:                cfg.AutoUpdateTime,
+		AutoUpdateWindowEnd:           cfg.AutoUpdateWindowEnd,
+		AutoUpdateRollback:            &autoUpdateRollback,
+		AutoUpdateRolloutHours:        &autoUpdateRolloutHours,
+		UpdateSource:                  cfg.UpdateSource,
 		AllowedOrigins:                cfg.AllowedOrigins,
 		ConnectionTimeout:             int(cfg.ConnectionTimeout.Seconds()),
 		LogLevel:                      cfg.LogLevel,
@@ -1204,6 +1283,9 @@ func (c *Config) Validate() error {
 		if pve.Password == "" && (pve.TokenName == "" || pve.TokenValue == "") {
 			return fmt.Errorf("PVE instance %d: either password or token authentication is required", i+1)
 		}
+		if err := pve.ValidatePollingOptions(); err != nil {
+			return fmt.Errorf("PVE instance %d: %w", i+1, err)
+		}
 	}
 
 	// Validate and auto-fix PBS instances
@@ -5,28 +5,109 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/rs/zerolog/log"
 )
 
+// SecretBackend resolves references of one URI scheme, e.g. vault://. The reference is passed
+// without the scheme prefix.
+type SecretBackend interface {
+	Resolve(ref string) (string, error)
+}
+
 // CredentialResolver handles resolving credential values from various sources
 type CredentialResolver struct {
+	mu sync.Mutex
 	// Track which credentials are stored insecurely for warnings
-	insecureCredentials []string
+	insecureCredentials map[string]struct{}
+	backends            map[string]SecretBackend
+	// Values resolved by backends, keyed by reference, so reconnects do not hit the backend
+	resolved map[string]string
 }
 
-// NewCredentialResolver creates a new credential resolver
+// NewCredentialResolver creates a new credential resolver with the built-in secret backends
 func NewCredentialResolver() *CredentialResolver {
-	return &CredentialResolver{
-		insecureCredentials: []string{},
+	cr := &CredentialResolver{
+		insecureCredentials: make(map[string]struct{}),
+		backends:            make(map[string]SecretBackend),
+		resolved:            make(map[string]string),
 	}
+	cr.RegisterBackend("vault", newLazyVaultBackend())
+	cr.RegisterBackend("sops", newSOPSBackend())
+	cr.RegisterBackend("systemd-creds", newSystemdCredsBackend())
+	return cr
+}
+
+var (
+	defaultResolverOnce sync.Once
+	defaultResolver     *CredentialResolver
+)
+
+// DefaultCredentialResolver returns the resolver used for node and notification credentials
+func DefaultCredentialResolver() *CredentialResolver {
+	defaultResolverOnce.Do(func() {
+		defaultResolver = NewCredentialResolver()
+	})
+	return defaultResolver
+}
+
+// RegisterBackend adds or replaces the backend for a reference scheme
+func (cr *CredentialResolver) RegisterBackend(scheme string, backend SecretBackend) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.backends[scheme] = backend
+}
+
+// backendFor returns the backend and reference of a scheme://ref value
+func (cr *CredentialResolver) backendFor(value string) (SecretBackend, string, bool) {
+	scheme, ref, found := strings.Cut(value, "://")
+	if !found {
+		return nil, "", false
+	}
+	cr.mu.Lock()
+	backend, ok := cr.backends[scheme]
+	cr.mu.Unlock()
+	return backend, ref, ok
+}
+
+// Refresh re-resolves every cached backend reference and reports whether any value changed,
+// so rotated secrets are picked up on config reload
+func (cr *CredentialResolver) Refresh() bool {
+	cr.mu.Lock()
+	refs := make(map[string]string, len(cr.resolved))
+	for ref, value := range cr.resolved {
+		refs[ref] = value
+	}
+	cr.mu.Unlock()
+
+	changed := false
+	for value, previous := range refs {
+		backend, ref, ok := cr.backendFor(value)
+		if !ok {
+			continue
+		}
+		resolved, err := backend.Resolve(ref)
+		if err != nil {
+			// Keep the last good value; the backend may be briefly unavailable
+			log.Warn().Err(err).Str("reference", value).Msg("Failed to refresh credential reference")
+			continue
+		}
+		if resolved != previous {
+			changed = true
+			cr.mu.Lock()
+			cr.resolved[value] = resolved
+			cr.mu.Unlock()
+		}
+	}
+	return changed
 }
 
 // ResolveValue resolves a credential value that might be:
 // - A literal value (backwards compatible)
 // - An environment variable reference: ${VAR_NAME} (for secrets, not node config)
 // - A file reference: file:///path/to/secret
-// - Future: vault://path/to/secret, keyring://secret-name, etc.
+// - A secret backend reference: vault://mount/path#key, sops:///file#key.path, systemd-creds://name
 // NOTE: This is for credential values only, not for node configuration which is done via UI
 func (cr *CredentialResolver) ResolveValue(value string, fieldName string) (string, error) {
 	if value == "" {
@@ -69,9 +150,43 @@ func (cr *CredentialResolver) ResolveValue(value string, fieldName string) (stri
 		return resolved, nil
 	}
 
+	return cr.ResolveReference(value, fieldName)
+}
+
+// ResolveReference resolves secret backend references (vault://, sops://, systemd-creds://) and
+// returns any other value unchanged. Credentials that can be set through the API use it instead of
+// ResolveValue, so ${VAR} and file:// values are taken literally rather than read from the
+// server's environment or filesystem.
+func (cr *CredentialResolver) ResolveReference(value string, fieldName string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	// Check for secret backend reference
+	if backend, ref, ok := cr.backendFor(value); ok {
+		cr.mu.Lock()
+		cached, found := cr.resolved[value]
+		cr.mu.Unlock()
+		if found {
+			return cached, nil
+		}
+
+		resolved, err := backend.Resolve(ref)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", fieldName, err)
+		}
+		cr.mu.Lock()
+		cr.resolved[value] = resolved
+		cr.mu.Unlock()
+		log.Debug().Str("field", fieldName).Str("reference", value).Msg("Resolved credential from secret backend")
+		return resolved, nil
+	}
+
 	// Check if this looks like a credential (UUID pattern, token pattern, etc)
 	if looksLikeCredential(value) {
-		cr.insecureCredentials = append(cr.insecureCredentials, fieldName)
+		cr.mu.Lock()
+		cr.insecureCredentials[fieldName] = struct{}{}
+		cr.mu.Unlock()
 	}
 
 	// Return as-is (literal value - backwards compatible)
@@ -86,15 +201,21 @@ func (cr *CredentialResolver) CheckConfigSecurity(configPath string) {
 		log.Debug().
 			Str("file", configPath).
 			Str("permissions", mode.String()).
-			Int("inline_credentials", len(cr.insecureCredentials)).
+			Int("inline_credentials", cr.insecureCredentialCount()).
 			Msg("Config file security check")
 	}
 }
 
+func (cr *CredentialResolver) insecureCredentialCount() int {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return len(cr.insecureCredentials)
+}
+
 // looksLikeCredential uses heuristics to detect if a value is likely a credential
 func looksLikeCredential(value string) bool {
 	// Skip if it's a reference
-	if strings.HasPrefix(value, "${") || strings.HasPrefix(value, "file://") {
+	if strings.HasPrefix(value, "${") || strings.HasPrefix(value, "file://") || IsSecretReference(value) {
 		return false
 	}
 
@@ -142,6 +263,27 @@ func (cr *CredentialResolver) ResolveNodeCredentials(node interface{}, nodeName
 		if err != nil {
 			return err
 		}
+	case *PMGInstance:
+		var err error
+		n.Password, err = cr.ResolveValue(n.Password, fmt.Sprintf("%s.password", nodeName))
+		if err != nil {
+			return err
+		}
+		n.TokenValue, err = cr.ResolveValue(n.TokenValue, fmt.Sprintf("%s.token_value", nodeName))
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// IsSecretReference reports whether a value refers to a secret backend rather than holding the
+// secret itself
+func IsSecretReference(value string) bool {
+	for _, prefix := range []string{"vault://", "sops://", "systemd-creds://"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// vaultStub is a minimal Vault server with AppRole login, token renewal and a KV v2 engine
+type vaultStub struct {
+	mu       sync.Mutex
+	secrets  map[string]map[string]interface{}
+	tokens   map[string]bool
+	logins   int
+	renewals int
+	issued   int
+}
+
+func newVaultStub() *vaultStub {
+	return &vaultStub{
+		secrets: map[string]map[string]interface{}{
+			"/v1/secret/data/pulse/pve1": {"token_value": "pve-secret", "port": 8006},
+		},
+		tokens: map[string]bool{"static-token": true},
+	}
+}
+
+func (v *vaultStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/auth/approle/login":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.logins++
+		v.issued++
+		token := "approle-" + string(rune('a'+v.issued))
+		v.tokens[token] = true
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": token, "lease_duration": 3600, "renewable": true},
+		})
+		return
+	}
+
+	if !v.tokens[r.Header.Get("X-Vault-Token")] {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/auth/token/lookup-self":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"ttl": 0}})
+	case "/v1/auth/token/renew-self":
+		v.renewals++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": r.Header.Get("X-Vault-Token"), "lease_duration": 3600, "renewable": true},
+		})
+	default:
+		secret, ok := v.secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": secret}})
+	}
+}
+
+func TestVaultBackendTokenAuth(t *testing.T) {
+	stub := newVaultStub()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	backend, err := NewVaultBackend(VaultConfig{Address: server.URL, Token: "static-token"})
+	if err != nil {
+		t.Fatalf("NewVaultBackend: %v", err)
+	}
+
+	value, err := backend.Resolve("secret/pulse/pve1#token_value")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if value != "pve-secret" {
+		t.Fatalf("expected pve-secret, got %q", value)
+	}
+	if value, err := backend.Resolve("secret/pulse/pve1#port"); err != nil || value != "8006" {
+		t.Fatalf("expected non-string values to be formatted, got %q (%v)", value, err)
+	}
+	if _, err := backend.Resolve("secret/pulse/pve1#missing"); err == nil {
+		t.Fatalf("expected an error for a missing key")
+	}
+	if _, err := backend.Resolve("secret"); err == nil {
+		t.Fatalf("expected an error for a reference without a path")
+	}
+}
+
+func TestVaultBackendAppRoleLoginAndRenewal(t *testing.T) {
+	stub := newVaultStub()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	backend, err := NewVaultBackend(VaultConfig{Address: server.URL, RoleID: "role", SecretID: "secret"})
+	if err != nil {
+		t.Fatalf("NewVaultBackend: %v", err)
+	}
+	now := time.Now()
+	backend.now = func() time.Time { return now }
+
+	if _, err := backend.Resolve("secret/pulse/pve1#token_value"); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if stub.logins != 1 || stub.renewals != 0 {
+		t.Fatalf("expected one login, got logins=%d renewals=%d", stub.logins, stub.renewals)
+	}
+
+	// Past two thirds of the lease the token is renewed rather than replaced
+	now = now.Add(45 * time.Minute)
+	if _, err := backend.Resolve("secret/pulse/pve1#token_value"); err != nil {
+		t.Fatalf("Resolve after renewal: %v", err)
+	}
+	if stub.logins != 1 || stub.renewals != 1 {
+		t.Fatalf("expected a renewal, got logins=%d renewals=%d", stub.logins, stub.renewals)
+	}
+
+	// A revoked token triggers a fresh login
+	stub.mu.Lock()
+	stub.tokens = map[string]bool{}
+	stub.mu.Unlock()
+	if _, err := backend.Resolve("secret/pulse/pve1#token_value"); err != nil {
+		t.Fatalf("Resolve after revocation: %v", err)
+	}
+	if stub.logins != 2 {
+		t.Fatalf("expected a second login after revocation, got %d", stub.logins)
+	}
+}
+
+func TestCredentialResolverSystemdCredentials(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "smtp-password"), []byte("hunter2\n"), 0600); err != nil {
+		t.Fatalf("write credential: %v", err)
+	}
+	t.Setenv("CREDENTIALS_DIRECTORY", dir)
+
+	cr := NewCredentialResolver()
+	value, err := cr.ResolveValue("systemd-creds://smtp-password", "email.password")
+	if err != nil {
+		t.Fatalf("ResolveValue: %v", err)
+	}
+	if value != "hunter2" {
+		t.Fatalf("expected hunter2, got %q", value)
+	}
+	if _, err := cr.ResolveValue("systemd-creds://../etc/passwd", "email.password"); err == nil {
+		t.Fatalf("expected credential names with path separators to be rejected")
+	}
+}
+
+func TestCredentialResolverSOPS(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "sops")
+	// Echo the arguments so the test can check how sops is invoked
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\"\n"), 0700); err != nil {
+		t.Fatalf("write fake sops: %v", err)
+	}
+	t.Setenv("PULSE_SOPS_PATH", script)
+
+	cr := NewCredentialResolver()
+	value, err := cr.ResolveValue("sops:///etc/pulse/secrets.enc.yaml#pve1.token", "pve1.token_value")
+	if err != nil {
+		t.Fatalf("ResolveValue: %v", err)
+	}
+	if want := `--decrypt --extract ["pve1"]["token"] -- /etc/pulse/secrets.enc.yaml`; value != want {
+		t.Fatalf("unexpected sops invocation %q, want %q", value, want)
+	}
+
+	for _, ref := range []string{"sops://--config=/tmp/evil", "sops://secrets.enc.yaml#token", "sops://#token"} {
+		if _, err := cr.ResolveReference(ref, "pve1.token_value"); err == nil {
+			t.Errorf("expected %q to be rejected", ref)
+		}
+	}
+}
+
+type countingBackend struct {
+	value string
+	calls int
+}
+
+func (b *countingBackend) Resolve(ref string) (string, error) {
+	b.calls++
+	return b.value + ":" + ref, nil
+}
+
+func TestCredentialResolverCachesAndRefreshes(t *testing.T) {
+	cr := NewCredentialResolver()
+	backend := &countingBackend{value: "v1"}
+	cr.RegisterBackend("test", backend)
+
+	for i := 0; i < 3; i++ {
+		value, err := cr.ResolveValue("test://secret", "pve1.password")
+		if err != nil || value != "v1:secret" {
+			t.Fatalf("ResolveValue: %q (%v)", value, err)
+		}
+	}
+	if backend.calls != 1 {
+		t.Fatalf("expected resolved values to be cached, got %d calls", backend.calls)
+	}
+
+	if cr.Refresh() {
+		t.Fatalf("refresh must not report a change when the secret is unchanged")
+	}
+	backend.value = "v2"
+	if !cr.Refresh() {
+		t.Fatalf("expected refresh to report the rotated secret")
+	}
+	if value, _ := cr.ResolveValue("test://secret", "pve1.password"); value != "v2:secret" {
+		t.Fatalf("expected rotated value, got %q", value)
+	}
+
+	// Unknown schemes are literal values
+	if value, _ := cr.ResolveValue("https://example.com", "pve1.password"); !strings.HasPrefix(value, "https://") {
+		t.Fatalf("expected unknown schemes to be left alone, got %q", value)
+	}
+}
+
+func TestResolveReferenceIgnoresEnvironmentAndFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+	if err := os.WriteFile(path, []byte("from-file"), 0600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	t.Setenv("PULSE_TEST_SECRET", "from-env")
+
+	cr := NewCredentialResolver()
+	cr.RegisterBackend("test", &countingBackend{value: "v1"})
+
+	for _, value := range []string{"${PULSE_TEST_SECRET}", "file://" + path} {
+		got, err := cr.ResolveReference(value, "pve1.password")
+		if err != nil || got != value {
+			t.Fatalf("expected %q to be kept literally, got %q (%v)", value, got, err)
+		}
+	}
+	if got, err := cr.ResolveReference("test://secret", "pve1.password"); err != nil || got != "v1:secret" {
+		t.Fatalf("expected backend reference to resolve, got %q (%v)", got, err)
+	}
+	if got := resolveClientSecret("file://"+path, "pve1.password"); got != "file://"+path {
+		t.Fatalf("expected node credentials not to read files, got %q", got)
+	}
+}
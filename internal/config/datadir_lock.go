@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// dataDirLockFile is locked by running servers and by maintenance that rewrites the data directory.
+// Backups and restores leave it alone.
+const dataDirLockFile = ".pulse.lock"
+
+// ErrDataDirInUse is returned when maintenance needs the data directory while a server is running
+var ErrDataDirInUse = errors.New("the data directory is in use by a running Pulse server")
+
+// DataDirLock is an advisory lock on the data directory
+type DataDirLock struct {
+	file *os.File
+}
+
+// LockDataDirShared is held by a Pulse server for as long as it runs. Several servers may
+// hold it at once (e.g. HA peers on a shared directory); it waits for exclusive maintenance to finish.
+func LockDataDirShared(dir string) (*DataDirLock, error) {
+	return lockDataDir(dir, syscall.LOCK_SH)
+}
+
+// LockDataDirExclusive is taken by maintenance that rewrites encrypted files or the keyring.
+// It fails with ErrDataDirInUse while a server holds the data directory.
+func LockDataDirExclusive(dir string) (*DataDirLock, error) {
+	lock, err := lockDataDir(dir, syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return nil, ErrDataDirInUse
+	}
+	return lock, err
+}
+
+func lockDataDir(dir string, how int) (*DataDirLock, error) {
+	file, err := os.OpenFile(filepath.Join(dir, dataDirLockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data directory lock: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), how); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock data directory: %w", err)
+	}
+	return &DataDirLock{file: file}, nil
+}
+
+// Release unlocks the data directory
+func (l *DataDirLock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,884 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/notifications"
+	"gopkg.in/yaml.v3"
+)
+
+// DeclarativeConfigVersion is the schema version of config-as-code documents
+const DeclarativeConfigVersion = 1
+
+// Sections of a config-as-code document, in the order they are planned and applied
+var declarativeSections = []string{"nodes", "alerts", "notifications", "system", "guestMetadata"}
+
+// Discovered cluster details are maintained by Pulse, not declared
+var discoveredNodeKeys = []string{"isCluster", "clusterName", "clusterEndpoints"}
+
+// secretKeys are the setting names whose values are masked in plans and replaced by references
+// on export. Webhook headers are treated as secrets as well.
+var secretKeys = map[string]bool{
+	"password":       true,
+	"tokenvalue":     true,
+	"apikey":         true,
+	"community":      true,
+	"authpassphrase": true,
+	"privpassphrase": true,
+}
+
+const sensitiveValue = "(sensitive)"
+
+// DeclarativeConfig is a parsed config-as-code document with its secret settings resolved.
+// Each section present in the document is a JSON merge patch (RFC 7386) over the live settings:
+// objects are merged, lists replace the live list and null removes a key.
+type DeclarativeConfig struct {
+	sections map[string]interface{}
+	Warnings []string
+}
+
+// ConfigChange is one difference between the live configuration and a declarative document
+type ConfigChange struct {
+	Action string      `json:"action"` // add, remove or change
+	Path   string      `json:"path"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// Config change actions
+const (
+	ConfigChangeAdd    = "add"
+	ConfigChangeRemove = "remove"
+	ConfigChangeChange = "change"
+)
+
+// String renders the change as a single plan line with secrets already masked
+func (c ConfigChange) String() string {
+	switch c.Action {
+	case ConfigChangeAdd:
+		return fmt.Sprintf("+ %s = %s", c.Path, renderValue(c.New))
+	case ConfigChangeRemove:
+		return fmt.Sprintf("- %s = %s", c.Path, renderValue(c.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, renderValue(c.Old), renderValue(c.New))
+}
+
+// ConfigPlan is the set of changes needed to bring the live configuration in line with a
+// declarative document. Apply it with ApplyDeclarativeConfig.
+type ConfigPlan struct {
+	Changes  []ConfigChange `json:"changes"`
+	Warnings []string       `json:"warnings,omitempty"`
+
+	desired *declarativeState
+	changed map[string]bool
+}
+
+// Counts returns the number of additions, changes and removals in the plan
+func (p *ConfigPlan) Counts() (add, change, remove int) {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ConfigChangeAdd:
+			add++
+		case ConfigChangeRemove:
+			remove++
+		default:
+			change++
+		}
+	}
+	return add, change, remove
+}
+
+// declarativeNodes is the nodes section. Instances are matched by name.
+type declarativeNodes struct {
+	PVE []PVEInstance `json:"pve"`
+	PBS []PBSInstance `json:"pbs"`
+	PMG []PMGInstance `json:"pmg"`
+}
+
+// declarativeNotifications is the notifications section
+type declarativeNotifications struct {
+	Email    notifications.EmailConfig     `json:"email"`
+	Webhooks []notifications.WebhookConfig `json:"webhooks"`
+	Apprise  notifications.AppriseConfig   `json:"apprise"`
+	Syslog   notifications.SyslogConfig    `json:"syslog"`
+	SNMP     notifications.SNMPConfig      `json:"snmp"`
+}
+
+// declarativeState holds the typed value of every declarative section
+type declarativeState struct {
+	nodes         declarativeNodes
+	alerts        alerts.AlertConfig
+	notifications declarativeNotifications
+	system        SystemSettings
+	guestMetadata map[string]*GuestMetadata
+}
+
+// ParseDeclarativeConfig parses a YAML config-as-code document and resolves its secret settings
+// with the credential resolver. Other settings are never expanded. Literal values in secret
+// settings are accepted but reported as warnings.
+func ParseDeclarativeConfig(data []byte) (*DeclarativeConfig, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config document: %w", err)
+	}
+	doc, ok := normalizeYAML(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config document must be a mapping")
+	}
+
+	version, ok := doc["version"].(int)
+	if !ok || version != DeclarativeConfigVersion {
+		return nil, fmt.Errorf("unsupported config document version %v (expected %d)", doc["version"], DeclarativeConfigVersion)
+	}
+
+	decl := &DeclarativeConfig{sections: make(map[string]interface{})}
+	for key, value := range doc {
+		if key == "version" {
+			continue
+		}
+		if !isDeclarativeSection(key) {
+			return nil, fmt.Errorf("unknown config section %q", key)
+		}
+		if _, ok := value.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("config section %q must be a mapping", key)
+		}
+
+		walkLeaves(key, key, "", value, func(path, key, parent string, leaf interface{}) (interface{}, error) {
+			if s, ok := leaf.(string); ok && s != "" && isSecretSetting(key, parent) && !isCredentialReference(s) {
+				decl.Warnings = append(decl.Warnings, fmt.Sprintf("%s is a literal secret; use a ${VAR}, file://, vault://, sops:// or systemd-creds:// reference instead", path))
+			}
+			return leaf, nil
+		})
+
+		resolved, err := walkLeaves(key, key, "", value, func(path, key, parent string, leaf interface{}) (interface{}, error) {
+			s, ok := leaf.(string)
+			if !ok || !isSecretSetting(key, parent) {
+				return leaf, nil
+			}
+			value, err := resolveSecretSetting(path, key, parent, s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			return value, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		decl.sections[key] = resolved
+	}
+	sort.Strings(decl.Warnings)
+	return decl, nil
+}
+
+// PlanDeclarativeConfig compares a declarative document with the live configuration
+func (c *ConfigPersistence) PlanDeclarativeConfig(decl *DeclarativeConfig) (*ConfigPlan, error) {
+	live, err := c.loadDeclarativeState()
+	if err != nil {
+		return nil, err
+	}
+
+	desired := *live
+	plan := &ConfigPlan{
+		Changes:  []ConfigChange{},
+		Warnings: decl.Warnings,
+		desired:  &desired,
+		changed:  make(map[string]bool),
+	}
+
+	for _, section := range declarativeSections {
+		patch, ok := decl.sections[section]
+		if !ok {
+			continue
+		}
+		current, err := live.document(section)
+		if err != nil {
+			return nil, err
+		}
+		if err := desired.decode(section, mergePatch(current, patch), live); err != nil {
+			return nil, fmt.Errorf("invalid %s section: %w", section, err)
+		}
+		target, err := desired.document(section)
+		if err != nil {
+			return nil, err
+		}
+
+		before := len(plan.Changes)
+		plan.Changes = diffDocuments(plan.Changes, section, section, "", current, target)
+		plan.changed[section] = len(plan.Changes) > before
+	}
+	return plan, nil
+}
+
+// ApplyDeclarativeConfig writes every section changed by the plan in a single transaction, so
+// either all of them are applied or none are
+func (c *ConfigPersistence) ApplyDeclarativeConfig(plan *ConfigPlan) error {
+	if plan == nil || plan.desired == nil {
+		return fmt.Errorf("plan has no desired configuration")
+	}
+	if len(plan.Changes) == 0 {
+		return nil
+	}
+
+	tx, err := newImportTransaction(c.configDir)
+	if err != nil {
+		return fmt.Errorf("failed to start apply transaction: %w", err)
+	}
+	defer tx.Cleanup()
+
+	c.beginTransaction(tx)
+	defer c.endTransaction(tx)
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	desired := plan.desired
+	if plan.changed["nodes"] {
+		if err := c.SaveNodesConfig(desired.nodes.PVE, desired.nodes.PBS, desired.nodes.PMG); err != nil {
+			return fmt.Errorf("failed to apply nodes config: %w", err)
+		}
+	}
+
+	if plan.changed["alerts"] {
+		if err := c.SaveAlertConfig(desired.alerts); err != nil {
+			return fmt.Errorf("failed to apply alert config: %w", err)
+		}
+	}
+
+	if plan.changed["notifications"] {
+		if err := c.SaveEmailConfig(desired.notifications.Email); err != nil {
+			return fmt.Errorf("failed to apply email config: %w", err)
+		}
+		if err := c.SaveWebhooks(desired.notifications.Webhooks); err != nil {
+			return fmt.Errorf("failed to apply webhooks: %w", err)
+		}
+		if err := c.SaveAppriseConfig(desired.notifications.Apprise); err != nil {
+			return fmt.Errorf("failed to apply Apprise config: %w", err)
+		}
+		if err := c.SaveSyslogConfig(desired.notifications.Syslog); err != nil {
+			return fmt.Errorf("failed to apply syslog config: %w", err)
+		}
+		if err := c.SaveSNMPConfig(desired.notifications.SNMP); err != nil {
+			return fmt.Errorf("failed to apply SNMP config: %w", err)
+		}
+	}
+
+	if plan.changed["system"] {
+		if err := c.SaveSystemSettings(desired.system); err != nil {
+			return fmt.Errorf("failed to apply system settings: %w", err)
+		}
+	}
+
+	if plan.changed["guestMetadata"] {
+		data, err := json.MarshalIndent(desired.guestMetadata, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal guest metadata: %w", err)
+		}
+		if err := tx.StageFile(filepath.Join(guestMetadataDataPath(), "guest_metadata.json"), data, 0644); err != nil {
+			return fmt.Errorf("failed to apply guest metadata: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit apply transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// ExportDeclarativeConfig renders the live configuration as a config-as-code document. Secret
+// values are replaced by ${VAR} references named after their setting; stored secret backend
+// references are kept.
+func (c *ConfigPersistence) ExportDeclarativeConfig() ([]byte, error) {
+	live, err := c.loadDeclarativeState()
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make(map[string]interface{}, len(declarativeSections))
+	for _, section := range declarativeSections {
+		doc, err := live.document(section)
+		if err != nil {
+			return nil, err
+		}
+		sections[section], _ = walkLeaves(section, section, "", doc, func(path, key, parent string, leaf interface{}) (interface{}, error) {
+			if s, ok := leaf.(string); ok && s != "" && isSecretSetting(key, parent) && !IsSecretReference(s) {
+				return "${" + secretEnvName(path) + "}", nil
+			}
+			return leaf, nil
+		})
+	}
+
+	// A struct keeps the sections in schema order
+	document := struct {
+		Version       int         `yaml:"version"`
+		Nodes         interface{} `yaml:"nodes"`
+		Alerts        interface{} `yaml:"alerts"`
+		Notifications interface{} `yaml:"notifications"`
+		System        interface{} `yaml:"system"`
+		GuestMetadata interface{} `yaml:"guestMetadata"`
+	}{
+		Version:       DeclarativeConfigVersion,
+		Nodes:         sections["nodes"],
+		Alerts:        sections["alerts"],
+		Notifications: sections["notifications"],
+		System:        sections["system"],
+		GuestMetadata: sections["guestMetadata"],
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, fmt.Errorf("failed to encode config document: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *ConfigPersistence) loadDeclarativeState() (*declarativeState, error) {
+	nodes, err := c.LoadNodesConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes config: %w", err)
+	}
+	alertConfig, err := c.LoadAlertConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert config: %w", err)
+	}
+	emailConfig, err := c.LoadEmailConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load email config: %w", err)
+	}
+	webhooks, err := c.LoadWebhooks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhooks: %w", err)
+	}
+	appriseConfig, err := c.LoadAppriseConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Apprise config: %w", err)
+	}
+	syslogConfig, err := c.LoadSyslogConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load syslog config: %w", err)
+	}
+	snmpConfig, err := c.LoadSNMPConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SNMP config: %w", err)
+	}
+	systemSettings, err := c.LoadSystemSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system settings: %w", err)
+	}
+	if systemSettings == nil {
+		systemSettings = DefaultSystemSettings()
+	}
+
+	return &declarativeState{
+		nodes: declarativeNodes{
+			PVE: nodes.PVEInstances,
+			PBS: nodes.PBSInstances,
+			PMG: nodes.PMGInstances,
+		},
+		alerts: *alertConfig,
+		notifications: declarativeNotifications{
+			Email:    *emailConfig,
+			Webhooks: webhooks,
+			Apprise:  *appriseConfig,
+			Syslog:   *syslogConfig,
+			SNMP:     *snmpConfig,
+		},
+		system:        *systemSettings,
+		guestMetadata: NewGuestMetadataStore(guestMetadataDataPath()).GetAll(),
+	}, nil
+}
+
+// document converts a section to its generic document form, as used in YAML files
+func (s *declarativeState) document(section string) (interface{}, error) {
+	switch section {
+	case "nodes":
+		doc, err := toDocument(s.nodes)
+		if err != nil {
+			return nil, err
+		}
+		// Instances have no JSON tags, so their keys are lower-cased to match the schema
+		for _, kind := range []string{"pve", "pbs", "pmg"} {
+			instances, _ := doc.(map[string]interface{})[kind].([]interface{})
+			for i, instance := range instances {
+				fields, ok := instance.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				lowered := make(map[string]interface{}, len(fields))
+				for key, value := range fields {
+					lowered[lowerFirst(key)] = value
+				}
+				for _, key := range discoveredNodeKeys {
+					delete(lowered, key)
+				}
+				instances[i] = lowered
+			}
+		}
+		return doc, nil
+	case "alerts":
+		return toDocument(s.alerts)
+	case "notifications":
+		return toDocument(s.notifications)
+	case "system":
+		return toDocument(s.system)
+	case "guestMetadata":
+		doc, err := toDocument(s.guestMetadata)
+		if err != nil {
+			return nil, err
+		}
+		if entries, ok := doc.(map[string]interface{}); ok {
+			for _, entry := range entries {
+				if fields, ok := entry.(map[string]interface{}); ok {
+					delete(fields, "id") // The key is the guest ID
+				}
+			}
+		} else {
+			doc = map[string]interface{}{}
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown config section %q", section)
+}
+
+// decode replaces a section with the value of a document. Unknown settings are rejected so
+// typos do not silently fall back to defaults.
+func (s *declarativeState) decode(section string, doc interface{}, live *declarativeState) error {
+	switch section {
+	case "nodes":
+		var nodes declarativeNodes
+		if err := decodeDocument(doc, &nodes); err != nil {
+			return err
+		}
+		if err := validateDeclarativeNodes(nodes); err != nil {
+			return err
+		}
+		// Keep what Pulse discovered about clusters
+		for i := range nodes.PVE {
+			for _, existing := range live.nodes.PVE {
+				if existing.Name == nodes.PVE[i].Name {
+					nodes.PVE[i].IsCluster = existing.IsCluster
+					nodes.PVE[i].ClusterName = existing.ClusterName
+					nodes.PVE[i].ClusterEndpoints = existing.ClusterEndpoints
+				}
+			}
+		}
+		s.nodes = nodes
+	case "alerts":
+		var alertConfig alerts.AlertConfig
+		if err := decodeDocument(doc, &alertConfig); err != nil {
+			return err
+		}
+		s.alerts = alertConfig
+	case "notifications":
+		var channels declarativeNotifications
+		if err := decodeDocument(doc, &channels); err != nil {
+			return err
+		}
+		s.notifications = channels
+	case "system":
+		var settings SystemSettings
+		if err := decodeDocument(doc, &settings); err != nil {
+			return err
+		}
+		s.system = settings
+	case "guestMetadata":
+		metadata := make(map[string]*GuestMetadata)
+		if err := decodeDocument(doc, &metadata); err != nil {
+			return err
+		}
+		for guestID, meta := range metadata {
+			if meta == nil {
+				delete(metadata, guestID)
+				continue
+			}
+			meta.ID = guestID
+			if meta.Tags == nil {
+				meta.Tags = []string{}
+			}
+		}
+		s.guestMetadata = metadata
+	default:
+		return fmt.Errorf("unknown config section %q", section)
+	}
+	return nil
+}
+
+func validateDeclarativeNodes(nodes declarativeNodes) error {
+	seen := make(map[string]bool)
+	check := func(kind, name, host string) error {
+		if name == "" {
+			return fmt.Errorf("%s node without a name", kind)
+		}
+		if host == "" {
+			return fmt.Errorf("%s node %q has no host", kind, name)
+		}
+		if seen[kind+":"+name] {
+			return fmt.Errorf("duplicate %s node %q", kind, name)
+		}
+		seen[kind+":"+name] = true
+		return nil
+	}
+	for _, node := range nodes.PVE {
+		if err := check("pve", node.Name, node.Host); err != nil {
+			return err
+		}
+		if err := node.ValidatePollingOptions(); err != nil {
+			return fmt.Errorf("pve node %q: %w", node.Name, err)
+		}
+	}
+	for _, node := range nodes.PBS {
+		if err := check("pbs", node.Name, node.Host); err != nil {
+			return err
+		}
+	}
+	for _, node := range nodes.PMG {
+		if err := check("pmg", node.Name, node.Host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffDocuments appends the differences between two documents. List entries with unique names
+// are matched by name so reordering or inserting an entry only reports what really changed.
+func diffDocuments(changes []ConfigChange, path, key, parent string, old, new interface{}) []ConfigChange {
+	if isEmptyDocument(old) && isEmptyDocument(new) {
+		return changes
+	}
+	if old == nil {
+		return append(changes, ConfigChange{Action: ConfigChangeAdd, Path: path, New: maskSecrets(key, parent, new)})
+	}
+	if new == nil {
+		return append(changes, ConfigChange{Action: ConfigChangeRemove, Path: path, Old: maskSecrets(key, parent, old)})
+	}
+
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make([]string, 0, len(oldMap)+len(newMap))
+		for k := range oldMap {
+			keys = append(keys, k)
+		}
+		for k := range newMap {
+			if _, ok := oldMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			changes = diffDocuments(changes, path+"."+k, k, key, oldMap[k], newMap[k])
+		}
+		return changes
+	}
+
+	oldList, oldIsList := old.([]interface{})
+	newList, newIsList := new.([]interface{})
+	if oldIsList && newIsList {
+		oldNames, oldNamed := listNames(oldList)
+		newNames, newNamed := listNames(newList)
+		if oldNamed && newNamed {
+			oldByName := make(map[string]interface{}, len(oldList))
+			for i, name := range oldNames {
+				oldByName[name] = oldList[i]
+			}
+			for i, name := range newNames {
+				changes = diffDocuments(changes, path+"["+name+"]", key, parent, oldByName[name], newList[i])
+				delete(oldByName, name)
+			}
+			for _, name := range oldNames {
+				if entry, ok := oldByName[name]; ok {
+					changes = diffDocuments(changes, path+"["+name+"]", key, parent, entry, nil)
+				}
+			}
+			return changes
+		}
+		if listOfMaps(oldList) && listOfMaps(newList) {
+			for i := 0; i < len(oldList) || i < len(newList); i++ {
+				var oldEntry, newEntry interface{}
+				if i < len(oldList) {
+					oldEntry = oldList[i]
+				}
+				if i < len(newList) {
+					newEntry = newList[i]
+				}
+				changes = diffDocuments(changes, path+"["+strconv.Itoa(i)+"]", key, parent, oldEntry, newEntry)
+			}
+			return changes
+		}
+	}
+
+	if reflect.DeepEqual(old, new) {
+		return changes
+	}
+	return append(changes, ConfigChange{Action: ConfigChangeChange, Path: path, Old: maskSecrets(key, parent, old), New: maskSecrets(key, parent, new)})
+}
+
+// listNames returns the names of list entries when every entry is an object with a unique name
+func listNames(list []interface{}) ([]string, bool) {
+	names := make([]string, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, entry := range list {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, _ := fields["name"].(string)
+		if name == "" || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, true
+}
+
+func listOfMaps(list []interface{}) bool {
+	for _, entry := range list {
+		if _, ok := entry.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isEmptyDocument(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// maskSecrets hides secret values in a document fragment. key is the setting name of the fragment
+// and parent the name of the setting enclosing it.
+func maskSecrets(key, parent string, value interface{}) interface{} {
+	masked, _ := walkLeaves("", key, parent, value, func(path, key, parent string, leaf interface{}) (interface{}, error) {
+		if s, ok := leaf.(string); ok && s != "" && isSecretSetting(key, parent) {
+			return sensitiveValue, nil
+		}
+		return leaf, nil
+	})
+	return masked
+}
+
+// walkLeaves rebuilds a document, passing every scalar to fn along with its path, setting name and
+// the name of the enclosing setting
+func walkLeaves(path, key, parent string, value interface{}, fn func(path, key, parent string, leaf interface{}) (interface{}, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, entry := range v {
+			walked, err := walkLeaves(joinPath(path, k), k, key, entry, fn)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = walked
+		}
+		return result, nil
+	case []interface{}:
+		names, named := listNames(v)
+		result := make([]interface{}, len(v))
+		for i, entry := range v {
+			label := strconv.Itoa(i)
+			if named {
+				label = names[i]
+			}
+			walked, err := walkLeaves(path+"["+label+"]", key, parent, entry, fn)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = walked
+		}
+		return result, nil
+	}
+	return fn(path, key, parent, value)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isSecretSetting(key, parent string) bool {
+	return secretKeys[strings.ToLower(key)] || strings.EqualFold(parent, "headers")
+}
+
+func isDeclarativeSection(name string) bool {
+	for _, section := range declarativeSections {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
+
+// keepsSecretReference reports whether Pulse resolves secret backend references in a setting
+// itself when it connects, so the reference is stored as written
+func keepsSecretReference(key, parent string) bool {
+	switch strings.ToLower(parent) {
+	case "pve", "pbs", "pmg":
+		return strings.EqualFold(key, "password") || strings.EqualFold(key, "tokenValue")
+	case "email":
+		return strings.EqualFold(key, "password")
+	}
+	return false
+}
+
+// isCredentialReference reports whether a value uses the credential resolver reference syntax
+func isCredentialReference(value string) bool {
+	return (strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}")) ||
+		strings.HasPrefix(value, "file://") || IsSecretReference(value)
+}
+
+// resolveSecretSetting resolves a secret setting of a document. ${VAR} and file:// references are
+// read by the CLI, since stored settings are taken literally; secret backend references are kept
+// where Pulse resolves them and fetched now elsewhere.
+func resolveSecretSetting(path, key, parent, value string) (string, error) {
+	if keepsSecretReference(key, parent) && IsSecretReference(value) {
+		return value, nil
+	}
+	return DefaultCredentialResolver().ResolveValue(value, path)
+}
+
+// secretEnvName derives an environment variable name from a setting path, e.g.
+// nodes.pve[pve1].tokenValue becomes PULSE_NODES_PVE_PVE1_TOKEN_VALUE
+func secretEnvName(path string) string {
+	var b strings.Builder
+	b.WriteString("PULSE_")
+	var prev rune
+	for _, r := range path {
+		switch {
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			b.WriteRune('_')
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			if !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+		}
+		prev = r
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+func renderValue(value interface{}) string {
+	if s, ok := value.(string); ok && s == sensitiveValue {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// mergePatch applies a JSON merge patch (RFC 7386) to a document
+func mergePatch(target, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	result := make(map[string]interface{})
+	if targetMap, ok := target.(map[string]interface{}); ok {
+		for k, v := range targetMap {
+			result[k] = v
+		}
+	}
+	for k, v := range patchMap {
+		if v == nil {
+			delete(result, k)
+			continue
+		}
+		result[k] = mergePatch(result[k], v)
+	}
+	return result
+}
+
+// toDocument converts a value to its generic JSON form
+func toDocument(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func decodeDocument(doc interface{}, target interface{}) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+// normalizeYAML converts mappings with non-string keys so the document can be encoded as JSON
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, entry := range v {
+			v[k] = normalizeYAML(entry)
+		}
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, entry := range v {
+			result[fmt.Sprint(k)] = normalizeYAML(entry)
+		}
+		return result
+	case []interface{}:
+		for i, entry := range v {
+			v[i] = normalizeYAML(entry)
+		}
+		return v
+	}
+	return value
+}
+
+// guestMetadataDataPath returns the directory holding guest metadata.
+// Use PULSE_DATA_DIR if set, otherwise use /etc/pulse for backwards compatibility
+func guestMetadataDataPath() string {
+	if dataPath := os.Getenv("PULSE_DATA_DIR"); dataPath != "" {
+		return dataPath
+	}
+	return "/etc/pulse"
+}
@@ -0,0 +1,312 @@
+package config_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+func newDeclarativeTestPersistence(t *testing.T) *config.ConfigPersistence {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", tempDir)
+	cp := config.NewConfigPersistence(tempDir)
+	if err := cp.EnsureConfigDir(); err != nil {
+		t.Fatalf("EnsureConfigDir: %v", err)
+	}
+	return cp
+}
+
+func TestParseDeclarativeConfigResolvesSecretReferences(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "smtp-password")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("write secret file: %v", err)
+	}
+	t.Setenv("TEST_PVE_TOKEN", "from-env")
+
+	decl, err := config.ParseDeclarativeConfig([]byte(`
+version: 1
+nodes:
+  pve:
+    - name: pve1
+      host: https://pve1:8006
+      tokenName: pulse@pve!monitor
+      tokenValue: ${TEST_PVE_TOKEN}
+  pbs:
+    - name: pbs1
+      host: https://pbs1:8007
+      tokenName: pulse@pbs!monitor
+      tokenValue: vault://secret/pulse#pbs1
+notifications:
+  email:
+    password: file://` + secretFile + `
+  apprise:
+    apiKey: literal-key
+  webhooks:
+    - id: ops
+      name: ops
+      url: https://hooks.example.com/${TEST_PVE_TOKEN}
+      service: generic
+      enabled: true
+`))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeConfig: %v", err)
+	}
+
+	if len(decl.Warnings) != 1 || !strings.Contains(decl.Warnings[0], "notifications.apprise.apiKey") {
+		t.Fatalf("expected a literal secret warning for apiKey, got %v", decl.Warnings)
+	}
+
+	cp := newDeclarativeTestPersistence(t)
+	plan, err := cp.PlanDeclarativeConfig(decl)
+	if err != nil {
+		t.Fatalf("PlanDeclarativeConfig: %v", err)
+	}
+	if err := cp.ApplyDeclarativeConfig(plan); err != nil {
+		t.Fatalf("ApplyDeclarativeConfig: %v", err)
+	}
+
+	nodes, err := cp.LoadNodesConfig()
+	if err != nil {
+		t.Fatalf("LoadNodesConfig: %v", err)
+	}
+	if len(nodes.PVEInstances) != 1 || nodes.PVEInstances[0].TokenValue != "from-env" {
+		t.Fatalf("expected token from environment, got %+v", nodes.PVEInstances)
+	}
+	if len(nodes.PBSInstances) != 1 || nodes.PBSInstances[0].TokenValue != "vault://secret/pulse#pbs1" {
+		t.Fatalf("expected secret backend reference to be stored unresolved, got %+v", nodes.PBSInstances)
+	}
+	email, err := cp.LoadEmailConfig()
+	if err != nil {
+		t.Fatalf("LoadEmailConfig: %v", err)
+	}
+	if email.Password != "from-file" {
+		t.Fatalf("expected password from file, got %q", email.Password)
+	}
+	webhooks, err := cp.LoadWebhooks()
+	if err != nil {
+		t.Fatalf("LoadWebhooks: %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].URL != "https://hooks.example.com/${TEST_PVE_TOKEN}" {
+		t.Fatalf("expected settings other than secrets not to be expanded, got %+v", webhooks)
+	}
+}
+
+func TestParseDeclarativeConfigRejectsInvalidDocuments(t *testing.T) {
+	cases := map[string]string{
+		"missing version": "nodes: {}\n",
+		"unknown section": "version: 1\ntokens: {}\n",
+		"missing secret":  "version: 1\nnotifications:\n  email:\n    password: ${PULSE_TEST_UNSET_SECRET}\n",
+	}
+	for name, doc := range cases {
+		if _, err := config.ParseDeclarativeConfig([]byte(doc)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPlanDeclarativeConfigRejectsUnknownSettings(t *testing.T) {
+	cp := newDeclarativeTestPersistence(t)
+	decl, err := config.ParseDeclarativeConfig([]byte("version: 1\nsystem:\n  pbsPolingInterval: 30\n"))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeConfig: %v", err)
+	}
+	if _, err := cp.PlanDeclarativeConfig(decl); err == nil || !strings.Contains(err.Error(), "pbsPolingInterval") {
+		t.Fatalf("expected unknown setting error, got %v", err)
+	}
+}
+
+func TestPlanAndApplyDeclarativeConfig(t *testing.T) {
+	cp := newDeclarativeTestPersistence(t)
+	if err := cp.SaveNodesConfig([]config.PVEInstance{{
+		Name:             "pve1",
+		Host:             "https://pve1:8006",
+		TokenName:        "pulse@pve!monitor",
+		TokenValue:       "old-secret",
+		MonitorVMs:       true,
+		IsCluster:        true,
+		ClusterName:      "lab",
+		ClusterEndpoints: []config.ClusterEndpoint{{NodeName: "pve1", Host: "https://pve1:8006"}},
+	}}, nil, nil); err != nil {
+		t.Fatalf("SaveNodesConfig: %v", err)
+	}
+	settings := config.DefaultSystemSettings()
+	if err := cp.SaveSystemSettings(*settings); err != nil {
+		t.Fatalf("SaveSystemSettings: %v", err)
+	}
+
+	t.Setenv("TEST_PVE_TOKEN", "new-secret")
+	decl, err := config.ParseDeclarativeConfig([]byte(`
+version: 1
+nodes:
+  pve:
+    - name: pve2
+      host: https://pve2:8006
+      monitorVMs: true
+    - name: pve1
+      host: https://pve1:8006
+      tokenName: pulse@pve!monitor
+      tokenValue: ${TEST_PVE_TOKEN}
+      monitorVMs: true
+system:
+  pbsPollingInterval: 30
+guestMetadata:
+  pve1:node1:100:
+    customUrl: https://app.lan
+    tags: [web]
+`))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeConfig: %v", err)
+	}
+
+	plan, err := cp.PlanDeclarativeConfig(decl)
+	if err != nil {
+		t.Fatalf("PlanDeclarativeConfig: %v", err)
+	}
+
+	lines := make(map[string]string)
+	for _, change := range plan.Changes {
+		lines[change.Path] = change.String()
+	}
+	if line := lines["nodes.pve[pve1].tokenValue"]; line != "~ nodes.pve[pve1].tokenValue: (sensitive) -> (sensitive)" {
+		t.Fatalf("expected masked token change, got %q (plan %v)", line, plan.Changes)
+	}
+	if _, ok := lines["nodes.pve[pve2]"]; !ok {
+		t.Fatalf("expected new node to be added, got %v", plan.Changes)
+	}
+	if line := lines["system.pbsPollingInterval"]; line != "~ system.pbsPollingInterval: 60 -> 30" {
+		t.Fatalf("unexpected polling change %q", line)
+	}
+	if _, ok := lines["guestMetadata.pve1:node1:100"]; !ok {
+		t.Fatalf("expected guest metadata to be added, got %v", plan.Changes)
+	}
+	if _, ok := lines["nodes.pve[pve1].host"]; ok {
+		t.Fatalf("unchanged node fields must not be reported")
+	}
+	if add, change, remove := plan.Counts(); add != 2 || change != 2 || remove != 0 {
+		t.Fatalf("unexpected counts add=%d change=%d remove=%d", add, change, remove)
+	}
+
+	if err := cp.ApplyDeclarativeConfig(plan); err != nil {
+		t.Fatalf("ApplyDeclarativeConfig: %v", err)
+	}
+
+	nodes, err := cp.LoadNodesConfig()
+	if err != nil {
+		t.Fatalf("LoadNodesConfig: %v", err)
+	}
+	if len(nodes.PVEInstances) != 2 {
+		t.Fatalf("expected 2 PVE nodes, got %d", len(nodes.PVEInstances))
+	}
+	existing := nodes.PVEInstances[1]
+	if existing.TokenValue != "new-secret" || !existing.IsCluster || existing.ClusterName != "lab" || len(existing.ClusterEndpoints) != 1 {
+		t.Fatalf("expected token update with discovered cluster details kept, got %+v", existing)
+	}
+	system, err := cp.LoadSystemSettings()
+	if err != nil {
+		t.Fatalf("LoadSystemSettings: %v", err)
+	}
+	if system.PBSPollingInterval != 30 || system.PMGPollingInterval != 60 {
+		t.Fatalf("expected merged system settings, got %+v", system)
+	}
+	metadata := config.NewGuestMetadataStore(os.Getenv("PULSE_DATA_DIR")).Get("pve1:node1:100")
+	if metadata == nil || metadata.CustomURL != "https://app.lan" {
+		t.Fatalf("expected guest metadata to be applied, got %+v", metadata)
+	}
+
+	replan, err := cp.PlanDeclarativeConfig(decl)
+	if err != nil {
+		t.Fatalf("PlanDeclarativeConfig after apply: %v", err)
+	}
+	if len(replan.Changes) != 0 {
+		t.Fatalf("expected no changes after apply, got %v", replan.Changes)
+	}
+}
+
+func TestPlanDeclarativeConfigNullRemovesEntries(t *testing.T) {
+	cp := newDeclarativeTestPersistence(t)
+	store := config.NewGuestMetadataStore(os.Getenv("PULSE_DATA_DIR"))
+	if err := store.Set("pve1:node1:100", &config.GuestMetadata{CustomURL: "https://old.lan"}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	decl, err := config.ParseDeclarativeConfig([]byte("version: 1\nguestMetadata:\n  pve1:node1:100: null\n"))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeConfig: %v", err)
+	}
+	plan, err := cp.PlanDeclarativeConfig(decl)
+	if err != nil {
+		t.Fatalf("PlanDeclarativeConfig: %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Action != config.ConfigChangeRemove {
+		t.Fatalf("expected a single removal, got %v", plan.Changes)
+	}
+	if err := cp.ApplyDeclarativeConfig(plan); err != nil {
+		t.Fatalf("ApplyDeclarativeConfig: %v", err)
+	}
+	if got := config.NewGuestMetadataStore(os.Getenv("PULSE_DATA_DIR")).Get("pve1:node1:100"); got != nil {
+		t.Fatalf("expected guest metadata to be removed, got %+v", got)
+	}
+}
+
+func TestExportDeclarativeConfigRoundTrips(t *testing.T) {
+	cp := newDeclarativeTestPersistence(t)
+	if err := cp.SaveNodesConfig([]config.PVEInstance{{
+		Name:       "pve1",
+		Host:       "https://pve1:8006",
+		TokenName:  "pulse@pve!monitor",
+		TokenValue: "secret-value",
+	}}, nil, nil); err != nil {
+		t.Fatalf("SaveNodesConfig: %v", err)
+	}
+
+	exported, err := cp.ExportDeclarativeConfig()
+	if err != nil {
+		t.Fatalf("ExportDeclarativeConfig: %v", err)
+	}
+	if strings.Contains(string(exported), "secret-value") {
+		t.Fatalf("export must not contain secret values:\n%s", exported)
+	}
+	if !strings.Contains(string(exported), "${PULSE_NODES_PVE_PVE1_TOKEN_VALUE}") {
+		t.Fatalf("expected token reference in export:\n%s", exported)
+	}
+
+	t.Setenv("PULSE_NODES_PVE_PVE1_TOKEN_VALUE", "secret-value")
+	t.Setenv("PULSE_NOTIFICATIONS_SNMP_COMMUNITY", "public")
+	decl, err := config.ParseDeclarativeConfig(exported)
+	if err != nil {
+		t.Fatalf("ParseDeclarativeConfig: %v", err)
+	}
+	plan, err := cp.PlanDeclarativeConfig(decl)
+	if err != nil {
+		t.Fatalf("PlanDeclarativeConfig: %v", err)
+	}
+	if len(plan.Changes) != 0 {
+		t.Fatalf("expected exported config to match live config, got %v", plan.Changes)
+	}
+}
+
+func TestPlanDeclarativeConfigValidatesNodeFilters(t *testing.T) {
+	cp := newDeclarativeTestPersistence(t)
+	node := "version: 1\nnodes:\n  pve:\n    - name: pve1\n      host: https://pve1.lan:8006\n      pollIntervalSeconds: 60\n      filters:\n        excludeVMIDs: [%s]\n"
+
+	decl, err := config.ParseDeclarativeConfig([]byte(fmt.Sprintf(node, "9000-9999")))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeConfig: %v", err)
+	}
+	if _, err := cp.PlanDeclarativeConfig(decl); err != nil {
+		t.Fatalf("expected valid filters to plan, got %v", err)
+	}
+
+	decl, err = config.ParseDeclarativeConfig([]byte(fmt.Sprintf(node, "9999-9000")))
+	if err != nil {
+		t.Fatalf("ParseDeclarativeConfig: %v", err)
+	}
+	if _, err := cp.PlanDeclarativeConfig(decl); err == nil || !strings.Contains(err.Error(), "9999-9000") {
+		t.Fatalf("expected an invalid VMID range error, got %v", err)
+	}
+}
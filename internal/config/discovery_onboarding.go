@@ -0,0 +1,226 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+)
+
+// Discovery onboarding modes
+const (
+	// DiscoveryOnboardingModeAuto adds servers that pass a connection test right away
+	DiscoveryOnboardingModeAuto = "auto"
+	// DiscoveryOnboardingModeApprove queues servers until an admin approves them
+	DiscoveryOnboardingModeApprove = "approve"
+
+	// DefaultDiscoveryMissingAfterScans is how many scans a server may be absent from before it
+	// is reported missing
+	DefaultDiscoveryMissingAfterScans = 3
+	// DefaultDiscoverySweepIntervalMinutes is how often a sweep runs when no interval is set
+	DefaultDiscoverySweepIntervalMinutes = 60
+	// MinDiscoverySweepIntervalMinutes keeps scheduled sweeps from flooding the network
+	MinDiscoverySweepIntervalMinutes = 5
+)
+
+// DiscoveryOnboardingConfig controls how servers found by discovery become monitored nodes
+type DiscoveryOnboardingConfig struct {
+	Enabled bool   `json:"enabled"`
+	Mode    string `json:"mode"`
+	// Allowlist holds CIDRs, IP addresses and hostname patterns such as "pve-*.lan". Servers
+	// outside it are never onboarded. Hostname patterns only match names that resolve back to
+	// the server's address.
+	Allowlist []string                     `json:"allowlist"`
+	Profiles  []DiscoveryCredentialProfile `json:"profiles"`
+	Sweeps    []DiscoverySweep             `json:"sweeps"`
+	// MissingAfterScans is how many consecutive scans a server may be absent from before it is
+	// reported missing
+	MissingAfterScans int `json:"missingAfterScans"`
+}
+
+// DiscoveryCredentialProfile is the API token used to onboard servers of one product type
+type DiscoveryCredentialProfile struct {
+	Type string `json:"type"` // "pve", "pbs" or "pmg"
+	// TokenName is the full token ID, e.g. pulse-monitor@pve!pulse
+	TokenName string `json:"tokenName"`
+	// TokenValue is the token secret, or a secret reference kept as-is in the added nodes
+	TokenValue string `json:"tokenValue,omitempty"`
+	// VerifySSL is required for automatic onboarding, which would otherwise send the token to any
+	// server that answers on an allowed address
+	VerifySSL bool `json:"verifySSL"`
+}
+
+// DiscoverySweep scans a fixed set of subnets on its own schedule, in addition to the
+// background discovery scan
+type DiscoverySweep struct {
+	Name            string   `json:"name"`
+	Subnets         []string `json:"subnets"`
+	IntervalMinutes int      `json:"intervalMinutes"`
+}
+
+// NormalizeDiscoveryOnboardingConfig cleans onboarding values and applies defaults.
+func NormalizeDiscoveryOnboardingConfig(cfg DiscoveryOnboardingConfig) DiscoveryOnboardingConfig {
+	normalized := cfg
+	normalized.Mode = strings.ToLower(strings.TrimSpace(cfg.Mode))
+	if normalized.Mode == "" {
+		normalized.Mode = DiscoveryOnboardingModeApprove
+	}
+	if normalized.MissingAfterScans <= 0 {
+		normalized.MissingAfterScans = DefaultDiscoveryMissingAfterScans
+	}
+	normalized.Allowlist = trimEntries(cfg.Allowlist)
+
+	profiles := make([]DiscoveryCredentialProfile, 0, len(cfg.Profiles))
+	for _, profile := range cfg.Profiles {
+		profile.Type = strings.ToLower(strings.TrimSpace(profile.Type))
+		profile.TokenName = strings.TrimSpace(profile.TokenName)
+		profile.TokenValue = strings.TrimSpace(profile.TokenValue)
+		profiles = append(profiles, profile)
+	}
+	normalized.Profiles = profiles
+
+	sweeps := make([]DiscoverySweep, 0, len(cfg.Sweeps))
+	for _, sweep := range cfg.Sweeps {
+		sweep.Name = strings.TrimSpace(sweep.Name)
+		sweep.Subnets = trimEntries(sweep.Subnets)
+		if sweep.IntervalMinutes <= 0 {
+			sweep.IntervalMinutes = DefaultDiscoverySweepIntervalMinutes
+		}
+		sweeps = append(sweeps, sweep)
+	}
+	normalized.Sweeps = sweeps
+	return normalized
+}
+
+// Validate returns an error for unusable onboarding settings.
+func (c DiscoveryOnboardingConfig) Validate() error {
+	if c.Mode != DiscoveryOnboardingModeAuto && c.Mode != DiscoveryOnboardingModeApprove {
+		return fmt.Errorf("mode must be %q or %q", DiscoveryOnboardingModeAuto, DiscoveryOnboardingModeApprove)
+	}
+	if c.Enabled && len(c.Allowlist) == 0 {
+		return fmt.Errorf("an allowlist is required to enable onboarding")
+	}
+	for _, entry := range c.Allowlist {
+		if strings.Contains(entry, "/") {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return fmt.Errorf("allowlist: invalid CIDR %q", entry)
+			}
+			continue
+		}
+		if _, err := path.Match(strings.ToLower(entry), ""); err != nil {
+			return fmt.Errorf("allowlist: invalid pattern %q", entry)
+		}
+	}
+
+	seenTypes := make(map[string]bool)
+	for _, profile := range c.Profiles {
+		if profile.Type != "pve" && profile.Type != "pbs" && profile.Type != "pmg" {
+			return fmt.Errorf("profile type must be pve, pbs or pmg, got %q", profile.Type)
+		}
+		if seenTypes[profile.Type] {
+			return fmt.Errorf("duplicate %s credential profile", profile.Type)
+		}
+		seenTypes[profile.Type] = true
+		if !strings.Contains(profile.TokenName, "!") {
+			return fmt.Errorf("%s profile: token name must be a full token ID such as pulse-monitor@%s!pulse", profile.Type, profile.Type)
+		}
+		if profile.TokenValue == "" {
+			return fmt.Errorf("%s profile: a token value is required", profile.Type)
+		}
+		if c.Enabled && c.Mode == DiscoveryOnboardingModeAuto && !profile.VerifySSL {
+			return fmt.Errorf("%s profile: automatic onboarding requires verifySSL; use approve mode for servers with self-signed certificates", profile.Type)
+		}
+	}
+
+	seenSweeps := make(map[string]bool)
+	for _, sweep := range c.Sweeps {
+		if sweep.Name == "" {
+			return fmt.Errorf("sweep without a name")
+		}
+		if seenSweeps[sweep.Name] {
+			return fmt.Errorf("duplicate sweep %q", sweep.Name)
+		}
+		seenSweeps[sweep.Name] = true
+		if len(sweep.Subnets) == 0 {
+			return fmt.Errorf("sweep %s: at least one subnet is required", sweep.Name)
+		}
+		for _, subnet := range sweep.Subnets {
+			if _, _, err := net.ParseCIDR(subnet); err != nil {
+				return fmt.Errorf("sweep %s: invalid subnet %q", sweep.Name, subnet)
+			}
+		}
+		if sweep.IntervalMinutes < MinDiscoverySweepIntervalMinutes {
+			return fmt.Errorf("sweep %s: interval must be at least %d minutes", sweep.Name, MinDiscoverySweepIntervalMinutes)
+		}
+	}
+	return nil
+}
+
+// Profile returns the credential profile for a product type
+func (c DiscoveryOnboardingConfig) Profile(serverType string) (DiscoveryCredentialProfile, bool) {
+	for _, profile := range c.Profiles {
+		if profile.Type == serverType {
+			return profile, true
+		}
+	}
+	return DiscoveryCredentialProfile{}, false
+}
+
+// HostLookupFunc resolves a hostname to its addresses
+type HostLookupFunc func(hostname string) ([]string, error)
+
+// Allows reports whether a server with the given IP and hostname matches the allowlist. The
+// hostname comes from the server's own API reply or its PTR record, so a hostname pattern only
+// counts when lookup resolves the name to the server's IP. A nil lookup matches addresses only.
+func (c DiscoveryOnboardingConfig) Allows(ip, hostname string, lookup HostLookupFunc) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+	nameMatches := false
+	for _, entry := range c.Allowlist {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if entryIP := net.ParseIP(entry); entryIP != nil {
+			if entryIP.Equal(addr) {
+				return true
+			}
+			continue
+		}
+		if hostname != "" {
+			if matched, _ := path.Match(strings.ToLower(entry), hostname); matched {
+				nameMatches = true
+			}
+		}
+	}
+	if !nameMatches || lookup == nil {
+		return false
+	}
+
+	addrs, err := lookup(hostname)
+	if err != nil {
+		return false
+	}
+	for _, resolved := range addrs {
+		if resolvedIP := net.ParseIP(resolved); resolvedIP != nil && resolvedIP.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimEntries drops blank entries and surrounding whitespace
+func trimEntries(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
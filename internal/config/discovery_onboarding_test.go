@@ -0,0 +1,92 @@
+package config
+
+import "testing"
+
+func TestDiscoveryOnboardingAllows(t *testing.T) {
+	cfg := NormalizeDiscoveryOnboardingConfig(DiscoveryOnboardingConfig{
+		Enabled:   true,
+		Allowlist: []string{" 10.0.10.0/24 ", "192.168.1.20", "pve-*.lan", ""},
+	})
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if cfg.Mode != DiscoveryOnboardingModeApprove || cfg.MissingAfterScans != DefaultDiscoveryMissingAfterScans {
+		t.Fatalf("unexpected defaults: mode=%q missingAfterScans=%d", cfg.Mode, cfg.MissingAfterScans)
+	}
+
+	dns := map[string][]string{"pve-3.lan": {"172.16.0.5"}, "pbs-1.lan": {"172.16.0.9"}}
+	lookup := func(hostname string) ([]string, error) {
+		return dns[hostname], nil
+	}
+
+	cases := []struct {
+		name     string
+		ip       string
+		hostname string
+		allow    bool
+	}{
+		{"inside CIDR", "10.0.10.7", "", true},
+		{"outside CIDR", "10.0.11.7", "", false},
+		{"exact address", "192.168.1.20", "", true},
+		{"hostname pattern", "172.16.0.5", "PVE-3.lan.", true},
+		{"other hostname", "172.16.0.5", "pbs-1.lan", false},
+		// A rogue host can report any name; it must resolve to the host's own address
+		{"hostile hostname", "172.16.0.66", "pve-3.lan", false},
+		{"unresolvable hostname", "172.16.0.67", "pve-evil.lan", false},
+	}
+	for _, tc := range cases {
+		if got := cfg.Allows(tc.ip, tc.hostname, lookup); got != tc.allow {
+			t.Errorf("%s: Allows = %v, want %v", tc.name, got, tc.allow)
+		}
+	}
+	if cfg.Allows("172.16.0.5", "pve-3.lan", nil) {
+		t.Error("expected hostname patterns to be ignored without a lookup")
+	}
+}
+
+func TestDiscoveryOnboardingValidate(t *testing.T) {
+	valid := DiscoveryOnboardingConfig{
+		Enabled:   true,
+		Mode:      "Auto",
+		Allowlist: []string{"10.0.0.0/16"},
+		Profiles: []DiscoveryCredentialProfile{
+			{Type: "PVE", TokenName: "pulse-monitor@pve!pulse", TokenValue: "secret", VerifySSL: true},
+			{Type: "pbs", TokenName: "pulse-monitor@pbs!pulse", TokenValue: "vault://secret/pulse#pbs", VerifySSL: true},
+		},
+		Sweeps: []DiscoverySweep{{Name: "lab", Subnets: []string{"10.0.20.0/24"}}},
+	}
+	cfg := NormalizeDiscoveryOnboardingConfig(valid)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if _, ok := cfg.Profile("pve"); !ok {
+		t.Fatal("expected the pve profile after normalization")
+	}
+	if cfg.Sweeps[0].IntervalMinutes != DefaultDiscoverySweepIntervalMinutes {
+		t.Fatalf("sweep interval = %d, want default", cfg.Sweeps[0].IntervalMinutes)
+	}
+
+	invalid := map[string]func(*DiscoveryOnboardingConfig){
+		"unknown mode":       func(c *DiscoveryOnboardingConfig) { c.Mode = "sometimes" },
+		"missing allowlist":  func(c *DiscoveryOnboardingConfig) { c.Allowlist = nil },
+		"bad CIDR":           func(c *DiscoveryOnboardingConfig) { c.Allowlist = []string{"10.0.0.0/99"} },
+		"duplicate profile":  func(c *DiscoveryOnboardingConfig) { c.Profiles = append(c.Profiles, c.Profiles[0]) },
+		"short token name":   func(c *DiscoveryOnboardingConfig) { c.Profiles[0].TokenName = "pulse" },
+		"missing token":      func(c *DiscoveryOnboardingConfig) { c.Profiles[0].TokenValue = "" },
+		"auto without TLS":   func(c *DiscoveryOnboardingConfig) { c.Profiles[1].VerifySSL = false },
+		"unknown type":       func(c *DiscoveryOnboardingConfig) { c.Profiles[0].Type = "pdm" },
+		"sweep interval":     func(c *DiscoveryOnboardingConfig) { c.Sweeps[0].IntervalMinutes = 1 },
+		"sweep subnet":       func(c *DiscoveryOnboardingConfig) { c.Sweeps[0].Subnets = []string{"lab"} },
+		"duplicate sweep":    func(c *DiscoveryOnboardingConfig) { c.Sweeps = append(c.Sweeps, c.Sweeps[0]) },
+		"sweep without name": func(c *DiscoveryOnboardingConfig) { c.Sweeps[0].Name = "" },
+	}
+	for name, mutate := range invalid {
+		broken := NormalizeDiscoveryOnboardingConfig(valid)
+		broken.Profiles = append([]DiscoveryCredentialProfile(nil), broken.Profiles...)
+		broken.Sweeps = append([]DiscoverySweep(nil), broken.Sweeps...)
+		mutate(&broken)
+		if err := broken.Validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
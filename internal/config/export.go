@@ -26,6 +26,9 @@ type ExportData struct {
 	Email         notifications.EmailConfig     `json:"email"`
 	Webhooks      []notifications.WebhookConfig `json:"webhooks"`
 	Apprise       notifications.AppriseConfig   `json:"apprise"`
+	Syslog        *notifications.SyslogConfig   `json:"syslog,omitempty"`
+	SNMP          *notifications.SNMPConfig     `json:"snmp,omitempty"`
+	ChatOps       *ChatOpsConfig                `json:"chatops,omitempty"`
 	System        SystemSettings                `json:"system"`
 	GuestMetadata map[string]*GuestMetadata     `json:"guestMetadata,omitempty"`
 	OIDC          *OIDCConfig                   `json:"oidc,omitempty"`
@@ -62,6 +65,21 @@ func (c *ConfigPersistence) ExportConfig(passphrase string) (string, error) {
 		return "", fmt.Errorf("failed to load Apprise config: %w", err)
 	}
 
+	syslogConfig, err := c.LoadSyslogConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load syslog config: %w", err)
+	}
+
+	snmpConfig, err := c.LoadSNMPConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load SNMP config: %w", err)
+	}
+
+	chatOpsConfig, err := c.LoadChatOpsConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load chat-ops config: %w", err)
+	}
+
 	webhooks, err := c.LoadWebhooks()
 	if err != nil {
 		return "", fmt.Errorf("failed to load webhooks: %w", err)
@@ -106,6 +124,9 @@ func (c *ConfigPersistence) ExportConfig(passphrase string) (string, error) {
 		Email:         *emailConfig,
 		Webhooks:      webhooks,
 		Apprise:       *appriseConfig,
+		Syslog:        syslogConfig,
+		SNMP:          snmpConfig,
+		ChatOps:       chatOpsConfig,
 		System:        *systemSettings,
 		GuestMetadata: guestMetadata,
 		OIDC:          oidcConfig,
@@ -195,6 +216,25 @@ func (c *ConfigPersistence) ImportConfig(encryptedData string, passphrase string
 		return fmt.Errorf("failed to import Apprise config: %w", err)
 	}
 
+	// Exports created before syslog, SNMP and chat-ops support leave those settings untouched
+	if exportData.Syslog != nil {
+		if err := c.SaveSyslogConfig(*exportData.Syslog); err != nil {
+			return fmt.Errorf("failed to import syslog config: %w", err)
+		}
+	}
+
+	if exportData.SNMP != nil {
+		if err := c.SaveSNMPConfig(*exportData.SNMP); err != nil {
+			return fmt.Errorf("failed to import SNMP config: %w", err)
+		}
+	}
+
+	if exportData.ChatOps != nil {
+		if err := c.SaveChatOpsConfig(*exportData.ChatOps); err != nil {
+			return fmt.Errorf("failed to import chat-ops config: %w", err)
+		}
+	}
+
 	if err := c.SaveWebhooks(exportData.Webhooks); err != nil {
 		return fmt.Errorf("failed to import webhooks: %w", err)
 	}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultFederationStaleSeconds is how long a site may go without an update before its data
+	// is marked stale
+	DefaultFederationStaleSeconds = 60
+	// DefaultFederationIntervalSeconds is how often downstream instances stream a snapshot
+	DefaultFederationIntervalSeconds = 10
+)
+
+var federationSiteIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// FederationConfig lists the downstream Pulse instances a central Pulse aggregates
+type FederationConfig struct {
+	Enabled         bool             `json:"enabled"`
+	IntervalSeconds int              `json:"intervalSeconds"`
+	StaleSeconds    int              `json:"staleSeconds"`
+	Sites           []FederationSite `json:"sites"`
+}
+
+// FederationSite is a downstream Pulse instance. Its resources are namespaced by ID.
+type FederationSite struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url"`
+	// Token is an API token of the downstream instance, or a secret reference. Binding the
+	// token to a tenant scope there limits what the site exports.
+	Token       string `json:"token,omitempty"`
+	VerifySSL   bool   `json:"verifySSL"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// DisplayName returns the site name, falling back to its ID
+func (s FederationSite) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.ID
+}
+
+// NormalizeFederationConfig cleans federation values and applies defaults.
+func NormalizeFederationConfig(cfg FederationConfig) FederationConfig {
+	normalized := cfg
+	if normalized.IntervalSeconds <= 0 {
+		normalized.IntervalSeconds = DefaultFederationIntervalSeconds
+	}
+	if normalized.StaleSeconds <= 0 {
+		normalized.StaleSeconds = DefaultFederationStaleSeconds
+	}
+	sites := make([]FederationSite, 0, len(cfg.Sites))
+	for _, site := range cfg.Sites {
+		site.ID = strings.ToLower(strings.TrimSpace(site.ID))
+		site.Name = strings.TrimSpace(site.Name)
+		site.URL = strings.TrimRight(strings.TrimSpace(site.URL), "/")
+		site.Token = strings.TrimSpace(site.Token)
+		site.Fingerprint = strings.TrimSpace(site.Fingerprint)
+		sites = append(sites, site)
+	}
+	normalized.Sites = sites
+	return normalized
+}
+
+// Validate returns an error for unusable sites.
+func (c FederationConfig) Validate() error {
+	if c.StaleSeconds < c.IntervalSeconds {
+		return fmt.Errorf("staleSeconds must be at least intervalSeconds")
+	}
+	seen := make(map[string]bool)
+	for _, site := range c.Sites {
+		if !federationSiteIDPattern.MatchString(site.ID) {
+			return fmt.Errorf("site ID %q must be lowercase letters, digits, '-' or '_'", site.ID)
+		}
+		if seen[site.ID] {
+			return fmt.Errorf("duplicate site ID %q", site.ID)
+		}
+		seen[site.ID] = true
+		u, err := url.Parse(site.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("site %s: invalid URL %q", site.ID, site.URL)
+		}
+		if site.Token == "" {
+			return fmt.Errorf("site %s: an API token is required", site.ID)
+		}
+	}
+	return nil
+}
+
+// Site returns the site with the given ID
+func (c FederationConfig) Site(id string) (FederationSite, bool) {
+	for _, site := range c.Sites {
+		if site.ID == id {
+			return site, true
+		}
+	}
+	return FederationSite{}, false
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HALockFile coordinates leadership through a lease file on storage shared by both instances
+	HALockFile = "file"
+	// HALockPeer coordinates leadership through a lease negotiated with the peer over HTTP
+	HALockPeer = "peer"
+
+	// DefaultHALeaseTTL is how long a leader keeps its lease without renewing it, and so roughly
+	// how long a takeover takes
+	DefaultHALeaseTTL = 15 * time.Second
+	// DefaultHASyncInterval is how often a standby copies the leader's data directory
+	DefaultHASyncInterval = 30 * time.Second
+
+	minHALeaseTTL     = 3 * time.Second
+	minHASecretLength = 16
+)
+
+// HAConfig configures active/passive high availability. It is read from the environment only so
+// that each instance keeps its own identity when the data directory is replicated.
+type HAConfig struct {
+	Enabled bool
+	// NodeID identifies this instance in the lease, defaulting to the hostname
+	NodeID string
+	// Lock is HALockFile or HALockPeer
+	Lock     string
+	LockFile string
+	PeerURL  string
+	// AdvertiseURL is where the other instance reaches this one, defaulting to the public URL
+	AdvertiseURL string
+	// Secret is shared by both instances to authenticate lease and replication requests. It may
+	// be a secret reference.
+	Secret       string
+	VerifySSL    bool
+	LeaseTTL     time.Duration
+	SyncInterval time.Duration
+}
+
+// LoadHAConfigFromEnv reads the PULSE_HA_* environment variables
+func LoadHAConfigFromEnv() (HAConfig, error) {
+	cfg := HAConfig{
+		Lock:         HALockFile,
+		VerifySSL:    true,
+		LeaseTTL:     DefaultHALeaseTTL,
+		SyncInterval: DefaultHASyncInterval,
+	}
+	if value := strings.TrimSpace(os.Getenv("PULSE_HA_ENABLED")); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid PULSE_HA_ENABLED %q", value)
+		}
+		cfg.Enabled = enabled
+	}
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+
+	cfg.NodeID = strings.TrimSpace(os.Getenv("PULSE_HA_NODE_ID"))
+	if cfg.NodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return cfg, fmt.Errorf("PULSE_HA_NODE_ID is required: %w", err)
+		}
+		cfg.NodeID = hostname
+	}
+	if value := strings.ToLower(strings.TrimSpace(os.Getenv("PULSE_HA_LOCK"))); value != "" {
+		cfg.Lock = value
+	}
+	cfg.LockFile = strings.TrimSpace(os.Getenv("PULSE_HA_LOCK_FILE"))
+	cfg.PeerURL = strings.TrimRight(strings.TrimSpace(os.Getenv("PULSE_HA_PEER_URL")), "/")
+	cfg.AdvertiseURL = strings.TrimRight(strings.TrimSpace(os.Getenv("PULSE_HA_ADVERTISE_URL")), "/")
+	cfg.Secret = strings.TrimSpace(os.Getenv("PULSE_HA_SECRET"))
+	if value := strings.TrimSpace(os.Getenv("PULSE_HA_VERIFY_SSL")); value != "" {
+		verify, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid PULSE_HA_VERIFY_SSL %q", value)
+		}
+		cfg.VerifySSL = verify
+	}
+	for name, target := range map[string]*time.Duration{
+		"PULSE_HA_LEASE_TTL":     &cfg.LeaseTTL,
+		"PULSE_HA_SYNC_INTERVAL": &cfg.SyncInterval,
+	} {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return cfg, fmt.Errorf("invalid %s %q", name, value)
+		}
+		*target = duration
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate returns an error for an unusable HA setup
+func (c HAConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Lock {
+	case HALockFile:
+		if c.LockFile == "" {
+			return fmt.Errorf("PULSE_HA_LOCK_FILE is required for the file lock")
+		}
+	case HALockPeer:
+		if err := validateHAURL("PULSE_HA_PEER_URL", c.PeerURL); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("PULSE_HA_LOCK must be %q or %q", HALockFile, HALockPeer)
+	}
+	if c.AdvertiseURL != "" {
+		if err := validateHAURL("PULSE_HA_ADVERTISE_URL", c.AdvertiseURL); err != nil {
+			return err
+		}
+	}
+	if len(c.Secret) < minHASecretLength && !IsSecretReference(c.Secret) {
+		return fmt.Errorf("PULSE_HA_SECRET must be at least %d characters", minHASecretLength)
+	}
+	if c.LeaseTTL < minHALeaseTTL {
+		return fmt.Errorf("PULSE_HA_LEASE_TTL must be at least %s", minHALeaseTTL)
+	}
+	return nil
+}
+
+func validateHAURL(name, value string) error {
+	u, err := url.Parse(value)
+	if value == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http(s) URL", name)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadHAConfigFromEnv(t *testing.T) {
+	t.Setenv("PULSE_HA_ENABLED", "false")
+	if cfg, err := LoadHAConfigFromEnv(); err != nil || cfg.Enabled {
+		t.Fatalf("disabled = %+v, %v", cfg, err)
+	}
+
+	t.Setenv("PULSE_HA_ENABLED", "true")
+	t.Setenv("PULSE_HA_NODE_ID", "pulse-a")
+	t.Setenv("PULSE_HA_LOCK", "peer")
+	t.Setenv("PULSE_HA_PEER_URL", "https://pulse-b:7655/")
+	t.Setenv("PULSE_HA_SECRET", "shared-secret-value")
+	t.Setenv("PULSE_HA_LEASE_TTL", "10s")
+	cfg, err := LoadHAConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadHAConfigFromEnv: %v", err)
+	}
+	if cfg.NodeID != "pulse-a" || cfg.PeerURL != "https://pulse-b:7655" || cfg.LeaseTTL != 10*time.Second ||
+		cfg.SyncInterval != DefaultHASyncInterval || !cfg.VerifySSL {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	t.Setenv("PULSE_HA_SECRET", "short")
+	if _, err := LoadHAConfigFromEnv(); err == nil {
+		t.Fatal("expected a short secret to be rejected")
+	}
+	t.Setenv("PULSE_HA_SECRET", "shared-secret-value")
+	t.Setenv("PULSE_HA_LOCK", "file")
+	if _, err := LoadHAConfigFromEnv(); err == nil {
+		t.Fatal("expected the file lock to require PULSE_HA_LOCK_FILE")
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultInstanceBackupIntervalHours is how often scheduled backups run
+	DefaultInstanceBackupIntervalHours = 24
+	// DefaultInstanceBackupRetain is how many scheduled backups are kept on the target
+	DefaultInstanceBackupRetain = 7
+)
+
+// InstanceBackupConfig schedules backups of the whole Pulse data directory
+type InstanceBackupConfig struct {
+	Enabled       bool                   `json:"enabled"`
+	IntervalHours int                    `json:"intervalHours"`
+	Retain        int                    `json:"retain"`
+	Target        string                 `json:"target"`              // local or s3
+	LocalPath     string                 `json:"localPath,omitempty"` // Empty uses <data>/backups
+	S3            InstanceBackupS3Config `json:"s3,omitempty"`
+}
+
+// InstanceBackupS3Config is an S3-compatible backup target. SecretAccessKey may be a secret
+// reference such as vault://secret/pulse/s3#secret_key.
+type InstanceBackupS3Config struct {
+	Endpoint        string `json:"endpoint,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Bucket          string `json:"bucket,omitempty"`
+	Prefix          string `json:"prefix,omitempty"`
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	PathStyle       bool   `json:"pathStyle,omitempty"`
+}
+
+// NormalizeInstanceBackupConfig cleans backup schedule values and applies defaults.
+func NormalizeInstanceBackupConfig(cfg InstanceBackupConfig) InstanceBackupConfig {
+	normalized := cfg
+	if normalized.IntervalHours <= 0 {
+		normalized.IntervalHours = DefaultInstanceBackupIntervalHours
+	}
+	if normalized.Retain <= 0 {
+		normalized.Retain = DefaultInstanceBackupRetain
+	}
+	normalized.Target = strings.ToLower(strings.TrimSpace(normalized.Target))
+	if normalized.Target == "" {
+		normalized.Target = "local"
+	}
+	normalized.LocalPath = strings.TrimSpace(normalized.LocalPath)
+	normalized.S3.Endpoint = strings.TrimSpace(normalized.S3.Endpoint)
+	normalized.S3.Region = strings.TrimSpace(normalized.S3.Region)
+	normalized.S3.Bucket = strings.TrimSpace(normalized.S3.Bucket)
+	normalized.S3.Prefix = strings.Trim(strings.TrimSpace(normalized.S3.Prefix), "/")
+	normalized.S3.AccessKeyID = strings.TrimSpace(normalized.S3.AccessKeyID)
+	return normalized
+}
+
+// Validate returns an error for an incomplete target.
+func (c InstanceBackupConfig) Validate() error {
+	switch c.Target {
+	case "local":
+	case "s3":
+		if c.S3.Bucket == "" {
+			return fmt.Errorf("s3 bucket is required")
+		}
+		if c.S3.AccessKeyID == "" || c.S3.SecretAccessKey == "" {
+			return fmt.Errorf("s3 access key ID and secret access key are required")
+		}
+	default:
+		return fmt.Errorf("unsupported backup target %q", c.Target)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// KeyRotationResult summarises a completed encryption key rotation
+type KeyRotationResult struct {
+	KeyVersion  uint32
+	Reencrypted []string // File names rewritten with the new key
+	Skipped     []string // Files that could not be decrypted and were left unchanged
+}
+
+// RotateEncryptionKey generates a new data key and re-encrypts every encrypted file in the
+// config directory, including node config backups, in a single transaction. Older keys are
+// removed from the keyring once all files have been rewritten. A running server could write
+// files with the removed key, so rotation fails with ErrDataDirInUse while one holds the directory.
+func (c *ConfigPersistence) RotateEncryptionKey() (*KeyRotationResult, error) {
+	if c.crypto == nil {
+		if c.cryptoErr != nil {
+			return nil, fmt.Errorf("encryption is not available: %w", c.cryptoErr)
+		}
+		return nil, fmt.Errorf("encryption is not enabled")
+	}
+
+	lock, err := LockDataDirExclusive(c.configDir)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := c.encryptedFilesLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KeyRotationResult{}
+	plaintexts := make(map[string][]byte, len(files))
+	perms := make(map[string]os.FileMode, len(files))
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", filepath.Base(path), err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+		}
+		plaintext, err := c.crypto.Decrypt(data)
+		if err != nil {
+			log.Warn().Err(err).Str("file", path).Msg("Skipping file that cannot be decrypted during key rotation")
+			result.Skipped = append(result.Skipped, filepath.Base(path))
+			continue
+		}
+		plaintexts[path] = plaintext
+		perms[path] = info.Mode().Perm()
+	}
+
+	rotation, err := c.crypto.BeginRotation()
+	if err != nil {
+		return nil, fmt.Errorf("failed to add new key to keyring: %w", err)
+	}
+	result.KeyVersion = rotation.Version()
+
+	tx, err := newImportTransaction(c.configDir)
+	if err != nil {
+		_ = rotation.Abort()
+		return nil, fmt.Errorf("failed to start rotation transaction: %w", err)
+	}
+	defer tx.Cleanup()
+
+	staged := false
+	defer func() {
+		if !staged {
+			tx.Rollback()
+			if err := rotation.Abort(); err != nil {
+				log.Warn().Err(err).Msg("Failed to remove unused key from keyring")
+			}
+		}
+	}()
+
+	for _, path := range files {
+		plaintext, ok := plaintexts[path]
+		if !ok {
+			continue
+		}
+		ciphertext, err := rotation.Encrypt(plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", filepath.Base(path), err)
+		}
+		if err := tx.StageFile(path, ciphertext, perms[path]); err != nil {
+			return nil, err
+		}
+		result.Reencrypted = append(result.Reencrypted, filepath.Base(path))
+	}
+
+	// From here on files may carry the new key version, so it has to stay in the keyring
+	staged = true
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to replace encrypted files: %w", err)
+	}
+	if err := rotation.Commit(); err != nil {
+		return nil, fmt.Errorf("files were re-encrypted with key version %d but the keyring could not be updated: %w", rotation.Version(), err)
+	}
+
+	log.Info().
+		Uint32("keyVersion", result.KeyVersion).
+		Int("files", len(result.Reencrypted)).
+		Int("skipped", len(result.Skipped)).
+		Msg("Rotated encryption key")
+	return result, nil
+}
+
+// encryptedFilesLocked lists the encrypted config files and their backups
+func (c *ConfigPersistence) encryptedFilesLocked() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(c.configDir, "*.enc*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, path := range matches {
+		name := filepath.Base(path)
+		// Hidden files include the keyring itself and transaction staging directories
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".tmp") || strings.Contains(name, ".import-backup-") {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+			continue
+		}
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
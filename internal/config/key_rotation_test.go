@@ -0,0 +1,134 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/crypto"
+	"github.com/RouXx67/PulseUp/internal/notifications"
+)
+
+func TestRotateEncryptionKeyReencryptsAllFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", tempDir)
+	cp := config.NewConfigPersistence(tempDir)
+
+	nodes := []config.PVEInstance{{Name: "pve1", Host: "https://pve1:8006", TokenValue: "secret"}}
+	if err := cp.SaveNodesConfig(nodes, nil, nil); err != nil {
+		t.Fatalf("SaveNodesConfig: %v", err)
+	}
+	// Saving again leaves a backup of the previous nodes.enc behind
+	if err := cp.SaveNodesConfig(nodes, nil, nil); err != nil {
+		t.Fatalf("SaveNodesConfig: %v", err)
+	}
+	if err := cp.SaveEmailConfig(notifications.EmailConfig{SMTPHost: "smtp.lan", Password: "hunter2"}); err != nil {
+		t.Fatalf("SaveEmailConfig: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "snmp.enc.corrupted-20240101-000000"), []byte("garbage"), 0600); err != nil {
+		t.Fatalf("write corrupted file: %v", err)
+	}
+
+	result, err := cp.RotateEncryptionKey()
+	if err != nil {
+		t.Fatalf("RotateEncryptionKey: %v", err)
+	}
+	if result.KeyVersion != 2 {
+		t.Fatalf("expected key version 2, got %d", result.KeyVersion)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "snmp.enc.corrupted-20240101-000000" {
+		t.Fatalf("expected the corrupted file to be skipped, got %v", result.Skipped)
+	}
+
+	for _, name := range append([]string{"nodes.enc.backup"}, result.Reencrypted...) {
+		data, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if !bytes.HasPrefix(data, []byte("PENC\x01\x00\x00\x00\x02")) {
+			t.Fatalf("expected %s to be encrypted with key version 2", name)
+		}
+	}
+
+	reloaded := config.NewConfigPersistence(tempDir)
+	loaded, err := reloaded.LoadNodesConfig()
+	if err != nil {
+		t.Fatalf("LoadNodesConfig: %v", err)
+	}
+	if len(loaded.PVEInstances) != 1 || loaded.PVEInstances[0].TokenValue != "secret" {
+		t.Fatalf("unexpected nodes after rotation: %+v", loaded.PVEInstances)
+	}
+	email, err := reloaded.LoadEmailConfig()
+	if err != nil || email.Password != "hunter2" {
+		t.Fatalf("unexpected email config after rotation: %+v (%v)", email, err)
+	}
+}
+
+func TestLockedKeyringRefusesPlaintextWrites(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", tempDir)
+	t.Setenv("PULSE_MASTER_PASSPHRASE", "")
+	cp := config.NewConfigPersistence(tempDir)
+	if err := cp.SaveNodesConfig([]config.PVEInstance{{Name: "pve1", Host: "https://pve1:8006"}}, nil, nil); err != nil {
+		t.Fatalf("SaveNodesConfig: %v", err)
+	}
+
+	cm, err := crypto.NewCryptoManager()
+	if err != nil {
+		t.Fatalf("NewCryptoManager: %v", err)
+	}
+	wrapper, err := crypto.NewPassphraseWrapper("passphrase")
+	if err != nil {
+		t.Fatalf("NewPassphraseWrapper: %v", err)
+	}
+	if err := cm.SetKeyWrapper(wrapper); err != nil {
+		t.Fatalf("SetKeyWrapper: %v", err)
+	}
+
+	locked := config.NewConfigPersistence(tempDir)
+	if !errors.Is(locked.EncryptionError(), crypto.ErrMasterKeyLocked) {
+		t.Fatalf("expected a locked keyring, got %v", locked.EncryptionError())
+	}
+	before, _ := os.ReadFile(filepath.Join(tempDir, "nodes.enc"))
+	if err := locked.SaveNodesConfig([]config.PVEInstance{{Name: "pve2", Host: "https://pve2:8006"}}, nil, nil); err == nil {
+		t.Fatalf("expected saving nodes to fail while the keyring is locked")
+	}
+	after, _ := os.ReadFile(filepath.Join(tempDir, "nodes.enc"))
+	if !bytes.Equal(before, after) {
+		t.Fatalf("nodes.enc must not be modified while the keyring is locked")
+	}
+	if _, err := config.Load(); !errors.Is(err, crypto.ErrMasterKeyLocked) {
+		t.Fatalf("expected Load to fail while the keyring is locked, got %v", err)
+	}
+}
+
+func TestRotateEncryptionKeyRefusesWhileServerRuns(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", tempDir)
+	cp := config.NewConfigPersistence(tempDir)
+	if err := cp.SaveNodesConfig([]config.PVEInstance{{Name: "pve1", Host: "https://pve1:8006"}}, nil, nil); err != nil {
+		t.Fatalf("SaveNodesConfig: %v", err)
+	}
+
+	server, err := config.LockDataDirShared(tempDir)
+	if err != nil {
+		t.Fatalf("LockDataDirShared: %v", err)
+	}
+	before, _ := os.ReadFile(filepath.Join(tempDir, "nodes.enc"))
+	if _, err := cp.RotateEncryptionKey(); !errors.Is(err, config.ErrDataDirInUse) {
+		t.Fatalf("expected rotation to be refused while a server holds the data directory, got %v", err)
+	}
+	if after, _ := os.ReadFile(filepath.Join(tempDir, "nodes.enc")); !bytes.Equal(before, after) {
+		t.Fatal("nodes.enc must not be modified by a refused rotation")
+	}
+
+	if err := server.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := cp.RotateEncryptionKey(); err != nil {
+		t.Fatalf("RotateEncryptionKey after the server stopped: %v", err)
+	}
+}
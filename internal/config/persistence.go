@@ -3,6 +3,7 @@ package config
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,18 +20,29 @@ import (
 
 // ConfigPersistence handles saving and loading configuration
 type ConfigPersistence struct {
-	mu            sync.RWMutex
-	tx            *importTransaction
-	configDir     string
-	alertFile     string
-	emailFile     string
-	webhookFile   string
-	appriseFile   string
-	nodesFile     string
-	systemFile    string
-	oidcFile      string
-	apiTokensFile string
-	crypto        *crypto.CryptoManager
+	mu             sync.RWMutex
+	tx             *importTransaction
+	configDir      string
+	alertFile      string
+	emailFile      string
+	webhookFile    string
+	appriseFile    string
+	syslogFile     string
+	snmpFile       string
+	snmpEngineFile string
+	chatOpsFile    string
+	retentionFile  string
+	vzdumpFile     string
+	nodesFile      string
+	systemFile     string
+	oidcFile       string
+	apiTokensFile  string
+	tenantFile     string
+	instBackupFile string
+	federationFile string
+	onboardingFile string
+	crypto         *crypto.CryptoManager
+	cryptoErr      error
 }
 
 // NewConfigPersistence creates a new config persistence manager
@@ -47,16 +59,27 @@ func NewConfigPersistence(configDir string) *ConfigPersistence {
 	}
 
 	cp := &ConfigPersistence{
-		configDir:     configDir,
-		alertFile:     filepath.Join(configDir, "alerts.json"),
-		emailFile:     filepath.Join(configDir, "email.enc"),
-		webhookFile:   filepath.Join(configDir, "webhooks.enc"),
-		appriseFile:   filepath.Join(configDir, "apprise.enc"),
-		nodesFile:     filepath.Join(configDir, "nodes.enc"),
-		systemFile:    filepath.Join(configDir, "system.json"),
-		oidcFile:      filepath.Join(configDir, "oidc.enc"),
-		apiTokensFile: filepath.Join(configDir, "api_tokens.json"),
-		crypto:        cryptoMgr,
+		configDir:      configDir,
+		alertFile:      filepath.Join(configDir, "alerts.json"),
+		emailFile:      filepath.Join(configDir, "email.enc"),
+		webhookFile:    filepath.Join(configDir, "webhooks.enc"),
+		appriseFile:    filepath.Join(configDir, "apprise.enc"),
+		syslogFile:     filepath.Join(configDir, "syslog.enc"),
+		snmpFile:       filepath.Join(configDir, "snmp.enc"),
+		snmpEngineFile: filepath.Join(configDir, "snmp_engine.json"),
+		chatOpsFile:    filepath.Join(configDir, "chatops.enc"),
+		retentionFile:  filepath.Join(configDir, "snapshot_retention.json"),
+		vzdumpFile:     filepath.Join(configDir, "backup_remediation.json"),
+		nodesFile:      filepath.Join(configDir, "nodes.enc"),
+		systemFile:     filepath.Join(configDir, "system.json"),
+		oidcFile:       filepath.Join(configDir, "oidc.enc"),
+		apiTokensFile:  filepath.Join(configDir, "api_tokens.json"),
+		tenantFile:     filepath.Join(configDir, "tenant_scopes.json"),
+		instBackupFile: filepath.Join(configDir, "instance_backup.enc"),
+		federationFile: filepath.Join(configDir, "federation.enc"),
+		onboardingFile: filepath.Join(configDir, "discovery_onboarding.enc"),
+		crypto:         cryptoMgr,
+		cryptoErr:      err,
 	}
 
 	log.Debug().
@@ -88,7 +111,16 @@ func (c *ConfigPersistence) endTransaction(tx *importTransaction) {
 	c.mu.Unlock()
 }
 
+// EncryptionError returns the error that prevented the crypto manager from starting, if any
+func (c *ConfigPersistence) EncryptionError() error {
+	return c.cryptoErr
+}
+
 func (c *ConfigPersistence) writeConfigFileLocked(path string, data []byte, perm os.FileMode) error {
+	if c.crypto == nil && errors.Is(c.cryptoErr, crypto.ErrMasterKeyLocked) && strings.HasSuffix(path, ".enc") {
+		// Never replace encrypted files with plaintext because the keyring could not be unlocked
+		return fmt.Errorf("refusing to write %s unencrypted: %w", filepath.Base(path), c.cryptoErr)
+	}
 	if c.tx != nil {
 		return c.tx.StageFile(path, data, perm)
 	}
@@ -562,6 +594,607 @@ func (c *ConfigPersistence) LoadAppriseConfig() (*notifications.AppriseConfig, e
 	return &normalized, nil
 }
 
+// SaveSyslogConfig saves Syslog configuration to file (encrypted if available)
+func (c *ConfigPersistence) SaveSyslogConfig(config notifications.SyslogConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = notifications.NormalizeSyslogConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if c.crypto != nil {
+		encrypted, err := c.crypto.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+
+	if err := c.writeConfigFileLocked(c.syslogFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.syslogFile).
+		Bool("encrypted", c.crypto != nil).
+		Msg("Syslog configuration saved")
+	return nil
+}
+
+// LoadSyslogConfig loads Syslog configuration from file (decrypts if encrypted)
+func (c *ConfigPersistence) LoadSyslogConfig() (*notifications.SyslogConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.syslogFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := notifications.NormalizeSyslogConfig(notifications.SyslogConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	if c.crypto != nil {
+		decrypted, err := c.crypto.Decrypt(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decrypted
+	}
+
+	var config notifications.SyslogConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := notifications.NormalizeSyslogConfig(config)
+
+	log.Info().
+		Str("file", c.syslogFile).
+		Bool("encrypted", c.crypto != nil).
+		Msg("Syslog configuration loaded")
+	return &normalized, nil
+}
+
+// SaveSNMPConfig saves SNMP trap configuration to file (encrypted if available)
+func (c *ConfigPersistence) SaveSNMPConfig(config notifications.SNMPConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = notifications.NormalizeSNMPConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if c.crypto != nil {
+		encrypted, err := c.crypto.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+
+	if err := c.writeConfigFileLocked(c.snmpFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.snmpFile).
+		Bool("encrypted", c.crypto != nil).
+		Msg("SNMP trap configuration saved")
+	return nil
+}
+
+// snmpEngineState persists the SNMPv3 engine boot counter across restarts
+type snmpEngineState struct {
+	EngineBoots uint32 `json:"engineBoots"`
+}
+
+// snmpMaxEngineBoots is the largest snmpEngineBoots value; RFC 3414 keeps it there once reached
+const snmpMaxEngineBoots = 2147483647
+
+// NextSNMPEngineBoots increments and returns the SNMPv3 snmpEngineBoots counter. It is called
+// each time the notification manager starts so trap receivers accept the engine time restarting at zero.
+func (c *ConfigPersistence) NextSNMPEngineBoots() (uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var state snmpEngineState
+	data, err := os.ReadFile(c.snmpEngineFile)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", filepath.Base(c.snmpEngineFile), err)
+		}
+	}
+	if state.EngineBoots < snmpMaxEngineBoots {
+		state.EngineBoots++
+	}
+
+	data, err = json.Marshal(state)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.EnsureConfigDir(); err != nil {
+		return 0, err
+	}
+	if err := c.writeConfigFileLocked(c.snmpEngineFile, data, 0600); err != nil {
+		return 0, err
+	}
+	return state.EngineBoots, nil
+}
+
+// LoadSNMPConfig loads SNMP trap configuration from file (decrypts if encrypted)
+func (c *ConfigPersistence) LoadSNMPConfig() (*notifications.SNMPConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.snmpFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := notifications.NormalizeSNMPConfig(notifications.SNMPConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	if c.crypto != nil {
+		decrypted, err := c.crypto.Decrypt(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decrypted
+	}
+
+	var config notifications.SNMPConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := notifications.NormalizeSNMPConfig(config)
+
+	log.Info().
+		Str("file", c.snmpFile).
+		Bool("encrypted", c.crypto != nil).
+		Msg("SNMP trap configuration loaded")
+	return &normalized, nil
+}
+
+// SaveChatOpsConfig saves chat-ops configuration to file (encrypted if available)
+func (c *ConfigPersistence) SaveChatOpsConfig(config ChatOpsConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = NormalizeChatOpsConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if c.crypto != nil {
+		encrypted, err := c.crypto.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+
+	if err := c.writeConfigFileLocked(c.chatOpsFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.chatOpsFile).
+		Bool("encrypted", c.crypto != nil).
+		Msg("Chat-ops configuration saved")
+	return nil
+}
+
+// LoadChatOpsConfig loads chat-ops configuration from file (decrypts if encrypted)
+func (c *ConfigPersistence) LoadChatOpsConfig() (*ChatOpsConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.chatOpsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := NormalizeChatOpsConfig(ChatOpsConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	if c.crypto != nil {
+		decrypted, err := c.crypto.Decrypt(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decrypted
+	}
+
+	var config ChatOpsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeChatOpsConfig(config)
+	return &normalized, nil
+}
+
+// SaveSnapshotRetentionConfig saves snapshot retention policies to file
+func (c *ConfigPersistence) SaveSnapshotRetentionConfig(config SnapshotRetentionConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = NormalizeSnapshotRetentionConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if err := c.writeConfigFileLocked(c.retentionFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.retentionFile).
+		Int("policies", len(config.Policies)).
+		Msg("Snapshot retention configuration saved")
+	return nil
+}
+
+// LoadSnapshotRetentionConfig loads snapshot retention policies from file
+func (c *ConfigPersistence) LoadSnapshotRetentionConfig() (*SnapshotRetentionConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.retentionFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := NormalizeSnapshotRetentionConfig(SnapshotRetentionConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	var config SnapshotRetentionConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeSnapshotRetentionConfig(config)
+	return &normalized, nil
+}
+
+// SaveTenantScopesConfig saves tenant scopes to file
+func (c *ConfigPersistence) SaveTenantScopesConfig(config TenantScopesConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = NormalizeTenantScopesConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if err := c.writeConfigFileLocked(c.tenantFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.tenantFile).
+		Int("scopes", len(config.Scopes)).
+		Msg("Tenant scopes configuration saved")
+	return nil
+}
+
+// LoadTenantScopesConfig loads tenant scopes from file
+func (c *ConfigPersistence) LoadTenantScopesConfig() (*TenantScopesConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.tenantFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &TenantScopesConfig{Scopes: []TenantScope{}}, nil
+		}
+		return nil, err
+	}
+
+	var config TenantScopesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeTenantScopesConfig(config)
+	return &normalized, nil
+}
+
+// SaveBackupRemediationConfig saves the automatic backup remediation policy to file
+func (c *ConfigPersistence) SaveBackupRemediationConfig(config BackupRemediationConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = NormalizeBackupRemediationConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if err := c.writeConfigFileLocked(c.vzdumpFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.vzdumpFile).
+		Bool("enabled", config.Enabled).
+		Msg("Backup remediation configuration saved")
+	return nil
+}
+
+// LoadBackupRemediationConfig loads the automatic backup remediation policy from file
+func (c *ConfigPersistence) LoadBackupRemediationConfig() (*BackupRemediationConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.vzdumpFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := NormalizeBackupRemediationConfig(BackupRemediationConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	var config BackupRemediationConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeBackupRemediationConfig(config)
+	return &normalized, nil
+}
+
+// SaveInstanceBackupConfig saves the data directory backup schedule to file (encrypted if available)
+func (c *ConfigPersistence) SaveInstanceBackupConfig(config InstanceBackupConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = NormalizeInstanceBackupConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if c.crypto != nil {
+		encrypted, err := c.crypto.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+
+	if err := c.writeConfigFileLocked(c.instBackupFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.instBackupFile).
+		Bool("enabled", config.Enabled).
+		Bool("encrypted", c.crypto != nil).
+		Msg("Instance backup configuration saved")
+	return nil
+}
+
+// LoadInstanceBackupConfig loads the data directory backup schedule from file
+func (c *ConfigPersistence) LoadInstanceBackupConfig() (*InstanceBackupConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.instBackupFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := NormalizeInstanceBackupConfig(InstanceBackupConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	if c.crypto != nil {
+		decrypted, err := c.crypto.Decrypt(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decrypted
+	}
+
+	var config InstanceBackupConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeInstanceBackupConfig(config)
+	return &normalized, nil
+}
+
+// SaveFederationConfig saves the downstream Pulse instances to file (encrypted if available)
+func (c *ConfigPersistence) SaveFederationConfig(config FederationConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = NormalizeFederationConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if c.crypto != nil {
+		encrypted, err := c.crypto.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+
+	if err := c.writeConfigFileLocked(c.federationFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.federationFile).
+		Int("sites", len(config.Sites)).
+		Bool("encrypted", c.crypto != nil).
+		Msg("Federation configuration saved")
+	return nil
+}
+
+// LoadFederationConfig loads the downstream Pulse instances from file
+func (c *ConfigPersistence) LoadFederationConfig() (*FederationConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.federationFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := NormalizeFederationConfig(FederationConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	if c.crypto != nil {
+		decrypted, err := c.crypto.Decrypt(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decrypted
+	}
+
+	var config FederationConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeFederationConfig(config)
+	return &normalized, nil
+}
+
+// SaveDiscoveryOnboardingConfig saves the discovery onboarding settings and credential profiles
+func (c *ConfigPersistence) SaveDiscoveryOnboardingConfig(config DiscoveryOnboardingConfig) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config = NormalizeDiscoveryOnboardingConfig(config)
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := c.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	if c.crypto != nil {
+		encrypted, err := c.crypto.Encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+
+	if err := c.writeConfigFileLocked(c.onboardingFile, data, 0600); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("file", c.onboardingFile).
+		Bool("enabled", config.Enabled).
+		Int("profiles", len(config.Profiles)).
+		Int("sweeps", len(config.Sweeps)).
+		Bool("encrypted", c.crypto != nil).
+		Msg("Discovery onboarding configuration saved")
+	return nil
+}
+
+// LoadDiscoveryOnboardingConfig loads the discovery onboarding settings from file
+func (c *ConfigPersistence) LoadDiscoveryOnboardingConfig() (*DiscoveryOnboardingConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, err := os.ReadFile(c.onboardingFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			defaultCfg := NormalizeDiscoveryOnboardingConfig(DiscoveryOnboardingConfig{})
+			return &defaultCfg, nil
+		}
+		return nil, err
+	}
+
+	if c.crypto != nil {
+		decrypted, err := c.crypto.Decrypt(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decrypted
+	}
+
+	var config DiscoveryOnboardingConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeDiscoveryOnboardingConfig(config)
+	return &normalized, nil
+}
+
 // SaveWebhooks saves webhook configurations to file
 func (c *ConfigPersistence) SaveWebhooks(webhooks []notifications.WebhookConfig) error {
 	c.mu.Lock()
@@ -731,6 +1364,10 @@ type SystemSettings struct {
 	AutoUpdateEnabled           bool            `json:"autoUpdateEnabled"` // Removed omitempty so false is saved
 	AutoUpdateCheckInterval     int             `json:"autoUpdateCheckInterval,omitempty"`
 	AutoUpdateTime              string          `json:"autoUpdateTime,omitempty"`
+	AutoUpdateWindowEnd         string          `json:"autoUpdateWindowEnd,omitempty"`
+	AutoUpdateRollback          *bool           `json:"autoUpdateRollback,omitempty"`
+	AutoUpdateRolloutHours      *int            `json:"autoUpdateRolloutHours,omitempty"`
+	UpdateSource                string          `json:"updateSource,omitempty"`
 	LogLevel                    string          `json:"logLevel,omitempty"`
 	DiscoveryEnabled            bool            `json:"discoveryEnabled"`
 	DiscoverySubnet             string          `json:"discoverySubnet,omitempty"`
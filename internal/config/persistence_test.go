@@ -915,3 +915,26 @@ func assertJSONEqual(t *testing.T, got interface{}, want interface{}, context st
 		t.Fatalf("%s mismatch:\n got: %s\nwant: %s", context, gotJSON, wantJSON)
 	}
 }
+
+func TestNextSNMPEngineBootsIncreasesAcrossRestarts(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", tempDir)
+
+	for want := uint32(1); want <= 3; want++ {
+		// Each start uses a fresh persistence, like a restarted server
+		boots, err := config.NewConfigPersistence(tempDir).NextSNMPEngineBoots()
+		if err != nil {
+			t.Fatalf("NextSNMPEngineBoots: %v", err)
+		}
+		if boots != want {
+			t.Fatalf("expected engine boots %d, got %d", want, boots)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, "snmp_engine.json"), []byte(`{"engineBoots":2147483647}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if boots, err := config.NewConfigPersistence(tempDir).NextSNMPEngineBoots(); err != nil || boots != 2147483647 {
+		t.Fatalf("expected the counter to stay at its maximum, got %d, %v", boots, err)
+	}
+}
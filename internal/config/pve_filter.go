@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Bounds for PVEInstance.PollIntervalSeconds
+const (
+	MinPVEPollIntervalSeconds = 10 // cluster/resources only refreshes every 10s
+	MaxPVEPollIntervalSeconds = 3600
+)
+
+// PVEResourceFilter narrows what Pulse monitors on a PVE instance. An include list, when set,
+// keeps only resources matching one of its entries; an exclude list drops matches and wins over
+// includes. Node, tag, pool and storage entries accept shell-style wildcards such as "lab-*".
+// VMID entries are single IDs or inclusive ranges such as "9000-9999".
+type PVEResourceFilter struct {
+	IncludeNodes   []string `json:"includeNodes,omitempty"`
+	ExcludeNodes   []string `json:"excludeNodes,omitempty"`
+	IncludeVMIDs   []string `json:"includeVMIDs,omitempty"`
+	ExcludeVMIDs   []string `json:"excludeVMIDs,omitempty"`
+	IncludeTags    []string `json:"includeTags,omitempty"`
+	ExcludeTags    []string `json:"excludeTags,omitempty"`
+	IncludePools   []string `json:"includePools,omitempty"` // Pools are only known when cluster/resources is available
+	ExcludePools   []string `json:"excludePools,omitempty"`
+	IncludeStorage []string `json:"includeStorage,omitempty"`
+	ExcludeStorage []string `json:"excludeStorage,omitempty"`
+}
+
+// IsEmpty reports whether the filter lets everything through
+func (f PVEResourceFilter) IsEmpty() bool {
+	for _, list := range [][]string{
+		f.IncludeNodes, f.ExcludeNodes, f.IncludeVMIDs, f.ExcludeVMIDs, f.IncludeTags,
+		f.ExcludeTags, f.IncludePools, f.ExcludePools, f.IncludeStorage, f.ExcludeStorage,
+	} {
+		if len(list) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks wildcard patterns and VMID ranges
+func (f PVEResourceFilter) Validate() error {
+	patterns := map[string][]string{
+		"includeNodes":   f.IncludeNodes,
+		"excludeNodes":   f.ExcludeNodes,
+		"includeTags":    f.IncludeTags,
+		"excludeTags":    f.ExcludeTags,
+		"includePools":   f.IncludePools,
+		"excludePools":   f.ExcludePools,
+		"includeStorage": f.IncludeStorage,
+		"excludeStorage": f.ExcludeStorage,
+	}
+	for field, list := range patterns {
+		for _, pattern := range list {
+			if strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("filters.%s contains an empty entry", field)
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("filters.%s: invalid pattern %q", field, pattern)
+			}
+		}
+	}
+	for field, list := range map[string][]string{"includeVMIDs": f.IncludeVMIDs, "excludeVMIDs": f.ExcludeVMIDs} {
+		for _, entry := range list {
+			if _, _, err := parseVMIDRange(entry); err != nil {
+				return fmt.Errorf("filters.%s: %w", field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AllowsNode reports whether the node and everything on it should be monitored
+func (f PVEResourceFilter) AllowsNode(node string) bool {
+	return allowedByPatterns(f.IncludeNodes, f.ExcludeNodes, []string{node})
+}
+
+// AllowsGuest reports whether a VM or container should be monitored. tags are the guest's
+// Proxmox tags and pool is empty when the guest is in no pool.
+func (f PVEResourceFilter) AllowsGuest(node string, vmid int, tags []string, pool string) bool {
+	if !f.AllowsGuestInUnknownPool(node, vmid, tags) {
+		return false
+	}
+	var pools []string
+	if pool != "" {
+		pools = []string{pool}
+	}
+	return allowedByPatterns(f.IncludePools, f.ExcludePools, pools)
+}
+
+// AllowsGuestInUnknownPool applies every guest filter except the pool lists. The per-node
+// fallback used when cluster/resources is unavailable cannot see pool membership, and treating
+// every guest as poolless there would drop all guests under includePools and ignore excludePools.
+func (f PVEResourceFilter) AllowsGuestInUnknownPool(node string, vmid int, tags []string) bool {
+	if !f.AllowsNode(node) {
+		return false
+	}
+	if len(f.IncludeVMIDs) > 0 && !vmidInRanges(f.IncludeVMIDs, vmid) {
+		return false
+	}
+	if vmidInRanges(f.ExcludeVMIDs, vmid) {
+		return false
+	}
+	return allowedByPatterns(f.IncludeTags, f.ExcludeTags, tags)
+}
+
+// HasPoolFilters reports whether the filter includes or excludes pools
+func (f PVEResourceFilter) HasPoolFilters() bool {
+	return len(f.IncludePools) > 0 || len(f.ExcludePools) > 0
+}
+
+// AllowsStorage reports whether a storage ID should be monitored
+func (f PVEResourceFilter) AllowsStorage(storage string) bool {
+	return allowedByPatterns(f.IncludeStorage, f.ExcludeStorage, []string{storage})
+}
+
+// allowedByPatterns reports whether any of values matches include (when set) and none matches
+// exclude
+func allowedByPatterns(include, exclude, values []string) bool {
+	if len(include) > 0 && !anyPatternMatches(include, values) {
+		return false
+	}
+	return !anyPatternMatches(exclude, values)
+}
+
+func anyPatternMatches(patterns, values []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		for _, value := range values {
+			if matched, _ := path.Match(pattern, strings.ToLower(strings.TrimSpace(value))); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func vmidInRanges(ranges []string, vmid int) bool {
+	for _, entry := range ranges {
+		low, high, err := parseVMIDRange(entry)
+		if err == nil && vmid >= low && vmid <= high {
+			return true
+		}
+	}
+	return false
+}
+
+// parseVMIDRange parses "100" or "100-199"
+func parseVMIDRange(entry string) (int, int, error) {
+	entry = strings.TrimSpace(entry)
+	lowText, highText, isRange := strings.Cut(entry, "-")
+	low, err := strconv.Atoi(strings.TrimSpace(lowText))
+	if err != nil || low < 0 {
+		return 0, 0, fmt.Errorf("invalid VMID %q", entry)
+	}
+	if !isRange {
+		return low, low, nil
+	}
+	high, err := strconv.Atoi(strings.TrimSpace(highText))
+	if err != nil || high < low {
+		return 0, 0, fmt.Errorf("invalid VMID range %q", entry)
+	}
+	return low, high, nil
+}
+
+// ValidatePollingOptions checks the per-instance poll interval and resource filters
+func (p PVEInstance) ValidatePollingOptions() error {
+	if p.PollIntervalSeconds != 0 &&
+		(p.PollIntervalSeconds < MinPVEPollIntervalSeconds || p.PollIntervalSeconds > MaxPVEPollIntervalSeconds) {
+		return fmt.Errorf("poll interval must be between %d and %d seconds, got %d",
+			MinPVEPollIntervalSeconds, MaxPVEPollIntervalSeconds, p.PollIntervalSeconds)
+	}
+	return p.Filters.Validate()
+}
@@ -0,0 +1,106 @@
+package config
+
+import "testing"
+
+func TestPVEResourceFilterGuests(t *testing.T) {
+	filter := PVEResourceFilter{
+		ExcludeNodes: []string{"lab-*"},
+		IncludeVMIDs: []string{"100-199", "250"},
+		ExcludeVMIDs: []string{"150-159"},
+		ExcludeTags:  []string{"Noisy"},
+		ExcludePools: []string{"templates"},
+	}
+	if err := filter.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		node  string
+		vmid  int
+		tags  []string
+		pool  string
+		allow bool
+	}{
+		{"included range", "pve1", 120, nil, "", true},
+		{"single included id", "pve1", 250, nil, "", true},
+		{"outside include ranges", "pve1", 300, nil, "", false},
+		{"excluded range wins", "pve1", 155, nil, "", false},
+		{"excluded node", "lab-2", 120, nil, "", false},
+		{"excluded tag is case-insensitive", "pve1", 120, []string{"prod", "noisy"}, "", false},
+		{"excluded pool", "pve1", 120, nil, "templates", false},
+		{"other pool", "pve1", 120, []string{"prod"}, "web", true},
+	}
+	for _, tc := range cases {
+		if got := filter.AllowsGuest(tc.node, tc.vmid, tc.tags, tc.pool); got != tc.allow {
+			t.Errorf("%s: AllowsGuest = %v, want %v", tc.name, got, tc.allow)
+		}
+	}
+}
+
+func TestPVEResourceFilterIncludeLists(t *testing.T) {
+	filter := PVEResourceFilter{
+		IncludeNodes:   []string{"pve1", "pve2"},
+		IncludeTags:    []string{"monitored"},
+		IncludeStorage: []string{"local*", "ceph"},
+		ExcludeStorage: []string{"local-lvm"},
+	}
+	if filter.AllowsNode("pve3") || !filter.AllowsNode("PVE1") {
+		t.Fatal("expected only the included nodes to be allowed")
+	}
+	if filter.AllowsGuest("pve1", 100, nil, "") {
+		t.Fatal("expected an untagged guest to be dropped by includeTags")
+	}
+	if !filter.AllowsGuest("pve2", 100, []string{"monitored"}, "") {
+		t.Fatal("expected a tagged guest on an included node to be allowed")
+	}
+	for storage, want := range map[string]bool{"local": true, "local-zfs": true, "local-lvm": false, "ceph": true, "nfs": false} {
+		if got := filter.AllowsStorage(storage); got != want {
+			t.Errorf("AllowsStorage(%q) = %v, want %v", storage, got, want)
+		}
+	}
+	if !(PVEResourceFilter{}).AllowsGuest("any", 1, nil, "") {
+		t.Fatal("expected an empty filter to allow everything")
+	}
+}
+
+func TestPVEResourceFilterUnknownPool(t *testing.T) {
+	filter := PVEResourceFilter{
+		IncludePools: []string{"prod"},
+		ExcludePools: []string{"templates"},
+		ExcludeVMIDs: []string{"9000-9999"},
+	}
+	if !filter.HasPoolFilters() || (PVEResourceFilter{ExcludeTags: []string{"x"}}).HasPoolFilters() {
+		t.Fatal("HasPoolFilters should only report pool lists")
+	}
+	if filter.AllowsGuest("pve1", 100, nil, "") {
+		t.Fatal("expected a guest in no pool to be dropped by includePools")
+	}
+	if !filter.AllowsGuestInUnknownPool("pve1", 100, nil) {
+		t.Fatal("expected pool filters to be skipped when the pool is unknown")
+	}
+	if filter.AllowsGuestInUnknownPool("pve1", 9001, nil) {
+		t.Fatal("expected the other filters to apply when the pool is unknown")
+	}
+}
+
+func TestPVEInstanceValidatePollingOptions(t *testing.T) {
+	valid := PVEInstance{PollIntervalSeconds: 60, Filters: PVEResourceFilter{ExcludeVMIDs: []string{"9000-9999"}}}
+	if err := valid.ValidatePollingOptions(); err != nil {
+		t.Fatalf("expected valid options, got %v", err)
+	}
+
+	invalid := []PVEInstance{
+		{PollIntervalSeconds: 5},
+		{PollIntervalSeconds: 7200},
+		{Filters: PVEResourceFilter{IncludeVMIDs: []string{"200-100"}}},
+		{Filters: PVEResourceFilter{ExcludeVMIDs: []string{"abc"}}},
+		{Filters: PVEResourceFilter{ExcludeNodes: []string{"[lab"}}},
+		{Filters: PVEResourceFilter{ExcludeTags: []string{" "}}},
+	}
+	for i, inst := range invalid {
+		if err := inst.ValidatePollingOptions(); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
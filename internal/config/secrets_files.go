@@ -0,0 +1,96 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// systemdCredsBackend reads credentials passed by systemd (LoadCredential=, LoadCredentialEncrypted=
+// or SetCredential=) from $CREDENTIALS_DIRECTORY. References are credential names.
+type systemdCredsBackend struct {
+	dir func() string
+}
+
+func newSystemdCredsBackend() *systemdCredsBackend {
+	return &systemdCredsBackend{dir: func() string { return os.Getenv("CREDENTIALS_DIRECTORY") }}
+}
+
+func (s *systemdCredsBackend) Resolve(ref string) (string, error) {
+	dir := s.dir()
+	if dir == "" {
+		return "", fmt.Errorf("CREDENTIALS_DIRECTORY is not set; is Pulse running under systemd with LoadCredential=?")
+	}
+	if ref == "" || strings.ContainsAny(ref, "/\\") || ref == "." || ref == ".." {
+		return "", fmt.Errorf("invalid systemd credential name %q", ref)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ref))
+	if err != nil {
+		return "", fmt.Errorf("failed to read systemd credential %s: %w", ref, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// sopsExecFunc runs the sops binary and returns its standard output
+type sopsExecFunc func(ctx context.Context, args []string) ([]byte, error)
+
+// sopsBackend decrypts values from SOPS files (e.g. age-encrypted YAML or JSON) with the sops
+// binary. References have the form /path/to/file#key.path; without a key the whole file is
+// returned. Keys are read by sops itself, e.g. from SOPS_AGE_KEY_FILE.
+type sopsBackend struct {
+	exec sopsExecFunc
+}
+
+func newSOPSBackend() *sopsBackend {
+	return &sopsBackend{exec: runSOPS}
+}
+
+func (s *sopsBackend) Resolve(ref string) (string, error) {
+	path, key, _ := strings.Cut(ref, "#")
+	// References can be set through the API, so only absolute paths are accepted and the path is
+	// passed after "--" where sops cannot read it as a flag
+	if path == "" || !filepath.IsAbs(path) {
+		return "", fmt.Errorf("invalid sops reference %q (expected sops:///path/to/file#key)", ref)
+	}
+
+	args := []string{"--decrypt"}
+	if key != "" {
+		var extract strings.Builder
+		for _, part := range strings.Split(key, ".") {
+			fmt.Fprintf(&extract, "[%q]", part)
+		}
+		args = append(args, "--extract", extract.String())
+	}
+	args = append(args, "--", path)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := s.exec(ctx, args)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s with sops: %w", path, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func runSOPS(ctx context.Context, args []string) ([]byte, error) {
+	binary := os.Getenv("PULSE_SOPS_PATH")
+	if binary == "" {
+		binary = "sops"
+	}
+	cmd := exec.CommandContext(ctx, binary, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
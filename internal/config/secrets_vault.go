@@ -0,0 +1,322 @@
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// VaultConfig configures the vault:// secret backend. Token auth uses Token; AppRole auth is
+// used when RoleID is set.
+type VaultConfig struct {
+	Address      string
+	Namespace    string
+	Token        string
+	RoleID       string
+	SecretID     string
+	AppRoleMount string // Defaults to "approle"
+	CACert       string // PEM file used to verify the Vault server
+	SkipVerify   bool
+}
+
+// VaultConfigFromEnv reads the Vault backend settings from the standard VAULT_* variables.
+// VAULT_TOKEN_FILE and VAULT_SECRET_ID_FILE may be used instead of the literal values.
+func VaultConfigFromEnv() (VaultConfig, error) {
+	cfg := VaultConfig{
+		Address:      os.Getenv("VAULT_ADDR"),
+		Namespace:    os.Getenv("VAULT_NAMESPACE"),
+		Token:        os.Getenv("VAULT_TOKEN"),
+		RoleID:       os.Getenv("VAULT_ROLE_ID"),
+		SecretID:     os.Getenv("VAULT_SECRET_ID"),
+		AppRoleMount: os.Getenv("VAULT_APPROLE_MOUNT"),
+		CACert:       os.Getenv("VAULT_CACERT"),
+		SkipVerify:   os.Getenv("VAULT_SKIP_VERIFY") == "true",
+	}
+	if path := os.Getenv("VAULT_TOKEN_FILE"); path != "" && cfg.Token == "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read VAULT_TOKEN_FILE: %w", err)
+		}
+		cfg.Token = strings.TrimSpace(string(data))
+	}
+	if path := os.Getenv("VAULT_SECRET_ID_FILE"); path != "" && cfg.SecretID == "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read VAULT_SECRET_ID_FILE: %w", err)
+		}
+		cfg.SecretID = strings.TrimSpace(string(data))
+	}
+	return cfg, nil
+}
+
+// VaultBackend reads secrets from a Vault KV v2 engine. References have the form
+// mount/path/to/secret#key; the key defaults to "value".
+type VaultBackend struct {
+	cfg    VaultConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	ttl       time.Duration
+	expiresAt time.Time // Zero for tokens without a TTL
+	renewable bool
+	checked   bool // Static token has been looked up
+}
+
+// NewVaultBackend creates a Vault backend
+func NewVaultBackend(cfg VaultConfig) (*VaultBackend, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("VAULT_ADDR is not set")
+	}
+	if cfg.Token == "" && cfg.RoleID == "" {
+		return nil, fmt.Errorf("vault requires VAULT_TOKEN or VAULT_ROLE_ID and VAULT_SECRET_ID")
+	}
+	if cfg.AppRoleMount == "" {
+		cfg.AppRoleMount = "approle"
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.SkipVerify}
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read VAULT_CACERT: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("VAULT_CACERT contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &VaultBackend{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+		now:   time.Now,
+		token: cfg.Token,
+	}, nil
+}
+
+// Resolve reads one key of a KV v2 secret
+func (v *VaultBackend) Resolve(ref string) (string, error) {
+	path, key, _ := strings.Cut(ref, "#")
+	if key == "" {
+		key = "value"
+	}
+	mount, secretPath, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok || mount == "" || secretPath == "" {
+		return "", fmt.Errorf("invalid vault reference %q (expected vault://mount/path#key)", ref)
+	}
+
+	var response struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	status, err := v.request(http.MethodGet, "/v1/"+mount+"/data/"+secretPath, nil, &response)
+	if status == http.StatusForbidden && v.cfg.RoleID != "" {
+		// The token may have been revoked; log in again once
+		v.mu.Lock()
+		v.token = ""
+		v.mu.Unlock()
+		_, err = v.request(http.MethodGet, "/v1/"+mount+"/data/"+secretPath, nil, &response)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := response.Data.Data[key]
+	if !ok {
+		return "", fmt.Errorf("vault secret %s/%s has no key %q", mount, secretPath, key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// request performs an authenticated Vault API call, logging in or renewing the token first
+func (v *VaultBackend) request(method, path string, body, out interface{}) (int, error) {
+	token, err := v.ensureToken()
+	if err != nil {
+		return 0, err
+	}
+	return v.do(method, path, token, body, out)
+}
+
+func (v *VaultBackend) do(method, path, token string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(v.cfg.Address, "/")+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if v.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&apiErr)
+		return resp.StatusCode, fmt.Errorf("vault %s %s: %s %s", method, path, resp.Status, strings.Join(apiErr.Errors, "; "))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode vault response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+type vaultAuth struct {
+	Auth *struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+// ensureToken returns a usable token. AppRole tokens are obtained on first use, renewable tokens
+// are renewed once two thirds of their TTL has passed, and expired AppRole tokens are replaced by
+// logging in again.
+func (v *VaultBackend) ensureToken() (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if v.token == "" {
+		if v.cfg.RoleID == "" {
+			return "", fmt.Errorf("vault token is not set")
+		}
+		err := v.loginLocked()
+		return v.token, err
+	}
+
+	if !v.checked && v.cfg.RoleID == "" {
+		// Learn the TTL of a static token so it can be renewed before it expires
+		v.checked = true
+		var lookup struct {
+			Data struct {
+				TTL       int  `json:"ttl"`
+				Renewable bool `json:"renewable"`
+			} `json:"data"`
+		}
+		if _, err := v.do(http.MethodGet, "/v1/auth/token/lookup-self", v.token, nil, &lookup); err != nil {
+			log.Warn().Err(err).Msg("Failed to look up Vault token; it will not be renewed")
+		} else if lookup.Data.TTL > 0 {
+			v.setLeaseLocked(time.Duration(lookup.Data.TTL)*time.Second, lookup.Data.Renewable, now)
+		}
+	}
+
+	if v.expiresAt.IsZero() || now.Before(v.expiresAt.Add(-v.ttl/3)) {
+		return v.token, nil
+	}
+
+	if v.renewable && now.Before(v.expiresAt) {
+		var renewed vaultAuth
+		if _, err := v.do(http.MethodPost, "/v1/auth/token/renew-self", v.token, map[string]string{}, &renewed); err == nil && renewed.Auth != nil {
+			v.setLeaseLocked(time.Duration(renewed.Auth.LeaseDuration)*time.Second, renewed.Auth.Renewable, now)
+			log.Debug().Time("expiresAt", v.expiresAt).Msg("Renewed Vault token")
+			return v.token, nil
+		} else if err != nil {
+			log.Warn().Err(err).Msg("Failed to renew Vault token")
+		}
+	}
+
+	if v.cfg.RoleID != "" {
+		err := v.loginLocked()
+		return v.token, err
+	}
+	if now.Before(v.expiresAt) {
+		return v.token, nil
+	}
+	return "", fmt.Errorf("vault token expired and cannot be renewed")
+}
+
+func (v *VaultBackend) loginLocked() error {
+	var login vaultAuth
+	payload := map[string]string{"role_id": v.cfg.RoleID, "secret_id": v.cfg.SecretID}
+	if _, err := v.do(http.MethodPost, "/v1/auth/"+v.cfg.AppRoleMount+"/login", "", payload, &login); err != nil {
+		v.token = ""
+		return fmt.Errorf("vault approle login failed: %w", err)
+	}
+	if login.Auth == nil || login.Auth.ClientToken == "" {
+		v.token = ""
+		return fmt.Errorf("vault approle login returned no token")
+	}
+	v.token = login.Auth.ClientToken
+	v.setLeaseLocked(time.Duration(login.Auth.LeaseDuration)*time.Second, login.Auth.Renewable, v.now())
+	log.Info().Time("expiresAt", v.expiresAt).Msg("Logged in to Vault with AppRole")
+	return nil
+}
+
+func (v *VaultBackend) setLeaseLocked(ttl time.Duration, renewable bool, now time.Time) {
+	v.ttl = ttl
+	v.renewable = renewable
+	if ttl > 0 {
+		v.expiresAt = now.Add(ttl)
+	} else {
+		v.expiresAt = time.Time{}
+	}
+}
+
+// lazyVaultBackend configures Vault from the environment on first use, so installs that do not
+// use vault:// references need no Vault settings
+type lazyVaultBackend struct {
+	once    sync.Once
+	backend *VaultBackend
+	err     error
+}
+
+func newLazyVaultBackend() *lazyVaultBackend {
+	return &lazyVaultBackend{}
+}
+
+func (l *lazyVaultBackend) Resolve(ref string) (string, error) {
+	l.once.Do(func() {
+		cfg, err := VaultConfigFromEnv()
+		if err != nil {
+			l.err = err
+			return
+		}
+		l.backend, l.err = NewVaultBackend(cfg)
+	})
+	if l.err != nil {
+		return "", l.err
+	}
+	return l.backend.Resolve(ref)
+}
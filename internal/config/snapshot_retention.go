@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultSnapshotRetentionIntervalHours is how often retention policies are enforced
+	DefaultSnapshotRetentionIntervalHours = 6
+	maxSnapshotRetentionIntervalHours     = 24 * 7
+)
+
+// SnapshotRetentionConfig configures automatic cleanup of guest snapshots.
+// DryRun evaluates the policies on schedule and records what would be deleted
+// without touching any snapshot.
+type SnapshotRetentionConfig struct {
+	Enabled       bool                      `json:"enabled"`
+	DryRun        bool                      `json:"dryRun"`
+	IntervalHours int                       `json:"intervalHours"`
+	Policies      []SnapshotRetentionPolicy `json:"policies"`
+	// Exclusions protect snapshots from every policy. Entries are snapshot name
+	// globs ("keep-*"), guest IDs or "guest-id:snapshot" pairs.
+	Exclusions []string `json:"exclusions"`
+}
+
+// SnapshotRetentionPolicy applies to guests matching any of its tags, pools or guest IDs.
+// A snapshot is deleted when it is beyond the newest KeepLast snapshots or older than MaxAge;
+// with both set, a snapshot is only deleted if both limits are exceeded.
+type SnapshotRetentionPolicy struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Enabled     bool     `json:"enabled"`
+	Tags        []string `json:"tags,omitempty"`
+	Pools       []string `json:"pools,omitempty"`
+	Guests      []string `json:"guests,omitempty"`      // Guest IDs as shown in /api/state
+	NamePattern string   `json:"namePattern,omitempty"` // Only manage snapshots whose name matches this glob
+	KeepLast    int      `json:"keepLast,omitempty"`
+	MaxAge      string   `json:"maxAge,omitempty"` // e.g. "14d", "36h"
+}
+
+// NormalizeSnapshotRetentionConfig cleans retention values and applies defaults.
+func NormalizeSnapshotRetentionConfig(cfg SnapshotRetentionConfig) SnapshotRetentionConfig {
+	normalized := cfg
+	if normalized.IntervalHours <= 0 {
+		normalized.IntervalHours = DefaultSnapshotRetentionIntervalHours
+	}
+	normalized.Exclusions = normalizeStringList(normalized.Exclusions, false)
+
+	policies := make([]SnapshotRetentionPolicy, 0, len(cfg.Policies))
+	for _, policy := range cfg.Policies {
+		policy.ID = strings.TrimSpace(policy.ID)
+		if policy.ID == "" {
+			policy.ID = uuid.NewString()
+		}
+		policy.Name = strings.TrimSpace(policy.Name)
+		policy.Tags = normalizeStringList(policy.Tags, true)
+		policy.Pools = normalizeStringList(policy.Pools, false)
+		policy.Guests = normalizeStringList(policy.Guests, false)
+		policy.NamePattern = strings.TrimSpace(policy.NamePattern)
+		policy.MaxAge = strings.ToLower(strings.TrimSpace(policy.MaxAge))
+		policies = append(policies, policy)
+	}
+	normalized.Policies = policies
+
+	return normalized
+}
+
+// Validate returns an error for policies that cannot be enforced safely.
+func (c SnapshotRetentionConfig) Validate() error {
+	if c.IntervalHours > maxSnapshotRetentionIntervalHours {
+		return fmt.Errorf("intervalHours must be at most %d", maxSnapshotRetentionIntervalHours)
+	}
+	for _, exclusion := range c.Exclusions {
+		if _, err := path.Match(exclusion, ""); err != nil {
+			return fmt.Errorf("invalid exclusion %q: %w", exclusion, err)
+		}
+	}
+
+	seen := make(map[string]bool, len(c.Policies))
+	for _, policy := range c.Policies {
+		label := policy.Name
+		if label == "" {
+			label = policy.ID
+		}
+		if seen[policy.ID] {
+			return fmt.Errorf("duplicate policy id %q", policy.ID)
+		}
+		seen[policy.ID] = true
+
+		if len(policy.Tags) == 0 && len(policy.Pools) == 0 && len(policy.Guests) == 0 {
+			return fmt.Errorf("policy %q must match at least one tag, pool or guest", label)
+		}
+		if policy.KeepLast < 0 {
+			return fmt.Errorf("policy %q: keepLast cannot be negative", label)
+		}
+		maxAge, err := ParseRetentionAge(policy.MaxAge)
+		if err != nil {
+			return fmt.Errorf("policy %q: %w", label, err)
+		}
+		if policy.KeepLast == 0 && maxAge == 0 {
+			return fmt.Errorf("policy %q must set keepLast or maxAge", label)
+		}
+		if policy.NamePattern != "" {
+			if _, err := path.Match(policy.NamePattern, ""); err != nil {
+				return fmt.Errorf("policy %q: invalid name pattern: %w", label, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseRetentionAge parses ages such as "14d", "2w" or "36h". An empty value means no age limit.
+func ParseRetentionAge(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return 0, nil
+	}
+
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit > 0 {
+		count, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(value, "d"), "w"))
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("invalid maxAge %q", value)
+		}
+		return time.Duration(count) * unit, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid maxAge %q", value)
+	}
+	return duration, nil
+}
+
+func normalizeStringList(values []string, lower bool) []string {
+	normalized := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if lower {
+			value = strings.ToLower(value)
+		}
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		normalized = append(normalized, value)
+	}
+	return normalized
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRetentionAge(t *testing.T) {
+	cases := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"14d", 14 * 24 * time.Hour, false},
+		{"2W", 14 * 24 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"0d", 0, true},
+		{"-5h", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tc := range cases {
+		got, err := ParseRetentionAge(tc.value)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseRetentionAge(%q) error = %v, wantErr %t", tc.value, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseRetentionAge(%q) = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestSnapshotRetentionConfigValidate(t *testing.T) {
+	valid := NormalizeSnapshotRetentionConfig(SnapshotRetentionConfig{
+		Policies: []SnapshotRetentionPolicy{{Name: "prod", Enabled: true, Tags: []string{" Prod "}, KeepLast: 3}},
+	})
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	if valid.IntervalHours != DefaultSnapshotRetentionIntervalHours {
+		t.Errorf("IntervalHours = %d, want default", valid.IntervalHours)
+	}
+	if valid.Policies[0].ID == "" || valid.Policies[0].Tags[0] != "prod" {
+		t.Errorf("policy not normalized: %+v", valid.Policies[0])
+	}
+
+	invalid := map[string]SnapshotRetentionPolicy{
+		"no selector": {ID: "a", KeepLast: 1},
+		"no limit":    {ID: "a", Tags: []string{"prod"}},
+		"bad age":     {ID: "a", Tags: []string{"prod"}, MaxAge: "later"},
+		"bad pattern": {ID: "a", Tags: []string{"prod"}, KeepLast: 1, NamePattern: "auto-["},
+	}
+	for name, policy := range invalid {
+		cfg := SnapshotRetentionConfig{IntervalHours: 6, Policies: []SnapshotRetentionPolicy{policy}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// TenantScopesConfig holds the tenant scopes that restrict what users see.
+type TenantScopesConfig struct {
+	Scopes []TenantScope `json:"scopes"`
+}
+
+// TenantScope limits a set of identities to guests in the listed PVE pools, guests carrying
+// any of the listed tags and everything belonging to the listed instances. A request whose
+// user, proxy role or API token is bound to one or more scopes only sees the union of them;
+// identities bound to no scope keep the unrestricted view.
+type TenantScope struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Pools     []string `json:"pools,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Instances []string `json:"instances,omitempty"` // PVE, PBS or PMG instance names
+	Users     []string `json:"users,omitempty"`     // Proxy or OIDC usernames
+	Roles     []string `json:"roles,omitempty"`     // Proxy auth roles
+	Tokens    []string `json:"tokens,omitempty"`    // API token IDs or names
+	// Emails and Webhooks receive notifications for alerts inside the scope,
+	// in addition to the global notification channels.
+	Emails   []string `json:"emails,omitempty"`
+	Webhooks []string `json:"webhooks,omitempty"` // IDs of configured webhooks
+}
+
+// NormalizeTenantScopesConfig trims and de-duplicates scope values and assigns missing IDs.
+func NormalizeTenantScopesConfig(cfg TenantScopesConfig) TenantScopesConfig {
+	scopes := make([]TenantScope, 0, len(cfg.Scopes))
+	for _, scope := range cfg.Scopes {
+		scope.ID = strings.TrimSpace(scope.ID)
+		if scope.ID == "" {
+			scope.ID = uuid.NewString()
+		}
+		scope.Name = strings.TrimSpace(scope.Name)
+		scope.Pools = normalizeStringList(scope.Pools, false)
+		scope.Tags = normalizeStringList(scope.Tags, true)
+		scope.Instances = normalizeStringList(scope.Instances, false)
+		scope.Users = normalizeStringList(scope.Users, false)
+		scope.Roles = normalizeStringList(scope.Roles, false)
+		scope.Tokens = normalizeStringList(scope.Tokens, false)
+		scope.Emails = normalizeStringList(scope.Emails, false)
+		scope.Webhooks = normalizeStringList(scope.Webhooks, false)
+		scopes = append(scopes, scope)
+	}
+	return TenantScopesConfig{Scopes: scopes}
+}
+
+// Validate returns an error for scopes that would grant nothing or cannot be identified.
+func (c TenantScopesConfig) Validate() error {
+	seen := make(map[string]bool, len(c.Scopes))
+	for _, scope := range c.Scopes {
+		label := scope.Name
+		if label == "" {
+			label = scope.ID
+		}
+		if seen[scope.ID] {
+			return fmt.Errorf("duplicate scope id %q", scope.ID)
+		}
+		seen[scope.ID] = true
+
+		if scope.Name == "" {
+			return fmt.Errorf("scope %q must have a name", scope.ID)
+		}
+		if len(scope.Pools) == 0 && len(scope.Tags) == 0 && len(scope.Instances) == 0 {
+			return fmt.Errorf("scope %q must match at least one pool, tag or instance", label)
+		}
+		for _, email := range scope.Emails {
+			if !strings.Contains(email, "@") {
+				return fmt.Errorf("scope %q: invalid email %q", label, email)
+			}
+		}
+	}
+	return nil
+}
+
+// Matching returns the scopes bound to the given identity. The username and roles come from
+// proxy auth or the OIDC session; tokenID and tokenName identify the API token used, if any.
+func (c TenantScopesConfig) Matching(username string, roles []string, tokenID, tokenName string) []TenantScope {
+	var matched []TenantScope
+	for _, scope := range c.Scopes {
+		if scope.boundTo(username, roles, tokenID, tokenName) {
+			matched = append(matched, scope)
+		}
+	}
+	return matched
+}
+
+func (s TenantScope) boundTo(username string, roles []string, tokenID, tokenName string) bool {
+	if username != "" && containsFold(s.Users, username) {
+		return true
+	}
+	for _, role := range roles {
+		if role != "" && containsFold(s.Roles, role) {
+			return true
+		}
+	}
+	for _, token := range s.Tokens {
+		if (tokenID != "" && token == tokenID) || (tokenName != "" && token == tokenName) {
+			return true
+		}
+	}
+	return false
+}
+
+// ByID returns the scopes with the given IDs, skipping unknown ones.
+func (c TenantScopesConfig) ByID(ids []string) []TenantScope {
+	var scopes []TenantScope
+	for _, id := range ids {
+		for _, scope := range c.Scopes {
+			if scope.ID == id {
+				scopes = append(scopes, scope)
+				break
+			}
+		}
+	}
+	return scopes
+}
+
+func containsFold(values []string, value string) bool {
+	value = strings.TrimSpace(value)
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
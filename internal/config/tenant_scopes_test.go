@@ -0,0 +1,69 @@
+package config
+
+import "testing"
+
+func TestTenantScopesConfigValidate(t *testing.T) {
+	valid := NormalizeTenantScopesConfig(TenantScopesConfig{
+		Scopes: []TenantScope{{Name: " Team A ", Tags: []string{" Team-A ", "team-a"}, Users: []string{"alice"}}},
+	})
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	if scope := valid.Scopes[0]; scope.ID == "" || scope.Name != "Team A" || len(scope.Tags) != 1 || scope.Tags[0] != "team-a" {
+		t.Fatalf("unexpected normalized scope: %+v", scope)
+	}
+
+	invalid := []TenantScope{
+		{ID: "empty", Name: "Grants nothing", Users: []string{"bob"}},
+		{ID: "unnamed", Pools: []string{"prod"}},
+		{ID: "mail", Name: "Bad email", Pools: []string{"prod"}, Emails: []string{"ops"}},
+	}
+	for _, scope := range invalid {
+		if err := NormalizeTenantScopesConfig(TenantScopesConfig{Scopes: []TenantScope{scope}}).Validate(); err == nil {
+			t.Errorf("expected scope %q to be rejected", scope.ID)
+		}
+	}
+
+	duplicate := TenantScopesConfig{Scopes: []TenantScope{
+		{ID: "a", Name: "A", Pools: []string{"p"}},
+		{ID: "a", Name: "B", Pools: []string{"q"}},
+	}}
+	if err := duplicate.Validate(); err == nil {
+		t.Error("expected duplicate scope IDs to be rejected")
+	}
+}
+
+func TestTenantScopesConfigMatching(t *testing.T) {
+	cfg := TenantScopesConfig{Scopes: []TenantScope{
+		{ID: "a", Name: "A", Pools: []string{"a"}, Users: []string{"Alice"}},
+		{ID: "b", Name: "B", Pools: []string{"b"}, Roles: []string{"team-b"}},
+		{ID: "c", Name: "C", Pools: []string{"c"}, Tokens: []string{"ci-token"}},
+	}}
+
+	cases := []struct {
+		name      string
+		username  string
+		roles     []string
+		tokenID   string
+		tokenName string
+		want      []string
+	}{
+		{"user is case insensitive", "alice", nil, "", "", []string{"a"}},
+		{"roles", "carol", []string{"viewer", "TEAM-B"}, "", "", []string{"b"}},
+		{"user and role combine", "alice", []string{"team-b"}, "", "", []string{"a", "b"}},
+		{"token by name", "", nil, "8f2c", "ci-token", []string{"c"}},
+		{"unbound", "dave", []string{"admin"}, "", "", nil},
+	}
+	for _, tc := range cases {
+		matched := cfg.Matching(tc.username, tc.roles, tc.tokenID, tc.tokenName)
+		if len(matched) != len(tc.want) {
+			t.Errorf("%s: matched %d scopes, want %v", tc.name, len(matched), tc.want)
+			continue
+		}
+		for i, scope := range matched {
+			if scope.ID != tc.want[i] {
+				t.Errorf("%s: scope %d = %q, want %q", tc.name, i, scope.ID, tc.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TracingConfig configures OpenTelemetry tracing. It is read from PULSE_TRACING_* environment
+// variables; the standard OTEL_EXPORTER_OTLP_* variables also apply to the exporter.
+type TracingConfig struct {
+	Enabled bool
+	// Endpoint is the collector's OTLP/HTTP URL, e.g. http://otel-collector:4318
+	Endpoint string
+	// SampleRatio is the fraction of polls and requests traced, between 0 and 1
+	SampleRatio float64
+}
+
+// LoadTracingConfigFromEnv reads the PULSE_TRACING_* environment variables
+func LoadTracingConfigFromEnv() (TracingConfig, error) {
+	cfg := TracingConfig{SampleRatio: 1}
+	if value := strings.TrimSpace(os.Getenv("PULSE_TRACING_ENABLED")); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid PULSE_TRACING_ENABLED %q", value)
+		}
+		cfg.Enabled = enabled
+	}
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+
+	cfg.Endpoint = strings.TrimSpace(os.Getenv("PULSE_TRACING_ENDPOINT"))
+	if cfg.Endpoint != "" {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cfg, fmt.Errorf("PULSE_TRACING_ENDPOINT must be an http(s) URL")
+		}
+	}
+	if value := strings.TrimSpace(os.Getenv("PULSE_TRACING_SAMPLE_RATIO")); value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return cfg, fmt.Errorf("PULSE_TRACING_SAMPLE_RATIO must be between 0 and 1, got %q", value)
+		}
+		cfg.SampleRatio = ratio
+	}
+	return cfg, nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestLoadTracingConfigFromEnv(t *testing.T) {
+	t.Setenv("PULSE_TRACING_ENABLED", "")
+	if cfg, err := LoadTracingConfigFromEnv(); err != nil || cfg.Enabled {
+		t.Fatalf("default = %+v, %v", cfg, err)
+	}
+
+	t.Setenv("PULSE_TRACING_ENABLED", "true")
+	t.Setenv("PULSE_TRACING_ENDPOINT", "http://otel-collector:4318")
+	t.Setenv("PULSE_TRACING_SAMPLE_RATIO", "0.25")
+	cfg, err := LoadTracingConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadTracingConfigFromEnv: %v", err)
+	}
+	if !cfg.Enabled || cfg.Endpoint != "http://otel-collector:4318" || cfg.SampleRatio != 0.25 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	t.Setenv("PULSE_TRACING_SAMPLE_RATIO", "2")
+	if _, err := LoadTracingConfigFromEnv(); err == nil {
+		t.Fatal("expected a sample ratio above 1 to be rejected")
+	}
+	t.Setenv("PULSE_TRACING_SAMPLE_RATIO", "")
+	t.Setenv("PULSE_TRACING_ENDPOINT", "otel-collector:4318")
+	if _, err := LoadTracingConfigFromEnv(); err == nil {
+		t.Fatal("expected an endpoint without a scheme to be rejected")
+	}
+}
@@ -28,6 +28,7 @@ type ConfigWatcher struct {
 	apiTokensLastModTime time.Time
 	mu                   sync.RWMutex
 	onMockReload         func() // Callback to trigger backend restart
+	onCredentialsChange  func() // Callback to reconnect nodes after a referenced secret changed
 }
 
 // NewConfigWatcher creates a new config watcher
@@ -92,6 +93,14 @@ func (cw *ConfigWatcher) SetMockReloadCallback(callback func()) {
 	cw.onMockReload = callback
 }
 
+// SetCredentialsChangeCallback sets the callback function to trigger when a reload finds that a
+// secret referenced by vault://, sops:// or systemd-creds:// has changed
+func (cw *ConfigWatcher) SetCredentialsChangeCallback(callback func()) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.onCredentialsChange = callback
+}
+
 // Start begins watching the config file
 func (cw *ConfigWatcher) Start() error {
 	// Watch the directory for .env
@@ -240,6 +249,9 @@ func (cw *ConfigWatcher) pollForChanges() {
 
 // reloadConfig reloads the config from the .env file
 func (cw *ConfigWatcher) reloadConfig() {
+	// Runs after the lock is released
+	defer cw.refreshCredentials()
+
 	cw.mu.Lock()
 	defer cw.mu.Unlock()
 
@@ -389,6 +401,22 @@ func (cw *ConfigWatcher) reloadConfig() {
 	}
 }
 
+// refreshCredentials re-resolves secret backend references so rotated credentials are picked up
+func (cw *ConfigWatcher) refreshCredentials() {
+	if !DefaultCredentialResolver().Refresh() {
+		return
+	}
+
+	cw.mu.RLock()
+	callback := cw.onCredentialsChange
+	cw.mu.RUnlock()
+
+	log.Info().Msg("Referenced credentials changed, reconnecting nodes")
+	if callback != nil {
+		callback()
+	}
+}
+
 func (cw *ConfigWatcher) reloadAPITokens() {
 	cw.mu.Lock()
 	defer cw.mu.Unlock()
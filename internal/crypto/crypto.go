@@ -1,37 +1,68 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/RouXx67/PulseUp/internal/utils"
 	"github.com/rs/zerolog/log"
 )
 
-// CryptoManager handles encryption/decryption of sensitive data
+// CryptoManager handles encryption/decryption of sensitive data. Data is encrypted with the
+// active key of a versioned keyring stored at <data>/.encryption.key; every ciphertext carries
+// the version of the key that produced it so older files stay readable until they are rotated.
 type CryptoManager struct {
-	key []byte
+	mu      sync.RWMutex
+	keyPath string
+	ring    *keyring
+	stamp   fileStamp
 }
 
 // NewCryptoManager creates a new crypto manager
 func NewCryptoManager() (*CryptoManager, error) {
-	key, err := getOrCreateKey()
+	keyPath := filepath.Join(utils.GetDataDir(), ".encryption.key")
+	data, err := getOrCreateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	ring, err := parseKeyring(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get encryption key: %w", err)
 	}
 
 	return &CryptoManager{
-		key: key,
+		keyPath: keyPath,
+		ring:    ring,
+		stamp:   statFile(keyPath),
 	}, nil
 }
 
-// getOrCreateKey gets the encryption key or creates one if it doesn't exist
+// OpenKeyFile loads an existing key file without creating or migrating keys, e.g. to check the
+// keyring of a backup before it is restored
+func OpenKeyFile(path string) (*CryptoManager, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ring, err := parseKeyring(data)
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoManager{keyPath: path, ring: ring, stamp: statFile(path)}, nil
+}
+
+// getOrCreateKey returns the contents of the key file, creating a new key if none exists. The
+// file holds either a single base64 key (version 1) or a JSON keyring.
 func getOrCreateKey() ([]byte, error) {
 	// Use data directory for key storage (for Docker persistence)
 	dataDir := utils.GetDataDir()
@@ -45,11 +76,15 @@ func getOrCreateKey() ([]byte, error) {
 
 	// Try to read existing key from new location
 	if data, err := os.ReadFile(keyPath); err == nil {
+		if isKeyringFile(data) {
+			log.Debug().Msg("Found and loaded existing encryption keyring")
+			return data, nil
+		}
 		key := make([]byte, 32)
 		n, err := base64.StdEncoding.Decode(key, data)
 		if err == nil && n == 32 {
 			log.Debug().Msg("Found and loaded existing encryption key")
-			return key, nil
+			return data, nil
 		} else {
 			log.Warn().
 				Err(err).
@@ -70,12 +105,12 @@ func getOrCreateKey() ([]byte, error) {
 				if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
 					// Migration failed, but we can still use the old key
 					log.Warn().Err(err).Msg("Failed to create directory for key migration, using old location")
-					return key, nil
+					return data, nil
 				}
 				if err := os.WriteFile(keyPath, data, 0600); err != nil {
 					// Migration failed, but we can still use the old key
 					log.Warn().Err(err).Msg("Failed to migrate encryption key, using old location")
-					return key, nil
+					return data, nil
 				}
 				log.Info().
 					Str("from", oldKeyPath).
@@ -85,7 +120,7 @@ func getOrCreateKey() ([]byte, error) {
 				if err := os.Remove(oldKeyPath); err != nil {
 					log.Debug().Err(err).Msg("Could not remove old encryption key (may lack permissions)")
 				}
-				return key, nil
+				return data, nil
 			}
 		}
 	}
@@ -138,38 +173,121 @@ func getOrCreateKey() ([]byte, error) {
 	}
 
 	log.Info().Msg("Generated new encryption key")
-	return key, nil
+	return []byte(encoded), nil
 }
 
-// Encrypt encrypts data using AES-GCM
+// Encrypt encrypts data using AES-GCM with the active key
 func (c *CryptoManager) Encrypt(plaintext []byte) ([]byte, error) {
-	block, err := aes.NewCipher(c.key)
+	c.reloadIfChanged()
+
+	c.mu.RLock()
+	version := c.ring.active
+	key := c.ring.keys[version]
+	c.mu.RUnlock()
+
+	return seal(version, key, plaintext)
+}
+
+// Decrypt decrypts data using AES-GCM. Data written before key versioning was introduced has
+// no header and is decrypted with key version 1.
+func (c *CryptoManager) Decrypt(ciphertext []byte) ([]byte, error) {
+	legacy := c.key(legacyKeyVersion)
+	if version, ok := parseHeader(ciphertext); ok {
+		key := c.key(version)
+		if key == nil {
+			// The keyring may have been rotated by another process
+			c.reloadIfChanged()
+			key = c.key(version)
+			legacy = c.key(legacyKeyVersion)
+		}
+
+		var err error
+		if key == nil {
+			err = fmt.Errorf("data is encrypted with key version %d, which is not in the keyring", version)
+		} else if plaintext, openErr := open(key, ciphertext); openErr == nil {
+			return plaintext, nil
+		} else {
+			err = openErr
+		}
+		// A legacy nonce can begin with the header magic by chance
+		if legacy == nil {
+			return nil, err
+		}
+	}
+
+	if legacy == nil {
+		return nil, fmt.Errorf("data has no key version header and key version %d is not in the keyring", legacyKeyVersion)
+	}
+	return openLegacy(legacy, ciphertext)
+}
+
+func (c *CryptoManager) key(version uint32) []byte {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ring.keys[version]
+}
+
+// ciphertextMagic starts every versioned ciphertext: magic, format byte, big-endian key version
+var ciphertextMagic = []byte("PENC")
+
+const (
+	ciphertextFormat = 1
+	headerSize       = 9
+	legacyKeyVersion = 1
+)
+
+func parseHeader(data []byte) (uint32, bool) {
+	if len(data) < headerSize || !bytes.Equal(data[:4], ciphertextMagic) || data[4] != ciphertextFormat {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(data[5:headerSize]), true
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
+	return cipher.NewGCM(block)
+}
 
-	gcm, err := cipher.NewGCM(block)
+// seal encrypts plaintext as header || nonce || ciphertext, authenticating the header
+func seal(version uint32, key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
 	if err != nil {
 		return nil, err
 	}
 
+	out := make([]byte, headerSize, headerSize+gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	copy(out, ciphertextMagic)
+	out[4] = ciphertextFormat
+	binary.BigEndian.PutUint32(out[5:], version)
+
 	nonce := make([]byte, gcm.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
-
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
-	return ciphertext, nil
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plaintext, out[:headerSize]), nil
 }
 
-// Decrypt decrypts data using AES-GCM
-func (c *CryptoManager) Decrypt(ciphertext []byte) ([]byte, error) {
-	block, err := aes.NewCipher(c.key)
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
 	if err != nil {
 		return nil, err
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	header, body := data[:headerSize], data[headerSize:]
+	if len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, header)
+}
+
+// openLegacy decrypts the original nonce || ciphertext format
+func openLegacy(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
 	if err != nil {
 		return nil, err
 	}
@@ -180,12 +298,7 @@ func (c *CryptoManager) Decrypt(ciphertext []byte) ([]byte, error) {
 	}
 
 	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	return plaintext, nil
+	return gcm.Open(nil, nonce, ciphertext, nil)
 }
 
 // EncryptString encrypts a string and returns base64
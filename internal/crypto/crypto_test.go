@@ -0,0 +1,238 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestManager(t *testing.T) (*CryptoManager, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", dir)
+	t.Setenv("PULSE_MASTER_PASSPHRASE", "")
+	t.Setenv("PULSE_MASTER_PASSPHRASE_FILE", "")
+	t.Setenv("CREDENTIALS_DIRECTORY", "")
+
+	cm, err := NewCryptoManager()
+	if err != nil {
+		t.Fatalf("NewCryptoManager: %v", err)
+	}
+	return cm, dir
+}
+
+func TestLegacyCiphertextIsStillDecrypted(t *testing.T) {
+	cm, dir := newTestManager(t)
+
+	encoded, err := os.ReadFile(filepath.Join(dir, ".encryption.key"))
+	if err != nil {
+		t.Fatalf("read key: %v", err)
+	}
+	key, _ := base64.StdEncoding.DecodeString(string(encoded))
+
+	// The pre-versioning format is nonce || ciphertext without a header
+	gcm, err := newGCM(key)
+	if err != nil {
+		t.Fatalf("newGCM: %v", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, _ = rand.Read(nonce)
+	legacy := gcm.Seal(nonce, nonce, []byte("legacy secret"), nil)
+
+	plaintext, err := cm.Decrypt(legacy)
+	if err != nil || string(plaintext) != "legacy secret" {
+		t.Fatalf("expected legacy ciphertext to decrypt, got %q (%v)", plaintext, err)
+	}
+
+	encrypted, err := cm.Encrypt([]byte("new secret"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if version, ok := parseHeader(encrypted); !ok || version != 1 {
+		t.Fatalf("expected a version 1 header, got version=%d ok=%v", version, ok)
+	}
+
+	// The header is authenticated
+	encrypted[8] = 2
+	if _, err := cm.Decrypt(encrypted); err == nil {
+		t.Fatalf("expected a tampered header to be rejected")
+	}
+}
+
+func TestRotationActivatesNewKeyAndDropsOldOnes(t *testing.T) {
+	cm, _ := newTestManager(t)
+	old, err := cm.Encrypt([]byte("before"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	rotation, err := cm.BeginRotation()
+	if err != nil {
+		t.Fatalf("BeginRotation: %v", err)
+	}
+	if rotation.Version() != 2 {
+		t.Fatalf("expected key version 2, got %d", rotation.Version())
+	}
+
+	// Before commit both keys are available and the old one is still active
+	rotated, err := rotation.Encrypt([]byte("after"))
+	if err != nil {
+		t.Fatalf("rotation Encrypt: %v", err)
+	}
+	if info := cm.KeyInfo(); info.ActiveVersion != 1 || len(info.Versions) != 2 {
+		t.Fatalf("unexpected key info during rotation: %+v", info)
+	}
+	if plaintext, err := cm.Decrypt(rotated); err != nil || string(plaintext) != "after" {
+		t.Fatalf("expected pending key to decrypt, got %q (%v)", plaintext, err)
+	}
+
+	if err := rotation.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if info := cm.KeyInfo(); info.ActiveVersion != 2 || len(info.Versions) != 1 {
+		t.Fatalf("unexpected key info after rotation: %+v", info)
+	}
+	if _, err := cm.Decrypt(old); err == nil {
+		t.Fatalf("expected data encrypted with the retired key to be unreadable")
+	}
+
+	reloaded, err := NewCryptoManager()
+	if err != nil {
+		t.Fatalf("NewCryptoManager after rotation: %v", err)
+	}
+	if plaintext, err := reloaded.Decrypt(rotated); err != nil || string(plaintext) != "after" {
+		t.Fatalf("expected keyring to be persisted, got %q (%v)", plaintext, err)
+	}
+	encrypted, _ := reloaded.Encrypt([]byte("x"))
+	if version, _ := parseHeader(encrypted); version != 2 {
+		t.Fatalf("expected new data to use key version 2, got %d", version)
+	}
+}
+
+func TestRotationByAnotherProcessIsPickedUp(t *testing.T) {
+	server, _ := newTestManager(t)
+	cli, err := NewCryptoManager()
+	if err != nil {
+		t.Fatalf("NewCryptoManager: %v", err)
+	}
+
+	rotation, err := cli.BeginRotation()
+	if err != nil {
+		t.Fatalf("BeginRotation: %v", err)
+	}
+	rotated, _ := rotation.Encrypt([]byte("rotated"))
+	if err := rotation.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if plaintext, err := server.Decrypt(rotated); err != nil || string(plaintext) != "rotated" {
+		t.Fatalf("expected server to reload the keyring, got %q (%v)", plaintext, err)
+	}
+	encrypted, _ := server.Encrypt([]byte("x"))
+	if version, _ := parseHeader(encrypted); version != 2 {
+		t.Fatalf("expected server to encrypt with the rotated key, got version %d", version)
+	}
+}
+
+func TestPassphraseWrappedKeyring(t *testing.T) {
+	cm, dir := newTestManager(t)
+	encrypted, _ := cm.Encrypt([]byte("secret"))
+	rawKey, _ := os.ReadFile(filepath.Join(dir, ".encryption.key"))
+
+	wrapper, err := NewPassphraseWrapper("correct horse")
+	if err != nil {
+		t.Fatalf("NewPassphraseWrapper: %v", err)
+	}
+	if err := cm.SetKeyWrapper(wrapper); err != nil {
+		t.Fatalf("SetKeyWrapper: %v", err)
+	}
+
+	keyring, _ := os.ReadFile(filepath.Join(dir, ".encryption.key"))
+	if bytes.Contains(keyring, bytes.TrimSpace(rawKey)) {
+		t.Fatalf("wrapped keyring must not contain the raw key:\n%s", keyring)
+	}
+
+	if _, err := NewCryptoManager(); !errors.Is(err, ErrMasterKeyLocked) {
+		t.Fatalf("expected a locked keyring without passphrase, got %v", err)
+	}
+	t.Setenv("PULSE_MASTER_PASSPHRASE", "wrong")
+	if _, err := NewCryptoManager(); !errors.Is(err, ErrMasterKeyLocked) {
+		t.Fatalf("expected a locked keyring with the wrong passphrase, got %v", err)
+	}
+
+	passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("correct horse\n"), 0600); err != nil {
+		t.Fatalf("write passphrase: %v", err)
+	}
+	t.Setenv("PULSE_MASTER_PASSPHRASE", "")
+	t.Setenv("PULSE_MASTER_PASSPHRASE_FILE", passphraseFile)
+	unlocked, err := NewCryptoManager()
+	if err != nil {
+		t.Fatalf("NewCryptoManager with passphrase: %v", err)
+	}
+	if plaintext, err := unlocked.Decrypt(encrypted); err != nil || string(plaintext) != "secret" {
+		t.Fatalf("expected unwrapped key to decrypt, got %q (%v)", plaintext, err)
+	}
+	if info := unlocked.KeyInfo(); info.WrapMethod != "passphrase" {
+		t.Fatalf("expected passphrase wrapping, got %+v", info)
+	}
+}
+
+func TestPluginWrappedKeyring(t *testing.T) {
+	cm, dir := newTestManager(t)
+	encrypted, _ := cm.Encrypt([]byte("secret"))
+
+	// The fake KMS prefixes wrapped keys with "KMS" (base64 S01T) and strips it again on unwrap
+	plugin := filepath.Join(t.TempDir(), "kms-plugin")
+	script := "#!/bin/sh\ncase \"$3\" in\n  wrap) printf 'S01T'; cat ;;\n  unwrap) cut -c5- ;;\n  *) exit 1 ;;\nesac\n"
+	if err := os.WriteFile(plugin, []byte(script), 0700); err != nil {
+		t.Fatalf("write plugin: %v", err)
+	}
+	t.Setenv(KeyWrapPluginEnv, plugin+" --key pulse")
+	wrapper, err := NewPluginWrapper(KeyWrapPluginFromEnv())
+	if err != nil {
+		t.Fatalf("NewPluginWrapper: %v", err)
+	}
+	if err := cm.SetKeyWrapper(wrapper); err != nil {
+		t.Fatalf("SetKeyWrapper: %v", err)
+	}
+
+	keyring, _ := os.ReadFile(filepath.Join(dir, ".encryption.key"))
+	if !strings.Contains(string(keyring), `"method": "plugin"`) || !strings.Contains(string(keyring), `"key": "S01T`) {
+		t.Fatalf("expected plugin wrapping to be recorded:\n%s", keyring)
+	}
+	if strings.Contains(string(keyring), plugin) {
+		t.Fatalf("the plugin command must not be stored in the keyring:\n%s", keyring)
+	}
+
+	unlocked, err := NewCryptoManager()
+	if err != nil {
+		t.Fatalf("NewCryptoManager: %v", err)
+	}
+	if plaintext, err := unlocked.Decrypt(encrypted); err != nil || string(plaintext) != "secret" {
+		t.Fatalf("expected plugin-unwrapped key to decrypt, got %q (%v)", plaintext, err)
+	}
+
+	// A different configured command, or none, is refused without running anything
+	t.Setenv(KeyWrapPluginEnv, plugin+" --key other")
+	if _, err := NewCryptoManager(); !errors.Is(err, ErrMasterKeyLocked) {
+		t.Fatalf("expected a locked keyring for a different plugin command, got %v", err)
+	}
+	t.Setenv(KeyWrapPluginEnv, "")
+	if _, err := NewCryptoManager(); !errors.Is(err, ErrMasterKeyLocked) {
+		t.Fatalf("expected a locked keyring without a plugin command, got %v", err)
+	}
+	t.Setenv(KeyWrapPluginEnv, plugin+" --key pulse")
+
+	if err := os.Remove(plugin); err != nil {
+		t.Fatalf("remove plugin: %v", err)
+	}
+	if _, err := NewCryptoManager(); !errors.Is(err, ErrMasterKeyLocked) {
+		t.Fatalf("expected a locked keyring when the plugin fails, got %v", err)
+	}
+}
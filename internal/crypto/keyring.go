@@ -0,0 +1,342 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const keyringFormat = 1
+
+// keyring holds every data key that may still be needed to decrypt persisted files
+type keyring struct {
+	active  uint32
+	keys    map[uint32][]byte
+	created map[uint32]time.Time
+	wrapper KeyWrapper // nil when keys are stored unwrapped
+}
+
+// keyringFile is the JSON form of the keyring. Keys are base64 encoded and, when Wrap is set,
+// wrapped by the named KeyWrapper.
+type keyringFile struct {
+	Format int              `json:"format"`
+	Active uint32           `json:"active"`
+	Wrap   *keyringWrap     `json:"wrap,omitempty"`
+	Keys   []keyringFileKey `json:"keys"`
+}
+
+type keyringWrap struct {
+	Method string            `json:"method"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+type keyringFileKey struct {
+	Version uint32    `json:"version"`
+	Key     string    `json:"key"`
+	Created time.Time `json:"created,omitempty"`
+}
+
+// KeyInfo describes the keyring without exposing key material
+type KeyInfo struct {
+	ActiveVersion uint32
+	Versions      []uint32
+	WrapMethod    string // Empty when keys are stored unwrapped
+}
+
+// KeyFileWrapMethod returns the wrapping method named in a key file without unwrapping anything.
+// It returns "" for unwrapped keyrings and legacy single-key files.
+func KeyFileWrapMethod(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !isKeyringFile(data) {
+		return "", nil
+	}
+	var file keyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("invalid encryption keyring: %w", err)
+	}
+	if file.Wrap == nil {
+		return "", nil
+	}
+	return file.Wrap.Method, nil
+}
+
+func isKeyringFile(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// parseKeyring reads a JSON keyring or a legacy single-key file, unwrapping keys as needed
+func parseKeyring(data []byte) (*keyring, error) {
+	if !isKeyringFile(data) {
+		key := make([]byte, 32)
+		n, err := base64.StdEncoding.Decode(key, bytes.TrimSpace(data))
+		if err != nil || n != 32 {
+			return nil, fmt.Errorf("invalid encryption key file")
+		}
+		return &keyring{
+			active:  legacyKeyVersion,
+			keys:    map[uint32][]byte{legacyKeyVersion: key},
+			created: map[uint32]time.Time{},
+		}, nil
+	}
+
+	var file keyringFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid encryption keyring: %w", err)
+	}
+	if file.Format != keyringFormat {
+		return nil, fmt.Errorf("unsupported encryption keyring format %d", file.Format)
+	}
+
+	ring := &keyring{
+		active:  file.Active,
+		keys:    make(map[uint32][]byte, len(file.Keys)),
+		created: make(map[uint32]time.Time, len(file.Keys)),
+	}
+	if file.Wrap != nil {
+		wrapper, err := newKeyWrapper(file.Wrap.Method, file.Wrap.Params)
+		if err != nil {
+			return nil, err
+		}
+		ring.wrapper = wrapper
+	}
+
+	for _, entry := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(entry.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key version %d in keyring: %w", entry.Version, err)
+		}
+		if ring.wrapper != nil {
+			if key, err = ring.wrapper.Unwrap(entry.Version, key); err != nil {
+				return nil, fmt.Errorf("failed to unwrap key version %d: %w", entry.Version, err)
+			}
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key version %d in keyring is not a 256-bit key", entry.Version)
+		}
+		ring.keys[entry.Version] = key
+		ring.created[entry.Version] = entry.Created
+	}
+
+	if ring.keys[ring.active] == nil {
+		return nil, fmt.Errorf("active key version %d is missing from the keyring", ring.active)
+	}
+	return ring, nil
+}
+
+// encode serialises the keyring, wrapping keys with the keyring's wrapper
+func (r *keyring) encode() ([]byte, error) {
+	file := keyringFile{Format: keyringFormat, Active: r.active}
+	if r.wrapper != nil {
+		file.Wrap = &keyringWrap{Method: r.wrapper.Method(), Params: r.wrapper.Params()}
+	}
+
+	for _, version := range r.versions() {
+		key := r.keys[version]
+		if r.wrapper != nil {
+			wrapped, err := r.wrapper.Wrap(version, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to wrap key version %d: %w", version, err)
+			}
+			key = wrapped
+		}
+		file.Keys = append(file.Keys, keyringFileKey{
+			Version: version,
+			Key:     base64.StdEncoding.EncodeToString(key),
+			Created: r.created[version],
+		})
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+func (r *keyring) versions() []uint32 {
+	versions := make([]uint32, 0, len(r.keys))
+	for version := range r.keys {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+func (r *keyring) clone() *keyring {
+	out := &keyring{
+		active:  r.active,
+		keys:    make(map[uint32][]byte, len(r.keys)),
+		created: make(map[uint32]time.Time, len(r.created)),
+		wrapper: r.wrapper,
+	}
+	for version, key := range r.keys {
+		out.keys[version] = key
+	}
+	for version, created := range r.created {
+		out.created[version] = created
+	}
+	return out
+}
+
+// fileStamp identifies a version of the key file so changes made by other processes are noticed
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// reloadIfChanged re-reads the keyring when the key file was replaced, e.g. by `pulse crypto
+// rotate` while the server is running
+func (c *CryptoManager) reloadIfChanged() {
+	if c.keyPath == "" {
+		return
+	}
+	stamp := statFile(c.keyPath)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stamp == c.stamp || stamp == (fileStamp{}) {
+		return
+	}
+
+	data, err := os.ReadFile(c.keyPath)
+	if err == nil {
+		var ring *keyring
+		if ring, err = parseKeyring(data); err == nil {
+			c.ring = ring
+			c.stamp = stamp
+			log.Info().Uint32("activeVersion", ring.active).Msg("Reloaded encryption keyring")
+			return
+		}
+	}
+	log.Warn().Err(err).Str("path", c.keyPath).Msg("Failed to reload changed encryption keyring, keeping current keys")
+	c.stamp = stamp
+}
+
+// saveLocked atomically replaces the key file with ring and makes it the current keyring
+func (c *CryptoManager) saveLocked(ring *keyring) error {
+	data, err := ring.encode()
+	if err != nil {
+		return err
+	}
+
+	tmp := c.keyPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write keyring: %w", err)
+	}
+	if err := os.Rename(tmp, c.keyPath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace keyring: %w", err)
+	}
+
+	c.ring = ring
+	c.stamp = statFile(c.keyPath)
+	return nil
+}
+
+// KeyInfo reports the key versions in the keyring
+func (c *CryptoManager) KeyInfo() KeyInfo {
+	c.reloadIfChanged()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info := KeyInfo{ActiveVersion: c.ring.active, Versions: c.ring.versions()}
+	if c.ring.wrapper != nil {
+		info.WrapMethod = c.ring.wrapper.Method()
+	}
+	return info
+}
+
+// SetKeyWrapper rewrites the keyring with every key wrapped by wrapper. A nil wrapper stores the
+// keys unwrapped again. Encrypted data is not touched.
+func (c *CryptoManager) SetKeyWrapper(wrapper KeyWrapper) error {
+	c.reloadIfChanged()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ring := c.ring.clone()
+	ring.wrapper = wrapper
+	return c.saveLocked(ring)
+}
+
+// Rotation is a key rotation in progress. The new key is stored in the keyring before any data
+// is encrypted with it, so files stay readable whichever step an interrupted rotation reached.
+type Rotation struct {
+	c       *CryptoManager
+	version uint32
+	key     []byte
+}
+
+// BeginRotation adds a new, not yet active, key version to the keyring
+func (c *CryptoManager) BeginRotation() (*Rotation, error) {
+	c.reloadIfChanged()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ring := c.ring.clone()
+	versions := ring.versions()
+	version := versions[len(versions)-1] + 1
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	ring.keys[version] = key
+	ring.created[version] = time.Now().UTC()
+
+	if err := c.saveLocked(ring); err != nil {
+		return nil, err
+	}
+	return &Rotation{c: c, version: version, key: key}, nil
+}
+
+// Version returns the key version being rotated to
+func (r *Rotation) Version() uint32 {
+	return r.version
+}
+
+// Encrypt encrypts data with the new key
+func (r *Rotation) Encrypt(plaintext []byte) ([]byte, error) {
+	return seal(r.version, r.key, plaintext)
+}
+
+// Commit activates the new key and drops every older key version
+func (r *Rotation) Commit() error {
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	ring := r.c.ring.clone()
+	ring.active = r.version
+	for version := range ring.keys {
+		if version != r.version {
+			delete(ring.keys, version)
+			delete(ring.created, version)
+		}
+	}
+	return r.c.saveLocked(ring)
+}
+
+// Abort removes the new key from the keyring; nothing may have been written with it
+func (r *Rotation) Abort() error {
+	r.c.mu.Lock()
+	defer r.c.mu.Unlock()
+
+	ring := r.c.ring.clone()
+	delete(ring.keys, r.version)
+	delete(ring.created, r.version)
+	return r.c.saveLocked(ring)
+}
@@ -0,0 +1,289 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrMasterKeyLocked is returned when the keyring is wrapped and the passphrase or plugin needed
+// to unwrap it is not available
+var ErrMasterKeyLocked = errors.New("encryption keyring is locked")
+
+// KeyWrapper protects the data keys in the keyring so that copying the data directory alone does
+// not reveal them. Params are stored unencrypted next to the wrapped keys and passed back to the
+// registered factory when the keyring is loaded, so they must not contain secrets.
+type KeyWrapper interface {
+	Method() string
+	Params() map[string]string
+	Wrap(version uint32, key []byte) ([]byte, error)
+	Unwrap(version uint32, wrapped []byte) ([]byte, error)
+}
+
+// KeyWrapperFactory recreates a KeyWrapper from the parameters stored in the keyring
+type KeyWrapperFactory func(params map[string]string) (KeyWrapper, error)
+
+var (
+	keyWrappersMu sync.RWMutex
+	keyWrappers   = map[string]KeyWrapperFactory{
+		"passphrase": newPassphraseWrapperFromParams,
+		"plugin":     newPluginWrapperFromParams,
+	}
+)
+
+// RegisterKeyWrapper makes a key wrapping method available to keyrings that name it
+func RegisterKeyWrapper(method string, factory KeyWrapperFactory) {
+	keyWrappersMu.Lock()
+	defer keyWrappersMu.Unlock()
+	keyWrappers[method] = factory
+}
+
+func newKeyWrapper(method string, params map[string]string) (KeyWrapper, error) {
+	keyWrappersMu.RLock()
+	factory, ok := keyWrappers[method]
+	keyWrappersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown key wrapping method %q", method)
+	}
+	return factory(params)
+}
+
+// MasterPassphraseFromEnv returns the passphrase for a passphrase-wrapped keyring from
+// PULSE_MASTER_PASSPHRASE, the file named by PULSE_MASTER_PASSPHRASE_FILE, or the systemd
+// credential pulse-master-passphrase. It returns "" when none is set.
+func MasterPassphraseFromEnv() (string, error) {
+	if value := os.Getenv("PULSE_MASTER_PASSPHRASE"); value != "" {
+		return value, nil
+	}
+
+	path := os.Getenv("PULSE_MASTER_PASSPHRASE_FILE")
+	if path == "" {
+		if dir := os.Getenv("CREDENTIALS_DIRECTORY"); dir != "" {
+			if candidate := filepath.Join(dir, "pulse-master-passphrase"); fileExists(candidate) {
+				path = candidate
+			}
+		}
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read master passphrase: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Argon2id parameters for new passphrase-wrapped keyrings
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024 // KiB
+	argonThreads = 4
+)
+
+// passphraseWrapper wraps keys with AES-GCM under a key derived from a passphrase with argon2id
+type passphraseWrapper struct {
+	salt    []byte
+	time    uint32
+	memory  uint32
+	threads uint8
+	kek     []byte
+}
+
+// NewPassphraseWrapper creates a wrapper with a fresh salt for the given passphrase
+func NewPassphraseWrapper(passphrase string) (KeyWrapper, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("master passphrase must not be empty")
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return newPassphraseWrapper(passphrase, salt, argonTime, argonMemory, argonThreads), nil
+}
+
+func newPassphraseWrapper(passphrase string, salt []byte, time, memory uint32, threads uint8) *passphraseWrapper {
+	return &passphraseWrapper{
+		salt:    salt,
+		time:    time,
+		memory:  memory,
+		threads: threads,
+		kek:     argon2.IDKey([]byte(passphrase), salt, time, memory, threads, 32),
+	}
+}
+
+func newPassphraseWrapperFromParams(params map[string]string) (KeyWrapper, error) {
+	salt, err := base64.StdEncoding.DecodeString(params["salt"])
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("invalid argon2id salt in keyring")
+	}
+	time, err1 := strconv.ParseUint(params["time"], 10, 32)
+	memory, err2 := strconv.ParseUint(params["memory"], 10, 32)
+	threads, err3 := strconv.ParseUint(params["threads"], 10, 8)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, fmt.Errorf("invalid argon2id parameters in keyring: %w", err)
+	}
+
+	passphrase, err := MasterPassphraseFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("%w: set PULSE_MASTER_PASSPHRASE or PULSE_MASTER_PASSPHRASE_FILE", ErrMasterKeyLocked)
+	}
+	return newPassphraseWrapper(passphrase, salt, uint32(time), uint32(memory), uint8(threads)), nil
+}
+
+func (p *passphraseWrapper) Method() string { return "passphrase" }
+
+func (p *passphraseWrapper) Params() map[string]string {
+	return map[string]string{
+		"kdf":     "argon2id",
+		"salt":    base64.StdEncoding.EncodeToString(p.salt),
+		"time":    strconv.FormatUint(uint64(p.time), 10),
+		"memory":  strconv.FormatUint(uint64(p.memory), 10),
+		"threads": strconv.FormatUint(uint64(p.threads), 10),
+	}
+}
+
+func (p *passphraseWrapper) Wrap(version uint32, key []byte) ([]byte, error) {
+	gcm, err := newGCM(p.kek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, key, wrapAAD(version)), nil
+}
+
+func (p *passphraseWrapper) Unwrap(version uint32, wrapped []byte) ([]byte, error) {
+	gcm, err := newGCM(p.kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("wrapped key too short")
+	}
+	key, err := gcm.Open(nil, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():], wrapAAD(version))
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong master passphrase", ErrMasterKeyLocked)
+	}
+	return key, nil
+}
+
+// wrapAAD binds a wrapped key to its version so keys cannot be swapped within the keyring
+func wrapAAD(version uint32) []byte {
+	return []byte("pulse-data-key-v" + strconv.FormatUint(uint64(version), 10))
+}
+
+// KeyWrapPluginEnv names the environment variable holding the key wrap plugin command. The
+// command is never read from the keyring, so writing to the data directory cannot make Pulse run
+// a program.
+const KeyWrapPluginEnv = "PULSE_KEY_WRAP_PLUGIN"
+
+// pluginWrapper delegates wrapping to an external program, e.g. a KMS client or a TPM sealing
+// tool. The program is run as `<command> wrap` or `<command> unwrap` with the base64 input on
+// stdin and must print the base64 result on stdout. PULSE_KEY_VERSION is set in its environment.
+type pluginWrapper struct {
+	command string
+	timeout time.Duration
+}
+
+// NewPluginWrapper creates a wrapper that runs command, which may include arguments
+func NewPluginWrapper(command string) (KeyWrapper, error) {
+	command = strings.TrimSpace(command)
+	if len(strings.Fields(command)) == 0 {
+		return nil, fmt.Errorf("key wrap plugin command must not be empty")
+	}
+	return &pluginWrapper{command: command, timeout: 30 * time.Second}, nil
+}
+
+// KeyWrapPluginFromEnv returns the plugin command configured in PULSE_KEY_WRAP_PLUGIN, or ""
+func KeyWrapPluginFromEnv() string {
+	return strings.TrimSpace(os.Getenv(KeyWrapPluginEnv))
+}
+
+// newPluginWrapperFromParams uses the configured plugin command. The keyring only records a hash
+// of the command it was wrapped with, and loading is refused when the two differ.
+func newPluginWrapperFromParams(params map[string]string) (KeyWrapper, error) {
+	command := KeyWrapPluginFromEnv()
+	if command == "" {
+		return nil, fmt.Errorf("%w: set %s to the key wrap plugin command", ErrMasterKeyLocked, KeyWrapPluginEnv)
+	}
+	if params["commandSHA256"] != commandDigest(command) {
+		return nil, fmt.Errorf("%w: %s differs from the plugin command the keyring was wrapped with", ErrMasterKeyLocked, KeyWrapPluginEnv)
+	}
+	return NewPluginWrapper(command)
+}
+
+func commandDigest(command string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(command)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (p *pluginWrapper) Method() string { return "plugin" }
+
+func (p *pluginWrapper) Params() map[string]string {
+	return map[string]string{"commandSHA256": commandDigest(p.command)}
+}
+
+func (p *pluginWrapper) Wrap(version uint32, key []byte) ([]byte, error) {
+	return p.run("wrap", version, key)
+}
+
+func (p *pluginWrapper) Unwrap(version uint32, wrapped []byte) ([]byte, error) {
+	key, err := p.run("unwrap", version, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMasterKeyLocked, err)
+	}
+	return key, nil
+}
+
+func (p *pluginWrapper) run(action string, version uint32, input []byte) ([]byte, error) {
+	fields := strings.Fields(p.command)
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, fields[0], append(fields[1:], action)...)
+	cmd.Env = append(os.Environ(), "PULSE_KEY_VERSION="+strconv.FormatUint(uint64(version), 10))
+	cmd.Stdin = strings.NewReader(base64.StdEncoding.EncodeToString(input) + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("key wrap plugin %s failed: %w: %s", action, err, msg)
+		}
+		return nil, fmt.Errorf("key wrap plugin %s failed: %w", action, err)
+	}
+
+	result, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("key wrap plugin %s returned invalid base64: %w", action, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,601 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/websocket"
+	pkgdiscovery "github.com/RouXx67/PulseUp/pkg/discovery"
+	"github.com/rs/zerolog/log"
+)
+
+// Onboarding candidate states
+const (
+	CandidatePending    = "pending"
+	CandidateOnboarding = "onboarding"
+	CandidateAdded      = "added"
+	CandidateFailed     = "failed"
+	CandidateRejected   = "rejected"
+)
+
+// BackgroundScanSource names the regular discovery scan as the source of a candidate
+const BackgroundScanSource = "background"
+
+const (
+	// onboardingRetryInterval spaces out repeated attempts against a server that failed validation
+	onboardingRetryInterval = time.Hour
+	// sweepCheckInterval is how often the sweep loop looks for due sweeps
+	sweepCheckInterval = time.Minute
+	// sweepTimeout bounds a single scheduled sweep
+	sweepTimeout = 5 * time.Minute
+	// hostLookupTimeout bounds the forward lookup that confirms a discovered hostname
+	hostLookupTimeout = 5 * time.Second
+)
+
+// OnboardFunc validates a discovered server with the given credential profile and adds it as a
+// node, returning the name of the new node. approvedBy is empty for automatic onboarding.
+type OnboardFunc func(ctx context.Context, server pkgdiscovery.DiscoveredServer, profile config.DiscoveryCredentialProfile, approvedBy string) (string, error)
+
+// ConfiguredFunc reports whether a discovered server is already monitored
+type ConfiguredFunc func(server pkgdiscovery.DiscoveredServer) bool
+
+// Candidate is a discovered server that matched the onboarding allowlist
+type Candidate struct {
+	ID          string                        `json:"id"`
+	Server      pkgdiscovery.DiscoveredServer `json:"server"`
+	Status      string                        `json:"status"`
+	Source      string                        `json:"source"`
+	NodeName    string                        `json:"nodeName,omitempty"`
+	Error       string                        `json:"error,omitempty"`
+	FirstSeen   time.Time                     `json:"firstSeen"`
+	LastSeen    time.Time                     `json:"lastSeen"`
+	LastAttempt time.Time                     `json:"lastAttempt,omitempty"`
+}
+
+// MissingServer is a previously discovered server that has stopped answering discovery scans
+type MissingServer struct {
+	Server      pkgdiscovery.DiscoveredServer `json:"server"`
+	LastSeen    time.Time                     `json:"lastSeen"`
+	MissedScans int                           `json:"missedScans"`
+	ReportedAt  time.Time                     `json:"reportedAt"`
+}
+
+// trackedServer is the presence record of a discovered server
+type trackedServer struct {
+	server   pkgdiscovery.DiscoveredServer
+	lastSeen time.Time
+	missed   int
+	reported time.Time
+}
+
+// Onboarder turns discovered servers into monitored nodes. It outlives individual discovery
+// services so that queued candidates and presence history survive a discovery restart.
+type Onboarder struct {
+	mu           sync.Mutex
+	cfg          config.DiscoveryOnboardingConfig
+	wsHub        *websocket.Hub
+	cfgProvider  func() config.DiscoveryConfig
+	onboard      OnboardFunc
+	isConfigured ConfiguredFunc
+	candidates   map[string]*Candidate
+	servers      map[string]*trackedServer
+	lastSweep    map[string]time.Time
+	sweeping     map[string]bool
+	now          func() time.Time
+	lookupHost   config.HostLookupFunc
+}
+
+// NewOnboarder creates an onboarder. cfgProvider supplies the discovery settings used to build
+// the scanner for scheduled sweeps.
+func NewOnboarder(wsHub *websocket.Hub, cfgProvider func() config.DiscoveryConfig) *Onboarder {
+	if cfgProvider == nil {
+		cfgProvider = func() config.DiscoveryConfig { return config.DefaultDiscoveryConfig() }
+	}
+	return &Onboarder{
+		cfg:         config.NormalizeDiscoveryOnboardingConfig(config.DiscoveryOnboardingConfig{}),
+		wsHub:       wsHub,
+		cfgProvider: cfgProvider,
+		candidates:  make(map[string]*Candidate),
+		servers:     make(map[string]*trackedServer),
+		lastSweep:   make(map[string]time.Time),
+		sweeping:    make(map[string]bool),
+		now:         time.Now,
+		lookupHost:  lookupHost,
+	}
+}
+
+// lookupHost resolves a discovered hostname so hostname allowlist entries can be confirmed
+func lookupHost(hostname string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), hostLookupTimeout)
+	defer cancel()
+	return net.DefaultResolver.LookupHost(ctx, hostname)
+}
+
+// SetHandlers installs the callbacks used to validate, add and recognise nodes
+func (o *Onboarder) SetHandlers(onboard OnboardFunc, isConfigured ConfiguredFunc) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.onboard = onboard
+	o.isConfigured = isConfigured
+}
+
+// SetWebSocketHub sets the hub used to broadcast onboarding events
+func (o *Onboarder) SetWebSocketHub(wsHub *websocket.Hub) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.wsHub = wsHub
+}
+
+// SetConfig replaces the onboarding settings
+func (o *Onboarder) SetConfig(cfg config.DiscoveryOnboardingConfig) {
+	cfg = config.NormalizeDiscoveryOnboardingConfig(cfg)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.cfg = cfg
+
+	// Forget the schedule of sweeps that no longer exist
+	for name := range o.lastSweep {
+		found := false
+		for _, sweep := range cfg.Sweeps {
+			if sweep.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(o.lastSweep, name)
+		}
+	}
+}
+
+// Config returns the current onboarding settings
+func (o *Onboarder) Config() config.DiscoveryOnboardingConfig {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.cfg
+}
+
+// Candidates returns the tracked onboarding candidates, newest first
+func (o *Onboarder) Candidates() []Candidate {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	result := make([]Candidate, 0, len(o.candidates))
+	for _, candidate := range o.candidates {
+		result = append(result, *candidate)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].FirstSeen.Equal(result[j].FirstSeen) {
+			return result[i].FirstSeen.After(result[j].FirstSeen)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Missing returns the servers that have been absent from enough scans to be reported
+func (o *Onboarder) Missing() []MissingServer {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var result []MissingServer
+	for _, tracked := range o.servers {
+		if tracked.reported.IsZero() {
+			continue
+		}
+		result = append(result, MissingServer{
+			Server:      tracked.server,
+			LastSeen:    tracked.lastSeen,
+			MissedScans: tracked.missed,
+			ReportedAt:  tracked.reported,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	return result
+}
+
+// Observe records the servers found by a scan. scanned limits the subnets the scan covered;
+// nil means the scan covered every subnet. Only complete scans count servers as missed.
+func (o *Onboarder) Observe(ctx context.Context, source string, scanned []*net.IPNet, servers []pkgdiscovery.DiscoveredServer, complete bool) {
+	o.mu.Lock()
+	cfg := o.cfg
+	lookup := o.lookupHost
+	o.mu.Unlock()
+
+	// Match the allowlist before taking the lock, since hostname entries need a DNS lookup
+	allowed := make(map[string]bool, len(servers))
+	if cfg.Enabled {
+		for _, server := range servers {
+			allowed[CandidateID(server)] = cfg.Allows(server.IP, server.Hostname, lookup)
+		}
+	}
+
+	now := o.now()
+	found := make(map[string]bool, len(servers))
+
+	o.mu.Lock()
+	var toOnboard []Candidate
+	var newlyMissing []MissingServer
+
+	for _, server := range servers {
+		id := CandidateID(server)
+		found[id] = true
+
+		tracked, ok := o.servers[id]
+		if !ok {
+			tracked = &trackedServer{}
+			o.servers[id] = tracked
+		}
+		if !tracked.reported.IsZero() {
+			log.Info().
+				Str("ip", server.IP).
+				Str("type", server.Type).
+				Msg("Missing discovered server is answering again")
+		}
+		tracked.server = server
+		tracked.lastSeen = now
+		tracked.missed = 0
+		tracked.reported = time.Time{}
+
+		if candidate, ok := o.considerLocked(cfg, source, server, allowed[id], now); ok {
+			toOnboard = append(toOnboard, candidate)
+		}
+	}
+
+	if complete {
+		for id, tracked := range o.servers {
+			if found[id] || !coveredBy(scanned, tracked.server.IP) {
+				continue
+			}
+			tracked.missed++
+			if tracked.missed >= cfg.MissingAfterScans && tracked.reported.IsZero() {
+				tracked.reported = now
+				newlyMissing = append(newlyMissing, MissingServer{
+					Server:      tracked.server,
+					LastSeen:    tracked.lastSeen,
+					MissedScans: tracked.missed,
+					ReportedAt:  now,
+				})
+			}
+		}
+	}
+	o.mu.Unlock()
+
+	for _, missing := range newlyMissing {
+		log.Warn().
+			Str("ip", missing.Server.IP).
+			Str("type", missing.Server.Type).
+			Str("hostname", missing.Server.Hostname).
+			Int("missedScans", missing.MissedScans).
+			Time("lastSeen", missing.LastSeen).
+			Msg("Discovered server has disappeared")
+		o.broadcast("discovery_server_missing", missing)
+	}
+
+	for _, candidate := range toOnboard {
+		o.attempt(ctx, candidate.ID, "")
+	}
+}
+
+// considerLocked tracks a server that may match the allowlist and reports whether it should be
+// onboarded automatically now. Profiles without TLS verification are never used automatically,
+// so their servers wait for approval even in auto mode.
+func (o *Onboarder) considerLocked(cfg config.DiscoveryOnboardingConfig, source string, server pkgdiscovery.DiscoveredServer, allowed bool, now time.Time) (Candidate, bool) {
+	id := CandidateID(server)
+	candidate, exists := o.candidates[id]
+	if exists {
+		candidate.Server = server
+		candidate.LastSeen = now
+	}
+
+	if !cfg.Enabled || !allowed {
+		return Candidate{}, false
+	}
+	profile, ok := cfg.Profile(server.Type)
+	if !ok {
+		return Candidate{}, false
+	}
+	automatic := cfg.Mode == config.DiscoveryOnboardingModeAuto && profile.VerifySSL
+	if o.isConfigured != nil && o.isConfigured(server) {
+		return Candidate{}, false
+	}
+
+	if !exists {
+		candidate = &Candidate{
+			ID:        id,
+			Server:    server,
+			Status:    CandidatePending,
+			Source:    source,
+			FirstSeen: now,
+			LastSeen:  now,
+		}
+		o.candidates[id] = candidate
+		log.Info().
+			Str("ip", server.IP).
+			Str("type", server.Type).
+			Str("source", source).
+			Str("mode", cfg.Mode).
+			Msg("Discovered server matches onboarding allowlist")
+		if !automatic {
+			o.broadcastLocked("discovery_onboarding_pending", *candidate)
+		}
+	}
+
+	if !automatic {
+		return Candidate{}, false
+	}
+	switch candidate.Status {
+	case CandidatePending:
+		return *candidate, true
+	case CandidateFailed:
+		return *candidate, now.Sub(candidate.LastAttempt) >= onboardingRetryInterval
+	}
+	return Candidate{}, false
+}
+
+// Approve onboards a queued candidate on behalf of an admin
+func (o *Onboarder) Approve(ctx context.Context, id, user string) (Candidate, error) {
+	return o.attempt(ctx, id, user)
+}
+
+// Reject drops a queued candidate; it is not offered again unless the rejection is cleared
+func (o *Onboarder) Reject(id string) (Candidate, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	candidate, ok := o.candidates[id]
+	if !ok {
+		return Candidate{}, fmt.Errorf("candidate %s not found", id)
+	}
+	switch candidate.Status {
+	case CandidateAdded:
+		return *candidate, fmt.Errorf("candidate %s has already been added", id)
+	case CandidateOnboarding:
+		return *candidate, fmt.Errorf("candidate %s is being onboarded", id)
+	}
+	candidate.Status = CandidateRejected
+	candidate.Error = ""
+	return *candidate, nil
+}
+
+// Forget removes a candidate so it is evaluated afresh by the next scan
+func (o *Onboarder) Forget(id string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.candidates[id]; !ok {
+		return false
+	}
+	delete(o.candidates, id)
+	return true
+}
+
+// attempt validates and adds a candidate, recording the outcome. The candidate is marked as
+// onboarding meanwhile so a scan and an approval cannot add the same server twice.
+func (o *Onboarder) attempt(ctx context.Context, id, approvedBy string) (Candidate, error) {
+	o.mu.Lock()
+	candidate, ok := o.candidates[id]
+	if !ok {
+		o.mu.Unlock()
+		return Candidate{}, fmt.Errorf("candidate %s not found", id)
+	}
+	switch candidate.Status {
+	case CandidateAdded:
+		result := *candidate
+		o.mu.Unlock()
+		return result, fmt.Errorf("candidate %s has already been added", id)
+	case CandidateOnboarding:
+		result := *candidate
+		o.mu.Unlock()
+		return result, fmt.Errorf("candidate %s is already being onboarded", id)
+	}
+	candidate.Status = CandidateOnboarding
+	profile, hasProfile := o.cfg.Profile(candidate.Server.Type)
+	onboard := o.onboard
+	server := candidate.Server
+	candidate.LastAttempt = o.now()
+	o.mu.Unlock()
+
+	var nodeName string
+	var err error
+	switch {
+	case !hasProfile:
+		err = fmt.Errorf("no %s credential profile is configured", server.Type)
+	case onboard == nil:
+		err = fmt.Errorf("onboarding is not available")
+	default:
+		nodeName, err = onboard(ctx, server, profile, approvedBy)
+	}
+
+	o.mu.Lock()
+	if err != nil {
+		candidate.Status = CandidateFailed
+		candidate.Error = err.Error()
+	} else {
+		candidate.Status = CandidateAdded
+		candidate.NodeName = nodeName
+		candidate.Error = ""
+	}
+	result := *candidate
+	o.mu.Unlock()
+
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("ip", server.IP).
+			Str("type", server.Type).
+			Msg("Failed to onboard discovered server")
+		o.broadcast("discovery_onboarding_failed", result)
+		return result, err
+	}
+
+	log.Info().
+		Str("ip", server.IP).
+		Str("type", server.Type).
+		Str("node", nodeName).
+		Str("approvedBy", approvedBy).
+		Msg("Onboarded discovered server")
+	o.broadcast("discovery_onboarding_added", result)
+	return result, nil
+}
+
+// Run executes the scheduled subnet sweeps until ctx is cancelled
+func (o *Onboarder) Run(ctx context.Context) {
+	ticker := time.NewTicker(sweepCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, sweep := range o.dueSweeps() {
+				go o.runSweep(ctx, sweep)
+			}
+		}
+	}
+}
+
+// dueSweeps returns the sweeps whose interval has elapsed and marks them as running
+func (o *Onboarder) dueSweeps() []config.DiscoverySweep {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.cfg.Enabled {
+		return nil
+	}
+	now := o.now()
+	var due []config.DiscoverySweep
+	for _, sweep := range o.cfg.Sweeps {
+		if o.sweeping[sweep.Name] {
+			continue
+		}
+		interval := time.Duration(sweep.IntervalMinutes) * time.Minute
+		if last, ok := o.lastSweep[sweep.Name]; ok && now.Sub(last) < interval {
+			continue
+		}
+		o.sweeping[sweep.Name] = true
+		o.lastSweep[sweep.Name] = now
+		due = append(due, sweep)
+	}
+	return due
+}
+
+// RunSweep runs a configured sweep immediately
+func (o *Onboarder) RunSweep(ctx context.Context, name string) error {
+	o.mu.Lock()
+	var sweep config.DiscoverySweep
+	found := false
+	for _, candidate := range o.cfg.Sweeps {
+		if candidate.Name == name {
+			sweep = candidate
+			found = true
+			break
+		}
+	}
+	if !found {
+		o.mu.Unlock()
+		return fmt.Errorf("sweep %s not found", name)
+	}
+	if o.sweeping[name] {
+		o.mu.Unlock()
+		return fmt.Errorf("sweep %s is already running", name)
+	}
+	o.sweeping[name] = true
+	o.lastSweep[name] = o.now()
+	o.mu.Unlock()
+
+	go o.runSweep(ctx, sweep)
+	return nil
+}
+
+// runSweep scans the subnets of a sweep and feeds the result to Observe
+func (o *Onboarder) runSweep(ctx context.Context, sweep config.DiscoverySweep) {
+	defer func() {
+		o.mu.Lock()
+		delete(o.sweeping, sweep.Name)
+		o.mu.Unlock()
+	}()
+
+	var scanned []*net.IPNet
+	for _, subnet := range sweep.Subnets {
+		if _, network, err := net.ParseCIDR(subnet); err == nil {
+			scanned = append(scanned, network)
+		}
+	}
+	if len(scanned) == 0 {
+		return
+	}
+
+	scanner, err := BuildScanner(o.cfgProvider())
+	if err != nil {
+		log.Warn().Err(err).Str("sweep", sweep.Name).Msg("Environment detection failed for discovery sweep; using default scanner")
+		scanner = pkgdiscovery.NewScanner()
+	}
+
+	sweepCtx, cancel := context.WithTimeout(ctx, sweepTimeout)
+	defer cancel()
+
+	log.Info().
+		Str("sweep", sweep.Name).
+		Strs("subnets", sweep.Subnets).
+		Msg("Starting scheduled discovery sweep")
+
+	result, err := scanner.DiscoverServers(sweepCtx, strings.Join(sweep.Subnets, ","))
+	if result == nil {
+		log.Warn().Err(err).Str("sweep", sweep.Name).Msg("Discovery sweep failed")
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("sweep", sweep.Name).Int("servers", len(result.Servers)).Msg("Discovery sweep incomplete")
+	}
+
+	o.Observe(ctx, sweep.Name, scanned, result.Servers, err == nil)
+	log.Info().
+		Str("sweep", sweep.Name).
+		Int("servers", len(result.Servers)).
+		Msg("Discovery sweep completed")
+}
+
+func (o *Onboarder) broadcast(eventType string, data interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.broadcastLocked(eventType, data)
+}
+
+func (o *Onboarder) broadcastLocked(eventType string, data interface{}) {
+	if o.wsHub == nil {
+		return
+	}
+	o.wsHub.Broadcast(websocket.Message{Type: eventType, Data: data})
+}
+
+// CandidateID identifies a discovered server by product type, address and port
+func CandidateID(server pkgdiscovery.DiscoveredServer) string {
+	return fmt.Sprintf("%s-%s-%d", server.Type, server.IP, server.Port)
+}
+
+// coveredBy reports whether ip lies within one of the scanned subnets; nil covers everything
+func coveredBy(scanned []*net.IPNet, ip string) bool {
+	if scanned == nil {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, network := range scanned {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
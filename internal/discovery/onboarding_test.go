@@ -0,0 +1,213 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	pkgdiscovery "github.com/RouXx67/PulseUp/pkg/discovery"
+)
+
+func newTestOnboarder(mode string) (*Onboarder, *[]string) {
+	onboarder := NewOnboarder(nil, nil)
+	onboarder.SetConfig(config.DiscoveryOnboardingConfig{
+		Enabled:           true,
+		Mode:              mode,
+		Allowlist:         []string{"10.0.0.0/24"},
+		Profiles:          []config.DiscoveryCredentialProfile{{Type: "pve", TokenName: "pulse@pve!pulse", TokenValue: "secret", VerifySSL: true}},
+		MissingAfterScans: 2,
+	})
+
+	var added []string
+	onboarder.SetHandlers(
+		func(ctx context.Context, server pkgdiscovery.DiscoveredServer, profile config.DiscoveryCredentialProfile, approvedBy string) (string, error) {
+			if server.IP == "10.0.0.99" {
+				return "", errors.New("connection refused")
+			}
+			added = append(added, server.IP+"/"+approvedBy)
+			return "node-" + server.IP, nil
+		},
+		func(server pkgdiscovery.DiscoveredServer) bool { return server.IP == "10.0.0.1" },
+	)
+	return onboarder, &added
+}
+
+func TestOnboarderAutoMode(t *testing.T) {
+	onboarder, added := newTestOnboarder(config.DiscoveryOnboardingModeAuto)
+	servers := []pkgdiscovery.DiscoveredServer{
+		{IP: "10.0.0.1", Port: 8006, Type: "pve"},  // already configured
+		{IP: "10.0.0.2", Port: 8006, Type: "pve"},  // onboarded
+		{IP: "10.0.0.99", Port: 8006, Type: "pve"}, // fails validation
+		{IP: "10.0.0.3", Port: 8007, Type: "pbs"},  // no profile
+		{IP: "10.1.0.2", Port: 8006, Type: "pve"},  // outside allowlist
+	}
+	onboarder.Observe(context.Background(), BackgroundScanSource, nil, servers, true)
+	onboarder.Observe(context.Background(), BackgroundScanSource, nil, servers, true)
+
+	if len(*added) != 1 || (*added)[0] != "10.0.0.2/" {
+		t.Fatalf("added = %v, want only 10.0.0.2 added once", *added)
+	}
+
+	candidates := onboarder.Candidates()
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(candidates))
+	}
+	for _, candidate := range candidates {
+		switch candidate.Server.IP {
+		case "10.0.0.2":
+			if candidate.Status != CandidateAdded || candidate.NodeName != "node-10.0.0.2" {
+				t.Errorf("unexpected candidate %+v", candidate)
+			}
+		case "10.0.0.99":
+			if candidate.Status != CandidateFailed || candidate.Error == "" {
+				t.Errorf("unexpected candidate %+v", candidate)
+			}
+		}
+	}
+}
+
+func TestOnboarderApproveMode(t *testing.T) {
+	onboarder, added := newTestOnboarder(config.DiscoveryOnboardingModeApprove)
+	server := pkgdiscovery.DiscoveredServer{IP: "10.0.0.5", Port: 8006, Type: "pve"}
+	onboarder.Observe(context.Background(), "lab", nil, []pkgdiscovery.DiscoveredServer{server}, true)
+
+	if len(*added) != 0 {
+		t.Fatalf("approve mode added %v without approval", *added)
+	}
+	candidates := onboarder.Candidates()
+	if len(candidates) != 1 || candidates[0].Status != CandidatePending || candidates[0].Source != "lab" {
+		t.Fatalf("unexpected candidates %+v", candidates)
+	}
+
+	candidate, err := onboarder.Approve(context.Background(), CandidateID(server), "admin")
+	if err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if candidate.Status != CandidateAdded || len(*added) != 1 || (*added)[0] != "10.0.0.5/admin" {
+		t.Fatalf("unexpected approval result %+v, added %v", candidate, *added)
+	}
+	if _, err := onboarder.Approve(context.Background(), CandidateID(server), "admin"); err == nil {
+		t.Fatal("expected approving an added candidate to fail")
+	}
+	if _, err := onboarder.Reject("pve-10.0.0.6-8006"); err == nil {
+		t.Fatal("expected rejecting an unknown candidate to fail")
+	}
+}
+
+func TestOnboarderDoesNotAddCandidateTwice(t *testing.T) {
+	onboarder, _ := newTestOnboarder(config.DiscoveryOnboardingModeApprove)
+	server := pkgdiscovery.DiscoveredServer{IP: "10.0.0.7", Port: 8006, Type: "pve"}
+	onboarder.Observe(context.Background(), "lab", nil, []pkgdiscovery.DiscoveredServer{server}, true)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	onboarder.SetHandlers(
+		func(ctx context.Context, server pkgdiscovery.DiscoveredServer, profile config.DiscoveryCredentialProfile, approvedBy string) (string, error) {
+			calls++
+			close(started)
+			<-release
+			return "node-" + server.IP, nil
+		},
+		nil,
+	)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := onboarder.Approve(context.Background(), CandidateID(server), "admin")
+		done <- err
+	}()
+	<-started
+
+	if candidate, err := onboarder.Approve(context.Background(), CandidateID(server), "other"); err == nil || candidate.Status != CandidateOnboarding {
+		t.Fatalf("expected a concurrent approval to be refused, got %+v (%v)", candidate, err)
+	}
+	if _, err := onboarder.Reject(CandidateID(server)); err == nil {
+		t.Fatal("expected rejecting a candidate being onboarded to fail")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one onboarding call, got %d", calls)
+	}
+}
+
+func TestOnboarderReportsMissingServers(t *testing.T) {
+	onboarder, _ := newTestOnboarder(config.DiscoveryOnboardingModeApprove)
+	ctx := context.Background()
+	a := pkgdiscovery.DiscoveredServer{IP: "10.0.0.5", Port: 8006, Type: "pve"}
+	b := pkgdiscovery.DiscoveredServer{IP: "10.0.1.5", Port: 8007, Type: "pbs"}
+
+	onboarder.Observe(ctx, BackgroundScanSource, nil, []pkgdiscovery.DiscoveredServer{a, b}, true)
+
+	// Incomplete scans and sweeps of other subnets do not count as misses
+	onboarder.Observe(ctx, BackgroundScanSource, nil, nil, false)
+	_, lab, _ := net.ParseCIDR("10.0.1.0/24")
+	onboarder.Observe(ctx, "lab", []*net.IPNet{lab}, []pkgdiscovery.DiscoveredServer{b}, true)
+	onboarder.Observe(ctx, "lab", []*net.IPNet{lab}, []pkgdiscovery.DiscoveredServer{b}, true)
+	if missing := onboarder.Missing(); len(missing) != 0 {
+		t.Fatalf("expected no missing servers yet, got %+v", missing)
+	}
+
+	onboarder.Observe(ctx, BackgroundScanSource, nil, []pkgdiscovery.DiscoveredServer{b}, true)
+	onboarder.Observe(ctx, BackgroundScanSource, nil, []pkgdiscovery.DiscoveredServer{b}, true)
+	missing := onboarder.Missing()
+	if len(missing) != 1 || missing[0].Server.IP != a.IP || missing[0].MissedScans != 2 {
+		t.Fatalf("expected %s to be reported missing, got %+v", a.IP, missing)
+	}
+
+	onboarder.Observe(ctx, BackgroundScanSource, nil, []pkgdiscovery.DiscoveredServer{a, b}, true)
+	if missing := onboarder.Missing(); len(missing) != 0 {
+		t.Fatalf("expected returning server to clear the report, got %+v", missing)
+	}
+}
+
+func TestOnboarderIgnoresHostileHostnames(t *testing.T) {
+	onboarder, added := newTestOnboarder(config.DiscoveryOnboardingModeAuto)
+	cfg := onboarder.Config()
+	cfg.Allowlist = []string{"pve-*.lan"}
+	onboarder.SetConfig(cfg)
+	onboarder.lookupHost = func(hostname string) ([]string, error) {
+		if hostname == "pve-1.lan" {
+			return []string{"172.16.0.5"}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	servers := []pkgdiscovery.DiscoveredServer{
+		{IP: "172.16.0.5", Port: 8006, Type: "pve", Hostname: "pve-1.lan"},
+		// A rogue host reporting a matching name must not receive the profile token
+		{IP: "172.16.0.66", Port: 8006, Type: "pve", Hostname: "pve-1.lan"},
+		{IP: "172.16.0.67", Port: 8006, Type: "pve", Hostname: "pve-rogue.lan"},
+	}
+	onboarder.Observe(context.Background(), BackgroundScanSource, nil, servers, true)
+
+	if len(*added) != 1 || (*added)[0] != "172.16.0.5/" {
+		t.Fatalf("added = %v, want only the forward-confirmed host", *added)
+	}
+	if candidates := onboarder.Candidates(); len(candidates) != 1 {
+		t.Fatalf("expected only the confirmed host as a candidate, got %+v", candidates)
+	}
+}
+
+func TestOnboarderAutoModeRequiresVerifiedTLS(t *testing.T) {
+	onboarder, added := newTestOnboarder(config.DiscoveryOnboardingModeAuto)
+	cfg := onboarder.Config()
+	cfg.Profiles[0].VerifySSL = false
+	onboarder.SetConfig(cfg)
+
+	server := pkgdiscovery.DiscoveredServer{IP: "10.0.0.8", Port: 8006, Type: "pve"}
+	onboarder.Observe(context.Background(), BackgroundScanSource, nil, []pkgdiscovery.DiscoveredServer{server}, true)
+
+	if len(*added) != 0 {
+		t.Fatalf("auto mode sent the token without TLS verification: %v", *added)
+	}
+	if candidates := onboarder.Candidates(); len(candidates) != 1 || candidates[0].Status != CandidatePending {
+		t.Fatalf("expected the server to wait for approval, got %+v", candidates)
+	}
+}
@@ -25,6 +25,7 @@ type Service struct {
 	stopChan   chan struct{}
 	ctx        context.Context
 	cfgProvider func() config.DiscoveryConfig
+	onboarder   *Onboarder
 }
 
 // DiscoveryCache stores the latest discovery results
@@ -218,6 +219,13 @@ func (s *Service) performScan() {
 		s.cache.updated = time.Now()
 		s.cache.mu.Unlock()
 
+		s.mu.RLock()
+		onboarder := s.onboarder
+		s.mu.RUnlock()
+		if onboarder != nil {
+			onboarder.Observe(s.ctx, BackgroundScanSource, nil, result.Servers, err == nil)
+		}
+
 		if result.Environment != nil {
 			log.Info().
 				Str("environment", result.Environment.Type).
@@ -278,6 +286,13 @@ func (s *Service) ForceRefresh() {
 	go s.performScan()
 }
 
+// SetOnboarder routes scan results to the onboarder
+func (s *Service) SetOnboarder(onboarder *Onboarder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onboarder = onboarder
+}
+
 // SetInterval updates the scan interval
 func (s *Service) SetInterval(interval time.Duration) {
 	s.mu.Lock()
@@ -0,0 +1,184 @@
+package federation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/pkg/tlsutil"
+)
+
+const (
+	// StreamPath is the downstream endpoint streaming one Envelope per line
+	StreamPath = "/api/federation/stream"
+	// SnapshotPath is the downstream endpoint returning a single Envelope
+	SnapshotPath = "/api/federation/snapshot"
+	// AckPath is the downstream endpoint acknowledging an alert on behalf of the central instance
+	AckPath = "/api/federation/ack"
+
+	maxEnvelopeSize = 64 << 20
+)
+
+// Envelope is a downstream state snapshot as sent to the central instance
+type Envelope struct {
+	PulseVersion string               `json:"pulseVersion,omitempty"`
+	Hostname     string               `json:"hostname,omitempty"`
+	GeneratedAt  time.Time            `json:"generatedAt"`
+	State        models.StateSnapshot `json:"state"`
+}
+
+// AckRequest acknowledges or unacknowledges a downstream alert
+type AckRequest struct {
+	AlertID      string `json:"alertId"`
+	Acknowledged bool   `json:"acknowledged"`
+	User         string `json:"user,omitempty"`
+}
+
+// Client talks to one downstream Pulse instance
+type Client struct {
+	baseURL string
+	token   string
+	stream  *http.Client // No overall timeout; the stream stays open
+	http    *http.Client
+}
+
+// NewClient creates a client for the downstream instance at baseURL using an API token
+func NewClient(baseURL, token string, verifySSL bool, fingerprint string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		stream:  tlsutil.CreateHTTPClientWithTimeout(verifySSL, fingerprint, 0),
+		http:    tlsutil.CreateHTTPClientWithTimeout(verifySSL, fingerprint, 30*time.Second),
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-API-Token", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// Stream receives snapshots every interval and passes each to handle until the context is
+// cancelled or the connection fails. It always returns a non-nil error.
+func (c *Client) Stream(ctx context.Context, interval time.Duration, handle func(Envelope)) error {
+	query := url.Values{"interval": {strconv.Itoa(int(interval / time.Second))}}
+	req, err := c.newRequest(ctx, http.MethodGet, StreamPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.stream.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReaderSize(resp.Body, 64*1024)
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("stream closed by downstream instance")
+			}
+			return err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var envelope Envelope
+		if err := json.Unmarshal(line, &envelope); err != nil {
+			return fmt.Errorf("invalid snapshot: %w", err)
+		}
+		handle(envelope)
+	}
+}
+
+// readLine reads one newline-terminated message of at most maxEnvelopeSize bytes
+func readLine(reader *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxEnvelopeSize {
+			return nil, fmt.Errorf("snapshot exceeds %d bytes", maxEnvelopeSize)
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// Snapshot fetches a single snapshot
+func (c *Client) Snapshot(ctx context.Context) (*Envelope, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, SnapshotPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	var envelope Envelope
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxEnvelopeSize)).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	return &envelope, nil
+}
+
+// Acknowledge forwards an acknowledgement of a downstream alert
+func (c *Client) Acknowledge(ctx context.Context, ack AckRequest) error {
+	body, err := json.Marshal(ack)
+	if err != nil {
+		return err
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, AckPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	message := string(bytes.TrimSpace(body))
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("downstream instance rejected the API token")
+	case http.StatusForbidden:
+		return fmt.Errorf("API token is not allowed to use federation: %s", message)
+	default:
+		return fmt.Errorf("downstream instance returned %s: %s", resp.Status, message)
+	}
+}
@@ -0,0 +1,109 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func siteSnapshot() models.StateSnapshot {
+	return models.StateSnapshot{
+		Nodes:            []models.Node{{ID: "pve-node1", Instance: "pve"}},
+		VMs:              []models.VM{{ID: "pve-node1-100", VMID: 100, Instance: "pve"}},
+		ActiveAlerts:     []models.Alert{{ID: "pve-node1-100-cpu", ResourceID: "pve-node1-100", Instance: "pve"}},
+		ConnectionHealth: map[string]bool{"pve": true},
+		LastUpdate:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNamespaceAndMerge(t *testing.T) {
+	local := models.StateSnapshot{
+		Nodes:            []models.Node{{ID: "pve-node1", Instance: "pve"}},
+		ConnectionHealth: map[string]bool{"pve": true},
+	}
+	original := siteSnapshot()
+	berlin := Namespace("berlin", original)
+
+	if berlin.VMs[0].ID != "berlin/pve-node1-100" || berlin.VMs[0].Instance != "berlin/pve" {
+		t.Fatalf("VM not namespaced: %+v", berlin.VMs[0])
+	}
+	if original.VMs[0].ID != "pve-node1-100" {
+		t.Fatal("Namespace modified the downstream snapshot")
+	}
+	site, id, ok := Split(berlin.ActiveAlerts[0].ID)
+	if !ok || site != "berlin" || id != "pve-node1-100-cpu" {
+		t.Fatalf("Split(%q) = %q, %q, %t", berlin.ActiveAlerts[0].ID, site, id, ok)
+	}
+
+	merged := Merge(local,
+		map[string]models.StateSnapshot{"berlin": berlin, "paris": Namespace("paris", siteSnapshot())},
+		map[string]bool{"paris": true})
+
+	// Identical downstream IDs no longer collide with each other or with local resources
+	if len(merged.Nodes) != 3 || len(merged.VMs) != 2 || len(merged.ActiveAlerts) != 2 {
+		t.Fatalf("unexpected merged counts: %d nodes, %d VMs, %d alerts", len(merged.Nodes), len(merged.VMs), len(merged.ActiveAlerts))
+	}
+	if !merged.ConnectionHealth["pve"] || !merged.ConnectionHealth["berlin/pve"] || merged.ConnectionHealth["paris/pve"] {
+		t.Fatalf("stale site should be reported disconnected: %+v", merged.ConnectionHealth)
+	}
+	if len(local.Nodes) != 1 {
+		t.Fatal("Merge modified the local snapshot")
+	}
+	if !merged.LastUpdate.Equal(original.LastUpdate) {
+		t.Fatalf("LastUpdate = %v", merged.LastUpdate)
+	}
+}
+
+func TestClientStreamAndAcknowledge(t *testing.T) {
+	var acked AckRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc(StreamPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Token") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("interval") != "10" {
+			t.Errorf("interval = %q", r.URL.Query().Get("interval"))
+		}
+		encoder := json.NewEncoder(w)
+		for i := 0; i < 2; i++ {
+			encoder.Encode(Envelope{PulseVersion: "v4.99.0", State: siteSnapshot()})
+			w.(http.Flusher).Flush()
+		}
+	})
+	mux.HandleFunc(AckPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&acked)
+		w.Write([]byte(`{"success":true}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret", true, "")
+	received := 0
+	err := client.Stream(context.Background(), 10*time.Second, func(envelope Envelope) {
+		received++
+		if envelope.PulseVersion != "v4.99.0" || len(envelope.State.VMs) != 1 {
+			t.Errorf("unexpected envelope: %+v", envelope)
+		}
+	})
+	if received != 2 || err == nil {
+		t.Fatalf("received %d snapshots, err %v", received, err)
+	}
+
+	if err := client.Acknowledge(context.Background(), AckRequest{AlertID: "a1", Acknowledged: true, User: "admin"}); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	if acked.AlertID != "a1" || !acked.Acknowledged || acked.User != "admin" {
+		t.Fatalf("unexpected ack: %+v", acked)
+	}
+
+	unauthorized := NewClient(server.URL, "wrong", true, "")
+	if err := unauthorized.Stream(context.Background(), 10*time.Second, func(Envelope) {}); err == nil {
+		t.Fatal("expected the stream to be rejected")
+	}
+}
@@ -0,0 +1,250 @@
+// Package federation lets a central Pulse aggregate the state of downstream Pulse instances.
+package federation
+
+import (
+	"strings"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+// Separator joins a site ID to the IDs and instance names of its resources
+const Separator = "/"
+
+// Qualify prefixes a downstream ID or instance name with its site
+func Qualify(site, id string) string {
+	if id == "" {
+		return ""
+	}
+	return site + Separator + id
+}
+
+// Split returns the site and downstream ID of a qualified ID
+func Split(qualified string) (site, id string, ok bool) {
+	site, id, ok = strings.Cut(qualified, Separator)
+	if !ok || site == "" || id == "" {
+		return "", "", false
+	}
+	return site, id, true
+}
+
+// Namespace qualifies every ID and instance name in a downstream snapshot with its site so that
+// resources of different sites never collide in the global view
+func Namespace(site string, snapshot models.StateSnapshot) models.StateSnapshot {
+	q := func(id string) string { return Qualify(site, id) }
+
+	out := snapshot
+	out.Nodes = make([]models.Node, len(snapshot.Nodes))
+	for i, node := range snapshot.Nodes {
+		node.ID, node.Instance = q(node.ID), q(node.Instance)
+		out.Nodes[i] = node
+	}
+	out.VMs = make([]models.VM, len(snapshot.VMs))
+	for i, vm := range snapshot.VMs {
+		vm.ID, vm.Instance = q(vm.ID), q(vm.Instance)
+		out.VMs[i] = vm
+	}
+	out.Containers = make([]models.Container, len(snapshot.Containers))
+	for i, ct := range snapshot.Containers {
+		ct.ID, ct.Instance = q(ct.ID), q(ct.Instance)
+		out.Containers[i] = ct
+	}
+	out.DockerHosts = make([]models.DockerHost, len(snapshot.DockerHosts))
+	for i, host := range snapshot.DockerHosts {
+		host.ID = q(host.ID)
+		out.DockerHosts[i] = host
+	}
+	out.Storage = make([]models.Storage, len(snapshot.Storage))
+	for i, storage := range snapshot.Storage {
+		storage.ID, storage.Instance = q(storage.ID), q(storage.Instance)
+		out.Storage[i] = storage
+	}
+	out.CephClusters = make([]models.CephCluster, len(snapshot.CephClusters))
+	for i, cluster := range snapshot.CephClusters {
+		cluster.ID, cluster.Instance = q(cluster.ID), q(cluster.Instance)
+		out.CephClusters[i] = cluster
+	}
+	out.PhysicalDisks = make([]models.PhysicalDisk, len(snapshot.PhysicalDisks))
+	for i, disk := range snapshot.PhysicalDisks {
+		disk.ID, disk.Instance = q(disk.ID), q(disk.Instance)
+		out.PhysicalDisks[i] = disk
+	}
+	out.PBSInstances = make([]models.PBSInstance, len(snapshot.PBSInstances))
+	for i, pbs := range snapshot.PBSInstances {
+		pbs.ID, pbs.Name = q(pbs.ID), q(pbs.Name)
+		out.PBSInstances[i] = pbs
+	}
+	out.PMGInstances = make([]models.PMGInstance, len(snapshot.PMGInstances))
+	for i, pmg := range snapshot.PMGInstances {
+		pmg.ID, pmg.Name = q(pmg.ID), q(pmg.Name)
+		out.PMGInstances[i] = pmg
+	}
+	out.ReplicationJobs = make([]models.ReplicationJob, len(snapshot.ReplicationJobs))
+	for i, job := range snapshot.ReplicationJobs {
+		job.ID, job.Instance = q(job.ID), q(job.Instance)
+		out.ReplicationJobs[i] = job
+	}
+	out.Metrics = make([]models.Metric, len(snapshot.Metrics))
+	for i, metric := range snapshot.Metrics {
+		metric.ID = q(metric.ID)
+		out.Metrics[i] = metric
+	}
+	out.ActiveAlerts = make([]models.Alert, len(snapshot.ActiveAlerts))
+	for i, alert := range snapshot.ActiveAlerts {
+		out.ActiveAlerts[i] = namespaceAlert(site, alert)
+	}
+	out.RecentlyResolved = make([]models.ResolvedAlert, len(snapshot.RecentlyResolved))
+	for i, resolved := range snapshot.RecentlyResolved {
+		resolved.Alert = namespaceAlert(site, resolved.Alert)
+		out.RecentlyResolved[i] = resolved
+	}
+	out.ClusterHA = make([]models.ClusterHAStatus, len(snapshot.ClusterHA))
+	for i, status := range snapshot.ClusterHA {
+		status.ID, status.Instance = q(status.ID), q(status.Instance)
+		out.ClusterHA[i] = status
+	}
+	out.NodeMaintenance = make([]models.NodeMaintenance, len(snapshot.NodeMaintenance))
+	for i, entry := range snapshot.NodeMaintenance {
+		entry.ID, entry.Instance = q(entry.ID), q(entry.Instance)
+		out.NodeMaintenance[i] = entry
+	}
+	out.ConnectionHealth = make(map[string]bool, len(snapshot.ConnectionHealth))
+	for key, healthy := range snapshot.ConnectionHealth {
+		out.ConnectionHealth[q(key)] = healthy
+	}
+
+	out.PVEBackups = namespacePVEBackups(site, snapshot.PVEBackups)
+	out.PBSBackups = namespacePBSBackups(site, snapshot.PBSBackups)
+	out.PMGBackups = namespacePMGBackups(site, snapshot.PMGBackups)
+	out.Backups = models.Backups{
+		PVE: namespacePVEBackups(site, snapshot.Backups.PVE),
+		PBS: namespacePBSBackups(site, snapshot.Backups.PBS),
+		PMG: namespacePMGBackups(site, snapshot.Backups.PMG),
+	}
+	out.SnapshotRetention = nil
+	return out
+}
+
+func namespaceAlert(site string, alert models.Alert) models.Alert {
+	alert.ID = Qualify(site, alert.ID)
+	alert.ResourceID = Qualify(site, alert.ResourceID)
+	alert.Instance = Qualify(site, alert.Instance)
+	return alert
+}
+
+func namespacePVEBackups(site string, backups models.PVEBackups) models.PVEBackups {
+	out := models.PVEBackups{
+		BackupTasks:    make([]models.BackupTask, len(backups.BackupTasks)),
+		StorageBackups: make([]models.StorageBackup, len(backups.StorageBackups)),
+		GuestSnapshots: make([]models.GuestSnapshot, len(backups.GuestSnapshots)),
+	}
+	for i, task := range backups.BackupTasks {
+		task.ID = Qualify(site, task.ID)
+		out.BackupTasks[i] = task
+	}
+	for i, backup := range backups.StorageBackups {
+		backup.ID, backup.Instance = Qualify(site, backup.ID), Qualify(site, backup.Instance)
+		out.StorageBackups[i] = backup
+	}
+	for i, snapshot := range backups.GuestSnapshots {
+		snapshot.ID, snapshot.Instance = Qualify(site, snapshot.ID), Qualify(site, snapshot.Instance)
+		out.GuestSnapshots[i] = snapshot
+	}
+	return out
+}
+
+func namespacePBSBackups(site string, backups []models.PBSBackup) []models.PBSBackup {
+	out := make([]models.PBSBackup, len(backups))
+	for i, backup := range backups {
+		backup.ID, backup.Instance = Qualify(site, backup.ID), Qualify(site, backup.Instance)
+		out[i] = backup
+	}
+	return out
+}
+
+func namespacePMGBackups(site string, backups []models.PMGBackup) []models.PMGBackup {
+	out := make([]models.PMGBackup, len(backups))
+	for i, backup := range backups {
+		backup.ID, backup.Instance = Qualify(site, backup.ID), Qualify(site, backup.Instance)
+		out[i] = backup
+	}
+	return out
+}
+
+// Merge adds namespaced site snapshots to the local state. Stale sites are reported as
+// disconnected in ConnectionHealth while their last known resources stay visible.
+func Merge(local models.StateSnapshot, sites map[string]models.StateSnapshot, stale map[string]bool) models.StateSnapshot {
+	merged := local
+	merged.Nodes = append([]models.Node{}, local.Nodes...)
+	merged.VMs = append([]models.VM{}, local.VMs...)
+	merged.Containers = append([]models.Container{}, local.Containers...)
+	merged.DockerHosts = append([]models.DockerHost{}, local.DockerHosts...)
+	merged.Storage = append([]models.Storage{}, local.Storage...)
+	merged.CephClusters = append([]models.CephCluster{}, local.CephClusters...)
+	merged.PhysicalDisks = append([]models.PhysicalDisk{}, local.PhysicalDisks...)
+	merged.PBSInstances = append([]models.PBSInstance{}, local.PBSInstances...)
+	merged.PMGInstances = append([]models.PMGInstance{}, local.PMGInstances...)
+	merged.PBSBackups = append([]models.PBSBackup{}, local.PBSBackups...)
+	merged.PMGBackups = append([]models.PMGBackup{}, local.PMGBackups...)
+	merged.ReplicationJobs = append([]models.ReplicationJob{}, local.ReplicationJobs...)
+	merged.Metrics = append([]models.Metric{}, local.Metrics...)
+	merged.ActiveAlerts = append([]models.Alert{}, local.ActiveAlerts...)
+	merged.RecentlyResolved = append([]models.ResolvedAlert{}, local.RecentlyResolved...)
+	merged.ClusterHA = append([]models.ClusterHAStatus{}, local.ClusterHA...)
+	merged.NodeMaintenance = append([]models.NodeMaintenance{}, local.NodeMaintenance...)
+	merged.PVEBackups = appendPVEBackups(emptyPVEBackups(), local.PVEBackups)
+	merged.Backups = models.Backups{
+		PVE: appendPVEBackups(emptyPVEBackups(), local.Backups.PVE),
+		PBS: append([]models.PBSBackup{}, local.Backups.PBS...),
+		PMG: append([]models.PMGBackup{}, local.Backups.PMG...),
+	}
+	merged.ConnectionHealth = make(map[string]bool, len(local.ConnectionHealth))
+	for key, healthy := range local.ConnectionHealth {
+		merged.ConnectionHealth[key] = healthy
+	}
+
+	for site, snapshot := range sites {
+		merged.Nodes = append(merged.Nodes, snapshot.Nodes...)
+		merged.VMs = append(merged.VMs, snapshot.VMs...)
+		merged.Containers = append(merged.Containers, snapshot.Containers...)
+		merged.DockerHosts = append(merged.DockerHosts, snapshot.DockerHosts...)
+		merged.Storage = append(merged.Storage, snapshot.Storage...)
+		merged.CephClusters = append(merged.CephClusters, snapshot.CephClusters...)
+		merged.PhysicalDisks = append(merged.PhysicalDisks, snapshot.PhysicalDisks...)
+		merged.PBSInstances = append(merged.PBSInstances, snapshot.PBSInstances...)
+		merged.PMGInstances = append(merged.PMGInstances, snapshot.PMGInstances...)
+		merged.PBSBackups = append(merged.PBSBackups, snapshot.PBSBackups...)
+		merged.PMGBackups = append(merged.PMGBackups, snapshot.PMGBackups...)
+		merged.ReplicationJobs = append(merged.ReplicationJobs, snapshot.ReplicationJobs...)
+		merged.Metrics = append(merged.Metrics, snapshot.Metrics...)
+		merged.ActiveAlerts = append(merged.ActiveAlerts, snapshot.ActiveAlerts...)
+		merged.RecentlyResolved = append(merged.RecentlyResolved, snapshot.RecentlyResolved...)
+		merged.ClusterHA = append(merged.ClusterHA, snapshot.ClusterHA...)
+		merged.NodeMaintenance = append(merged.NodeMaintenance, snapshot.NodeMaintenance...)
+		merged.PVEBackups = appendPVEBackups(merged.PVEBackups, snapshot.PVEBackups)
+		merged.Backups.PVE = appendPVEBackups(merged.Backups.PVE, snapshot.Backups.PVE)
+		merged.Backups.PBS = append(merged.Backups.PBS, snapshot.Backups.PBS...)
+		merged.Backups.PMG = append(merged.Backups.PMG, snapshot.Backups.PMG...)
+		for key, healthy := range snapshot.ConnectionHealth {
+			merged.ConnectionHealth[key] = healthy && !stale[site]
+		}
+		if snapshot.LastUpdate.After(merged.LastUpdate) {
+			merged.LastUpdate = snapshot.LastUpdate
+		}
+	}
+	return merged
+}
+
+func emptyPVEBackups() models.PVEBackups {
+	return models.PVEBackups{
+		BackupTasks:    []models.BackupTask{},
+		StorageBackups: []models.StorageBackup{},
+		GuestSnapshots: []models.GuestSnapshot{},
+	}
+}
+
+func appendPVEBackups(dst, src models.PVEBackups) models.PVEBackups {
+	dst.BackupTasks = append(dst.BackupTasks, src.BackupTasks...)
+	dst.StorageBackups = append(dst.StorageBackups, src.StorageBackups...)
+	dst.GuestSnapshots = append(dst.GuestSnapshots, src.GuestSnapshots...)
+	return dst
+}
@@ -0,0 +1,249 @@
+// Package forecast fits usage trends and projects when a resource runs out of capacity.
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/types"
+)
+
+// Models used for a projection
+const (
+	ModelLinear   = "linear"
+	ModelSeasonal = "seasonal"
+)
+
+const (
+	// MinSamples is the fewest points a trend is fitted on
+	MinSamples = 12
+	// MinSpan is the shortest history a trend is fitted on
+	MinSpan = 6 * time.Hour
+	// fullConfidenceSpan is the history length from which confidence is no longer discounted
+	fullConfidenceSpan = 7 * 24 * time.Hour
+	// seasonalBuckets splits the seasonal period into phases
+	seasonalBuckets = 24
+)
+
+// Options tune a projection
+type Options struct {
+	// Capacity is the value at which the resource is exhausted, e.g. 100 for percentages
+	Capacity float64
+	// Period enables the seasonal model for usage with a repeating pattern, usually a day.
+	// The seasonal model is only tried when the history covers at least two periods.
+	Period time.Duration
+}
+
+// Projection is the fitted trend of a series and the projected exhaustion time
+type Projection struct {
+	Model        string     `json:"model"`
+	Current      float64    `json:"current"`      // Last observed value
+	GrowthPerDay float64    `json:"growthPerDay"` // Trend slope in units per day
+	SeasonalPeak float64    `json:"seasonalPeak,omitempty"`
+	ExhaustionAt *time.Time `json:"exhaustionAt,omitempty"`
+	Confidence   float64    `json:"confidence"` // 0-1, goodness of fit discounted for short histories
+	Samples      int        `json:"samples"`
+}
+
+// DaysUntilExhaustion returns the days from now until the projected exhaustion, or -1 when
+// the resource is not projected to run out
+func (p Projection) DaysUntilExhaustion(now time.Time) float64 {
+	if p.ExhaustionAt == nil {
+		return -1
+	}
+	days := p.ExhaustionAt.Sub(now).Hours() / 24
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// Project fits the points and projects when they reach opts.Capacity. It returns false when
+// the history is too short to fit a trend.
+func Project(points []types.MetricPoint, opts Options, now time.Time) (Projection, bool) {
+	points = sortedPoints(points)
+	if len(points) < MinSamples || points[len(points)-1].Timestamp.Sub(points[0].Timestamp) < MinSpan {
+		return Projection{}, false
+	}
+
+	origin := points[0].Timestamp
+	fit := fitLinear(points, origin)
+	projection := Projection{
+		Model:        ModelLinear,
+		Current:      points[len(points)-1].Value,
+		GrowthPerDay: fit.slope * 24,
+		Samples:      len(points),
+	}
+	r2 := adjustedRSquared(fit.r2, len(points), 1)
+
+	span := points[len(points)-1].Timestamp.Sub(origin)
+	if opts.Period > 0 && span >= 2*opts.Period {
+		if seasonal, ok := fitSeasonal(points, origin, opts.Period); ok {
+			// The seasonal profile adds a parameter per bucket, so it must explain
+			// noticeably more than the plain trend to be chosen
+			if adjusted := adjustedRSquared(seasonal.r2, len(points), 1+seasonalBuckets); adjusted > r2 {
+				fit = seasonal.trend
+				projection.Model = ModelSeasonal
+				projection.GrowthPerDay = fit.slope * 24
+				projection.SeasonalPeak = seasonal.peak
+				r2 = adjusted
+			}
+		}
+	}
+
+	projection.Confidence = math.Max(0, math.Min(1, r2*math.Min(1, float64(span)/float64(fullConfidenceSpan))))
+	projection.ExhaustionAt = exhaustion(fit, projection.SeasonalPeak, origin, opts.Capacity, projection.Current, now)
+	return projection, true
+}
+
+// exhaustion solves trend(t) + peak = capacity for the first time at or after now
+func exhaustion(fit linearFit, peak float64, origin time.Time, capacity, current float64, now time.Time) *time.Time {
+	if current >= capacity {
+		return &now
+	}
+	if fit.slope <= 0 {
+		return nil
+	}
+	hours := (capacity - peak - fit.intercept) / fit.slope
+	at := origin.Add(time.Duration(hours * float64(time.Hour)))
+	if at.Before(now) {
+		at = now
+	}
+	return &at
+}
+
+type linearFit struct {
+	slope     float64 // Units per hour
+	intercept float64 // Value at the origin
+	r2        float64
+}
+
+func (f linearFit) at(hours float64) float64 {
+	return f.intercept + f.slope*hours
+}
+
+// fitLinear fits an ordinary least squares line over hours since origin
+func fitLinear(points []types.MetricPoint, origin time.Time) linearFit {
+	n := float64(len(points))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, point := range points {
+		x := point.Timestamp.Sub(origin).Hours()
+		sumX += x
+		sumY += point.Value
+		sumXY += x * point.Value
+		sumXX += x * x
+	}
+
+	fit := linearFit{intercept: sumY / n}
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		fit.slope = (n*sumXY - sumX*sumY) / denominator
+		fit.intercept = (sumY - fit.slope*sumX) / n
+	}
+
+	residuals := make([]float64, len(points))
+	for i, point := range points {
+		residuals[i] = point.Value - fit.at(point.Timestamp.Sub(origin).Hours())
+	}
+	fit.r2 = rSquared(points, residuals)
+	return fit
+}
+
+type seasonalFit struct {
+	trend linearFit
+	r2    float64
+	peak  float64 // Largest seasonal offset above the trend
+}
+
+// fitSeasonal models usage as a linear trend plus a repeating profile over the period. Trend
+// and profile are fitted jointly: the slope comes from the values centred within each phase
+// bucket, so a periodic pattern does not bias it.
+func fitSeasonal(points []types.MetricPoint, origin time.Time, period time.Duration) (seasonalFit, bool) {
+	bucketOf := func(ts time.Time) int {
+		phase := ts.Sub(origin) % period
+		return int(float64(phase) / float64(period) * seasonalBuckets)
+	}
+
+	var sumX, sumY, counts [seasonalBuckets]float64
+	for _, point := range points {
+		bucket := bucketOf(point.Timestamp)
+		sumX[bucket] += point.Timestamp.Sub(origin).Hours()
+		sumY[bucket] += point.Value
+		counts[bucket]++
+	}
+	for _, count := range counts {
+		if count == 0 {
+			return seasonalFit{}, false
+		}
+	}
+
+	var covariance, variance float64
+	for _, point := range points {
+		bucket := bucketOf(point.Timestamp)
+		x := point.Timestamp.Sub(origin).Hours() - sumX[bucket]/counts[bucket]
+		covariance += x * (point.Value - sumY[bucket]/counts[bucket])
+		variance += x * x
+	}
+	var trend linearFit
+	if variance != 0 {
+		trend.slope = covariance / variance
+	}
+
+	// Each bucket's level is trend intercept plus its seasonal offset
+	var profile [seasonalBuckets]float64
+	for i := range profile {
+		profile[i] = sumY[i]/counts[i] - trend.slope*sumX[i]/counts[i]
+		trend.intercept += profile[i] / seasonalBuckets
+	}
+	peak := math.Inf(-1)
+	for i := range profile {
+		profile[i] -= trend.intercept
+		peak = math.Max(peak, profile[i])
+	}
+
+	residuals := make([]float64, len(points))
+	for i, point := range points {
+		residuals[i] = point.Value - trend.at(point.Timestamp.Sub(origin).Hours()) - profile[bucketOf(point.Timestamp)]
+	}
+	return seasonalFit{trend: trend, r2: rSquared(points, residuals), peak: math.Max(0, peak)}, true
+}
+
+// rSquared is the coefficient of determination of a model with the given residuals. A flat
+// series that the model reproduces exactly counts as a perfect fit.
+func rSquared(points []types.MetricPoint, residuals []float64) float64 {
+	var mean float64
+	for _, point := range points {
+		mean += point.Value
+	}
+	mean /= float64(len(points))
+
+	var total, residual float64
+	for i, point := range points {
+		total += (point.Value - mean) * (point.Value - mean)
+		residual += residuals[i] * residuals[i]
+	}
+	if total == 0 {
+		if residual == 0 {
+			return 1
+		}
+		return 0
+	}
+	return math.Max(0, 1-residual/total)
+}
+
+// adjustedRSquared penalises r2 for the number of model parameters
+func adjustedRSquared(r2 float64, samples, parameters int) float64 {
+	if samples <= parameters+1 {
+		return 0
+	}
+	return 1 - (1-r2)*float64(samples-1)/float64(samples-parameters-1)
+}
+
+func sortedPoints(points []types.MetricPoint) []types.MetricPoint {
+	if sort.SliceIsSorted(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) }) {
+		return points
+	}
+	sorted := append([]types.MetricPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+	return sorted
+}
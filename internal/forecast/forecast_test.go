@@ -0,0 +1,102 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/types"
+)
+
+func series(start time.Time, count int, step time.Duration, value func(hours float64) float64) []types.MetricPoint {
+	points := make([]types.MetricPoint, count)
+	for i := range points {
+		ts := start.Add(time.Duration(i) * step)
+		points[i] = types.MetricPoint{Timestamp: ts, Value: value(ts.Sub(start).Hours())}
+	}
+	return points
+}
+
+func TestProjectLinearGrowth(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	// 50% growing one point per day, sampled hourly for ten days
+	points := series(start, 240, time.Hour, func(hours float64) float64 { return 50 + hours/24 })
+	now := points[len(points)-1].Timestamp
+
+	projection, ok := Project(points, Options{Capacity: 100, Period: 24 * time.Hour}, now)
+	if !ok {
+		t.Fatal("expected a projection")
+	}
+	if projection.Model != ModelLinear {
+		t.Errorf("expected linear model, got %s", projection.Model)
+	}
+	if math.Abs(projection.GrowthPerDay-1) > 0.01 {
+		t.Errorf("expected growth of 1/day, got %f", projection.GrowthPerDay)
+	}
+	// 59.96% used, so full in about 40 days
+	if days := projection.DaysUntilExhaustion(now); math.Abs(days-40.04) > 0.1 {
+		t.Errorf("expected exhaustion in ~40 days, got %f", days)
+	}
+	if projection.Confidence < 0.99 {
+		t.Errorf("expected high confidence for an exact trend over 10 days, got %f", projection.Confidence)
+	}
+}
+
+func TestProjectSeasonalUsesPeak(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	// Daily swing of +-10 points around a trend growing one point per day
+	points := series(start, 240, time.Hour, func(hours float64) float64 {
+		return 50 + hours/24 + 10*math.Sin(2*math.Pi*hours/24)
+	})
+	now := points[len(points)-1].Timestamp
+
+	projection, ok := Project(points, Options{Capacity: 100, Period: 24 * time.Hour}, now)
+	if !ok {
+		t.Fatal("expected a projection")
+	}
+	if projection.Model != ModelSeasonal {
+		t.Fatalf("expected seasonal model, got %s", projection.Model)
+	}
+	if projection.SeasonalPeak < 9 || projection.SeasonalPeak > 10.5 {
+		t.Errorf("expected seasonal peak near 10, got %f", projection.SeasonalPeak)
+	}
+	// The daily peak reaches capacity about ten days before the trend does
+	if days := projection.DaysUntilExhaustion(now); days < 28 || days > 32 {
+		t.Errorf("expected exhaustion in ~30 days, got %f", days)
+	}
+
+	linearOnly, _ := Project(points, Options{Capacity: 100}, now)
+	if linearOnly.Model != ModelLinear {
+		t.Errorf("expected linear model without a period, got %s", linearOnly.Model)
+	}
+}
+
+func TestProjectFlatAndShrinkingUsage(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start.Add(47 * time.Hour)
+
+	flat, ok := Project(series(start, 48, time.Hour, func(float64) float64 { return 40 }), Options{Capacity: 100}, now)
+	if !ok || flat.ExhaustionAt != nil {
+		t.Errorf("expected flat usage to never run out, got %+v", flat)
+	}
+	shrinking, ok := Project(series(start, 48, time.Hour, func(hours float64) float64 { return 90 - hours/10 }), Options{Capacity: 100}, now)
+	if !ok || shrinking.ExhaustionAt != nil {
+		t.Errorf("expected shrinking usage to never run out, got %+v", shrinking)
+	}
+	full, ok := Project(series(start, 48, time.Hour, func(float64) float64 { return 100 }), Options{Capacity: 100}, now)
+	if !ok || full.DaysUntilExhaustion(now) != 0 {
+		t.Errorf("expected full resource to be exhausted now, got %+v", full)
+	}
+}
+
+func TestProjectRequiresHistory(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	grow := func(hours float64) float64 { return 50 + hours }
+
+	if _, ok := Project(series(start, MinSamples-1, time.Hour, grow), Options{Capacity: 100}, start); ok {
+		t.Error("expected too few samples to be rejected")
+	}
+	if _, ok := Project(series(start, 60, time.Minute, grow), Options{Capacity: 100}, start); ok {
+		t.Error("expected too short a span to be rejected")
+	}
+}
@@ -0,0 +1,160 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+func TestFileLock(t *testing.T) {
+	ctx := context.Background()
+	lock := NewFileLock(filepath.Join(t.TempDir(), "lease.json"))
+	ttl := time.Hour
+
+	lease, err := lock.TryAcquire(ctx, "a", "https://a:7655", ttl)
+	if err != nil || lease.Holder != "a" {
+		t.Fatalf("first acquire = %+v, %v", lease, err)
+	}
+	// b sees a's lease, and a can renew it
+	if lease, err := lock.TryAcquire(ctx, "b", "https://b:7655", ttl); err != nil || lease.Holder != "a" || lease.URL != "https://a:7655" {
+		t.Fatalf("b acquired a held lease: %+v, %v", lease, err)
+	}
+	if lease, err := lock.TryAcquire(ctx, "a", "https://a:7655", ttl); err != nil || lease.Holder != "a" {
+		t.Fatalf("renew = %+v, %v", lease, err)
+	}
+
+	// Releasing hands the lease over at once
+	if err := lock.Release(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if lease, err := lock.TryAcquire(ctx, "b", "https://b:7655", ttl); err != nil || lease.Holder != "b" {
+		t.Fatalf("b after release = %+v, %v", lease, err)
+	}
+
+	// An expired lease is taken over
+	if _, err := lock.TryAcquire(ctx, "b", "", -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if lease, err := lock.TryAcquire(ctx, "a", "", ttl); err != nil || lease.Holder != "a" {
+		t.Fatalf("a after expiry = %+v, %v", lease, err)
+	}
+}
+
+// peerServer serves the lease endpoint of lock, as the API does, until closed
+func peerServer(t *testing.T, lock **PeerLock) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != LeasePath || r.Header.Get(SecretHeader) != "shared-secret-value" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req LeaseRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode((*lock).Grant(req))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPeerLockElectsOneLeaderAndFailsOver(t *testing.T) {
+	ctx := context.Background()
+	ttl := time.Minute
+	var a, b *PeerLock
+	serverA := peerServer(t, &a)
+	serverB := peerServer(t, &b)
+	a = NewPeerLock("a", serverB.URL, "shared-secret-value", true)
+	b = NewPeerLock("b", serverA.URL, "shared-secret-value", true)
+
+	// Both contend at once: the lower node ID wins
+	b.mu.Lock()
+	b.contending = time.Now()
+	b.mu.Unlock()
+	leaseA, err := a.TryAcquire(ctx, "a", serverA.URL, ttl)
+	if err != nil || leaseA.Holder != "a" {
+		t.Fatalf("a = %+v, %v", leaseA, err)
+	}
+	leaseB, err := b.TryAcquire(ctx, "b", serverB.URL, ttl)
+	if err != nil || leaseB.Holder != "a" || leaseB.URL != serverA.URL {
+		t.Fatalf("b should stand by for a: %+v, %v", leaseB, err)
+	}
+	if lease, _ := a.TryAcquire(ctx, "a", serverA.URL, ttl); lease.Holder != "a" {
+		t.Fatalf("a lost its lease on renewal: %+v", lease)
+	}
+
+	// a goes away: b takes over once a's lease has expired
+	serverA.Close()
+	b.mu.Lock()
+	b.granted.Expires = time.Now().Add(-time.Second)
+	b.mu.Unlock()
+	if lease, err := b.TryAcquire(ctx, "b", serverB.URL, ttl); err != nil || lease.Holder != "b" {
+		t.Fatalf("b after a stopped = %+v, %v", lease, err)
+	}
+
+	// A returning a does not take the lease back
+	a.granted = Lease{}
+	if lease, err := a.TryAcquire(ctx, "a", serverA.URL, ttl); err != nil || lease.Holder != "b" {
+		t.Fatalf("returning a = %+v, %v", lease, err)
+	}
+}
+
+func TestPeerLockRejectedSecret(t *testing.T) {
+	var peer *PeerLock
+	server := peerServer(t, &peer)
+	peer = NewPeerLock("b", "http://unused", "shared-secret-value", true)
+	lock := NewPeerLock("a", server.URL, "wrong-secret-value!", true)
+
+	if _, err := lock.TryAcquire(context.Background(), "a", "", time.Minute); err == nil {
+		t.Fatal("a peer rejecting the secret must not be mistaken for a stopped peer")
+	}
+}
+
+func TestNodeRoleChanges(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.HAConfig{
+		Enabled:      true,
+		NodeID:       "a",
+		Lock:         config.HALockFile,
+		LockFile:     filepath.Join(dir, "lease.json"),
+		Secret:       "shared-secret-value",
+		LeaseTTL:     time.Minute,
+		SyncInterval: time.Minute,
+	}
+	node, err := New(cfg, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.IsLeader() || node.Status().Role != RoleStandby {
+		t.Fatal("a node must start as standby")
+	}
+
+	var changes []bool
+	onChange := func(leader bool) { changes = append(changes, leader) }
+	node.renew(context.Background(), onChange)
+	if !node.IsLeader() || len(changes) != 1 || !changes[0] {
+		t.Fatalf("expected to lead, changes %v", changes)
+	}
+
+	// Another instance holds the lease
+	lock := NewFileLock(cfg.LockFile)
+	if err := lock.write(Lease{Holder: "b", URL: "https://b:7655", Expires: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	node.renew(context.Background(), onChange)
+	status := node.Status()
+	if node.IsLeader() || len(changes) != 2 || status.Leader != "b" || status.LeaderURL != "https://b:7655" {
+		t.Fatalf("expected to stand by for b: %+v, changes %v", status, changes)
+	}
+
+	if got := node.syncExclude(); len(got) != 3 || got[1] != "lease.json" {
+		t.Fatalf("syncExclude = %v", got)
+	}
+}
@@ -0,0 +1,137 @@
+// Package ha runs Pulse as an active/passive pair: the instance holding the lease polls and
+// notifies, the other stands by and replicates the leader's data directory.
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// staleMutexAge is how long a file lock mutex may be held before it is considered abandoned
+const staleMutexAge = 30 * time.Second
+
+// Lease records which instance leads and until when
+type Lease struct {
+	Holder  string    `json:"holder"`
+	URL     string    `json:"url,omitempty"`
+	Expires time.Time `json:"expires"`
+}
+
+// Lock coordinates leadership between the two instances
+type Lock interface {
+	// TryAcquire takes or renews the lease for holder and returns the current lease, which
+	// belongs to the other instance when it still leads
+	TryAcquire(ctx context.Context, holder, url string, ttl time.Duration) (Lease, error)
+	// Release gives up the lease so the other instance can take over right away
+	Release(ctx context.Context, holder string) error
+}
+
+// FileLock keeps the lease in a file on storage shared by both instances. Updates are serialized
+// by an exclusively created mutex file, which works on NFS and SMB, and expiry relies on the
+// instances' clocks being in sync.
+type FileLock struct {
+	path string
+}
+
+// NewFileLock creates a lock backed by the lease file at path
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+// TryAcquire implements Lock
+func (l *FileLock) TryAcquire(ctx context.Context, holder, url string, ttl time.Duration) (Lease, error) {
+	unlock, err := l.lockMutex()
+	if err != nil {
+		return Lease{}, err
+	}
+	defer unlock()
+
+	current := l.read()
+	now := time.Now()
+	if current.Holder != "" && current.Holder != holder && now.Before(current.Expires) {
+		return current, nil
+	}
+	lease := Lease{Holder: holder, URL: url, Expires: now.Add(ttl)}
+	if err := l.write(lease); err != nil {
+		return Lease{}, err
+	}
+	return lease, nil
+}
+
+// Release implements Lock
+func (l *FileLock) Release(ctx context.Context, holder string) error {
+	unlock, err := l.lockMutex()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if l.read().Holder != holder {
+		return nil
+	}
+	return l.write(Lease{})
+}
+
+func (l *FileLock) lockMutex() (func(), error) {
+	mutex := l.path + ".lock"
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(mutex, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			file.Close()
+			return func() { _ = os.Remove(mutex) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock lease file: %w", err)
+		}
+		// An instance that died while holding the mutex must not block the lease forever
+		info, statErr := os.Stat(mutex)
+		if statErr != nil || time.Since(info.ModTime()) < staleMutexAge {
+			break
+		}
+		log.Warn().Str("path", mutex).Msg("Removing abandoned HA lease mutex")
+		_ = os.Remove(mutex)
+	}
+	return nil, fmt.Errorf("lease file is being updated by the other instance")
+}
+
+func (l *FileLock) read() Lease {
+	var lease Lease
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return lease
+	}
+	if err := json.Unmarshal(data, &lease); err != nil {
+		log.Warn().Err(err).Str("path", l.path).Msg("Ignoring unreadable HA lease file")
+		return Lease{}
+	}
+	return lease
+}
+
+func (l *FileLock) write(lease Lease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write lease file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write lease file: %w", err)
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
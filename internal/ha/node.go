@@ -0,0 +1,250 @@
+package ha
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/backup"
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/pkg/tlsutil"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// RoleLeader polls, alerts and notifies
+	RoleLeader = "leader"
+	// RoleStandby replicates the leader and takes over when its lease expires
+	RoleStandby = "standby"
+)
+
+// Status is the HA state reported by /api/health
+type Status struct {
+	Enabled   bool       `json:"enabled"`
+	NodeID    string     `json:"nodeId"`
+	Role      string     `json:"role"`
+	Lock      string     `json:"lock"`
+	Leader    string     `json:"leader,omitempty"`
+	LeaderURL string     `json:"leaderUrl,omitempty"`
+	Since     time.Time  `json:"since"`
+	LastError string     `json:"lastError,omitempty"`
+	LastSync  *time.Time `json:"lastSync,omitempty"`
+	SyncError string     `json:"syncError,omitempty"`
+}
+
+// Node is this instance's side of an HA pair
+type Node struct {
+	cfg     config.HAConfig
+	dataDir string
+	lock    Lock
+	peer    *PeerLock // Nil unless the peer lock is used
+	client  *http.Client
+
+	mu        sync.RWMutex
+	status    Status
+	renewedAt time.Time
+}
+
+// New creates the HA node for cfg. The secret is resolved once here.
+func New(cfg config.HAConfig, dataDir string) (*Node, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	secret, err := config.DefaultCredentialResolver().ResolveValue(cfg.Secret, "ha.secret")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve PULSE_HA_SECRET: %w", err)
+	}
+	cfg.Secret = secret
+
+	n := &Node{
+		cfg:     cfg,
+		dataDir: dataDir,
+		client:  tlsutil.CreateHTTPClientWithTimeout(cfg.VerifySSL, "", 5*time.Minute),
+		status: Status{
+			Enabled: true,
+			NodeID:  cfg.NodeID,
+			Role:    RoleStandby,
+			Lock:    cfg.Lock,
+			Since:   time.Now(),
+		},
+	}
+	switch cfg.Lock {
+	case config.HALockPeer:
+		n.peer = NewPeerLock(cfg.NodeID, cfg.PeerURL, secret, cfg.VerifySSL)
+		n.lock = n.peer
+	default:
+		n.lock = NewFileLock(cfg.LockFile)
+	}
+	return n, nil
+}
+
+// Peer returns the peer lock, or nil when leadership is coordinated through a file
+func (n *Node) Peer() *PeerLock {
+	return n.peer
+}
+
+// Authorized reports whether a request carries the shared secret
+func (n *Node) Authorized(r *http.Request) bool {
+	provided := r.Header.Get(SecretHeader)
+	return provided != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(n.cfg.Secret)) == 1
+}
+
+// IsLeader reports whether this instance currently leads
+func (n *Node) IsLeader() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.status.Role == RoleLeader
+}
+
+// Status returns the current HA state
+func (n *Node) Status() Status {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	status := n.status
+	if status.LastSync != nil {
+		lastSync := *status.LastSync
+		status.LastSync = &lastSync
+	}
+	return status
+}
+
+// Run renews or contends for the lease every third of its TTL and, while standing by, copies
+// the leader's data directory. onChange is called on every change of role; the node starts as
+// a standby. Leaving Run releases a held lease.
+func (n *Node) Run(ctx context.Context, onChange func(leader bool)) {
+	renew := time.NewTicker(n.cfg.LeaseTTL / 3)
+	defer renew.Stop()
+	replicate := time.NewTicker(n.cfg.SyncInterval)
+	defer replicate.Stop()
+
+	log.Info().
+		Str("node", n.cfg.NodeID).
+		Str("lock", n.cfg.Lock).
+		Dur("leaseTTL", n.cfg.LeaseTTL).
+		Msg("High availability enabled, starting as standby")
+
+	n.renew(ctx, onChange)
+	for {
+		select {
+		case <-ctx.Done():
+			if n.IsLeader() {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := n.lock.Release(releaseCtx, n.cfg.NodeID); err != nil {
+					log.Warn().Err(err).Msg("Failed to release HA lease")
+				}
+				cancel()
+			}
+			return
+		case <-renew.C:
+			n.renew(ctx, onChange)
+		case <-replicate.C:
+			if !n.IsLeader() {
+				n.sync(ctx)
+			}
+		}
+	}
+}
+
+func (n *Node) renew(ctx context.Context, onChange func(leader bool)) {
+	lease, err := n.lock.TryAcquire(ctx, n.cfg.NodeID, n.cfg.AdvertiseURL, n.cfg.LeaseTTL)
+	now := time.Now()
+
+	n.mu.Lock()
+	wasLeader := n.status.Role == RoleLeader
+	leader := wasLeader
+	if err != nil {
+		n.status.LastError = err.Error()
+		// A leader that cannot renew steps down once its lease may have passed to the standby
+		if wasLeader && now.Sub(n.renewedAt) >= n.cfg.LeaseTTL {
+			leader = false
+		}
+	} else {
+		n.status.LastError = ""
+		n.status.Leader = lease.Holder
+		n.status.LeaderURL = lease.URL
+		leader = lease.Holder == n.cfg.NodeID
+		if leader {
+			n.renewedAt = now
+		}
+	}
+	if leader != wasLeader {
+		n.status.Role = RoleStandby
+		if leader {
+			n.status.Role = RoleLeader
+		}
+		n.status.Since = now
+	}
+	n.mu.Unlock()
+
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to renew HA lease")
+	}
+	if leader == wasLeader {
+		return
+	}
+	if leader {
+		log.Warn().Str("node", n.cfg.NodeID).Msg("Became HA leader, starting monitoring")
+	} else {
+		log.Warn().Str("node", n.cfg.NodeID).Str("leader", lease.Holder).Msg("Lost HA leadership, standing by")
+	}
+	onChange(leader)
+}
+
+// syncExclude lists data directory paths that belong to this instance: .env holds its HA
+// identity, and the lease file may live in a shared data directory
+func (n *Node) syncExclude() []string {
+	exclude := []string{".env"}
+	if n.cfg.LockFile != "" {
+		if rel, err := filepath.Rel(n.dataDir, n.cfg.LockFile); err == nil && !strings.HasPrefix(rel, "..") {
+			exclude = append(exclude, rel, rel+".lock")
+		}
+	}
+	return exclude
+}
+
+// sync copies the leader's data directory, including its active alerts
+func (n *Node) sync(ctx context.Context) {
+	status := n.Status()
+	if status.LeaderURL == "" || status.Leader == "" || status.Leader == n.cfg.NodeID {
+		return
+	}
+
+	changed, err := n.fetch(ctx, status.LeaderURL)
+	now := time.Now()
+	n.mu.Lock()
+	if err != nil {
+		n.status.SyncError = err.Error()
+	} else {
+		n.status.SyncError = ""
+		n.status.LastSync = &now
+	}
+	n.mu.Unlock()
+
+	if err != nil {
+		log.Warn().Err(err).Str("leader", status.Leader).Msg("Failed to replicate from HA leader")
+	} else if len(changed) > 0 {
+		log.Info().Strs("files", changed).Str("leader", status.Leader).Msg("Replicated data directory from HA leader")
+	}
+}
+
+func (n *Node) fetch(ctx context.Context, leaderURL string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(leaderURL, "/")+ReplicatePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(SecretHeader, n.cfg.Secret)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("leader returned %s", resp.Status)
+	}
+	return backup.Sync(resp.Body, n.dataDir, n.syncExclude())
+}
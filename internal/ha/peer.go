@@ -0,0 +1,190 @@
+package ha
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/RouXx67/PulseUp/pkg/tlsutil"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// LeasePath is the endpoint a peer asks for the lease on
+	LeasePath = "/api/ha/lease"
+	// ReplicatePath is the leader endpoint serving an archive of its data directory
+	ReplicatePath = "/api/ha/replicate"
+	// SecretHeader carries the secret shared by both instances
+	SecretHeader = "X-Pulse-HA-Secret"
+)
+
+// LeaseRequest asks the peer for the lease, or gives it back
+type LeaseRequest struct {
+	Holder    string `json:"holder"`
+	URL       string `json:"url,omitempty"`
+	TTLMillis int64  `json:"ttlMs"`
+	Release   bool   `json:"release,omitempty"`
+}
+
+// LeaseResponse answers a LeaseRequest with the lease as the peer sees it
+type LeaseResponse struct {
+	Granted bool  `json:"granted"`
+	Lease   Lease `json:"lease"`
+}
+
+// PeerLock negotiates the lease directly with the other instance over HTTP. The leader renews
+// its lease with the peer; a standby that stops hearing from the leader for a lease period
+// takes over. When the peer cannot be reached at all it is assumed to be down, so a network
+// partition between the two instances makes both lead until it heals. Use the file lock when
+// that is not acceptable.
+type PeerLock struct {
+	self    string
+	peerURL string
+	secret  string
+	client  *http.Client
+
+	mu         sync.Mutex
+	url        string
+	own        Lease     // Held by this instance
+	granted    Lease     // Held by the peer, with the expiry measured locally
+	contending time.Time // Last time this instance asked for the lease
+}
+
+// NewPeerLock creates a lock negotiated between node self and the instance at peerURL
+func NewPeerLock(self, peerURL, secret string, verifySSL bool) *PeerLock {
+	return &PeerLock{
+		self:    self,
+		peerURL: peerURL,
+		secret:  secret,
+		client:  tlsutil.CreateHTTPClientWithTimeout(verifySSL, "", 5*time.Second),
+	}
+}
+
+// TryAcquire implements Lock
+func (l *PeerLock) TryAcquire(ctx context.Context, holder, url string, ttl time.Duration) (Lease, error) {
+	l.mu.Lock()
+	l.url = url
+	if l.granted.Holder != "" && l.granted.Holder != holder && time.Now().Before(l.granted.Expires) {
+		granted := l.granted
+		l.mu.Unlock()
+		return granted, nil
+	}
+	l.contending = time.Now()
+	l.mu.Unlock()
+
+	resp, err := l.request(ctx, LeaseRequest{Holder: holder, URL: url, TTLMillis: ttl.Milliseconds()})
+	var status *statusError
+	if errors.As(err, &status) && status.misconfigured() {
+		// The peer is up but rejects us, e.g. a different secret or HA disabled there
+		return Lease{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err == nil && !resp.Granted {
+		lease := resp.Lease
+		if lease.URL == "" {
+			lease.URL = l.peerURL
+		}
+		// Expiry is measured locally so clock skew between the instances does not matter
+		lease.Expires = time.Now().Add(ttl)
+		l.granted = lease
+		l.own = Lease{}
+		return lease, nil
+	}
+	if err != nil {
+		log.Debug().Err(err).Str("peer", l.peerURL).Msg("HA peer unreachable, holding the lease")
+	}
+	l.granted = Lease{}
+	l.own = Lease{Holder: holder, URL: url, Expires: time.Now().Add(ttl)}
+	return l.own, nil
+}
+
+// Release implements Lock
+func (l *PeerLock) Release(ctx context.Context, holder string) error {
+	l.mu.Lock()
+	l.own = Lease{}
+	l.mu.Unlock()
+	_, err := l.request(ctx, LeaseRequest{Holder: holder, Release: true})
+	return err
+}
+
+// Grant answers a lease request from the peer
+func (l *PeerLock) Grant(req LeaseRequest) LeaseResponse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+
+	if req.Release {
+		if l.granted.Holder == req.Holder {
+			l.granted = Lease{}
+		}
+		return LeaseResponse{}
+	}
+
+	if l.own.Holder != "" && l.own.Holder != req.Holder && now.Before(l.own.Expires) {
+		return LeaseResponse{Lease: l.own}
+	}
+	// When both instances ask at once, typically at startup, the lower node ID wins. A peer
+	// renewing a lease it already holds always keeps it.
+	ttl := time.Duration(req.TTLMillis) * time.Millisecond
+	renewing := l.granted.Holder == req.Holder && now.Before(l.granted.Expires)
+	if !renewing && now.Sub(l.contending) < ttl && l.self < req.Holder {
+		return LeaseResponse{Lease: Lease{Holder: l.self, URL: l.url}}
+	}
+
+	l.own = Lease{}
+	l.granted = Lease{Holder: req.Holder, URL: req.URL, Expires: now.Add(ttl)}
+	return LeaseResponse{Granted: true, Lease: l.granted}
+}
+
+// statusError is a response from a peer that is up but refused the request
+type statusError struct {
+	code   int
+	status string
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HA peer returned %s: %s", e.status, e.body)
+}
+
+// misconfigured reports a rejection by Pulse itself rather than, say, a proxy in front of a
+// stopped peer
+func (e *statusError) misconfigured() bool {
+	return e.code == http.StatusUnauthorized || e.code == http.StatusForbidden || e.code == http.StatusNotFound
+}
+
+func (l *PeerLock) request(ctx context.Context, lease LeaseRequest) (*LeaseResponse, error) {
+	body, err := json.Marshal(lease)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.peerURL+LeasePath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SecretHeader, l.secret)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &statusError{code: resp.StatusCode, status: resp.Status, body: string(bytes.TrimSpace(message))}
+	}
+	var out LeaseResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid lease response: %w", err)
+	}
+	return &out, nil
+}
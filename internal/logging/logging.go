@@ -16,6 +16,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/term"
 )
 
@@ -56,7 +57,7 @@ var (
 )
 
 func init() {
-	baseLogger = zerolog.New(baseWriter).With().Timestamp().Logger()
+	baseLogger = zerolog.New(baseWriter).Hook(traceHook{}).With().Timestamp().Logger()
 	log.Logger = baseLogger
 }
 
@@ -77,7 +78,7 @@ func Init(cfg Config) zerolog.Logger {
 	}
 	component := strings.TrimSpace(cfg.Component)
 
-	contextBuilder := zerolog.New(writer).With().Timestamp()
+	contextBuilder := zerolog.New(writer).Hook(traceHook{}).With().Timestamp()
 	if component != "" {
 		contextBuilder = contextBuilder.Str("component", component)
 	}
@@ -164,7 +165,7 @@ func New(component string, opts ...Option) zerolog.Logger {
 		component = globalComponent
 	}
 
-	logger := zerolog.New(writer)
+	logger := zerolog.New(writer).Hook(traceHook{})
 	contextBuilder := logger.With().Timestamp()
 
 	if component != "" {
@@ -334,11 +335,31 @@ func enrichWithRequestID(logger zerolog.Logger, ctx context.Context) zerolog.Log
 		return logger
 	}
 	if id := GetRequestID(ctx); id != "" {
-		return logger.With().Str("request_id", id).Logger()
+		logger = logger.With().Str("request_id", id).Logger()
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		logger = logger.With().
+			Str("trace_id", spanCtx.TraceID().String()).
+			Str("span_id", spanCtx.SpanID().String()).
+			Logger()
 	}
 	return logger
 }
 
+// traceHook adds the trace and span IDs to events logged with a span context attached through
+// Event.Ctx, so log lines can be found from a trace and the other way round
+type traceHook struct{}
+
+func (traceHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+	if ctx == nil {
+		return
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		e.Str("trace_id", spanCtx.TraceID().String()).Str("span_id", spanCtx.SpanID().String())
+	}
+}
+
 type rollingFileWriter struct {
 	mu          sync.Mutex
 	path        string
@@ -13,6 +13,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func resetLoggingState() {
@@ -266,6 +267,39 @@ func TestContextHelpersWithExistingLogger(t *testing.T) {
 	}
 }
 
+func TestTraceIDsAreAddedToLogs(t *testing.T) {
+	t.Cleanup(resetLoggingState)
+
+	Init(Config{
+		Format: "json",
+		Level:  "info",
+	})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	var buf bytes.Buffer
+	logger := New("svc", WithWriter(&buf))
+	logger.Info().Ctx(ctx).Msg("with-span")
+	event := readJSONLine(t, &buf)
+	if event["trace_id"] != traceID.String() || event["span_id"] != spanID.String() {
+		t.Fatalf("expected trace and span IDs on an event with a span context, got %v", event)
+	}
+
+	buf.Reset()
+	enriched := FromContext(WithLogger(ctx, New("svc", WithWriter(&buf))))
+	enriched.Info().Msg("from-context")
+	event = readJSONLine(t, &buf)
+	if event["trace_id"] != traceID.String() {
+		t.Fatalf("expected FromContext to add the trace ID, got %v", event)
+	}
+}
+
 func TestWithLoggerNilContext(t *testing.T) {
 	t.Cleanup(resetLoggingState)
 
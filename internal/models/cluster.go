@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // ClusterHealth represents the health status of a cluster
 type ClusterHealth struct {
 	Name             string              `json:"name"`
@@ -15,3 +17,60 @@ type ClusterNodeStatus struct {
 	Endpoint string `json:"endpoint"`
 	Online   bool   `json:"online"`
 }
+
+// ClusterHAStatus is the corosync quorum and Proxmox HA state of a PVE cluster instance
+type ClusterHAStatus struct {
+	ID              string            `json:"id"`
+	Instance        string            `json:"instance"`
+	ClusterName     string            `json:"clusterName"`
+	Quorate         bool              `json:"quorate"`
+	ConfigVersion   int               `json:"configVersion,omitempty"`
+	ExpectedVotes   int               `json:"expectedVotes"`
+	TotalVotes      int               `json:"totalVotes"`
+	QuorumThreshold int               `json:"quorumThreshold"`
+	Members         []ClusterMember   `json:"members"`
+	ManagerNode     string            `json:"managerNode,omitempty"`
+	ManagerState    string            `json:"managerState,omitempty"`
+	LRMs            []HALRMStatus     `json:"lrms,omitempty"`
+	Resources       []HAResourceState `json:"resources,omitempty"`
+	LastUpdated     time.Time         `json:"lastUpdated"`
+}
+
+// ClusterMember is a corosync member node and its vote
+type ClusterMember struct {
+	Name   string `json:"name"`
+	NodeID int    `json:"nodeId"`
+	IP     string `json:"ip,omitempty"`
+	Online bool   `json:"online"`
+	Votes  int    `json:"votes"`
+}
+
+// HALRMStatus is the local resource manager state on a node
+type HALRMStatus struct {
+	Node      string    `json:"node"`
+	State     string    `json:"state"`
+	Dead      bool      `json:"dead"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// HAResourceState is a resource managed by the Proxmox HA stack
+type HAResourceState struct {
+	SID          string `json:"sid"`
+	Type         string `json:"type"` // vm or ct
+	VMID         int    `json:"vmid"`
+	Node         string `json:"node"`
+	State        string `json:"state"`
+	CRMState     string `json:"crmState,omitempty"`
+	RequestState string `json:"requestState,omitempty"`
+	Group        string `json:"group,omitempty"`
+	MaxRestart   int    `json:"maxRestart,omitempty"`
+	MaxRelocate  int    `json:"maxRelocate,omitempty"`
+}
+
+func (c ClusterHAStatus) clone() ClusterHAStatus {
+	clone := c
+	clone.Members = append([]ClusterMember(nil), c.Members...)
+	clone.LRMs = append([]HALRMStatus(nil), c.LRMs...)
+	clone.Resources = append([]HAResourceState(nil), c.Resources...)
+	return clone
+}
@@ -78,6 +78,7 @@ func (v VM) ToFrontend() VMFrontend {
 		DiskWrite:        zeroIfNegative(v.DiskWrite),
 		Uptime:           v.Uptime,
 		Template:         v.Template,
+		Pool:             v.Pool,
 		Lock:             v.Lock,
 		LastSeen:         v.LastSeen.Unix() * 1000,
 		DiskStatusReason: v.DiskStatusReason,
@@ -152,6 +153,7 @@ func (c Container) ToFrontend() ContainerFrontend {
 		DiskWrite: zeroIfNegative(c.DiskWrite),
 		Uptime:    c.Uptime,
 		Template:  c.Template,
+		Pool:      c.Pool,
 		Lock:      c.Lock,
 		LastSeen:  c.LastSeen.Unix() * 1000,
 	}
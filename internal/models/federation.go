@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// FederationSiteStatus is the health of a downstream Pulse instance as seen by the central
+// instance
+type FederationSiteStatus struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	URL          string     `json:"url"`
+	Enabled      bool       `json:"enabled"`
+	Connected    bool       `json:"connected"`
+	Stale        bool       `json:"stale"`
+	LastUpdate   *time.Time `json:"lastUpdate,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	PulseVersion string     `json:"pulseVersion,omitempty"`
+	Nodes        int        `json:"nodes"`
+	Guests       int        `json:"guests"`
+	ActiveAlerts int        `json:"activeAlerts"`
+	// OfflineInstances counts the site's PVE, PBS and PMG connections that are down
+	OfflineInstances int `json:"offlineInstances"`
+}
+
+// FederatedStateFrontend is the global view of the central instance and all downstream sites
+type FederatedStateFrontend struct {
+	Sites []FederationSiteStatus `json:"sites"`
+	State StateFrontend          `json:"state"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Capacity forecast resource kinds
+const (
+	ForecastNodeMemory   = "node-memory"
+	ForecastStorage      = "storage"
+	ForecastPBSDatastore = "pbs-datastore"
+	ForecastCephPool     = "ceph-pool"
+)
+
+// CapacityForecast projects when a node, storage, PBS datastore or Ceph pool runs out of capacity.
+// Usage values are percentages of the resource's capacity.
+type CapacityForecast struct {
+	ID            string     `json:"id"` // kind:resourceID
+	Kind          string     `json:"kind"`
+	ResourceID    string     `json:"resourceId"`
+	Name          string     `json:"name"`
+	Instance      string     `json:"instance"`
+	Node          string     `json:"node,omitempty"`
+	Model         string     `json:"model"` // linear or seasonal
+	Usage         float64    `json:"usage"`
+	TotalBytes    int64      `json:"totalBytes,omitempty"`
+	GrowthPerDay  float64    `json:"growthPerDay"` // Percentage points per day
+	ExhaustionAt  *time.Time `json:"exhaustionAt,omitempty"`
+	DaysRemaining *float64   `json:"daysRemaining,omitempty"`
+	Confidence    float64    `json:"confidence"` // 0-1
+	Samples       int        `json:"samples"`
+	SampledSince  time.Time  `json:"sampledSince"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
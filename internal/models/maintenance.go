@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// NodeMaintenance is the package update, subscription and certificate status of a PVE, PBS or PMG node
+type NodeMaintenance struct {
+	ID              string            `json:"id"`
+	Platform        string            `json:"platform"` // pve, pbs or pmg
+	Instance        string            `json:"instance"`
+	Node            string            `json:"node"`
+	PendingUpdates  int               `json:"pendingUpdates"`
+	SecurityUpdates int               `json:"securityUpdates"`
+	Updates         []PendingUpdate   `json:"updates,omitempty"`
+	RunningKernel   string            `json:"runningKernel,omitempty"`
+	LatestKernel    string            `json:"latestKernel,omitempty"`
+	KernelOutdated  bool              `json:"kernelOutdated"` // A newer kernel is installed; a reboot is required to use it
+	Subscription    *NodeSubscription `json:"subscription,omitempty"`
+	Certificates    []NodeCertificate `json:"certificates,omitempty"`
+	Errors          []string          `json:"errors,omitempty"` // Endpoints that could not be read on the last check
+	LastChecked     time.Time         `json:"lastChecked"`
+}
+
+// PendingUpdate is a package with an upgrade available
+type PendingUpdate struct {
+	Package    string `json:"package"`
+	Version    string `json:"version"`
+	OldVersion string `json:"oldVersion,omitempty"`
+	Origin     string `json:"origin,omitempty"`
+	Security   bool   `json:"security"`
+}
+
+// NodeSubscription is the subscription state of a node (the key itself is never stored)
+type NodeSubscription struct {
+	Status      string `json:"status"`
+	Level       string `json:"level,omitempty"`
+	ProductName string `json:"productName,omitempty"`
+	NextDueDate string `json:"nextDueDate,omitempty"`
+}
+
+// Active reports whether the node has a valid subscription
+func (s *NodeSubscription) Active() bool {
+	return s != nil && s.Status == "active"
+}
+
+// NodeCertificate is a TLS certificate installed on a node
+type NodeCertificate struct {
+	Filename      string    `json:"filename"`
+	Subject       string    `json:"subject,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	Fingerprint   string    `json:"fingerprint,omitempty"`
+	SANs          []string  `json:"sans,omitempty"`
+	NotBefore     time.Time `json:"notBefore,omitempty"`
+	NotAfter      time.Time `json:"notAfter"`
+	DaysRemaining int       `json:"daysRemaining"`
+}
+
+// PatchStatusSummary aggregates maintenance status across the fleet
+type PatchStatusSummary struct {
+	Nodes                int               `json:"nodes"`
+	UpToDate             int               `json:"upToDate"`
+	WithUpdates          int               `json:"withUpdates"`
+	WithSecurityUpdates  int               `json:"withSecurityUpdates"`
+	PendingUpdates       int               `json:"pendingUpdates"`
+	SecurityUpdates      int               `json:"securityUpdates"`
+	RebootRequired       int               `json:"rebootRequired"`
+	WithoutSubscription  int               `json:"withoutSubscription"`
+	CertificatesExpiring int               `json:"certificatesExpiring"`
+	Entries              []NodeMaintenance `json:"entries"`
+}
+
+// SummarizePatchStatus builds the fleet patch view. Certificates count as expiring when
+// they have expiryDays or fewer days left.
+func SummarizePatchStatus(entries []NodeMaintenance, expiryDays int) PatchStatusSummary {
+	summary := PatchStatusSummary{
+		Nodes:   len(entries),
+		Entries: entries,
+	}
+	if summary.Entries == nil {
+		summary.Entries = []NodeMaintenance{}
+	}
+
+	for _, entry := range entries {
+		summary.PendingUpdates += entry.PendingUpdates
+		summary.SecurityUpdates += entry.SecurityUpdates
+		switch {
+		case entry.SecurityUpdates > 0:
+			summary.WithSecurityUpdates++
+			summary.WithUpdates++
+		case entry.PendingUpdates > 0:
+			summary.WithUpdates++
+		}
+		if entry.PendingUpdates == 0 && !entry.KernelOutdated {
+			summary.UpToDate++
+		}
+		if entry.KernelOutdated {
+			summary.RebootRequired++
+		}
+		if entry.Subscription != nil && !entry.Subscription.Active() {
+			summary.WithoutSubscription++
+		}
+		for _, cert := range entry.Certificates {
+			if cert.DaysRemaining <= expiryDays {
+				summary.CertificatesExpiring++
+			}
+		}
+	}
+
+	return summary
+}
+
+func (n NodeMaintenance) clone() NodeMaintenance {
+	clone := n
+	clone.Updates = append([]PendingUpdate(nil), n.Updates...)
+	clone.Certificates = append([]NodeCertificate(nil), n.Certificates...)
+	clone.Errors = append([]string(nil), n.Errors...)
+	if n.Subscription != nil {
+		subscription := *n.Subscription
+		clone.Subscription = &subscription
+	}
+	return clone
+}
@@ -32,6 +32,13 @@ type State struct {
 	ActiveAlerts     []Alert          `json:"activeAlerts"`
 	RecentlyResolved []ResolvedAlert  `json:"recentlyResolved"`
 	LastUpdate       time.Time        `json:"lastUpdate"`
+
+	// SnapshotRetention is the most recent snapshot retention run
+	SnapshotRetention *SnapshotRetentionRun `json:"snapshotRetention,omitempty"`
+	// ClusterHA holds corosync quorum and HA manager state per cluster instance
+	ClusterHA []ClusterHAStatus `json:"clusterHA,omitempty"`
+	// NodeMaintenance holds package update, subscription and certificate status per node
+	NodeMaintenance []NodeMaintenance `json:"nodeMaintenance,omitempty"`
 }
 
 // Alert represents an active alert (simplified for State)
@@ -110,6 +117,7 @@ type VM struct {
 	Template          bool                    `json:"template"`
 	LastBackup        time.Time               `json:"lastBackup,omitempty"`
 	Tags              []string                `json:"tags,omitempty"`
+	Pool              string                  `json:"pool,omitempty"`
 	Lock              string                  `json:"lock,omitempty"`
 	LastSeen          time.Time               `json:"lastSeen"`
 }
@@ -136,6 +144,7 @@ type Container struct {
 	Template          bool                    `json:"template"`
 	LastBackup        time.Time               `json:"lastBackup,omitempty"`
 	Tags              []string                `json:"tags,omitempty"`
+	Pool              string                  `json:"pool,omitempty"`
 	Lock              string                  `json:"lock,omitempty"`
 	LastSeen          time.Time               `json:"lastSeen"`
 	IPAddresses       []string                `json:"ipAddresses,omitempty"`
@@ -728,6 +737,46 @@ type GuestSnapshot struct {
 	SizeBytes   int64     `json:"sizeBytes,omitempty"`
 }
 
+// Snapshot retention outcomes
+const (
+	SnapshotRetentionPlanned = "planned"
+	SnapshotRetentionDeleted = "deleted"
+	SnapshotRetentionFailed  = "failed"
+)
+
+// SnapshotRetentionAction records the decision a retention run made for one snapshot
+type SnapshotRetentionAction struct {
+	SnapshotID   string    `json:"snapshotId"`
+	GuestID      string    `json:"guestId"`
+	GuestName    string    `json:"guestName,omitempty"`
+	Instance     string    `json:"instance"`
+	Node         string    `json:"node"`
+	VMID         int       `json:"vmid"`
+	Type         string    `json:"type"`
+	Snapshot     string    `json:"snapshot"`
+	SnapshotTime time.Time `json:"snapshotTime"`
+	PolicyID     string    `json:"policyId"`
+	PolicyName   string    `json:"policyName,omitempty"`
+	Reason       string    `json:"reason"`
+	Outcome      string    `json:"outcome"`
+	UPID         string    `json:"upid,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// SnapshotRetentionRun summarises one evaluation of the snapshot retention policies
+type SnapshotRetentionRun struct {
+	ID         string                    `json:"id"`
+	Trigger    string                    `json:"trigger"` // schedule, manual or preview
+	DryRun     bool                      `json:"dryRun"`
+	StartedAt  time.Time                 `json:"startedAt"`
+	FinishedAt time.Time                 `json:"finishedAt"`
+	Evaluated  int                       `json:"evaluated"`
+	Planned    int                       `json:"planned"`
+	Deleted    int                       `json:"deleted"`
+	Failed     int                       `json:"failed"`
+	Actions    []SnapshotRetentionAction `json:"actions"`
+}
+
 // ReplicationJob represents the status of a Proxmox storage replication job.
 type ReplicationJob struct {
 	ID                      string     `json:"id"`
@@ -1572,6 +1621,79 @@ func (s *State) UpdateGuestSnapshotsForInstance(instanceName string, snapshots [
 	s.LastUpdate = time.Now()
 }
 
+// RemoveGuestSnapshot drops a snapshot from state after it has been deleted
+func (s *State) RemoveGuestSnapshot(snapshotID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filtered := make([]GuestSnapshot, 0, len(s.PVEBackups.GuestSnapshots))
+	for _, snapshot := range s.PVEBackups.GuestSnapshots {
+		if snapshot.ID != snapshotID {
+			filtered = append(filtered, snapshot)
+		}
+	}
+	s.PVEBackups.GuestSnapshots = filtered
+	s.syncBackupsLocked()
+	s.LastUpdate = time.Now()
+}
+
+// SetSnapshotRetentionRun records the latest snapshot retention run
+func (s *State) SetSnapshotRetentionRun(run SnapshotRetentionRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SnapshotRetention = &run
+}
+
+// UpdateClusterHAForInstance replaces the quorum and HA state for an instance; nil clears it
+func (s *State) UpdateClusterHAForInstance(instanceName string, status *ClusterHAStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filtered := make([]ClusterHAStatus, 0, len(s.ClusterHA)+1)
+	for _, cluster := range s.ClusterHA {
+		if cluster.Instance != instanceName {
+			filtered = append(filtered, cluster)
+		}
+	}
+	if status != nil {
+		filtered = append(filtered, *status)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Instance < filtered[j].Instance
+	})
+
+	s.ClusterHA = filtered
+	s.LastUpdate = time.Now()
+}
+
+// UpdateNodeMaintenanceForInstance replaces the maintenance status of every node of a PVE, PBS or PMG instance
+func (s *State) UpdateNodeMaintenanceForInstance(platform, instanceName string, entries []NodeMaintenance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filtered := make([]NodeMaintenance, 0, len(s.NodeMaintenance)+len(entries))
+	for _, entry := range s.NodeMaintenance {
+		if entry.Platform != platform || entry.Instance != instanceName {
+			filtered = append(filtered, entry)
+		}
+	}
+	filtered = append(filtered, entries...)
+
+	sort.Slice(filtered, func(i, j int) bool {
+		if filtered[i].Platform != filtered[j].Platform {
+			return filtered[i].Platform < filtered[j].Platform
+		}
+		if filtered[i].Instance != filtered[j].Instance {
+			return filtered[i].Instance < filtered[j].Instance
+		}
+		return filtered[i].Node < filtered[j].Node
+	})
+
+	s.NodeMaintenance = filtered
+	s.LastUpdate = time.Now()
+}
+
 // SetConnectionHealth updates the connection health for an instance
 func (s *State) SetConnectionHealth(instanceID string, healthy bool) {
 	s.mu.Lock()
@@ -61,6 +61,7 @@ type VMFrontend struct {
 	Template          bool                    `json:"template"`
 	LastBackup        int64                   `json:"lastBackup,omitempty"` // Unix timestamp
 	Tags              string                  `json:"tags,omitempty"`       // Joined string
+	Pool              string                  `json:"pool,omitempty"`
 	Lock              string                  `json:"lock,omitempty"`
 	LastSeen          int64                   `json:"lastSeen"` // Unix timestamp
 }
@@ -91,6 +92,7 @@ type ContainerFrontend struct {
 	Template          bool                    `json:"template"`
 	LastBackup        int64                   `json:"lastBackup,omitempty"` // Unix timestamp
 	Tags              string                  `json:"tags,omitempty"`       // Joined string
+	Pool              string                  `json:"pool,omitempty"`
 	Lock              string                  `json:"lock,omitempty"`
 	LastSeen          int64                   `json:"lastSeen"` // Unix timestamp
 }
@@ -318,4 +320,8 @@ type StateFrontend struct {
 	ConnectionHealth map[string]bool          `json:"connectionHealth"` // Keep as is
 	Stats            map[string]any           `json:"stats"`            // Empty object for now
 	LastUpdate       int64                    `json:"lastUpdate"`       // Unix timestamp
+
+	SnapshotRetention *SnapshotRetentionRun `json:"snapshotRetention,omitempty"`
+	ClusterHA         []ClusterHAStatus     `json:"clusterHA,omitempty"`
+	NodeMaintenance   []NodeMaintenance     `json:"nodeMaintenance,omitempty"`
 }
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Rightsizing recommendation kinds
+const (
+	RightsizingOversized  = "oversized"
+	RightsizingUndersized = "undersized"
+	RightsizingIdle       = "idle"
+)
+
+// Rightsizing recommendation resources
+const (
+	RightsizingCPU    = "cpu"
+	RightsizingMemory = "memory"
+	RightsizingGuest  = "guest" // Idle guests: the whole allocation
+)
+
+// GuestRecommendation is one suggested sizing change for a guest, based on its p95 usage over the
+// report window. Reclaimable values are what resizing or removing the guest would free up.
+type GuestRecommendation struct {
+	ID                     string    `json:"id"` // guestID:kind:resource
+	GuestID                string    `json:"guestId"`
+	VMID                   int       `json:"vmid"`
+	Name                   string    `json:"name"`
+	Type                   string    `json:"type"` // qemu or lxc
+	Node                   string    `json:"node"`
+	Instance               string    `json:"instance"`
+	Kind                   string    `json:"kind"`
+	Resource               string    `json:"resource"`
+	Reason                 string    `json:"reason"`
+	CPUs                   int       `json:"cpus"`
+	RecommendedCPUs        int       `json:"recommendedCpus"`
+	CPUP95                 float64   `json:"cpuP95"` // vCPUs in use
+	MemoryBytes            int64     `json:"memoryBytes"`
+	RecommendedMemoryBytes int64     `json:"recommendedMemoryBytes"`
+	MemoryP95Bytes         int64     `json:"memoryP95Bytes"`
+	RunningPercent         float64   `json:"runningPercent"`
+	ReclaimableCPUs        int       `json:"reclaimableCpus"`
+	ReclaimableMemoryBytes int64     `json:"reclaimableMemoryBytes"`
+	ReclaimableDiskBytes   int64     `json:"reclaimableDiskBytes,omitempty"`
+	Samples                int       `json:"samples"` // Hours of history
+	SampledSince           time.Time `json:"sampledSince"`
+}
+
+// NodeRightsizing totals the reclaimable resources of the recommendations on a node
+type NodeRightsizing struct {
+	Node                   string `json:"node"`
+	Instance               string `json:"instance"`
+	Oversized              int    `json:"oversized"`
+	Undersized             int    `json:"undersized"`
+	Idle                   int    `json:"idle"`
+	ReclaimableCPUs        int    `json:"reclaimableCpus"`
+	ReclaimableMemoryBytes int64  `json:"reclaimableMemoryBytes"`
+	ReclaimableDiskBytes   int64  `json:"reclaimableDiskBytes"`
+}
+
+// RightsizingReport is the set of guest sizing recommendations over a usage window
+type RightsizingReport struct {
+	WindowDays      int                   `json:"windowDays"`
+	GeneratedAt     time.Time             `json:"generatedAt"`
+	Recommendations []GuestRecommendation `json:"recommendations"`
+	Nodes           []NodeRightsizing     `json:"nodes"`
+}
@@ -25,6 +25,13 @@ type StateSnapshot struct {
 	ActiveAlerts     []Alert          `json:"activeAlerts"`
 	RecentlyResolved []ResolvedAlert  `json:"recentlyResolved"`
 	LastUpdate       time.Time        `json:"lastUpdate"`
+
+	// SnapshotRetention is the most recent snapshot retention run
+	SnapshotRetention *SnapshotRetentionRun `json:"snapshotRetention,omitempty"`
+	// ClusterHA holds corosync quorum and HA manager state per cluster instance
+	ClusterHA []ClusterHAStatus `json:"clusterHA,omitempty"`
+	// NodeMaintenance holds package update, subscription and certificate status per node
+	NodeMaintenance []NodeMaintenance `json:"nodeMaintenance,omitempty"`
 }
 
 // GetSnapshot returns a snapshot of the current state without mutex
@@ -69,6 +76,24 @@ func (s *State) GetSnapshot() StateSnapshot {
 		LastUpdate:       s.LastUpdate,
 	}
 
+	if s.SnapshotRetention != nil {
+		run := *s.SnapshotRetention
+		run.Actions = append([]SnapshotRetentionAction{}, run.Actions...)
+		snapshot.SnapshotRetention = &run
+	}
+	if len(s.ClusterHA) > 0 {
+		snapshot.ClusterHA = make([]ClusterHAStatus, 0, len(s.ClusterHA))
+		for _, cluster := range s.ClusterHA {
+			snapshot.ClusterHA = append(snapshot.ClusterHA, cluster.clone())
+		}
+	}
+	if len(s.NodeMaintenance) > 0 {
+		snapshot.NodeMaintenance = make([]NodeMaintenance, 0, len(s.NodeMaintenance))
+		for _, entry := range s.NodeMaintenance {
+			snapshot.NodeMaintenance = append(snapshot.NodeMaintenance, entry.clone())
+		}
+	}
+
 	// Copy map
 	for k, v := range s.ConnectionHealth {
 		snapshot.ConnectionHealth[k] = v
@@ -119,7 +144,7 @@ func (s StateSnapshot) ToFrontend() StateFrontend {
 		replicationJobs[i] = job.ToFrontend()
 	}
 
-	return StateFrontend{
+	frontend := StateFrontend{
 		Nodes:            nodes,
 		VMs:              vms,
 		Containers:       containers,
@@ -141,4 +166,8 @@ func (s StateSnapshot) ToFrontend() StateFrontend {
 		Stats:            make(map[string]any),
 		LastUpdate:       s.LastUpdate.Unix() * 1000, // JavaScript timestamp
 	}
+	frontend.SnapshotRetention = s.SnapshotRetention
+	frontend.ClusterHA = s.ClusterHA
+	frontend.NodeMaintenance = s.NodeMaintenance
+	return frontend
 }
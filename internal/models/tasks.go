@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Task states derived from the Proxmox exit status
+const (
+	TaskStateRunning = "running"
+	TaskStateOK      = "ok"
+	TaskStateWarning = "warning"
+	TaskStateFailed  = "failed"
+)
+
+// ClusterTask is a task from a PVE node's task log (migration, clone, vzdump, HA and so on)
+type ClusterTask struct {
+	ID        string    `json:"id"` // instance-UPID
+	UPID      string    `json:"upid"`
+	Instance  string    `json:"instance"`
+	Node      string    `json:"node"`
+	Type      string    `json:"type"`
+	Target    string    `json:"target,omitempty"` // Task object ID, usually a VMID or storage
+	VMID      int       `json:"vmid,omitempty"`
+	User      string    `json:"user"`
+	Status    string    `json:"status,omitempty"` // Raw exit status, e.g. "OK" or the error message
+	State     string    `json:"state"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+	Duration  float64   `json:"duration,omitempty"` // Seconds, once finished
+}
+
+// Finished reports whether the task has completed
+func (t ClusterTask) Finished() bool {
+	return t.State != TaskStateRunning
+}
@@ -0,0 +1,457 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/websocket"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Backup job triggers
+const (
+	BackupJobTriggerManual      = "manual"
+	BackupJobTriggerRemediation = "remediation"
+)
+
+const (
+	backupJobPollInterval = 15 * time.Second
+	backupJobTimeout      = 12 * time.Hour
+	backupJobHistoryLimit = 100
+
+	backupRemediationCheckInterval = 5 * time.Minute
+	// Give the backup polls time to populate state and alerts before acting on them
+	backupRemediationStartupDelay = 15 * time.Minute
+	backupRemediationRequester    = "backup-remediation"
+)
+
+// BackupJobClient is implemented by PVE clients that can start vzdump backups.
+type BackupJobClient interface {
+	StartBackup(ctx context.Context, node string, vmids []int, opts proxmox.BackupOptions) (string, error)
+	GetBackupTasks(ctx context.Context) ([]proxmox.Task, error)
+}
+
+// BackupJobGuest is a guest included in a backup job
+type BackupJobGuest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	VMID int    `json:"vmid"`
+	Type string `json:"type"`
+}
+
+// BackupJob tracks one vzdump task started by Pulse. Guests on the same node share a job.
+type BackupJob struct {
+	ID          string           `json:"id"`
+	Instance    string           `json:"instance"`
+	Node        string           `json:"node"`
+	Guests      []BackupJobGuest `json:"guests"`
+	Storage     string           `json:"storage,omitempty"`
+	Mode        string           `json:"mode,omitempty"`
+	Compress    string           `json:"compress,omitempty"`
+	Trigger     string           `json:"trigger"`
+	Status      string           `json:"status"`
+	UPID        string           `json:"upid,omitempty"`
+	TaskStatus  string           `json:"taskStatus,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	RequestedBy string           `json:"requestedBy,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+	FinishedAt  *time.Time       `json:"finishedAt,omitempty"`
+}
+
+func (m *Monitor) backupJobClient(instance string) (BackupJobClient, error) {
+	m.mu.RLock()
+	client, ok := m.pveClients[instance]
+	m.mu.RUnlock()
+	if !ok || client == nil {
+		return nil, fmt.Errorf("no client for instance %s", instance)
+	}
+	backupClient, ok := client.(BackupJobClient)
+	if !ok {
+		return nil, fmt.Errorf("client for instance %s does not support backups", instance)
+	}
+	return backupClient, nil
+}
+
+// StartBackupJobs starts vzdump for the given guests, one task per node, and tracks each
+// task through the node task list. Jobs that could not be submitted are returned as failed;
+// an error is only returned when nothing was started.
+func (m *Monitor) StartBackupJobs(guestIDs []string, opts proxmox.BackupOptions, trigger, requestedBy string) ([]BackupJob, error) {
+	if len(guestIDs) == 0 {
+		return nil, fmt.Errorf("no guests selected")
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	type nodeGroup struct {
+		instance string
+		node     string
+		guests   []BackupJobGuest
+	}
+	var groups []*nodeGroup
+	byNode := make(map[string]*nodeGroup)
+	seen := make(map[string]bool, len(guestIDs))
+
+	for _, guestID := range guestIDs {
+		if seen[guestID] {
+			continue
+		}
+		seen[guestID] = true
+
+		guest, ok := m.findGuest(guestID)
+		if !ok {
+			return nil, fmt.Errorf("guest %s not found", guestID)
+		}
+		key := guest.instance + "/" + guest.node
+		group := byNode[key]
+		if group == nil {
+			group = &nodeGroup{instance: guest.instance, node: guest.node}
+			byNode[key] = group
+			groups = append(groups, group)
+		}
+		group.guests = append(group.guests, BackupJobGuest{
+			ID:   guest.id,
+			Name: guest.name,
+			VMID: guest.vmid,
+			Type: string(guest.guestType),
+		})
+	}
+
+	jobs := make([]BackupJob, 0, len(groups))
+	var firstErr error
+	started := 0
+	for _, group := range groups {
+		job, err := m.startBackupJob(group.instance, group.node, group.guests, opts, trigger, requestedBy)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err == nil {
+			started++
+		}
+		jobs = append(jobs, job)
+	}
+
+	if started == 0 {
+		return jobs, firstErr
+	}
+	return jobs, nil
+}
+
+func (m *Monitor) startBackupJob(instance, node string, guests []BackupJobGuest, opts proxmox.BackupOptions, trigger, requestedBy string) (BackupJob, error) {
+	now := time.Now().UTC()
+	job := &BackupJob{
+		ID:          uuid.NewString(),
+		Instance:    instance,
+		Node:        node,
+		Guests:      guests,
+		Storage:     opts.Storage,
+		Mode:        string(opts.Mode),
+		Compress:    opts.Compress,
+		Trigger:     trigger,
+		Status:      GuestActionStatusRunning,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	client, err := m.backupJobClient(instance)
+	if err == nil {
+		vmids := make([]int, 0, len(guests))
+		for _, guest := range guests {
+			vmids = append(vmids, guest.VMID)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		job.UPID, err = client.StartBackup(ctx, node, vmids, opts)
+		cancel()
+	}
+	if err != nil {
+		job.finish(GuestActionStatusFailed, "", err.Error())
+		m.recordBackupJob(job)
+		log.Warn().
+			Err(err).
+			Str("instance", instance).
+			Str("node", node).
+			Str("trigger", trigger).
+			Msg("Failed to start backup job")
+		return *job, err
+	}
+
+	m.recordBackupJob(job)
+	log.Info().
+		Str("instance", instance).
+		Str("node", node).
+		Int("guests", len(guests)).
+		Str("upid", job.UPID).
+		Str("trigger", trigger).
+		Str("requestedBy", requestedBy).
+		Msg("Backup job started")
+
+	snapshot := *job
+	go m.trackBackupJob(client, job)
+	return snapshot, nil
+}
+
+// trackBackupJob waits for the vzdump task to appear as finished in the node task list.
+// Each poll also refreshes the instance's backup tasks in state.
+func (m *Monitor) trackBackupJob(client BackupJobClient, job *BackupJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), backupJobTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(backupJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.finishBackupJob(job, GuestActionStatusFailed, "", "timed out waiting for backup task to finish")
+			return
+		case <-ticker.C:
+			tasks, err := client.GetBackupTasks(ctx)
+			if err != nil {
+				log.Debug().Err(err).Str("upid", job.UPID).Msg("Failed to poll backup tasks")
+				continue
+			}
+			m.state.UpdateBackupTasksForInstance(job.Instance, convertBackupTasks(job.Instance, tasks))
+
+			for _, task := range tasks {
+				if task.UPID != job.UPID || task.EndTime == 0 {
+					continue
+				}
+				if proxmox.IsBackupTaskSuccess(task.Status) {
+					m.finishBackupJob(job, GuestActionStatusCompleted, task.Status, "")
+				} else {
+					m.finishBackupJob(job, GuestActionStatusFailed, task.Status, task.Status)
+				}
+				return
+			}
+		}
+	}
+}
+
+func (m *Monitor) finishBackupJob(job *BackupJob, status, taskStatus, errMsg string) {
+	m.backupJobsMu.Lock()
+	job.finish(status, taskStatus, errMsg)
+	snapshot := *job
+	m.backupJobsMu.Unlock()
+
+	log.Info().
+		Str("instance", snapshot.Instance).
+		Str("node", snapshot.Node).
+		Str("upid", snapshot.UPID).
+		Str("status", snapshot.Status).
+		Str("taskStatus", snapshot.TaskStatus).
+		Msg("Backup job finished")
+	m.broadcastBackupJob(snapshot)
+}
+
+func (j *BackupJob) finish(status, taskStatus, errMsg string) {
+	now := time.Now().UTC()
+	j.Status = status
+	j.TaskStatus = taskStatus
+	j.Error = errMsg
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+func (m *Monitor) recordBackupJob(job *BackupJob) {
+	m.backupJobsMu.Lock()
+	m.backupJobs = append(m.backupJobs, job)
+	if len(m.backupJobs) > backupJobHistoryLimit {
+		m.backupJobs = m.backupJobs[len(m.backupJobs)-backupJobHistoryLimit:]
+	}
+	snapshot := *job
+	m.backupJobsMu.Unlock()
+
+	m.broadcastBackupJob(snapshot)
+}
+
+func (m *Monitor) broadcastBackupJob(job BackupJob) {
+	if m.wsHub == nil {
+		return
+	}
+	m.wsHub.BroadcastMessage(websocket.Message{
+		Type:      "backupJob",
+		Data:      job,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetBackupJobs returns recent backup jobs, newest first
+func (m *Monitor) GetBackupJobs() []BackupJob {
+	m.backupJobsMu.Lock()
+	defer m.backupJobsMu.Unlock()
+
+	jobs := make([]BackupJob, 0, len(m.backupJobs))
+	for i := len(m.backupJobs) - 1; i >= 0; i-- {
+		jobs = append(jobs, *m.backupJobs[i])
+	}
+	return jobs
+}
+
+// SetBackupRemediationConfig replaces the automatic backup remediation policy
+func (m *Monitor) SetBackupRemediationConfig(cfg config.BackupRemediationConfig) {
+	m.remediationMu.Lock()
+	m.remediationConfig = config.NormalizeBackupRemediationConfig(cfg)
+	m.remediationMu.Unlock()
+}
+
+// GetBackupRemediationConfig returns the automatic backup remediation policy
+func (m *Monitor) GetBackupRemediationConfig() config.BackupRemediationConfig {
+	m.remediationMu.RLock()
+	defer m.remediationMu.RUnlock()
+	return config.NormalizeBackupRemediationConfig(m.remediationConfig)
+}
+
+func (m *Monitor) runBackupRemediationScheduler(ctx context.Context) {
+	if m.persistence != nil {
+		if cfg, err := m.persistence.LoadBackupRemediationConfig(); err == nil {
+			m.SetBackupRemediationConfig(*cfg)
+		} else {
+			log.Warn().Ctx(ctx).Err(err).Msg("Failed to load backup remediation configuration")
+		}
+	}
+
+	notBefore := time.Now().Add(backupRemediationStartupDelay)
+	ticker := time.NewTicker(backupRemediationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if now.Before(notBefore) || m.alertManager == nil {
+				continue
+			}
+			if cfg := m.GetBackupRemediationConfig(); cfg.Enabled {
+				m.remediateStaleBackups(cfg, now)
+			}
+		}
+	}
+}
+
+// remediateStaleBackups starts a backup for each guest with a critical backup-age alert,
+// within the per-node concurrency limit and per-guest cooldown
+func (m *Monitor) remediateStaleBackups(cfg config.BackupRemediationConfig, now time.Time) {
+	candidates := m.staleBackupGuests(m.alertManager.GetActiveAlerts())
+	if len(candidates) == 0 {
+		return
+	}
+
+	m.backupJobsMu.Lock()
+	running := make(map[string]int)
+	inFlight := make(map[string]bool)
+	for _, job := range m.backupJobs {
+		if job.Status != GuestActionStatusRunning {
+			continue
+		}
+		running[job.Instance+"/"+job.Node]++
+		for _, guest := range job.Guests {
+			inFlight[guest.ID] = true
+		}
+	}
+	if m.backupAttempts == nil {
+		m.backupAttempts = make(map[string]time.Time)
+	}
+	selected := planBackupRemediation(cfg, candidates, running, inFlight, m.backupAttempts, now)
+	for _, guest := range selected {
+		m.backupAttempts[guest.id] = now
+	}
+	m.backupJobsMu.Unlock()
+
+	opts := proxmox.BackupOptions{
+		Storage:  cfg.Storage,
+		Mode:     proxmox.BackupMode(cfg.Mode),
+		Compress: cfg.Compress,
+	}
+	for _, guest := range selected {
+		log.Info().
+			Str("guest", guest.id).
+			Str("name", guest.name).
+			Msg("Starting backup for guest with critical backup age")
+		if _, err := m.StartBackupJobs([]string{guest.id}, opts, BackupJobTriggerRemediation, backupRemediationRequester); err != nil {
+			log.Warn().Err(err).Str("guest", guest.id).Msg("Backup remediation failed to start")
+		}
+	}
+}
+
+// staleBackupGuests resolves critical backup-age alerts to guests in the current state
+func (m *Monitor) staleBackupGuests(active []alerts.Alert) []guestTarget {
+	state := m.GetState()
+	byVMID := make(map[string]guestTarget)
+	for _, vm := range state.VMs {
+		byVMID[fmt.Sprintf("%s/%d", vm.Instance, vm.VMID)] = guestTarget{id: vm.ID, name: vm.Name, instance: vm.Instance, node: vm.Node, vmid: vm.VMID, guestType: proxmox.GuestTypeVM}
+	}
+	for _, ct := range state.Containers {
+		byVMID[fmt.Sprintf("%s/%d", ct.Instance, ct.VMID)] = guestTarget{id: ct.ID, name: ct.Name, instance: ct.Instance, node: ct.Node, vmid: ct.VMID, guestType: proxmox.GuestTypeContainer}
+	}
+
+	var guests []guestTarget
+	for _, alert := range active {
+		if alert.Type != "backup-age" || alert.Level != alerts.AlertLevelCritical {
+			continue
+		}
+		vmid, ok := alertMetadataInt(alert.Metadata, "vmid")
+		if !ok {
+			continue
+		}
+		if guest, found := byVMID[fmt.Sprintf("%s/%d", alert.Instance, vmid)]; found {
+			guests = append(guests, guest)
+		}
+	}
+	return guests
+}
+
+// planBackupRemediation picks the guests to back up now. Guests already being backed up,
+// excluded or still in cooldown are skipped, and each node gets at most
+// MaxConcurrentPerNode running backups.
+func planBackupRemediation(cfg config.BackupRemediationConfig, candidates []guestTarget, running map[string]int, inFlight map[string]bool, attempts map[string]time.Time, now time.Time) []guestTarget {
+	excluded := make(map[string]bool, len(cfg.Exclusions))
+	for _, id := range cfg.Exclusions {
+		excluded[id] = true
+	}
+	cooldown := time.Duration(cfg.CooldownHours) * time.Hour
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].id < candidates[j].id
+	})
+
+	slots := make(map[string]int)
+	var selected []guestTarget
+	for _, guest := range candidates {
+		if excluded[guest.id] || inFlight[guest.id] {
+			continue
+		}
+		if last, ok := attempts[guest.id]; ok && now.Sub(last) < cooldown {
+			continue
+		}
+		key := guest.instance + "/" + guest.node
+		if running[key]+slots[key] >= cfg.MaxConcurrentPerNode {
+			continue
+		}
+		slots[key]++
+		inFlight[guest.id] = true
+		selected = append(selected, guest)
+	}
+	return selected
+}
+
+func alertMetadataInt(metadata map[string]interface{}, key string) (int, bool) {
+	switch value := metadata[key].(type) {
+	case int:
+		return value, true
+	case float64:
+		// Alerts restored from disk decode numbers as float64
+		return int(value), true
+	default:
+		return 0, false
+	}
+}
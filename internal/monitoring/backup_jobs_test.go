@@ -0,0 +1,65 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+func TestPlanBackupRemediation(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	guest := func(id, node string) guestTarget {
+		return guestTarget{id: id, instance: "pve1", node: node}
+	}
+
+	cfg := config.NormalizeBackupRemediationConfig(config.BackupRemediationConfig{
+		Enabled:              true,
+		MaxConcurrentPerNode: 2,
+		CooldownHours:        6,
+		Exclusions:           []string{"pve1-node1-105"},
+	})
+
+	candidates := []guestTarget{
+		guest("pve1-node1-101", "node1"),
+		guest("pve1-node1-102", "node1"),
+		guest("pve1-node1-103", "node1"),
+		guest("pve1-node1-104", "node1"),
+		guest("pve1-node1-105", "node1"),
+		guest("pve1-node2-201", "node2"),
+		guest("pve1-node2-202", "node2"),
+		guest("pve1-node3-301", "node3"),
+	}
+	running := map[string]int{"pve1/node2": 2}
+	inFlight := map[string]bool{"pve1-node1-101": true}
+	attempts := map[string]time.Time{
+		"pve1-node1-102": now.Add(-2 * time.Hour),  // still cooling down
+		"pve1-node3-301": now.Add(-12 * time.Hour), // cooldown elapsed
+	}
+
+	selected := planBackupRemediation(cfg, candidates, running, inFlight, attempts, now)
+
+	want := []string{"pve1-node1-103", "pve1-node1-104", "pve1-node3-301"}
+	if len(selected) != len(want) {
+		t.Fatalf("selected %d guests, want %d: %+v", len(selected), len(want), selected)
+	}
+	for i, id := range want {
+		if selected[i].id != id {
+			t.Errorf("selected[%d] = %s, want %s", i, selected[i].id, id)
+		}
+	}
+}
+
+func TestAlertMetadataInt(t *testing.T) {
+	metadata := map[string]interface{}{"vmid": 101, "restored": float64(202), "name": "web"}
+
+	if got, ok := alertMetadataInt(metadata, "vmid"); !ok || got != 101 {
+		t.Errorf("vmid = %d, %t", got, ok)
+	}
+	if got, ok := alertMetadataInt(metadata, "restored"); !ok || got != 202 {
+		t.Errorf("restored = %d, %t", got, ok)
+	}
+	if _, ok := alertMetadataInt(metadata, "name"); ok {
+		t.Error("expected non-numeric metadata to be ignored")
+	}
+}
@@ -0,0 +1,300 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/forecast"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/internal/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// capacitySampleInterval is how often usage is sampled for forecasting
+	capacitySampleInterval = 30 * time.Minute
+	// capacityHistoryRetention is how far back forecasts look
+	capacityHistoryRetention = 30 * 24 * time.Hour
+	// capacityStaleAfter drops resources from forecasts once they stop reporting
+	capacityStaleAfter = 2 * time.Hour
+	// capacitySeasonality is the repeating usage pattern the seasonal model looks for
+	capacitySeasonality = 24 * time.Hour
+	capacityHistoryFile = "capacity_history.json"
+)
+
+// capacitySeries is the sampled usage history of one resource. It is persisted so forecasts
+// survive restarts.
+type capacitySeries struct {
+	Kind       string              `json:"kind"`
+	ResourceID string              `json:"resourceId"`
+	Name       string              `json:"name"`
+	Instance   string              `json:"instance"`
+	Node       string              `json:"node,omitempty"`
+	TotalBytes int64               `json:"totalBytes"`
+	Points     []types.MetricPoint `json:"points"`
+}
+
+func (s *capacitySeries) key() string {
+	return s.Kind + ":" + s.ResourceID
+}
+
+// ForecastFilter selects capacity forecasts. WithinDays keeps only resources projected to be
+// full within that many days; empty fields match everything.
+type ForecastFilter struct {
+	Kind       string
+	Instance   string
+	WithinDays int
+}
+
+func (m *Monitor) runCapacityForecaster(ctx context.Context) {
+	m.loadCapacityHistory()
+	m.updateCapacityForecasts(time.Now())
+
+	ticker := time.NewTicker(capacitySampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.recordCapacitySamples(capacityObservations(m.state.GetSnapshot()), now)
+			forecasts := m.updateCapacityForecasts(now)
+			if m.alertManager != nil {
+				m.alertManager.CheckCapacityForecasts(forecasts)
+			}
+			if err := m.saveCapacityHistory(); err != nil {
+				log.Warn().Ctx(ctx).Err(err).Msg("Failed to save capacity history")
+			}
+		}
+	}
+}
+
+// capacityObservations returns the current usage of every forecastable resource. Usage is a
+// percentage of the resource's capacity.
+func capacityObservations(snapshot models.StateSnapshot) []capacitySeries {
+	var observations []capacitySeries
+	observe := func(series capacitySeries, usage float64) {
+		series.Points = []types.MetricPoint{{Value: usage}}
+		observations = append(observations, series)
+	}
+
+	for _, node := range snapshot.Nodes {
+		if node.Status != "online" || node.Memory.Total <= 0 {
+			continue
+		}
+		observe(capacitySeries{
+			Kind: models.ForecastNodeMemory, ResourceID: node.ID, Name: node.Name,
+			Instance: node.Instance, Node: node.Name, TotalBytes: node.Memory.Total,
+		}, node.Memory.Usage)
+	}
+
+	seenStorage := make(map[string]bool)
+	for _, storage := range snapshot.Storage {
+		if !storage.Enabled || !storage.Active || storage.Total <= 0 || seenStorage[storage.ID] {
+			continue
+		}
+		seenStorage[storage.ID] = true
+		observe(capacitySeries{
+			Kind: models.ForecastStorage, ResourceID: storage.ID, Name: storage.Name,
+			Instance: storage.Instance, Node: storage.Node, TotalBytes: storage.Total,
+		}, storage.Usage)
+	}
+
+	for _, pbs := range snapshot.PBSInstances {
+		for _, datastore := range pbs.Datastores {
+			if datastore.Total <= 0 {
+				continue
+			}
+			observe(capacitySeries{
+				Kind: models.ForecastPBSDatastore, ResourceID: pbs.ID + "-" + datastore.Name, Name: datastore.Name,
+				Instance: pbs.Name, TotalBytes: datastore.Total,
+			}, safePercentage(float64(datastore.Used), float64(datastore.Total)))
+		}
+	}
+
+	for _, cluster := range snapshot.CephClusters {
+		for _, pool := range cluster.Pools {
+			total := pool.StoredBytes + pool.AvailableBytes
+			if total <= 0 {
+				continue
+			}
+			observe(capacitySeries{
+				Kind: models.ForecastCephPool, ResourceID: cluster.ID + "-" + pool.Name, Name: pool.Name,
+				Instance: cluster.Instance, TotalBytes: total,
+			}, safePercentage(float64(pool.StoredBytes), float64(total)))
+		}
+	}
+
+	return observations
+}
+
+// recordCapacitySamples appends the observations to the history and drops samples beyond the retention
+func (m *Monitor) recordCapacitySamples(observations []capacitySeries, now time.Time) {
+	m.forecastMu.Lock()
+	defer m.forecastMu.Unlock()
+
+	if m.capacitySeries == nil {
+		m.capacitySeries = make(map[string]*capacitySeries)
+	}
+	for _, observation := range observations {
+		point := types.MetricPoint{Value: observation.Points[0].Value, Timestamp: now}
+		series, ok := m.capacitySeries[observation.key()]
+		if !ok {
+			observation.Points = []types.MetricPoint{point}
+			m.capacitySeries[observation.key()] = &observation
+			continue
+		}
+		series.Name, series.Instance, series.Node, series.TotalBytes = observation.Name, observation.Instance, observation.Node, observation.TotalBytes
+		// A restart can sample again shortly after the last persisted sample
+		if last := series.Points[len(series.Points)-1]; now.Sub(last.Timestamp) < capacitySampleInterval/2 {
+			continue
+		}
+		series.Points = append(series.Points, point)
+	}
+
+	cutoff := now.Add(-capacityHistoryRetention)
+	for key, series := range m.capacitySeries {
+		first := sort.Search(len(series.Points), func(i int) bool { return !series.Points[i].Timestamp.Before(cutoff) })
+		series.Points = series.Points[first:]
+		if len(series.Points) == 0 {
+			delete(m.capacitySeries, key)
+		}
+	}
+}
+
+// updateCapacityForecasts fits every resource that is still reporting and stores the results
+func (m *Monitor) updateCapacityForecasts(now time.Time) []models.CapacityForecast {
+	m.forecastMu.Lock()
+	defer m.forecastMu.Unlock()
+
+	forecasts := make([]models.CapacityForecast, 0, len(m.capacitySeries))
+	for _, series := range m.capacitySeries {
+		last := series.Points[len(series.Points)-1]
+		if now.Sub(last.Timestamp) > capacityStaleAfter {
+			continue
+		}
+		projection, ok := forecast.Project(series.Points, forecast.Options{Capacity: 100, Period: capacitySeasonality}, now)
+		if !ok {
+			continue
+		}
+
+		entry := models.CapacityForecast{
+			ID:           series.key(),
+			Kind:         series.Kind,
+			ResourceID:   series.ResourceID,
+			Name:         series.Name,
+			Instance:     series.Instance,
+			Node:         series.Node,
+			Model:        projection.Model,
+			Usage:        projection.Current,
+			TotalBytes:   series.TotalBytes,
+			GrowthPerDay: projection.GrowthPerDay,
+			ExhaustionAt: projection.ExhaustionAt,
+			Confidence:   projection.Confidence,
+			Samples:      projection.Samples,
+			SampledSince: series.Points[0].Timestamp,
+			UpdatedAt:    now,
+		}
+		if days := projection.DaysUntilExhaustion(now); days >= 0 {
+			entry.DaysRemaining = &days
+		}
+		forecasts = append(forecasts, entry)
+	}
+
+	sortCapacityForecasts(forecasts)
+	m.forecasts = forecasts
+	return append([]models.CapacityForecast(nil), forecasts...)
+}
+
+// sortCapacityForecasts orders forecasts by soonest exhaustion, then by ID
+func sortCapacityForecasts(forecasts []models.CapacityForecast) {
+	sort.Slice(forecasts, func(i, j int) bool {
+		left, right := forecasts[i].DaysRemaining, forecasts[j].DaysRemaining
+		switch {
+		case left != nil && right != nil && *left != *right:
+			return *left < *right
+		case (left == nil) != (right == nil):
+			return left != nil
+		}
+		return forecasts[i].ID < forecasts[j].ID
+	})
+}
+
+// GetCapacityForecasts returns the latest forecasts matching the filter, soonest exhaustion first
+func (m *Monitor) GetCapacityForecasts(filter ForecastFilter) []models.CapacityForecast {
+	m.forecastMu.Lock()
+	defer m.forecastMu.Unlock()
+
+	result := make([]models.CapacityForecast, 0, len(m.forecasts))
+	for _, entry := range m.forecasts {
+		switch {
+		case filter.Kind != "" && entry.Kind != filter.Kind,
+			filter.Instance != "" && entry.Instance != filter.Instance,
+			filter.WithinDays > 0 && (entry.DaysRemaining == nil || *entry.DaysRemaining > float64(filter.WithinDays)):
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func (m *Monitor) capacityHistoryPath() string {
+	dataPath := "/etc/pulse"
+	if m.config != nil && m.config.DataPath != "" {
+		dataPath = m.config.DataPath
+	}
+	return filepath.Join(dataPath, capacityHistoryFile)
+}
+
+func (m *Monitor) loadCapacityHistory() {
+	data, err := os.ReadFile(m.capacityHistoryPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to read capacity history")
+		}
+		return
+	}
+
+	var stored []*capacitySeries
+	if err := json.Unmarshal(data, &stored); err != nil {
+		log.Warn().Err(err).Msg("Discarding unreadable capacity history")
+		return
+	}
+
+	m.forecastMu.Lock()
+	defer m.forecastMu.Unlock()
+	m.capacitySeries = make(map[string]*capacitySeries, len(stored))
+	for _, series := range stored {
+		if series != nil && len(series.Points) > 0 {
+			m.capacitySeries[series.key()] = series
+		}
+	}
+}
+
+func (m *Monitor) saveCapacityHistory() error {
+	m.forecastMu.Lock()
+	stored := make([]*capacitySeries, 0, len(m.capacitySeries))
+	for _, series := range m.capacitySeries {
+		stored = append(stored, series)
+	}
+	data, err := json.Marshal(stored)
+	m.forecastMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	historyPath := m.capacityHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		return err
+	}
+	tmp := historyPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, historyPath)
+}
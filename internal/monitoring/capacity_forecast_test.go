@@ -0,0 +1,106 @@
+package monitoring
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/internal/types"
+)
+
+func TestCapacityObservations(t *testing.T) {
+	snapshot := models.StateSnapshot{
+		Nodes: []models.Node{
+			{ID: "pve-pve1", Name: "pve1", Instance: "pve", Status: "online", Memory: models.Memory{Total: 64 << 30, Usage: 50}},
+			{ID: "pve-pve2", Name: "pve2", Instance: "pve", Status: "offline", Memory: models.Memory{Total: 64 << 30, Usage: 10}},
+		},
+		Storage: []models.Storage{
+			{ID: "pve-shared", Name: "shared", Instance: "pve", Node: "pve1", Total: 1000, Usage: 40, Enabled: true, Active: true},
+			{ID: "pve-shared", Name: "shared", Instance: "pve", Node: "pve2", Total: 1000, Usage: 40, Enabled: true, Active: true},
+			{ID: "pve-pve1-disabled", Name: "disabled", Instance: "pve", Node: "pve1", Total: 1000, Usage: 90},
+		},
+		PBSInstances: []models.PBSInstance{
+			{ID: "pbs-main", Name: "main", Datastores: []models.PBSDatastore{{Name: "store", Total: 200, Used: 50}}},
+		},
+		CephClusters: []models.CephCluster{
+			{ID: "pve-ceph", Instance: "pve", Pools: []models.CephPool{{Name: "rbd", StoredBytes: 300, AvailableBytes: 100}}},
+		},
+	}
+
+	usage := make(map[string]float64)
+	for _, observation := range capacityObservations(snapshot) {
+		usage[observation.key()] = observation.Points[0].Value
+	}
+	want := map[string]float64{
+		models.ForecastNodeMemory + ":pve-pve1":         50,
+		models.ForecastStorage + ":pve-shared":          40,
+		models.ForecastPBSDatastore + ":pbs-main-store": 25,
+		models.ForecastCephPool + ":pve-ceph-rbd":       75,
+	}
+	if len(usage) != len(want) {
+		t.Fatalf("expected %d observations, got %v", len(want), usage)
+	}
+	for key, value := range want {
+		if usage[key] != value {
+			t.Errorf("%s: expected usage %.0f, got %.0f", key, value, usage[key])
+		}
+	}
+}
+
+func TestCapacityForecastsFromSamples(t *testing.T) {
+	m := &Monitor{config: &config.Config{DataPath: t.TempDir()}}
+	start := time.Unix(1700000000, 0)
+
+	observe := func(id string, usage float64) capacitySeries {
+		return capacitySeries{
+			Kind: models.ForecastStorage, ResourceID: id, Name: id, Instance: "pve",
+			Points: []types.MetricPoint{{Value: usage}},
+		}
+	}
+
+	// Two days of half-hourly samples, one storage growing 12 points per day
+	var now time.Time
+	for i := 0; i < 96; i++ {
+		now = start.Add(time.Duration(i) * capacitySampleInterval)
+		m.recordCapacitySamples([]capacitySeries{observe("pve-data", 60+float64(i)*0.25), observe("pve-flat", 30)}, now)
+	}
+	// A repeated sample right after a restart is ignored
+	m.recordCapacitySamples([]capacitySeries{observe("pve-data", 99)}, now.Add(time.Minute))
+
+	forecasts := m.updateCapacityForecasts(now)
+	if len(forecasts) != 2 {
+		t.Fatalf("expected 2 forecasts, got %d", len(forecasts))
+	}
+	growing := forecasts[0]
+	if growing.ResourceID != "pve-data" || growing.DaysRemaining == nil || growing.Samples != 96 {
+		t.Fatalf("expected growing storage first with an exhaustion date, got %+v", growing)
+	}
+	// 83.75% used growing 12 points per day
+	if math.Abs(*growing.DaysRemaining-16.25/12) > 0.01 {
+		t.Errorf("expected exhaustion in ~1.35 days, got %f", *growing.DaysRemaining)
+	}
+	if forecasts[1].DaysRemaining != nil {
+		t.Errorf("expected flat storage to have no exhaustion date, got %f", *forecasts[1].DaysRemaining)
+	}
+
+	within := m.GetCapacityForecasts(ForecastFilter{WithinDays: 7})
+	if len(within) != 1 || within[0].ResourceID != "pve-data" {
+		t.Errorf("expected only the growing storage within 7 days, got %+v", within)
+	}
+
+	if err := m.saveCapacityHistory(); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+	restored := &Monitor{config: m.config}
+	restored.loadCapacityHistory()
+	if len(restored.capacitySeries) != 2 || len(restored.capacitySeries[models.ForecastStorage+":pve-data"].Points) != 96 {
+		t.Errorf("expected history to survive a restart, got %d series", len(restored.capacitySeries))
+	}
+
+	// Resources that stop reporting drop out of the forecasts
+	if stale := m.updateCapacityForecasts(now.Add(3 * time.Hour)); len(stale) != 0 {
+		t.Errorf("expected stale series to be skipped, got %d forecasts", len(stale))
+	}
+}
@@ -0,0 +1,173 @@
+package monitoring
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/rs/zerolog/log"
+)
+
+// ClusterHAClient is implemented by PVE clients that can read corosync and HA manager state.
+type ClusterHAClient interface {
+	GetClusterStatus(ctx context.Context) ([]proxmox.ClusterStatus, error)
+	GetClusterConfigNodes(ctx context.Context) ([]proxmox.ClusterConfigNode, error)
+	GetHAStatus(ctx context.Context) ([]proxmox.HAStatusEntry, error)
+}
+
+// pollClusterHA gathers quorum and HA manager state for clustered instances.
+func (m *Monitor) pollClusterHA(ctx context.Context, instanceName string, client PVEClientInterface, isCluster bool) {
+	if !isCluster {
+		m.state.UpdateClusterHAForInstance(instanceName, nil)
+		if m.alertManager != nil {
+			m.alertManager.ClearClusterHAAlerts(instanceName)
+		}
+		return
+	}
+
+	haClient, ok := client.(ClusterHAClient)
+	if !ok {
+		return
+	}
+
+	haCtx := ctx
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > 15*time.Second {
+		var cancel context.CancelFunc
+		haCtx, cancel = context.WithTimeout(ctx, 15*time.Second)
+		defer cancel()
+	}
+
+	clusterStatus, err := haClient.GetClusterStatus(haCtx)
+	if err != nil {
+		log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Cluster status unavailable – preserving previous quorum state")
+		return
+	}
+
+	configNodes, err := haClient.GetClusterConfigNodes(haCtx)
+	if err != nil {
+		log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Corosync node list unavailable – assuming one vote per node")
+	}
+
+	haEntries, haErr := haClient.GetHAStatus(haCtx)
+	if haErr != nil {
+		log.Debug().Ctx(ctx).Err(haErr).Str("instance", instanceName).Msg("HA status unavailable – preserving previous HA state")
+	}
+
+	status := buildClusterHAModel(instanceName, clusterStatus, configNodes, haEntries, time.Now())
+	if haErr != nil {
+		// Keep the last known HA view so a transient failure doesn't clear its alerts
+		for _, previous := range m.state.GetSnapshot().ClusterHA {
+			if previous.Instance == instanceName {
+				status.ManagerNode = previous.ManagerNode
+				status.ManagerState = previous.ManagerState
+				status.LRMs = previous.LRMs
+				status.Resources = previous.Resources
+				break
+			}
+		}
+	}
+
+	m.state.UpdateClusterHAForInstance(instanceName, &status)
+
+	if m.alertManager != nil {
+		m.traceAlertCheck(ctx, "CheckClusterHA", instanceName, func() { m.alertManager.CheckClusterHA(status) })
+	}
+}
+
+// buildClusterHAModel converts corosync and HA responses into the shared model.
+// Expected votes come from the corosync node list; nodes without an explicit vote count as one.
+func buildClusterHAModel(instanceName string, clusterStatus []proxmox.ClusterStatus, configNodes []proxmox.ClusterConfigNode, haEntries []proxmox.HAStatusEntry, now time.Time) models.ClusterHAStatus {
+	status := models.ClusterHAStatus{
+		ID:          instanceName,
+		Instance:    instanceName,
+		LastUpdated: now,
+	}
+
+	votes := make(map[string]int, len(configNodes))
+	for _, node := range configNodes {
+		vote := int(node.QuorumVotes)
+		if vote <= 0 {
+			vote = 1
+		}
+		votes[node.Node] = vote
+		status.ExpectedVotes += vote
+	}
+
+	quorateKnown := false
+	for _, entry := range clusterStatus {
+		switch entry.Type {
+		case "cluster":
+			status.ClusterName = entry.Name
+			status.Quorate = entry.Quorate == 1
+			status.ConfigVersion = entry.Version
+			quorateKnown = true
+		case "node":
+			vote, ok := votes[entry.Name]
+			if !ok {
+				vote = 1
+				if len(configNodes) == 0 {
+					status.ExpectedVotes += vote
+				}
+			}
+			member := models.ClusterMember{
+				Name:   entry.Name,
+				NodeID: entry.Nodeid,
+				IP:     entry.IP,
+				Online: entry.Online == 1,
+				Votes:  vote,
+			}
+			if member.Online {
+				status.TotalVotes += vote
+			}
+			status.Members = append(status.Members, member)
+		}
+	}
+
+	status.QuorumThreshold = status.ExpectedVotes/2 + 1
+	if !quorateKnown {
+		status.Quorate = status.TotalVotes >= status.QuorumThreshold
+	}
+	if status.ClusterName != "" {
+		status.ID = instanceName + "-" + status.ClusterName
+	}
+
+	for _, entry := range haEntries {
+		switch entry.Type {
+		case "master":
+			status.ManagerNode = entry.Node
+			status.ManagerState = entry.DaemonState()
+		case "lrm":
+			state := entry.DaemonState()
+			lrm := models.HALRMStatus{
+				Node:  entry.Node,
+				State: state,
+				Dead:  strings.Contains(strings.ToLower(state), "dead"),
+			}
+			if entry.Timestamp > 0 {
+				lrm.Timestamp = time.Unix(entry.Timestamp, 0)
+			}
+			status.LRMs = append(status.LRMs, lrm)
+		case "service":
+			resource := models.HAResourceState{
+				SID:          entry.SID,
+				Node:         entry.Node,
+				State:        entry.State,
+				CRMState:     entry.CRMState,
+				RequestState: entry.RequestState,
+				Group:        entry.Group,
+				MaxRestart:   int(entry.MaxRestart),
+				MaxRelocate:  int(entry.MaxRelocate),
+			}
+			if resourceType, id, ok := strings.Cut(entry.SID, ":"); ok {
+				resource.Type = resourceType
+				resource.VMID, _ = strconv.Atoi(id)
+			}
+			status.Resources = append(status.Resources, resource)
+		}
+	}
+
+	return status
+}
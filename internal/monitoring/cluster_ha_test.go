@@ -0,0 +1,75 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+)
+
+func TestBuildClusterHAModel(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clusterStatus := []proxmox.ClusterStatus{
+		{Type: "cluster", Name: "prod", Quorate: 0, Nodes: 3, Version: 7},
+		{Type: "node", Name: "pve1", Nodeid: 1, Online: 1, IP: "10.0.0.1"},
+		{Type: "node", Name: "pve2", Nodeid: 2, Online: 0, IP: "10.0.0.2"},
+		{Type: "node", Name: "pve3", Nodeid: 3, Online: 0, IP: "10.0.0.3"},
+	}
+	configNodes := []proxmox.ClusterConfigNode{
+		{Node: "pve1", NodeID: 1, QuorumVotes: 1},
+		{Node: "pve2", NodeID: 2, QuorumVotes: 1},
+		{Node: "pve3", NodeID: 3, QuorumVotes: 2},
+	}
+	haEntries := []proxmox.HAStatusEntry{
+		{ID: "quorum", Type: "quorum", Node: "pve1", Status: "No quorum on node 'pve1'!", Quorate: 0},
+		{ID: "master", Type: "master", Node: "pve1", Status: "pve1 (active, Tue Nov 14 22:13:20 2023)"},
+		{ID: "lrm:pve1", Type: "lrm", Node: "pve1", Status: "pve1 (active, Tue Nov 14 22:13:20 2023)", Timestamp: 1700000000},
+		{ID: "lrm:pve2", Type: "lrm", Node: "pve2", Status: "pve2 (old timestamp - dead?, Tue Nov 14 21:00:00 2023)"},
+		{ID: "service:vm:100", Type: "service", SID: "vm:100", Node: "pve2", State: "fence", CRMState: "stopped", RequestState: "started", MaxRestart: 1},
+		{ID: "service:ct:200", Type: "service", SID: "ct:200", Node: "pve1", State: "started", RequestState: "started"},
+	}
+
+	status := buildClusterHAModel("pve-prod", clusterStatus, configNodes, haEntries, now)
+
+	if status.ID != "pve-prod-prod" || status.ClusterName != "prod" || status.ConfigVersion != 7 {
+		t.Fatalf("unexpected cluster identity: %+v", status)
+	}
+	if status.Quorate {
+		t.Error("expected cluster to be reported without quorum")
+	}
+	if status.ExpectedVotes != 4 || status.TotalVotes != 1 || status.QuorumThreshold != 3 {
+		t.Errorf("votes = expected %d total %d threshold %d, want 4/1/3", status.ExpectedVotes, status.TotalVotes, status.QuorumThreshold)
+	}
+	if len(status.Members) != 3 || status.Members[2].Votes != 2 || status.Members[0].IP != "10.0.0.1" {
+		t.Errorf("unexpected members: %+v", status.Members)
+	}
+	if status.ManagerNode != "pve1" || status.ManagerState != "active" {
+		t.Errorf("manager = %s/%s, want pve1/active", status.ManagerNode, status.ManagerState)
+	}
+	if len(status.LRMs) != 2 || status.LRMs[0].Dead || !status.LRMs[1].Dead || status.LRMs[0].Timestamp.IsZero() {
+		t.Errorf("unexpected LRMs: %+v", status.LRMs)
+	}
+	if len(status.Resources) != 2 {
+		t.Fatalf("expected 2 HA resources, got %d", len(status.Resources))
+	}
+	if r := status.Resources[0]; r.Type != "vm" || r.VMID != 100 || r.State != "fence" || r.MaxRestart != 1 {
+		t.Errorf("unexpected resource: %+v", r)
+	}
+}
+
+func TestBuildClusterHAModelWithoutVoteConfig(t *testing.T) {
+	clusterStatus := []proxmox.ClusterStatus{
+		{Type: "node", Name: "pve1", Online: 1},
+		{Type: "node", Name: "pve2", Online: 1},
+		{Type: "node", Name: "pve3", Online: 0},
+	}
+
+	status := buildClusterHAModel("pve", clusterStatus, nil, nil, time.Now())
+
+	if status.ExpectedVotes != 3 || status.TotalVotes != 2 || status.QuorumThreshold != 2 {
+		t.Errorf("votes = expected %d total %d threshold %d, want 3/2/2", status.ExpectedVotes, status.TotalVotes, status.QuorumThreshold)
+	}
+	if !status.Quorate {
+		t.Error("expected quorum to be derived from votes when the cluster entry is missing")
+	}
+}
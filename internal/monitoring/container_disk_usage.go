@@ -74,7 +74,7 @@ func (m *Monitor) collectContainerRootUsage(ctx context.Context, client PVEClien
 
 	storages, err := client.GetStorage(ctx, node)
 	if err != nil {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Err(err).
 			Str("node", node).
 			Msg("Unable to list storages for container disk overrides")
@@ -88,7 +88,7 @@ func (m *Monitor) collectContainerRootUsage(ctx context.Context, client PVEClien
 
 		contents, err := client.GetStorageContent(ctx, node, storage.Storage)
 		if err != nil {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Err(err).
 				Str("node", node).
 				Str("storage", storage.Storage).
@@ -0,0 +1,43 @@
+package monitoring
+
+import (
+	"context"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/discovery"
+	"github.com/RouXx67/PulseUp/internal/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// discoveryConfig supplies the current discovery settings to the discovery service and sweeps
+func (m *Monitor) discoveryConfig() config.DiscoveryConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.config == nil {
+		return config.DefaultDiscoveryConfig()
+	}
+	return config.CloneDiscoveryConfig(m.config.Discovery)
+}
+
+// GetDiscoveryOnboarder returns the onboarder that turns discovered servers into nodes
+func (m *Monitor) GetDiscoveryOnboarder() *discovery.Onboarder {
+	return m.discoveryOnboarder
+}
+
+func (m *Monitor) runDiscoveryOnboarding(ctx context.Context, wsHub *websocket.Hub) {
+	if m.discoveryOnboarder == nil {
+		return
+	}
+	m.discoveryOnboarder.SetWebSocketHub(wsHub)
+
+	if m.persistence != nil {
+		cfg, err := m.persistence.LoadDiscoveryOnboardingConfig()
+		if err != nil {
+			log.Warn().Ctx(ctx).Err(err).Msg("Failed to load discovery onboarding configuration")
+		} else {
+			m.discoveryOnboarder.SetConfig(*cfg)
+		}
+	}
+
+	m.discoveryOnboarder.Run(ctx)
+}
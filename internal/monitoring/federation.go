@@ -0,0 +1,290 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/federation"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+var federationBackoff = backoffConfig{
+	Initial:    2 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+	Max:        2 * time.Minute,
+}
+
+// federatedSite is the last known state of a downstream Pulse instance
+type federatedSite struct {
+	cfg          config.FederationSite
+	client       *federation.Client
+	cancel       context.CancelFunc
+	connected    bool
+	lastUpdate   time.Time
+	lastError    string
+	pulseVersion string
+	snapshot     models.StateSnapshot // Namespaced
+	hasSnapshot  bool
+}
+
+// SetFederationConfig replaces the downstream sites, restarting the streams of changed sites
+func (m *Monitor) SetFederationConfig(cfg config.FederationConfig) {
+	cfg = config.NormalizeFederationConfig(cfg)
+
+	m.mu.RLock()
+	ctx := m.runtimeCtx
+	m.mu.RUnlock()
+
+	m.federationMu.Lock()
+	defer m.federationMu.Unlock()
+
+	previous := m.federationConfig
+	m.federationConfig = cfg
+	if m.federationSites == nil {
+		m.federationSites = make(map[string]*federatedSite)
+	}
+
+	wanted := make(map[string]config.FederationSite)
+	if cfg.Enabled {
+		for _, site := range cfg.Sites {
+			if !site.Disabled {
+				wanted[site.ID] = site
+			}
+		}
+	}
+
+	for id, site := range m.federationSites {
+		next, ok := wanted[id]
+		if ok && next == site.cfg && previous.IntervalSeconds == cfg.IntervalSeconds {
+			continue
+		}
+		if site.cancel != nil {
+			site.cancel()
+		}
+		delete(m.federationSites, id)
+	}
+
+	if ctx == nil {
+		// Streams start once the monitor is running
+		return
+	}
+	for id, siteCfg := range wanted {
+		if _, running := m.federationSites[id]; running {
+			continue
+		}
+		m.startFederatedSiteLocked(ctx, siteCfg, time.Duration(cfg.IntervalSeconds)*time.Second)
+	}
+}
+
+// GetFederationConfig returns the downstream sites
+func (m *Monitor) GetFederationConfig() config.FederationConfig {
+	m.federationMu.RLock()
+	defer m.federationMu.RUnlock()
+	return config.NormalizeFederationConfig(m.federationConfig)
+}
+
+func (m *Monitor) startFederatedSiteLocked(parent context.Context, cfg config.FederationSite, interval time.Duration) {
+	site := &federatedSite{cfg: cfg}
+	m.federationSites[cfg.ID] = site
+
+	token, err := config.DefaultCredentialResolver().ResolveReference(cfg.Token, "federation."+cfg.ID+".token")
+	if err != nil {
+		site.lastError = err.Error()
+		log.Warn().Err(err).Str("site", cfg.ID).Msg("Failed to resolve federation token")
+		return
+	}
+	site.client = federation.NewClient(cfg.URL, token, cfg.VerifySSL, cfg.Fingerprint)
+
+	ctx, cancel := context.WithCancel(parent)
+	site.cancel = cancel
+	go m.runFederatedSite(ctx, site, interval)
+}
+
+// runFederatedSite keeps a stream open to a downstream instance, reconnecting with backoff
+func (m *Monitor) runFederatedSite(ctx context.Context, site *federatedSite, interval time.Duration) {
+	attempt := 0
+	for {
+		received := false
+		err := site.client.Stream(ctx, interval, func(envelope federation.Envelope) {
+			received = true
+			m.federationMu.Lock()
+			site.connected = true
+			site.lastError = ""
+			site.lastUpdate = time.Now()
+			site.pulseVersion = envelope.PulseVersion
+			site.snapshot = federation.Namespace(site.cfg.ID, envelope.State)
+			site.hasSnapshot = true
+			m.federationMu.Unlock()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.federationMu.Lock()
+		site.connected = false
+		site.lastError = err.Error()
+		m.federationMu.Unlock()
+
+		if received {
+			attempt = 0
+		}
+		delay := federationBackoff.nextDelay(attempt, rand.Float64())
+		attempt++
+		log.Warn().Ctx(ctx).
+			Err(err).
+			Str("site", site.cfg.ID).
+			Dur("retryIn", delay).
+			Msg("Federation stream interrupted")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (m *Monitor) runFederation(ctx context.Context) {
+	if m.persistence == nil {
+		return
+	}
+	cfg, err := m.persistence.LoadFederationConfig()
+	if err != nil {
+		log.Warn().Ctx(ctx).Err(err).Msg("Failed to load federation configuration")
+		return
+	}
+	m.SetFederationConfig(*cfg)
+
+	<-ctx.Done()
+	m.federationMu.Lock()
+	for _, site := range m.federationSites {
+		if site.cancel != nil {
+			site.cancel()
+		}
+	}
+	m.federationSites = nil
+	m.federationMu.Unlock()
+}
+
+// isStaleLocked reports whether a site has not sent a snapshot within the stale window
+func (m *Monitor) isStaleLocked(site *federatedSite, now time.Time) bool {
+	staleAfter := time.Duration(m.federationConfig.StaleSeconds) * time.Second
+	return !site.hasSnapshot || now.Sub(site.lastUpdate) > staleAfter
+}
+
+// GetFederationSites returns the health of every configured downstream site
+func (m *Monitor) GetFederationSites() []models.FederationSiteStatus {
+	m.federationMu.RLock()
+	defer m.federationMu.RUnlock()
+
+	now := time.Now()
+	statuses := make([]models.FederationSiteStatus, 0, len(m.federationConfig.Sites))
+	for _, cfg := range m.federationConfig.Sites {
+		status := models.FederationSiteStatus{
+			ID:      cfg.ID,
+			Name:    cfg.DisplayName(),
+			URL:     cfg.URL,
+			Enabled: m.federationConfig.Enabled && !cfg.Disabled,
+		}
+		if site, ok := m.federationSites[cfg.ID]; ok {
+			status.Connected = site.connected
+			status.Stale = m.isStaleLocked(site, now)
+			status.LastError = site.lastError
+			status.PulseVersion = site.pulseVersion
+			if site.hasSnapshot {
+				lastUpdate := site.lastUpdate
+				status.LastUpdate = &lastUpdate
+				status.Nodes = len(site.snapshot.Nodes)
+				status.Guests = len(site.snapshot.VMs) + len(site.snapshot.Containers)
+				status.ActiveAlerts = len(site.snapshot.ActiveAlerts)
+				for _, healthy := range site.snapshot.ConnectionHealth {
+					if !healthy {
+						status.OfflineInstances++
+					}
+				}
+			}
+		} else {
+			status.Stale = status.Enabled
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ID < statuses[j].ID })
+	return statuses
+}
+
+// GetFederatedState returns the local state merged with the last known state of every site
+func (m *Monitor) GetFederatedState() models.StateSnapshot {
+	local := m.GetState()
+
+	m.federationMu.RLock()
+	now := time.Now()
+	sites := make(map[string]models.StateSnapshot, len(m.federationSites))
+	stale := make(map[string]bool, len(m.federationSites))
+	for id, site := range m.federationSites {
+		if !site.hasSnapshot {
+			continue
+		}
+		sites[id] = site.snapshot
+		stale[id] = m.isStaleLocked(site, now)
+	}
+	m.federationMu.RUnlock()
+
+	return federation.Merge(local, sites, stale)
+}
+
+// AcknowledgeFederatedAlert forwards an acknowledgement of a site's alert to its downstream
+// instance. alertID is the namespaced ID from the global view.
+func (m *Monitor) AcknowledgeFederatedAlert(ctx context.Context, alertID string, acknowledged bool, user string) error {
+	siteID, downstreamID, ok := federation.Split(alertID)
+	if !ok {
+		return fmt.Errorf("alert %q does not belong to a federated site", alertID)
+	}
+
+	m.federationMu.RLock()
+	site, ok := m.federationSites[siteID]
+	var client *federation.Client
+	if ok {
+		client = site.client
+	}
+	m.federationMu.RUnlock()
+	if client == nil {
+		return fmt.Errorf("site %q is not connected", siteID)
+	}
+
+	if err := client.Acknowledge(ctx, federation.AckRequest{
+		AlertID:      downstreamID,
+		Acknowledged: acknowledged,
+		User:         user,
+	}); err != nil {
+		return err
+	}
+
+	// Reflect the change until the next snapshot arrives. The alerts are copied because merged
+	// views may still reference the current slice.
+	m.federationMu.Lock()
+	alerts := append([]models.Alert(nil), site.snapshot.ActiveAlerts...)
+	site.snapshot.ActiveAlerts = alerts
+	for i := range alerts {
+		alert := &alerts[i]
+		if alert.ID != alertID {
+			continue
+		}
+		alert.Acknowledged = acknowledged
+		if acknowledged {
+			now := time.Now()
+			alert.AckTime = &now
+			alert.AckUser = user
+		} else {
+			alert.AckTime = nil
+			alert.AckUser = ""
+		}
+	}
+	m.federationMu.Unlock()
+	return nil
+}
@@ -0,0 +1,227 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// GuestActionStatusRunning indicates Proxmox accepted the action and the task is running.
+	GuestActionStatusRunning = "running"
+	// GuestActionStatusCompleted indicates the task finished successfully.
+	GuestActionStatusCompleted = "completed"
+	// GuestActionStatusFailed indicates the task could not be started or finished with an error.
+	GuestActionStatusFailed = "failed"
+)
+
+const (
+	guestActionTimeout   = 10 * time.Minute
+	guestMigrateTimeout  = 2 * time.Hour
+	guestActionLogLength = 50
+)
+
+// GuestActionClient is implemented by PVE clients that can run guest power actions.
+// It is kept separate from PVEClientInterface so read-only fakes don't need it.
+type GuestActionClient interface {
+	StartGuestAction(ctx context.Context, node string, guestType proxmox.GuestType, vmid int, action proxmox.GuestAction, opts proxmox.GuestActionOptions) (string, error)
+	WaitForTask(ctx context.Context, node, upid string) (*proxmox.TaskResult, error)
+	FollowTask(ctx context.Context, node, upid string, onProgress func(proxmox.TaskResult)) (*proxmox.TaskResult, error)
+}
+
+// GuestActionStatus tracks a single power or lifecycle action on a guest
+type GuestActionStatus struct {
+	ID          string     `json:"id"`
+	GuestID     string     `json:"guestId"`
+	GuestName   string     `json:"guestName"`
+	Instance    string     `json:"instance"`
+	Node        string     `json:"node"`
+	VMID        int        `json:"vmid"`
+	Type        string     `json:"type"`
+	Action      string     `json:"action"`
+	TargetNode  string     `json:"targetNode,omitempty"`
+	Snapshot    string     `json:"snapshot,omitempty"`
+	Status      string     `json:"status"`
+	UPID        string     `json:"upid,omitempty"`
+	ExitStatus  string     `json:"exitStatus,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Log         []string   `json:"log,omitempty"`
+	RequestedBy string     `json:"requestedBy,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
+
+type guestTarget struct {
+	id        string
+	name      string
+	instance  string
+	node      string
+	vmid      int
+	guestType proxmox.GuestType
+}
+
+// findGuest resolves a guest ID from the current state
+func (m *Monitor) findGuest(guestID string) (guestTarget, bool) {
+	state := m.GetState()
+	for _, vm := range state.VMs {
+		if vm.ID == guestID {
+			return guestTarget{id: vm.ID, name: vm.Name, instance: vm.Instance, node: vm.Node, vmid: vm.VMID, guestType: proxmox.GuestTypeVM}, true
+		}
+	}
+	for _, ct := range state.Containers {
+		if ct.ID == guestID {
+			return guestTarget{id: ct.ID, name: ct.Name, instance: ct.Instance, node: ct.Node, vmid: ct.VMID, guestType: proxmox.GuestTypeContainer}, true
+		}
+	}
+	return guestTarget{}, false
+}
+
+func (m *Monitor) guestActionClient(instance string) (GuestActionClient, error) {
+	m.mu.RLock()
+	client, ok := m.pveClients[instance]
+	m.mu.RUnlock()
+	if !ok || client == nil {
+		return nil, fmt.Errorf("no client for instance %s", instance)
+	}
+	actionClient, ok := client.(GuestActionClient)
+	if !ok {
+		return nil, fmt.Errorf("client for instance %s does not support guest actions", instance)
+	}
+	return actionClient, nil
+}
+
+// StartGuestAction submits an action for a guest and follows its task in the background.
+// The returned status reflects submission; onUpdate receives running updates as the task log
+// grows and the final status once the task ends.
+func (m *Monitor) StartGuestAction(guestID string, action proxmox.GuestAction, opts proxmox.GuestActionOptions, requestedBy string, onUpdate func(GuestActionStatus)) (GuestActionStatus, error) {
+	guest, client, err := m.resolveGuestAction(guestID)
+	if err != nil {
+		return GuestActionStatus{}, err
+	}
+
+	status := newGuestActionStatus(guest, string(action), requestedBy)
+	status.TargetNode = opts.TargetNode
+
+	timeout := guestActionTimeout
+	if action == proxmox.GuestActionMigrate {
+		timeout = guestMigrateTimeout
+	}
+
+	return m.runGuestTask(client, status, timeout, func(ctx context.Context) (string, error) {
+		return client.StartGuestAction(ctx, guest.node, guest.guestType, guest.vmid, action, opts)
+	}, onUpdate)
+}
+
+func (m *Monitor) resolveGuestAction(guestID string) (guestTarget, GuestActionClient, error) {
+	guest, ok := m.findGuest(guestID)
+	if !ok {
+		return guestTarget{}, nil, fmt.Errorf("guest %s not found", guestID)
+	}
+	client, err := m.guestActionClient(guest.instance)
+	if err != nil {
+		return guestTarget{}, nil, err
+	}
+	return guest, client, nil
+}
+
+func newGuestActionStatus(guest guestTarget, action, requestedBy string) GuestActionStatus {
+	now := time.Now().UTC()
+	return GuestActionStatus{
+		ID:          uuid.NewString(),
+		GuestID:     guest.id,
+		GuestName:   guest.name,
+		Instance:    guest.instance,
+		Node:        guest.node,
+		VMID:        guest.vmid,
+		Type:        string(guest.guestType),
+		Action:      action,
+		Status:      GuestActionStatusRunning,
+		RequestedBy: requestedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// runGuestTask submits a Proxmox task and follows it in the background
+func (m *Monitor) runGuestTask(client GuestActionClient, status GuestActionStatus, timeout time.Duration, submit func(context.Context) (string, error), onUpdate func(GuestActionStatus)) (GuestActionStatus, error) {
+	submitCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	upid, err := submit(submitCtx)
+	cancel()
+	if err != nil {
+		status.finish(GuestActionStatusFailed, "", err.Error(), nil)
+		return status, err
+	}
+	status.UPID = upid
+
+	log.Info().
+		Str("guest", status.GuestID).
+		Str("action", status.Action).
+		Str("upid", upid).
+		Str("requestedBy", status.RequestedBy).
+		Msg("Guest action submitted")
+
+	go func(status GuestActionStatus) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		result, err := client.FollowTask(ctx, status.Node, upid, func(progress proxmox.TaskResult) {
+			if onUpdate == nil {
+				return
+			}
+			status.progress(progress.Log)
+			onUpdate(status)
+		})
+		switch {
+		case err != nil:
+			status.finish(GuestActionStatusFailed, "", fmt.Sprintf("failed to follow task: %v", err), nil)
+		case result.Succeeded():
+			status.finish(GuestActionStatusCompleted, result.ExitStatus, "", result.Log)
+		default:
+			status.finish(GuestActionStatusFailed, result.ExitStatus, result.ExitStatus, result.Log)
+		}
+
+		log.Info().
+			Str("guest", status.GuestID).
+			Str("action", status.Action).
+			Str("upid", status.UPID).
+			Str("status", status.Status).
+			Str("exitStatus", status.ExitStatus).
+			Msg("Guest action finished")
+
+		if onUpdate != nil {
+			onUpdate(status)
+		}
+	}(status)
+
+	return status, nil
+}
+
+// progress records the log of a task that is still running
+func (s *GuestActionStatus) progress(lines []string) {
+	s.Log = tailLog(lines)
+	s.UpdatedAt = time.Now().UTC()
+}
+
+func (s *GuestActionStatus) finish(state, exitStatus, errMsg string, lines []string) {
+	now := time.Now().UTC()
+	s.Status = state
+	s.ExitStatus = exitStatus
+	s.Error = strings.TrimSpace(errMsg)
+	s.Log = tailLog(lines)
+	s.UpdatedAt = now
+	s.FinishedAt = &now
+}
+
+// tailLog keeps the last guestActionLogLength lines of a task log
+func tailLog(lines []string) []string {
+	if len(lines) > guestActionLogLength {
+		return lines[len(lines)-guestActionLogLength:]
+	}
+	return lines
+}
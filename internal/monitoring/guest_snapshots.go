@@ -0,0 +1,114 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+)
+
+// Snapshot operations reported through GuestActionStatus
+const (
+	GuestActionSnapshot       = "snapshot"
+	GuestActionDeleteSnapshot = "delete-snapshot"
+	GuestActionRollback       = "rollback"
+)
+
+// GuestSnapshotClient is implemented by PVE clients that can manage guest snapshots.
+type GuestSnapshotClient interface {
+	GuestActionClient
+	CreateSnapshot(ctx context.Context, node string, guestType proxmox.GuestType, vmid int, name, description string, vmState bool) (string, error)
+	DeleteSnapshot(ctx context.Context, node string, guestType proxmox.GuestType, vmid int, name string) (string, error)
+	RollbackSnapshot(ctx context.Context, node string, guestType proxmox.GuestType, vmid int, name string) (string, error)
+}
+
+// guestSnapshotID matches the snapshot IDs built by pollGuestSnapshots
+func guestSnapshotID(instance, node string, vmid int, name string) string {
+	return fmt.Sprintf("%s-%s-%d-%s", instance, node, vmid, name)
+}
+
+func proxmoxGuestType(guestType string) proxmox.GuestType {
+	if guestType == string(proxmox.GuestTypeContainer) {
+		return proxmox.GuestTypeContainer
+	}
+	return proxmox.GuestTypeVM
+}
+
+func (m *Monitor) guestSnapshotClient(instance string) (GuestSnapshotClient, error) {
+	client, err := m.guestActionClient(instance)
+	if err != nil {
+		return nil, err
+	}
+	snapshotClient, ok := client.(GuestSnapshotClient)
+	if !ok {
+		return nil, fmt.Errorf("client for instance %s does not support snapshot management", instance)
+	}
+	return snapshotClient, nil
+}
+
+func (m *Monitor) resolveGuestSnapshot(guestID string) (guestTarget, GuestSnapshotClient, error) {
+	guest, ok := m.findGuest(guestID)
+	if !ok {
+		return guestTarget{}, nil, fmt.Errorf("guest %s not found", guestID)
+	}
+	client, err := m.guestSnapshotClient(guest.instance)
+	if err != nil {
+		return guestTarget{}, nil, err
+	}
+	return guest, client, nil
+}
+
+// SnapshotGuest takes a snapshot of a guest and follows the task in the background
+func (m *Monitor) SnapshotGuest(guestID, name, description string, vmState bool, requestedBy string, onUpdate func(GuestActionStatus)) (GuestActionStatus, error) {
+	if err := proxmox.ValidateSnapshotName(name); err != nil {
+		return GuestActionStatus{}, err
+	}
+	guest, client, err := m.resolveGuestSnapshot(guestID)
+	if err != nil {
+		return GuestActionStatus{}, err
+	}
+
+	status := newGuestActionStatus(guest, GuestActionSnapshot, requestedBy)
+	status.Snapshot = name
+
+	return m.runGuestTask(client, status, guestActionTimeout, func(ctx context.Context) (string, error) {
+		return client.CreateSnapshot(ctx, guest.node, guest.guestType, guest.vmid, name, description, vmState)
+	}, onUpdate)
+}
+
+// DeleteGuestSnapshot removes a guest snapshot and drops it from state once the task succeeds
+func (m *Monitor) DeleteGuestSnapshot(guestID, name, requestedBy string, onUpdate func(GuestActionStatus)) (GuestActionStatus, error) {
+	guest, client, err := m.resolveGuestSnapshot(guestID)
+	if err != nil {
+		return GuestActionStatus{}, err
+	}
+
+	status := newGuestActionStatus(guest, GuestActionDeleteSnapshot, requestedBy)
+	status.Snapshot = name
+
+	return m.runGuestTask(client, status, guestActionTimeout, func(ctx context.Context) (string, error) {
+		return client.DeleteSnapshot(ctx, guest.node, guest.guestType, guest.vmid, name)
+	}, func(update GuestActionStatus) {
+		if update.Status == GuestActionStatusCompleted {
+			m.state.RemoveGuestSnapshot(guestSnapshotID(guest.instance, guest.node, guest.vmid, name))
+		}
+		if onUpdate != nil {
+			onUpdate(update)
+		}
+	})
+}
+
+// RollbackGuestSnapshot reverts a guest to one of its snapshots
+func (m *Monitor) RollbackGuestSnapshot(guestID, name, requestedBy string, onUpdate func(GuestActionStatus)) (GuestActionStatus, error) {
+	guest, client, err := m.resolveGuestSnapshot(guestID)
+	if err != nil {
+		return GuestActionStatus{}, err
+	}
+
+	status := newGuestActionStatus(guest, GuestActionRollback, requestedBy)
+	status.Snapshot = name
+
+	return m.runGuestTask(client, status, guestActionTimeout, func(ctx context.Context) (string, error) {
+		return client.RollbackSnapshot(ctx, guest.node, guest.guestType, guest.vmid, name)
+	}, onUpdate)
+}
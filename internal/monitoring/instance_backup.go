@@ -0,0 +1,234 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/backup"
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/internal/updates"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	instanceBackupCheckInterval = 5 * time.Minute
+	instanceBackupStartupDelay  = 10 * time.Minute
+
+	runtimeMetricsHistory = "metrics-history"
+	runtimeDockerHosts    = "docker-hosts"
+)
+
+// SetInstanceBackupConfig replaces the scheduled data directory backup policy
+func (m *Monitor) SetInstanceBackupConfig(cfg config.InstanceBackupConfig) {
+	m.instanceBackupMu.Lock()
+	m.instanceBackupConfig = config.NormalizeInstanceBackupConfig(cfg)
+	m.instanceBackupMu.Unlock()
+}
+
+// GetInstanceBackupConfig returns the scheduled data directory backup policy
+func (m *Monitor) GetInstanceBackupConfig() config.InstanceBackupConfig {
+	m.instanceBackupMu.RLock()
+	defer m.instanceBackupMu.RUnlock()
+	return config.NormalizeInstanceBackupConfig(m.instanceBackupConfig)
+}
+
+func (m *Monitor) dataPath() string {
+	if m.config != nil && m.config.DataPath != "" {
+		return m.config.DataPath
+	}
+	return "/etc/pulse"
+}
+
+// WriteInstanceBackup writes an archive of the data directory to w. Alert, capacity and
+// rightsizing state is flushed to disk first, and metrics history and Docker hosts, which only
+// live in memory, are added as runtime snapshots.
+func (m *Monitor) WriteInstanceBackup(w io.Writer) (*backup.Manifest, error) {
+	opts := backup.Options{
+		DataDir: m.dataPath(),
+		Flush: []func() error{
+			m.saveCapacityHistory,
+			m.saveRightsizingHistory,
+		},
+		Runtime: map[string]func() ([]byte, error){
+			runtimeDockerHosts: func() ([]byte, error) {
+				return json.Marshal(m.state.GetDockerHosts())
+			},
+		},
+	}
+	if m.alertManager != nil {
+		opts.Flush = append(opts.Flush, m.alertManager.PersistState)
+	}
+	if m.metricsHistory != nil {
+		opts.Runtime[runtimeMetricsHistory] = m.metricsHistory.Snapshot
+	}
+	if info, err := updates.GetCurrentVersion(); err == nil {
+		opts.PulseVersion = info.Version
+	}
+
+	// A local target inside the data directory must not be archived into itself
+	if cfg := m.GetInstanceBackupConfig(); cfg.Target == "local" && cfg.LocalPath != "" {
+		if rel, err := filepath.Rel(opts.DataDir, cfg.LocalPath); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			opts.Exclude = append(opts.Exclude, rel)
+		}
+	}
+
+	return backup.Create(w, opts)
+}
+
+// instanceBackupTarget builds the configured backup target
+func (m *Monitor) instanceBackupTarget(cfg config.InstanceBackupConfig) (backup.Target, error) {
+	switch cfg.Target {
+	case "local":
+		dir := cfg.LocalPath
+		if dir == "" {
+			dir = filepath.Join(m.dataPath(), backup.LocalDirName)
+		}
+		return &backup.LocalTarget{Dir: dir}, nil
+	case "s3":
+		secret, err := config.DefaultCredentialResolver().ResolveReference(cfg.S3.SecretAccessKey, "instanceBackup.s3.secretAccessKey")
+		if err != nil {
+			return nil, err
+		}
+		return backup.NewS3Target(backup.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			Prefix:          cfg.S3.Prefix,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: secret,
+			PathStyle:       cfg.S3.PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported backup target %q", cfg.Target)
+	}
+}
+
+// RunInstanceBackup creates an archive, stores it on the configured target and prunes old
+// archives beyond the retention count. It returns the archive name.
+func (m *Monitor) RunInstanceBackup(ctx context.Context) (string, error) {
+	cfg := m.GetInstanceBackupConfig()
+	if err := cfg.Validate(); err != nil {
+		return "", err
+	}
+	target, err := m.instanceBackupTarget(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp("", "pulse-backup-*.tar.gz")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	manifest, err := m.WriteInstanceBackup(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	name := backup.ArchiveName(manifest.CreatedAt)
+	if err := target.Upload(ctx, name, tmp.Name()); err != nil {
+		return "", fmt.Errorf("failed to store backup on %s: %w", target.Name(), err)
+	}
+
+	deleted, err := backup.Prune(ctx, target, cfg.Retain)
+	if err != nil {
+		log.Warn().Ctx(ctx).Err(err).Str("target", target.Name()).Msg("Failed to prune old instance backups")
+	}
+
+	log.Info().Ctx(ctx).
+		Str("archive", name).
+		Str("target", target.Name()).
+		Int("files", len(manifest.Files)).
+		Int("pruned", len(deleted)).
+		Msg("Instance backup completed")
+	return name, nil
+}
+
+func (m *Monitor) runInstanceBackupScheduler(ctx context.Context) {
+	if m.persistence != nil {
+		if cfg, err := m.persistence.LoadInstanceBackupConfig(); err == nil {
+			m.SetInstanceBackupConfig(*cfg)
+		} else {
+			log.Warn().Ctx(ctx).Err(err).Msg("Failed to load instance backup configuration")
+		}
+	}
+
+	notBefore := time.Now().Add(instanceBackupStartupDelay)
+	ticker := time.NewTicker(instanceBackupCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if now.Before(notBefore) {
+				continue
+			}
+			cfg := m.GetInstanceBackupConfig()
+			if !cfg.Enabled {
+				continue
+			}
+			target, err := m.instanceBackupTarget(cfg)
+			if err != nil {
+				log.Warn().Ctx(ctx).Err(err).Msg("Instance backup target is not usable")
+				continue
+			}
+			latest, err := backup.LatestTime(ctx, target)
+			if err != nil {
+				log.Warn().Ctx(ctx).Err(err).Str("target", target.Name()).Msg("Failed to list instance backups")
+				continue
+			}
+			if now.Sub(latest) < time.Duration(cfg.IntervalHours)*time.Hour {
+				continue
+			}
+			if _, err := m.RunInstanceBackup(ctx); err != nil {
+				log.Error().Ctx(ctx).Err(err).Msg("Scheduled instance backup failed")
+			}
+		}
+	}
+}
+
+// restoreRuntimeSnapshots loads the in-memory state of a restored backup, then removes it
+func (m *Monitor) restoreRuntimeSnapshots() {
+	dir := filepath.Join(m.dataPath(), backup.RuntimeRestoreDirName)
+	if _, err := os.Stat(dir); err != nil {
+		return
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warn().Err(err).Msg("Failed to remove restored runtime state")
+		}
+	}()
+
+	if data, err := os.ReadFile(filepath.Join(dir, runtimeMetricsHistory+".json")); err == nil && m.metricsHistory != nil {
+		if err := m.metricsHistory.Restore(data); err != nil {
+			log.Warn().Err(err).Msg("Failed to restore metrics history from backup")
+		} else {
+			log.Info().Msg("Restored metrics history from backup")
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, runtimeDockerHosts+".json")); err == nil {
+		var hosts []models.DockerHost
+		if err := json.Unmarshal(data, &hosts); err != nil {
+			log.Warn().Err(err).Msg("Failed to restore Docker hosts from backup")
+		} else {
+			for _, host := range hosts {
+				m.state.UpsertDockerHost(host)
+			}
+			log.Info().Int("count", len(hosts)).Msg("Restored Docker hosts from backup")
+		}
+	}
+}
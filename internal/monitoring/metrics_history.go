@@ -1,6 +1,7 @@
 package monitoring
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
@@ -369,3 +370,45 @@ func (mh *MetricsHistory) cleanupMetrics(metrics []MetricPoint, cutoffTime time.
 	}
 	return metrics
 }
+
+// metricsHistorySnapshot is the serialized form of a MetricsHistory
+type metricsHistorySnapshot struct {
+	Guests  map[string]*GuestMetrics   `json:"guests"`
+	Nodes   map[string]*GuestMetrics   `json:"nodes"`
+	Storage map[string]*StorageMetrics `json:"storage"`
+}
+
+// Snapshot serializes all stored metrics
+func (mh *MetricsHistory) Snapshot() ([]byte, error) {
+	mh.mu.RLock()
+	defer mh.mu.RUnlock()
+
+	return json.Marshal(metricsHistorySnapshot{
+		Guests:  mh.guestMetrics,
+		Nodes:   mh.nodeMetrics,
+		Storage: mh.storageMetrics,
+	})
+}
+
+// Restore replaces the stored metrics with a snapshot and drops points past the retention time
+func (mh *MetricsHistory) Restore(data []byte) error {
+	var snapshot metricsHistorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	mh.mu.Lock()
+	if snapshot.Guests != nil {
+		mh.guestMetrics = snapshot.Guests
+	}
+	if snapshot.Nodes != nil {
+		mh.nodeMetrics = snapshot.Nodes
+	}
+	if snapshot.Storage != nil {
+		mh.storageMetrics = snapshot.Storage
+	}
+	mh.mu.Unlock()
+
+	mh.Cleanup()
+	return nil
+}
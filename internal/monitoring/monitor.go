@@ -29,6 +29,7 @@ import (
 	"github.com/RouXx67/PulseUp/internal/mock"
 	"github.com/RouXx67/PulseUp/internal/models"
 	"github.com/RouXx67/PulseUp/internal/notifications"
+	"github.com/RouXx67/PulseUp/internal/tracing"
 	"github.com/RouXx67/PulseUp/internal/websocket"
 	agentsdocker "github.com/RouXx67/PulseUp/pkg/agents/docker"
 	agentshost "github.com/RouXx67/PulseUp/pkg/agents/host"
@@ -37,6 +38,7 @@ import (
 	"github.com/RouXx67/PulseUp/pkg/proxmox"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // PVEClientInterface defines the interface for PVE clients (both regular and cluster)
@@ -309,7 +311,7 @@ func (r *realExecutor) Execute(ctx context.Context, task PollTask) {
 	switch strings.ToLower(task.InstanceType) {
 	case "pve":
 		if task.PVEClient == nil {
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Str("instance", task.InstanceName).
 				Msg("PollExecutor received nil PVE client")
 			return
@@ -317,7 +319,7 @@ func (r *realExecutor) Execute(ctx context.Context, task PollTask) {
 		r.monitor.pollPVEInstance(ctx, task.InstanceName, task.PVEClient)
 	case "pbs":
 		if task.PBSClient == nil {
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Str("instance", task.InstanceName).
 				Msg("PollExecutor received nil PBS client")
 			return
@@ -325,7 +327,7 @@ func (r *realExecutor) Execute(ctx context.Context, task PollTask) {
 		r.monitor.pollPBSInstance(ctx, task.InstanceName, task.PBSClient)
 	case "pmg":
 		if task.PMGClient == nil {
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Str("instance", task.InstanceName).
 				Msg("PollExecutor received nil PMG client")
 			return
@@ -333,7 +335,7 @@ func (r *realExecutor) Execute(ctx context.Context, task PollTask) {
 		r.monitor.pollPMGInstance(ctx, task.InstanceName, task.PMGClient)
 	default:
 		if logging.IsLevelEnabled(zerolog.DebugLevel) {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", task.InstanceName).
 				Str("type", task.InstanceType).
 				Msg("PollExecutor received unsupported task type")
@@ -446,6 +448,7 @@ type Monitor struct {
 	notificationMgr       *notifications.NotificationManager
 	configPersist         *config.ConfigPersistence
 	discoveryService      *discovery.Service        // Background discovery service
+	discoveryOnboarder    *discovery.Onboarder      // Outlives discoveryService restarts
 	activePollCount       int32                     // Number of active polling operations
 	pollCounter           int64                     // Counter for polling cycles
 	authFailures          map[string]int            // Track consecutive auth failures per node
@@ -475,6 +478,34 @@ type Monitor struct {
 	instanceInfoCache     map[string]*instanceInfo
 	pollStatusMap         map[string]*pollStatus
 	dlqInsightMap         map[string]*dlqInsight
+	retentionMu           sync.Mutex // Serialises snapshot retention runs
+	retentionCfgMu        sync.RWMutex
+	retentionConfig       config.SnapshotRetentionConfig
+	lastRetentionRun      time.Time
+	backupJobsMu          sync.Mutex
+	backupJobs            []*BackupJob
+	backupAttempts        map[string]time.Time // Last remediation backup per guest ID
+	remediationMu         sync.RWMutex
+	remediationConfig     config.BackupRemediationConfig
+	instanceBackupMu      sync.RWMutex
+	instanceBackupConfig  config.InstanceBackupConfig
+	federationMu          sync.RWMutex
+	federationConfig      config.FederationConfig
+	federationSites       map[string]*federatedSite
+	maintenanceMu         sync.Mutex
+	maintenanceChecked    map[string]time.Time // Last package/certificate check per platform:instance
+	taskLogMu             sync.Mutex
+	taskLogPolled         map[string]time.Time          // Last task log collection per instance
+	taskCursors           map[string]int64              // Task start time cursor per instance/node
+	tasks                 map[string]models.ClusterTask // Bounded task history keyed by instance-UPID
+	tenantMu              sync.RWMutex
+	tenantScopes          config.TenantScopesConfig
+	forecastMu            sync.Mutex
+	capacitySeries        map[string]*capacitySeries
+	forecasts             []models.CapacityForecast
+	rightsizingMu         sync.Mutex
+	guestUsage            map[string]*guestUsageHistory
+	alertTraces           alertTraces // Span of the latest alert check per resource
 }
 
 type rrdMemCacheEntry struct {
@@ -1544,7 +1575,7 @@ func (m *Monitor) fetchGuestAgentMetadata(ctx context.Context, client PVEClientI
 	interfaces, err := client.GetVMNetworkInterfaces(ifaceCtx, nodeName, vmid)
 	cancelIface()
 	if err != nil {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", instanceName).
 			Str("vm", vmName).
 			Int("vmid", vmid).
@@ -1561,7 +1592,7 @@ func (m *Monitor) fetchGuestAgentMetadata(ctx context.Context, client PVEClientI
 	agentInfo, err := client.GetVMAgentInfo(osCtx, nodeName, vmid)
 	cancelOS()
 	if err != nil {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", instanceName).
 			Str("vm", vmName).
 			Int("vmid", vmid).
@@ -1578,7 +1609,7 @@ func (m *Monitor) fetchGuestAgentMetadata(ctx context.Context, client PVEClientI
 	version, err := client.GetVMAgentVersion(versionCtx, nodeName, vmid)
 	cancelVersion()
 	if err != nil {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", instanceName).
 			Str("vm", vmName).
 			Int("vmid", vmid).
@@ -1853,7 +1884,7 @@ func (m *Monitor) enrichContainerMetadata(ctx context.Context, client PVEClientI
 	status, err := client.GetContainerStatus(statusCtx, nodeName, container.VMID)
 	cancel()
 	if err != nil {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Err(err).
 			Str("instance", instanceName).
 			Str("node", nodeName).
@@ -1938,7 +1969,7 @@ func (m *Monitor) enrichContainerMetadata(ctx context.Context, client PVEClientI
 	configData, configErr := client.GetContainerConfig(configCtx, nodeName, container.VMID)
 	cancelConfig()
 	if configErr != nil {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Err(configErr).
 			Str("instance", instanceName).
 			Str("node", nodeName).
@@ -1964,7 +1995,7 @@ func (m *Monitor) enrichContainerMetadata(ctx context.Context, client PVEClientI
 		ifaceDetails, ifaceErr := client.GetContainerInterfaces(interfacesCtx, nodeName, container.VMID)
 		cancelInterfaces()
 		if ifaceErr != nil {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Err(ifaceErr).
 				Str("instance", instanceName).
 				Str("node", nodeName).
@@ -2557,6 +2588,7 @@ func New(cfg *config.Config) (*Monitor, error) {
 
 	m := &Monitor{
 		config:               cfg,
+		tenantScopes:         cfg.TenantScopes,
 		state:                models.NewState(),
 		pveClients:           make(map[string]PVEClientInterface),
 		pbsClients:           make(map[string]*pbs.Client),
@@ -2612,6 +2644,7 @@ func New(cfg *config.Config) (*Monitor, error) {
 
 	m.executor = newRealExecutor(m)
 	m.buildInstanceInfoCache(cfg)
+	m.discoveryOnboarder = discovery.NewOnboarder(nil, m.discoveryConfig)
 
 	if m.pollMetrics != nil {
 		m.pollMetrics.ResetQueueDepth(0)
@@ -2637,6 +2670,9 @@ func New(cfg *config.Config) (*Monitor, error) {
 		log.Warn().Err(err).Msg("Failed to load alert configuration")
 	}
 
+	// SMTP passwords may be vault://, sops:// or systemd-creds:// references
+	m.notificationMgr.SetSecretResolver(config.DefaultCredentialResolver().ResolveReference)
+
 	if emailConfig, err := m.configPersist.LoadEmailConfig(); err == nil {
 		m.notificationMgr.SetEmailConfig(*emailConfig)
 	} else {
@@ -2649,6 +2685,23 @@ func New(cfg *config.Config) (*Monitor, error) {
 		log.Warn().Err(err).Msg("Failed to load Apprise configuration")
 	}
 
+	if syslogConfig, err := m.configPersist.LoadSyslogConfig(); err == nil {
+		m.notificationMgr.SetSyslogConfig(*syslogConfig)
+	} else {
+		log.Warn().Err(err).Msg("Failed to load syslog configuration")
+	}
+
+	if snmpConfig, err := m.configPersist.LoadSNMPConfig(); err == nil {
+		m.notificationMgr.SetSNMPConfig(*snmpConfig)
+	} else {
+		log.Warn().Err(err).Msg("Failed to load SNMP configuration")
+	}
+	if boots, err := m.configPersist.NextSNMPEngineBoots(); err == nil {
+		m.notificationMgr.SetSNMPEngineBoots(boots)
+	} else {
+		log.Warn().Err(err).Msg("Failed to update the SNMPv3 engine boot counter")
+	}
+
 	// Migrate webhooks if needed (from unencrypted to encrypted)
 	if err := m.configPersist.MigrateWebhooksIfNeeded(); err != nil {
 		log.Warn().Err(err).Msg("Failed to migrate webhooks")
@@ -2729,6 +2782,7 @@ func New(cfg *config.Config) (*Monitor, error) {
 					Msg("Creating cluster-aware client")
 
 				clientConfig := config.CreateProxmoxConfig(&pve)
+				clientConfig.WrapTransport = tracedTransport("PVE")
 				clientConfig.Timeout = cfg.ConnectionTimeout
 				clusterClient := proxmox.NewClusterClient(
 					pve.Name,
@@ -2746,6 +2800,7 @@ func New(cfg *config.Config) (*Monitor, error) {
 			} else {
 				// Create regular client
 				clientConfig := config.CreateProxmoxConfig(&pve)
+				clientConfig.WrapTransport = tracedTransport("PVE")
 				clientConfig.Timeout = cfg.ConnectionTimeout
 				client, err := proxmox.NewClient(clientConfig)
 				if err != nil {
@@ -2780,6 +2835,7 @@ func New(cfg *config.Config) (*Monitor, error) {
 				Msg("Configuring PBS instance")
 
 			clientConfig := config.CreatePBSConfig(&pbsInst)
+			clientConfig.WrapTransport = tracedTransport("PBS")
 			clientConfig.Timeout = 60 * time.Second // Very generous timeout for slow PBS servers
 			client, err := pbs.NewClient(clientConfig)
 			if err != nil {
@@ -2813,6 +2869,7 @@ func New(cfg *config.Config) (*Monitor, error) {
 				Msg("Configuring PMG instance")
 
 			clientConfig := config.CreatePMGConfig(&pmgInst)
+			clientConfig.WrapTransport = tracedTransport("PMG")
 			if clientConfig.Timeout <= 0 {
 				clientConfig.Timeout = 45 * time.Second
 			}
@@ -2948,7 +3005,7 @@ func (m *Monitor) getExecutor() PollExecutor {
 
 // Start begins the monitoring loop
 func (m *Monitor) Start(ctx context.Context, wsHub *websocket.Hub) {
-	log.Info().
+	log.Info().Ctx(ctx).
 		Dur("pollingInterval", 10*time.Second).
 		Msg("Starting monitoring loop")
 
@@ -2957,32 +3014,28 @@ func (m *Monitor) Start(ctx context.Context, wsHub *websocket.Hub) {
 	m.wsHub = wsHub
 	m.mu.Unlock()
 
+	// Load metrics history and Docker hosts carried over by a restored backup
+	m.restoreRuntimeSnapshots()
+
 	// Initialize and start discovery service if enabled
 	if mock.IsMockEnabled() {
-		log.Info().Msg("Mock mode enabled - skipping discovery service")
+		log.Info().Ctx(ctx).Msg("Mock mode enabled - skipping discovery service")
 		m.discoveryService = nil
 	} else if m.config.DiscoveryEnabled {
 		discoverySubnet := m.config.DiscoverySubnet
 		if discoverySubnet == "" {
 			discoverySubnet = "auto"
 		}
-		cfgProvider := func() config.DiscoveryConfig {
-			m.mu.RLock()
-			defer m.mu.RUnlock()
-			if m.config == nil {
-				return config.DefaultDiscoveryConfig()
-			}
-			return config.CloneDiscoveryConfig(m.config.Discovery)
-		}
-		m.discoveryService = discovery.NewService(wsHub, 5*time.Minute, discoverySubnet, cfgProvider)
+		m.discoveryService = discovery.NewService(wsHub, 5*time.Minute, discoverySubnet, m.discoveryConfig)
 		if m.discoveryService != nil {
+			m.discoveryService.SetOnboarder(m.discoveryOnboarder)
 			m.discoveryService.Start(ctx)
-			log.Info().Msg("Discovery service initialized and started")
+			log.Info().Ctx(ctx).Msg("Discovery service initialized and started")
 		} else {
-			log.Error().Msg("Failed to initialize discovery service")
+			log.Error().Ctx(ctx).Msg("Failed to initialize discovery service")
 		}
 	} else {
-		log.Info().Msg("Discovery service disabled by configuration")
+		log.Info().Ctx(ctx).Msg("Discovery service disabled by configuration")
 		m.discoveryService = nil
 	}
 
@@ -2990,28 +3043,50 @@ func (m *Monitor) Start(ctx context.Context, wsHub *websocket.Hub) {
 	m.alertManager.SetAlertCallback(func(alert *alerts.Alert) {
 		wsHub.BroadcastAlert(alert)
 		// Send notifications
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("alertID", alert.ID).
 			Str("level", string(alert.Level)).
 			Msg("Alert raised, sending to notification manager")
-		go m.notificationMgr.SendAlert(alert)
+		alertCtx := m.alertContext(alert)
+		go m.notificationMgr.SendAlert(alertCtx, alert)
+		go m.notifyTenantScopes(alertCtx, alert)
 	})
 	m.alertManager.SetResolvedCallback(func(alertID string) {
 		wsHub.BroadcastAlertResolved(alertID)
 		m.notificationMgr.CancelAlert(alertID)
+		if resolved, ok := m.alertManager.GetResolvedAlert(alertID); ok {
+			m.notificationMgr.SendResolved(m.alertContext(resolved), resolved)
+		}
 		// Don't broadcast full state here - it causes a cascade with many guests
 		// The frontend will get the updated alerts through the regular broadcast ticker
 		// state := m.GetState()
 		// wsHub.BroadcastState(state)
 	})
 	m.alertManager.SetEscalateCallback(func(alert *alerts.Alert, level int) {
-		log.Info().
+		log.Info().Ctx(ctx).
 			Str("alertID", alert.ID).
 			Int("level", level).
 			Msg("Alert escalated - sending notifications")
 
 		// Get escalation config
 		config := m.alertManager.GetConfig()
+		alertCtx := m.alertContext(alert)
+
+		// Policy-based escalations notify the resolved on-call target directly
+		if alert.EscalationPolicy != "" {
+			if target, ok := config.Schedule.Escalation.FindTarget(alert.EscalationTarget); ok {
+				m.notificationMgr.SendEscalation(alertCtx, alert, target)
+			} else {
+				log.Warn().Ctx(ctx).
+					Str("alertID", alert.ID).
+					Str("policy", alert.EscalationPolicy).
+					Str("target", alert.EscalationTarget).
+					Msg("Escalation target could not be resolved")
+			}
+			wsHub.BroadcastAlert(alert)
+			return
+		}
+
 		if level <= 0 || level > len(config.Schedule.Escalation.Levels) {
 			return
 		}
@@ -3023,19 +3098,19 @@ func (m *Monitor) Start(ctx context.Context, wsHub *websocket.Hub) {
 		case "email":
 			// Only send email
 			if emailConfig := m.notificationMgr.GetEmailConfig(); emailConfig.Enabled {
-				m.notificationMgr.SendAlert(alert)
+				m.notificationMgr.SendAlert(alertCtx, alert)
 			}
 		case "webhook":
 			// Only send webhooks
 			for _, webhook := range m.notificationMgr.GetWebhooks() {
 				if webhook.Enabled {
-					m.notificationMgr.SendAlert(alert)
+					m.notificationMgr.SendAlert(alertCtx, alert)
 					break
 				}
 			}
 		case "all":
 			// Send all notifications
-			m.notificationMgr.SendAlert(alert)
+			m.notificationMgr.SendAlert(alertCtx, alert)
 		}
 
 		// Update WebSocket with escalation
@@ -3061,9 +3136,44 @@ func (m *Monitor) Start(ctx context.Context, wsHub *websocket.Hub) {
 		go m.retryFailedConnections(ctx)
 	}
 
+	// Enforce snapshot retention policies on their configured schedule
+	if !mock.IsMockEnabled() {
+		go m.runSnapshotRetentionScheduler(ctx)
+	}
+
+	// Sample capacity usage and project when resources run full
+	if !mock.IsMockEnabled() {
+		go m.runCapacityForecaster(ctx)
+	}
+
+	// Track guest usage for rightsizing recommendations
+	if !mock.IsMockEnabled() {
+		go m.runRightsizingSampler(ctx)
+	}
+
+	// Start backups for guests whose backup-age alert turns critical, if enabled
+	if !mock.IsMockEnabled() {
+		go m.runBackupRemediationScheduler(ctx)
+	}
+
+	// Back up the data directory on the configured schedule
+	if !mock.IsMockEnabled() {
+		go m.runInstanceBackupScheduler(ctx)
+	}
+
+	// Stream the state of downstream Pulse instances when acting as a federation hub
+	if !mock.IsMockEnabled() {
+		go m.runFederation(ctx)
+	}
+
+	// Onboard discovered servers and run scheduled subnet sweeps
+	if !mock.IsMockEnabled() {
+		go m.runDiscoveryOnboarding(ctx, wsHub)
+	}
+
 	// Do an immediate poll on start (only if not in mock mode)
 	if mock.IsMockEnabled() {
-		log.Info().Msg("Mock mode enabled - skipping real node polling")
+		log.Info().Ctx(ctx).Msg("Mock mode enabled - skipping real node polling")
 		go m.checkMockAlerts()
 	} else {
 		go m.poll(ctx, wsHub)
@@ -3087,7 +3197,7 @@ func (m *Monitor) Start(ctx context.Context, wsHub *websocket.Hub) {
 			// Broadcast current state regardless of polling status
 			// Use GetState() instead of m.state.GetSnapshot() to respect mock mode
 			state := m.GetState()
-			log.Info().
+			log.Info().Ctx(ctx).
 				Int("nodes", len(state.Nodes)).
 				Int("vms", len(state.VMs)).
 				Int("containers", len(state.Containers)).
@@ -3099,7 +3209,7 @@ func (m *Monitor) Start(ctx context.Context, wsHub *websocket.Hub) {
 			wsHub.BroadcastState(state.ToFrontend())
 
 		case <-ctx.Done():
-			log.Info().Msg("Monitoring loop stopped")
+			log.Info().Ctx(ctx).Msg("Monitoring loop stopped")
 			return
 		}
 	}
@@ -3130,7 +3240,7 @@ func (m *Monitor) retryFailedConnections(ctx context.Context) {
 		}
 
 		if time.Since(startTime) > maxRetryDuration {
-			log.Info().Msg("Connection retry period expired")
+			log.Info().Ctx(ctx).Msg("Connection retry period expired")
 			return
 		}
 
@@ -3172,11 +3282,11 @@ func (m *Monitor) retryFailedConnections(ctx context.Context) {
 
 		// If no missing clients, we're done
 		if len(missingPVE) == 0 && len(missingPBS) == 0 {
-			log.Info().Msg("All client connections established successfully")
+			log.Info().Ctx(ctx).Msg("All client connections established successfully")
 			return
 		}
 
-		log.Info().
+		log.Info().Ctx(ctx).
 			Int("missingPVE", len(missingPVE)).
 			Int("missingPBS", len(missingPBS)).
 			Dur("nextRetry", delay).
@@ -3214,6 +3324,7 @@ func (m *Monitor) retryFailedConnections(ctx context.Context) {
 				}
 
 				clientConfig := config.CreateProxmoxConfig(&pve)
+				clientConfig.WrapTransport = tracedTransport("PVE")
 				clientConfig.Timeout = m.config.ConnectionTimeout
 				clusterClient := proxmox.NewClusterClient(pve.Name, clientConfig, endpoints)
 
@@ -3222,17 +3333,18 @@ func (m *Monitor) retryFailedConnections(ctx context.Context) {
 				m.state.SetConnectionHealth(pve.Name, true)
 				m.mu.Unlock()
 
-				log.Info().
+				log.Info().Ctx(ctx).
 					Str("instance", pve.Name).
 					Str("cluster", pve.ClusterName).
 					Msg("Successfully reconnected cluster client")
 			} else {
 				// Create regular client
 				clientConfig := config.CreateProxmoxConfig(&pve)
+				clientConfig.WrapTransport = tracedTransport("PVE")
 				clientConfig.Timeout = m.config.ConnectionTimeout
 				client, err := proxmox.NewClient(clientConfig)
 				if err != nil {
-					log.Warn().
+					log.Warn().Ctx(ctx).
 						Err(err).
 						Str("instance", pve.Name).
 						Msg("Failed to reconnect PVE client, will retry")
@@ -3244,7 +3356,7 @@ func (m *Monitor) retryFailedConnections(ctx context.Context) {
 				m.state.SetConnectionHealth(pve.Name, true)
 				m.mu.Unlock()
 
-				log.Info().
+				log.Info().Ctx(ctx).
 					Str("instance", pve.Name).
 					Msg("Successfully reconnected PVE client")
 			}
@@ -3253,10 +3365,11 @@ func (m *Monitor) retryFailedConnections(ctx context.Context) {
 		// Try to recreate PBS clients
 		for _, pbsInst := range missingPBS {
 			clientConfig := config.CreatePBSConfig(&pbsInst)
+			clientConfig.WrapTransport = tracedTransport("PBS")
 			clientConfig.Timeout = 60 * time.Second
 			client, err := pbs.NewClient(clientConfig)
 			if err != nil {
-				log.Warn().
+				log.Warn().Ctx(ctx).
 					Err(err).
 					Str("instance", pbsInst.Name).
 					Msg("Failed to reconnect PBS client, will retry")
@@ -3268,7 +3381,7 @@ func (m *Monitor) retryFailedConnections(ctx context.Context) {
 			m.state.SetConnectionHealth("pbs-"+pbsInst.Name, true)
 			m.mu.Unlock()
 
-			log.Info().
+			log.Info().Ctx(ctx).
 				Str("instance", pbsInst.Name).
 				Msg("Successfully reconnected PBS client")
 		}
@@ -3282,14 +3395,14 @@ func (m *Monitor) poll(ctx context.Context, wsHub *websocket.Hub) {
 	if currentCount > 2 {
 		atomic.AddInt32(&m.activePollCount, -1)
 		if logging.IsLevelEnabled(zerolog.DebugLevel) {
-			log.Debug().Int32("activePolls", currentCount-1).Msg("Too many concurrent polls, skipping")
+			log.Debug().Ctx(ctx).Int32("activePolls", currentCount-1).Msg("Too many concurrent polls, skipping")
 		}
 		return
 	}
 	defer atomic.AddInt32(&m.activePollCount, -1)
 
 	if logging.IsLevelEnabled(zerolog.DebugLevel) {
-		log.Debug().Msg("Starting polling cycle")
+		log.Debug().Ctx(ctx).Msg("Starting polling cycle")
 	}
 	startTime := time.Now()
 	now := startTime
@@ -3315,7 +3428,7 @@ func (m *Monitor) poll(ctx context.Context, wsHub *websocket.Hub) {
 	m.mu.Unlock()
 
 	if logging.IsLevelEnabled(zerolog.DebugLevel) {
-		log.Debug().Dur("duration", time.Since(startTime)).Msg("Polling cycle completed")
+		log.Debug().Ctx(ctx).Dur("duration", time.Since(startTime)).Msg("Polling cycle completed")
 	}
 
 	// Broadcasting is now handled by the timer in Start()
@@ -3456,13 +3569,13 @@ func (m *Monitor) startTaskWorkers(ctx context.Context, workers int) {
 
 func (m *Monitor) taskWorker(ctx context.Context, id int) {
 	if logging.IsLevelEnabled(zerolog.DebugLevel) {
-		log.Debug().Int("worker", id).Msg("Task worker started")
+		log.Debug().Ctx(ctx).Int("worker", id).Msg("Task worker started")
 	}
 	for {
 		task, ok := m.taskQueue.WaitNext(ctx)
 		if !ok {
 			if logging.IsLevelEnabled(zerolog.DebugLevel) {
-				log.Debug().Int("worker", id).Msg("Task worker stopping")
+				log.Debug().Ctx(ctx).Int("worker", id).Msg("Task worker stopping")
 			}
 			return
 		}
@@ -3477,7 +3590,7 @@ func (m *Monitor) taskWorker(ctx context.Context, id int) {
 func (m *Monitor) executeScheduledTask(ctx context.Context, task ScheduledTask) {
 	if !m.allowExecution(task) {
 		if logging.IsLevelEnabled(zerolog.DebugLevel) {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", task.InstanceName).
 				Str("type", string(task.InstanceType)).
 				Msg("Task blocked by circuit breaker")
@@ -3485,6 +3598,15 @@ func (m *Monitor) executeScheduledTask(ctx context.Context, task ScheduledTask)
 		return
 	}
 
+	// Trace the whole poll so slow API calls and alert checks show up as its children
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "poll "+string(task.InstanceType),
+		attribute.String("pulse.instance", task.InstanceName),
+		attribute.String("pulse.instance_type", string(task.InstanceType)),
+	)
+	var pollErr error
+	defer func() { tracing.End(span, pollErr) }()
+
 	if m.pollMetrics != nil {
 		wait := time.Duration(0)
 		if !task.NextRun.IsZero() {
@@ -3502,7 +3624,7 @@ func (m *Monitor) executeScheduledTask(ctx context.Context, task ScheduledTask)
 
 	executor := m.getExecutor()
 	if executor == nil {
-		log.Error().
+		log.Error().Ctx(ctx).
 			Str("instance", task.InstanceName).
 			Str("type", string(task.InstanceType)).
 			Msg("No poll executor configured; skipping task")
@@ -3518,26 +3640,26 @@ func (m *Monitor) executeScheduledTask(ctx context.Context, task ScheduledTask)
 	case InstanceTypePVE:
 		client, ok := m.pveClients[task.InstanceName]
 		if !ok || client == nil {
-			log.Warn().Str("instance", task.InstanceName).Msg("PVE client missing for scheduled task")
+			log.Warn().Ctx(ctx).Str("instance", task.InstanceName).Msg("PVE client missing for scheduled task")
 			return
 		}
 		pollTask.PVEClient = client
 	case InstanceTypePBS:
 		client, ok := m.pbsClients[task.InstanceName]
 		if !ok || client == nil {
-			log.Warn().Str("instance", task.InstanceName).Msg("PBS client missing for scheduled task")
+			log.Warn().Ctx(ctx).Str("instance", task.InstanceName).Msg("PBS client missing for scheduled task")
 			return
 		}
 		pollTask.PBSClient = client
 	case InstanceTypePMG:
 		client, ok := m.pmgClients[task.InstanceName]
 		if !ok || client == nil {
-			log.Warn().Str("instance", task.InstanceName).Msg("PMG client missing for scheduled task")
+			log.Warn().Ctx(ctx).Str("instance", task.InstanceName).Msg("PMG client missing for scheduled task")
 			return
 		}
 		pollTask.PMGClient = client
 	default:
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", task.InstanceName).
 			Str("type", string(task.InstanceType)).
 			Msg("Skipping unsupported task type")
@@ -3545,6 +3667,12 @@ func (m *Monitor) executeScheduledTask(ctx context.Context, task ScheduledTask)
 	}
 
 	executor.Execute(ctx, pollTask)
+
+	m.mu.Lock()
+	if outcome, ok := m.lastOutcome[schedulerKey(task.InstanceType, task.InstanceName)]; ok && !outcome.success && !outcome.recordedAt.Before(start) {
+		pollErr = outcome.err
+	}
+	m.mu.Unlock()
 }
 
 func (m *Monitor) rescheduleTask(task ScheduledTask) {
@@ -3581,7 +3709,10 @@ func (m *Monitor) rescheduleTask(task ScheduledTask) {
 	}
 
 	if m.scheduler == nil {
-		nextInterval := task.Interval
+		nextInterval := m.pollIntervalOverride(task.InstanceType, task.InstanceName)
+		if nextInterval <= 0 {
+			nextInterval = task.Interval
+		}
 		if nextInterval <= 0 && m.config != nil {
 			nextInterval = m.config.AdaptivePollingBaseInterval
 		}
@@ -3596,10 +3727,11 @@ func (m *Monitor) rescheduleTask(task ScheduledTask) {
 	}
 
 	desc := InstanceDescriptor{
-		Name:          task.InstanceName,
-		Type:          task.InstanceType,
-		LastInterval:  task.Interval,
-		LastScheduled: task.NextRun,
+		Name:             task.InstanceName,
+		Type:             task.InstanceType,
+		LastInterval:     task.Interval,
+		LastScheduled:    task.NextRun,
+		IntervalOverride: m.pollIntervalOverride(task.InstanceType, task.InstanceName),
 	}
 	if m.stalenessTracker != nil {
 		if snap, ok := m.stalenessTracker.snapshot(task.InstanceType, task.InstanceName); ok {
@@ -4170,14 +4302,14 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 	case <-ctx.Done():
 		pollErr = ctx.Err()
 		if debugEnabled {
-			log.Debug().Str("instance", instanceName).Msg("Polling cancelled")
+			log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling cancelled")
 		}
 		return
 	default:
 	}
 
 	if debugEnabled {
-		log.Debug().Str("instance", instanceName).Msg("Polling PVE instance")
+		log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling PVE instance")
 	}
 
 	// Get instance config
@@ -4198,7 +4330,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 	if err != nil {
 		monErr := errors.WrapConnectionError("poll_nodes", instanceName, err)
 		pollErr = monErr
-		log.Error().Err(monErr).Str("instance", instanceName).Msg("Failed to get nodes")
+		log.Error().Ctx(ctx).Err(monErr).Str("instance", instanceName).Msg("Failed to get nodes")
 		m.state.SetConnectionHealth(instanceName, false)
 
 		// Track auth failure if it's an authentication error
@@ -4211,6 +4343,10 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 	// Reset auth failures on successful connection
 	m.resetAuthFailures(instanceName, "pve")
 
+	// Leave filtered-out nodes, and everything on them, out of this poll
+	reportedNodes := len(nodes)
+	nodes = filterPVENodes(instanceCfg.Filters, nodes)
+
 	// Check if client is a ClusterClient to determine health status
 	connectionHealthStr := "healthy"
 	if clusterClient, ok := client.(*proxmox.ClusterClient); ok {
@@ -4233,7 +4369,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 			// Some endpoints are down - degraded state
 			connectionHealthStr = "degraded"
 			m.state.SetConnectionHealth(instanceName, true) // Still functional but degraded
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Str("instance", instanceName).
 				Int("healthy", healthyCount).
 				Int("total", totalCount).
@@ -4322,7 +4458,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 		// Debug logging for disk metrics - note that these values can fluctuate
 		// due to thin provisioning and dynamic allocation
 		if node.Disk > 0 && node.MaxDisk > 0 {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", node.Node).
 				Uint64("disk", node.Disk).
 				Uint64("maxDisk", node.MaxDisk).
@@ -4340,7 +4476,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 				nodeFallbackReason = "node-status-unavailable"
 				// If we can't get node status, log but continue with data from /nodes endpoint
 				if node.Disk > 0 && node.MaxDisk > 0 {
-					log.Warn().
+					log.Warn().Ctx(ctx).
 						Str("instance", instanceName).
 						Str("node", node.Node).
 						Err(nodeErr).
@@ -4348,7 +4484,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 						Uint64("usingMaxDisk", node.MaxDisk).
 						Msg("Could not get node status - using fallback metrics (memory will include cache/buffers)")
 				} else {
-					log.Warn().
+					log.Warn().Ctx(ctx).
 						Str("instance", instanceName).
 						Str("node", node.Node).
 						Err(nodeErr).
@@ -4395,7 +4531,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 						Free:  int64(nodeInfo.RootFS.Free),
 						Usage: safePercentage(float64(nodeInfo.RootFS.Used), float64(nodeInfo.RootFS.Total)),
 					}
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("node", node.Node).
 						Uint64("rootfsUsed", nodeInfo.RootFS.Used).
 						Uint64("rootfsTotal", nodeInfo.RootFS.Total).
@@ -4404,7 +4540,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 				} else if node.Disk > 0 && node.MaxDisk > 0 {
 					// RootFS unavailable but we have valid disk data from /nodes endpoint
 					// Keep the values we already set from the nodes list
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("node", node.Node).
 						Bool("rootfsNil", nodeInfo.RootFS == nil).
 						Uint64("fallbackDisk", node.Disk).
@@ -4412,7 +4548,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 						Msg("RootFS data unavailable - using /nodes endpoint disk metrics")
 				} else {
 					// Neither rootfs nor valid node disk data available
-					log.Warn().
+					log.Warn().Ctx(ctx).
 						Str("node", node.Node).
 						Bool("rootfsNil", nodeInfo.RootFS == nil).
 						Uint64("nodeDisk", node.Disk).
@@ -4471,7 +4607,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 								rrdMemUsedFallback = true
 							}
 						} else if err != nil {
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Err(err).
 								Str("instance", instanceName).
 								Str("node", node.Node).
@@ -4502,7 +4638,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 							actualUsed = nodeInfo.Memory.Total
 						}
 
-						logCtx := log.Debug().
+						logCtx := log.Debug().Ctx(ctx).
 							Str("node", node.Node).
 							Uint64("total", nodeInfo.Memory.Total).
 							Uint64("effectiveAvailable", effectiveAvailable).
@@ -4549,7 +4685,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 							if actualUsed > nodeInfo.Memory.Total {
 								actualUsed = nodeInfo.Memory.Total
 							}
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Str("node", node.Node).
 								Uint64("total", nodeInfo.Memory.Total).
 								Uint64("rrdUsed", rrdMetrics.used).
@@ -4566,7 +4702,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 							if actualUsed > nodeInfo.Memory.Total {
 								actualUsed = nodeInfo.Memory.Total
 							}
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Str("node", node.Node).
 								Uint64("total", nodeInfo.Memory.Total).
 								Uint64("used", actualUsed).
@@ -4605,7 +4741,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 					}
 
 					mhzStr := nodeInfo.CPUInfo.GetMHzString()
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("node", node.Node).
 						Str("model", nodeInfo.CPUInfo.Model).
 						Int("cores", nodeInfo.CPUInfo.Cores).
@@ -4646,7 +4782,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 				preserved.Usage = safePercentage(float64(used), float64(total))
 
 				modelNode.Memory = preserved
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("instance", instanceName).
 					Str("node", node.Node).
 					Msg("Preserving previous memory metrics - node status unavailable this cycle")
@@ -4740,7 +4876,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 				}
 
 				modelNode.Temperature = temp
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("node", node.Node).
 					Str("sshHost", sshHost).
 					Float64("cpuPackage", temp.CPUPackage).
@@ -4750,14 +4886,14 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 					Int("nvmeCount", len(temp.NVMe)).
 					Msg("Collected temperature data")
 			} else if err != nil {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("node", node.Node).
 					Str("sshHost", sshHost).
 					Bool("isCluster", modelNode.IsClusterMember).
 					Int("endpointCount", len(instanceCfg.ClusterEndpoints)).
 					Msg("Temperature collection failed - check SSH access")
 			} else if temp != nil {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("node", node.Node).
 					Str("sshHost", sshHost).
 					Bool("available", temp.Available).
@@ -4810,8 +4946,8 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 		modelNodes = append(modelNodes, modelNode)
 	}
 
-	if len(modelNodes) == 0 && len(prevInstanceNodes) > 0 {
-		log.Warn().
+	if len(modelNodes) == 0 && reportedNodes == 0 && len(prevInstanceNodes) > 0 {
+		log.Warn().Ctx(ctx).
 			Str("instance", instanceName).
 			Int("previousCount", len(prevInstanceNodes)).
 			Msg("No Proxmox nodes returned this cycle - preserving previous state")
@@ -4850,7 +4986,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 					// Look for local or local-lvm storage as most stable disk metric
 					for _, storage := range nodeStorages {
 						if reason, skip := readOnlyFilesystemReason(storage.Type, storage.Total, storage.Used); skip {
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Str("node", node.Node).
 								Str("storage", storage.Storage).
 								Str("type", storage.Type).
@@ -4870,7 +5006,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 							// Prefer "local" over "local-lvm"
 							if _, exists := storageByNode[node.Node]; !exists || storage.Storage == "local" {
 								storageByNode[node.Node] = disk
-								log.Debug().
+								log.Debug().Ctx(ctx).
 									Str("node", node.Node).
 									Str("storage", storage.Storage).
 									Float64("usage", disk.Usage).
@@ -4886,7 +5022,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 	// Poll physical disks for health monitoring (enabled by default unless explicitly disabled)
 	// Skip if MonitorPhysicalDisks is explicitly set to false
 	if instanceCfg.MonitorPhysicalDisks != nil && !*instanceCfg.MonitorPhysicalDisks {
-		log.Debug().Str("instance", instanceName).Msg("Physical disk monitoring explicitly disabled")
+		log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Physical disk monitoring explicitly disabled")
 		// Keep any existing disk data visible (don't clear it)
 	} else {
 		// Enabled by default (when nil or true)
@@ -4906,7 +5042,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 		m.mu.Unlock()
 
 		if !shouldPoll {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", instanceName).
 				Dur("sinceLastPoll", time.Since(lastPoll)).
 				Dur("interval", pollingInterval).
@@ -4924,7 +5060,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 				m.state.UpdatePhysicalDisks(instanceName, updated)
 			}
 		} else {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Int("nodeCount", len(nodes)).
 				Dur("interval", pollingInterval).
 				Msg("Starting disk health polling")
@@ -4944,26 +5080,26 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 			for _, node := range nodes {
 				// Skip offline nodes but preserve their existing disk data
 				if node.Status != "online" {
-					log.Debug().Str("node", node.Node).Msg("Skipping disk poll for offline node - preserving existing data")
+					log.Debug().Ctx(ctx).Str("node", node.Node).Msg("Skipping disk poll for offline node - preserving existing data")
 					continue
 				}
 
 				// Get disk list for this node
-				log.Debug().Str("node", node.Node).Msg("Getting disk list for node")
+				log.Debug().Ctx(ctx).Str("node", node.Node).Msg("Getting disk list for node")
 				disks, err := client.GetDisks(ctx, node.Node)
 				if err != nil {
 					// Check if it's a permission error or if the endpoint doesn't exist
 					if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "403") {
-						log.Warn().
+						log.Warn().Ctx(ctx).
 							Str("node", node.Node).
 							Err(err).
 							Msg("Insufficient permissions to access disk information - check API token permissions")
 					} else if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "501") {
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("node", node.Node).
 							Msg("Disk monitoring not available on this node (may be using non-standard storage)")
 					} else {
-						log.Warn().
+						log.Warn().Ctx(ctx).
 							Str("node", node.Node).
 							Err(err).
 							Msg("Failed to get disk list")
@@ -4971,7 +5107,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 					continue
 				}
 
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("node", node.Node).
 					Int("diskCount", len(disks)).
 					Msg("Got disk list for node")
@@ -5001,7 +5137,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 
 					allDisks = append(allDisks, physicalDisk)
 
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("node", node.Node).
 						Str("disk", disk.DevPath).
 						Str("model", disk.Model).
@@ -5012,7 +5148,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 					normalizedHealth := strings.ToUpper(strings.TrimSpace(disk.Health))
 					if normalizedHealth != "" && normalizedHealth != "UNKNOWN" && normalizedHealth != "PASSED" && normalizedHealth != "OK" {
 						// Disk has failed or is failing - alert manager will handle this
-						log.Warn().
+						log.Warn().Ctx(ctx).
 							Str("node", node.Node).
 							Str("disk", disk.DevPath).
 							Str("model", disk.Model).
@@ -5024,7 +5160,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 						m.alertManager.CheckDiskHealth(instanceName, node.Node, disk)
 					} else if disk.Wearout > 0 && disk.Wearout < 10 {
 						// Low wearout warning (less than 10% life remaining)
-						log.Warn().
+						log.Warn().Ctx(ctx).
 							Str("node", node.Node).
 							Str("disk", disk.DevPath).
 							Str("model", disk.Model).
@@ -5043,7 +5179,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 				if !polledNodes[existingDisk.Node] {
 					// Keep the existing disk data but update the LastChecked to indicate it's stale
 					allDisks = append(allDisks, existingDisk)
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("node", existingDisk.Node).
 						Str("disk", existingDisk.DevPath).
 						Msg("Preserving existing disk data for unpolled node")
@@ -5053,7 +5189,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 			allDisks = mergeNVMeTempsIntoDisks(allDisks, modelNodes)
 
 			// Update physical disks in state
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", instanceName).
 				Int("diskCount", len(allDisks)).
 				Int("preservedCount", len(existingDisksMap)-len(polledNodes)).
@@ -5069,7 +5205,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 		if modelNodes[i].Disk.Total == 0 {
 			if disk, exists := storageByNode[modelNodes[i].Name]; exists {
 				modelNodes[i].Disk = disk
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("node", modelNodes[i].Name).
 					Float64("usage", disk.Usage).
 					Msg("Applied storage fallback for disk metrics")
@@ -5085,7 +5221,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 		}
 
 		// Check thresholds for alerts
-		m.alertManager.CheckNode(modelNodes[i])
+		m.traceAlertCheck(ctx, "CheckNode", modelNodes[i].ID, func() { m.alertManager.CheckNode(modelNodes[i]) })
 	}
 
 	// Update state again with corrected disk metrics
@@ -5111,7 +5247,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 			isActuallyCluster, checkErr := client.IsClusterMember(ctx)
 			if checkErr == nil && isActuallyCluster {
 				// This node is actually part of a cluster!
-				log.Info().
+				log.Info().Ctx(ctx).
 					Str("instance", instanceName).
 					Msg("Detected that standalone node is actually part of a cluster - updating configuration")
 
@@ -5121,14 +5257,14 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 						m.config.PVEInstances[i].IsCluster = true
 						// Note: We can't get the cluster name here without direct client access
 						// It will be detected on the next configuration update
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("instance", instanceName).
 							Msg("Marked node as cluster member - cluster name will be detected on next update")
 
 							// Save the updated configuration
 						if m.persistence != nil {
 							if err := m.persistence.SaveNodesConfig(m.config.PVEInstances, m.config.PBSInstances, m.config.PMGInstances); err != nil {
-								log.Warn().Err(err).Msg("Failed to persist updated node configuration")
+								log.Warn().Ctx(ctx).Err(err).Msg("Failed to persist updated node configuration")
 							}
 						}
 						break
@@ -5167,6 +5303,15 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 		}
 	}
 
+	// Track corosync quorum and HA manager state for clusters
+	m.pollClusterHA(ctx, instanceName, client, instanceCfg.IsCluster)
+
+	// Refresh pending updates, subscription and certificate status on a slower cadence
+	m.checkPVENodeMaintenance(instanceName, client, modelNodes)
+
+	// Collect new entries from the node task logs for task history and failed-task alerts
+	m.checkTaskLog(instanceName, client, modelNodes)
+
 	// Poll VMs and containers together using cluster/resources for efficiency
 	if instanceCfg.MonitorVMs || instanceCfg.MonitorContainers {
 		select {
@@ -5182,7 +5327,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 			if !useClusterEndpoint {
 				// Fall back to traditional polling only if cluster/resources not available
 				// This should be rare - only for very old Proxmox versions
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("instance", instanceName).
 					Msg("cluster/resources endpoint not available, using traditional polling")
 
@@ -5190,7 +5335,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 				if instanceCfg.IsCluster {
 					isActuallyCluster, checkErr := client.IsClusterMember(ctx)
 					if checkErr == nil && !isActuallyCluster {
-						log.Warn().
+						log.Warn().Ctx(ctx).
 							Str("instance", instanceName).
 							Msg("Instance marked as cluster but is actually standalone - consider updating configuration")
 						instanceCfg.IsCluster = false
@@ -5208,6 +5353,11 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 		}
 	}
 
+	// Guest totals are the only throughput known for nodes, so check them once guests are polled
+	if instanceCfg.MonitorVMs || instanceCfg.MonitorContainers {
+		m.checkNodeIOAnomalies(instanceName, modelNodes)
+	}
+
 	// Poll storage if enabled
 	if instanceCfg.MonitorStorage {
 		select {
@@ -5222,7 +5372,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 	// Poll backups if enabled - respect configured interval or cycle gating
 	if instanceCfg.MonitorBackups {
 		if !m.config.EnableBackupPolling {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", instanceName).
 				Msg("Skipping backup polling - globally disabled")
 		} else {
@@ -5235,7 +5385,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 			shouldPoll, reason, newLast := m.shouldRunBackupPoll(lastPoll, now)
 			if !shouldPoll {
 				if reason != "" {
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("instance", instanceName).
 						Str("reason", reason).
 						Msg("Skipping PVE backup polling this cycle")
@@ -5253,7 +5403,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 					// Run backup polling in a separate goroutine to avoid blocking real-time stats
 					go func(startTime time.Time, inst string, pveClient PVEClientInterface) {
 						timeout := m.calculateBackupOperationTimeout(inst)
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("instance", inst).
 							Dur("timeout", timeout).
 							Msg("Starting background backup/snapshot polling")
@@ -5272,7 +5422,7 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 						m.pollGuestSnapshots(backupCtx, inst, pveClient)
 
 						duration := time.Since(startTime)
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("instance", inst).
 							Dur("duration", duration).
 							Msg("Completed background backup/snapshot polling")
@@ -5291,19 +5441,24 @@ func (m *Monitor) pollPVEInstance(ctx context.Context, instanceName string, clie
 // pollVMsAndContainersEfficient uses the cluster/resources endpoint to get all VMs and containers in one call
 // This works on both clustered and standalone nodes for efficient polling
 func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceName string, client PVEClientInterface) bool {
-	log.Info().Str("instance", instanceName).Msg("Polling VMs and containers using efficient cluster/resources endpoint")
+	log.Info().Ctx(ctx).Str("instance", instanceName).Msg("Polling VMs and containers using efficient cluster/resources endpoint")
 
 	// Get all resources in a single API call
 	resources, err := client.GetClusterResources(ctx, "vm")
 	if err != nil {
-		log.Debug().Err(err).Str("instance", instanceName).Msg("cluster/resources not available, falling back to traditional polling")
+		log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("cluster/resources not available, falling back to traditional polling")
 		return false
 	}
 
 	var allVMs []models.VM
 	var allContainers []models.Container
+	filter := m.pveResourceFilter(instanceName)
 
 	for _, res := range resources {
+		if !filter.AllowsGuest(res.Node, res.VMID, splitGuestTags(res.Tags), res.Pool) {
+			continue
+		}
+
 		// Avoid duplicating node name in ID when instance name equals node name
 		var guestID string
 		if instanceName == res.Node {
@@ -5313,7 +5468,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 		}
 
 		// Debug log the resource type
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", instanceName).
 			Str("name", res.Name).
 			Int("vmid", res.VMID).
@@ -5364,7 +5519,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 				// First check if agent is enabled by getting VM status
 				status, err := client.GetVMStatus(ctx, res.Node, res.VMID)
 				if err != nil {
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Err(err).
 						Str("instance", instanceName).
 						Str("vm", res.Name).
@@ -5454,7 +5609,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 					// Always try to get filesystem info if agent is enabled
 					// Prefer guest agent data over cluster/resources data for accuracy
 					if detailedStatus.Agent > 0 {
-						log.Debug().
+						log.Debug().Ctx(ctx).
 							Str("instance", instanceName).
 							Str("vm", res.Name).
 							Int("vmid", res.VMID).
@@ -5468,50 +5623,50 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 							// Log more helpful error messages based on the error type
 							errMsg := err.Error()
 							if strings.Contains(errMsg, "500") || strings.Contains(errMsg, "QEMU guest agent is not running") {
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Int("vmid", res.VMID).
 									Msg("Guest agent enabled in VM config but not running inside guest OS. Install and start qemu-guest-agent in the VM")
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Msg("To verify: ssh into VM and run 'systemctl status qemu-guest-agent' or 'ps aux | grep qemu-ga'")
 							} else if strings.Contains(errMsg, "timeout") {
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Int("vmid", res.VMID).
 									Msg("Guest agent timeout - agent may be installed but not responding")
 							} else if strings.Contains(errMsg, "403") || strings.Contains(errMsg, "401") || strings.Contains(errMsg, "authentication error") {
 								// Permission error - user/token lacks required permissions
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Int("vmid", res.VMID).
 									Msg("VM disk monitoring permission denied. Check permissions:")
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Msg("• Proxmox 9: Ensure token/user has VM.GuestAgent.Audit privilege (Pulse setup adds this via PulseMonitor role)")
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Msg("• Proxmox 8: Ensure token/user has VM.Monitor privilege (Pulse setup adds this via PulseMonitor role)")
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Msg("• All versions: Sys.Audit is recommended for Ceph metrics and applied when available")
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Msg("• Re-run Pulse setup script if node was added before v4.7")
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Msg("• Verify guest agent is installed and running inside the VM")
 							} else {
-								log.Debug().
+								log.Debug().Ctx(ctx).
 									Err(err).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
@@ -5519,13 +5674,13 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 									Msg("Failed to get filesystem info from guest agent")
 							}
 						} else if len(fsInfo) == 0 {
-							log.Info().
+							log.Info().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", res.Name).
 								Int("vmid", res.VMID).
 								Msg("Guest agent returned no filesystem info - agent may need restart or VM may have no mounted filesystems")
 						} else {
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", res.Name).
 								Int("filesystems", len(fsInfo)).
@@ -5537,7 +5692,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 							var includedFS []string
 
 							// Log all filesystems received for debugging
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", res.Name).
 								Int("vmid", res.VMID).
@@ -5590,7 +5745,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 
 								if shouldSkip {
 									if reasonReadOnly != "" {
-										log.Debug().
+										log.Debug().Ctx(ctx).
 											Str("instance", instanceName).
 											Str("vm", res.Name).
 											Int("vmid", res.VMID).
@@ -5624,7 +5779,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 										Device:     fs.Disk,
 									})
 
-									log.Debug().
+									log.Debug().Ctx(ctx).
 										Str("instance", instanceName).
 										Str("vm", res.Name).
 										Int("vmid", res.VMID).
@@ -5637,7 +5792,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 										Msg("Including filesystem in disk usage calculation")
 								} else if fs.TotalBytes == 0 && len(fs.Mountpoint) > 0 {
 									skippedFS = append(skippedFS, fmt.Sprintf("%s(%s,0GB)", fs.Mountpoint, fs.Type))
-									log.Debug().
+									log.Debug().Ctx(ctx).
 										Str("instance", instanceName).
 										Str("vm", res.Name).
 										Int("vmid", res.VMID).
@@ -5648,7 +5803,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 							}
 
 							if len(skippedFS) > 0 {
-								log.Debug().
+								log.Debug().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Strs("skipped", skippedFS).
@@ -5656,7 +5811,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 							}
 
 							if len(includedFS) > 0 {
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Int("vmid", res.VMID).
@@ -5674,7 +5829,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 								// If reported disk is more than 2x the allocated disk, log a warning
 								// This could indicate we're getting host disk or network shares
 								if allocatedDiskGB > 0 && reportedDiskGB > allocatedDiskGB*2 {
-									log.Warn().
+									log.Warn().Ctx(ctx).
 										Str("instance", instanceName).
 										Str("vm", res.Name).
 										Int("vmid", res.VMID).
@@ -5690,7 +5845,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 								diskFree = totalBytes - usedBytes
 								diskUsage = safePercentage(float64(usedBytes), float64(totalBytes))
 
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Int("vmid", res.VMID).
@@ -5708,7 +5863,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 								if diskTotal > 0 {
 									diskUsage = -1 // Show as allocated size
 								}
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", res.Name).
 									Int("filesystems_found", len(fsInfo)).
@@ -5720,7 +5875,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 						if diskTotal > 0 {
 							diskUsage = -1 // Show as allocated size
 						}
-						log.Debug().
+						log.Debug().Ctx(ctx).
 							Str("instance", instanceName).
 							Str("vm", res.Name).
 							Int("vmid", res.VMID).
@@ -5729,7 +5884,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 					}
 				} else {
 					// No vmStatus available - keep cluster/resources data
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("instance", instanceName).
 						Str("vm", res.Name).
 						Int("vmid", res.VMID).
@@ -5803,6 +5958,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 				DiskWrite:         maxInt64(0, int64(diskWriteRate)),
 				Uptime:            int64(res.Uptime),
 				Template:          res.Template == 1,
+				Pool:              res.Pool,
 				LastSeen:          sampleTime,
 			}
 
@@ -5814,7 +5970,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 				for _, tag := range vm.Tags {
 					switch tag {
 					case "pulse-no-alerts", "pulse-monitor-only", "pulse-relaxed":
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("vm", vm.Name).
 							Str("node", vm.Node).
 							Str("tag", tag).
@@ -5837,7 +5993,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 			// For non-running VMs, zero out resource usage metrics to prevent false alerts
 			// Proxmox may report stale or residual metrics for stopped VMs
 			if vm.Status != "running" {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("vm", vm.Name).
 					Str("status", vm.Status).
 					Float64("originalCpu", vm.CPU).
@@ -5855,7 +6011,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 			}
 
 			// Check thresholds for alerts
-			m.alertManager.CheckGuest(vm, instanceName)
+			m.traceAlertCheck(ctx, "CheckGuest", vm.ID, func() { m.alertManager.CheckGuest(vm, instanceName) })
 
 		} else if res.Type == "lxc" {
 			// Skip templates if configured
@@ -5901,6 +6057,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 				DiskWrite:  maxInt64(0, int64(diskWriteRate)),
 				Uptime:     int64(res.Uptime),
 				Template:   res.Template == 1,
+				Pool:       res.Pool,
 				LastSeen:   time.Now(),
 			}
 
@@ -5912,7 +6069,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 				for _, tag := range container.Tags {
 					switch tag {
 					case "pulse-no-alerts", "pulse-monitor-only", "pulse-relaxed":
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("container", container.Name).
 							Str("node", container.Node).
 							Str("tag", tag).
@@ -5928,7 +6085,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 			// For non-running containers, zero out resource usage metrics to prevent false alerts
 			// Proxmox may report stale or residual metrics for stopped containers
 			if container.Status != "running" {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("container", container.Name).
 					Str("status", container.Status).
 					Float64("originalCpu", container.CPU).
@@ -5946,7 +6103,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 			}
 
 			// Check thresholds for alerts
-			m.alertManager.CheckGuest(container, instanceName)
+			m.traceAlertCheck(ctx, "CheckGuest", container.ID, func() { m.alertManager.CheckGuest(container, instanceName) })
 		}
 	}
 
@@ -5960,7 +6117,7 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 
 	m.pollReplicationStatus(ctx, instanceName, client, allVMs)
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("vms", len(allVMs)).
 		Int("containers", len(allContainers)).
@@ -5971,15 +6128,21 @@ func (m *Monitor) pollVMsAndContainersEfficient(ctx context.Context, instanceNam
 
 // pollBackupTasks polls backup tasks from a PVE instance
 func (m *Monitor) pollBackupTasks(ctx context.Context, instanceName string, client PVEClientInterface) {
-	log.Debug().Str("instance", instanceName).Msg("Polling backup tasks")
+	log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling backup tasks")
 
 	tasks, err := client.GetBackupTasks(ctx)
 	if err != nil {
 		monErr := errors.WrapAPIError("get_backup_tasks", instanceName, err, 0)
-		log.Error().Err(monErr).Str("instance", instanceName).Msg("Failed to get backup tasks")
+		log.Error().Ctx(ctx).Err(monErr).Str("instance", instanceName).Msg("Failed to get backup tasks")
 		return
 	}
 
+	// Update state with new backup tasks for this instance
+	m.state.UpdateBackupTasksForInstance(instanceName, convertBackupTasks(instanceName, tasks))
+}
+
+// convertBackupTasks maps vzdump tasks to the state model
+func convertBackupTasks(instanceName string, tasks []proxmox.Task) []models.BackupTask {
 	var backupTasks []models.BackupTask
 	for _, task := range tasks {
 		// Extract VMID from task ID (format: "UPID:node:pid:starttime:type:vmid:user@realm:")
@@ -6007,21 +6170,19 @@ func (m *Monitor) pollBackupTasks(ctx context.Context, instanceName string, clie
 
 		backupTasks = append(backupTasks, backupTask)
 	}
-
-	// Update state with new backup tasks for this instance
-	m.state.UpdateBackupTasksForInstance(instanceName, backupTasks)
+	return backupTasks
 }
 
 // pollReplicationStatus polls storage replication jobs for a PVE instance.
 func (m *Monitor) pollReplicationStatus(ctx context.Context, instanceName string, client PVEClientInterface, vms []models.VM) {
-	log.Debug().Str("instance", instanceName).Msg("Polling replication status")
+	log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling replication status")
 
 	jobs, err := client.GetReplicationStatus(ctx)
 	if err != nil {
 		errMsg := err.Error()
 		lowerMsg := strings.ToLower(errMsg)
 		if strings.Contains(errMsg, "501") || strings.Contains(errMsg, "404") || strings.Contains(lowerMsg, "not implemented") || strings.Contains(lowerMsg, "not supported") {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", instanceName).
 				Msg("Replication API not available on this Proxmox instance")
 			m.state.UpdateReplicationJobsForInstance(instanceName, []models.ReplicationJob{})
@@ -6029,7 +6190,7 @@ func (m *Monitor) pollReplicationStatus(ctx context.Context, instanceName string
 		}
 
 		monErr := errors.WrapAPIError("get_replication_status", instanceName, err, 0)
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Err(monErr).
 			Str("instance", instanceName).
 			Msg("Failed to get replication status")
@@ -6210,14 +6371,14 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 	case <-ctx.Done():
 		pollErr = ctx.Err()
 		if debugEnabled {
-			log.Debug().Str("instance", instanceName).Msg("Polling cancelled")
+			log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling cancelled")
 		}
 		return
 	default:
 	}
 
 	if debugEnabled {
-		log.Debug().Str("instance", instanceName).Msg("Polling PBS instance")
+		log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling PBS instance")
 	}
 
 	// Get instance config
@@ -6226,7 +6387,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 		if cfg.Name == instanceName {
 			instanceCfg = &cfg
 			if debugEnabled {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("instance", instanceName).
 					Bool("monitorDatastores", cfg.MonitorDatastores).
 					Msg("Found PBS instance config")
@@ -6235,7 +6396,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 		}
 	}
 	if instanceCfg == nil {
-		log.Error().Str("instance", instanceName).Msg("PBS instance config not found")
+		log.Error().Ctx(ctx).Str("instance", instanceName).Msg("PBS instance config not found")
 		return
 	}
 
@@ -6260,7 +6421,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 		m.state.SetConnectionHealth("pbs-"+instanceName, true)
 
 		if debugEnabled {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", instanceName).
 				Str("version", version.Version).
 				Bool("monitorDatastores", instanceCfg.MonitorDatastores).
@@ -6268,7 +6429,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 		}
 	} else {
 		if debugEnabled {
-			log.Debug().Err(versionErr).Str("instance", instanceName).Msg("Failed to get PBS version, trying fallback")
+			log.Debug().Ctx(ctx).Err(versionErr).Str("instance", instanceName).Msg("Failed to get PBS version, trying fallback")
 		}
 
 		ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
@@ -6281,14 +6442,14 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 			m.resetAuthFailures(instanceName, "pbs")
 			m.state.SetConnectionHealth("pbs-"+instanceName, true)
 
-			log.Info().
+			log.Info().Ctx(ctx).
 				Str("instance", instanceName).
 				Msg("PBS connected (version unavailable but datastores accessible)")
 		} else {
 			pbsInst.Status = "offline"
 			pbsInst.ConnectionHealth = "error"
 			monErr := errors.WrapConnectionError("get_pbs_version", instanceName, versionErr)
-			log.Error().Err(monErr).Str("instance", instanceName).Msg("Failed to connect to PBS")
+			log.Error().Ctx(ctx).Err(monErr).Str("instance", instanceName).Msg("Failed to connect to PBS")
 			m.state.SetConnectionHealth("pbs-"+instanceName, false)
 
 			if errors.IsAuthError(versionErr) || errors.IsAuthError(datastoreErr) {
@@ -6302,7 +6463,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 	nodeStatus, err := client.GetNodeStatus(ctx)
 	if err != nil {
 		if debugEnabled {
-			log.Debug().Err(err).Str("instance", instanceName).Msg("Could not get PBS node status (may need Sys.Audit permission)")
+			log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Could not get PBS node status (may need Sys.Audit permission)")
 		}
 	} else if nodeStatus != nil {
 		pbsInst.CPU = nodeStatus.CPU
@@ -6313,7 +6474,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 		}
 		pbsInst.Uptime = nodeStatus.Uptime
 
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", instanceName).
 			Float64("cpu", pbsInst.CPU).
 			Float64("memory", pbsInst.Memory).
@@ -6326,9 +6487,9 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 		datastores, err := client.GetDatastores(ctx)
 		if err != nil {
 			monErr := errors.WrapAPIError("get_datastores", instanceName, err, 0)
-			log.Error().Err(monErr).Str("instance", instanceName).Msg("Failed to get datastores")
+			log.Error().Ctx(ctx).Err(monErr).Str("instance", instanceName).Msg("Failed to get datastores")
 		} else {
-			log.Info().
+			log.Info().Ctx(ctx).
 				Str("instance", instanceName).
 				Int("count", len(datastores)).
 				Msg("Got PBS datastores")
@@ -6350,7 +6511,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 					total = used + avail
 				}
 
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("store", ds.Store).
 					Int64("total", total).
 					Int64("used", used).
@@ -6371,7 +6532,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 
 				namespaces, err := client.ListNamespaces(ctx, ds.Store, "", 0)
 				if err != nil {
-					log.Warn().Err(err).
+					log.Warn().Ctx(ctx).Err(err).
 						Str("instance", instanceName).
 						Str("datastore", ds.Store).
 						Msg("Failed to list namespaces")
@@ -6412,24 +6573,27 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 
 	// Update state and run alerts
 	m.state.UpdatePBSInstance(pbsInst)
-	log.Info().
+	if pbsInst.Status == "online" {
+		m.checkPBSNodeMaintenance(instanceName, client)
+	}
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Str("id", pbsInst.ID).
 		Int("datastores", len(pbsInst.Datastores)).
 		Msg("PBS instance updated in state")
 
 	if m.alertManager != nil {
-		m.alertManager.CheckPBS(pbsInst)
+		m.traceAlertCheck(ctx, "CheckPBS", pbsInst.ID, func() { m.alertManager.CheckPBS(pbsInst) })
 	}
 
 	// Poll backups if enabled
 	if instanceCfg.MonitorBackups {
 		if len(pbsInst.Datastores) == 0 {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", instanceName).
 				Msg("No PBS datastores available for backup polling")
 		} else if !m.config.EnableBackupPolling {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("instance", instanceName).
 				Msg("Skipping PBS backup polling - globally disabled")
 		} else {
@@ -6443,13 +6607,13 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 			shouldPoll, reason, newLast := m.shouldRunBackupPoll(lastPoll, now)
 			if !shouldPoll {
 				if reason != "" {
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("instance", instanceName).
 						Str("reason", reason).
 						Msg("Skipping PBS backup polling this cycle")
 				}
 			} else if inProgress {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("instance", instanceName).
 					Msg("PBS backup polling already in progress")
 			} else {
@@ -6475,7 +6639,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 							m.mu.Unlock()
 						}()
 
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("instance", inst).
 							Int("datastores", len(ds)).
 							Msg("Starting background PBS backup polling")
@@ -6485,7 +6649,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 
 						m.pollPBSBackups(backupCtx, inst, pbsClient, ds)
 
-						log.Info().
+						log.Info().Ctx(ctx).
 							Str("instance", inst).
 							Dur("duration", time.Since(start)).
 							Msg("Completed background PBS backup polling")
@@ -6494,7 +6658,7 @@ func (m *Monitor) pollPBSInstance(ctx context.Context, instanceName string, clie
 			}
 		}
 	} else {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", instanceName).
 			Msg("PBS backup monitoring disabled")
 	}
@@ -6534,14 +6698,14 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 	case <-ctx.Done():
 		pollErr = ctx.Err()
 		if debugEnabled {
-			log.Debug().Str("instance", instanceName).Msg("PMG polling cancelled by context")
+			log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("PMG polling cancelled by context")
 		}
 		return
 	default:
 	}
 
 	if debugEnabled {
-		log.Debug().Str("instance", instanceName).Msg("Polling PMG instance")
+		log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling PMG instance")
 	}
 
 	var instanceCfg *config.PMGInstance
@@ -6553,7 +6717,7 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 	}
 
 	if instanceCfg == nil {
-		log.Error().Str("instance", instanceName).Msg("PMG instance config not found")
+		log.Error().Ctx(ctx).Str("instance", instanceName).Msg("PMG instance config not found")
 		pollErr = fmt.Errorf("pmg instance config not found for %s", instanceName)
 		return
 	}
@@ -6573,13 +6737,13 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 	if err != nil {
 		monErr := errors.WrapConnectionError("pmg_get_version", instanceName, err)
 		pollErr = monErr
-		log.Error().Err(monErr).Str("instance", instanceName).Msg("Failed to connect to PMG instance")
+		log.Error().Ctx(ctx).Err(monErr).Str("instance", instanceName).Msg("Failed to connect to PMG instance")
 		m.state.SetConnectionHealth("pmg-"+instanceName, false)
 		m.state.UpdatePMGInstance(pmgInst)
 
 		// Check PMG offline status against alert thresholds
 		if m.alertManager != nil {
-			m.alertManager.CheckPMG(pmgInst)
+			m.traceAlertCheck(ctx, "CheckPMG", pmgInst.ID, func() { m.alertManager.CheckPMG(pmgInst) })
 		}
 
 		if errors.IsAuthError(err) {
@@ -6599,7 +6763,7 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 	cluster, err := client.GetClusterStatus(ctx, true)
 	if err != nil {
 		if debugEnabled {
-			log.Debug().Err(err).Str("instance", instanceName).Msg("Failed to retrieve PMG cluster status")
+			log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Failed to retrieve PMG cluster status")
 		}
 	}
 
@@ -6623,7 +6787,7 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 			// Fetch queue status for this node
 			if queueData, qErr := client.GetQueueStatus(ctx, entry.Name); qErr != nil {
 				if debugEnabled {
-					log.Debug().Err(qErr).
+					log.Debug().Ctx(ctx).Err(qErr).
 						Str("instance", instanceName).
 						Str("node", entry.Name).
 						Msg("Failed to fetch PMG queue status")
@@ -6664,7 +6828,7 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 		backups, backupErr := client.ListBackups(ctx, nodeName)
 		if backupErr != nil {
 			if debugEnabled {
-				log.Debug().Err(backupErr).
+				log.Debug().Ctx(ctx).Err(backupErr).
 					Str("instance", instanceName).
 					Str("node", nodeName).
 					Msg("Failed to list PMG configuration backups")
@@ -6692,14 +6856,14 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 	}
 
 	if debugEnabled {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("instance", instanceName).
 			Int("backupCount", len(pmgBackups)).
 			Msg("PMG backups polled")
 	}
 
 	if stats, err := client.GetMailStatistics(ctx, "day"); err != nil {
-		log.Warn().Err(err).Str("instance", instanceName).Msg("Failed to fetch PMG mail statistics")
+		log.Warn().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Failed to fetch PMG mail statistics")
 	} else if stats != nil {
 		pmgInst.MailStats = &models.PMGMailStats{
 			Timeframe:            "day",
@@ -6725,7 +6889,7 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 
 	if counts, err := client.GetMailCount(ctx, 24); err != nil {
 		if debugEnabled {
-			log.Debug().Err(err).Str("instance", instanceName).Msg("Failed to fetch PMG mail count data")
+			log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Failed to fetch PMG mail count data")
 		}
 	} else if len(counts) > 0 {
 		points := make([]models.PMGMailCountPoint, 0, len(counts))
@@ -6755,7 +6919,7 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 
 	if scores, err := client.GetSpamScores(ctx); err != nil {
 		if debugEnabled {
-			log.Debug().Err(err).Str("instance", instanceName).Msg("Failed to fetch PMG spam score distribution")
+			log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Failed to fetch PMG spam score distribution")
 		}
 	} else if len(scores) > 0 {
 		buckets := make([]models.PMGSpamBucket, 0, len(scores))
@@ -6779,7 +6943,13 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 
 	m.state.UpdatePMGBackups(instanceName, pmgBackups)
 	m.state.UpdatePMGInstance(pmgInst)
-	log.Info().
+	maintenanceNodes := make([]string, 0, len(backupNodes))
+	for nodeName := range backupNodes {
+		maintenanceNodes = append(maintenanceNodes, nodeName)
+	}
+	sort.Strings(maintenanceNodes)
+	m.checkPMGNodeMaintenance(instanceName, client, maintenanceNodes)
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Str("status", pmgInst.Status).
 		Int("nodes", len(pmgInst.Nodes)).
@@ -6787,7 +6957,7 @@ func (m *Monitor) pollPMGInstance(ctx context.Context, instanceName string, clie
 
 	// Check PMG metrics against alert thresholds
 	if m.alertManager != nil {
-		m.alertManager.CheckPMG(pmgInst)
+		m.traceAlertCheck(ctx, "CheckPMG", pmgInst.ID, func() { m.alertManager.CheckPMG(pmgInst) })
 	}
 }
 
@@ -6873,7 +7043,7 @@ func (m *Monitor) StartDiscoveryService(ctx context.Context, wsHub *websocket.Hu
 	defer m.mu.Unlock()
 
 	if m.discoveryService != nil {
-		log.Debug().Msg("Discovery service already running")
+		log.Debug().Ctx(ctx).Msg("Discovery service already running")
 		return
 	}
 
@@ -6881,21 +7051,13 @@ func (m *Monitor) StartDiscoveryService(ctx context.Context, wsHub *websocket.Hu
 		subnet = "auto"
 	}
 
-	cfgProvider := func() config.DiscoveryConfig {
-		m.mu.RLock()
-		defer m.mu.RUnlock()
-		if m.config == nil {
-			return config.DefaultDiscoveryConfig()
-		}
-		return config.CloneDiscoveryConfig(m.config.Discovery)
-	}
-
-	m.discoveryService = discovery.NewService(wsHub, 5*time.Minute, subnet, cfgProvider)
+	m.discoveryService = discovery.NewService(wsHub, 5*time.Minute, subnet, m.discoveryConfig)
 	if m.discoveryService != nil {
+		m.discoveryService.SetOnboarder(m.discoveryOnboarder)
 		m.discoveryService.Start(ctx)
-		log.Info().Str("subnet", subnet).Msg("Discovery service started")
+		log.Info().Ctx(ctx).Str("subnet", subnet).Msg("Discovery service started")
 	} else {
-		log.Error().Msg("Failed to create discovery service")
+		log.Error().Ctx(ctx).Msg("Failed to create discovery service")
 	}
 }
 
@@ -6945,13 +7107,13 @@ func (m *Monitor) GetConfigPersistence() *config.ConfigPersistence {
 // Deprecated: This function should not be called directly as it causes duplicate GetNodes calls.
 // Use pollStorageBackupsWithNodes instead.
 func (m *Monitor) pollStorageBackups(ctx context.Context, instanceName string, client PVEClientInterface) {
-	log.Warn().Str("instance", instanceName).Msg("pollStorageBackups called directly - this causes duplicate GetNodes calls and syslog spam on non-clustered nodes")
+	log.Warn().Ctx(ctx).Str("instance", instanceName).Msg("pollStorageBackups called directly - this causes duplicate GetNodes calls and syslog spam on non-clustered nodes")
 
 	// Get all nodes
 	nodes, err := client.GetNodes(ctx)
 	if err != nil {
 		monErr := errors.WrapConnectionError("get_nodes_for_backups", instanceName, err)
-		log.Error().Err(monErr).Str("instance", instanceName).Msg("Failed to get nodes for backup polling")
+		log.Error().Ctx(ctx).Err(monErr).Str("instance", instanceName).Msg("Failed to get nodes for backup polling")
 		return
 	}
 
@@ -6969,6 +7131,8 @@ func (m *Monitor) pollStorageBackupsWithNodes(ctx context.Context, instanceName
 	contentFailures := 0                // Number of failed storage content fetches
 	storageQueryErrors := 0             // Number of nodes where storage list could not be queried
 
+	filter := m.pveResourceFilter(instanceName)
+
 	// For each node, get storage and check content
 	for _, node := range nodes {
 		if node.Status != "online" {
@@ -6988,7 +7152,7 @@ func (m *Monitor) pollStorageBackupsWithNodes(ctx context.Context, instanceName
 			// Check if it's a timeout error
 			if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline exceeded") {
 				if attempt == 1 {
-					log.Warn().
+					log.Warn().Ctx(ctx).
 						Str("node", node.Node).
 						Str("instance", instanceName).
 						Msg("Storage query timed out, retrying with extended timeout...")
@@ -7003,13 +7167,15 @@ func (m *Monitor) pollStorageBackupsWithNodes(ctx context.Context, instanceName
 
 		if err != nil {
 			monErr := errors.NewMonitorError(errors.ErrorTypeAPI, "get_storage_for_backups", instanceName, err).WithNode(node.Node)
-			log.Warn().Err(monErr).Str("node", node.Node).Msg("Failed to get storage for backups - skipping node")
+			log.Warn().Ctx(ctx).Err(monErr).Str("node", node.Node).Msg("Failed to get storage for backups - skipping node")
 			storageQueryErrors++
 			continue
 		}
 
 		hadSuccessfulNode = true
 
+		storages = filterPVEStorage(filter, storages)
+
 		// For each storage that can contain backups or templates
 		for _, storage := range storages {
 			// Check if storage supports backup content
@@ -7023,7 +7189,7 @@ func (m *Monitor) pollStorageBackupsWithNodes(ctx context.Context, instanceName
 			contents, err := client.GetStorageContent(ctx, node.Node, storage.Storage)
 			if err != nil {
 				monErr := errors.NewMonitorError(errors.ErrorTypeAPI, "get_storage_content", instanceName, err).WithNode(node.Node)
-				log.Debug().Err(monErr).
+				log.Debug().Ctx(ctx).Err(monErr).
 					Str("node", node.Node).
 					Str("storage", storage.Storage).
 					Msg("Failed to get storage content")
@@ -7110,13 +7276,13 @@ func (m *Monitor) pollStorageBackupsWithNodes(ctx context.Context, instanceName
 	// Decide whether to keep existing backups when every query failed
 	if shouldPreserveBackups(len(nodes), hadSuccessfulNode, storagesWithBackup, contentSuccess) {
 		if len(nodes) > 0 && !hadSuccessfulNode {
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Str("instance", instanceName).
 				Int("nodes", len(nodes)).
 				Int("errors", storageQueryErrors).
 				Msg("Failed to query storage on all nodes; keeping previous backup list")
 		} else if storagesWithBackup > 0 && contentSuccess == 0 {
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Str("instance", instanceName).
 				Int("storages", storagesWithBackup).
 				Int("failures", contentFailures).
@@ -7143,10 +7309,12 @@ func (m *Monitor) pollStorageBackupsWithNodes(ctx context.Context, instanceName
 		if len(pmgBackups) == 0 && len(snapshot.PMGBackups) > 0 {
 			pmgBackups = snapshot.PMGBackups
 		}
-		m.alertManager.CheckBackups(pveStorage, pbsBackups, pmgBackups, guestsByKey, guestsByVMID)
+		m.traceAlertCheck(ctx, "CheckBackups", instanceName, func() {
+			m.alertManager.CheckBackups(pveStorage, pbsBackups, pmgBackups, guestsByKey, guestsByVMID)
+		})
 	}
 
-	log.Debug().
+	log.Debug().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("count", len(allBackups)).
 		Msg("Storage backups polled")
@@ -7243,7 +7411,7 @@ func (m *Monitor) calculateBackupOperationTimeout(instanceName string) time.Dura
 
 // pollGuestSnapshots polls snapshots for all VMs and containers
 func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, client PVEClientInterface) {
-	log.Debug().Str("instance", instanceName).Msg("Polling guest snapshots")
+	log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling guest snapshots")
 
 	// Get current VMs and containers from state for this instance
 	m.mu.RLock()
@@ -7308,7 +7476,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 	if deadline, ok := ctx.Deadline(); ok {
 		remaining := time.Until(deadline)
 		if remaining <= 0 {
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Str("instance", instanceName).
 				Msg("Skipping guest snapshot polling; backup context deadline exceeded")
 			return
@@ -7321,7 +7489,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 	snapshotCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	log.Debug().
+	log.Debug().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("guestCount", activeGuests).
 		Dur("timeout", timeout).
@@ -7340,7 +7508,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 		snapshots, err := client.GetVMSnapshots(snapshotCtx, vm.Node, vm.VMID)
 		if err != nil {
 			if snapshotCtx.Err() != nil {
-				log.Warn().
+				log.Warn().Ctx(ctx).
 					Str("instance", instanceName).
 					Str("node", vm.Node).
 					Int("vmid", vm.VMID).
@@ -7351,7 +7519,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 			}
 			// This is common for VMs without snapshots, so use debug level
 			monErr := errors.NewMonitorError(errors.ErrorTypeAPI, "get_vm_snapshots", instanceName, err).WithNode(vm.Node)
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Err(monErr).
 				Str("node", vm.Node).
 				Int("vmid", vm.VMID).
@@ -7361,7 +7529,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 
 		for _, snap := range snapshots {
 			snapshot := models.GuestSnapshot{
-				ID:          fmt.Sprintf("%s-%s-%d-%s", instanceName, vm.Node, vm.VMID, snap.Name),
+				ID:          guestSnapshotID(instanceName, vm.Node, vm.VMID, snap.Name),
 				Name:        snap.Name,
 				Node:        vm.Node,
 				Instance:    instanceName,
@@ -7378,7 +7546,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 	}
 
 	if deadlineExceeded {
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Str("instance", instanceName).
 			Msg("Guest snapshot polling timed out before completing VM collection; retaining previous snapshots")
 		return
@@ -7394,7 +7562,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 		snapshots, err := client.GetContainerSnapshots(snapshotCtx, ct.Node, ct.VMID)
 		if err != nil {
 			if snapshotCtx.Err() != nil {
-				log.Warn().
+				log.Warn().Ctx(ctx).
 					Str("instance", instanceName).
 					Str("node", ct.Node).
 					Int("vmid", ct.VMID).
@@ -7411,7 +7579,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 			}
 			// Log other errors at debug level
 			monErr := errors.NewMonitorError(errors.ErrorTypeAPI, "get_container_snapshots", instanceName, err).WithNode(ct.Node)
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Err(monErr).
 				Str("node", ct.Node).
 				Int("vmid", ct.VMID).
@@ -7421,7 +7589,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 
 		for _, snap := range snapshots {
 			snapshot := models.GuestSnapshot{
-				ID:          fmt.Sprintf("%s-%s-%d-%s", instanceName, ct.Node, ct.VMID, snap.Name),
+				ID:          guestSnapshotID(instanceName, ct.Node, ct.VMID, snap.Name),
 				Name:        snap.Name,
 				Node:        ct.Node,
 				Instance:    instanceName,
@@ -7438,7 +7606,7 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 	}
 
 	if deadlineExceeded || snapshotCtx.Err() != nil {
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Str("instance", instanceName).
 			Msg("Guest snapshot polling timed out before completion; retaining previous snapshots")
 		return
@@ -7459,10 +7627,12 @@ func (m *Monitor) pollGuestSnapshots(ctx context.Context, instanceName string, c
 	m.state.UpdateGuestSnapshotsForInstance(instanceName, allSnapshots)
 
 	if m.alertManager != nil {
-		m.alertManager.CheckSnapshotsForInstance(instanceName, allSnapshots, guestNames)
+		m.traceAlertCheck(ctx, "CheckSnapshots", instanceName, func() {
+			m.alertManager.CheckSnapshotsForInstance(instanceName, allSnapshots, guestNames)
+		})
 	}
 
-	log.Debug().
+	log.Debug().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("count", len(allSnapshots)).
 		Msg("Guest snapshots polled")
@@ -7497,7 +7667,7 @@ func (m *Monitor) collectSnapshotSizes(ctx context.Context, instanceName string,
 
 		storages, err := client.GetStorage(ctx, nodeName)
 		if err != nil {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Err(err).
 				Str("node", nodeName).
 				Str("instance", instanceName).
@@ -7517,7 +7687,7 @@ func (m *Monitor) collectSnapshotSizes(ctx context.Context, instanceName string,
 
 			contents, err := client.GetStorageContent(ctx, nodeName, storage.Storage)
 			if err != nil {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Err(err).
 					Str("node", nodeName).
 					Str("storage", storage.Storage).
@@ -7738,7 +7908,7 @@ func (m *Monitor) removeFailedPMGInstance(instanceName string) {
 
 // pollPBSBackups fetches all backups from PBS datastores
 func (m *Monitor) pollPBSBackups(ctx context.Context, instanceName string, client *pbs.Client, datastores []models.PBSDatastore) {
-	log.Debug().Str("instance", instanceName).Msg("Polling PBS backups")
+	log.Debug().Ctx(ctx).Str("instance", instanceName).Msg("Polling PBS backups")
 
 	var allBackups []models.PBSBackup
 
@@ -7750,7 +7920,7 @@ func (m *Monitor) pollPBSBackups(ctx context.Context, instanceName string, clien
 			namespacePaths = append(namespacePaths, ns.Path)
 		}
 
-		log.Info().
+		log.Info().Ctx(ctx).
 			Str("instance", instanceName).
 			Str("datastore", ds.Name).
 			Int("namespaces", len(namespacePaths)).
@@ -7760,7 +7930,7 @@ func (m *Monitor) pollPBSBackups(ctx context.Context, instanceName string, clien
 		// Fetch backups from all namespaces concurrently
 		backupsMap, err := client.ListAllBackups(ctx, ds.Name, namespacePaths)
 		if err != nil {
-			log.Error().Err(err).
+			log.Error().Ctx(ctx).Err(err).
 				Str("instance", instanceName).
 				Str("datastore", ds.Name).
 				Msg("Failed to fetch PBS backups")
@@ -7804,7 +7974,7 @@ func (m *Monitor) pollPBSBackups(ctx context.Context, instanceName string, clien
 					}
 
 					// Debug log verification data
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("vmid", snapshot.BackupID).
 						Int64("time", snapshot.BackupTime).
 						Interface("verification", snapshot.Verification).
@@ -7833,7 +8003,7 @@ func (m *Monitor) pollPBSBackups(ctx context.Context, instanceName string, clien
 		}
 	}
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("count", len(allBackups)).
 		Msg("PBS backups fetched")
@@ -7856,7 +8026,9 @@ func (m *Monitor) pollPBSBackups(ctx context.Context, instanceName string, clien
 		if len(pmgBackups) == 0 && len(snapshot.PMGBackups) > 0 {
 			pmgBackups = snapshot.PMGBackups
 		}
-		m.alertManager.CheckBackups(pveStorage, pbsBackups, pmgBackups, guestsByKey, guestsByVMID)
+		m.traceAlertCheck(ctx, "CheckBackups", instanceName, func() {
+			m.alertManager.CheckBackups(pveStorage, pbsBackups, pmgBackups, guestsByKey, guestsByVMID)
+		})
 	}
 }
 
@@ -34,8 +34,9 @@ func (m *Monitor) describeInstancesForScheduler() []InstanceDescriptor {
 		sort.Strings(names)
 		for _, name := range names {
 			desc := InstanceDescriptor{
-				Name: name,
-				Type: InstanceTypePVE,
+				Name:             name,
+				Type:             InstanceTypePVE,
+				IntervalOverride: m.pollIntervalOverride(InstanceTypePVE, name),
 			}
 			if m.scheduler != nil {
 				if last, ok := m.scheduler.LastScheduled(InstanceTypePVE, name); ok {
@@ -131,12 +132,24 @@ func (m *Monitor) buildScheduledTasks(now time.Time) []ScheduledTask {
 			interval = DefaultSchedulerConfig().BaseInterval
 		}
 		for _, desc := range descriptors {
-			tasks = append(tasks, ScheduledTask{
+			task := ScheduledTask{
 				InstanceName: desc.Name,
 				InstanceType: desc.Type,
 				NextRun:      now,
 				Interval:     interval,
-			})
+			}
+			if desc.IntervalOverride > 0 {
+				// Hold the task back until a full override interval has passed since the last poll
+				task.Interval = desc.IntervalOverride
+				last := desc.LastSuccess
+				if desc.LastFailure.After(last) {
+					last = desc.LastFailure
+				}
+				if next := last.Add(desc.IntervalOverride); next.After(now) {
+					task.NextRun = next
+				}
+			}
+			tasks = append(tasks, task)
 		}
 		return tasks
 	}
@@ -197,6 +210,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 
 	resultChan := make(chan nodeResult, len(nodes))
 	var wg sync.WaitGroup
+	filter := m.pveFallbackResourceFilter(instanceName)
 
 	// Count online nodes for logging
 	onlineNodes := 0
@@ -206,7 +220,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 		}
 	}
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("totalNodes", len(nodes)).
 		Int("onlineNodes", onlineNodes).
@@ -216,7 +230,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 	for _, node := range nodes {
 		// Skip offline nodes
 		if node.Status != "online" {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", node.Node).
 				Str("status", node.Status).
 				Msg("Skipping offline node for VM polling")
@@ -233,7 +247,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 			vms, err := client.GetVMs(ctx, n.Node)
 			if err != nil {
 				monErr := errors.NewMonitorError(errors.ErrorTypeAPI, "get_vms", instanceName, err).WithNode(n.Node)
-				log.Error().Err(monErr).Str("node", n.Node).Msg("Failed to get VMs; deferring node poll until next cycle")
+				log.Error().Ctx(ctx).Err(monErr).Str("node", n.Node).Msg("Failed to get VMs; deferring node poll until next cycle")
 				resultChan <- nodeResult{node: n.Node, err: err}
 				return
 			}
@@ -246,6 +260,9 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 				if vm.Template == 1 {
 					continue
 				}
+				if !filter.AllowsGuestInUnknownPool(n.Node, vm.VMID, splitGuestTags(vm.Tags)) {
+					continue
+				}
 
 				// Parse tags
 				var tags []string
@@ -425,7 +442,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 
 				// Debug log disk I/O rates
 				if diskReadRate > 0 || diskWriteRate > 0 {
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("vm", vm.Name).
 						Int("vmid", vm.VMID).
 						Float64("diskReadRate", diskReadRate).
@@ -465,7 +482,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 				if vm.Status == "running" && vmStatus != nil && diskTotal > 0 {
 					// Log the initial state
 					if logging.IsLevelEnabled(zerolog.DebugLevel) {
-						log.Debug().
+						log.Debug().Ctx(ctx).
 							Str("instance", instanceName).
 							Str("vm", vm.Name).
 							Int("vmid", vm.VMID).
@@ -479,14 +496,14 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 					if vmStatus.Agent == 0 {
 						diskStatusReason = "agent-disabled"
 						if logging.IsLevelEnabled(zerolog.DebugLevel) {
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", vm.Name).
 								Msg("Guest agent disabled in VM config")
 						}
 					} else if vmStatus.Agent > 0 || diskUsed == 0 {
 						if logging.IsLevelEnabled(zerolog.DebugLevel) {
-							log.Debug().
+							log.Debug().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", vm.Name).
 								Int("vmid", vm.VMID).
@@ -497,7 +514,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 						if fsInfo, err := client.GetVMFSInfo(statusCtx, n.Node, vm.VMID); err != nil {
 							// Handle errors
 							errStr := err.Error()
-							log.Warn().
+							log.Warn().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", vm.Name).
 								Int("vmid", vm.VMID).
@@ -506,7 +523,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 
 							if strings.Contains(errStr, "QEMU guest agent is not running") {
 								diskStatusReason = "agent-not-running"
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", vm.Name).
 									Int("vmid", vm.VMID).
@@ -520,13 +537,13 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 							}
 						} else if len(fsInfo) == 0 {
 							diskStatusReason = "no-filesystems"
-							log.Warn().
+							log.Warn().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", vm.Name).
 								Int("vmid", vm.VMID).
 								Msg("Guest agent returned empty filesystem list")
 						} else {
-							log.Info().
+							log.Info().Ctx(ctx).
 								Str("instance", instanceName).
 								Str("vm", vm.Name).
 								Int("vmid", vm.VMID).
@@ -539,7 +556,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 
 							for _, fs := range fsInfo {
 								// Log each filesystem for debugging
-								log.Debug().
+								log.Debug().Ctx(ctx).
 									Str("vm", vm.Name).
 									Str("mountpoint", fs.Mountpoint).
 									Str("type", fs.Type).
@@ -554,7 +571,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 
 								if !isWindowsDrive {
 									if reason, skip := readOnlyFilesystemReason(fs.Type, fs.TotalBytes, fs.UsedBytes); skip {
-										log.Debug().
+										log.Debug().Ctx(ctx).
 											Str("vm", vm.Name).
 											Str("mountpoint", fs.Mountpoint).
 											Str("type", fs.Type).
@@ -574,7 +591,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 										fs.Mountpoint == "System Reserved" ||
 										strings.Contains(fs.Mountpoint, "System Reserved") ||
 										strings.HasPrefix(fs.Mountpoint, "/snap") { // Skip snap mounts
-										log.Debug().
+										log.Debug().Ctx(ctx).
 											Str("vm", vm.Name).
 											Str("mountpoint", fs.Mountpoint).
 											Str("type", fs.Type).
@@ -585,7 +602,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 
 								// Skip if we've already seen this device (duplicate mount point)
 								if fs.Disk != "" && seenDevices[fs.Disk] {
-									log.Debug().
+									log.Debug().Ctx(ctx).
 										Str("vm", vm.Name).
 										Str("mountpoint", fs.Mountpoint).
 										Str("disk", fs.Disk).
@@ -611,7 +628,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 										Type:       fs.Type,
 										Device:     fs.Disk,
 									})
-									log.Debug().
+									log.Debug().Ctx(ctx).
 										Str("vm", vm.Name).
 										Str("mountpoint", fs.Mountpoint).
 										Str("disk", fs.Disk).
@@ -619,7 +636,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 										Uint64("added_used", fs.UsedBytes).
 										Msg("Adding filesystem to total")
 								} else {
-									log.Debug().
+									log.Debug().Ctx(ctx).
 										Str("vm", vm.Name).
 										Str("mountpoint", fs.Mountpoint).
 										Msg("Skipping filesystem with 0 total bytes")
@@ -634,7 +651,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 								diskUsage = safePercentage(float64(usedBytes), float64(totalBytes))
 								diskStatusReason = "" // Clear reason on success
 
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", vm.Name).
 									Int("vmid", vm.VMID).
@@ -648,7 +665,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 								if diskTotal > 0 {
 									diskUsage = -1 // Show as allocated size
 								}
-								log.Info().
+								log.Info().Ctx(ctx).
 									Str("instance", instanceName).
 									Str("vm", vm.Name).
 									Int("filesystems_found", len(fsInfo)).
@@ -746,11 +763,11 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 				})
 
 				// Check alerts
-				m.alertManager.CheckGuest(modelVM, instanceName)
+				m.traceAlertCheck(ctx, "CheckGuest", modelVM.ID, func() { m.alertManager.CheckGuest(modelVM, instanceName) })
 			}
 
 			nodeDuration := time.Since(nodeStart)
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", n.Node).
 				Int("vms", len(nodeVMs)).
 				Dur("duration", nodeDuration).
@@ -784,7 +801,7 @@ func (m *Monitor) pollVMsWithNodes(ctx context.Context, instanceName string, cli
 	m.state.UpdateVMsForInstance(instanceName, allVMs)
 
 	duration := time.Since(startTime)
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("totalVMs", len(allVMs)).
 		Int("successfulNodes", successfulNodes).
@@ -806,6 +823,7 @@ func (m *Monitor) pollContainersWithNodes(ctx context.Context, instanceName stri
 
 	resultChan := make(chan nodeResult, len(nodes))
 	var wg sync.WaitGroup
+	filter := m.pveFallbackResourceFilter(instanceName)
 
 	// Count online nodes for logging
 	onlineNodes := 0
@@ -815,7 +833,7 @@ func (m *Monitor) pollContainersWithNodes(ctx context.Context, instanceName stri
 		}
 	}
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("totalNodes", len(nodes)).
 		Int("onlineNodes", onlineNodes).
@@ -825,7 +843,7 @@ func (m *Monitor) pollContainersWithNodes(ctx context.Context, instanceName stri
 	for _, node := range nodes {
 		// Skip offline nodes
 		if node.Status != "online" {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", node.Node).
 				Str("status", node.Status).
 				Msg("Skipping offline node for container polling")
@@ -842,11 +860,19 @@ func (m *Monitor) pollContainersWithNodes(ctx context.Context, instanceName stri
 			containers, err := client.GetContainers(ctx, n.Node)
 			if err != nil {
 				monErr := errors.NewMonitorError(errors.ErrorTypeAPI, "get_containers", instanceName, err).WithNode(n.Node)
-				log.Error().Err(monErr).Str("node", n.Node).Msg("Failed to get containers")
+				log.Error().Ctx(ctx).Err(monErr).Str("node", n.Node).Msg("Failed to get containers")
 				resultChan <- nodeResult{node: n.Node, err: err}
 				return
 			}
 
+			allowed := containers[:0]
+			for _, ct := range containers {
+				if filter.AllowsGuestInUnknownPool(n.Node, int(ct.VMID), splitGuestTags(ct.Tags)) {
+					allowed = append(allowed, ct)
+				}
+			}
+			containers = allowed
+
 			vmIDs := make([]int, 0, len(containers))
 			for _, ct := range containers {
 				if ct.Template == 1 {
@@ -991,11 +1017,11 @@ func (m *Monitor) pollContainersWithNodes(ctx context.Context, instanceName stri
 				nodeContainers = append(nodeContainers, modelContainer)
 
 				// Check alerts
-				m.alertManager.CheckGuest(modelContainer, instanceName)
+				m.traceAlertCheck(ctx, "CheckGuest", modelContainer.ID, func() { m.alertManager.CheckGuest(modelContainer, instanceName) })
 			}
 
 			nodeDuration := time.Since(nodeStart)
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", n.Node).
 				Int("containers", len(nodeContainers)).
 				Dur("duration", nodeDuration).
@@ -1029,7 +1055,7 @@ func (m *Monitor) pollContainersWithNodes(ctx context.Context, instanceName stri
 	m.state.UpdateContainersForInstance(instanceName, allContainers)
 
 	duration := time.Since(startTime)
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("totalContainers", len(allContainers)).
 		Int("successfulNodes", successfulNodes).
@@ -1046,7 +1072,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 	clusterStorages, err := client.GetAllStorage(ctx)
 	clusterStorageAvailable := err == nil
 	if err != nil {
-		log.Warn().Err(err).Str("instance", instanceName).Msg("Failed to get cluster storage config - will continue with node storage only")
+		log.Warn().Ctx(ctx).Err(err).Str("instance", instanceName).Msg("Failed to get cluster storage config - will continue with node storage only")
 	}
 
 	// Create a map for quick lookup of cluster storage config
@@ -1070,6 +1096,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 
 	resultChan := make(chan nodeResult, len(nodes))
 	var wg sync.WaitGroup
+	filter := m.pveResourceFilter(instanceName)
 
 	// Count online nodes for logging
 	onlineNodes := 0
@@ -1079,7 +1106,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 		}
 	}
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("instance", instanceName).
 		Int("totalNodes", len(nodes)).
 		Int("onlineNodes", onlineNodes).
@@ -1101,7 +1128,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 	for _, node := range nodes {
 		// Skip offline nodes but preserve their existing storage data
 		if node.Status != "online" {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", node.Node).
 				Str("status", node.Status).
 				Msg("Skipping offline node for storage polling - preserving existing data")
@@ -1119,7 +1146,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 			if err != nil {
 				// Handle timeout gracefully - unavailable storage (e.g., NFS mounts) can cause this
 				if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline exceeded") {
-					log.Warn().
+					log.Warn().Ctx(ctx).
 						Str("node", n.Node).
 						Str("instance", instanceName).
 						Msg("Storage query timed out - likely due to unavailable storage mounts. Preserving existing storage data for this node.")
@@ -1128,7 +1155,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 					return
 				}
 				// For other errors, log as error
-				log.Error().
+				log.Error().Ctx(ctx).
 					Err(err).
 					Str("node", n.Node).
 					Str("instance", instanceName).
@@ -1137,6 +1164,8 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 				return
 			}
 
+			nodeStorage = filterPVEStorage(filter, nodeStorage)
+
 			var nodeStorageList []models.Storage
 
 			// Get ZFS pool status for this node if any storage is ZFS
@@ -1155,7 +1184,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 
 				if hasZFSStorage {
 					if poolInfos, err := client.GetZFSPoolsWithDetails(ctx, n.Node); err == nil {
-						log.Debug().
+						log.Debug().Ctx(ctx).
 							Str("node", n.Node).
 							Int("pools", len(poolInfos)).
 							Msg("Successfully fetched ZFS pool details")
@@ -1169,7 +1198,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 						}
 					} else {
 						// Log but don't fail - ZFS monitoring is optional
-						log.Debug().
+						log.Debug().Ctx(ctx).
 							Err(err).
 							Str("node", n.Node).
 							Str("instance", instanceName).
@@ -1181,7 +1210,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 			// Process each storage
 			for _, storage := range nodeStorage {
 				if reason, skip := readOnlyFilesystemReason(storage.Type, storage.Total, storage.Used); skip {
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("node", n.Node).
 						Str("storage", storage.Storage).
 						Str("type", storage.Type).
@@ -1270,7 +1299,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 			}
 
 			nodeDuration := time.Since(nodeStart)
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", n.Node).
 				Int("storage", len(nodeStorageList)).
 				Dur("duration", nodeDuration).
@@ -1288,7 +1317,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 					}
 				}
 				if hasExisting {
-					log.Warn().
+					log.Warn().Ctx(ctx).
 						Str("node", n.Node).
 						Str("instance", instanceName).
 						Msg("Node returned empty storage but has existing storage - preserving existing data")
@@ -1387,7 +1416,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 		if !polledNodes[existingStorage.Node] && existingStorage.Node != "cluster" {
 			allStorage = append(allStorage, existingStorage)
 			preservedCount++
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", existingStorage.Node).
 				Str("storage", existingStorage.Name).
 				Msg("Preserving existing storage data for unpolled node")
@@ -1405,7 +1434,7 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 		}
 
 		if m.alertManager != nil {
-			m.alertManager.CheckStorage(storage)
+			m.traceAlertCheck(ctx, "CheckStorage", storage.ID, func() { m.alertManager.CheckStorage(storage) })
 		}
 	}
 
@@ -1428,12 +1457,12 @@ func (m *Monitor) pollStorageWithNodes(ctx context.Context, instanceName string,
 
 	// Warn if all nodes failed to get storage
 	if successfulNodes == 0 && failedNodes > 0 {
-		log.Error().
+		log.Error().Ctx(ctx).
 			Str("instance", instanceName).
 			Int("failedNodes", failedNodes).
 			Msg("All nodes failed to retrieve storage - check Proxmox API permissions for Datastore.Audit on all storage")
 	} else {
-		log.Info().
+		log.Info().Ctx(ctx).
 			Str("instance", instanceName).
 			Int("totalStorage", len(allStorage)).
 			Int("successfulNodes", successfulNodes).
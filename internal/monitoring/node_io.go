@@ -0,0 +1,49 @@
+package monitoring
+
+import "github.com/RouXx67/PulseUp/internal/models"
+
+// nodeGuestIO is the combined throughput of the running guests on a node in bytes/s
+type nodeGuestIO struct {
+	netIn, netOut, diskRead, diskWrite float64
+}
+
+// checkNodeIOAnomalies compares each node's guest traffic with its baseline. PVE nodes do not
+// report network or disk throughput, so the node's running guests are summed instead.
+func (m *Monitor) checkNodeIOAnomalies(instanceName string, nodes []models.Node) {
+	if m.alertManager == nil || len(nodes) == 0 {
+		return
+	}
+
+	snapshot := m.state.GetSnapshot()
+	totals := sumNodeGuestIO(instanceName, snapshot.VMs, snapshot.Containers)
+	for _, node := range nodes {
+		if node.Status != "online" {
+			continue
+		}
+		io := totals[node.Name]
+		m.alertManager.CheckNodeIO(node, io.netIn, io.netOut, io.diskRead, io.diskWrite)
+	}
+}
+
+func sumNodeGuestIO(instanceName string, vms []models.VM, containers []models.Container) map[string]nodeGuestIO {
+	totals := make(map[string]nodeGuestIO)
+	add := func(node string, netIn, netOut, diskRead, diskWrite int64) {
+		io := totals[node]
+		io.netIn += float64(max(netIn, 0))
+		io.netOut += float64(max(netOut, 0))
+		io.diskRead += float64(max(diskRead, 0))
+		io.diskWrite += float64(max(diskWrite, 0))
+		totals[node] = io
+	}
+	for _, vm := range vms {
+		if vm.Instance == instanceName && vm.Status == "running" {
+			add(vm.Node, vm.NetworkIn, vm.NetworkOut, vm.DiskRead, vm.DiskWrite)
+		}
+	}
+	for _, container := range containers {
+		if container.Instance == instanceName && container.Status == "running" {
+			add(container.Node, container.NetworkIn, container.NetworkOut, container.DiskRead, container.DiskWrite)
+		}
+	}
+	return totals
+}
@@ -0,0 +1,27 @@
+package monitoring
+
+import (
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func TestSumNodeGuestIO(t *testing.T) {
+	vms := []models.VM{
+		{Instance: "pve", Node: "pve1", Status: "running", NetworkIn: 100, NetworkOut: 200, DiskRead: 300, DiskWrite: 400},
+		{Instance: "pve", Node: "pve1", Status: "stopped", NetworkIn: 1000},
+		{Instance: "other", Node: "pve1", Status: "running", NetworkIn: 1000},
+	}
+	containers := []models.Container{
+		{Instance: "pve", Node: "pve1", Status: "running", NetworkIn: 10, NetworkOut: -1, DiskWrite: 5},
+		{Instance: "pve", Node: "pve2", Status: "running", DiskRead: 7},
+	}
+
+	totals := sumNodeGuestIO("pve", vms, containers)
+	if got := totals["pve1"]; got != (nodeGuestIO{netIn: 110, netOut: 200, diskRead: 300, diskWrite: 405}) {
+		t.Errorf("unexpected pve1 totals: %+v", got)
+	}
+	if got := totals["pve2"]; got.diskRead != 7 {
+		t.Errorf("unexpected pve2 totals: %+v", got)
+	}
+}
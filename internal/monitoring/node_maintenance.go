@@ -0,0 +1,454 @@
+package monitoring
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/pkg/pbs"
+	"github.com/RouXx67/PulseUp/pkg/pmg"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// nodeMaintenanceInterval is how often package, subscription and certificate status is refreshed.
+	// None of it changes quickly and apt/update can be slow on busy nodes.
+	nodeMaintenanceInterval = 30 * time.Minute
+	nodeMaintenanceTimeout  = 2 * time.Minute
+)
+
+// NodeMaintenanceClient is implemented by PVE clients that can read apt, subscription and certificate status.
+type NodeMaintenanceClient interface {
+	GetNodeAptUpdates(ctx context.Context, node string) ([]proxmox.AptUpdate, error)
+	GetNodeAptVersions(ctx context.Context, node string) ([]proxmox.AptPackageVersion, error)
+	GetNodeSubscription(ctx context.Context, node string) (*proxmox.NodeSubscription, error)
+	GetNodeCertificates(ctx context.Context, node string) ([]proxmox.NodeCertificate, error)
+}
+
+// kernelPackagePattern matches versioned kernel image packages such as
+// proxmox-kernel-6.8.12-4-pve-signed or pve-kernel-5.15.108-1-pve.
+var kernelPackagePattern = regexp.MustCompile(`^(?:proxmox|pve)-kernel-(\d+\.\d+\.\d+-\d+-pve)(?:-signed)?$`)
+
+// maintenanceSample is the product-neutral result of one maintenance check for a node.
+// A nil field means the endpoint could not be read.
+type maintenanceSample struct {
+	node          string
+	updates       []models.PendingUpdate
+	runningKernel string
+	kernels       []string
+	subscription  *models.NodeSubscription
+	certificates  []models.NodeCertificate
+	errors        []string
+}
+
+// maintenanceDue reports whether an instance's maintenance status should be refreshed and
+// reserves the slot so overlapping polls don't start a second check.
+func (m *Monitor) maintenanceDue(platform, instanceName string, now time.Time) bool {
+	m.maintenanceMu.Lock()
+	defer m.maintenanceMu.Unlock()
+
+	if m.maintenanceChecked == nil {
+		m.maintenanceChecked = make(map[string]time.Time)
+	}
+	key := platform + ":" + instanceName
+	if last, ok := m.maintenanceChecked[key]; ok && now.Sub(last) < nodeMaintenanceInterval {
+		return false
+	}
+	m.maintenanceChecked[key] = now
+	return true
+}
+
+// backgroundContext returns a context bound to the monitor's lifetime for checks that run
+// outside the poll cycle
+func (m *Monitor) backgroundContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	m.mu.RLock()
+	parent := m.runtimeCtx
+	m.mu.RUnlock()
+	if parent == nil {
+		parent = context.Background()
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// checkPVENodeMaintenance refreshes maintenance status for the online nodes of a PVE instance in the background
+func (m *Monitor) checkPVENodeMaintenance(instanceName string, client PVEClientInterface, nodes []models.Node) {
+	maintenanceClient, ok := client.(NodeMaintenanceClient)
+	if !ok || !m.maintenanceDue("pve", instanceName, time.Now()) {
+		return
+	}
+
+	var nodeNames []string
+	for _, node := range nodes {
+		if node.Status == "online" {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+	if len(nodeNames) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := m.backgroundContext(nodeMaintenanceTimeout)
+		defer cancel()
+
+		samples := make([]maintenanceSample, 0, len(nodeNames))
+		for _, node := range nodeNames {
+			if ctx.Err() != nil {
+				break
+			}
+			samples = append(samples, collectPVEMaintenance(ctx, maintenanceClient, node))
+		}
+		m.applyNodeMaintenance("pve", instanceName, samples, time.Now())
+	}()
+}
+
+// checkPBSNodeMaintenance refreshes maintenance status for a PBS instance in the background
+func (m *Monitor) checkPBSNodeMaintenance(instanceName string, client *pbs.Client) {
+	if client == nil || !m.maintenanceDue("pbs", instanceName, time.Now()) {
+		return
+	}
+
+	go func() {
+		ctx, cancel := m.backgroundContext(nodeMaintenanceTimeout)
+		defer cancel()
+
+		sample := collectPBSMaintenance(ctx, client, instanceName)
+		m.applyNodeMaintenance("pbs", instanceName, []maintenanceSample{sample}, time.Now())
+	}()
+}
+
+// checkPMGNodeMaintenance refreshes maintenance status for every node of a PMG instance in the background
+func (m *Monitor) checkPMGNodeMaintenance(instanceName string, client *pmg.Client, nodeNames []string) {
+	if client == nil || len(nodeNames) == 0 || !m.maintenanceDue("pmg", instanceName, time.Now()) {
+		return
+	}
+
+	go func() {
+		ctx, cancel := m.backgroundContext(nodeMaintenanceTimeout)
+		defer cancel()
+
+		samples := make([]maintenanceSample, 0, len(nodeNames))
+		for _, node := range nodeNames {
+			if ctx.Err() != nil {
+				break
+			}
+			samples = append(samples, collectPMGMaintenance(ctx, client, node))
+		}
+
+		m.applyNodeMaintenance("pmg", instanceName, samples, time.Now())
+	}()
+}
+
+func collectPVEMaintenance(ctx context.Context, client NodeMaintenanceClient, node string) maintenanceSample {
+	sample := maintenanceSample{node: node}
+
+	if updates, err := client.GetNodeAptUpdates(ctx, node); err != nil {
+		sample.addError("updates", err)
+	} else {
+		sample.updates = make([]models.PendingUpdate, 0, len(updates))
+		for _, update := range updates {
+			sample.updates = append(sample.updates, pendingUpdate(update.Package, update.Version, update.OldVersion, update.Origin, update.Section, update.Title))
+		}
+	}
+	if versions, err := client.GetNodeAptVersions(ctx, node); err != nil {
+		sample.addError("kernel", err)
+	} else {
+		for _, version := range versions {
+			sample.addPackage(version.Package, version.CurrentState, version.RunningKernel, version.ExtraInfo)
+		}
+	}
+	if subscription, err := client.GetNodeSubscription(ctx, node); err != nil {
+		sample.addError("subscription", err)
+	} else if subscription != nil {
+		sample.subscription = nodeSubscription(subscription.Status, subscription.Level, subscription.ProductName, subscription.NextDueDate)
+	}
+	if certificates, err := client.GetNodeCertificates(ctx, node); err != nil {
+		sample.addError("certificates", err)
+	} else {
+		sample.certificates = make([]models.NodeCertificate, 0, len(certificates))
+		for _, cert := range certificates {
+			sample.certificates = append(sample.certificates, nodeCertificate(cert.Filename, cert.Subject, cert.Issuer, cert.Fingerprint, cert.SAN, cert.NotBefore, cert.NotAfter))
+		}
+	}
+
+	return sample
+}
+
+// collectPBSMaintenance reads maintenance status from a PBS server. PBS is a single node, so the
+// instance name stands in for the node name.
+func collectPBSMaintenance(ctx context.Context, client *pbs.Client, instanceName string) maintenanceSample {
+	sample := maintenanceSample{node: instanceName}
+	if updates, err := client.GetAptUpdates(ctx); err != nil {
+		sample.addError("updates", err)
+	} else {
+		sample.updates = make([]models.PendingUpdate, 0, len(updates))
+		for _, update := range updates {
+			sample.updates = append(sample.updates, pendingUpdate(update.Package, update.Version, update.OldVersion, update.Origin, update.Section, update.Title))
+		}
+	}
+	if versions, err := client.GetAptVersions(ctx); err != nil {
+		sample.addError("kernel", err)
+	} else {
+		for _, version := range versions {
+			sample.addPackage(version.Package, version.CurrentState, "", version.ExtraInfo)
+		}
+	}
+	if subscription, err := client.GetSubscription(ctx); err != nil {
+		sample.addError("subscription", err)
+	} else if subscription != nil {
+		sample.subscription = nodeSubscription(subscription.Status, subscription.Level, subscription.ProductName, subscription.NextDueDate)
+	}
+	if certificates, err := client.GetCertificates(ctx); err != nil {
+		sample.addError("certificates", err)
+	} else {
+		sample.certificates = make([]models.NodeCertificate, 0, len(certificates))
+		for _, cert := range certificates {
+			sample.certificates = append(sample.certificates, nodeCertificate(cert.Filename, cert.Subject, cert.Issuer, cert.Fingerprint, cert.SAN, cert.NotBefore, cert.NotAfter))
+		}
+	}
+
+	return sample
+}
+
+func collectPMGMaintenance(ctx context.Context, client *pmg.Client, node string) maintenanceSample {
+	sample := maintenanceSample{node: node}
+	if updates, err := client.GetAptUpdates(ctx, node); err != nil {
+		sample.addError("updates", err)
+	} else {
+		sample.updates = make([]models.PendingUpdate, 0, len(updates))
+		for _, update := range updates {
+			sample.updates = append(sample.updates, pendingUpdate(update.Package, update.Version, update.OldVersion, update.Origin, update.Section, update.Title))
+		}
+	}
+	if versions, err := client.GetAptVersions(ctx, node); err != nil {
+		sample.addError("kernel", err)
+	} else {
+		for _, version := range versions {
+			sample.addPackage(version.Package, version.CurrentState, version.RunningKernel, version.ExtraInfo)
+		}
+	}
+	if subscription, err := client.GetSubscription(ctx, node); err != nil {
+		sample.addError("subscription", err)
+	} else if subscription != nil {
+		sample.subscription = nodeSubscription(subscription.Status, subscription.Level, subscription.ProductName, subscription.NextDueDate)
+	}
+	if certificates, err := client.GetCertificates(ctx, node); err != nil {
+		sample.addError("certificates", err)
+	} else {
+		sample.certificates = make([]models.NodeCertificate, 0, len(certificates))
+		for _, cert := range certificates {
+			sample.certificates = append(sample.certificates, nodeCertificate(cert.Filename, cert.Subject, cert.Issuer, cert.Fingerprint, cert.SAN, cert.NotBefore.Int64(), cert.NotAfter.Int64()))
+		}
+	}
+
+	return sample
+}
+
+func (s *maintenanceSample) addError(section string, err error) {
+	log.Debug().Err(err).Str("node", s.node).Str("section", section).Msg("Node maintenance status unavailable")
+	s.errors = append(s.errors, section)
+}
+
+// addPackage records installed kernel images and the running kernel from an apt versions entry.
+// PVE and PMG report the running kernel in RunningKernel, PBS in ExtraInfo ("running kernel: ...").
+func (s *maintenanceSample) addPackage(name, state, runningKernel, extraInfo string) {
+	if s.kernels == nil {
+		s.kernels = []string{}
+	}
+	if runningKernel != "" {
+		s.runningKernel = runningKernel
+	} else if release, ok := strings.CutPrefix(strings.TrimSpace(extraInfo), "running kernel: "); ok {
+		s.runningKernel = strings.TrimSpace(release)
+	}
+	if state != "" && !strings.EqualFold(state, "Installed") {
+		return
+	}
+	if match := kernelPackagePattern.FindStringSubmatch(name); match != nil {
+		s.kernels = append(s.kernels, match[1])
+	}
+}
+
+// pendingUpdate converts an apt update entry. Security fixes are recognised by their origin,
+// section or title mentioning "security" (e.g. Debian-Security), which is best effort.
+func pendingUpdate(pkg, version, oldVersion, origin, section, title string) models.PendingUpdate {
+	security := false
+	for _, field := range []string{origin, section, title} {
+		if strings.Contains(strings.ToLower(field), "security") {
+			security = true
+			break
+		}
+	}
+	return models.PendingUpdate{
+		Package:    pkg,
+		Version:    version,
+		OldVersion: oldVersion,
+		Origin:     origin,
+		Security:   security,
+	}
+}
+
+func nodeSubscription(status, level, productName, nextDueDate string) *models.NodeSubscription {
+	return &models.NodeSubscription{
+		Status:      strings.ToLower(strings.TrimSpace(status)),
+		Level:       level,
+		ProductName: productName,
+		NextDueDate: nextDueDate,
+	}
+}
+
+func nodeCertificate(filename, subject, issuer, fingerprint string, sans []string, notBefore, notAfter int64) models.NodeCertificate {
+	cert := models.NodeCertificate{
+		Filename:    filename,
+		Subject:     subject,
+		Issuer:      issuer,
+		Fingerprint: fingerprint,
+		SANs:        append([]string(nil), sans...),
+	}
+	if notBefore > 0 {
+		cert.NotBefore = time.Unix(notBefore, 0)
+	}
+	if notAfter > 0 {
+		cert.NotAfter = time.Unix(notAfter, 0)
+	}
+	return cert
+}
+
+// applyNodeMaintenance stores the samples for an instance and evaluates maintenance alerts
+func (m *Monitor) applyNodeMaintenance(platform, instanceName string, samples []maintenanceSample, now time.Time) {
+	previous := make(map[string]models.NodeMaintenance)
+	for _, entry := range m.state.GetSnapshot().NodeMaintenance {
+		if entry.Platform == platform && entry.Instance == instanceName {
+			previous[entry.Node] = entry
+		}
+	}
+
+	entries := make([]models.NodeMaintenance, 0, len(samples))
+	for _, sample := range samples {
+		prev, hasPrev := previous[sample.node]
+		entries = append(entries, buildNodeMaintenance(platform, instanceName, sample, prev, hasPrev, now))
+	}
+
+	m.state.UpdateNodeMaintenanceForInstance(platform, instanceName, entries)
+
+	if m.alertManager != nil {
+		m.alertManager.CheckNodeMaintenance(platform, instanceName, entries)
+	}
+}
+
+// buildNodeMaintenance converts a sample into the shared model. Sections that failed to load
+// keep their previous values so a transient error doesn't clear alerts.
+func buildNodeMaintenance(platform, instanceName string, sample maintenanceSample, prev models.NodeMaintenance, hasPrev bool, now time.Time) models.NodeMaintenance {
+	entry := models.NodeMaintenance{
+		ID:          platform + "-" + instanceName + "-" + sample.node,
+		Platform:    platform,
+		Instance:    instanceName,
+		Node:        sample.node,
+		Errors:      sample.errors,
+		LastChecked: now,
+	}
+
+	if sample.updates != nil {
+		entry.Updates = sample.updates
+		entry.PendingUpdates = len(sample.updates)
+		for _, update := range sample.updates {
+			if update.Security {
+				entry.SecurityUpdates++
+			}
+		}
+	} else if hasPrev {
+		entry.Updates = prev.Updates
+		entry.PendingUpdates = prev.PendingUpdates
+		entry.SecurityUpdates = prev.SecurityUpdates
+	}
+
+	if sample.kernels != nil {
+		entry.RunningKernel = sample.runningKernel
+		for _, kernel := range sample.kernels {
+			if entry.LatestKernel == "" || compareKernelReleases(kernel, entry.LatestKernel) > 0 {
+				entry.LatestKernel = kernel
+			}
+		}
+		entry.KernelOutdated = entry.RunningKernel != "" && entry.LatestKernel != "" &&
+			compareKernelReleases(entry.RunningKernel, entry.LatestKernel) < 0
+	} else if hasPrev {
+		entry.RunningKernel = prev.RunningKernel
+		entry.LatestKernel = prev.LatestKernel
+		entry.KernelOutdated = prev.KernelOutdated
+	}
+
+	if sample.subscription != nil {
+		entry.Subscription = sample.subscription
+	} else if hasPrev {
+		entry.Subscription = prev.Subscription
+	}
+
+	certificates := sample.certificates
+	if certificates == nil && hasPrev {
+		certificates = prev.Certificates
+	}
+	for _, cert := range certificates {
+		if !cert.NotAfter.IsZero() {
+			cert.DaysRemaining = int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+		}
+		entry.Certificates = append(entry.Certificates, cert)
+	}
+
+	return entry
+}
+
+// compareKernelReleases compares kernel releases such as 6.8.12-4-pve numerically,
+// returning -1, 0 or 1.
+func compareKernelReleases(a, b string) int {
+	left, right := kernelReleaseParts(a), kernelReleaseParts(b)
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r int
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		switch {
+		case l < r:
+			return -1
+		case l > r:
+			return 1
+		}
+	}
+	return 0
+}
+
+func kernelReleaseParts(release string) []int {
+	fields := strings.FieldsFunc(release, func(r rune) bool { return r == '.' || r == '-' })
+	parts := make([]int, 0, len(fields))
+	for _, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, value)
+	}
+	return parts
+}
+
+// GetPatchStatus returns the fleet-wide package update, reboot, subscription and certificate view,
+// optionally limited to one platform (pve, pbs, pmg) and instance
+func (m *Monitor) GetPatchStatus(platform, instanceName string) models.PatchStatusSummary {
+	expiryDays := 30
+	if m.alertManager != nil {
+		expiryDays = m.alertManager.GetConfig().MaintenanceDefaults.CertificateWarningDays
+	}
+
+	var entries []models.NodeMaintenance
+	for _, entry := range m.state.GetSnapshot().NodeMaintenance {
+		if (platform == "" || entry.Platform == platform) && (instanceName == "" || entry.Instance == instanceName) {
+			entries = append(entries, entry)
+		}
+	}
+	return models.SummarizePatchStatus(entries, expiryDays)
+}
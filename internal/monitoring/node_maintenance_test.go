@@ -0,0 +1,83 @@
+package monitoring
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func TestCompareKernelReleases(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"6.8.12-4-pve", "6.8.12-4-pve", 0},
+		{"6.8.8-2-pve", "6.8.12-4-pve", -1},
+		{"6.8.12-10-pve", "6.8.12-9-pve", 1},
+		{"5.15.108-1-pve", "6.2.16-3-pve", -1},
+	}
+	for _, tc := range cases {
+		if got := compareKernelReleases(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareKernelReleases(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestBuildNodeMaintenance(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	sample := maintenanceSample{node: "pve1"}
+	sample.updates = []models.PendingUpdate{
+		pendingUpdate("libssl3", "3.0.15-1~deb12u1", "3.0.14-1~deb12u2", "Debian-Security", "libs", ""),
+		pendingUpdate("pve-manager", "8.2.7", "8.2.4", "Proxmox", "admin", ""),
+	}
+	for _, pkg := range []struct{ name, state, running string }{
+		{"proxmox-ve", "Installed", "6.8.8-2-pve"},
+		{"proxmox-kernel-6.8", "Installed", ""},
+		{"proxmox-kernel-6.8.8-2-pve-signed", "Installed", ""},
+		{"proxmox-kernel-6.8.12-4-pve-signed", "Installed", ""},
+		{"proxmox-kernel-6.11.0-1-pve-signed", "ConfigFiles", ""},
+	} {
+		sample.addPackage(pkg.name, pkg.state, pkg.running, "")
+	}
+	sample.certificates = []models.NodeCertificate{
+		nodeCertificate("pveproxy-ssl.pem", "CN=pve1", "CN=R11", "AA:BB", nil, 0, now.Add(10*24*time.Hour+time.Hour).Unix()),
+	}
+
+	entry := buildNodeMaintenance("pve", "lab", sample, models.NodeMaintenance{}, false, now)
+	if entry.ID != "pve-lab-pve1" || entry.PendingUpdates != 2 || entry.SecurityUpdates != 1 {
+		t.Fatalf("unexpected update counts: %+v", entry)
+	}
+	if entry.RunningKernel != "6.8.8-2-pve" || entry.LatestKernel != "6.8.12-4-pve" || !entry.KernelOutdated {
+		t.Fatalf("unexpected kernel state: running=%s latest=%s outdated=%t", entry.RunningKernel, entry.LatestKernel, entry.KernelOutdated)
+	}
+	if len(entry.Certificates) != 1 || entry.Certificates[0].DaysRemaining != 10 {
+		t.Fatalf("unexpected certificates: %+v", entry.Certificates)
+	}
+
+	// Failed sections keep the previous values
+	failed := maintenanceSample{node: "pve1"}
+	failed.addError("updates", errors.New("permission denied"))
+	failed.addError("kernel", errors.New("permission denied"))
+	failed.addError("certificates", errors.New("timeout"))
+	later := buildNodeMaintenance("pve", "lab", failed, entry, true, now.Add(24*time.Hour))
+	if later.PendingUpdates != 2 || !later.KernelOutdated || len(later.Errors) != 3 {
+		t.Fatalf("expected previous values to be preserved: %+v", later)
+	}
+	if later.Certificates[0].DaysRemaining != 9 {
+		t.Fatalf("expected days remaining to be recomputed, got %d", later.Certificates[0].DaysRemaining)
+	}
+}
+
+func TestAddPackagePBSRunningKernel(t *testing.T) {
+	sample := maintenanceSample{node: "pbs"}
+	sample.addPackage("proxmox-backup-server", "Installed", "", "running kernel: 6.8.12-4-pve")
+	sample.addPackage("proxmox-kernel-6.8.12-4-pve-signed", "Installed", "", "")
+
+	entry := buildNodeMaintenance("pbs", "backup", sample, models.NodeMaintenance{}, false, time.Now())
+	if entry.RunningKernel != "6.8.12-4-pve" || entry.KernelOutdated {
+		t.Fatalf("unexpected kernel state: %+v", entry)
+	}
+}
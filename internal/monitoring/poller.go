@@ -67,20 +67,20 @@ func (p *PollerPool) Start(ctx context.Context) {
 // worker processes polling tasks
 func (p *PollerPool) worker(ctx context.Context, id int) {
 	if logging.IsLevelEnabled(zerolog.DebugLevel) {
-		log.Debug().Int("worker", id).Msg("Poller worker started")
+		log.Debug().Ctx(ctx).Int("worker", id).Msg("Poller worker started")
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			if logging.IsLevelEnabled(zerolog.DebugLevel) {
-				log.Debug().Int("worker", id).Msg("Poller worker stopped")
+				log.Debug().Ctx(ctx).Int("worker", id).Msg("Poller worker stopped")
 			}
 			return
 		case task, ok := <-p.tasksChan:
 			if !ok {
 				if logging.IsLevelEnabled(zerolog.DebugLevel) {
-					log.Debug().Int("worker", id).Msg("Task channel closed, worker stopping")
+					log.Debug().Ctx(ctx).Int("worker", id).Msg("Task channel closed, worker stopping")
 				}
 				return
 			}
@@ -100,7 +100,7 @@ func (p *PollerPool) worker(ctx context.Context, id int) {
 				default:
 					// Channel might be closed, just continue
 					if logging.IsLevelEnabled(zerolog.DebugLevel) {
-						log.Debug().Int("worker", id).Msg("Results channel appears closed, skipping result")
+						log.Debug().Ctx(ctx).Int("worker", id).Msg("Results channel appears closed, skipping result")
 					}
 				}
 			}
@@ -161,13 +161,13 @@ func (p *PollerPool) collectResults(ctx context.Context) {
 
 			duration := result.EndTime.Sub(result.StartTime)
 			if result.Success {
-				log.Debug().
+				log.Debug().Ctx(ctx).
 					Str("instance", result.InstanceName).
 					Str("type", result.InstanceType).
 					Dur("duration", duration).
 					Msg("Polling completed successfully")
 			} else {
-				log.Error().
+				log.Error().Ctx(ctx).
 					Err(result.Error).
 					Str("instance", result.InstanceName).
 					Str("type", result.InstanceType).
@@ -241,7 +241,7 @@ func (m *Monitor) pollWithChannels(ctx context.Context) {
 			PVEClient:    client,
 		}
 		if err := pool.SubmitTask(pollCtx, task); err != nil {
-			log.Error().Err(err).Str("instance", name).Msg("Failed to submit PVE polling task")
+			log.Error().Ctx(ctx).Err(err).Str("instance", name).Msg("Failed to submit PVE polling task")
 		} else {
 			taskCount++
 		}
@@ -255,7 +255,7 @@ func (m *Monitor) pollWithChannels(ctx context.Context) {
 			PBSClient:    client,
 		}
 		if err := pool.SubmitTask(pollCtx, task); err != nil {
-			log.Error().Err(err).Str("instance", name).Msg("Failed to submit PBS polling task")
+			log.Error().Ctx(ctx).Err(err).Str("instance", name).Msg("Failed to submit PBS polling task")
 		} else {
 			taskCount++
 		}
@@ -269,7 +269,7 @@ func (m *Monitor) pollWithChannels(ctx context.Context) {
 			PMGClient:    client,
 		}
 		if err := pool.SubmitTask(pollCtx, task); err != nil {
-			log.Error().Err(err).Str("instance", name).Msg("Failed to submit PMG polling task")
+			log.Error().Ctx(ctx).Err(err).Str("instance", name).Msg("Failed to submit PMG polling task")
 		} else {
 			taskCount++
 		}
@@ -281,5 +281,5 @@ func (m *Monitor) pollWithChannels(ctx context.Context) {
 	// Clean up
 	pool.Close()
 
-	log.Debug().Int("tasks", taskCount).Msg("Channel-based polling cycle completed")
+	log.Debug().Ctx(ctx).Int("tasks", taskCount).Msg("Channel-based polling cycle completed")
 }
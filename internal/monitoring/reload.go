@@ -20,6 +20,7 @@ type ReloadableMonitor struct {
 	cancel     context.CancelFunc
 	parentCtx  context.Context
 	reloadChan chan chan error
+	standby    bool // HA standby: the monitor is created but never started
 }
 
 // NewReloadableMonitor creates a new reloadable monitor
@@ -47,7 +48,9 @@ func (rm *ReloadableMonitor) Start(ctx context.Context) {
 	rm.mu.Unlock()
 
 	// Start the monitor
-	go rm.monitor.Start(rm.ctx, rm.wsHub)
+	if !rm.standby {
+		go rm.monitor.Start(rm.ctx, rm.wsHub)
+	}
 
 	// Watch for reload signals
 	go rm.watchReload(ctx)
@@ -67,12 +70,12 @@ func (rm *ReloadableMonitor) watchReload(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case done := <-rm.reloadChan:
-			log.Info().Msg("Reloading monitor configuration")
+			log.Info().Ctx(ctx).Msg("Reloading monitor configuration")
 			if err := rm.doReload(); err != nil {
-				log.Error().Err(err).Msg("Failed to reload monitor")
+				log.Error().Ctx(ctx).Err(err).Msg("Failed to reload monitor")
 				done <- err
 			} else {
-				log.Info().Msg("Monitor reloaded successfully")
+				log.Info().Ctx(ctx).Msg("Monitor reloaded successfully")
 				done <- nil
 			}
 		}
@@ -108,21 +111,58 @@ func (rm *ReloadableMonitor) doReload() error {
 	if err != nil {
 		// Restart old monitor if new one fails
 		rm.ctx, rm.cancel = context.WithCancel(rm.parentCtx)
-		go rm.monitor.Start(rm.ctx, rm.wsHub)
+		if !rm.standby {
+			go rm.monitor.Start(rm.ctx, rm.wsHub)
+		}
 		return err
 	}
 
 	// Replace monitor
 	rm.monitor = newMonitor
 	rm.config = cfg
+	if rm.standby {
+		newMonitor.alertManager.SetReadOnly(true)
+	}
 
 	// Start new monitor
 	rm.ctx, rm.cancel = context.WithCancel(rm.parentCtx)
-	go rm.monitor.Start(rm.ctx, rm.wsHub)
+	if !rm.standby {
+		go rm.monitor.Start(rm.ctx, rm.wsHub)
+	}
 
 	return nil
 }
 
+// SetStandby switches between monitoring as the HA leader and standing by. A standby's monitor
+// is never started and its alert state is read-only, so the data replicated from the leader is
+// not overwritten. Switching reloads the configuration, picking up what was replicated, and
+// stops the previous monitor.
+func (rm *ReloadableMonitor) SetStandby(standby bool) error {
+	rm.mu.Lock()
+	if rm.standby == standby {
+		rm.mu.Unlock()
+		return nil
+	}
+	rm.standby = standby
+	previous := rm.monitor
+	started := rm.parentCtx != nil
+	if !started && standby {
+		previous.alertManager.SetReadOnly(true)
+	}
+	rm.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+	if err := rm.Reload(); err != nil {
+		return err
+	}
+	if rm.GetMonitor() != previous {
+		previous.Stop()
+	}
+	return nil
+}
+
 // Note: Polling interval change detection removed - now hardcoded to 10s
 
 // GetMonitor returns the current monitor instance
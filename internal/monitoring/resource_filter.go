@@ -0,0 +1,85 @@
+package monitoring
+
+import (
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/rs/zerolog/log"
+)
+
+// pveResourceFilter returns the resource filter configured for a PVE instance
+func (m *Monitor) pveResourceFilter(instanceName string) config.PVEResourceFilter {
+	if m.config == nil {
+		return config.PVEResourceFilter{}
+	}
+	for _, inst := range m.config.PVEInstances {
+		if inst.Name == instanceName {
+			return inst.Filters
+		}
+	}
+	return config.PVEResourceFilter{}
+}
+
+// pveFallbackResourceFilter returns the resource filter for the per-node guest fallback. The node
+// endpoints do not report pool membership, so pool filters cannot be applied there.
+func (m *Monitor) pveFallbackResourceFilter(instanceName string) config.PVEResourceFilter {
+	filter := m.pveResourceFilter(instanceName)
+	if filter.HasPoolFilters() {
+		log.Debug().
+			Str("instance", instanceName).
+			Msg("Pool filters are skipped while cluster/resources is unavailable")
+	}
+	return filter
+}
+
+// pollIntervalOverride returns the fixed poll interval configured for an instance, or 0 when the
+// scheduler should pick one
+func (m *Monitor) pollIntervalOverride(instanceType InstanceType, instanceName string) time.Duration {
+	if m.config == nil || instanceType != InstanceTypePVE {
+		return 0
+	}
+	for _, inst := range m.config.PVEInstances {
+		if inst.Name == instanceName && inst.PollIntervalSeconds > 0 {
+			return time.Duration(inst.PollIntervalSeconds) * time.Second
+		}
+	}
+	return 0
+}
+
+// filterPVENodes drops nodes excluded by filter, so nothing on them is polled
+func filterPVENodes(filter config.PVEResourceFilter, nodes []proxmox.Node) []proxmox.Node {
+	if filter.IsEmpty() {
+		return nodes
+	}
+	kept := make([]proxmox.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if filter.AllowsNode(node.Node) {
+			kept = append(kept, node)
+		}
+	}
+	return kept
+}
+
+// filterPVEStorage drops storage excluded by filter
+func filterPVEStorage(filter config.PVEResourceFilter, storages []proxmox.Storage) []proxmox.Storage {
+	if filter.IsEmpty() {
+		return storages
+	}
+	kept := make([]proxmox.Storage, 0, len(storages))
+	for _, storage := range storages {
+		if filter.AllowsStorage(storage.Storage) {
+			kept = append(kept, storage)
+		}
+	}
+	return kept
+}
+
+// splitGuestTags splits Proxmox's semicolon-separated tag list
+func splitGuestTags(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ";")
+}
@@ -0,0 +1,499 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// rightsizingSampleInterval is how often guest usage is sampled; samples are averaged per hour
+	rightsizingSampleInterval = 5 * time.Minute
+	// rightsizingRetention is the longest window recommendations can cover
+	rightsizingRetention = 30 * 24 * time.Hour
+	// rightsizingMinHours is the running history a guest needs before it is sized
+	rightsizingMinHours = 7 * 24
+	// rightsizingIdleHours is how long a guest must be stopped or near-zero to count as idle
+	rightsizingIdleHours   = 14 * 24
+	rightsizingHistoryFile = "rightsizing_history.json"
+
+	// DefaultRightsizingDays is the default recommendation window
+	DefaultRightsizingDays = 30
+)
+
+const (
+	rightsizingCPUTarget     = 0.7  // p95 share of the recommended vCPUs
+	rightsizingMemoryTarget  = 0.8  // p95 share of the recommended memory
+	rightsizingPressure      = 0.9  // p95 share of the allocation that counts as undersized
+	rightsizingBalloonShare  = 0.25 // Share of running hours spent ballooned that counts as memory pressure
+	rightsizingIdleCPU       = 0.02 // p95 share of the vCPUs under which a guest is idle
+	rightsizingIdleNetwork   = 10 * 1024
+	rightsizingMemoryStep    = 256 << 20
+	rightsizingMinMemory     = 512 << 20
+	rightsizingBalloonMargin = 0.98 // Reported memory below this share of the allocation means ballooned
+)
+
+// guestUsageHour is the average usage of a guest over one hour. Usage fields only cover the time
+// the guest was running and are rounded to keep the persisted history compact.
+type guestUsageHour struct {
+	Hour      int64   `json:"h"`           // Unix hour
+	Running   float64 `json:"r"`           // Share of samples the guest was running
+	Cores     float64 `json:"c,omitempty"` // vCPUs in use
+	Memory    float64 `json:"m,omitempty"` // Bytes in use
+	Network   float64 `json:"n,omitempty"` // Bytes/s in and out
+	Ballooned float64 `json:"b,omitempty"` // Share of running samples with the balloon below the allocation
+	Allocated int64   `json:"a,omitempty"` // Largest memory total reported
+}
+
+// guestUsageHistory is the hourly usage of one guest over the retention window
+type guestUsageHistory struct {
+	GuestID   string           `json:"id"`
+	VMID      int              `json:"vmid"`
+	Name      string           `json:"name"`
+	Type      string           `json:"type"`
+	Node      string           `json:"node"`
+	Instance  string           `json:"instance"`
+	CPUs      int              `json:"cpus"`
+	DiskBytes int64            `json:"diskBytes"`
+	Hours     []guestUsageHour `json:"hours"`
+
+	pending        guestUsageHour
+	pendingSamples int
+	pendingRunning int
+}
+
+// guestUsageSample is one observation of a guest
+type guestUsageSample struct {
+	id, name, guestType, node, instance, status string
+	vmid, cpus                                  int
+	cpu                                         float64
+	memory                                      models.Memory
+	diskBytes, netIn, netOut                    int64
+}
+
+// RightsizingFilter selects recommendations. Days is the usage window; empty fields match everything.
+type RightsizingFilter struct {
+	Days     int
+	Kind     string
+	Instance string
+	Node     string
+}
+
+func (m *Monitor) runRightsizingSampler(ctx context.Context) {
+	m.loadRightsizingHistory()
+
+	ticker := time.NewTicker(rightsizingSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if m.sampleGuestUsage(guestUsageSamples(m.state.GetSnapshot()), now) {
+				if err := m.saveRightsizingHistory(); err != nil {
+					log.Warn().Ctx(ctx).Err(err).Msg("Failed to save rightsizing history")
+				}
+			}
+		}
+	}
+}
+
+func guestUsageSamples(snapshot models.StateSnapshot) []guestUsageSample {
+	samples := make([]guestUsageSample, 0, len(snapshot.VMs)+len(snapshot.Containers))
+	for _, vm := range snapshot.VMs {
+		if vm.Template {
+			continue
+		}
+		samples = append(samples, guestUsageSample{
+			id: vm.ID, name: vm.Name, guestType: "qemu", node: vm.Node, instance: vm.Instance, status: vm.Status,
+			vmid: vm.VMID, cpus: vm.CPUs, cpu: vm.CPU, memory: vm.Memory,
+			diskBytes: vm.Disk.Total, netIn: vm.NetworkIn, netOut: vm.NetworkOut,
+		})
+	}
+	for _, ct := range snapshot.Containers {
+		if ct.Template {
+			continue
+		}
+		samples = append(samples, guestUsageSample{
+			id: ct.ID, name: ct.Name, guestType: "lxc", node: ct.Node, instance: ct.Instance, status: ct.Status,
+			vmid: ct.VMID, cpus: ct.CPUs, cpu: ct.CPU, memory: ct.Memory,
+			diskBytes: ct.Disk.Total, netIn: ct.NetworkIn, netOut: ct.NetworkOut,
+		})
+	}
+	return samples
+}
+
+// sampleGuestUsage adds the samples to the current hour of each guest. It reports whether an hour
+// was completed, i.e. whether the history changed.
+func (m *Monitor) sampleGuestUsage(samples []guestUsageSample, now time.Time) bool {
+	m.rightsizingMu.Lock()
+	defer m.rightsizingMu.Unlock()
+
+	if m.guestUsage == nil {
+		m.guestUsage = make(map[string]*guestUsageHistory)
+	}
+
+	hour := now.Unix() / 3600
+	flushed := false
+	for _, sample := range samples {
+		history := m.guestUsage[sample.id]
+		if history == nil {
+			history = &guestUsageHistory{GuestID: sample.id}
+			m.guestUsage[sample.id] = history
+		}
+		history.VMID, history.Name, history.Type = sample.vmid, sample.name, sample.guestType
+		history.Node, history.Instance, history.CPUs = sample.node, sample.instance, sample.cpus
+		if sample.diskBytes > 0 {
+			history.DiskBytes = sample.diskBytes
+		}
+
+		if history.pending.Hour != hour {
+			flushed = history.flush() || flushed
+			history.pending = guestUsageHour{Hour: hour}
+		}
+
+		// Ballooning lowers the reported total, so the allocation is the largest total seen
+		allocated := history.allocated()
+		history.pending.Allocated = max(history.pending.Allocated, sample.memory.Total)
+		history.pendingSamples++
+		if sample.status != "running" {
+			continue
+		}
+		history.pendingRunning++
+		history.pending.Cores += sample.cpu * float64(sample.cpus)
+		history.pending.Memory += float64(sample.memory.Used)
+		history.pending.Network += float64(max(sample.netIn, 0) + max(sample.netOut, 0))
+		if sample.memory.Balloon > 0 && float64(sample.memory.Total) < float64(allocated)*rightsizingBalloonMargin {
+			history.pending.Ballooned++
+		}
+	}
+
+	cutoff := (now.Add(-rightsizingRetention).Unix()) / 3600
+	for id, history := range m.guestUsage {
+		first := sort.Search(len(history.Hours), func(i int) bool { return history.Hours[i].Hour >= cutoff })
+		history.Hours = history.Hours[first:]
+		if len(history.Hours) == 0 && history.pendingSamples == 0 {
+			delete(m.guestUsage, id)
+		}
+	}
+	return flushed
+}
+
+// flush stores the averages of the pending hour
+func (h *guestUsageHistory) flush() bool {
+	if h.pendingSamples == 0 {
+		return false
+	}
+	hour := guestUsageHour{
+		Hour:      h.pending.Hour,
+		Running:   roundTo(float64(h.pendingRunning)/float64(h.pendingSamples), 2),
+		Allocated: h.pending.Allocated,
+	}
+	if h.pendingRunning > 0 {
+		running := float64(h.pendingRunning)
+		hour.Cores = roundTo(h.pending.Cores/running, 3)
+		hour.Memory = math.Round(h.pending.Memory / running)
+		hour.Network = math.Round(h.pending.Network / running)
+		hour.Ballooned = roundTo(h.pending.Ballooned/running, 2)
+	}
+	h.Hours = append(h.Hours, hour)
+	h.pendingSamples, h.pendingRunning = 0, 0
+	return true
+}
+
+// allocated returns the guest's memory allocation: the largest total reported over the history
+func (h *guestUsageHistory) allocated() int64 {
+	allocated := h.pending.Allocated
+	for _, hour := range h.Hours {
+		allocated = max(allocated, hour.Allocated)
+	}
+	return allocated
+}
+
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
+
+// GetRightsizingReport returns sizing recommendations based on guest usage over the filter window
+func (m *Monitor) GetRightsizingReport(filter RightsizingFilter) models.RightsizingReport {
+	days := filter.Days
+	if days <= 0 || days > int(rightsizingRetention/(24*time.Hour)) {
+		days = DefaultRightsizingDays
+	}
+	now := time.Now()
+	since := now.Add(-time.Duration(days) * 24 * time.Hour)
+
+	m.rightsizingMu.Lock()
+	recommendations := []models.GuestRecommendation{}
+	for _, history := range m.guestUsage {
+		if (filter.Instance != "" && history.Instance != filter.Instance) || (filter.Node != "" && history.Node != filter.Node) {
+			continue
+		}
+		for _, recommendation := range analyzeGuestUsage(history, since) {
+			if filter.Kind == "" || recommendation.Kind == filter.Kind {
+				recommendations = append(recommendations, recommendation)
+			}
+		}
+	}
+	m.rightsizingMu.Unlock()
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		left, right := recommendations[i], recommendations[j]
+		if left.Instance != right.Instance {
+			return left.Instance < right.Instance
+		}
+		if left.Node != right.Node {
+			return left.Node < right.Node
+		}
+		return left.ID < right.ID
+	})
+
+	return models.RightsizingReport{
+		WindowDays:      days,
+		GeneratedAt:     now,
+		Recommendations: recommendations,
+		Nodes:           SummarizeRightsizing(recommendations),
+	}
+}
+
+// analyzeGuestUsage sizes a guest from its p95 usage over the hours since the cutoff
+func analyzeGuestUsage(history *guestUsageHistory, since time.Time) []models.GuestRecommendation {
+	cutoff := since.Unix() / 3600
+	var hours, running []guestUsageHour
+	for _, hour := range history.Hours {
+		if hour.Hour < cutoff {
+			continue
+		}
+		hours = append(hours, hour)
+		if hour.Running >= 0.5 {
+			running = append(running, hour)
+		}
+	}
+	if len(hours) < rightsizingMinHours {
+		return nil
+	}
+
+	var runningShare float64
+	var allocated int64
+	for _, hour := range hours {
+		runningShare += hour.Running
+		allocated = max(allocated, hour.Allocated)
+	}
+	runningShare /= float64(len(hours))
+
+	base := models.GuestRecommendation{
+		GuestID:        history.GuestID,
+		VMID:           history.VMID,
+		Name:           history.Name,
+		Type:           history.Type,
+		Node:           history.Node,
+		Instance:       history.Instance,
+		CPUs:           history.CPUs,
+		MemoryBytes:    allocated,
+		RunningPercent: roundTo(runningShare*100, 1),
+		Samples:        len(hours),
+		SampledSince:   time.Unix(hours[0].Hour*3600, 0),
+	}
+	recommend := func(kind, resource, reason string) models.GuestRecommendation {
+		recommendation := base
+		recommendation.ID = fmt.Sprintf("%s:%s:%s", history.GuestID, kind, resource)
+		recommendation.Kind, recommendation.Resource, recommendation.Reason = kind, resource, reason
+		recommendation.RecommendedCPUs, recommendation.RecommendedMemoryBytes = base.CPUs, base.MemoryBytes
+		return recommendation
+	}
+	days := len(hours) / 24
+
+	if runningShare == 0 {
+		if len(hours) < rightsizingIdleHours {
+			return nil
+		}
+		idle := recommend(models.RightsizingIdle, models.RightsizingGuest, fmt.Sprintf("Stopped for the last %d days", days))
+		idle.RecommendedCPUs, idle.RecommendedMemoryBytes = 0, 0
+		idle.ReclaimableDiskBytes = history.DiskBytes
+		return []models.GuestRecommendation{idle}
+	}
+	if len(running) < rightsizingMinHours {
+		return nil
+	}
+
+	cores := make([]float64, len(running))
+	memory := make([]float64, len(running))
+	network := make([]float64, len(running))
+	var ballooned float64
+	for i, hour := range running {
+		cores[i], memory[i], network[i] = hour.Cores, hour.Memory, hour.Network
+		ballooned += hour.Ballooned
+	}
+	ballooned /= float64(len(running))
+	base.CPUP95 = roundTo(percentile(cores, 95), 2)
+	base.MemoryP95Bytes = int64(percentile(memory, 95))
+	networkP95 := percentile(network, 95)
+
+	if len(hours) >= rightsizingIdleHours && base.CPUs > 0 &&
+		base.CPUP95 < rightsizingIdleCPU*float64(base.CPUs) && networkP95 < rightsizingIdleNetwork {
+		idle := recommend(models.RightsizingIdle, models.RightsizingGuest,
+			fmt.Sprintf("Near-zero CPU (p95 %.2f vCPUs) and network activity for %d days", base.CPUP95, days))
+		idle.RecommendedCPUs, idle.RecommendedMemoryBytes = 0, 0
+		idle.ReclaimableCPUs, idle.ReclaimableMemoryBytes = base.CPUs, base.MemoryBytes
+		return []models.GuestRecommendation{idle}
+	}
+
+	var recommendations []models.GuestRecommendation
+	if base.CPUs > 0 {
+		needed := int(math.Ceil(base.CPUP95 / rightsizingCPUTarget))
+		switch {
+		case base.CPUP95 >= rightsizingPressure*float64(base.CPUs):
+			undersized := recommend(models.RightsizingUndersized, models.RightsizingCPU,
+				fmt.Sprintf("p95 CPU uses %.1f of %d vCPUs", base.CPUP95, base.CPUs))
+			undersized.RecommendedCPUs = max(base.CPUs+1, needed)
+			recommendations = append(recommendations, undersized)
+		case max(needed, 1) < base.CPUs:
+			oversized := recommend(models.RightsizingOversized, models.RightsizingCPU,
+				fmt.Sprintf("p95 CPU uses %.1f of %d vCPUs", base.CPUP95, base.CPUs))
+			oversized.RecommendedCPUs = max(needed, 1)
+			oversized.ReclaimableCPUs = base.CPUs - oversized.RecommendedCPUs
+			recommendations = append(recommendations, oversized)
+		}
+	}
+
+	if allocated > 0 {
+		used := float64(base.MemoryP95Bytes)
+		switch {
+		case used >= rightsizingPressure*float64(allocated) || ballooned >= rightsizingBalloonShare:
+			reason := fmt.Sprintf("p95 memory uses %.0f%% of the allocation", used/float64(allocated)*100)
+			if ballooned >= rightsizingBalloonShare {
+				reason = fmt.Sprintf("Ballooned below its allocation %.0f%% of the time", ballooned*100)
+			}
+			undersized := recommend(models.RightsizingUndersized, models.RightsizingMemory, reason)
+			undersized.RecommendedMemoryBytes = roundUpMemory(math.Max(used/rightsizingMemoryTarget, float64(allocated)*1.25))
+			recommendations = append(recommendations, undersized)
+		default:
+			recommended := max(roundUpMemory(used/rightsizingMemoryTarget), rightsizingMinMemory)
+			if allocated-recommended >= max(rightsizingMinMemory, allocated/4) {
+				oversized := recommend(models.RightsizingOversized, models.RightsizingMemory,
+					fmt.Sprintf("p95 memory uses %.0f%% of the allocation", used/float64(allocated)*100))
+				oversized.RecommendedMemoryBytes = recommended
+				oversized.ReclaimableMemoryBytes = allocated - recommended
+				recommendations = append(recommendations, oversized)
+			}
+		}
+	}
+	return recommendations
+}
+
+func roundUpMemory(bytes float64) int64 {
+	return int64(math.Ceil(bytes/rightsizingMemoryStep)) * rightsizingMemoryStep
+}
+
+// percentile returns the nearest-rank percentile of the values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// SummarizeRightsizing totals recommendations per node
+func SummarizeRightsizing(recommendations []models.GuestRecommendation) []models.NodeRightsizing {
+	byNode := make(map[string]*models.NodeRightsizing)
+	for _, recommendation := range recommendations {
+		key := recommendation.Instance + "/" + recommendation.Node
+		summary := byNode[key]
+		if summary == nil {
+			summary = &models.NodeRightsizing{Node: recommendation.Node, Instance: recommendation.Instance}
+			byNode[key] = summary
+		}
+		switch recommendation.Kind {
+		case models.RightsizingOversized:
+			summary.Oversized++
+		case models.RightsizingUndersized:
+			summary.Undersized++
+		case models.RightsizingIdle:
+			summary.Idle++
+		}
+		summary.ReclaimableCPUs += recommendation.ReclaimableCPUs
+		summary.ReclaimableMemoryBytes += recommendation.ReclaimableMemoryBytes
+		summary.ReclaimableDiskBytes += recommendation.ReclaimableDiskBytes
+	}
+
+	nodes := make([]models.NodeRightsizing, 0, len(byNode))
+	for _, summary := range byNode {
+		nodes = append(nodes, *summary)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Instance != nodes[j].Instance {
+			return nodes[i].Instance < nodes[j].Instance
+		}
+		return nodes[i].Node < nodes[j].Node
+	})
+	return nodes
+}
+
+func (m *Monitor) rightsizingHistoryPath() string {
+	dataPath := "/etc/pulse"
+	if m.config != nil && m.config.DataPath != "" {
+		dataPath = m.config.DataPath
+	}
+	return filepath.Join(dataPath, rightsizingHistoryFile)
+}
+
+func (m *Monitor) loadRightsizingHistory() {
+	data, err := os.ReadFile(m.rightsizingHistoryPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to read rightsizing history")
+		}
+		return
+	}
+
+	var stored []*guestUsageHistory
+	if err := json.Unmarshal(data, &stored); err != nil {
+		log.Warn().Err(err).Msg("Discarding unreadable rightsizing history")
+		return
+	}
+
+	m.rightsizingMu.Lock()
+	defer m.rightsizingMu.Unlock()
+	m.guestUsage = make(map[string]*guestUsageHistory, len(stored))
+	for _, history := range stored {
+		if history != nil && history.GuestID != "" {
+			m.guestUsage[history.GuestID] = history
+		}
+	}
+}
+
+func (m *Monitor) saveRightsizingHistory() error {
+	m.rightsizingMu.Lock()
+	stored := make([]*guestUsageHistory, 0, len(m.guestUsage))
+	for _, history := range m.guestUsage {
+		stored = append(stored, history)
+	}
+	data, err := json.Marshal(stored)
+	m.rightsizingMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	historyPath := m.rightsizingHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		return err
+	}
+	tmp := historyPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, historyPath)
+}
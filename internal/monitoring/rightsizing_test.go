@@ -0,0 +1,106 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+const gib = int64(1 << 30)
+
+func TestRightsizingRecommendations(t *testing.T) {
+	m := &Monitor{config: &config.Config{DataPath: t.TempDir()}}
+	start := time.Now().Add(-15 * 24 * time.Hour)
+
+	guest := func(id string, cpus int, cpu float64, memory models.Memory) guestUsageSample {
+		return guestUsageSample{
+			id: id, name: id, guestType: "qemu", node: "pve1", instance: "pve", status: "running",
+			cpus: cpus, cpu: cpu, memory: memory, diskBytes: 32 * gib, netIn: 1 << 20,
+		}
+	}
+	for hour := 0; hour < 15*24; hour++ {
+		ballooned := models.Memory{Total: 8 * gib, Used: 3 * gib}
+		if hour > 0 {
+			ballooned = models.Memory{Total: 4 * gib, Used: 3 * gib, Balloon: 4 * gib}
+		}
+		stopped := guest("stopped", 2, 0, models.Memory{Total: 2 * gib})
+		stopped.status = "stopped"
+		quiet := guest("quiet", 2, 0.001, models.Memory{Total: 2 * gib, Used: gib})
+		quiet.netIn = 0
+
+		m.sampleGuestUsage([]guestUsageSample{
+			guest("big", 8, 0.05, models.Memory{Total: 16 * gib, Used: 2 * gib}),
+			guest("tight", 2, 0.5, models.Memory{Total: 4 * gib, Used: 4*gib - gib/10}),
+			guest("balloon", 1, 0.3, ballooned),
+			stopped,
+			quiet,
+		}, start.Add(time.Duration(hour)*time.Hour))
+	}
+
+	report := m.GetRightsizingReport(RightsizingFilter{Days: 30})
+	found := make(map[string]models.GuestRecommendation)
+	for _, recommendation := range report.Recommendations {
+		found[recommendation.ID] = recommendation
+	}
+	if len(found) != 6 {
+		t.Fatalf("expected 6 recommendations, got %d: %+v", len(found), report.Recommendations)
+	}
+
+	if cpu := found["big:oversized:cpu"]; cpu.RecommendedCPUs != 1 || cpu.ReclaimableCPUs != 7 {
+		t.Errorf("expected 8 vCPUs at p95 0.4 to shrink to 1, got %+v", cpu)
+	}
+	if memory := found["big:oversized:memory"]; memory.RecommendedMemoryBytes != 2*gib+gib/2 || memory.ReclaimableMemoryBytes != 13*gib+gib/2 {
+		t.Errorf("expected 16 GiB at p95 2 GiB to shrink to 2.5 GiB, got %+v", memory)
+	}
+	if memory := found["tight:undersized:memory"]; memory.RecommendedMemoryBytes != 5*gib {
+		t.Errorf("expected 4 GiB at 97%% to grow to 5 GiB, got %+v", memory)
+	}
+	if memory := found["balloon:undersized:memory"]; memory.MemoryBytes != 8*gib || memory.Reason == "" {
+		t.Errorf("expected ballooned guest to be undersized against its 8 GiB allocation, got %+v", memory)
+	}
+	if idle := found["stopped:idle:guest"]; idle.ReclaimableDiskBytes != 32*gib || idle.RunningPercent != 0 {
+		t.Errorf("expected stopped guest to free its disk, got %+v", idle)
+	}
+	if idle := found["quiet:idle:guest"]; idle.ReclaimableCPUs != 2 || idle.ReclaimableMemoryBytes != 2*gib {
+		t.Errorf("expected near-zero guest to free its allocation, got %+v", idle)
+	}
+
+	if len(report.Nodes) != 1 {
+		t.Fatalf("expected one node summary, got %+v", report.Nodes)
+	}
+	node := report.Nodes[0]
+	if node.Oversized != 2 || node.Undersized != 2 || node.Idle != 2 || node.ReclaimableCPUs != 9 ||
+		node.ReclaimableMemoryBytes != 15*gib+gib/2 || node.ReclaimableDiskBytes != 32*gib {
+		t.Errorf("unexpected node summary: %+v", node)
+	}
+
+	// A week of history is enough to size guests, but not to call them idle
+	recent := m.GetRightsizingReport(RightsizingFilter{Days: 7, Kind: models.RightsizingIdle})
+	if len(recent.Recommendations) != 0 {
+		t.Errorf("expected no idle guests within a 7-day window, got %+v", recent.Recommendations)
+	}
+
+	if err := m.saveRightsizingHistory(); err != nil {
+		t.Fatalf("save history: %v", err)
+	}
+	restored := &Monitor{config: m.config}
+	restored.loadRightsizingHistory()
+	if got := restored.GetRightsizingReport(RightsizingFilter{}); len(got.Recommendations) != 6 {
+		t.Errorf("expected history to survive a restart, got %d recommendations", len(got.Recommendations))
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(100 - i)
+	}
+	if got := percentile(values, 95); got != 95 {
+		t.Errorf("expected p95 of 1..100 to be 95, got %.0f", got)
+	}
+	if got := percentile([]float64{3}, 95); got != 3 {
+		t.Errorf("expected single value, got %.0f", got)
+	}
+}
@@ -60,6 +60,8 @@ type InstanceDescriptor struct {
 	LastInterval time.Duration
 	ErrorCount   int
 	Metadata     map[string]any
+	// IntervalOverride, when set, is used as the poll interval instead of the adaptive choice
+	IntervalOverride time.Duration
 }
 
 // ScheduledTask represents a single polling opportunity planned by the scheduler.
@@ -175,15 +177,18 @@ func (s *AdaptiveScheduler) BuildPlan(now time.Time, inventory []InstanceDescrip
 			InstanceType:   inst.Type,
 		}
 
-		nextInterval := s.interval.SelectInterval(req)
+		nextInterval := inst.IntervalOverride
 		if nextInterval <= 0 {
-			nextInterval = s.cfg.BaseInterval
-		}
-		if nextInterval < s.cfg.MinInterval {
-			nextInterval = s.cfg.MinInterval
-		}
-		if nextInterval > s.cfg.MaxInterval {
-			nextInterval = s.cfg.MaxInterval
+			nextInterval = s.interval.SelectInterval(req)
+			if nextInterval <= 0 {
+				nextInterval = s.cfg.BaseInterval
+			}
+			if nextInterval < s.cfg.MinInterval {
+				nextInterval = s.cfg.MinInterval
+			}
+			if nextInterval > s.cfg.MaxInterval {
+				nextInterval = s.cfg.MaxInterval
+			}
 		}
 
 		nextRun := now
@@ -248,7 +253,7 @@ func (s *AdaptiveScheduler) DispatchDue(ctx context.Context, now time.Time, task
 	}
 	for _, task := range due {
 		if err := s.enqueuer.Enqueue(ctx, task); err != nil {
-			log.Warn().
+			log.Warn().Ctx(ctx).
 				Err(err).
 				Str("instance", task.InstanceName).
 				Str("type", string(task.InstanceType)).
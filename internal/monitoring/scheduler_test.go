@@ -0,0 +1,67 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+func TestBuildPlanUsesIntervalOverride(t *testing.T) {
+	scheduler := NewAdaptiveScheduler(SchedulerConfig{
+		BaseInterval: 10 * time.Second,
+		MinInterval:  5 * time.Second,
+		MaxInterval:  30 * time.Second,
+	}, nil, nil, nil)
+
+	now := time.Now()
+	last := now.Add(-5 * time.Second)
+	tasks := scheduler.BuildPlan(now, []InstanceDescriptor{
+		{Name: "remote", Type: InstanceTypePVE, LastScheduled: last, IntervalOverride: time.Minute},
+		{Name: "local", Type: InstanceTypePVE, LastScheduled: last},
+	}, 0)
+
+	byName := make(map[string]ScheduledTask)
+	for _, task := range tasks {
+		byName[task.InstanceName] = task
+	}
+	remote := byName["remote"]
+	if remote.Interval != time.Minute || !remote.NextRun.Equal(last.Add(time.Minute)) {
+		t.Fatalf("remote task = %+v, want a 60s interval beyond the max", remote)
+	}
+	if local := byName["local"]; local.Interval > 30*time.Second {
+		t.Fatalf("local task interval %s exceeds the max interval", local.Interval)
+	}
+}
+
+func TestBuildScheduledTasksHonoursOverrideWithoutScheduler(t *testing.T) {
+	tracker := NewStalenessTracker(nil)
+	tracker.UpdateSuccess(InstanceTypePVE, "remote", nil)
+	tracker.UpdateSuccess(InstanceTypePVE, "local", nil)
+
+	m := &Monitor{
+		config: &config.Config{
+			AdaptivePollingBaseInterval: 10 * time.Second,
+			PVEInstances: []config.PVEInstance{
+				{Name: "remote", PollIntervalSeconds: 60},
+				{Name: "local"},
+			},
+		},
+		pveClients:       map[string]PVEClientInterface{"remote": nil, "local": nil},
+		stalenessTracker: tracker,
+	}
+
+	now := time.Now()
+	for _, task := range m.buildScheduledTasks(now) {
+		switch task.InstanceName {
+		case "remote":
+			if task.Interval != time.Minute || !task.NextRun.After(now.Add(50*time.Second)) {
+				t.Fatalf("remote task = %+v, want it held back for a minute after its last poll", task)
+			}
+		case "local":
+			if task.Interval != 10*time.Second || !task.NextRun.Equal(now) {
+				t.Fatalf("local task = %+v, want it due now at the base interval", task)
+			}
+		}
+	}
+}
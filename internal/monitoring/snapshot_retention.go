@@ -0,0 +1,469 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Snapshot retention run triggers
+const (
+	SnapshotRetentionTriggerSchedule = "schedule"
+	SnapshotRetentionTriggerManual   = "manual"
+	SnapshotRetentionTriggerPreview  = "preview"
+)
+
+const (
+	snapshotRetentionCheckInterval = time.Minute
+	// Give the first backup/snapshot poll time to populate state before enforcing
+	snapshotRetentionStartupDelay  = 10 * time.Minute
+	snapshotRetentionDeleteTimeout = 10 * time.Minute
+	snapshotRetentionHistoryFile   = "snapshot_retention_history.json"
+	snapshotRetentionHistoryLimit  = 50
+)
+
+// retentionGuest is the guest information retention policies match against
+type retentionGuest struct {
+	id        string
+	name      string
+	instance  string
+	node      string
+	vmid      int
+	guestType string
+	tags      []string
+	pool      string
+	locked    bool
+}
+
+type retentionVote struct {
+	covering int
+	deletes  int
+	policy   config.SnapshotRetentionPolicy
+	reason   string
+}
+
+// SetSnapshotRetentionConfig replaces the retention policies used by the scheduler
+func (m *Monitor) SetSnapshotRetentionConfig(cfg config.SnapshotRetentionConfig) {
+	m.retentionCfgMu.Lock()
+	defer m.retentionCfgMu.Unlock()
+	m.retentionConfig = config.NormalizeSnapshotRetentionConfig(cfg)
+}
+
+// GetSnapshotRetentionConfig returns the active retention policies
+func (m *Monitor) GetSnapshotRetentionConfig() config.SnapshotRetentionConfig {
+	m.retentionCfgMu.RLock()
+	defer m.retentionCfgMu.RUnlock()
+	return m.retentionConfig
+}
+
+func (m *Monitor) runSnapshotRetentionScheduler(ctx context.Context) {
+	if m.persistence != nil {
+		if cfg, err := m.persistence.LoadSnapshotRetentionConfig(); err == nil {
+			m.SetSnapshotRetentionConfig(*cfg)
+		} else {
+			log.Warn().Ctx(ctx).Err(err).Msg("Failed to load snapshot retention configuration")
+		}
+	}
+
+	notBefore := time.Now().Add(snapshotRetentionStartupDelay)
+	if history, err := m.GetSnapshotRetentionHistory(); err == nil {
+		for _, run := range history {
+			if run.Trigger == SnapshotRetentionTriggerSchedule {
+				m.retentionMu.Lock()
+				m.lastRetentionRun = run.StartedAt
+				m.retentionMu.Unlock()
+				break
+			}
+		}
+	}
+
+	ticker := time.NewTicker(snapshotRetentionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if now.Before(notBefore) {
+				continue
+			}
+			cfg := m.GetSnapshotRetentionConfig()
+			if !cfg.Enabled || len(cfg.Policies) == 0 {
+				continue
+			}
+
+			m.retentionMu.Lock()
+			due := now.Sub(m.lastRetentionRun) >= time.Duration(cfg.IntervalHours)*time.Hour
+			m.retentionMu.Unlock()
+			if due {
+				m.EnforceSnapshotRetention(ctx, SnapshotRetentionTriggerSchedule)
+			}
+		}
+	}
+}
+
+// PreviewSnapshotRetention evaluates cfg against the current snapshots without deleting
+// or recording anything.
+func (m *Monitor) PreviewSnapshotRetention(cfg config.SnapshotRetentionConfig) models.SnapshotRetentionRun {
+	cfg = config.NormalizeSnapshotRetentionConfig(cfg)
+	run := m.newSnapshotRetentionRun(cfg, SnapshotRetentionTriggerPreview, true)
+	run.FinishedAt = time.Now()
+	return run
+}
+
+// EnforceSnapshotRetention applies the active policies, deleting expired snapshots unless
+// the configuration is in dry-run mode. The run is recorded in state and history.
+func (m *Monitor) EnforceSnapshotRetention(ctx context.Context, trigger string) models.SnapshotRetentionRun {
+	m.retentionMu.Lock()
+	defer m.retentionMu.Unlock()
+
+	cfg := m.GetSnapshotRetentionConfig()
+	run := m.newSnapshotRetentionRun(cfg, trigger, cfg.DryRun)
+
+	if !run.DryRun {
+		for i := range run.Actions {
+			if ctx.Err() != nil {
+				break
+			}
+			m.deleteRetainedSnapshot(ctx, &run.Actions[i])
+			switch run.Actions[i].Outcome {
+			case models.SnapshotRetentionDeleted:
+				run.Deleted++
+			case models.SnapshotRetentionFailed:
+				run.Failed++
+			}
+		}
+	}
+	run.FinishedAt = time.Now()
+
+	if trigger == SnapshotRetentionTriggerSchedule {
+		m.lastRetentionRun = run.StartedAt
+	}
+	m.state.SetSnapshotRetentionRun(run)
+	if err := m.appendSnapshotRetentionHistory(run); err != nil {
+		log.Warn().Ctx(ctx).Err(err).Msg("Failed to record snapshot retention history")
+	}
+
+	log.Info().Ctx(ctx).
+		Str("trigger", trigger).
+		Bool("dryRun", run.DryRun).
+		Int("evaluated", run.Evaluated).
+		Int("planned", run.Planned).
+		Int("deleted", run.Deleted).
+		Int("failed", run.Failed).
+		Msg("Snapshot retention run completed")
+
+	return run
+}
+
+func (m *Monitor) newSnapshotRetentionRun(cfg config.SnapshotRetentionConfig, trigger string, dryRun bool) models.SnapshotRetentionRun {
+	state := m.GetState()
+
+	guests := make([]retentionGuest, 0, len(state.VMs)+len(state.Containers))
+	for _, vm := range state.VMs {
+		if vm.Template {
+			continue
+		}
+		guests = append(guests, retentionGuest{id: vm.ID, name: vm.Name, instance: vm.Instance, node: vm.Node, vmid: vm.VMID, guestType: "qemu", tags: vm.Tags, pool: vm.Pool, locked: vm.Lock != ""})
+	}
+	for _, ct := range state.Containers {
+		if ct.Template {
+			continue
+		}
+		guests = append(guests, retentionGuest{id: ct.ID, name: ct.Name, instance: ct.Instance, node: ct.Node, vmid: ct.VMID, guestType: "lxc", tags: ct.Tags, pool: ct.Pool, locked: ct.Lock != ""})
+	}
+
+	now := time.Now()
+	evaluated, actions := planSnapshotRetention(cfg, guests, state.PVEBackups.GuestSnapshots, now)
+
+	return models.SnapshotRetentionRun{
+		ID:        uuid.NewString(),
+		Trigger:   trigger,
+		DryRun:    dryRun,
+		StartedAt: now,
+		Evaluated: evaluated,
+		Planned:   len(actions),
+		Actions:   actions,
+	}
+}
+
+func (m *Monitor) deleteRetainedSnapshot(ctx context.Context, action *models.SnapshotRetentionAction) {
+	fail := func(err error) {
+		action.Outcome = models.SnapshotRetentionFailed
+		action.Error = err.Error()
+		log.Warn().Ctx(ctx).
+			Err(err).
+			Str("guest", action.GuestID).
+			Str("snapshot", action.Snapshot).
+			Msg("Snapshot retention failed to delete snapshot")
+	}
+
+	client, err := m.guestSnapshotClient(action.Instance)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	taskCtx, cancel := context.WithTimeout(ctx, snapshotRetentionDeleteTimeout)
+	defer cancel()
+
+	upid, err := client.DeleteSnapshot(taskCtx, action.Node, proxmoxGuestType(action.Type), action.VMID, action.Snapshot)
+	if err != nil {
+		fail(err)
+		return
+	}
+	action.UPID = upid
+
+	// Wait for each deletion: Proxmox locks the guest while a snapshot is removed
+	result, err := client.WaitForTask(taskCtx, action.Node, upid)
+	if err != nil {
+		fail(fmt.Errorf("failed to follow task: %w", err))
+		return
+	}
+	if !result.Succeeded() {
+		fail(fmt.Errorf("task failed: %s", result.ExitStatus))
+		return
+	}
+
+	action.Outcome = models.SnapshotRetentionDeleted
+	m.state.RemoveGuestSnapshot(action.SnapshotID)
+	log.Info().Ctx(ctx).
+		Str("guest", action.GuestID).
+		Str("snapshot", action.Snapshot).
+		Str("policy", action.PolicyName).
+		Str("reason", action.Reason).
+		Msg("Snapshot retention deleted snapshot")
+}
+
+// planSnapshotRetention decides which snapshots the policies would delete. When several
+// policies cover the same snapshot, it is only deleted if all of them agree.
+func planSnapshotRetention(cfg config.SnapshotRetentionConfig, guests []retentionGuest, snapshots []models.GuestSnapshot, now time.Time) (int, []models.SnapshotRetentionAction) {
+	byGuest := make(map[string][]models.GuestSnapshot)
+	for _, snap := range snapshots {
+		key := fmt.Sprintf("%s/%s/%d", snap.Instance, snap.Node, snap.VMID)
+		byGuest[key] = append(byGuest[key], snap)
+	}
+
+	evaluated := 0
+	actions := make([]models.SnapshotRetentionAction, 0)
+
+	for _, guest := range guests {
+		guestSnapshots := byGuest[fmt.Sprintf("%s/%s/%d", guest.instance, guest.node, guest.vmid)]
+		if len(guestSnapshots) == 0 {
+			continue
+		}
+
+		policies := matchingRetentionPolicies(cfg.Policies, guest)
+		if len(policies) == 0 {
+			continue
+		}
+		if guest.locked {
+			// A locked guest is mid-backup, migration or snapshot; try again next run
+			continue
+		}
+
+		sort.Slice(guestSnapshots, func(i, j int) bool {
+			return guestSnapshots[i].Time.After(guestSnapshots[j].Time)
+		})
+
+		votes := make(map[string]*retentionVote)
+		for _, policy := range policies {
+			maxAge, err := config.ParseRetentionAge(policy.MaxAge)
+			if err != nil {
+				continue
+			}
+
+			position := 0
+			for _, snap := range guestSnapshots {
+				if policy.NamePattern != "" && !globMatch(policy.NamePattern, snap.Name) {
+					continue
+				}
+				if snapshotExcluded(cfg.Exclusions, guest.id, snap.Name) {
+					continue
+				}
+
+				vote := votes[snap.ID]
+				if vote == nil {
+					vote = &retentionVote{}
+					votes[snap.ID] = vote
+				}
+				vote.covering++
+
+				beyondKeep := policy.KeepLast > 0 && position >= policy.KeepLast
+				tooOld := maxAge > 0 && now.Sub(snap.Time) > maxAge
+				position++
+
+				var reason string
+				switch {
+				case policy.KeepLast > 0 && maxAge > 0:
+					if beyondKeep && tooOld {
+						reason = fmt.Sprintf("beyond the newest %d and older than %s", policy.KeepLast, policy.MaxAge)
+					}
+				case policy.KeepLast > 0:
+					if beyondKeep {
+						reason = fmt.Sprintf("beyond the newest %d", policy.KeepLast)
+					}
+				case tooOld:
+					reason = fmt.Sprintf("older than %s", policy.MaxAge)
+				}
+
+				if reason != "" {
+					vote.deletes++
+					if vote.reason == "" {
+						vote.reason = reason
+						vote.policy = policy
+					}
+				}
+			}
+		}
+
+		// Oldest first so interrupted runs remove the most stale snapshots
+		for i := len(guestSnapshots) - 1; i >= 0; i-- {
+			snap := guestSnapshots[i]
+			vote := votes[snap.ID]
+			if vote == nil {
+				continue
+			}
+			evaluated++
+			if vote.deletes == 0 || vote.deletes != vote.covering {
+				continue
+			}
+			actions = append(actions, models.SnapshotRetentionAction{
+				SnapshotID:   snap.ID,
+				GuestID:      guest.id,
+				GuestName:    guest.name,
+				Instance:     guest.instance,
+				Node:         guest.node,
+				VMID:         guest.vmid,
+				Type:         guest.guestType,
+				Snapshot:     snap.Name,
+				SnapshotTime: snap.Time,
+				PolicyID:     vote.policy.ID,
+				PolicyName:   vote.policy.Name,
+				Reason:       vote.reason,
+				Outcome:      models.SnapshotRetentionPlanned,
+			})
+		}
+	}
+
+	return evaluated, actions
+}
+
+func matchingRetentionPolicies(policies []config.SnapshotRetentionPolicy, guest retentionGuest) []config.SnapshotRetentionPolicy {
+	var matched []config.SnapshotRetentionPolicy
+	for _, policy := range policies {
+		if policy.Enabled && retentionPolicyMatches(policy, guest) {
+			matched = append(matched, policy)
+		}
+	}
+	return matched
+}
+
+func retentionPolicyMatches(policy config.SnapshotRetentionPolicy, guest retentionGuest) bool {
+	for _, id := range policy.Guests {
+		if id == guest.id {
+			return true
+		}
+	}
+	if guest.pool != "" {
+		for _, pool := range policy.Pools {
+			if pool == guest.pool {
+				return true
+			}
+		}
+	}
+	for _, tag := range policy.Tags {
+		for _, guestTag := range guest.tags {
+			if strings.EqualFold(tag, strings.TrimSpace(guestTag)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// snapshotExcluded checks exclusions: snapshot name globs, guest IDs or "guest-id:glob" pairs
+func snapshotExcluded(exclusions []string, guestID, name string) bool {
+	for _, exclusion := range exclusions {
+		if guest, pattern, ok := strings.Cut(exclusion, ":"); ok {
+			if guest == guestID && globMatch(pattern, name) {
+				return true
+			}
+			continue
+		}
+		if exclusion == guestID || globMatch(exclusion, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func globMatch(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// GetSnapshotRetentionHistory returns recorded retention runs, newest first
+func (m *Monitor) GetSnapshotRetentionHistory() ([]models.SnapshotRetentionRun, error) {
+	data, err := os.ReadFile(m.snapshotRetentionHistoryPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.SnapshotRetentionRun{}, nil
+		}
+		return nil, err
+	}
+
+	var runs []models.SnapshotRetentionRun
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (m *Monitor) appendSnapshotRetentionHistory(run models.SnapshotRetentionRun) error {
+	runs, err := m.GetSnapshotRetentionHistory()
+	if err != nil {
+		log.Warn().Err(err).Msg("Discarding unreadable snapshot retention history")
+		runs = nil
+	}
+
+	runs = append([]models.SnapshotRetentionRun{run}, runs...)
+	if len(runs) > snapshotRetentionHistoryLimit {
+		runs = runs[:snapshotRetentionHistoryLimit]
+	}
+
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	historyPath := m.snapshotRetentionHistoryPath()
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		return err
+	}
+	tmp := historyPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, historyPath)
+}
+
+func (m *Monitor) snapshotRetentionHistoryPath() string {
+	dataPath := "/etc/pulse"
+	if m.config != nil && m.config.DataPath != "" {
+		dataPath = m.config.DataPath
+	}
+	return filepath.Join(dataPath, snapshotRetentionHistoryFile)
+}
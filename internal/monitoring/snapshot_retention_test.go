@@ -0,0 +1,184 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func retentionTestSnapshots(now time.Time, instance, node string, vmid int, ages map[string]time.Duration) []models.GuestSnapshot {
+	snapshots := make([]models.GuestSnapshot, 0, len(ages))
+	for name, age := range ages {
+		snapshots = append(snapshots, models.GuestSnapshot{
+			ID:       guestSnapshotID(instance, node, vmid, name),
+			Name:     name,
+			Instance: instance,
+			Node:     node,
+			VMID:     vmid,
+			Time:     now.Add(-age),
+		})
+	}
+	return snapshots
+}
+
+func plannedSnapshotNames(actions []models.SnapshotRetentionAction) []string {
+	names := make([]string, 0, len(actions))
+	for _, action := range actions {
+		names = append(names, action.Snapshot)
+	}
+	return names
+}
+
+func TestPlanSnapshotRetention(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	guest := retentionGuest{
+		id:        "pve1-node1-100",
+		name:      "web",
+		instance:  "pve1",
+		node:      "node1",
+		vmid:      100,
+		guestType: "qemu",
+		tags:      []string{"Prod"},
+		pool:      "web",
+	}
+	snapshots := retentionTestSnapshots(now, "pve1", "node1", 100, map[string]time.Duration{
+		"auto-1":    1 * day,
+		"auto-2":    5 * day,
+		"auto-3":    10 * day,
+		"auto-4":    20 * day,
+		"keep-gold": 30 * day,
+	})
+
+	tests := []struct {
+		name       string
+		cfg        config.SnapshotRetentionConfig
+		guest      retentionGuest
+		wantPlan   []string
+		wantEvalAt int
+	}{
+		{
+			name: "keep last deletes oldest beyond count",
+			cfg: config.SnapshotRetentionConfig{Policies: []config.SnapshotRetentionPolicy{
+				{ID: "a", Enabled: true, Tags: []string{"prod"}, KeepLast: 3},
+			}},
+			guest:      guest,
+			wantPlan:   []string{"keep-gold", "auto-4"},
+			wantEvalAt: 5,
+		},
+		{
+			name: "max age only",
+			cfg: config.SnapshotRetentionConfig{Policies: []config.SnapshotRetentionPolicy{
+				{ID: "a", Enabled: true, Pools: []string{"web"}, MaxAge: "7d"},
+			}},
+			guest:      guest,
+			wantPlan:   []string{"keep-gold", "auto-4", "auto-3"},
+			wantEvalAt: 5,
+		},
+		{
+			name: "keep last and max age must both be exceeded",
+			cfg: config.SnapshotRetentionConfig{Policies: []config.SnapshotRetentionPolicy{
+				{ID: "a", Enabled: true, Guests: []string{"pve1-node1-100"}, KeepLast: 1, MaxAge: "15d"},
+			}},
+			guest:      guest,
+			wantPlan:   []string{"keep-gold", "auto-4"},
+			wantEvalAt: 5,
+		},
+		{
+			name: "exclusions and name pattern protect snapshots",
+			cfg: config.SnapshotRetentionConfig{
+				Exclusions: []string{"keep-*", "pve1-node1-100:auto-4"},
+				Policies: []config.SnapshotRetentionPolicy{
+					{ID: "a", Enabled: true, Tags: []string{"prod"}, NamePattern: "auto-*", KeepLast: 1},
+				},
+			},
+			guest:      guest,
+			wantPlan:   []string{"auto-3", "auto-2"},
+			wantEvalAt: 3,
+		},
+		{
+			name: "overlapping policies must all agree",
+			cfg: config.SnapshotRetentionConfig{Policies: []config.SnapshotRetentionPolicy{
+				{ID: "a", Enabled: true, Tags: []string{"prod"}, KeepLast: 1},
+				{ID: "b", Enabled: true, Pools: []string{"web"}, MaxAge: "15d"},
+			}},
+			guest:      guest,
+			wantPlan:   []string{"keep-gold", "auto-4"},
+			wantEvalAt: 5,
+		},
+		{
+			name: "disabled and non-matching policies are ignored",
+			cfg: config.SnapshotRetentionConfig{Policies: []config.SnapshotRetentionPolicy{
+				{ID: "a", Enabled: false, Tags: []string{"prod"}, KeepLast: 1},
+				{ID: "b", Enabled: true, Tags: []string{"dev"}, KeepLast: 1},
+			}},
+			guest:      guest,
+			wantPlan:   nil,
+			wantEvalAt: 0,
+		},
+		{
+			name: "locked guests are skipped",
+			cfg: config.SnapshotRetentionConfig{Policies: []config.SnapshotRetentionPolicy{
+				{ID: "a", Enabled: true, Tags: []string{"prod"}, KeepLast: 1},
+			}},
+			guest: func() retentionGuest {
+				locked := guest
+				locked.locked = true
+				return locked
+			}(),
+			wantPlan:   nil,
+			wantEvalAt: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated, actions := planSnapshotRetention(tt.cfg, []retentionGuest{tt.guest}, snapshots, now)
+			if evaluated != tt.wantEvalAt {
+				t.Errorf("evaluated = %d, want %d", evaluated, tt.wantEvalAt)
+			}
+			got := plannedSnapshotNames(actions)
+			if len(got) != len(tt.wantPlan) {
+				t.Fatalf("planned %v, want %v", got, tt.wantPlan)
+			}
+			for i := range got {
+				if got[i] != tt.wantPlan[i] {
+					t.Fatalf("planned %v, want %v", got, tt.wantPlan)
+				}
+			}
+			for _, action := range actions {
+				if action.Outcome != models.SnapshotRetentionPlanned {
+					t.Errorf("action %s outcome = %q, want planned", action.Snapshot, action.Outcome)
+				}
+				if action.GuestID != tt.guest.id || action.Reason == "" {
+					t.Errorf("action %s missing guest or reason: %+v", action.Snapshot, action)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotExcluded(t *testing.T) {
+	exclusions := []string{"keep-*", "pve1-node1-101", "pve1-node1-100:pre-upgrade"}
+
+	cases := []struct {
+		guestID string
+		name    string
+		want    bool
+	}{
+		{"pve1-node1-100", "keep-forever", true},
+		{"pve1-node1-101", "auto-1", true},
+		{"pve1-node1-100", "pre-upgrade", true},
+		{"pve1-node1-102", "pre-upgrade", false},
+		{"pve1-node1-100", "auto-1", false},
+	}
+
+	for _, tc := range cases {
+		if got := snapshotExcluded(exclusions, tc.guestID, tc.name); got != tc.want {
+			t.Errorf("snapshotExcluded(%q, %q) = %t, want %t", tc.guestID, tc.name, got, tc.want)
+		}
+	}
+}
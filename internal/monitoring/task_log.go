@@ -0,0 +1,266 @@
+package monitoring
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	taskLogInterval = time.Minute
+	taskLogTimeout  = time.Minute
+	// taskLogPageSize caps how many tasks are read per node and collection
+	taskLogPageSize = 500
+	// taskLogBackfill is how far back the first collection for a node reaches
+	taskLogBackfill = 24 * time.Hour
+	// maxTaskHistory bounds the in-memory task history across all instances
+	maxTaskHistory = 5000
+)
+
+// TaskLogClient is implemented by PVE clients that can list node task logs with filters.
+type TaskLogClient interface {
+	ListNodeTasks(ctx context.Context, node string, opts proxmox.TaskListOptions) ([]proxmox.Task, error)
+}
+
+// TaskFilter selects tasks from the collected history. Empty fields match everything.
+type TaskFilter struct {
+	Instance string
+	Node     string
+	Type     string
+	State    string
+	User     string
+	VMID     int
+	Since    time.Time
+	Limit    int
+}
+
+// checkTaskLog collects new tasks from the online nodes of a PVE instance in the background
+func (m *Monitor) checkTaskLog(instanceName string, client PVEClientInterface, nodes []models.Node) {
+	taskClient, ok := client.(TaskLogClient)
+	if !ok || !m.taskLogDue(instanceName, time.Now()) {
+		return
+	}
+
+	var nodeNames []string
+	for _, node := range nodes {
+		if node.Status == "online" {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+	if len(nodeNames) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := m.backgroundContext(taskLogTimeout)
+		defer cancel()
+
+		var finished []models.ClusterTask
+		for _, node := range nodeNames {
+			if ctx.Err() != nil {
+				break
+			}
+			finished = append(finished, m.collectNodeTasks(ctx, instanceName, node, taskClient, time.Now())...)
+		}
+
+		if m.alertManager != nil {
+			m.alertManager.CheckTasks(finished)
+		}
+	}()
+}
+
+// taskLogDue reports whether an instance's task log should be collected and reserves the slot
+func (m *Monitor) taskLogDue(instanceName string, now time.Time) bool {
+	m.taskLogMu.Lock()
+	defer m.taskLogMu.Unlock()
+
+	if m.taskLogPolled == nil {
+		m.taskLogPolled = make(map[string]time.Time)
+	}
+	if last, ok := m.taskLogPolled[instanceName]; ok && now.Sub(last) < taskLogInterval {
+		return false
+	}
+	m.taskLogPolled[instanceName] = now
+	return true
+}
+
+// collectNodeTasks reads tasks started since the node's cursor, merges them into the history and
+// returns the tasks that finished since the last collection. The first collection for a node only
+// backfills history so old failures don't raise alerts.
+func (m *Monitor) collectNodeTasks(ctx context.Context, instanceName, node string, client TaskLogClient, now time.Time) []models.ClusterTask {
+	cursorKey := instanceName + "/" + node
+
+	m.taskLogMu.Lock()
+	since, seen := m.taskCursors[cursorKey]
+	m.taskLogMu.Unlock()
+	if !seen {
+		since = now.Add(-taskLogBackfill).Unix()
+	}
+
+	tasks, err := client.ListNodeTasks(ctx, node, proxmox.TaskListOptions{
+		Since:  since,
+		Limit:  taskLogPageSize,
+		Source: "all",
+	})
+	if err != nil {
+		log.Debug().Ctx(ctx).Err(err).Str("instance", instanceName).Str("node", node).Msg("Failed to collect node task log")
+		return nil
+	}
+
+	m.taskLogMu.Lock()
+	defer m.taskLogMu.Unlock()
+
+	finished, cursor := mergeTasks(m.taskHistory(), instanceName, tasks, since)
+	if m.taskCursors == nil {
+		m.taskCursors = make(map[string]int64)
+	}
+	m.taskCursors[cursorKey] = cursor
+	m.trimTaskHistoryLocked()
+
+	if !seen {
+		return nil
+	}
+	return finished
+}
+
+// taskHistory returns the history map, creating it on first use (must be called with taskLogMu held)
+func (m *Monitor) taskHistory() map[string]models.ClusterTask {
+	if m.tasks == nil {
+		m.tasks = make(map[string]models.ClusterTask)
+	}
+	return m.tasks
+}
+
+// mergeTasks stores tasks in history and returns the ones that newly finished together with the
+// next cursor. The cursor is the latest start time seen, held back to the oldest task still running
+// so its completion is picked up by a later collection.
+func mergeTasks(history map[string]models.ClusterTask, instanceName string, tasks []proxmox.Task, since int64) ([]models.ClusterTask, int64) {
+	var finished []models.ClusterTask
+	cursor := since
+	oldestRunning := int64(0)
+
+	for _, raw := range tasks {
+		task := convertTask(instanceName, raw)
+		previous, existed := history[task.ID]
+		history[task.ID] = task
+
+		if raw.StartTime > cursor {
+			cursor = raw.StartTime
+		}
+		if !task.Finished() {
+			if oldestRunning == 0 || raw.StartTime < oldestRunning {
+				oldestRunning = raw.StartTime
+			}
+			continue
+		}
+		if !existed || !previous.Finished() {
+			finished = append(finished, task)
+		}
+	}
+
+	if oldestRunning > 0 && oldestRunning < cursor {
+		cursor = oldestRunning
+	}
+	return finished, cursor
+}
+
+// convertTask maps a Proxmox task to the history model
+func convertTask(instanceName string, task proxmox.Task) models.ClusterTask {
+	converted := models.ClusterTask{
+		ID:        instanceName + "-" + task.UPID,
+		UPID:      task.UPID,
+		Instance:  instanceName,
+		Node:      task.Node,
+		Type:      task.Type,
+		Target:    task.ID,
+		User:      task.User,
+		Status:    task.Status,
+		State:     taskState(task.Status, task.EndTime),
+		StartTime: time.Unix(task.StartTime, 0),
+	}
+	if vmid, err := strconv.Atoi(task.ID); err == nil {
+		converted.VMID = vmid
+	}
+	if task.EndTime > 0 {
+		converted.EndTime = time.Unix(task.EndTime, 0)
+		converted.Duration = converted.EndTime.Sub(converted.StartTime).Seconds()
+	}
+	return converted
+}
+
+// taskState classifies a Proxmox exit status. Running tasks have no end time and no status
+// (or "running" on some versions); "OK" succeeded, "WARNINGS: n" finished with warnings and
+// anything else is the error message of a failed task.
+func taskState(status string, endTime int64) string {
+	status = strings.TrimSpace(status)
+	switch {
+	case status == "" && endTime == 0, strings.EqualFold(status, "running"):
+		return models.TaskStateRunning
+	case status == "OK":
+		return models.TaskStateOK
+	case strings.HasPrefix(status, "WARNINGS"):
+		return models.TaskStateWarning
+	default:
+		return models.TaskStateFailed
+	}
+}
+
+// trimTaskHistoryLocked drops the oldest tasks beyond maxTaskHistory (must be called with taskLogMu held)
+func (m *Monitor) trimTaskHistoryLocked() {
+	if len(m.tasks) <= maxTaskHistory {
+		return
+	}
+	ordered := make([]models.ClusterTask, 0, len(m.tasks))
+	for _, task := range m.tasks {
+		ordered = append(ordered, task)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].StartTime.After(ordered[j].StartTime)
+	})
+	for _, task := range ordered[maxTaskHistory:] {
+		delete(m.tasks, task.ID)
+	}
+}
+
+// GetTasks returns collected tasks matching the filter, newest first
+func (m *Monitor) GetTasks(filter TaskFilter) []models.ClusterTask {
+	m.taskLogMu.Lock()
+	result := make([]models.ClusterTask, 0)
+	for _, task := range m.tasks {
+		if filter.matches(task) {
+			result = append(result, task)
+		}
+	}
+	m.taskLogMu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].StartTime.Equal(result[j].StartTime) {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].StartTime.After(result[j].StartTime)
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result
+}
+
+func (f TaskFilter) matches(task models.ClusterTask) bool {
+	switch {
+	case f.Instance != "" && task.Instance != f.Instance,
+		f.Node != "" && task.Node != f.Node,
+		f.Type != "" && task.Type != f.Type,
+		f.State != "" && task.State != f.State,
+		f.User != "" && task.User != f.User,
+		f.VMID > 0 && task.VMID != f.VMID,
+		!f.Since.IsZero() && task.StartTime.Before(f.Since):
+		return false
+	}
+	return true
+}
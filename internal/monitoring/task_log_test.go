@@ -0,0 +1,106 @@
+package monitoring
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+)
+
+type fakeTaskLogClient struct {
+	tasks []proxmox.Task
+	since []int64
+}
+
+func (f *fakeTaskLogClient) ListNodeTasks(ctx context.Context, node string, opts proxmox.TaskListOptions) ([]proxmox.Task, error) {
+	f.since = append(f.since, opts.Since)
+	var result []proxmox.Task
+	for _, task := range f.tasks {
+		if task.Node == node && task.StartTime >= opts.Since {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+func TestTaskState(t *testing.T) {
+	cases := []struct {
+		status  string
+		endTime int64
+		want    string
+	}{
+		{"", 0, models.TaskStateRunning},
+		{"running", 0, models.TaskStateRunning},
+		{"OK", 100, models.TaskStateOK},
+		{"WARNINGS: 1", 100, models.TaskStateWarning},
+		{"migration aborted", 100, models.TaskStateFailed},
+		{"unexpected status", 100, models.TaskStateFailed},
+	}
+	for _, tc := range cases {
+		if got := taskState(tc.status, tc.endTime); got != tc.want {
+			t.Errorf("taskState(%q, %d) = %q, want %q", tc.status, tc.endTime, got, tc.want)
+		}
+	}
+}
+
+func TestCollectNodeTasksIncremental(t *testing.T) {
+	now := time.Unix(1700100000, 0)
+	client := &fakeTaskLogClient{tasks: []proxmox.Task{
+		{UPID: "UPID:old", Node: "pve1", Type: "qmigrate", ID: "100", User: "root@pam", StartTime: now.Unix() - 3600, EndTime: now.Unix() - 3500, Status: "migration aborted"},
+		{UPID: "UPID:run", Node: "pve1", Type: "qmclone", ID: "101", User: "admin@pve", StartTime: now.Unix() - 600},
+	}}
+	m := &Monitor{}
+
+	// First collection only backfills history
+	if finished := m.collectNodeTasks(context.Background(), "lab", "pve1", client, now); len(finished) != 0 {
+		t.Fatalf("expected no alerts from backfill, got %d", len(finished))
+	}
+	if len(m.GetTasks(TaskFilter{})) != 2 {
+		t.Fatalf("expected 2 tasks in history")
+	}
+	// The running clone holds the cursor back
+	if cursor := m.taskCursors["lab/pve1"]; cursor != now.Unix()-600 {
+		t.Fatalf("cursor = %d, want %d", cursor, now.Unix()-600)
+	}
+
+	// The clone fails and a new migration succeeds
+	client.tasks[1].EndTime = now.Unix() - 60
+	client.tasks[1].Status = "clone failed: storage full"
+	client.tasks = append(client.tasks, proxmox.Task{UPID: "UPID:new", Node: "pve1", Type: "qmigrate", ID: "100", User: "root@pam", StartTime: now.Unix() - 30, EndTime: now.Unix() - 5, Status: "OK"})
+
+	finished := m.collectNodeTasks(context.Background(), "lab", "pve1", client, now)
+	if len(finished) != 2 {
+		t.Fatalf("expected 2 newly finished tasks, got %+v", finished)
+	}
+	if client.since[1] != now.Unix()-600 {
+		t.Fatalf("second collection since = %d", client.since[1])
+	}
+
+	failed := m.GetTasks(TaskFilter{State: models.TaskStateFailed, User: "admin@pve"})
+	if len(failed) != 1 || failed[0].Type != "qmclone" || failed[0].VMID != 101 || failed[0].Duration != 540 {
+		t.Fatalf("unexpected failed tasks: %+v", failed)
+	}
+	if migrations := m.GetTasks(TaskFilter{Type: "qmigrate", Limit: 1}); len(migrations) != 1 || migrations[0].UPID != "UPID:new" {
+		t.Fatalf("expected newest migration first, got %+v", migrations)
+	}
+}
+
+func TestTrimTaskHistory(t *testing.T) {
+	m := &Monitor{tasks: make(map[string]models.ClusterTask)}
+	base := time.Unix(1700000000, 0)
+	for i := 0; i < maxTaskHistory+10; i++ {
+		id := "task-" + strconv.Itoa(i)
+		m.tasks[id] = models.ClusterTask{ID: id, StartTime: base.Add(time.Duration(i) * time.Second)}
+	}
+	m.trimTaskHistoryLocked()
+
+	if len(m.tasks) != maxTaskHistory {
+		t.Fatalf("history size = %d, want %d", len(m.tasks), maxTaskHistory)
+	}
+	if _, ok := m.tasks["task-0"]; ok {
+		t.Fatal("expected oldest task to be trimmed")
+	}
+}
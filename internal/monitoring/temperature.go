@@ -90,7 +90,7 @@ func (tc *TemperatureCollector) CollectTemperature(ctx context.Context, nodeHost
 		output, err = tc.proxyClient.GetTemperature(host)
 		if err != nil {
 			tc.handleProxyFailure(err)
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", nodeName).
 				Str("host", host).
 				Err(err).
@@ -103,7 +103,7 @@ func (tc *TemperatureCollector) CollectTemperature(ctx context.Context, nodeHost
 		// Container compromise = SSH key compromise = root access to infrastructure
 		devModeAllowSSH := os.Getenv("PULSE_DEV_ALLOW_CONTAINER_SSH") == "true"
 		if isRunningInContainer() && !devModeAllowSSH {
-			log.Error().
+			log.Error().Ctx(ctx).
 				Str("node", nodeName).
 				Msg("SECURITY BLOCK: SSH temperature collection disabled in containers - deploy pulse-sensor-proxy")
 			return &models.Temperature{Available: false}, nil
@@ -145,7 +145,7 @@ func (tc *TemperatureCollector) CollectTemperature(ctx context.Context, nodeHost
 				return &models.Temperature{Available: false}, nil
 			}
 
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("node", nodeName).
 				Str("host", host).
 				Err(err).
@@ -157,7 +157,7 @@ func (tc *TemperatureCollector) CollectTemperature(ctx context.Context, nodeHost
 	// Parse sensors JSON output
 	temp, err := tc.parseSensorsJSON(output)
 	if err != nil {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("node", nodeName).
 			Err(err).
 			Msg("Failed to parse sensors output")
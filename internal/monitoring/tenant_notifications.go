@@ -0,0 +1,69 @@
+package monitoring
+
+import (
+	"context"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/tenancy"
+)
+
+// SetTenantScopesConfig replaces the tenant scopes used to route alert notifications
+func (m *Monitor) SetTenantScopesConfig(cfg config.TenantScopesConfig) {
+	m.tenantMu.Lock()
+	defer m.tenantMu.Unlock()
+	m.tenantScopes = config.NormalizeTenantScopesConfig(cfg)
+}
+
+// tenantNotificationTargets returns the notification targets of the tenant scopes that can see an alert
+func (m *Monitor) tenantNotificationTargets(alert *alerts.Alert) []alerts.EscalationTarget {
+	m.tenantMu.RLock()
+	scopes := m.tenantScopes.Scopes
+	m.tenantMu.RUnlock()
+
+	var targets []alerts.EscalationTarget
+	var snapshotScopes []config.TenantScope
+	for _, tenantScope := range scopes {
+		if len(tenantScope.Emails) == 0 && len(tenantScope.Webhooks) == 0 {
+			continue
+		}
+		scope := tenancy.New([]config.TenantScope{tenantScope})
+		if !scope.AllowsInstance(alert.Instance) {
+			// Guest level scopes need the current inventory to attribute the alert
+			snapshotScopes = append(snapshotScopes, tenantScope)
+			continue
+		}
+		targets = append(targets, tenantTarget(tenantScope))
+	}
+	if len(snapshotScopes) == 0 {
+		return targets
+	}
+
+	snapshot := m.GetState()
+	for _, tenantScope := range snapshotScopes {
+		scope := tenancy.New([]config.TenantScope{tenantScope})
+		if scope.AllowsAlert(alert.Instance, alert.ResourceID, scope.Guests(snapshot)) {
+			targets = append(targets, tenantTarget(tenantScope))
+		}
+	}
+	return targets
+}
+
+func tenantTarget(scope config.TenantScope) alerts.EscalationTarget {
+	return alerts.EscalationTarget{
+		Name:     "tenant:" + scope.Name,
+		Emails:   scope.Emails,
+		Webhooks: scope.Webhooks,
+	}
+}
+
+// notifyTenantScopes sends a raised alert to the notification targets of every tenant scope
+// that can see it, in addition to the global channels
+func (m *Monitor) notifyTenantScopes(ctx context.Context, alert *alerts.Alert) {
+	if alert == nil || m.notificationMgr == nil {
+		return
+	}
+	for _, target := range m.tenantNotificationTargets(alert) {
+		m.notificationMgr.SendEscalation(ctx, alert, target)
+	}
+}
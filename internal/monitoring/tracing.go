@@ -0,0 +1,90 @@
+package monitoring
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// alertTraceTTL bounds how long an alert check's span may parent the notifications it raises.
+// Alerts escalated or resolved outside a poll long after the last check start their own trace.
+const alertTraceTTL = 10 * time.Minute
+
+// alertTraces remembers the span of the latest alert check per resource. Alert callbacks run
+// on their own goroutines without a context, so notifications look up the check that raised
+// them here.
+type alertTraces struct {
+	mu    sync.Mutex
+	spans map[string]alertTrace
+}
+
+type alertTrace struct {
+	span    trace.SpanContext
+	checked time.Time
+}
+
+func (t *alertTraces) record(key string, span trace.SpanContext, now time.Time) {
+	if key == "" || !span.IsValid() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.spans == nil {
+		t.spans = make(map[string]alertTrace)
+	}
+	t.spans[key] = alertTrace{span: span, checked: now}
+}
+
+func (t *alertTraces) lookup(now time.Time, keys ...string) (trace.SpanContext, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, key := range keys {
+		entry, ok := t.spans[key]
+		if !ok {
+			continue
+		}
+		if now.Sub(entry.checked) > alertTraceTTL {
+			delete(t.spans, key)
+			continue
+		}
+		return entry.span, true
+	}
+	return trace.SpanContext{}, false
+}
+
+// traceAlertCheck runs an alert evaluation as a span of the poll in ctx, so slow rules show up
+// next to the API calls that fed them
+func (m *Monitor) traceAlertCheck(ctx context.Context, check, resourceID string, evaluate func()) {
+	ctx, span := tracing.Start(ctx, "alerts "+check, attribute.String("pulse.resource", resourceID))
+	defer span.End()
+	m.alertTraces.record(resourceID, trace.SpanContextFromContext(ctx), time.Now())
+	evaluate()
+}
+
+// alertContext returns a context carrying the span of the check that raised alert, so its
+// notifications join the poll's trace. Backup and snapshot checks run per instance, so the
+// instance is tried when the resource has no check of its own.
+func (m *Monitor) alertContext(alert *alerts.Alert) context.Context {
+	ctx := context.Background()
+	if alert == nil {
+		return ctx
+	}
+	if span, ok := m.alertTraces.lookup(time.Now(), alert.ResourceID, alert.Instance); ok {
+		ctx = trace.ContextWithSpanContext(ctx, span)
+	}
+	return ctx
+}
+
+// tracedTransport wraps the transport of a PVE, PBS or PMG client so every API call becomes a
+// client span of the poll in the request context
+func tracedTransport(system string) func(http.RoundTripper) http.RoundTripper {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return tracing.Transport(system, base)
+	}
+}
@@ -0,0 +1,81 @@
+package monitoring
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/logging"
+	"github.com/RouXx67/PulseUp/internal/models"
+	"github.com/RouXx67/PulseUp/internal/tracing"
+	"github.com/RouXx67/PulseUp/pkg/proxmox"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPollLogLinesCarryTraceID(t *testing.T) {
+	t.Setenv("PULSE_DATA_DIR", t.TempDir())
+
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	var buf bytes.Buffer
+	previousLogger := log.Logger
+	log.Logger = logging.New("test", logging.WithWriter(zerolog.SyncWriter(&buf)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		log.Logger = previousLogger
+	})
+
+	monitor := &Monitor{
+		state:          &models.State{},
+		metricsHistory: NewMetricsHistory(16, time.Hour),
+		alertManager:   alerts.NewManager(),
+	}
+	t.Cleanup(monitor.alertManager.Stop)
+
+	storage := proxmox.Storage{Storage: "local", Type: "dir", Content: "images", Active: 1, Enabled: 1, Total: 1000, Used: 100, Available: 900}
+	client := &fakeStorageClient{
+		allStorage:    []proxmox.Storage{storage},
+		storageByNode: map[string][]proxmox.Storage{"node1": {storage}},
+	}
+	// node2 is unknown to the fake client, so its storage query fails and is logged
+	nodes := []proxmox.Node{{Node: "node1", Status: "online"}, {Node: "node2", Status: "online"}}
+
+	ctx, span := tracing.Start(context.Background(), "poll pve")
+	monitor.pollStorageWithNodes(ctx, "inst1", client, nodes)
+	span.End()
+	traceID := span.SpanContext().TraceID().String()
+
+	found := false
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line["message"] == "Failed to get node storage - check API permissions" {
+			found = true
+			if line["trace_id"] != traceID {
+				t.Fatalf("poll log line has trace_id %v, want %s", line["trace_id"], traceID)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("poll did not log the failed storage query:\n%s", buf.String())
+	}
+
+	alertCtx := monitor.alertContext(&alerts.Alert{ResourceID: "inst1-node1-local"})
+	if got := trace.SpanContextFromContext(alertCtx).TraceID().String(); got != traceID {
+		t.Fatalf("alert context has trace %s, want the poll trace %s", got, traceID)
+	}
+	if trace.SpanContextFromContext(monitor.alertContext(&alerts.Alert{ResourceID: "other"})).IsValid() {
+		t.Fatal("expected no trace for a resource without an alert check")
+	}
+}
@@ -1,6 +1,7 @@
 package notifications
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -65,7 +66,7 @@ func TestNotificationManagerEmailConfigConcurrency(t *testing.T) {
 				Threshold:    80,
 				StartTime:    time.Now(),
 			}
-			manager.SendAlert(alert)
+			manager.SendAlert(context.Background(), alert)
 		}
 	}()
 
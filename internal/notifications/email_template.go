@@ -1,48 +1,333 @@
 package notifications
 
 import (
+	"bytes"
 	"fmt"
+	htmltemplate "html/template"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/rs/zerolog/log"
 )
 
-// EmailTemplate generates a professional HTML email template for alerts
+// EmailTemplateSet holds the Go templates used to render one kind of email.
+// Empty fields fall back to the built-in template of the same kind.
+type EmailTemplateSet struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// EmailTemplates holds user-editable templates for single and grouped alert emails
+type EmailTemplates struct {
+	Single  EmailTemplateSet `json:"single"`
+	Grouped EmailTemplateSet `json:"grouped"`
+}
+
+// IsEmpty reports whether no custom template has been configured
+func (t EmailTemplates) IsEmpty() bool {
+	return t == EmailTemplates{}
+}
+
+// EmailTemplateData is passed to email templates. It exposes the same fields as
+// webhook payload templates (for the first alert) plus per-alert entries for grouped emails.
+type EmailTemplateData struct {
+	WebhookPayloadData
+	Items         []WebhookPayloadData
+	CriticalCount int
+	WarningCount  int
+}
+
+// DefaultEmailTemplates returns the built-in email templates
+func DefaultEmailTemplates() EmailTemplates {
+	return EmailTemplates{
+		Single: EmailTemplateSet{
+			Subject: defaultSingleSubjectTemplate,
+			HTML:    defaultSingleHTMLTemplate,
+			Text:    defaultSingleTextTemplate,
+		},
+		Grouped: EmailTemplateSet{
+			Subject: defaultGroupedSubjectTemplate,
+			HTML:    defaultGroupedHTMLTemplate,
+			Text:    defaultGroupedTextTemplate,
+		},
+	}
+}
+
+// withDefaults fills empty template fields with the built-in templates
+func (t EmailTemplates) withDefaults() EmailTemplates {
+	defaults := DefaultEmailTemplates()
+	fill := func(set *EmailTemplateSet, def EmailTemplateSet) {
+		if strings.TrimSpace(set.Subject) == "" {
+			set.Subject = def.Subject
+		}
+		if strings.TrimSpace(set.HTML) == "" {
+			set.HTML = def.HTML
+		}
+		if strings.TrimSpace(set.Text) == "" {
+			set.Text = def.Text
+		}
+	}
+	fill(&t.Single, defaults.Single)
+	fill(&t.Grouped, defaults.Grouped)
+	return t
+}
+
+// EmailTemplate generates the built-in HTML email template for alerts
 func EmailTemplate(alertList []*alerts.Alert, isSingle bool) (subject, htmlBody, textBody string) {
+	n := &NotificationManager{}
+	subject, htmlBody, textBody, err := n.RenderEmail(EmailTemplates{}, alertList, isSingle)
+	if err != nil {
+		// Built-in templates are covered by tests; this only happens on programmer error
+		log.Error().Err(err).Msg("Failed to render built-in email template")
+	}
+	return subject, htmlBody, textBody
+}
+
+// RenderEmail renders the subject, HTML and text bodies for the given alerts.
+// Empty templates fall back to the built-ins.
+func (n *NotificationManager) RenderEmail(templates EmailTemplates, alertList []*alerts.Alert, isSingle bool) (subject, htmlBody, textBody string, err error) {
+	if len(alertList) == 0 {
+		return "", "", "", fmt.Errorf("no alerts to render")
+	}
+
+	templates = templates.withDefaults()
+	set := templates.Grouped
 	if isSingle && len(alertList) == 1 {
-		return singleAlertTemplate(alertList[0])
+		set = templates.Single
+	}
+
+	data := n.buildEmailTemplateData(alertList)
+
+	subject, err = renderTextTemplate("email_subject", set.Subject, data)
+	if err != nil {
+		return "", "", "", fmt.Errorf("subject template: %w", err)
+	}
+	// Subjects end up in a mail header; never allow line breaks through
+	subject = strings.Join(strings.Fields(subject), " ")
+	if subject == "" {
+		return "", "", "", fmt.Errorf("subject template produced an empty subject")
+	}
+
+	htmlBody, err = renderHTMLTemplate("email_html", set.HTML, data)
+	if err != nil {
+		return "", "", "", fmt.Errorf("HTML template: %w", err)
+	}
+
+	textBody, err = renderTextTemplate("email_text", set.Text, data)
+	if err != nil {
+		return "", "", "", fmt.Errorf("text template: %w", err)
+	}
+
+	return subject, htmlBody, textBody, nil
+}
+
+// ValidateEmailTemplates renders the templates against sample alerts and
+// returns an error if any of them fails to parse or execute.
+func (n *NotificationManager) ValidateEmailTemplates(templates EmailTemplates) error {
+	samples := SampleEmailAlerts()
+	if _, _, _, err := n.RenderEmail(templates, samples[:1], true); err != nil {
+		return fmt.Errorf("single alert template: %w", err)
+	}
+	if _, _, _, err := n.RenderEmail(templates, samples, false); err != nil {
+		return fmt.Errorf("grouped alert template: %w", err)
+	}
+	return nil
+}
+
+// renderEmail renders an email with the configured templates, falling back to
+// the built-ins if the custom templates fail so alerts are never lost.
+func (n *NotificationManager) renderEmail(config EmailConfig, alertList []*alerts.Alert, isSingle bool) (subject, htmlBody, textBody string) {
+	subject, htmlBody, textBody, err := n.RenderEmail(config.Templates, alertList, isSingle)
+	if err == nil {
+		return subject, htmlBody, textBody
+	}
+
+	log.Warn().
+		Err(err).
+		Int("alertCount", len(alertList)).
+		Msg("Custom email template failed to render - using built-in template")
+	subject, htmlBody, textBody, err = n.RenderEmail(EmailTemplates{}, alertList, isSingle)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to render built-in email template")
+	}
+	return subject, htmlBody, textBody
+}
+
+// SampleEmailAlerts returns alerts used to preview and validate email templates
+func SampleEmailAlerts() []*alerts.Alert {
+	now := time.Now()
+	return []*alerts.Alert{
+		{
+			ID:           "sample-vm-101-cpu",
+			Type:         "cpu",
+			Level:        alerts.AlertLevelCritical,
+			ResourceID:   "pve1:node1:101",
+			ResourceName: "web-server",
+			Node:         "node1",
+			Instance:     "pve1",
+			Message:      "VM cpu at 95.5%",
+			Value:        95.5,
+			Threshold:    90,
+			StartTime:    now.Add(-12 * time.Minute),
+			LastSeen:     now,
+			Metadata: map[string]interface{}{
+				"resourceType": "vm",
+			},
+		},
+		{
+			ID:           "sample-ct-200-memory",
+			Type:         "memory",
+			Level:        alerts.AlertLevelWarning,
+			ResourceID:   "pve1:node2:200",
+			ResourceName: "database",
+			Node:         "node2",
+			Instance:     "pve1",
+			Message:      "Container memory at 86.0%",
+			Value:        86,
+			Threshold:    85,
+			StartTime:    now.Add(-3 * time.Minute),
+			LastSeen:     now,
+			Metadata: map[string]interface{}{
+				"resourceType": "container",
+			},
+		},
+	}
+}
+
+func (n *NotificationManager) buildEmailTemplateData(alertList []*alerts.Alert) EmailTemplateData {
+	data := EmailTemplateData{
+		Items: make([]WebhookPayloadData, 0, len(alertList)),
+	}
+	for _, alert := range alertList {
+		if alert == nil {
+			continue
+		}
+		data.Items = append(data.Items, n.prepareWebhookData(alert, nil))
+		if alert.Level == alerts.AlertLevelCritical {
+			data.CriticalCount++
+		} else {
+			data.WarningCount++
+		}
+	}
+	if len(data.Items) > 0 {
+		data.WebhookPayloadData = data.Items[0]
 	}
-	return groupedAlertTemplate(alertList)
+	data.AlertCount = len(data.Items)
+	data.Alerts = alertList
+	return data
 }
 
-func singleAlertTemplate(alert *alerts.Alert) (subject, htmlBody, textBody string) {
-	levelColor := "#ff6b6b"
-	levelBg := "#fee"
-	if alert.Level == "warning" {
-		levelColor = "#ffd93d"
-		levelBg = "#fffaeb"
+// emailTemplateFuncMap extends the webhook template helpers with email formatting helpers
+func emailTemplateFuncMap() template.FuncMap {
+	funcs := templateFuncMap()
+	funcs["levelColor"] = func(level string) string {
+		if level == string(alerts.AlertLevelWarning) {
+			return "#ffd93d"
+		}
+		return "#ff6b6b"
+	}
+	funcs["levelBackground"] = func(level string) string {
+		if level == string(alerts.AlertLevelWarning) {
+			return "#fffaeb"
+		}
+		return "#fee"
 	}
+	funcs["alertTypeLabel"] = alertTypeLabel
+	funcs["pluralize"] = pluralize
+	funcs["add1"] = func(i int) int { return i + 1 }
+	funcs["formatTime"] = func(timestamp string) string {
+		parsed, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return timestamp
+		}
+		return parsed.Format("Jan 2, 2006 at 3:04 PM")
+	}
+	return funcs
+}
 
-	// Properly format alert type (CPU, Memory, etc.)
-	alertType := alert.Type
+func renderTextTemplate(name, templateStr string, data EmailTemplateData) (string, error) {
+	tmpl, err := template.New(name).Funcs(emailTemplateFuncMap()).Parse(templateStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("template execution failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func renderHTMLTemplate(name, templateStr string, data EmailTemplateData) (string, error) {
+	tmpl, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(emailTemplateFuncMap())).Parse(templateStr)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("template execution failed: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// alertTypeLabel properly formats an alert type (CPU, Memory, etc.)
+func alertTypeLabel(alertType string) string {
 	switch strings.ToLower(alertType) {
 	case "cpu":
-		alertType = "CPU"
+		return "CPU"
 	case "memory":
-		alertType = "Memory"
+		return "Memory"
 	case "disk":
-		alertType = "Disk"
+		return "Disk"
 	case "io":
-		alertType = "I/O"
+		return "I/O"
 	default:
-		alertType = strings.Title(alertType)
+		return strings.Title(alertType)
+	}
+}
+
+func pluralize(count int) string {
+	if count == 1 {
+		return ""
 	}
+	return "s"
+}
 
-	subject = fmt.Sprintf("[Pulse Alert] %s: %s on %s",
-		strings.Title(string(alert.Level)), alertType, alert.ResourceName)
+// formatMetricValue formats a metric value with the appropriate unit
+func formatMetricValue(metricType string, value float64) string {
+	switch strings.ToLower(metricType) {
+	case "diskread", "diskwrite", "networkin", "networkout":
+		return fmt.Sprintf("%.1f MB/s", value)
+	case "temperature":
+		return fmt.Sprintf("%.1f°C", value)
+	case "cpu", "memory", "disk", "usage":
+		return fmt.Sprintf("%.1f%%", value)
+	default:
+		return fmt.Sprintf("%.1f", value)
+	}
+}
 
-	htmlBody = fmt.Sprintf(`<!DOCTYPE html>
+// formatMetricThreshold formats a metric threshold with the appropriate unit
+func formatMetricThreshold(metricType string, threshold float64) string {
+	switch strings.ToLower(metricType) {
+	case "diskread", "diskwrite", "networkin", "networkout":
+		return fmt.Sprintf("%.0f MB/s", threshold)
+	case "temperature":
+		return fmt.Sprintf("%.0f°C", threshold)
+	case "cpu", "memory", "disk", "usage":
+		return fmt.Sprintf("%.0f%%", threshold)
+	default:
+		return fmt.Sprintf("%.0f", threshold)
+	}
+}
+
+const defaultSingleSubjectTemplate = `[Pulse Alert] {{.Level | title}}: {{alertTypeLabel .Type}} on {{.ResourceName}}`
+
+const defaultSingleHTMLTemplate = `<!DOCTYPE html>
 <html>
 <head>
     <meta charset="UTF-8">
@@ -54,8 +339,8 @@ func singleAlertTemplate(alert *alerts.Alert) (subject, htmlBody, textBody strin
         .header h1 { margin: 0; font-size: 24px; font-weight: 500; }
         .pulse-logo { width: 40px; height: 40px; margin: 0 auto 10px; }
         .content { padding: 30px; }
-        .alert-box { background: %s; border-left: 4px solid %s; padding: 20px; margin: 20px 0; border-radius: 4px; }
-        .alert-level { color: %s; font-weight: bold; text-transform: uppercase; font-size: 14px; }
+        .alert-box { background: {{levelBackground .Level}}; border-left: 4px solid {{levelColor .Level}}; padding: 20px; margin: 20px 0; border-radius: 4px; }
+        .alert-level { color: {{levelColor .Level}}; font-weight: bold; text-transform: uppercase; font-size: 14px; }
         .alert-resource { font-size: 18px; font-weight: 500; margin: 10px 0; color: #1a1a1a; }
         .metrics { display: grid; grid-template-columns: 1fr 1fr; gap: 20px; margin: 20px 0; }
         .metric { background: #f8f9fa; padding: 15px; border-radius: 4px; }
@@ -78,53 +363,53 @@ func singleAlertTemplate(alert *alerts.Alert) (subject, htmlBody, textBody strin
     <div class="container">
         <div class="header">
             <svg class="pulse-logo" viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
-                <path d="M10 50 L30 50 L35 30 L40 70 L45 10 L50 90 L55 30 L60 70 L65 50 L90 50" 
+                <path d="M10 50 L30 50 L35 30 L40 70 L45 10 L50 90 L55 30 L60 70 L65 50 L90 50"
                       stroke="#4ade80" stroke-width="3" fill="none"/>
             </svg>
             <h1>Pulse Monitoring Alert</h1>
         </div>
         <div class="content">
             <div class="alert-box">
-                <div class="alert-level">%s Alert</div>
-                <div class="alert-resource">%s</div>
-                <div>%s</div>
+                <div class="alert-level">{{.Level}} Alert</div>
+                <div class="alert-resource">{{.ResourceName}}</div>
+                <div>{{.Message}}</div>
             </div>
-            
+
             <div class="metrics">
                 <div class="metric">
                     <div class="metric-label">Current Value</div>
-                    <div class="metric-value">%s</div>
+                    <div class="metric-value">{{.ValueFormatted}}</div>
                 </div>
                 <div class="metric">
                     <div class="metric-label">Threshold</div>
-                    <div class="metric-value">%s</div>
+                    <div class="metric-value">{{.ThresholdFormatted}}</div>
                 </div>
             </div>
-            
+
             <div class="details">
                 <div class="detail-row">
                     <span class="detail-label">Resource ID</span>
-                    <span class="detail-value">%s</span>
+                    <span class="detail-value">{{.ResourceID}}</span>
                 </div>
                 <div class="detail-row">
                     <span class="detail-label">Alert Type</span>
-                    <span class="detail-value">%s</span>
+                    <span class="detail-value">{{alertTypeLabel .Type}}</span>
                 </div>
                 <div class="detail-row">
                     <span class="detail-label">Node</span>
-                    <span class="detail-value">%s</span>
+                    <span class="detail-value">{{.Node}}</span>
                 </div>
                 <div class="detail-row">
                     <span class="detail-label">Instance</span>
-                    <span class="detail-value">%s</span>
+                    <span class="detail-value">{{.Instance}}</span>
                 </div>
                 <div class="detail-row">
                     <span class="detail-label">Started</span>
-                    <span class="detail-value">%s</span>
+                    <span class="detail-value">{{formatTime .StartTime}}</span>
                 </div>
                 <div class="detail-row">
                     <span class="detail-label">Duration</span>
-                    <span class="detail-value">%s</span>
+                    <span class="detail-value">{{.Duration}}</span>
                 </div>
             </div>
         </div>
@@ -134,116 +419,29 @@ func singleAlertTemplate(alert *alerts.Alert) (subject, htmlBody, textBody strin
         </div>
     </div>
 </body>
-</html>`,
-		levelBg, levelColor, levelColor,
-		alert.Level,
-		alert.ResourceName,
-		alert.Message,
-		formatMetricValue(alert.Type, alert.Value),
-		formatMetricThreshold(alert.Type, alert.Threshold),
-		alert.ResourceID,
-		alertType,
-		alert.Node,
-		alert.Instance,
-		alert.StartTime.Format("Jan 2, 2006 at 3:04 PM"),
-		formatDuration(time.Since(alert.StartTime)),
-	)
-
-	// Plain text version
-	textBody = fmt.Sprintf(`PULSE MONITORING ALERT
-
-%s ALERT: %s
-
-Resource: %s (%s)
-Type: %s
-Current Value: %s (Threshold: %s)
-Message: %s
+</html>`
 
-Details:
-- Node: %s  
-- Instance: %s
-- Started: %s
-- Duration: %s
+const defaultSingleTextTemplate = `PULSE MONITORING ALERT
 
-This is an automated notification from Pulse Monitoring.
-View alerts and configure settings in your Pulse dashboard.`,
-		strings.ToUpper(string(alert.Level)),
-		alert.ResourceName,
-		alert.ResourceName,
-		alert.ResourceID,
-		alert.Type,
-		formatMetricValue(alert.Type, alert.Value),
-		formatMetricThreshold(alert.Type, alert.Threshold),
-		alert.Message,
-		alert.Node,
-		alert.Instance,
-		alert.StartTime.Format("Jan 2, 2006 at 3:04 PM"),
-		formatDuration(time.Since(alert.StartTime)),
-	)
+{{.Level | upper}} ALERT: {{.ResourceName}}
 
-	return subject, htmlBody, textBody
-}
+Resource: {{.ResourceName}} ({{.ResourceID}})
+Type: {{.Type}}
+Current Value: {{.ValueFormatted}} (Threshold: {{.ThresholdFormatted}})
+Message: {{.Message}}
 
-func groupedAlertTemplate(alertList []*alerts.Alert) (subject, htmlBody, textBody string) {
-	critical := 0
-	warning := 0
-	for _, alert := range alertList {
-		if alert.Level == "critical" {
-			critical++
-		} else {
-			warning++
-		}
-	}
-
-	// Subject line
-	if critical > 0 && warning > 0 {
-		subject = fmt.Sprintf("[Pulse Alert] %d Critical, %d Warning alerts", critical, warning)
-	} else if critical > 0 {
-		subject = fmt.Sprintf("[Pulse Alert] %d Critical alert%s", critical, pluralize(critical))
-	} else {
-		subject = fmt.Sprintf("[Pulse Alert] %d Warning alert%s", warning, pluralize(warning))
-	}
+Details:
+- Node: {{.Node}}
+- Instance: {{.Instance}}
+- Started: {{formatTime .StartTime}}
+- Duration: {{.Duration}}
 
-	// Build alert rows
-	var alertRows strings.Builder
-	for _, alert := range alertList {
-		levelColor := "#ff6b6b"
-		if alert.Level == "warning" {
-			levelColor = "#ffd93d"
-		}
+This is an automated notification from Pulse Monitoring.
+View alerts and configure settings in your Pulse dashboard.`
 
-		alertRows.WriteString(fmt.Sprintf(`
-                <tr>
-                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef;">
-                        <div style="display: flex; align-items: center;">
-                            <span style="display: inline-block; width: 8px; height: 8px; background: %s; border-radius: 50%%; margin-right: 10px;"></span>
-                            <div>
-                                <div style="font-weight: 500; color: #1a1a1a;">%s</div>
-                                <div style="font-size: 12px; color: #666; margin-top: 2px;">%s on %s</div>
-                            </div>
-                        </div>
-                    </td>
-                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef; text-align: center;">
-                        <span style="color: %s; font-weight: 500; text-transform: uppercase; font-size: 12px;">%s</span>
-                    </td>
-                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef; text-align: right;">
-                        <div style="font-weight: 500;">%s</div>
-                        <div style="font-size: 12px; color: #666;">of %s</div>
-                    </td>
-                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef; text-align: right; color: #666; font-size: 12px;">
-                        %s ago
-                    </td>
-                </tr>`,
-			levelColor,
-			alert.ResourceName,
-			alert.Type, alert.Node,
-			levelColor, alert.Level,
-			formatMetricValue(alert.Type, alert.Value), formatMetricThreshold(alert.Type, alert.Threshold),
-			formatDuration(time.Since(alert.StartTime)),
-		))
-	}
+const defaultGroupedSubjectTemplate = `[Pulse Alert] {{if and .CriticalCount .WarningCount}}{{.CriticalCount}} Critical, {{.WarningCount}} Warning alerts{{else if .CriticalCount}}{{.CriticalCount}} Critical alert{{pluralize .CriticalCount}}{{else}}{{.WarningCount}} Warning alert{{pluralize .WarningCount}}{{end}}`
 
-	htmlBody = fmt.Sprintf(`<!DOCTYPE html>
+const defaultGroupedHTMLTemplate = `<!DOCTYPE html>
 <html>
 <head>
     <meta charset="UTF-8">
@@ -262,7 +460,7 @@ func groupedAlertTemplate(alertList []*alerts.Alert) (subject, htmlBody, textBod
         .critical-count { color: #ff6b6b; }
         .warning-count { color: #ffd93d; }
         .summary-label { color: #666; font-size: 14px; margin-top: 5px; }
-        .alerts-table { width: 100%%; margin-top: 20px; border-collapse: collapse; }
+        .alerts-table { width: 100%; margin-top: 20px; border-collapse: collapse; }
         .alerts-table th { text-align: left; padding: 12px; border-bottom: 2px solid #e9ecef; color: #666; font-weight: 500; font-size: 12px; text-transform: uppercase; letter-spacing: 0.5px; }
         .footer { background: #f8f9fa; padding: 20px; text-align: center; color: #666; font-size: 12px; }
         .footer a { color: #0066cc; text-decoration: none; }
@@ -276,37 +474,26 @@ func groupedAlertTemplate(alertList []*alerts.Alert) (subject, htmlBody, textBod
     <div class="container">
         <div class="header">
             <svg class="pulse-logo" viewBox="0 0 100 100" xmlns="http://www.w3.org/2000/svg">
-                <path d="M10 50 L30 50 L35 30 L40 70 L45 10 L50 90 L55 30 L60 70 L65 50 L90 50" 
+                <path d="M10 50 L30 50 L35 30 L40 70 L45 10 L50 90 L55 30 L60 70 L65 50 L90 50"
                       stroke="#4ade80" stroke-width="3" fill="none"/>
             </svg>
             <h1>Pulse Monitoring Alert Summary</h1>
         </div>
         <div class="content">
             <div class="summary">
-                <h2 style="margin: 0 0 15px 0; font-size: 18px;">%d New Alert%s</h2>
-                <div class="summary-grid">`,
-		len(alertList), pluralize(len(alertList)))
-
-	if critical > 0 {
-		htmlBody += fmt.Sprintf(`
+                <h2 style="margin: 0 0 15px 0; font-size: 18px;">{{.AlertCount}} New Alert{{pluralize .AlertCount}}</h2>
+                <div class="summary-grid">{{if .CriticalCount}}
                     <div class="summary-item">
-                        <div class="summary-count critical-count">%d</div>
+                        <div class="summary-count critical-count">{{.CriticalCount}}</div>
                         <div class="summary-label">Critical</div>
-                    </div>`, critical)
-	}
-
-	if warning > 0 {
-		htmlBody += fmt.Sprintf(`
+                    </div>{{end}}{{if .WarningCount}}
                     <div class="summary-item">
-                        <div class="summary-count warning-count">%d</div>
+                        <div class="summary-count warning-count">{{.WarningCount}}</div>
                         <div class="summary-label">Warning</div>
-                    </div>`, warning)
-	}
-
-	htmlBody += fmt.Sprintf(`
+                    </div>{{end}}
                 </div>
             </div>
-            
+
             <table class="alerts-table">
                 <thead>
                     <tr>
@@ -316,7 +503,28 @@ func groupedAlertTemplate(alertList []*alerts.Alert) (subject, htmlBody, textBod
                         <th style="text-align: right;">Duration</th>
                     </tr>
                 </thead>
-                <tbody>%s
+                <tbody>{{range .Items}}
+                <tr>
+                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef;">
+                        <div style="display: flex; align-items: center;">
+                            <span style="display: inline-block; width: 8px; height: 8px; background: {{levelColor .Level}}; border-radius: 50%; margin-right: 10px;"></span>
+                            <div>
+                                <div style="font-weight: 500; color: #1a1a1a;">{{.ResourceName}}</div>
+                                <div style="font-size: 12px; color: #666; margin-top: 2px;">{{.Type}} on {{.Node}}</div>
+                            </div>
+                        </div>
+                    </td>
+                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef; text-align: center;">
+                        <span style="color: {{levelColor .Level}}; font-weight: 500; text-transform: uppercase; font-size: 12px;">{{.Level}}</span>
+                    </td>
+                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef; text-align: right;">
+                        <div style="font-weight: 500;">{{.ValueFormatted}}</div>
+                        <div style="font-size: 12px; color: #666;">of {{.ThresholdFormatted}}</div>
+                    </td>
+                    <td style="padding: 12px; border-bottom: 1px solid #e9ecef; text-align: right; color: #666; font-size: 12px;">
+                        {{.Duration}} ago
+                    </td>
+                </tr>{{end}}
                 </tbody>
             </table>
         </div>
@@ -326,79 +534,23 @@ func groupedAlertTemplate(alertList []*alerts.Alert) (subject, htmlBody, textBod
         </div>
     </div>
 </body>
-</html>`, alertRows.String())
-
-	// Plain text version
-	var textBuilder strings.Builder
-	textBuilder.WriteString("PULSE MONITORING ALERT SUMMARY\n\n")
-	textBuilder.WriteString(fmt.Sprintf("%d New Alert%s\n", len(alertList), pluralize(len(alertList))))
-	if critical > 0 {
-		textBuilder.WriteString(fmt.Sprintf("Critical: %d\n", critical))
-	}
-	if warning > 0 {
-		textBuilder.WriteString(fmt.Sprintf("Warning: %d\n", warning))
-	}
-	textBuilder.WriteString("\nAlert Details:\n")
-	textBuilder.WriteString("─────────────────────────────────────────────────────────────\n")
-
-	for i, alert := range alertList {
-		textBuilder.WriteString(fmt.Sprintf("\n%d. %s (%s)\n", i+1, alert.ResourceName, alert.ResourceID))
-		textBuilder.WriteString(fmt.Sprintf("   Level: %s | Type: %s\n", strings.ToUpper(string(alert.Level)), alert.Type))
-		textBuilder.WriteString(fmt.Sprintf("   Value: %s (Threshold: %s)\n", formatMetricValue(alert.Type, alert.Value), formatMetricThreshold(alert.Type, alert.Threshold)))
-		textBuilder.WriteString(fmt.Sprintf("   Node: %s | Started: %s ago\n", alert.Node, formatDuration(time.Since(alert.StartTime))))
-		textBuilder.WriteString(fmt.Sprintf("   Message: %s\n", alert.Message))
-	}
-
-	textBuilder.WriteString("\n─────────────────────────────────────────────────────────────\n")
-	textBuilder.WriteString("This is an automated notification from Pulse Monitoring.\n")
-	textBuilder.WriteString("Configure alert settings in the Pulse dashboard.")
+</html>`
 
-	textBody = textBuilder.String()
-	return subject, htmlBody, textBody
-}
+const defaultGroupedTextTemplate = `PULSE MONITORING ALERT SUMMARY
 
-func formatDuration(d time.Duration) string {
-	if d < time.Minute {
-		return fmt.Sprintf("%d seconds", int(d.Seconds()))
-	} else if d < time.Hour {
-		return fmt.Sprintf("%d minutes", int(d.Minutes()))
-	} else if d < 24*time.Hour {
-		return fmt.Sprintf("%.1f hours", d.Hours())
-	}
-	return fmt.Sprintf("%.1f days", d.Hours()/24)
-}
-
-func pluralize(count int) string {
-	if count == 1 {
-		return ""
-	}
-	return "s"
-}
-
-// formatMetricValue formats a metric value with the appropriate unit
-func formatMetricValue(metricType string, value float64) string {
-	switch strings.ToLower(metricType) {
-	case "diskread", "diskwrite", "networkin", "networkout":
-		return fmt.Sprintf("%.1f MB/s", value)
-	case "temperature":
-		return fmt.Sprintf("%.1f°C", value)
-	case "cpu", "memory", "disk", "usage":
-		return fmt.Sprintf("%.1f%%", value)
-	default:
-		return fmt.Sprintf("%.1f", value)
-	}
-}
-
-// formatMetricThreshold formats a metric threshold with the appropriate unit
-func formatMetricThreshold(metricType string, threshold float64) string {
-	switch strings.ToLower(metricType) {
-	case "diskread", "diskwrite", "networkin", "networkout":
-		return fmt.Sprintf("%.0f MB/s", threshold)
-	case "temperature":
-		return fmt.Sprintf("%.0f°C", threshold)
-	case "cpu", "memory", "disk", "usage":
-		return fmt.Sprintf("%.0f%%", threshold)
-	default:
-		return fmt.Sprintf("%.0f", threshold)
-	}
-}
+{{.AlertCount}} New Alert{{pluralize .AlertCount}}
+{{if .CriticalCount}}Critical: {{.CriticalCount}}
+{{end}}{{if .WarningCount}}Warning: {{.WarningCount}}
+{{end}}
+Alert Details:
+─────────────────────────────────────────────────────────────
+{{range $i, $alert := .Items}}
+{{add1 $i}}. {{$alert.ResourceName}} ({{$alert.ResourceID}})
+   Level: {{$alert.Level | upper}} | Type: {{$alert.Type}}
+   Value: {{$alert.ValueFormatted}} (Threshold: {{$alert.ThresholdFormatted}})
+   Node: {{$alert.Node}} | Started: {{$alert.Duration}} ago
+   Message: {{$alert.Message}}
+{{end}}
+─────────────────────────────────────────────────────────────
+This is an automated notification from Pulse Monitoring.
+Configure alert settings in the Pulse dashboard.`
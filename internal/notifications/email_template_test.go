@@ -0,0 +1,103 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+)
+
+func TestRenderEmailBuiltInTemplates(t *testing.T) {
+	n := NewNotificationManager("")
+	samples := SampleEmailAlerts()
+
+	subject, htmlBody, textBody, err := n.RenderEmail(EmailTemplates{}, samples[:1], true)
+	if err != nil {
+		t.Fatalf("expected built-in single template to render, got %v", err)
+	}
+	if subject != "[Pulse Alert] Critical: CPU on web-server" {
+		t.Fatalf("unexpected single subject %q", subject)
+	}
+	if !strings.Contains(htmlBody, "web-server") || !strings.Contains(htmlBody, "#ff6b6b") {
+		t.Fatalf("expected HTML body to contain resource name and level color")
+	}
+	if !strings.Contains(textBody, "CRITICAL ALERT: web-server") {
+		t.Fatalf("unexpected text body:\n%s", textBody)
+	}
+
+	subject, htmlBody, textBody, err = n.RenderEmail(EmailTemplates{}, samples, false)
+	if err != nil {
+		t.Fatalf("expected built-in grouped template to render, got %v", err)
+	}
+	if subject != "[Pulse Alert] 1 Critical, 1 Warning alerts" {
+		t.Fatalf("unexpected grouped subject %q", subject)
+	}
+	if !strings.Contains(htmlBody, "database") || !strings.Contains(htmlBody, "2 New Alerts") {
+		t.Fatalf("expected grouped HTML body to list every alert")
+	}
+	if !strings.Contains(textBody, "2. database (pve1:node2:200)") {
+		t.Fatalf("unexpected grouped text body:\n%s", textBody)
+	}
+}
+
+func TestRenderEmailCustomTemplates(t *testing.T) {
+	n := NewNotificationManager("https://pulse.example.com")
+	alert := SampleEmailAlerts()[0]
+	alert.Message = "<script>alert(1)</script>"
+
+	templates := EmailTemplates{
+		Single: EmailTemplateSet{
+			Subject: "{{.Level | upper}}\r\nBcc: victim@example.com {{.ResourceName}}",
+			HTML:    "<p>{{.Message}}</p><a href=\"{{.Instance}}\">open</a>",
+		},
+	}
+
+	subject, htmlBody, textBody, err := n.RenderEmail(templates, []*alerts.Alert{alert}, true)
+	if err != nil {
+		t.Fatalf("expected custom template to render, got %v", err)
+	}
+	if strings.ContainsAny(subject, "\r\n") {
+		t.Fatalf("expected line breaks to be stripped from subject, got %q", subject)
+	}
+	if strings.Contains(htmlBody, "<script>") {
+		t.Fatalf("expected HTML template to escape alert fields, got %s", htmlBody)
+	}
+	if !strings.Contains(htmlBody, "https://pulse.example.com") {
+		t.Fatalf("expected HTML template to include public URL, got %s", htmlBody)
+	}
+	if !strings.Contains(textBody, "PULSE MONITORING ALERT") {
+		t.Fatalf("expected empty text template to fall back to built-in")
+	}
+}
+
+func TestValidateEmailTemplates(t *testing.T) {
+	n := NewNotificationManager("")
+
+	if err := n.ValidateEmailTemplates(DefaultEmailTemplates()); err != nil {
+		t.Fatalf("expected built-in templates to validate, got %v", err)
+	}
+
+	cases := map[string]EmailTemplates{
+		"parse error":     {Single: EmailTemplateSet{Subject: "{{.Level"}},
+		"unknown field":   {Grouped: EmailTemplateSet{Text: "{{.DoesNotExist}}"}},
+		"empty subject":   {Single: EmailTemplateSet{Subject: "{{if false}}x{{end}}"}},
+		"bad html action": {Grouped: EmailTemplateSet{HTML: "{{range .Items}}{{.Nope}}{{end}}"}},
+	}
+	for name, templates := range cases {
+		if err := n.ValidateEmailTemplates(templates); err == nil {
+			t.Errorf("%s: expected validation error, got nil", name)
+		}
+	}
+}
+
+func TestRenderEmailFallsBackToBuiltIn(t *testing.T) {
+	n := NewNotificationManager("")
+	config := EmailConfig{
+		Templates: EmailTemplates{Single: EmailTemplateSet{Subject: "{{.Missing}}"}},
+	}
+
+	subject, _, _ := n.renderEmail(config, SampleEmailAlerts()[:1], true)
+	if subject != "[Pulse Alert] Critical: CPU on web-server" {
+		t.Fatalf("expected fallback to built-in subject, got %q", subject)
+	}
+}
@@ -0,0 +1,66 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/rs/zerolog/log"
+)
+
+// SendEscalation notifies an escalation target directly, bypassing grouping and cooldown.
+// Channels referenced by a target are used even when they are disabled for regular
+// alerts, so a channel can be reserved for escalations.
+func (n *NotificationManager) SendEscalation(ctx context.Context, alert *alerts.Alert, target alerts.EscalationTarget) {
+	if alert == nil {
+		return
+	}
+
+	n.mu.RLock()
+	enabled := n.enabled
+	emailConfig := copyEmailConfig(n.emailConfig)
+	webhooks := copyWebhookConfigs(n.webhooks)
+	n.mu.RUnlock()
+
+	if !enabled {
+		log.Debug().Ctx(ctx).Str("alertID", alert.ID).Msg("Notifications disabled, skipping escalation")
+		return
+	}
+
+	log.Info().Ctx(ctx).
+		Str("alertID", alert.ID).
+		Str("target", target.Name).
+		Int("emails", len(target.Emails)).
+		Int("webhooks", len(target.Webhooks)).
+		Msg("Sending escalation notification")
+
+	if len(target.Emails) > 0 {
+		if emailConfig.SMTPHost == "" || emailConfig.From == "" {
+			log.Warn().Ctx(ctx).
+				Str("alertID", alert.ID).
+				Str("target", target.Name).
+				Msg("Escalation target has email recipients but SMTP is not configured")
+		} else {
+			emailConfig.To = append([]string(nil), target.Emails...)
+			subject, htmlBody, textBody := n.renderEmail(emailConfig, []*alerts.Alert{alert}, true)
+			deliver(ctx, "email", 1, func(ctx context.Context) { n.sendHTMLEmail(ctx, subject, htmlBody, textBody, emailConfig) })
+		}
+	}
+
+	for _, webhookID := range target.Webhooks {
+		found := false
+		for _, webhook := range webhooks {
+			if webhook.ID == webhookID {
+				found = true
+				deliver(ctx, "webhook", 1, func(ctx context.Context) { n.sendWebhook(ctx, webhook, alert) })
+				break
+			}
+		}
+		if !found {
+			log.Warn().Ctx(ctx).
+				Str("alertID", alert.ID).
+				Str("target", target.Name).
+				Str("webhookID", webhookID).
+				Msg("Escalation target references unknown webhook")
+		}
+	}
+}
@@ -16,7 +16,9 @@ import (
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/tracing"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Webhook configuration constants
@@ -107,20 +109,29 @@ type NotificationManager struct {
 	emailConfig       EmailConfig
 	webhooks          []WebhookConfig
 	appriseConfig     AppriseConfig
+	syslogConfig      SyslogConfig
+	snmpConfig        SNMPConfig
+	snmpEngineBoots   uint32    // SNMPv3 snmpEngineBoots, persisted by the caller
+	snmpEngineStart   time.Time // snmpEngineTime counts from here
 	enabled           bool
 	cooldown          time.Duration
 	lastNotified      map[string]notificationRecord
 	groupWindow       time.Duration
 	pendingAlerts     []*alerts.Alert
 	groupTimer        *time.Timer
+	groupCtx          context.Context // Trace context of the alert that opened the pending group
 	groupByNode       bool
 	publicURL         string // Full URL to access Pulse
 	groupByGuest      bool
 	webhookHistory    []WebhookDelivery            // Keep last 100 webhook deliveries for debugging
 	webhookRateLimits map[string]*webhookRateLimit // Track rate limits per webhook URL
 	appriseExec       appriseExecFunc
+	secretResolver    SecretResolver
 }
 
+// SecretResolver resolves a credential reference such as vault://mount/path#key to its value
+type SecretResolver func(value, field string) (string, error)
+
 type appriseExecFunc func(ctx context.Context, path string, args []string) ([]byte, error)
 
 // copyEmailConfig returns a defensive copy of EmailConfig including its slices to avoid data races.
@@ -271,6 +282,8 @@ type EmailConfig struct {
 	To       []string `json:"to"`
 	TLS      bool     `json:"tls"`
 	StartTLS bool     `json:"startTLS"` // STARTTLS support
+
+	Templates EmailTemplates `json:"templates"` // Custom subject/body templates; empty fields use the built-ins
 }
 
 // WebhookConfig holds webhook settings
@@ -329,6 +342,10 @@ func NewNotificationManager(publicURL string) *NotificationManager {
 			TimeoutSeconds: 15,
 			APIKeyHeader:   "X-API-KEY",
 		},
+		syslogConfig:      NormalizeSyslogConfig(SyslogConfig{}),
+		snmpConfig:        NormalizeSNMPConfig(SNMPConfig{}),
+		snmpEngineBoots:   1,
+		snmpEngineStart:   time.Now(),
 		groupWindow:       30 * time.Second,
 		pendingAlerts:     make([]*alerts.Alert, 0),
 		groupByNode:       true,
@@ -372,6 +389,24 @@ func (n *NotificationManager) SetEmailConfig(config EmailConfig) {
 	n.emailConfig = config
 }
 
+// SetSecretResolver sets the resolver used for credential references in the SMTP password
+func (n *NotificationManager) SetSecretResolver(resolver SecretResolver) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.secretResolver = resolver
+}
+
+// resolveEmailPassword returns the SMTP password with any credential reference resolved
+func (n *NotificationManager) resolveEmailPassword(config EmailConfig) (string, error) {
+	n.mu.RLock()
+	resolver := n.secretResolver
+	n.mu.RUnlock()
+	if resolver == nil || config.Password == "" {
+		return config.Password, nil
+	}
+	return resolver(config.Password, "email.password")
+}
+
 // SetAppriseConfig updates Apprise configuration.
 func (n *NotificationManager) SetAppriseConfig(config AppriseConfig) {
 	n.mu.Lock()
@@ -467,12 +502,13 @@ func (n *NotificationManager) GetEmailConfig() EmailConfig {
 	return n.emailConfig
 }
 
-// SendAlert sends notifications for an alert
-func (n *NotificationManager) SendAlert(alert *alerts.Alert) {
+// SendAlert sends notifications for an alert. The notifications of a group are traced as
+// children of the span in ctx of the alert that opened the group.
+func (n *NotificationManager) SendAlert(ctx context.Context, alert *alerts.Alert) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("alertID", alert.ID).
 		Bool("enabled", n.enabled).
 		Int("webhooks", len(n.webhooks)).
@@ -480,14 +516,14 @@ func (n *NotificationManager) SendAlert(alert *alerts.Alert) {
 		Msg("SendAlert called")
 
 	if !n.enabled {
-		log.Debug().Msg("Notifications disabled, skipping")
+		log.Debug().Ctx(ctx).Msg("Notifications disabled, skipping")
 		return
 	}
 
 	// Check cooldown
 	record, exists := n.lastNotified[alert.ID]
 	if exists && record.alertStart.Equal(alert.StartTime) && time.Since(record.lastSent) < n.cooldown {
-		log.Info().
+		log.Info().Ctx(ctx).
 			Str("alertID", alert.ID).
 			Str("resourceName", alert.ResourceName).
 			Str("type", alert.Type).
@@ -498,7 +534,7 @@ func (n *NotificationManager) SendAlert(alert *alerts.Alert) {
 		return
 	}
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("alertID", alert.ID).
 		Str("resourceName", alert.ResourceName).
 		Str("type", alert.Type).
@@ -512,10 +548,11 @@ func (n *NotificationManager) SendAlert(alert *alerts.Alert) {
 
 	// If this is the first alert in the group, start the timer
 	if n.groupTimer == nil {
+		n.groupCtx = ctx
 		n.groupTimer = time.AfterFunc(n.groupWindow, func() {
 			n.sendGroupedAlerts()
 		})
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Int("pendingCount", len(n.pendingAlerts)).
 			Dur("groupWindow", n.groupWindow).
 			Msg("Started alert grouping timer")
@@ -559,6 +596,7 @@ func (n *NotificationManager) CancelAlert(alertID string) {
 			log.Debug().Str("alertID", alertID).Msg("Stopped grouping timer after alert cancellation")
 		}
 		n.groupTimer = nil
+		n.groupCtx = nil
 	}
 
 	log.Debug().
@@ -567,6 +605,33 @@ func (n *NotificationManager) CancelAlert(alertID string) {
 		Msg("Removed resolved alert from pending notifications")
 }
 
+// SendResolved notifies event-stream channels (syslog and SNMP) that an alert resolved.
+// Resolve events are only sent for alerts whose firing notification was delivered.
+func (n *NotificationManager) SendResolved(ctx context.Context, alert *alerts.Alert) {
+	if alert == nil {
+		return
+	}
+
+	n.mu.RLock()
+	enabled := n.enabled
+	record, notified := n.lastNotified[alert.ID]
+	syslogConfig := n.syslogConfig
+	snmpConfig := n.snmpConfig
+	n.mu.RUnlock()
+
+	if !enabled || !notified || !record.alertStart.Equal(alert.StartTime) {
+		return
+	}
+
+	resolved := []*alerts.Alert{alert}
+	if syslogConfig.Enabled {
+		deliver(ctx, "syslog", len(resolved), func(ctx context.Context) { n.sendSyslogAlerts(ctx, syslogConfig, resolved, AlertEventResolved) })
+	}
+	if snmpConfig.Enabled {
+		deliver(ctx, "snmp", len(resolved), func(ctx context.Context) { n.sendSNMPAlerts(ctx, snmpConfig, resolved, AlertEventResolved) })
+	}
+}
+
 // sendGroupedAlerts sends all pending alerts as a group
 func (n *NotificationManager) sendGroupedAlerts() {
 	n.mu.Lock()
@@ -583,6 +648,11 @@ func (n *NotificationManager) sendGroupedAlerts() {
 	// Clear pending alerts
 	n.pendingAlerts = n.pendingAlerts[:0]
 	n.groupTimer = nil
+	ctx := n.groupCtx
+	n.groupCtx = nil
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
 	log.Info().
 		Int("alertCount", len(alertsToSend)).
@@ -592,6 +662,8 @@ func (n *NotificationManager) sendGroupedAlerts() {
 	emailConfig := copyEmailConfig(n.emailConfig)
 	webhooks := copyWebhookConfigs(n.webhooks)
 	appriseConfig := copyAppriseConfig(n.appriseConfig)
+	syslogConfig := n.syslogConfig
+	snmpConfig := n.snmpConfig
 
 	// Send notifications using the captured snapshots outside the lock to avoid blocking writers
 	if emailConfig.Enabled {
@@ -602,7 +674,7 @@ func (n *NotificationManager) sendGroupedAlerts() {
 			Strs("recipients", emailConfig.To).
 			Bool("hasAuth", emailConfig.Username != "" && emailConfig.Password != "").
 			Msg("Email notifications enabled - sending grouped email")
-		go n.sendGroupedEmail(emailConfig, alertsToSend)
+		deliver(ctx, "email", len(alertsToSend), func(ctx context.Context) { n.sendGroupedEmail(ctx, emailConfig, alertsToSend) })
 	} else {
 		log.Debug().
 			Int("alertCount", len(alertsToSend)).
@@ -611,12 +683,20 @@ func (n *NotificationManager) sendGroupedAlerts() {
 
 	for _, webhook := range webhooks {
 		if webhook.Enabled {
-			go n.sendGroupedWebhook(webhook, alertsToSend)
+			deliver(ctx, "webhook", len(alertsToSend), func(ctx context.Context) { n.sendGroupedWebhook(ctx, webhook, alertsToSend) })
 		}
 	}
 
 	if appriseConfig.Enabled {
-		go n.sendGroupedApprise(appriseConfig, alertsToSend)
+		deliver(ctx, "apprise", len(alertsToSend), func(ctx context.Context) { n.sendGroupedApprise(ctx, appriseConfig, alertsToSend) })
+	}
+
+	if syslogConfig.Enabled {
+		deliver(ctx, "syslog", len(alertsToSend), func(ctx context.Context) { n.sendSyslogAlerts(ctx, syslogConfig, alertsToSend, AlertEventFired) })
+	}
+
+	if snmpConfig.Enabled {
+		deliver(ctx, "snmp", len(alertsToSend), func(ctx context.Context) { n.sendSNMPAlerts(ctx, snmpConfig, alertsToSend, AlertEventFired) })
 	}
 
 	// Update last notified time for all alerts
@@ -629,20 +709,30 @@ func (n *NotificationManager) sendGroupedAlerts() {
 	}
 }
 
+// deliver sends to one channel in the background, traced as a child span of ctx so slow SMTP
+// servers and webhooks show up in the trace of the poll that raised the alerts
+func deliver(ctx context.Context, channel string, alertCount int, send func(ctx context.Context)) {
+	go func() {
+		ctx, span := tracing.Start(ctx, "notify "+channel, attribute.Int("pulse.alert_count", alertCount))
+		defer span.End()
+		send(ctx)
+	}()
+}
+
 // sendGroupedEmail sends a grouped email notification
-func (n *NotificationManager) sendGroupedEmail(config EmailConfig, alertList []*alerts.Alert) {
+func (n *NotificationManager) sendGroupedEmail(ctx context.Context, config EmailConfig, alertList []*alerts.Alert) {
 
 	// Don't check for recipients here - sendHTMLEmail handles empty recipients
 	// by using the From address as the recipient
 
-	// Generate email using template
-	subject, htmlBody, textBody := EmailTemplate(alertList, false)
+	// Generate email using configured templates
+	subject, htmlBody, textBody := n.renderEmail(config, alertList, false)
 
 	// Send using HTML-aware method
-	n.sendHTMLEmail(subject, htmlBody, textBody, config)
+	n.sendHTMLEmail(ctx, subject, htmlBody, textBody, config)
 }
 
-func (n *NotificationManager) sendGroupedApprise(config AppriseConfig, alertList []*alerts.Alert) {
+func (n *NotificationManager) sendGroupedApprise(ctx context.Context, config AppriseConfig, alertList []*alerts.Alert) {
 	if len(alertList) == 0 {
 		return
 	}
@@ -654,22 +744,22 @@ func (n *NotificationManager) sendGroupedApprise(config AppriseConfig, alertList
 
 	title, body, notifyType := buildApprisePayload(alertList, n.publicURL)
 	if title == "" && body == "" {
-		log.Warn().Msg("Apprise notification skipped: failed to build payload")
+		log.Warn().Ctx(ctx).Msg("Apprise notification skipped: failed to build payload")
 		return
 	}
 
 	switch cfg.Mode {
 	case AppriseModeHTTP:
-		if err := n.sendAppriseViaHTTP(cfg, title, body, notifyType); err != nil {
-			log.Warn().
+		if err := n.sendAppriseViaHTTP(ctx, cfg, title, body, notifyType); err != nil {
+			log.Warn().Ctx(ctx).
 				Err(err).
 				Str("mode", string(cfg.Mode)).
 				Str("serverUrl", cfg.ServerURL).
 				Msg("Failed to send Apprise notification via API")
 		}
 	default:
-		if err := n.sendAppriseViaCLI(cfg, title, body); err != nil {
-			log.Warn().
+		if err := n.sendAppriseViaCLI(ctx, cfg, title, body); err != nil {
+			log.Warn().Ctx(ctx).
 				Err(err).
 				Str("mode", string(cfg.Mode)).
 				Str("cliPath", cfg.CLIPath).
@@ -740,12 +830,12 @@ func resolveAppriseNotificationType(alertList []*alerts.Alert) string {
 	return notifyType
 }
 
-func (n *NotificationManager) sendAppriseViaCLI(cfg AppriseConfig, title, body string) error {
+func (n *NotificationManager) sendAppriseViaCLI(ctx context.Context, cfg AppriseConfig, title, body string) error {
 	if len(cfg.Targets) == 0 {
 		return fmt.Errorf("no Apprise targets configured for CLI delivery")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSeconds)*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.TimeoutSeconds)*time.Second)
 	defer cancel()
 
 	args := []string{"-t", title, "-b", body}
@@ -759,7 +849,7 @@ func (n *NotificationManager) sendAppriseViaCLI(cfg AppriseConfig, title, body s
 	output, err := execFn(ctx, cfg.CLIPath, args)
 	if err != nil {
 		if len(output) > 0 {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("cliPath", cfg.CLIPath).
 				Strs("targets", cfg.Targets).
 				Str("output", string(output)).
@@ -769,7 +859,7 @@ func (n *NotificationManager) sendAppriseViaCLI(cfg AppriseConfig, title, body s
 	}
 
 	if len(output) > 0 {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("cliPath", cfg.CLIPath).
 			Strs("targets", cfg.Targets).
 			Str("output", string(output)).
@@ -778,7 +868,7 @@ func (n *NotificationManager) sendAppriseViaCLI(cfg AppriseConfig, title, body s
 	return nil
 }
 
-func (n *NotificationManager) sendAppriseViaHTTP(cfg AppriseConfig, title, body, notifyType string) error {
+func (n *NotificationManager) sendAppriseViaHTTP(ctx context.Context, cfg AppriseConfig, title, body, notifyType string) error {
 	if cfg.ServerURL == "" {
 		return fmt.Errorf("apprise server URL is not configured")
 	}
@@ -789,7 +879,7 @@ func (n *NotificationManager) sendAppriseViaHTTP(cfg AppriseConfig, title, body,
 		return fmt.Errorf("apprise server URL must start with http or https: %s", serverURL)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutSeconds)*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cfg.TimeoutSeconds)*time.Second)
 	defer cancel()
 
 	notifyEndpoint := "/notify"
@@ -858,7 +948,7 @@ func (n *NotificationManager) sendAppriseViaHTTP(cfg AppriseConfig, title, body,
 	}
 
 	if len(respBody) > 0 {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("mode", string(cfg.Mode)).
 			Str("serverUrl", cfg.ServerURL).
 			Str("response", string(respBody)).
@@ -877,11 +967,11 @@ func (n *NotificationManager) sendEmail(alert *alerts.Alert) {
 	// Don't check for recipients here - sendHTMLEmail handles empty recipients
 	// by using the From address as the recipient
 
-	// Generate email using template
-	subject, htmlBody, textBody := EmailTemplate([]*alerts.Alert{alert}, true)
+	// Generate email using configured templates
+	subject, htmlBody, textBody := n.renderEmail(config, []*alerts.Alert{alert}, true)
 
 	// Send using HTML-aware method
-	n.sendHTMLEmail(subject, htmlBody, textBody, config)
+	n.sendHTMLEmail(context.Background(), subject, htmlBody, textBody, config)
 }
 
 // sendHTMLEmailWithError sends an HTML email with multipart content and returns any error
@@ -895,6 +985,11 @@ func (n *NotificationManager) sendHTMLEmailWithError(subject, htmlBody, textBody
 			Msg("Using From address as recipient since To is empty")
 	}
 
+	password, err := n.resolveEmailPassword(config)
+	if err != nil {
+		return fmt.Errorf("failed to resolve SMTP password: %w", err)
+	}
+
 	// Create enhanced email configuration with proper STARTTLS support
 	enhancedConfig := EmailProviderConfig{
 		EmailConfig: EmailConfig{
@@ -903,7 +998,7 @@ func (n *NotificationManager) sendHTMLEmailWithError(subject, htmlBody, textBody
 			SMTPHost: config.SMTPHost,
 			SMTPPort: config.SMTPPort,
 			Username: config.Username,
-			Password: config.Password,
+			Password: password,
 		},
 		Provider:      config.Provider,
 		StartTLS:      config.StartTLS, // Use the configured StartTLS setting
@@ -925,7 +1020,7 @@ func (n *NotificationManager) sendHTMLEmailWithError(subject, htmlBody, textBody
 		Bool("startTLS", enhancedConfig.StartTLS).
 		Msg("Attempting to send email via SMTP with enhanced support")
 
-	err := enhancedManager.SendEmailWithRetry(subject, htmlBody, textBody)
+	err = enhancedManager.SendEmailWithRetry(subject, htmlBody, textBody)
 
 	if err != nil {
 		log.Error().
@@ -944,16 +1039,22 @@ func (n *NotificationManager) sendHTMLEmailWithError(subject, htmlBody, textBody
 }
 
 // sendHTMLEmail sends an HTML email with multipart content
-func (n *NotificationManager) sendHTMLEmail(subject, htmlBody, textBody string, config EmailConfig) {
+func (n *NotificationManager) sendHTMLEmail(ctx context.Context, subject, htmlBody, textBody string, config EmailConfig) {
 	// Use From address as recipient if To is empty
 	recipients := config.To
 	if len(recipients) == 0 && config.From != "" {
 		recipients = []string{config.From}
-		log.Info().
+		log.Info().Ctx(ctx).
 			Str("from", config.From).
 			Msg("Using From address as recipient since To is empty")
 	}
 
+	password, err := n.resolveEmailPassword(config)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).Msg("Failed to resolve SMTP password")
+		return
+	}
+
 	// Create enhanced email configuration with proper STARTTLS support
 	enhancedConfig := EmailProviderConfig{
 		EmailConfig: EmailConfig{
@@ -962,7 +1063,7 @@ func (n *NotificationManager) sendHTMLEmail(subject, htmlBody, textBody string,
 			SMTPHost: config.SMTPHost,
 			SMTPPort: config.SMTPPort,
 			Username: config.Username,
-			Password: config.Password,
+			Password: password,
 		},
 		Provider:      config.Provider,
 		StartTLS:      config.StartTLS, // Use the configured StartTLS setting
@@ -976,7 +1077,7 @@ func (n *NotificationManager) sendHTMLEmail(subject, htmlBody, textBody string,
 	// Use enhanced email manager for better compatibility
 	enhancedManager := NewEnhancedEmailManager(enhancedConfig)
 
-	log.Info().
+	log.Info().Ctx(ctx).
 		Str("smtp", fmt.Sprintf("%s:%d", config.SMTPHost, config.SMTPPort)).
 		Str("from", config.From).
 		Strs("to", recipients).
@@ -984,16 +1085,16 @@ func (n *NotificationManager) sendHTMLEmail(subject, htmlBody, textBody string,
 		Bool("startTLS", enhancedConfig.StartTLS).
 		Msg("Attempting to send email via SMTP with enhanced support")
 
-	err := enhancedManager.SendEmailWithRetry(subject, htmlBody, textBody)
+	err = enhancedManager.SendEmailWithRetry(subject, htmlBody, textBody)
 
 	if err != nil {
-		log.Error().
+		log.Error().Ctx(ctx).
 			Err(err).
 			Str("smtp", fmt.Sprintf("%s:%d", config.SMTPHost, config.SMTPPort)).
 			Strs("recipients", recipients).
 			Msg("Failed to send email notification")
 	} else {
-		log.Info().
+		log.Info().Ctx(ctx).
 			Strs("recipients", recipients).
 			Int("recipientCount", len(recipients)).
 			Msg("Email notification sent successfully")
@@ -1003,16 +1104,16 @@ func (n *NotificationManager) sendHTMLEmail(subject, htmlBody, textBody string,
 // sendEmailWithContent sends email with given content (plain text)
 func (n *NotificationManager) sendEmailWithContent(subject, body string, config EmailConfig) {
 	// For backward compatibility, send as plain text
-	n.sendHTMLEmail(subject, "", body, config)
+	n.sendHTMLEmail(context.Background(), subject, "", body, config)
 }
 
 // sendGroupedWebhook sends a grouped webhook notification
-func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertList []*alerts.Alert) {
+func (n *NotificationManager) sendGroupedWebhook(ctx context.Context, webhook WebhookConfig, alertList []*alerts.Alert) {
 	var jsonData []byte
 	var err error
 
 	if len(alertList) == 0 {
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Str("webhook", webhook.Name).
 			Msg("Attempted to send grouped webhook with no alerts")
 		return
@@ -1043,7 +1144,7 @@ func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertLis
 		if !urlRendered {
 			rendered, renderErr := renderWebhookURL(webhook.URL, *dataPtr)
 			if renderErr != nil {
-				log.Error().
+				log.Error().Ctx(ctx).
 					Err(renderErr).
 					Str("webhook", webhook.Name).
 					Msg("Failed to render webhook URL template for grouped notification")
@@ -1058,7 +1159,7 @@ func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertLis
 			case "telegram":
 				chatID, chatErr := extractTelegramChatID(webhook.URL)
 				if chatErr != nil {
-					log.Error().
+					log.Error().Ctx(ctx).
 						Err(chatErr).
 						Str("webhook", webhook.Name).
 						Msg("Failed to extract Telegram chat_id for grouped notification")
@@ -1066,7 +1167,7 @@ func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertLis
 				}
 				if chatID != "" {
 					dataPtr.ChatID = chatID
-					log.Debug().
+					log.Debug().Ctx(ctx).
 						Str("webhook", webhook.Name).
 						Str("chatID", chatID).
 						Msg("Extracted Telegram chat_id from rendered URL for grouped notification")
@@ -1119,7 +1220,7 @@ func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertLis
 			return
 		}
 		if err != nil {
-			log.Error().
+			log.Error().Ctx(ctx).
 				Err(err).
 				Str("webhook", webhook.Name).
 				Int("alertCount", len(alertList)).
@@ -1176,7 +1277,7 @@ func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertLis
 				return
 			}
 			if err != nil {
-				log.Error().
+				log.Error().Ctx(ctx).
 					Err(err).
 					Str("webhook", webhook.Name).
 					Int("alertCount", len(alertList)).
@@ -1207,7 +1308,7 @@ func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertLis
 
 		jsonData, err = json.Marshal(payload)
 		if err != nil {
-			log.Error().
+			log.Error().Ctx(ctx).
 				Err(err).
 				Str("webhook", webhook.Name).
 				Int("alertCount", len(alertList)).
@@ -1221,7 +1322,7 @@ func (n *NotificationManager) sendGroupedWebhook(webhook WebhookConfig, alertLis
 	}
 
 	// Send using same request logic
-	n.sendWebhookRequest(webhook, jsonData, "grouped")
+	n.sendWebhookRequest(ctx, webhook, jsonData, "grouped")
 }
 
 // checkWebhookRateLimit checks if a webhook can be sent based on rate limits
@@ -1265,10 +1366,10 @@ func (n *NotificationManager) checkWebhookRateLimit(webhookURL string) bool {
 }
 
 // sendWebhookRequest sends the actual webhook request
-func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData []byte, alertType string) {
+func (n *NotificationManager) sendWebhookRequest(ctx context.Context, webhook WebhookConfig, jsonData []byte, alertType string) {
 	// Check rate limit before sending
 	if !n.checkWebhookRateLimit(webhook.URL) {
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Str("webhook", webhook.Name).
 			Str("url", webhook.URL).
 			Msg("Webhook request dropped due to rate limiting")
@@ -1290,16 +1391,16 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 			q.Del("chat_id") // Remove chat_id from query params
 			u.RawQuery = q.Encode()
 			webhookURL = u.String()
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("original", webhook.URL).
 				Str("cleaned", webhookURL).
 				Msg("Stripped chat_id from Telegram webhook URL")
 		}
 	}
 
-	req, err := http.NewRequest(method, webhookURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, method, webhookURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		log.Error().
+		log.Error().Ctx(ctx).
 			Err(err).
 			Str("webhook", webhook.Name).
 			Str("type", alertType).
@@ -1328,7 +1429,7 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 
 	// Debug log the payload for Telegram and Gotify webhooks
 	if webhook.Service == "telegram" || webhook.Service == "gotify" {
-		log.Debug().
+		log.Debug().Ctx(ctx).
 			Str("webhook", webhook.Name).
 			Str("service", webhook.Service).
 			Str("url", webhookURL).
@@ -1341,7 +1442,7 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Error().
+		log.Error().Ctx(ctx).
 			Err(err).
 			Str("webhook", webhook.Name).
 			Str("type", alertType).
@@ -1355,7 +1456,7 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 	var respBody bytes.Buffer
 	bytesRead, err := respBody.ReadFrom(limitedReader)
 	if err != nil {
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Err(err).
 			Str("webhook", webhook.Name).
 			Str("type", alertType).
@@ -1365,7 +1466,7 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 
 	// Check if we hit the size limit
 	if bytesRead >= WebhookMaxResponseSize {
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Str("webhook", webhook.Name).
 			Int64("bytesRead", bytesRead).
 			Int("maxSize", WebhookMaxResponseSize).
@@ -1375,7 +1476,7 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 	responseBody := respBody.String()
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		log.Info().
+		log.Info().Ctx(ctx).
 			Str("webhook", webhook.Name).
 			Str("service", webhook.Service).
 			Str("type", alertType).
@@ -1385,13 +1486,13 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 
 		// Log response body only in debug mode for successful requests
 		if len(responseBody) > 0 {
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("webhook", webhook.Name).
 				Str("response", responseBody).
 				Msg("Webhook response body")
 		}
 	} else {
-		log.Warn().
+		log.Warn().Ctx(ctx).
 			Str("webhook", webhook.Name).
 			Str("service", webhook.Service).
 			Str("type", alertType).
@@ -1402,7 +1503,7 @@ func (n *NotificationManager) sendWebhookRequest(webhook WebhookConfig, jsonData
 }
 
 // sendWebhook sends a webhook notification
-func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.Alert) {
+func (n *NotificationManager) sendWebhook(ctx context.Context, webhook WebhookConfig, alert *alerts.Alert) {
 	var jsonData []byte
 	var err error
 
@@ -1412,7 +1513,7 @@ func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.A
 	// Render URL template if placeholders are present
 	renderedURL, renderErr := renderWebhookURL(webhook.URL, data)
 	if renderErr != nil {
-		log.Error().
+		log.Error().Ctx(ctx).
 			Err(renderErr).
 			Str("webhook", webhook.Name).
 			Msg("Failed to render webhook URL template")
@@ -1424,7 +1525,7 @@ func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.A
 	if webhook.Service == "telegram" {
 		chatID, chatErr := extractTelegramChatID(renderedURL)
 		if chatErr != nil {
-			log.Error().
+			log.Error().Ctx(ctx).
 				Err(chatErr).
 				Str("webhook", webhook.Name).
 				Msg("Failed to extract Telegram chat_id - skipping webhook")
@@ -1432,7 +1533,7 @@ func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.A
 		}
 		if chatID != "" {
 			data.ChatID = chatID
-			log.Debug().
+			log.Debug().Ctx(ctx).
 				Str("webhook", webhook.Name).
 				Str("chatID", chatID).
 				Msg("Extracted Telegram chat_id from rendered URL")
@@ -1459,7 +1560,7 @@ func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.A
 
 		jsonData, err = n.generatePayloadFromTemplateWithService(enhanced.PayloadTemplate, data, webhook.Service)
 		if err != nil {
-			log.Error().
+			log.Error().Ctx(ctx).
 				Err(err).
 				Str("webhook", webhook.Name).
 				Str("alertID", alert.ID).
@@ -1490,7 +1591,7 @@ func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.A
 		if templateFound {
 			jsonData, err = n.generatePayloadFromTemplateWithService(enhanced.PayloadTemplate, data, webhook.Service)
 			if err != nil {
-				log.Error().
+				log.Error().Ctx(ctx).
 					Err(err).
 					Str("webhook", webhook.Name).
 					Str("service", webhook.Service).
@@ -1516,7 +1617,7 @@ func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.A
 
 		jsonData, err = json.Marshal(payload)
 		if err != nil {
-			log.Error().
+			log.Error().Ctx(ctx).
 				Err(err).
 				Str("webhook", webhook.Name).
 				Str("alertID", alert.ID).
@@ -1526,7 +1627,7 @@ func (n *NotificationManager) sendWebhook(webhook WebhookConfig, alert *alerts.A
 	}
 
 	// Send using common request logic
-	n.sendWebhookRequest(webhook, jsonData, fmt.Sprintf("alert-%s", alert.ID))
+	n.sendWebhookRequest(ctx, webhook, jsonData, fmt.Sprintf("alert-%s", alert.ID))
 }
 
 func convertWebhookCustomFields(fields map[string]string) map[string]interface{} {
@@ -2005,7 +2106,7 @@ func (n *NotificationManager) SendTestNotification(method string) error {
 		if webhookToTest == nil {
 			return fmt.Errorf("no enabled webhooks found")
 		}
-		n.sendWebhook(*webhookToTest, testAlert)
+		n.sendWebhook(context.Background(), *webhookToTest, testAlert)
 		return nil
 	default:
 		return fmt.Errorf("unknown notification method: %s", method)
@@ -2042,7 +2143,7 @@ func (n *NotificationManager) SendTestWebhook(webhook WebhookConfig) error {
 	}
 
 	// Send the test webhook
-	n.sendWebhook(webhook, testAlert)
+	n.sendWebhook(context.Background(), webhook, testAlert)
 	return nil
 }
 
@@ -2106,8 +2207,8 @@ func (n *NotificationManager) SendTestNotificationWithConfig(method string, conf
 			return fmt.Errorf("email configuration is incomplete: SMTP host and from address are required")
 		}
 
-		// Generate email using template
-		subject, htmlBody, textBody := EmailTemplate([]*alerts.Alert{testAlert}, true)
+		// Generate email using configured templates
+		subject, htmlBody, textBody := n.renderEmail(*config, []*alerts.Alert{testAlert}, true)
 
 		// Send using provided config and return any error
 		return n.sendHTMLEmailWithError(subject, htmlBody, textBody, *config)
@@ -2127,6 +2228,7 @@ func (n *NotificationManager) Stop() {
 		n.groupTimer.Stop()
 		n.groupTimer = nil
 	}
+	n.groupCtx = nil
 
 	// Clear pending alerts
 	n.pendingAlerts = nil
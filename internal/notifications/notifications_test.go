@@ -11,6 +11,10 @@ import (
 	"time"
 
 	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/RouXx67/PulseUp/internal/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func flushPending(n *NotificationManager) {
@@ -101,6 +105,40 @@ func TestNormalizeAppriseConfig(t *testing.T) {
 	}
 }
 
+func TestGroupedNotificationsJoinAlertTrace(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	nm := NewNotificationManager("")
+	nm.SetGroupingWindow(0)
+	nm.SetEmailConfig(EmailConfig{Enabled: false})
+	nm.SetAppriseConfig(AppriseConfig{Enabled: true, Targets: []string{"discord://token"}, TimeoutSeconds: 10})
+
+	delivered := make(chan trace.SpanContext, 1)
+	nm.appriseExec = func(ctx context.Context, path string, args []string) ([]byte, error) {
+		delivered <- trace.SpanContextFromContext(ctx)
+		return []byte("success"), nil
+	}
+
+	ctx, span := tracing.Start(context.Background(), "alerts CheckGuest")
+	defer span.End()
+	nm.SendAlert(ctx, &alerts.Alert{ID: "vm-100-cpu", Type: "cpu", ResourceID: "vm-100", StartTime: time.Now()})
+	flushPending(nm)
+
+	select {
+	case got := <-delivered:
+		if got.TraceID() != span.SpanContext().TraceID() {
+			t.Fatalf("delivery ran in trace %s, want the alert trace %s", got.TraceID(), span.SpanContext().TraceID())
+		}
+		if got.SpanID() == span.SpanContext().SpanID() {
+			t.Fatal("expected the delivery to run in its own child span")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the Apprise delivery")
+	}
+}
+
 func TestSendGroupedAppriseInvokesExecutor(t *testing.T) {
 	nm := NewNotificationManager("")
 	nm.SetGroupingWindow(0)
@@ -292,7 +330,7 @@ func TestNotificationCooldownAllowsNewAlertInstance(t *testing.T) {
 		StartTime: alertStart,
 	}
 
-	nm.SendAlert(alertA)
+	nm.SendAlert(context.Background(), alertA)
 	flushPending(nm)
 
 	nm.mu.RLock()
@@ -302,7 +340,7 @@ func TestNotificationCooldownAllowsNewAlertInstance(t *testing.T) {
 		t.Fatalf("first notification not recorded")
 	}
 
-	nm.SendAlert(alertA)
+	nm.SendAlert(context.Background(), alertA)
 
 	nm.mu.RLock()
 	pendingAfter := len(nm.pendingAlerts)
@@ -318,7 +356,7 @@ func TestNotificationCooldownAllowsNewAlertInstance(t *testing.T) {
 		StartTime: alertStart.Add(time.Minute),
 	}
 
-	nm.SendAlert(alertRestart)
+	nm.SendAlert(context.Background(), alertRestart)
 	flushPending(nm)
 
 	nm.mu.RLock()
@@ -350,8 +388,8 @@ func TestCancelAlertRemovesPending(t *testing.T) {
 		StartTime: time.Now(),
 	}
 
-	nm.SendAlert(alertA)
-	nm.SendAlert(alertB)
+	nm.SendAlert(context.Background(), alertA)
+	nm.SendAlert(context.Background(), alertB)
 
 	nm.CancelAlert(alertA.ID)
 
@@ -0,0 +1,319 @@
+package notifications
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/gosnmp/gosnmp"
+	"github.com/rs/zerolog/log"
+)
+
+// SNMP settings
+const (
+	SNMPTimeout = 10 * time.Second
+
+	// SNMPDefaultBaseOID is the pulseAlertMIB root published in docs/mibs/PULSE-ALERT-MIB.txt.
+	// It sits under the RFC 5612 documentation enterprise number and can be overridden.
+	SNMPDefaultBaseOID = "1.3.6.1.4.1.32473.1"
+
+	snmpSysUpTimeOID  = "1.3.6.1.2.1.1.3.0"
+	snmpTrapOIDOID    = "1.3.6.1.6.3.1.1.4.1.0"
+	snmpDefaultEngine = "80007ed90470756c7365" // enterprise 32473, text format, "pulse"
+)
+
+// Object and notification arcs below the base OID, matching PULSE-ALERT-MIB
+const (
+	snmpNotificationFired    = ".0.1"
+	snmpNotificationResolved = ".0.2"
+	snmpObjectsArc           = ".1"
+)
+
+var processStart = time.Now()
+
+// SNMPConfig holds SNMP trap notification settings.
+type SNMPConfig struct {
+	Enabled   bool   `json:"enabled"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Version   string `json:"version"`             // v2c or v3
+	Community string `json:"community,omitempty"` // v2c only
+	BaseOID   string `json:"baseOid,omitempty"`   // Defaults to SNMPDefaultBaseOID
+
+	// SNMPv3 USM settings
+	Username       string `json:"username,omitempty"`
+	AuthProtocol   string `json:"authProtocol,omitempty"` // none, md5, sha, sha224, sha256, sha384, sha512
+	AuthPassphrase string `json:"authPassphrase,omitempty"`
+	PrivProtocol   string `json:"privProtocol,omitempty"` // none, des, aes, aes192, aes256
+	PrivPassphrase string `json:"privPassphrase,omitempty"`
+	EngineID       string `json:"engineId,omitempty"` // Hex encoded authoritative engine ID
+}
+
+var snmpAuthProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"none":   gosnmp.NoAuth,
+	"md5":    gosnmp.MD5,
+	"sha":    gosnmp.SHA,
+	"sha224": gosnmp.SHA224,
+	"sha256": gosnmp.SHA256,
+	"sha384": gosnmp.SHA384,
+	"sha512": gosnmp.SHA512,
+}
+
+var snmpPrivProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"none":   gosnmp.NoPriv,
+	"des":    gosnmp.DES,
+	"aes":    gosnmp.AES,
+	"aes192": gosnmp.AES192,
+	"aes256": gosnmp.AES256,
+}
+
+// NormalizeSNMPConfig cleans SNMP configuration values and applies defaults.
+func NormalizeSNMPConfig(cfg SNMPConfig) SNMPConfig {
+	normalized := cfg
+
+	normalized.Host = strings.TrimSpace(normalized.Host)
+	if normalized.Port <= 0 {
+		normalized.Port = 162
+	}
+	normalized.Version = strings.ToLower(strings.TrimSpace(normalized.Version))
+	if normalized.Version != "v3" {
+		normalized.Version = "v2c"
+	}
+	if normalized.Version == "v2c" && normalized.Community == "" {
+		normalized.Community = "public"
+	}
+
+	normalized.BaseOID = strings.Trim(strings.TrimSpace(normalized.BaseOID), ".")
+	if normalized.BaseOID == "" {
+		normalized.BaseOID = SNMPDefaultBaseOID
+	}
+
+	normalized.Username = strings.TrimSpace(normalized.Username)
+	normalized.AuthProtocol = strings.ToLower(strings.TrimSpace(normalized.AuthProtocol))
+	if normalized.AuthProtocol == "" {
+		normalized.AuthProtocol = "none"
+	}
+	normalized.PrivProtocol = strings.ToLower(strings.TrimSpace(normalized.PrivProtocol))
+	if normalized.PrivProtocol == "" {
+		normalized.PrivProtocol = "none"
+	}
+	normalized.EngineID = strings.ToLower(strings.TrimSpace(normalized.EngineID))
+
+	return normalized
+}
+
+// ValidateSNMPConfig returns an error if an enabled SNMP configuration cannot be used.
+func ValidateSNMPConfig(cfg SNMPConfig) error {
+	cfg = NormalizeSNMPConfig(cfg)
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Host == "" {
+		return fmt.Errorf("SNMP trap receiver host is required")
+	}
+	if cfg.Port > 65535 {
+		return fmt.Errorf("invalid SNMP port %d", cfg.Port)
+	}
+	for _, part := range strings.Split(cfg.BaseOID, ".") {
+		if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return fmt.Errorf("invalid base OID %q", cfg.BaseOID)
+		}
+	}
+	if cfg.Version != "v3" {
+		return nil
+	}
+
+	if cfg.Username == "" {
+		return fmt.Errorf("SNMPv3 username is required")
+	}
+	if _, ok := snmpAuthProtocols[cfg.AuthProtocol]; !ok {
+		return fmt.Errorf("unsupported SNMPv3 auth protocol %q", cfg.AuthProtocol)
+	}
+	if _, ok := snmpPrivProtocols[cfg.PrivProtocol]; !ok {
+		return fmt.Errorf("unsupported SNMPv3 privacy protocol %q", cfg.PrivProtocol)
+	}
+	if cfg.AuthProtocol != "none" && len(cfg.AuthPassphrase) < 8 {
+		return fmt.Errorf("SNMPv3 auth passphrase must be at least 8 characters")
+	}
+	if cfg.PrivProtocol != "none" {
+		if cfg.AuthProtocol == "none" {
+			return fmt.Errorf("SNMPv3 privacy requires an auth protocol")
+		}
+		if len(cfg.PrivPassphrase) < 8 {
+			return fmt.Errorf("SNMPv3 privacy passphrase must be at least 8 characters")
+		}
+	}
+	if cfg.EngineID != "" {
+		if decoded, err := hex.DecodeString(cfg.EngineID); err != nil || len(decoded) < 5 || len(decoded) > 32 {
+			return fmt.Errorf("SNMPv3 engine ID must be 5-32 bytes of hex")
+		}
+	}
+	return nil
+}
+
+// SetSNMPConfig updates SNMP trap configuration.
+func (n *NotificationManager) SetSNMPConfig(config SNMPConfig) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.snmpConfig = NormalizeSNMPConfig(config)
+}
+
+// SetSNMPEngineBoots sets the SNMPv3 snmpEngineBoots value and restarts snmpEngineTime.
+// RFC 3414 requires the value to increase every time Pulse starts, so it is kept in the data directory.
+func (n *NotificationManager) SetSNMPEngineBoots(boots uint32) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.snmpEngineBoots = boots
+	n.snmpEngineStart = time.Now()
+}
+
+// GetSNMPConfig returns the SNMP trap configuration.
+func (n *NotificationManager) GetSNMPConfig() SNMPConfig {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.snmpConfig
+}
+
+// SendTestSNMP sends a test trap using the provided configuration.
+func (n *NotificationManager) SendTestSNMP(config SNMPConfig) error {
+	config = NormalizeSNMPConfig(config)
+	config.Enabled = true
+	if err := ValidateSNMPConfig(config); err != nil {
+		return err
+	}
+	return n.sendSNMPTraps(config, []*alerts.Alert{newChannelTestAlert("SNMP")}, AlertEventFired)
+}
+
+func (n *NotificationManager) sendSNMPAlerts(ctx context.Context, config SNMPConfig, alertList []*alerts.Alert, event AlertEvent) {
+	if err := n.sendSNMPTraps(config, alertList, event); err != nil {
+		log.Warn().Ctx(ctx).
+			Err(err).
+			Str("host", config.Host).
+			Int("port", config.Port).
+			Str("version", config.Version).
+			Str("event", string(event)).
+			Msg("Failed to send SNMP trap")
+	}
+}
+
+func (n *NotificationManager) sendSNMPTraps(config SNMPConfig, alertList []*alerts.Alert, event AlertEvent) error {
+	n.mu.RLock()
+	boots, engineTime := n.snmpEngineBoots, uint32(time.Since(n.snmpEngineStart).Seconds())
+	n.mu.RUnlock()
+
+	client, err := newSNMPClient(config, boots, engineTime)
+	if err != nil {
+		return err
+	}
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to open SNMP socket: %w", err)
+	}
+	defer client.Conn.Close()
+
+	sent := 0
+	for _, alert := range alertList {
+		if alert == nil {
+			continue
+		}
+		trap := gosnmp.SnmpTrap{Variables: buildSNMPTrapVariables(config.BaseOID, alert, event)}
+		if _, err := client.SendTrap(trap); err != nil {
+			return fmt.Errorf("failed to send SNMP trap: %w", err)
+		}
+		sent++
+	}
+
+	log.Debug().
+		Str("host", config.Host).
+		Str("version", config.Version).
+		Int("traps", sent).
+		Str("event", string(event)).
+		Msg("SNMP traps sent")
+	return nil
+}
+
+func newSNMPClient(config SNMPConfig, engineBoots, engineTime uint32) (*gosnmp.GoSNMP, error) {
+	client := &gosnmp.GoSNMP{
+		Target:    config.Host,
+		Port:      uint16(config.Port),
+		Transport: "udp",
+		Timeout:   SNMPTimeout,
+		Retries:   0,
+		MaxOids:   gosnmp.MaxOids,
+	}
+
+	if config.Version != "v3" {
+		client.Version = gosnmp.Version2c
+		client.Community = config.Community
+		return client, nil
+	}
+
+	engineID := config.EngineID
+	if engineID == "" {
+		engineID = snmpDefaultEngine
+	}
+	rawEngineID, err := hex.DecodeString(engineID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SNMPv3 engine ID: %w", err)
+	}
+
+	msgFlags := gosnmp.NoAuthNoPriv
+	if config.AuthProtocol != "none" {
+		msgFlags = gosnmp.AuthNoPriv
+		if config.PrivProtocol != "none" {
+			msgFlags = gosnmp.AuthPriv
+		}
+	}
+
+	client.Version = gosnmp.Version3
+	client.SecurityModel = gosnmp.UserSecurityModel
+	client.MsgFlags = msgFlags
+	client.SecurityParameters = &gosnmp.UsmSecurityParameters{
+		// Pulse is the authoritative engine for the traps it sends
+		AuthoritativeEngineID:    string(rawEngineID),
+		AuthoritativeEngineBoots: engineBoots,
+		AuthoritativeEngineTime:  engineTime,
+		UserName:                 config.Username,
+		AuthenticationProtocol:   snmpAuthProtocols[config.AuthProtocol],
+		AuthenticationPassphrase: config.AuthPassphrase,
+		PrivacyProtocol:          snmpPrivProtocols[config.PrivProtocol],
+		PrivacyPassphrase:        config.PrivPassphrase,
+	}
+	return client, nil
+}
+
+// buildSNMPTrapVariables returns the varbinds for a pulseAlertFired/pulseAlertResolved notification.
+func buildSNMPTrapVariables(baseOID string, alert *alerts.Alert, event AlertEvent) []gosnmp.SnmpPDU {
+	notification := baseOID + snmpNotificationFired
+	state := 1
+	if event == AlertEventResolved {
+		notification = baseOID + snmpNotificationResolved
+		state = 2
+	}
+	objects := "." + baseOID + snmpObjectsArc
+
+	str := func(arc int, value string) gosnmp.SnmpPDU {
+		return gosnmp.SnmpPDU{Name: objects + "." + strconv.Itoa(arc), Type: gosnmp.OctetString, Value: value}
+	}
+
+	uptime := uint32(time.Since(processStart) / (10 * time.Millisecond))
+	return []gosnmp.SnmpPDU{
+		{Name: snmpSysUpTimeOID, Type: gosnmp.TimeTicks, Value: uptime},
+		{Name: snmpTrapOIDOID, Type: gosnmp.ObjectIdentifier, Value: "." + notification},
+		str(1, alert.ID),
+		str(2, string(alert.Level)),
+		str(3, alert.Type),
+		str(4, alert.ResourceID),
+		str(5, alert.ResourceName),
+		str(6, alert.Node),
+		str(7, alert.Instance),
+		str(8, alert.Message),
+		str(9, strconv.FormatFloat(alert.Value, 'f', 2, 64)),
+		str(10, strconv.FormatFloat(alert.Threshold, 'f', 2, 64)),
+		str(11, alert.StartTime.UTC().Format(time.RFC3339)),
+		{Name: objects + ".12", Type: gosnmp.Integer, Value: state},
+	}
+}
@@ -0,0 +1,185 @@
+package notifications
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/gosnmp/gosnmp"
+)
+
+// startTrapListener starts a local trap receiver and returns its port and received packets.
+func startTrapListener(t *testing.T, params *gosnmp.GoSNMP) (int, <-chan *gosnmp.SnmpPacket) {
+	t.Helper()
+
+	probe, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := probe.LocalAddr().(*net.UDPAddr).Port
+	probe.Close()
+
+	received := make(chan *gosnmp.SnmpPacket, 4)
+	listener := gosnmp.NewTrapListener()
+	listener.Params = params
+	listener.OnNewTrap = func(packet *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+		received <- packet
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- listener.Listen("127.0.0.1:" + strconv.Itoa(port))
+	}()
+	select {
+	case <-listener.Listening():
+	case err := <-errCh:
+		t.Fatalf("trap listener failed: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("trap listener did not start")
+	}
+	t.Cleanup(listener.Close)
+
+	return port, received
+}
+
+func waitForTrap(t *testing.T, received <-chan *gosnmp.SnmpPacket) map[string]interface{} {
+	t.Helper()
+	select {
+	case packet := <-received:
+		values := make(map[string]interface{}, len(packet.Variables))
+		for _, variable := range packet.Variables {
+			value := variable.Value
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			values[variable.Name] = value
+		}
+		return values
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for trap")
+	}
+	return nil
+}
+
+func TestSendSNMPv2cFiredAndResolved(t *testing.T) {
+	port, received := startTrapListener(t, &gosnmp.GoSNMP{
+		Version:   gosnmp.Version2c,
+		Community: "noc",
+		Timeout:   time.Second,
+		Logger:    gosnmp.NewLogger(nil),
+	})
+
+	n := NewNotificationManager("")
+	cfg := NormalizeSNMPConfig(SNMPConfig{
+		Enabled:   true,
+		Host:      "127.0.0.1",
+		Port:      port,
+		Community: "noc",
+	})
+	alert := testSyslogAlert()
+
+	if err := n.sendSNMPTraps(cfg, []*alerts.Alert{alert}, AlertEventFired); err != nil {
+		t.Fatalf("send fired trap: %v", err)
+	}
+	values := waitForTrap(t, received)
+	if got := values["."+snmpTrapOIDOID]; got != "."+SNMPDefaultBaseOID+".0.1" {
+		t.Fatalf("expected pulseAlertFired trap OID, got %v", got)
+	}
+	if got := values["."+SNMPDefaultBaseOID+".1.1"]; got != alert.ID {
+		t.Fatalf("expected pulseAlertId %q, got %v", alert.ID, got)
+	}
+	if got := values["."+SNMPDefaultBaseOID+".1.2"]; got != "critical" {
+		t.Fatalf("expected pulseAlertLevel critical, got %v", got)
+	}
+	if got := values["."+SNMPDefaultBaseOID+".1.12"]; got != 1 {
+		t.Fatalf("expected pulseAlertState firing(1), got %v", got)
+	}
+
+	if err := n.sendSNMPTraps(cfg, []*alerts.Alert{alert}, AlertEventResolved); err != nil {
+		t.Fatalf("send resolved trap: %v", err)
+	}
+	values = waitForTrap(t, received)
+	if got := values["."+snmpTrapOIDOID]; got != "."+SNMPDefaultBaseOID+".0.2" {
+		t.Fatalf("expected pulseAlertResolved trap OID, got %v", got)
+	}
+	if got := values["."+SNMPDefaultBaseOID+".1.12"]; got != 2 {
+		t.Fatalf("expected pulseAlertState resolved(2), got %v", got)
+	}
+}
+
+func TestSendSNMPv3Trap(t *testing.T) {
+	engineID, _ := hex.DecodeString(snmpDefaultEngine)
+	port, received := startTrapListener(t, &gosnmp.GoSNMP{
+		Version:       gosnmp.Version3,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      gosnmp.AuthPriv,
+		Timeout:       time.Second,
+		Logger:        gosnmp.NewLogger(nil),
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			AuthoritativeEngineID:    string(engineID),
+			UserName:                 "pulse",
+			AuthenticationProtocol:   gosnmp.SHA256,
+			AuthenticationPassphrase: "auth-secret",
+			PrivacyProtocol:          gosnmp.AES,
+			PrivacyPassphrase:        "priv-secret",
+		},
+	})
+
+	n := NewNotificationManager("")
+	err := n.SendTestSNMP(SNMPConfig{
+		Host:           "127.0.0.1",
+		Port:           port,
+		Version:        "v3",
+		Username:       "pulse",
+		AuthProtocol:   "sha256",
+		AuthPassphrase: "auth-secret",
+		PrivProtocol:   "aes",
+		PrivPassphrase: "priv-secret",
+	})
+	if err != nil {
+		t.Fatalf("SendTestSNMP: %v", err)
+	}
+
+	values := waitForTrap(t, received)
+	if got := values["."+SNMPDefaultBaseOID+".1.1"]; got != "test-SNMP" {
+		t.Fatalf("expected test alert ID in trap, got %v", got)
+	}
+}
+
+func TestValidateSNMPConfig(t *testing.T) {
+	valid := SNMPConfig{
+		Enabled:        true,
+		Host:           "nms",
+		Version:        "v3",
+		Username:       "pulse",
+		AuthProtocol:   "sha",
+		AuthPassphrase: "auth-secret",
+	}
+	if err := ValidateSNMPConfig(valid); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	mutations := map[string]func(c *SNMPConfig){
+		"missing host":     func(c *SNMPConfig) { c.Host = "" },
+		"missing username": func(c *SNMPConfig) { c.Username = "" },
+		"short passphrase": func(c *SNMPConfig) { c.AuthPassphrase = "short" },
+		"unknown auth":     func(c *SNMPConfig) { c.AuthProtocol = "sha3" },
+		"privacy without auth": func(c *SNMPConfig) {
+			c.AuthProtocol = "none"
+			c.PrivProtocol = "aes"
+			c.PrivPassphrase = "priv-secret"
+		},
+		"bad engine id": func(c *SNMPConfig) { c.EngineID = "zz" },
+		"bad base oid":  func(c *SNMPConfig) { c.BaseOID = "1.3.x" },
+	}
+	for name, mutate := range mutations {
+		cfg := valid
+		mutate(&cfg)
+		if err := ValidateSNMPConfig(cfg); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
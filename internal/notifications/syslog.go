@@ -0,0 +1,364 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+	"github.com/rs/zerolog/log"
+)
+
+// Syslog settings
+const (
+	SyslogTimeout = 10 * time.Second
+
+	// SyslogDefaultSDID uses the RFC 5612 documentation enterprise number; NOCs that
+	// register their own SD-ID can override it per configuration.
+	SyslogDefaultSDID     = "pulseAlert@32473"
+	SyslogDefaultFacility = 16 // local0
+)
+
+// SyslogProtocol identifies the transport used to deliver syslog messages.
+type SyslogProtocol string
+
+const (
+	SyslogProtocolUDP SyslogProtocol = "udp"
+	SyslogProtocolTCP SyslogProtocol = "tcp"
+	SyslogProtocolTLS SyslogProtocol = "tls"
+)
+
+// AlertEvent identifies whether a notification reports a firing or a resolved alert.
+type AlertEvent string
+
+const (
+	AlertEventFired    AlertEvent = "fired"
+	AlertEventResolved AlertEvent = "resolved"
+)
+
+// SyslogConfig holds RFC 5424 syslog notification settings.
+type SyslogConfig struct {
+	Enabled          bool           `json:"enabled"`
+	Protocol         SyslogProtocol `json:"protocol"`
+	Host             string         `json:"host"`
+	Port             int            `json:"port"`
+	Facility         int            `json:"facility"`                   // 1-23, unset uses local0 (16)
+	AppName          string         `json:"appName,omitempty"`          // Defaults to "pulse"
+	Hostname         string         `json:"hostname,omitempty"`         // Defaults to the local hostname
+	StructuredDataID string         `json:"structuredDataId,omitempty"` // Defaults to pulseAlert@32473
+	CACert           string         `json:"caCert,omitempty"`           // PEM bundle for TLS verification
+	SkipTLSVerify    bool           `json:"skipTlsVerify,omitempty"`
+}
+
+// NormalizeSyslogConfig cleans syslog configuration values and applies defaults.
+func NormalizeSyslogConfig(cfg SyslogConfig) SyslogConfig {
+	normalized := cfg
+
+	switch SyslogProtocol(strings.ToLower(strings.TrimSpace(string(cfg.Protocol)))) {
+	case SyslogProtocolTCP:
+		normalized.Protocol = SyslogProtocolTCP
+	case SyslogProtocolTLS:
+		normalized.Protocol = SyslogProtocolTLS
+	default:
+		normalized.Protocol = SyslogProtocolUDP
+	}
+
+	normalized.Host = strings.TrimSpace(normalized.Host)
+	if normalized.Port <= 0 {
+		if normalized.Protocol == SyslogProtocolTLS {
+			normalized.Port = 6514
+		} else {
+			normalized.Port = 514
+		}
+	}
+	if normalized.Facility <= 0 || normalized.Facility > 23 {
+		normalized.Facility = SyslogDefaultFacility
+	}
+
+	normalized.AppName = strings.TrimSpace(normalized.AppName)
+	if normalized.AppName == "" {
+		normalized.AppName = "pulse"
+	}
+	normalized.Hostname = strings.TrimSpace(normalized.Hostname)
+	normalized.StructuredDataID = strings.TrimSpace(normalized.StructuredDataID)
+	if normalized.StructuredDataID == "" {
+		normalized.StructuredDataID = SyslogDefaultSDID
+	}
+	normalized.CACert = strings.TrimSpace(normalized.CACert)
+
+	return normalized
+}
+
+// ValidateSyslogConfig returns an error if an enabled syslog configuration cannot be used.
+func ValidateSyslogConfig(cfg SyslogConfig) error {
+	cfg = NormalizeSyslogConfig(cfg)
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Host == "" {
+		return fmt.Errorf("syslog host is required")
+	}
+	if cfg.Port > 65535 {
+		return fmt.Errorf("invalid syslog port %d", cfg.Port)
+	}
+	if !isValidSyslogName(cfg.AppName, 48) {
+		return fmt.Errorf("syslog app name must be 1-48 printable ASCII characters without spaces")
+	}
+	if cfg.Hostname != "" && !isValidSyslogName(cfg.Hostname, 255) {
+		return fmt.Errorf("syslog hostname must be printable ASCII without spaces")
+	}
+	if !isValidSyslogName(cfg.StructuredDataID, 32) || strings.ContainsAny(cfg.StructuredDataID, `="]`) {
+		return fmt.Errorf("invalid structured data ID %q", cfg.StructuredDataID)
+	}
+	if cfg.CACert != "" {
+		if ok := x509.NewCertPool().AppendCertsFromPEM([]byte(cfg.CACert)); !ok {
+			return fmt.Errorf("syslog CA certificate is not valid PEM")
+		}
+	}
+	return nil
+}
+
+// SetSyslogConfig updates syslog configuration.
+func (n *NotificationManager) SetSyslogConfig(config SyslogConfig) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.syslogConfig = NormalizeSyslogConfig(config)
+}
+
+// GetSyslogConfig returns the syslog configuration.
+func (n *NotificationManager) GetSyslogConfig() SyslogConfig {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.syslogConfig
+}
+
+// SendTestSyslog sends a test syslog message using the provided configuration.
+func (n *NotificationManager) SendTestSyslog(config SyslogConfig) error {
+	config = NormalizeSyslogConfig(config)
+	config.Enabled = true
+	if err := ValidateSyslogConfig(config); err != nil {
+		return err
+	}
+	return n.sendSyslog(config, []*alerts.Alert{newChannelTestAlert("syslog")}, AlertEventFired)
+}
+
+func (n *NotificationManager) sendSyslogAlerts(ctx context.Context, config SyslogConfig, alertList []*alerts.Alert, event AlertEvent) {
+	if err := n.sendSyslog(config, alertList, event); err != nil {
+		log.Warn().Ctx(ctx).
+			Err(err).
+			Str("protocol", string(config.Protocol)).
+			Str("host", config.Host).
+			Int("port", config.Port).
+			Str("event", string(event)).
+			Msg("Failed to send syslog notification")
+	}
+}
+
+func (n *NotificationManager) sendSyslog(config SyslogConfig, alertList []*alerts.Alert, event AlertEvent) error {
+	hostname := config.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	messages := make([]string, 0, len(alertList))
+	for _, alert := range alertList {
+		if alert == nil {
+			continue
+		}
+		messages = append(messages, formatSyslogMessage(config, hostname, alert, event, time.Now()))
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	conn, err := dialSyslog(config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, msg := range messages {
+		if err := conn.SetWriteDeadline(time.Now().Add(SyslogTimeout)); err != nil {
+			return err
+		}
+		frame := msg
+		if config.Protocol != SyslogProtocolUDP {
+			// RFC 5425/6587 octet-counting framing for stream transports
+			frame = strconv.Itoa(len(msg)) + " " + msg
+		}
+		if _, err := conn.Write([]byte(frame)); err != nil {
+			return fmt.Errorf("failed to write syslog message: %w", err)
+		}
+	}
+
+	log.Debug().
+		Str("protocol", string(config.Protocol)).
+		Str("host", config.Host).
+		Int("messages", len(messages)).
+		Str("event", string(event)).
+		Msg("Syslog notification sent")
+	return nil
+}
+
+func dialSyslog(config SyslogConfig) (net.Conn, error) {
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	dialer := &net.Dialer{Timeout: SyslogTimeout}
+
+	switch config.Protocol {
+	case SyslogProtocolTLS:
+		tlsConfig := &tls.Config{
+			ServerName:         config.Host,
+			InsecureSkipVerify: config.SkipTLSVerify,
+			MinVersion:         tls.VersionTLS12,
+		}
+		if config.CACert != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(config.CACert)) {
+				return nil, fmt.Errorf("syslog CA certificate is not valid PEM")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog server over TLS: %w", err)
+		}
+		return conn, nil
+	case SyslogProtocolTCP:
+		conn, err := dialer.Dial("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog server: %w", err)
+		}
+		return conn, nil
+	default:
+		conn, err := dialer.Dial("udp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open syslog UDP socket: %w", err)
+		}
+		return conn, nil
+	}
+}
+
+// formatSyslogMessage renders an RFC 5424 message with the alert fields as structured data.
+func formatSyslogMessage(config SyslogConfig, hostname string, alert *alerts.Alert, event AlertEvent, now time.Time) string {
+	severity := 6 // informational
+	msgID := "ALERT_RESOLVED"
+	if event == AlertEventFired {
+		msgID = "ALERT_FIRED"
+		switch alert.Level {
+		case alerts.AlertLevelCritical:
+			severity = 2
+		case alerts.AlertLevelWarning:
+			severity = 4
+		}
+	} else {
+		severity = 5 // notice
+	}
+	pri := config.Facility*8 + severity
+
+	params := []struct{ key, value string }{
+		{"event", string(event)},
+		{"id", alert.ID},
+		{"level", string(alert.Level)},
+		{"type", alert.Type},
+		{"resourceId", alert.ResourceID},
+		{"resourceName", alert.ResourceName},
+		{"node", alert.Node},
+		{"instance", alert.Instance},
+		{"value", strconv.FormatFloat(alert.Value, 'f', 2, 64)},
+		{"threshold", strconv.FormatFloat(alert.Threshold, 'f', 2, 64)},
+		{"startTime", alert.StartTime.UTC().Format(time.RFC3339)},
+	}
+	if resourceType, ok := alert.Metadata["resourceType"].(string); ok && resourceType != "" {
+		params = append(params, struct{ key, value string }{"resourceType", resourceType})
+	}
+
+	var sd strings.Builder
+	sd.WriteString("[")
+	sd.WriteString(config.StructuredDataID)
+	for _, param := range params {
+		sd.WriteString(" ")
+		sd.WriteString(param.key)
+		sd.WriteString(`="`)
+		sd.WriteString(escapeSyslogParam(param.value))
+		sd.WriteString(`"`)
+	}
+	sd.WriteString("]")
+
+	message := alert.Message
+	if event == AlertEventResolved {
+		message = "Resolved: " + message
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s",
+		pri,
+		now.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderValue(hostname, 255),
+		syslogHeaderValue(config.AppName, 48),
+		os.Getpid(),
+		msgID,
+		sd.String(),
+		strings.ReplaceAll(message, "\n", " "),
+	)
+}
+
+// escapeSyslogParam escapes the characters RFC 5424 requires inside PARAM-VALUE.
+func escapeSyslogParam(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	return replacer.Replace(value)
+}
+
+// syslogHeaderValue returns a header field limited to printable ASCII, or the NILVALUE.
+func syslogHeaderValue(value string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+		if b.Len() >= maxLen {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+func isValidSyslogName(value string, maxLen int) bool {
+	if value == "" || len(value) > maxLen {
+		return false
+	}
+	for _, r := range value {
+		if r <= 32 || r >= 127 {
+			return false
+		}
+	}
+	return true
+}
+
+// newChannelTestAlert builds the alert sent by channel test buttons.
+func newChannelTestAlert(channel string) *alerts.Alert {
+	return &alerts.Alert{
+		ID:           "test-" + channel,
+		Type:         "cpu",
+		Level:        alerts.AlertLevelWarning,
+		ResourceID:   "test-resource",
+		ResourceName: "Test Resource",
+		Node:         "test-node",
+		Instance:     "test-instance",
+		Message:      fmt.Sprintf("This is a test alert from Pulse to verify your %s notification settings", channel),
+		Value:        85.5,
+		Threshold:    80,
+		StartTime:    time.Now().Add(-5 * time.Minute),
+		LastSeen:     time.Now(),
+		Metadata: map[string]interface{}{
+			"resourceType": "vm",
+		},
+	}
+}
@@ -0,0 +1,200 @@
+package notifications
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/alerts"
+)
+
+func testSyslogAlert() *alerts.Alert {
+	return &alerts.Alert{
+		ID:           "pve1-qemu-101-cpu",
+		Type:         "cpu",
+		Level:        alerts.AlertLevelCritical,
+		ResourceID:   "pve1-qemu-101",
+		ResourceName: `web "01"]`,
+		Node:         "pve1",
+		Instance:     "cluster",
+		Message:      "VM web01 CPU at 95%",
+		Value:        95,
+		Threshold:    90,
+		StartTime:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	cfg := NormalizeSyslogConfig(SyslogConfig{Host: "127.0.0.1"})
+	now := time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC)
+
+	msg := formatSyslogMessage(cfg, "pulse-host", testSyslogAlert(), AlertEventFired, now)
+
+	// local0 (16) * 8 + critical (2)
+	prefix := "<130>1 2024-05-01T10:01:00.000000Z pulse-host pulse "
+	if !strings.HasPrefix(msg, prefix) {
+		t.Fatalf("unexpected header: %q", msg)
+	}
+	if !strings.Contains(msg, " ALERT_FIRED [pulseAlert@32473 event=\"fired\" id=\"pve1-qemu-101-cpu\" level=\"critical\"") {
+		t.Fatalf("missing structured data: %q", msg)
+	}
+	if !strings.Contains(msg, `resourceName="web \"01\"\]"`) {
+		t.Fatalf("expected escaped param value: %q", msg)
+	}
+	if !strings.HasSuffix(msg, "] VM web01 CPU at 95%") {
+		t.Fatalf("unexpected message body: %q", msg)
+	}
+
+	resolved := formatSyslogMessage(cfg, "", testSyslogAlert(), AlertEventResolved, now)
+	if !strings.HasPrefix(resolved, "<133>1 2024-05-01T10:01:00.000000Z - pulse ") {
+		t.Fatalf("unexpected resolved header: %q", resolved)
+	}
+	if !strings.Contains(resolved, " ALERT_RESOLVED [") || !strings.HasSuffix(resolved, "Resolved: VM web01 CPU at 95%") {
+		t.Fatalf("unexpected resolved message: %q", resolved)
+	}
+}
+
+func TestValidateSyslogConfig(t *testing.T) {
+	if err := ValidateSyslogConfig(SyslogConfig{}); err != nil {
+		t.Fatalf("disabled config should be valid: %v", err)
+	}
+	if err := ValidateSyslogConfig(SyslogConfig{Enabled: true}); err == nil {
+		t.Fatalf("expected error for missing host")
+	}
+	if err := ValidateSyslogConfig(SyslogConfig{Enabled: true, Host: "log", StructuredDataID: "bad id"}); err == nil {
+		t.Fatalf("expected error for invalid structured data ID")
+	}
+	if err := ValidateSyslogConfig(SyslogConfig{Enabled: true, Host: "log", Protocol: SyslogProtocolTLS, CACert: "not pem"}); err == nil {
+		t.Fatalf("expected error for invalid CA certificate")
+	}
+
+	cfg := NormalizeSyslogConfig(SyslogConfig{Protocol: "TLS"})
+	if cfg.Protocol != SyslogProtocolTLS || cfg.Port != 6514 {
+		t.Fatalf("expected TLS defaults, got %s:%d", cfg.Protocol, cfg.Port)
+	}
+}
+
+func TestSendSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	n := NewNotificationManager("")
+	cfg := SyslogConfig{
+		Enabled:  true,
+		Protocol: SyslogProtocolUDP,
+		Host:     "127.0.0.1",
+		Port:     conn.LocalAddr().(*net.UDPAddr).Port,
+		Hostname: "pulse-test",
+	}
+	if err := n.SendTestSyslog(cfg); err != nil {
+		t.Fatalf("SendTestSyslog: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	size, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	msg := string(buf[:size])
+	if !strings.HasPrefix(msg, "<132>1 ") || !strings.Contains(msg, " pulse-test pulse ") || !strings.Contains(msg, `id="test-syslog"`) {
+		t.Fatalf("unexpected datagram: %q", msg)
+	}
+}
+
+func TestSendSyslogTCPFiredAndResolved(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				reader := bufio.NewReader(c)
+				for {
+					// Octet-counting framing: "<len> <msg>"
+					lengthStr, err := reader.ReadString(' ')
+					if err != nil {
+						return
+					}
+					length, err := strconv.Atoi(strings.TrimSpace(lengthStr))
+					if err != nil {
+						received <- "bad frame: " + lengthStr
+						return
+					}
+					payload := make([]byte, length)
+					if _, err := io.ReadFull(reader, payload); err != nil {
+						return
+					}
+					received <- string(payload)
+				}
+			}(conn)
+		}
+	}()
+
+	n := NewNotificationManager("")
+	n.SetGroupingWindow(0)
+	n.SetSyslogConfig(SyslogConfig{
+		Enabled:  true,
+		Protocol: SyslogProtocolTCP,
+		Host:     "127.0.0.1",
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+	})
+
+	alert := testSyslogAlert()
+	n.SendAlert(context.Background(), alert)
+
+	expectMsgID := func(msgID string) {
+		t.Helper()
+		select {
+		case msg := <-received:
+			if !strings.Contains(msg, " "+msgID+" ") || !strings.Contains(msg, `id="pve1-qemu-101-cpu"`) {
+				t.Fatalf("expected %s message, got %q", msgID, msg)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %s message", msgID)
+		}
+	}
+	expectMsgID("ALERT_FIRED")
+
+	// The firing notification must be recorded before the resolve event is accepted
+	deadline := time.Now().Add(time.Second)
+	for {
+		n.mu.RLock()
+		_, notified := n.lastNotified[alert.ID]
+		n.mu.RUnlock()
+		if notified || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	n.SendResolved(context.Background(), alert)
+	expectMsgID("ALERT_RESOLVED")
+
+	// Alerts that were never notified do not produce resolve events
+	other := testSyslogAlert()
+	other.ID = "never-notified"
+	n.SendResolved(context.Background(), other)
+	select {
+	case msg := <-received:
+		t.Fatalf("unexpected resolve event for unnotified alert: %q", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,473 @@
+// Package tenancy restricts monitoring data to the pools, tags and instances of tenant scopes.
+package tenancy
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+// Scope is the combined visibility of the tenant scopes bound to a caller. Instances grant
+// everything belonging to a PVE, PBS or PMG instance; pools and tags grant matching guests
+// together with their backups, snapshots, tasks and alerts. A nil Scope is unrestricted.
+type Scope struct {
+	ids       []string
+	instances map[string]bool
+	pools     map[string]bool
+	tags      map[string]bool
+}
+
+// New combines tenant scopes into one Scope. It returns nil when no scopes are given.
+func New(scopes []config.TenantScope) *Scope {
+	if len(scopes) == 0 {
+		return nil
+	}
+	s := &Scope{
+		instances: make(map[string]bool),
+		pools:     make(map[string]bool),
+		tags:      make(map[string]bool),
+	}
+	for _, scope := range scopes {
+		s.ids = append(s.ids, scope.ID)
+		for _, instance := range scope.Instances {
+			s.instances[instance] = true
+		}
+		for _, pool := range scope.Pools {
+			s.pools[pool] = true
+		}
+		for _, tag := range scope.Tags {
+			s.tags[strings.ToLower(tag)] = true
+		}
+	}
+	sort.Strings(s.ids)
+	return s
+}
+
+// IDs returns the sorted IDs of the combined tenant scopes
+func (s *Scope) IDs() []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s.ids...)
+}
+
+// Key identifies the combination of tenant scopes, empty for an unrestricted scope
+func (s *Scope) Key() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(s.ids, ",")
+}
+
+// ParseKey splits a key produced by Key back into scope IDs
+func ParseKey(key string) []string {
+	if key == "" {
+		return nil
+	}
+	return strings.Split(key, ",")
+}
+
+// AllowsInstance reports whether everything belonging to an instance is visible
+func (s *Scope) AllowsInstance(instance string) bool {
+	return s == nil || s.instances[instance]
+}
+
+// AllowsGuest reports whether a guest is visible through its instance, pool or tags
+func (s *Scope) AllowsGuest(instance, pool string, tags []string) bool {
+	if s.AllowsInstance(instance) {
+		return true
+	}
+	if pool != "" && s.pools[pool] {
+		return true
+	}
+	for _, tag := range tags {
+		if s.tags[strings.ToLower(strings.TrimSpace(tag))] {
+			return true
+		}
+	}
+	return false
+}
+
+// Guests indexes which guests a scope can see so that alerts, backups and tasks, which only
+// reference a guest by ID or VMID, can be attributed to them.
+type Guests struct {
+	ids       map[string]bool // Guest IDs as shown in /api/state
+	vmids     map[string]bool // "instance/vmid" of visible guests
+	instances map[string]bool // Instances with at least one visible guest
+	// PBS backups carry a VMID but no PVE instance. They are attributed to a visible guest
+	// only when no hidden guest shares the VMID.
+	visibleVMIDs map[string]bool
+	hiddenVMIDs  map[string]bool
+}
+
+func newGuests() Guests {
+	return Guests{
+		ids:          make(map[string]bool),
+		vmids:        make(map[string]bool),
+		instances:    make(map[string]bool),
+		visibleVMIDs: make(map[string]bool),
+		hiddenVMIDs:  make(map[string]bool),
+	}
+}
+
+func (g Guests) add(visible bool, id, instance string, vmid int) {
+	vmidKey := strconv.Itoa(vmid)
+	if !visible {
+		g.hiddenVMIDs[vmidKey] = true
+		return
+	}
+	g.ids[id] = true
+	g.vmids[instance+"/"+vmidKey] = true
+	g.instances[instance] = true
+	g.visibleVMIDs[vmidKey] = true
+}
+
+// Guests returns the index of guests in the snapshot that the scope can see
+func (s *Scope) Guests(snapshot models.StateSnapshot) Guests {
+	guests := newGuests()
+	for _, vm := range snapshot.VMs {
+		guests.add(s.AllowsGuest(vm.Instance, vm.Pool, vm.Tags), vm.ID, vm.Instance, vm.VMID)
+	}
+	for _, ct := range snapshot.Containers {
+		guests.add(s.AllowsGuest(ct.Instance, ct.Pool, ct.Tags), ct.ID, ct.Instance, ct.VMID)
+	}
+	return guests
+}
+
+func (s *Scope) frontendGuests(state models.StateFrontend) Guests {
+	guests := newGuests()
+	for _, vm := range state.VMs {
+		guests.add(s.AllowsGuest(vm.Instance, vm.Pool, splitTags(vm.Tags)), vm.ID, vm.Instance, vm.VMID)
+	}
+	for _, ct := range state.Containers {
+		guests.add(s.AllowsGuest(ct.Instance, ct.Pool, splitTags(ct.Tags)), ct.ID, ct.Instance, ct.VMID)
+	}
+	return guests
+}
+
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+// hasVMID reports whether the guest with the VMID on the instance is visible
+func (g Guests) hasVMID(instance string, vmid int) bool {
+	return vmid > 0 && g.vmids[instance+"/"+strconv.Itoa(vmid)]
+}
+
+// owns reports whether a resource ID is a visible guest or derived from one, such as
+// "<guest id>-<snapshot name>"
+func (g Guests) owns(resourceID string) bool {
+	if g.ids[resourceID] {
+		return true
+	}
+	for id := range g.ids {
+		if strings.HasPrefix(resourceID, id+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsAlert reports whether an alert on the given instance and resource is visible
+func (s *Scope) AllowsAlert(instance, resourceID string, guests Guests) bool {
+	return s.AllowsInstance(instance) || guests.owns(resourceID)
+}
+
+// AllowsTask reports whether a PVE task is visible
+func (s *Scope) AllowsTask(task models.ClusterTask, guests Guests) bool {
+	return s.AllowsInstance(task.Instance) || guests.hasVMID(task.Instance, task.VMID)
+}
+
+// FilterPVEBackups keeps the backup tasks, storage backups and snapshots of visible guests
+func (s *Scope) FilterPVEBackups(backups models.PVEBackups, guests Guests) models.PVEBackups {
+	if s == nil {
+		return backups
+	}
+	filtered := models.PVEBackups{
+		BackupTasks:    []models.BackupTask{},
+		StorageBackups: []models.StorageBackup{},
+		GuestSnapshots: []models.GuestSnapshot{},
+	}
+	for _, task := range backups.BackupTasks {
+		if s.allowsBackupTask(task, guests) {
+			filtered.BackupTasks = append(filtered.BackupTasks, task)
+		}
+	}
+	for _, backup := range backups.StorageBackups {
+		if s.AllowsInstance(backup.Instance) || guests.hasVMID(backup.Instance, backup.VMID) {
+			filtered.StorageBackups = append(filtered.StorageBackups, backup)
+		}
+	}
+	for _, snapshot := range backups.GuestSnapshots {
+		if s.AllowsInstance(snapshot.Instance) || guests.hasVMID(snapshot.Instance, snapshot.VMID) {
+			filtered.GuestSnapshots = append(filtered.GuestSnapshots, snapshot)
+		}
+	}
+	return filtered
+}
+
+// allowsBackupTask attributes a backup task through its ID, which is "<instance>-<UPID>"
+func (s *Scope) allowsBackupTask(task models.BackupTask, guests Guests) bool {
+	for instance := range s.instances {
+		if strings.HasPrefix(task.ID, instance+"-") {
+			return true
+		}
+	}
+	for instance := range guests.instances {
+		if strings.HasPrefix(task.ID, instance+"-") && guests.hasVMID(instance, task.VMID) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterPBSBackups keeps backups on PBS instances in scope and backups of visible guests
+func (s *Scope) FilterPBSBackups(backups []models.PBSBackup, guests Guests) []models.PBSBackup {
+	if s == nil {
+		return backups
+	}
+	filtered := []models.PBSBackup{}
+	for _, backup := range backups {
+		if s.AllowsInstance(backup.Instance) || (guests.visibleVMIDs[backup.VMID] && !guests.hiddenVMIDs[backup.VMID]) {
+			filtered = append(filtered, backup)
+		}
+	}
+	return filtered
+}
+
+// FilterPMGBackups keeps backups of PMG instances in scope
+func (s *Scope) FilterPMGBackups(backups []models.PMGBackup) []models.PMGBackup {
+	if s == nil {
+		return backups
+	}
+	filtered := []models.PMGBackup{}
+	for _, backup := range backups {
+		if s.AllowsInstance(backup.Instance) {
+			filtered = append(filtered, backup)
+		}
+	}
+	return filtered
+}
+
+// FilterFrontend returns the part of the frontend state visible to the scope. Docker and host
+// agents are not tied to an instance and are hidden from restricted scopes.
+func (s *Scope) FilterFrontend(state models.StateFrontend) models.StateFrontend {
+	if s == nil {
+		return state
+	}
+	guests := s.frontendGuests(state)
+
+	filtered := models.StateFrontend{
+		Nodes:            []models.NodeFrontend{},
+		VMs:              []models.VMFrontend{},
+		Containers:       []models.ContainerFrontend{},
+		DockerHosts:      []models.DockerHostFrontend{},
+		Hosts:            []models.HostFrontend{},
+		Storage:          []models.StorageFrontend{},
+		CephClusters:     []models.CephClusterFrontend{},
+		PhysicalDisks:    []models.PhysicalDisk{},
+		PBS:              []models.PBSInstance{},
+		PMG:              []models.PMGInstance{},
+		ReplicationJobs:  []models.ReplicationJobFrontend{},
+		ActiveAlerts:     []models.Alert{},
+		Metrics:          state.Metrics,
+		Performance:      state.Performance,
+		ConnectionHealth: make(map[string]bool),
+		Stats:            state.Stats,
+		LastUpdate:       state.LastUpdate,
+	}
+
+	for _, node := range state.Nodes {
+		if s.AllowsInstance(node.Instance) {
+			filtered.Nodes = append(filtered.Nodes, node)
+		}
+	}
+	for _, vm := range state.VMs {
+		if guests.ids[vm.ID] {
+			filtered.VMs = append(filtered.VMs, vm)
+		}
+	}
+	for _, ct := range state.Containers {
+		if guests.ids[ct.ID] {
+			filtered.Containers = append(filtered.Containers, ct)
+		}
+	}
+	for _, storage := range state.Storage {
+		if s.AllowsInstance(storage.Instance) {
+			filtered.Storage = append(filtered.Storage, storage)
+		}
+	}
+	for _, cluster := range state.CephClusters {
+		if s.AllowsInstance(cluster.Instance) {
+			filtered.CephClusters = append(filtered.CephClusters, cluster)
+		}
+	}
+	for _, disk := range state.PhysicalDisks {
+		if s.AllowsInstance(disk.Instance) {
+			filtered.PhysicalDisks = append(filtered.PhysicalDisks, disk)
+		}
+	}
+	for _, pbs := range state.PBS {
+		if s.AllowsInstance(pbs.Name) {
+			filtered.PBS = append(filtered.PBS, pbs)
+		}
+	}
+	for _, pmg := range state.PMG {
+		if s.AllowsInstance(pmg.Name) {
+			filtered.PMG = append(filtered.PMG, pmg)
+		}
+	}
+	for _, job := range state.ReplicationJobs {
+		if s.AllowsInstance(job.Instance) || guests.hasVMID(job.Instance, job.GuestID) {
+			filtered.ReplicationJobs = append(filtered.ReplicationJobs, job)
+		}
+	}
+	for _, alert := range state.ActiveAlerts {
+		if s.AllowsAlert(alert.Instance, alert.ResourceID, guests) {
+			filtered.ActiveAlerts = append(filtered.ActiveAlerts, alert)
+		}
+	}
+	for _, status := range state.ClusterHA {
+		if s.AllowsInstance(status.Instance) {
+			filtered.ClusterHA = append(filtered.ClusterHA, status)
+		}
+	}
+	for _, entry := range state.NodeMaintenance {
+		if s.AllowsInstance(entry.Instance) {
+			filtered.NodeMaintenance = append(filtered.NodeMaintenance, entry)
+		}
+	}
+	for key, healthy := range state.ConnectionHealth {
+		instance := strings.TrimPrefix(strings.TrimPrefix(key, "pbs-"), "pmg-")
+		if s.AllowsInstance(key) || s.AllowsInstance(instance) || guests.instances[key] {
+			filtered.ConnectionHealth[key] = healthy
+		}
+	}
+
+	filtered.PVEBackups = s.FilterPVEBackups(state.PVEBackups, guests)
+	filtered.PBSBackups = s.FilterPBSBackups(state.PBSBackups, guests)
+	filtered.PMGBackups = s.FilterPMGBackups(state.PMGBackups)
+	filtered.Backups = models.Backups{
+		PVE: s.FilterPVEBackups(state.Backups.PVE, guests),
+		PBS: s.FilterPBSBackups(state.Backups.PBS, guests),
+		PMG: s.FilterPMGBackups(state.Backups.PMG),
+	}
+	return filtered
+}
+
+// FilterSnapshot returns the part of a state snapshot visible to the scope, with the same rules
+// as FilterFrontend
+func (s *Scope) FilterSnapshot(snapshot models.StateSnapshot) models.StateSnapshot {
+	if s == nil {
+		return snapshot
+	}
+	guests := s.Guests(snapshot)
+
+	filtered := models.StateSnapshot{
+		Nodes:            []models.Node{},
+		VMs:              []models.VM{},
+		Containers:       []models.Container{},
+		DockerHosts:      []models.DockerHost{},
+		Storage:          []models.Storage{},
+		CephClusters:     []models.CephCluster{},
+		PhysicalDisks:    []models.PhysicalDisk{},
+		PBSInstances:     []models.PBSInstance{},
+		PMGInstances:     []models.PMGInstance{},
+		ReplicationJobs:  []models.ReplicationJob{},
+		ActiveAlerts:     []models.Alert{},
+		RecentlyResolved: []models.ResolvedAlert{},
+		Metrics:          snapshot.Metrics,
+		Performance:      snapshot.Performance,
+		ConnectionHealth: make(map[string]bool),
+		Stats:            snapshot.Stats,
+		LastUpdate:       snapshot.LastUpdate,
+	}
+
+	for _, node := range snapshot.Nodes {
+		if s.AllowsInstance(node.Instance) {
+			filtered.Nodes = append(filtered.Nodes, node)
+		}
+	}
+	for _, vm := range snapshot.VMs {
+		if guests.ids[vm.ID] {
+			filtered.VMs = append(filtered.VMs, vm)
+		}
+	}
+	for _, ct := range snapshot.Containers {
+		if guests.ids[ct.ID] {
+			filtered.Containers = append(filtered.Containers, ct)
+		}
+	}
+	for _, storage := range snapshot.Storage {
+		if s.AllowsInstance(storage.Instance) {
+			filtered.Storage = append(filtered.Storage, storage)
+		}
+	}
+	for _, cluster := range snapshot.CephClusters {
+		if s.AllowsInstance(cluster.Instance) {
+			filtered.CephClusters = append(filtered.CephClusters, cluster)
+		}
+	}
+	for _, disk := range snapshot.PhysicalDisks {
+		if s.AllowsInstance(disk.Instance) {
+			filtered.PhysicalDisks = append(filtered.PhysicalDisks, disk)
+		}
+	}
+	for _, pbs := range snapshot.PBSInstances {
+		if s.AllowsInstance(pbs.Name) {
+			filtered.PBSInstances = append(filtered.PBSInstances, pbs)
+		}
+	}
+	for _, pmg := range snapshot.PMGInstances {
+		if s.AllowsInstance(pmg.Name) {
+			filtered.PMGInstances = append(filtered.PMGInstances, pmg)
+		}
+	}
+	for _, job := range snapshot.ReplicationJobs {
+		if s.AllowsInstance(job.Instance) || guests.hasVMID(job.Instance, job.GuestID) {
+			filtered.ReplicationJobs = append(filtered.ReplicationJobs, job)
+		}
+	}
+	for _, alert := range snapshot.ActiveAlerts {
+		if s.AllowsAlert(alert.Instance, alert.ResourceID, guests) {
+			filtered.ActiveAlerts = append(filtered.ActiveAlerts, alert)
+		}
+	}
+	for _, alert := range snapshot.RecentlyResolved {
+		if s.AllowsAlert(alert.Instance, alert.ResourceID, guests) {
+			filtered.RecentlyResolved = append(filtered.RecentlyResolved, alert)
+		}
+	}
+	for _, status := range snapshot.ClusterHA {
+		if s.AllowsInstance(status.Instance) {
+			filtered.ClusterHA = append(filtered.ClusterHA, status)
+		}
+	}
+	for _, entry := range snapshot.NodeMaintenance {
+		if s.AllowsInstance(entry.Instance) {
+			filtered.NodeMaintenance = append(filtered.NodeMaintenance, entry)
+		}
+	}
+	for key, healthy := range snapshot.ConnectionHealth {
+		instance := strings.TrimPrefix(strings.TrimPrefix(key, "pbs-"), "pmg-")
+		if s.AllowsInstance(key) || s.AllowsInstance(instance) || guests.instances[key] {
+			filtered.ConnectionHealth[key] = healthy
+		}
+	}
+
+	filtered.PVEBackups = s.FilterPVEBackups(snapshot.PVEBackups, guests)
+	filtered.PBSBackups = s.FilterPBSBackups(snapshot.PBSBackups, guests)
+	filtered.PMGBackups = s.FilterPMGBackups(snapshot.PMGBackups)
+	filtered.Backups = models.Backups{
+		PVE: s.FilterPVEBackups(snapshot.Backups.PVE, guests),
+		PBS: s.FilterPBSBackups(snapshot.Backups.PBS, guests),
+		PMG: s.FilterPMGBackups(snapshot.Backups.PMG),
+	}
+	return filtered
+}
@@ -0,0 +1,157 @@
+package tenancy
+
+import (
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/RouXx67/PulseUp/internal/models"
+)
+
+func testState() models.StateFrontend {
+	return models.StateFrontend{
+		Nodes: []models.NodeFrontend{
+			{ID: "lab-pve1", Instance: "lab"},
+			{ID: "prod-pve1", Instance: "prod"},
+		},
+		VMs: []models.VMFrontend{
+			{ID: "prod-pve1-100", VMID: 100, Instance: "prod", Pool: "team-a"},
+			{ID: "prod-pve1-101", VMID: 101, Instance: "prod", Tags: "web,Team-B"},
+			{ID: "prod-pve1-102", VMID: 102, Instance: "prod"},
+			{ID: "lab-pve1-100", VMID: 100, Instance: "lab"},
+		},
+		Containers: []models.ContainerFrontend{
+			{ID: "prod-pve1-200", VMID: 200, Instance: "prod", Pool: "team-a"},
+		},
+		DockerHosts: []models.DockerHostFrontend{{ID: "docker1"}},
+		PBS:         []models.PBSInstance{{ID: "pbs-backup", Name: "backup"}},
+		ActiveAlerts: []models.Alert{
+			{ID: "a1", ResourceID: "prod-pve1-100", Instance: "prod"},
+			{ID: "a2", ResourceID: "prod-pve1-102", Instance: "prod"},
+			{ID: "a3", ResourceID: "prod-pve1-100-before-upgrade", Instance: "prod"},
+			{ID: "a4", ResourceID: "lab-pve1", Instance: "lab"},
+		},
+		PVEBackups: models.PVEBackups{
+			BackupTasks: []models.BackupTask{
+				{ID: "prod-UPID:pve1:1", VMID: 100},
+				{ID: "prod-UPID:pve1:2", VMID: 102},
+			},
+			StorageBackups: []models.StorageBackup{
+				{ID: "b1", Instance: "prod", VMID: 200},
+				{ID: "b2", Instance: "prod", VMID: 102},
+			},
+			GuestSnapshots: []models.GuestSnapshot{{ID: "s1", Instance: "prod", VMID: 101}},
+		},
+		PBSBackups: []models.PBSBackup{
+			{ID: "p1", Instance: "backup", VMID: "100"}, // VMID 100 also exists in the lab instance
+			{ID: "p2", Instance: "backup", VMID: "200"},
+		},
+		ConnectionHealth: map[string]bool{"prod": true, "lab": true, "pbs-backup": true},
+	}
+}
+
+func TestNilScopeIsUnrestricted(t *testing.T) {
+	var scope *Scope
+	state := testState()
+	if filtered := scope.FilterFrontend(state); len(filtered.VMs) != len(state.VMs) || len(filtered.DockerHosts) != 1 {
+		t.Fatal("expected nil scope to return the state unchanged")
+	}
+	if New(nil) != nil || scope.Key() != "" {
+		t.Fatal("expected no scopes to produce an unrestricted scope")
+	}
+}
+
+func TestFilterFrontendByPoolAndTag(t *testing.T) {
+	scope := New([]config.TenantScope{
+		{ID: "b", Tags: []string{"team-b"}},
+		{ID: "a", Pools: []string{"team-a"}},
+	})
+	if scope.Key() != "a,b" {
+		t.Fatalf("key = %q, want sorted scope IDs", scope.Key())
+	}
+
+	filtered := scope.FilterFrontend(testState())
+
+	if len(filtered.VMs) != 2 || len(filtered.Containers) != 1 {
+		t.Fatalf("expected 2 VMs and 1 container, got %+v / %+v", filtered.VMs, filtered.Containers)
+	}
+	if len(filtered.Nodes) != 0 || len(filtered.DockerHosts) != 0 || len(filtered.PBS) != 0 || len(filtered.ConnectionHealth) != 1 {
+		t.Fatalf("expected infrastructure outside the scope to be hidden: %+v", filtered)
+	}
+	if len(filtered.ActiveAlerts) != 2 || filtered.ActiveAlerts[0].ID != "a1" || filtered.ActiveAlerts[1].ID != "a3" {
+		t.Fatalf("expected guest and snapshot alerts only, got %+v", filtered.ActiveAlerts)
+	}
+	backups := filtered.PVEBackups
+	if len(backups.BackupTasks) != 1 || len(backups.StorageBackups) != 1 || backups.StorageBackups[0].ID != "b1" || len(backups.GuestSnapshots) != 1 {
+		t.Fatalf("unexpected PVE backups: %+v", backups)
+	}
+	// VMID 100 is ambiguous across instances, 200 is only the visible container
+	if len(filtered.PBSBackups) != 1 || filtered.PBSBackups[0].ID != "p2" {
+		t.Fatalf("unexpected PBS backups: %+v", filtered.PBSBackups)
+	}
+}
+
+func TestFilterFrontendByInstance(t *testing.T) {
+	scope := New([]config.TenantScope{{ID: "lab", Instances: []string{"lab", "backup"}}})
+	filtered := scope.FilterFrontend(testState())
+
+	if len(filtered.Nodes) != 1 || filtered.Nodes[0].Instance != "lab" {
+		t.Fatalf("unexpected nodes: %+v", filtered.Nodes)
+	}
+	if len(filtered.VMs) != 1 || filtered.VMs[0].ID != "lab-pve1-100" {
+		t.Fatalf("unexpected VMs: %+v", filtered.VMs)
+	}
+	if len(filtered.PBS) != 1 || len(filtered.PBSBackups) != 2 {
+		t.Fatalf("expected the PBS instance in scope with all its backups")
+	}
+	if len(filtered.ActiveAlerts) != 1 || filtered.ActiveAlerts[0].ID != "a4" {
+		t.Fatalf("unexpected alerts: %+v", filtered.ActiveAlerts)
+	}
+	if !filtered.ConnectionHealth["lab"] || !filtered.ConnectionHealth["pbs-backup"] || len(filtered.ConnectionHealth) != 2 {
+		t.Fatalf("unexpected connection health: %+v", filtered.ConnectionHealth)
+	}
+}
+
+func TestAllowsTask(t *testing.T) {
+	scope := New([]config.TenantScope{{ID: "a", Pools: []string{"team-a"}}})
+	guests := scope.Guests(models.StateSnapshot{
+		VMs: []models.VM{{ID: "prod-pve1-100", VMID: 100, Instance: "prod", Pool: "team-a"}},
+	})
+
+	if !scope.AllowsTask(models.ClusterTask{Instance: "prod", VMID: 100}, guests) {
+		t.Error("expected task of visible guest to be allowed")
+	}
+	if scope.AllowsTask(models.ClusterTask{Instance: "lab", VMID: 100}, guests) {
+		t.Error("expected task of same VMID on another instance to be hidden")
+	}
+	if scope.AllowsTask(models.ClusterTask{Instance: "prod", Type: "aptupdate"}, guests) {
+		t.Error("expected node tasks to be hidden from guest scopes")
+	}
+}
+
+func TestFilterSnapshotMatchesFrontend(t *testing.T) {
+	scope := New([]config.TenantScope{{ID: "a", Pools: []string{"team-a"}}})
+	snapshot := models.StateSnapshot{
+		Nodes: []models.Node{{ID: "prod-pve1", Instance: "prod"}},
+		VMs: []models.VM{
+			{ID: "prod-pve1-100", VMID: 100, Instance: "prod", Pool: "team-a"},
+			{ID: "prod-pve1-101", VMID: 101, Instance: "prod"},
+		},
+		ActiveAlerts: []models.Alert{
+			{ID: "a1", ResourceID: "prod-pve1-100", Instance: "prod"},
+			{ID: "a2", ResourceID: "prod-pve1-101", Instance: "prod"},
+		},
+		RecentlyResolved: []models.ResolvedAlert{{Alert: models.Alert{ID: "a3", ResourceID: "prod-pve1", Instance: "prod"}}},
+		ConnectionHealth: map[string]bool{"prod": true},
+	}
+
+	filtered := scope.FilterSnapshot(snapshot)
+	if len(filtered.Nodes) != 0 || len(filtered.VMs) != 1 || filtered.VMs[0].ID != "prod-pve1-100" {
+		t.Fatalf("unexpected resources: %+v / %+v", filtered.Nodes, filtered.VMs)
+	}
+	if len(filtered.ActiveAlerts) != 1 || filtered.ActiveAlerts[0].ID != "a1" || len(filtered.RecentlyResolved) != 0 {
+		t.Fatalf("unexpected alerts: %+v / %+v", filtered.ActiveAlerts, filtered.RecentlyResolved)
+	}
+	if !filtered.ConnectionHealth["prod"] {
+		t.Fatal("expected the guest's instance to stay visible in connection health")
+	}
+}
@@ -0,0 +1,381 @@
+package tlscert
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	// acmeCheckInterval is how often the certificate is checked for renewal
+	acmeCheckInterval = 12 * time.Hour
+	// acmeRetryInterval is how long to wait after a failed order before trying again
+	acmeRetryInterval = time.Hour
+	// acmeOrderTimeout bounds a single order, including challenge validation
+	acmeOrderTimeout = 10 * time.Minute
+
+	http01Prefix = "/.well-known/acme-challenge/"
+)
+
+// Issuer obtains and renews the store's certificate from an ACME certificate authority. The
+// certificate and key are written to the store's files, so they survive restarts and can be
+// used by other tools as well.
+type Issuer struct {
+	cfg      config.ACMEConfig
+	store    *Store
+	client   *acme.Client
+	dns      DNSProvider
+	resolver *net.Resolver
+
+	registered bool
+	// shouldRenew, when set, limits ordering to when it returns true
+	shouldRenew func() bool
+
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+// NewIssuer creates an issuer for cfg. The ACME account key is kept in dataDir/acme.
+func NewIssuer(cfg config.ACMEConfig, dataDir string, store *Store) (*Issuer, error) {
+	key, err := loadOrCreateAccountKey(filepath.Join(dataDir, "acme", "account.key"))
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := acmeHTTPClient(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	issuer := &Issuer{
+		cfg:   cfg,
+		store: store,
+		client: &acme.Client{
+			Key:          key,
+			DirectoryURL: cfg.DirectoryURL,
+			HTTPClient:   httpClient,
+			UserAgent:    "pulse",
+		},
+		resolver: net.DefaultResolver,
+		tokens:   make(map[string]string),
+	}
+	if cfg.Challenge == config.ACMEChallengeDNS01 {
+		if issuer.dns, err = NewDNSProvider(cfg.DNSProvider, cfg.DNSOptions); err != nil {
+			return nil, err
+		}
+	}
+	return issuer, nil
+}
+
+// SetRenewCondition limits ordering certificates to when fn returns true, e.g. only on the
+// active instance of a high availability pair, whose certificate files the standby replicates
+func (i *Issuer) SetRenewCondition(fn func() bool) {
+	i.shouldRenew = fn
+}
+
+// HTTPHandler answers HTTP-01 challenges and returns 404 for any other request
+func (i *Issuer) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.URL.Path, http01Prefix)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		i.mu.RLock()
+		response, found := i.tokens[token]
+		i.mu.RUnlock()
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(response))
+	})
+}
+
+// Run obtains a certificate when none is served yet and renews it before it expires, until ctx
+// is done
+func (i *Issuer) Run(ctx context.Context) {
+	for {
+		wait := acmeCheckInterval
+		active := i.shouldRenew == nil || i.shouldRenew()
+		if active && needsRenewal(i.store.Leaf(), i.cfg.Domains, i.cfg.RenewBefore, time.Now()) {
+			if err := i.Obtain(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Error().Err(err).Strs("domains", i.cfg.Domains).Msg("Failed to obtain ACME certificate, will retry")
+				wait = acmeRetryInterval
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Obtain orders a new certificate for the configured domains and installs it in the store
+func (i *Issuer) Obtain(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, acmeOrderTimeout)
+	defer cancel()
+
+	if err := i.register(ctx); err != nil {
+		return err
+	}
+	log.Info().Strs("domains", i.cfg.Domains).Str("challenge", i.cfg.Challenge).Msg("Ordering ACME certificate")
+
+	order, err := i.client.AuthorizeOrder(ctx, acme.DomainIDs(i.cfg.Domains...))
+	if err != nil {
+		return fmt.Errorf("create order: %w", err)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := i.authorize(ctx, authzURL); err != nil {
+			return err
+		}
+	}
+	if order, err = i.client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("wait for order: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: i.cfg.Domains[0]},
+		DNSNames: i.cfg.Domains,
+	}, key)
+	if err != nil {
+		return err
+	}
+	chain, _, err := i.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("finalize order: %w", err)
+	}
+
+	if err := i.install(chain, key); err != nil {
+		return err
+	}
+	if _, err := i.store.Reload(); err != nil {
+		return err
+	}
+	log.Info().Strs("domains", i.cfg.Domains).Msg("Installed ACME certificate")
+	return nil
+}
+
+func (i *Issuer) register(ctx context.Context) error {
+	if i.registered {
+		return nil
+	}
+	account := &acme.Account{}
+	if i.cfg.Email != "" {
+		account.Contact = []string{"mailto:" + i.cfg.Email}
+	}
+	if _, err := i.client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("register ACME account: %w", err)
+	}
+	i.registered = true
+	return nil
+}
+
+func (i *Issuer) authorize(ctx context.Context, authzURL string) error {
+	authz, err := i.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("get authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == i.cfg.Challenge {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("%s: the CA offers no %s challenge", authz.Identifier.Value, i.cfg.Challenge)
+	}
+
+	cleanup, err := i.fulfill(ctx, authz.Identifier.Value, challenge)
+	if err != nil {
+		return fmt.Errorf("%s: %w", authz.Identifier.Value, err)
+	}
+	defer cleanup()
+
+	if _, err := i.client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("%s: accept challenge: %w", authz.Identifier.Value, err)
+	}
+	if _, err := i.client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("%s: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+// fulfill publishes the challenge response and returns a function that withdraws it
+func (i *Issuer) fulfill(ctx context.Context, domain string, challenge *acme.Challenge) (func(), error) {
+	switch challenge.Type {
+	case config.ACMEChallengeHTTP01:
+		response, err := i.client.HTTP01ChallengeResponse(challenge.Token)
+		if err != nil {
+			return nil, err
+		}
+		i.mu.Lock()
+		i.tokens[challenge.Token] = response
+		i.mu.Unlock()
+		return func() {
+			i.mu.Lock()
+			delete(i.tokens, challenge.Token)
+			i.mu.Unlock()
+		}, nil
+
+	case config.ACMEChallengeDNS01:
+		value, err := i.client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return nil, err
+		}
+		fqdn := "_acme-challenge." + domain + "."
+		if err := i.dns.Present(ctx, fqdn, value); err != nil {
+			return nil, fmt.Errorf("create TXT record: %w", err)
+		}
+		if err := waitForTXT(ctx, i.resolver, fqdn, value, i.cfg.DNSPropagationTimeout); err != nil {
+			log.Warn().Err(err).Msg("Continuing with ACME validation anyway")
+		}
+		return func() {
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := i.dns.CleanUp(cleanupCtx, fqdn, value); err != nil {
+				log.Warn().Err(err).Str("record", fqdn).Msg("Failed to remove ACME TXT record")
+			}
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported challenge %s", challenge.Type)
+}
+
+// install writes the key before the chain so a reload between the two writes fails to pair them
+// and keeps the previous certificate
+func (i *Issuer) install(chain [][]byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	if err := writeFileAtomic(i.store.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return fmt.Errorf("write TLS key: %w", err)
+	}
+	if err := writeFileAtomic(i.store.certFile, certPEM, 0o644); err != nil {
+		return fmt.Errorf("write TLS certificate: %w", err)
+	}
+	return nil
+}
+
+// needsRenewal reports whether leaf is missing, does not cover domains, or is within its renewal
+// window. Short-lived certificates are renewed after two thirds of their lifetime.
+func needsRenewal(leaf *x509.Certificate, domains []string, renewBefore time.Duration, now time.Time) bool {
+	if leaf == nil {
+		return true
+	}
+	covered := make(map[string]bool, len(leaf.DNSNames))
+	for _, name := range leaf.DNSNames {
+		covered[strings.ToLower(name)] = true
+	}
+	for _, domain := range domains {
+		if !covered[domain] {
+			return true
+		}
+	}
+	if lifetime := leaf.NotAfter.Sub(leaf.NotBefore); lifetime/3 < renewBefore {
+		renewBefore = lifetime / 3
+	}
+	return !now.Add(renewBefore).Before(leaf.NotAfter)
+}
+
+func loadOrCreateAccountKey(path string) (crypto.Signer, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("ACME account key %s is not PEM encoded", path)
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, fmt.Errorf("write ACME account key: %w", err)
+	}
+	return key, nil
+}
+
+// acmeHTTPClient trusts caFile in addition to the system roots, for private or test CAs
+func acmeHTTPClient(caFile string) (*http.Client, error) {
+	if caFile == "" {
+		return &http.Client{Timeout: time.Minute}, nil
+	}
+	pemData, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("read ACME_CA_FILE: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("ACME_CA_FILE %s contains no certificates", caFile)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool}
+	return &http.Client{Timeout: time.Minute, Transport: transport}, nil
+}
+
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Chmod(perm)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,301 @@
+package tlscert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DNSProvider creates and removes the TXT records that answer DNS-01 challenges. fqdn is the full
+// record name including the trailing dot, e.g. "_acme-challenge.pulse.example.com.".
+type DNSProvider interface {
+	Present(ctx context.Context, fqdn, value string) error
+	CleanUp(ctx context.Context, fqdn, value string) error
+}
+
+// DNSProviderFactory creates a provider from its ACME_DNS_<KEY> options, keyed by lowercase key
+type DNSProviderFactory func(options map[string]string) (DNSProvider, error)
+
+var (
+	dnsProvidersMu sync.RWMutex
+	dnsProviders   = map[string]DNSProviderFactory{
+		"exec":       newExecProvider,
+		"webhook":    newWebhookProvider,
+		"cloudflare": newCloudflareProvider,
+	}
+)
+
+// RegisterDNSProvider makes a DNS-01 provider available under name
+func RegisterDNSProvider(name string, factory DNSProviderFactory) {
+	dnsProvidersMu.Lock()
+	defer dnsProvidersMu.Unlock()
+	dnsProviders[strings.ToLower(name)] = factory
+}
+
+// NewDNSProvider creates the registered provider called name
+func NewDNSProvider(name string, options map[string]string) (DNSProvider, error) {
+	dnsProvidersMu.RLock()
+	factory, ok := dnsProviders[strings.ToLower(name)]
+	names := make([]string, 0, len(dnsProviders))
+	for registered := range dnsProviders {
+		names = append(names, registered)
+	}
+	dnsProvidersMu.RUnlock()
+	if !ok {
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown DNS provider %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return factory(options)
+}
+
+// execProvider runs "<path> present|cleanup <fqdn> <value>" so any DNS API can be scripted
+type execProvider struct {
+	path string
+}
+
+func newExecProvider(options map[string]string) (DNSProvider, error) {
+	if options["exec_path"] == "" {
+		return nil, fmt.Errorf("ACME_DNS_EXEC_PATH is required for the exec DNS provider")
+	}
+	return &execProvider{path: options["exec_path"]}, nil
+}
+
+func (p *execProvider) Present(ctx context.Context, fqdn, value string) error {
+	return p.run(ctx, "present", fqdn, value)
+}
+
+func (p *execProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	return p.run(ctx, "cleanup", fqdn, value)
+}
+
+func (p *execProvider) run(ctx context.Context, action, fqdn, value string) error {
+	output, err := exec.CommandContext(ctx, p.path, action, fqdn, value).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", p.path, action, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// webhookProvider posts {"action","fqdn","value"} to a URL that manages the record
+type webhookProvider struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newWebhookProvider(options map[string]string) (DNSProvider, error) {
+	u, err := url.Parse(options["webhook_url"])
+	if options["webhook_url"] == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("ACME_DNS_WEBHOOK_URL must be an http(s) URL for the webhook DNS provider")
+	}
+	return &webhookProvider{
+		url:    options["webhook_url"],
+		token:  options["webhook_token"],
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (p *webhookProvider) Present(ctx context.Context, fqdn, value string) error {
+	return p.send(ctx, "present", fqdn, value)
+}
+
+func (p *webhookProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	return p.send(ctx, "cleanup", fqdn, value)
+}
+
+func (p *webhookProvider) send(ctx context.Context, action, fqdn, value string) error {
+	body, err := json.Marshal(map[string]string{"action": action, "fqdn": fqdn, "value": value})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("DNS webhook %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("DNS webhook %s: %s: %s", action, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+const cloudflareAPIURL = "https://api.cloudflare.com/client/v4"
+
+// cloudflareProvider manages TXT records through the Cloudflare API with a token that has
+// Zone:DNS:Edit permission
+type cloudflareProvider struct {
+	baseURL string
+	token   string
+	zoneID  string
+	client  *http.Client
+
+	mu      sync.Mutex
+	records map[string]string
+}
+
+func newCloudflareProvider(options map[string]string) (DNSProvider, error) {
+	if options["cloudflare_api_token"] == "" {
+		return nil, fmt.Errorf("ACME_DNS_CLOUDFLARE_API_TOKEN is required for the cloudflare DNS provider")
+	}
+	baseURL := cloudflareAPIURL
+	if options["cloudflare_api_url"] != "" {
+		baseURL = strings.TrimRight(options["cloudflare_api_url"], "/")
+	}
+	return &cloudflareProvider{
+		baseURL: baseURL,
+		token:   options["cloudflare_api_token"],
+		zoneID:  options["cloudflare_zone_id"],
+		client:  &http.Client{Timeout: 30 * time.Second},
+		records: make(map[string]string),
+	}, nil
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+func (p *cloudflareProvider) Present(ctx context.Context, fqdn, value string) error {
+	zoneID, err := p.zone(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+	var record struct {
+		ID string `json:"id"`
+	}
+	err = p.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", map[string]any{
+		"type":    "TXT",
+		"name":    strings.TrimSuffix(fqdn, "."),
+		"content": value,
+		"ttl":     120,
+	}, &record)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.records[fqdn+" "+value] = record.ID
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *cloudflareProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	p.mu.Lock()
+	id := p.records[fqdn+" "+value]
+	delete(p.records, fqdn+" "+value)
+	p.mu.Unlock()
+	if id == "" {
+		return nil
+	}
+	zoneID, err := p.zone(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+	return p.do(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+id, nil, nil)
+}
+
+// zone finds the zone holding fqdn by trying each parent domain, unless a zone ID is configured
+func (p *cloudflareProvider) zone(ctx context.Context, fqdn string) (string, error) {
+	if p.zoneID != "" {
+		return p.zoneID, nil
+	}
+	labels := strings.Split(strings.TrimSuffix(fqdn, "."), ".")
+	for i := 1; i < len(labels)-1; i++ {
+		var zones []struct {
+			ID string `json:"id"`
+		}
+		name := strings.Join(labels[i:], ".")
+		if err := p.do(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(name), nil, &zones); err != nil {
+			return "", err
+		}
+		if len(zones) > 0 {
+			return zones[0].ID, nil
+		}
+	}
+	return "", fmt.Errorf("no Cloudflare zone found for %s", fqdn)
+}
+
+func (p *cloudflareProvider) do(ctx context.Context, method, path string, payload, result any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudflare %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded cloudflareResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&decoded); err != nil {
+		return fmt.Errorf("cloudflare %s %s: %s", method, path, resp.Status)
+	}
+	if !decoded.Success {
+		messages := make([]string, 0, len(decoded.Errors))
+		for _, e := range decoded.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("cloudflare %s %s: %s", method, path, strings.Join(messages, "; "))
+	}
+	if result != nil && len(decoded.Result) > 0 {
+		return json.Unmarshal(decoded.Result, result)
+	}
+	return nil
+}
+
+// waitForTXT polls public DNS until fqdn carries value or timeout elapses. A record that never
+// becomes visible locally is not fatal: the CA resolves it independently, so only a warning is
+// returned.
+func waitForTXT(ctx context.Context, resolver *net.Resolver, fqdn, value string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		records, _ := resolver.LookupTXT(ctx, fqdn)
+		for _, record := range records {
+			if record == value {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("TXT record %s not visible after %s", fqdn, timeout)
+		case <-ticker.C:
+		}
+	}
+}
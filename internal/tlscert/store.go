@@ -0,0 +1,170 @@
+// Package tlscert serves Pulse's own HTTPS certificate, reloading it from disk when it changes
+// and optionally obtaining and renewing it from an ACME certificate authority.
+package tlscert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Store holds the certificate served by the HTTPS listener. Handshakes read it through
+// GetCertificate, so replacing the files on disk and calling Reload takes effect without a
+// restart.
+type Store struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	leaf    *x509.Certificate
+	modTime time.Time
+}
+
+// NewStore loads the certificate pair. While the files do not exist yet, which is the case until
+// ACME issues the first certificate, a self-signed placeholder is served instead.
+func NewStore(certFile, keyFile string) (*Store, error) {
+	s := &Store{certFile: certFile, keyFile: keyFile}
+	if _, err := os.Stat(certFile); os.IsNotExist(err) {
+		placeholder, err := selfSigned()
+		if err != nil {
+			return nil, err
+		}
+		s.cert = placeholder
+		log.Warn().Str("certFile", certFile).Msg("TLS certificate not found, serving a self-signed placeholder")
+		return s, nil
+	}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (s *Store) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cert, nil
+}
+
+// TLSConfig returns a server configuration that serves the store's certificate
+func (s *Store) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: s.GetCertificate,
+	}
+}
+
+// Leaf returns the parsed certificate being served, or nil while the placeholder is in use
+func (s *Store) Leaf() *x509.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.leaf
+}
+
+// Reload reads the certificate pair again when the files changed since the last load and reports
+// whether a new certificate is now served. On error the previous certificate stays in place.
+func (s *Store) Reload() (bool, error) {
+	modTime, err := s.latestModTime()
+	if err != nil {
+		return false, err
+	}
+	s.mu.RLock()
+	unchanged := s.leaf != nil && modTime.Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return false, fmt.Errorf("parse TLS certificate: %w", err)
+	}
+	cert.Leaf = leaf
+
+	s.mu.Lock()
+	s.cert = &cert
+	s.leaf = leaf
+	s.modTime = modTime
+	s.mu.Unlock()
+
+	log.Info().
+		Str("subject", leaf.Subject.CommonName).
+		Strs("dnsNames", leaf.DNSNames).
+		Time("notAfter", leaf.NotAfter).
+		Msg("Loaded TLS certificate")
+	return true, nil
+}
+
+// Watch reloads the certificate every interval until ctx is done. onLeaf, when set, is called
+// with the served certificate after every check so callers can track its expiry.
+func (s *Store) Watch(ctx context.Context, interval time.Duration, onLeaf func(*x509.Certificate)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Reload(); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to reload TLS certificate, keeping the current one")
+		}
+		if leaf := s.Leaf(); leaf != nil && onLeaf != nil {
+			onLeaf(leaf)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Store) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{s.certFile, s.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func selfSigned() (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "Pulse (certificate pending)"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
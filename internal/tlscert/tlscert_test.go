@@ -0,0 +1,262 @@
+package tlscert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestCert(t *testing.T, certFile, keyFile, name string, notAfter time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoreReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	store, err := NewStore(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewStore without files: %v", err)
+	}
+	if store.Leaf() != nil {
+		t.Fatal("expected the placeholder to have no leaf")
+	}
+	if cert, _ := store.GetCertificate(nil); cert == nil {
+		t.Fatal("expected a placeholder certificate")
+	}
+
+	writeTestCert(t, certFile, keyFile, "one.example.com", time.Now().Add(90*24*time.Hour))
+	if changed, err := store.Reload(); err != nil || !changed {
+		t.Fatalf("Reload = %v, %v", changed, err)
+	}
+	if store.Leaf().Subject.CommonName != "one.example.com" {
+		t.Fatalf("unexpected leaf %s", store.Leaf().Subject.CommonName)
+	}
+	if changed, err := store.Reload(); err != nil || changed {
+		t.Fatalf("Reload of unchanged files = %v, %v", changed, err)
+	}
+
+	writeTestCert(t, certFile, keyFile, "two.example.com", time.Now().Add(90*24*time.Hour))
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(certFile, future, future)
+	if _, err := store.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if cert, _ := store.GetCertificate(nil); cert.Leaf.Subject.CommonName != "two.example.com" {
+		t.Fatalf("handshakes still get %s", cert.Leaf.Subject.CommonName)
+	}
+
+	if err := os.WriteFile(certFile, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := future.Add(time.Minute)
+	_ = os.Chtimes(certFile, later, later)
+	if _, err := store.Reload(); err == nil {
+		t.Fatal("expected an invalid certificate to fail reloading")
+	}
+	if store.Leaf().Subject.CommonName != "two.example.com" {
+		t.Fatal("expected the previous certificate to stay in place")
+	}
+	if _, err := NewStore(certFile, keyFile); err == nil {
+		t.Fatal("expected NewStore to reject invalid existing files")
+	}
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	now := time.Now()
+	leaf := &x509.Certificate{
+		DNSNames:  []string{"pulse.example.com"},
+		NotBefore: now.Add(-30 * 24 * time.Hour),
+		NotAfter:  now.Add(60 * 24 * time.Hour),
+	}
+	domains := []string{"pulse.example.com"}
+	if needsRenewal(leaf, domains, 30*24*time.Hour, now) {
+		t.Fatal("a certificate with 60 days left should not be renewed")
+	}
+	if !needsRenewal(leaf, domains, 30*24*time.Hour, now.Add(31*24*time.Hour)) {
+		t.Fatal("a certificate inside the renewal window should be renewed")
+	}
+	if !needsRenewal(leaf, []string{"pulse.example.com", "other.example.com"}, time.Hour, now) {
+		t.Fatal("a certificate missing a domain should be renewed")
+	}
+	if !needsRenewal(nil, domains, time.Hour, now) {
+		t.Fatal("a missing certificate should be obtained")
+	}
+	shortLived := &x509.Certificate{DNSNames: domains, NotBefore: now, NotAfter: now.Add(6 * 24 * time.Hour)}
+	if needsRenewal(shortLived, domains, 30*24*time.Hour, now.Add(3*24*time.Hour)) {
+		t.Fatal("a short-lived certificate should be kept for two thirds of its lifetime")
+	}
+	if !needsRenewal(shortLived, domains, 30*24*time.Hour, now.Add(5*24*time.Hour)) {
+		t.Fatal("a short-lived certificate should be renewed after two thirds of its lifetime")
+	}
+}
+
+func TestHTTPHandlerServesChallenges(t *testing.T) {
+	issuer := &Issuer{tokens: map[string]string{"abc": "abc.thumbprint"}}
+	handler := issuer.HTTPHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/acme-challenge/abc", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "abc.thumbprint" {
+		t.Fatalf("challenge response = %d %q", rec.Code, rec.Body.String())
+	}
+	for _, path := range []string{"/.well-known/acme-challenge/unknown", "/api/health"} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s = %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func TestWebhookProvider(t *testing.T) {
+	var received []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body)
+	}))
+	defer server.Close()
+
+	provider, err := NewDNSProvider("webhook", map[string]string{"webhook_url": server.URL, "webhook_token": "token"})
+	if err != nil {
+		t.Fatalf("NewDNSProvider: %v", err)
+	}
+	ctx := context.Background()
+	if err := provider.Present(ctx, "_acme-challenge.pulse.example.com.", "value"); err != nil {
+		t.Fatalf("Present: %v", err)
+	}
+	if err := provider.CleanUp(ctx, "_acme-challenge.pulse.example.com.", "value"); err != nil {
+		t.Fatalf("CleanUp: %v", err)
+	}
+	if len(received) != 2 || received[0]["action"] != "present" || received[1]["action"] != "cleanup" ||
+		received[0]["fqdn"] != "_acme-challenge.pulse.example.com." || received[0]["value"] != "value" {
+		t.Fatalf("unexpected webhook calls: %+v", received)
+	}
+
+	unauthorized, _ := NewDNSProvider("webhook", map[string]string{"webhook_url": server.URL})
+	if err := unauthorized.Present(ctx, "_acme-challenge.pulse.example.com.", "value"); err == nil {
+		t.Fatal("expected a rejected webhook call to fail")
+	}
+}
+
+func TestExecProvider(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "calls")
+	script := filepath.Join(dir, "hook.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$1 $2 $3\" >> "+logFile+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := NewDNSProvider("exec", map[string]string{"exec_path": script})
+	if err != nil {
+		t.Fatalf("NewDNSProvider: %v", err)
+	}
+	ctx := context.Background()
+	if err := provider.Present(ctx, "_acme-challenge.a.example.com.", "v1"); err != nil {
+		t.Fatalf("Present: %v", err)
+	}
+	if err := provider.CleanUp(ctx, "_acme-challenge.a.example.com.", "v1"); err != nil {
+		t.Fatalf("CleanUp: %v", err)
+	}
+	calls, _ := os.ReadFile(logFile)
+	want := "present _acme-challenge.a.example.com. v1\ncleanup _acme-challenge.a.example.com. v1\n"
+	if string(calls) != want {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+	if _, err := NewDNSProvider("exec", nil); err == nil {
+		t.Fatal("expected the exec provider to require a path")
+	}
+}
+
+func TestCloudflareProvider(t *testing.T) {
+	var created, deleted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := any(nil)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/zones":
+			if r.URL.Query().Get("name") == "example.com" {
+				result = []map[string]string{{"id": "zone-1"}}
+			} else {
+				result = []map[string]string{}
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/zones/zone-1/dns_records":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			created = body["name"].(string) + "=" + body["content"].(string)
+			result = map[string]string{"id": "record-1"}
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/zones/zone-1/dns_records/"):
+			deleted = strings.TrimPrefix(r.URL.Path, "/zones/zone-1/dns_records/")
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"success": false, "errors": []map[string]string{{"message": "not found"}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "result": result})
+	}))
+	defer server.Close()
+
+	provider, err := NewDNSProvider("cloudflare", map[string]string{
+		"cloudflare_api_token": "token",
+		"cloudflare_api_url":   server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewDNSProvider: %v", err)
+	}
+	ctx := context.Background()
+	if err := provider.Present(ctx, "_acme-challenge.pulse.example.com.", "value"); err != nil {
+		t.Fatalf("Present: %v", err)
+	}
+	if created != "_acme-challenge.pulse.example.com=value" {
+		t.Fatalf("created %q", created)
+	}
+	if err := provider.CleanUp(ctx, "_acme-challenge.pulse.example.com.", "value"); err != nil {
+		t.Fatalf("CleanUp: %v", err)
+	}
+	if deleted != "record-1" {
+		t.Fatalf("deleted %q", deleted)
+	}
+	if _, err := NewDNSProvider("route53", nil); err == nil {
+		t.Fatal("expected an unknown provider to be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func TestTransportNamesSpansAfterAPIPath(t *testing.T) {
+	recorder := recordSpans(t)
+	var sawTraceHeader bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawTraceHeader = r.Header.Get("traceparent") != ""
+	}))
+	defer server.Close()
+
+	ctx, parent := Start(context.Background(), "poll pve")
+	client := &http.Client{Transport: Transport("PVE", http.DefaultTransport)}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api2/json/nodes/pve1/qemu?full=1", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	call := spans[0]
+	if call.Name() != "PVE GET /nodes/pve1/qemu" {
+		t.Fatalf("span name = %q", call.Name())
+	}
+	if call.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("expected the API call to be a child of the poll span")
+	}
+	if sawTraceHeader {
+		t.Fatal("expected no trace headers to be sent to Proxmox")
+	}
+}
+
+func TestServerSpanContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/nodes/pve1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, span := StartServer(req, "/api/nodes/:name")
+	EndServer(span, http.StatusBadGateway)
+
+	ended := recorder.Ended()[0]
+	if ended.Name() != "GET /api/nodes/:name" {
+		t.Fatalf("span name = %q", ended.Name())
+	}
+	if ended.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatal("expected the server span to continue the caller's trace")
+	}
+	if ended.Status().Code != codes.Error {
+		t.Fatal("expected a 5xx response to mark the span as failed")
+	}
+}
+
+func TestEndRecordsError(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := Start(context.Background(), "poll pbs")
+	End(span, errors.New("connection refused"))
+
+	ended := recorder.Ended()[0]
+	if ended.Status().Code != codes.Error || ended.Status().Description != "connection refused" {
+		t.Fatalf("unexpected status %+v", ended.Status())
+	}
+	if len(ended.Events()) != 1 {
+		t.Fatal("expected the error to be recorded as an event")
+	}
+}
@@ -16,17 +16,23 @@ import (
 
 // InstallShAdapter wraps the install.sh script for systemd/LXC deployments
 type InstallShAdapter struct {
-	history        *UpdateHistory
+	history          *UpdateHistory
 	installScriptURL string
-	logDir         string
+	logDir           string
+	healthURL        string
+	serviceControl   serviceControlFunc // runs service actions for an unprivileged supervisor
 }
 
+// defaultHealthURL is the health endpoint of a default installation
+const defaultHealthURL = "http://localhost:7655/api/health"
+
 // NewInstallShAdapter creates a new install.sh adapter
 func NewInstallShAdapter(history *UpdateHistory) *InstallShAdapter {
 	return &InstallShAdapter{
-		history:        history,
+		history:          history,
 		installScriptURL: "https://raw.githubusercontent.com/RouXx67/PulseUP/main/install.sh",
-		logDir:         "/var/log/pulse",
+		logDir:           "/var/log/pulse",
+		healthURL:        defaultHealthURL,
 	}
 }
 
@@ -404,14 +410,12 @@ func (a *InstallShAdapter) downloadBinary(ctx context.Context, version string) (
 
 // stopService stops the Pulse service
 func (a *InstallShAdapter) stopService(ctx context.Context, serviceName string) error {
-	cmd := exec.CommandContext(ctx, "systemctl", "stop", serviceName)
-	return cmd.Run()
+	return a.systemctl(ctx, "stop", serviceName)
 }
 
 // startService starts the Pulse service
 func (a *InstallShAdapter) startService(ctx context.Context, serviceName string) error {
-	cmd := exec.CommandContext(ctx, "systemctl", "start", serviceName)
-	return cmd.Run()
+	return a.systemctl(ctx, "start", serviceName)
 }
 
 // restoreConfig restores configuration from backup
@@ -426,6 +430,19 @@ func (a *InstallShAdapter) restoreConfig(ctx context.Context, backupPath, target
 	return cmd.Run()
 }
 
+// restartService restarts the Pulse service
+func (a *InstallShAdapter) restartService(ctx context.Context, serviceName string) error {
+	return a.systemctl(ctx, "restart", serviceName)
+}
+
+// systemctl runs a service action, through the root supervisor when one is attached
+func (a *InstallShAdapter) systemctl(ctx context.Context, action, serviceName string) error {
+	if a.serviceControl != nil {
+		return a.serviceControl(ctx, action, serviceName)
+	}
+	return exec.CommandContext(ctx, "systemctl", action, serviceName).Run()
+}
+
 // installBinary installs a binary to the target location
 func (a *InstallShAdapter) installBinary(ctx context.Context, sourcePath, targetPath string) error {
 	// Backup current binary
@@ -453,8 +470,8 @@ func (a *InstallShAdapter) waitForHealth(ctx context.Context, timeout time.Durat
 	deadline := time.Now().Add(timeout)
 
 	for time.Now().Before(deadline) {
-		// Try to hit health endpoint
-		cmd := exec.CommandContext(ctx, "curl", "-fsS", "http://localhost:7655/api/health")
+		// Try to hit health endpoint; -k because a loopback request may not match the certificate
+		cmd := exec.CommandContext(ctx, "curl", "-fsSk", "--max-time", "5", a.healthURL)
 		if err := cmd.Run(); err == nil {
 			return nil
 		}
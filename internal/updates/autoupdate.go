@@ -0,0 +1,269 @@
+package updates
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	autoUpdateTick       = 10 * time.Minute
+	pendingHealthTimeout = 90 * time.Second
+	defaultWindowStart   = "03:00"
+	defaultWindowEnd     = "05:00"
+	autoUpdateTimerUnit  = "pulse-update.timer"
+
+	awaitingHealthCheckNote = "Awaiting health check after restart"
+)
+
+// AutoUpdatePolicy controls when updates are installed without user interaction
+type AutoUpdatePolicy struct {
+	Enabled       bool
+	Channel       string
+	CheckInterval time.Duration
+	WindowStart   int // minutes after local midnight
+	WindowEnd     int // minutes after local midnight; may be before WindowStart to span midnight
+	RolloutPeriod time.Duration
+	Rollback      bool
+}
+
+// PolicyFromConfig builds the automatic update policy from the running configuration
+func PolicyFromConfig(cfg *config.Config) AutoUpdatePolicy {
+	policy := AutoUpdatePolicy{
+		Enabled:       cfg.AutoUpdateEnabled,
+		Channel:       cfg.UpdateChannel,
+		CheckInterval: cfg.AutoUpdateCheckInterval,
+		RolloutPeriod: time.Duration(cfg.AutoUpdateRolloutHours) * time.Hour,
+		Rollback:      cfg.AutoUpdateRollback,
+	}
+	if policy.CheckInterval <= 0 {
+		policy.CheckInterval = 24 * time.Hour
+	}
+	if policy.RolloutPeriod < 0 {
+		policy.RolloutPeriod = 0
+	}
+
+	start, err := ParseWindowTime(cfg.AutoUpdateTime)
+	if err != nil {
+		start, _ = ParseWindowTime(defaultWindowStart)
+	}
+	end, err := ParseWindowTime(cfg.AutoUpdateWindowEnd)
+	if err != nil {
+		end, _ = ParseWindowTime(defaultWindowEnd)
+	}
+	policy.WindowStart = start
+	policy.WindowEnd = end
+	return policy
+}
+
+// ParseWindowTime parses a 24-hour "HH:MM" time into minutes after midnight
+func ParseWindowTime(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid hour in %q", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("invalid minute in %q", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// ValidateUpdateSource checks that a release mirror URL is usable. Empty means GitHub.
+func ValidateUpdateSource(source string) error {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil
+	}
+	parsed, err := url.Parse(source)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("updateSource must be an http or https URL")
+	}
+	return nil
+}
+
+// InWindow reports whether t falls inside the maintenance window.
+// Equal start and end times allow installs at any time of day.
+func (p AutoUpdatePolicy) InWindow(t time.Time) bool {
+	now := t.Hour()*60 + t.Minute()
+	switch {
+	case p.WindowStart == p.WindowEnd:
+		return true
+	case p.WindowStart < p.WindowEnd:
+		return now >= p.WindowStart && now < p.WindowEnd
+	default:
+		return now >= p.WindowStart || now < p.WindowEnd
+	}
+}
+
+// ReadyAt returns when this installation joins the staged rollout of a release.
+// Each host gets a stable offset within the rollout period so a bad release
+// reaches a fraction of installations before the rest.
+func (p AutoUpdatePolicy) ReadyAt(published time.Time, hostID string) time.Time {
+	if p.RolloutPeriod <= 0 || published.IsZero() {
+		return published
+	}
+	h := fnv.New32a()
+	h.Write([]byte(hostID))
+	minutes := int64(p.RolloutPeriod / time.Minute)
+	if minutes <= 0 {
+		return published
+	}
+	return published.Add(time.Duration(int64(h.Sum32())%minutes) * time.Minute)
+}
+
+// rolloutHostID identifies this installation for staged rollouts
+func rolloutHostID() string {
+	if data, err := os.ReadFile("/etc/machine-id"); err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id
+		}
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// autoUpdateTimerEnabled reports whether the install.sh systemd timer handles automatic updates
+func autoUpdateTimerEnabled() bool {
+	return exec.Command("systemctl", "is-enabled", "--quiet", autoUpdateTimerUnit).Run() == nil
+}
+
+// RunAutoUpdates installs updates according to the configured policy until ctx is cancelled
+func (m *Manager) RunAutoUpdates(ctx context.Context) {
+	ticker := time.NewTicker(autoUpdateTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.runAutoUpdate(ctx, time.Now())
+		}
+	}
+}
+
+// runAutoUpdate performs a single policy evaluation
+func (m *Manager) runAutoUpdate(ctx context.Context, now time.Time) {
+	policy := PolicyFromConfig(m.config)
+	if !policy.Enabled || !policy.InWindow(now) {
+		return
+	}
+	if !m.lastAutoCheck.IsZero() && now.Sub(m.lastAutoCheck) < policy.CheckInterval {
+		return
+	}
+	if autoUpdateTimerEnabled() {
+		log.Debug().Msg("Automatic updates are handled by the pulse-update timer")
+		return
+	}
+	if !m.canVerifyReleases() {
+		if !m.warnedNoKeys {
+			m.warnedNoKeys = true
+			log.Warn().Msg("Automatic updates are enabled but skipped: this build has no release signing key to verify releases")
+		}
+		return
+	}
+
+	info, err := m.CheckForUpdatesWithChannel(ctx, policy.Channel)
+	if err != nil {
+		log.Warn().Err(err).Msg("Automatic update check failed")
+		return
+	}
+	if !info.Available || info.DownloadURL == "" {
+		m.lastAutoCheck = now
+		return
+	}
+
+	if readyAt := policy.ReadyAt(info.ReleaseDate, rolloutHostID()); now.Before(readyAt) {
+		// Re-evaluate on the next tick inside the window rather than waiting a full interval
+		log.Info().
+			Str("version", info.LatestVersion).
+			Time("readyAt", readyAt).
+			Msg("Update available; waiting for staged rollout")
+		return
+	}
+
+	if m.previouslyFailed(info.LatestVersion) {
+		m.lastAutoCheck = now
+		log.Warn().Str("version", info.LatestVersion).Msg("Skipping automatic update: this version failed or was rolled back before")
+		return
+	}
+
+	m.lastAutoCheck = now
+	log.Info().Str("version", info.LatestVersion).Msg("Installing update inside maintenance window")
+	if err := m.ApplyUpdateWithOptions(ctx, info.DownloadURL, ApplyOptions{
+		InitiatedBy:  InitiatedByAuto,
+		InitiatedVia: InitiatedViaPolicy,
+		Channel:      policy.Channel,
+	}); err != nil {
+		log.Error().Err(err).Str("version", info.LatestVersion).Msg("Automatic update failed")
+	}
+}
+
+// previouslyFailed reports whether installing version already failed or was rolled back
+func (m *Manager) previouslyFailed(version string) bool {
+	if m.history == nil {
+		return false
+	}
+	version = strings.TrimPrefix(version, "v")
+	for _, entry := range m.history.ListEntries(HistoryFilter{Action: ActionUpdate}) {
+		if strings.TrimPrefix(entry.VersionTo, "v") != version {
+			continue
+		}
+		if entry.Status == StatusFailed || entry.Status == StatusRolledBack {
+			return true
+		}
+	}
+	return false
+}
+
+// recordHistory creates a history entry, returning its ID or "" when history is unavailable
+func (m *Manager) recordHistory(ctx context.Context, entry UpdateHistoryEntry) string {
+	if m.history == nil {
+		return ""
+	}
+	eventID, err := m.history.CreateEntry(ctx, entry)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to record update history")
+		return ""
+	}
+	return eventID
+}
+
+// updateHistoryEntry amends an in-progress history entry
+func (m *Manager) updateHistoryEntry(ctx context.Context, eventID string, fn func(*UpdateHistoryEntry)) {
+	if m.history == nil || eventID == "" {
+		return
+	}
+	if err := m.history.UpdateEntry(ctx, eventID, func(e *UpdateHistoryEntry) error {
+		fn(e)
+		return nil
+	}); err != nil {
+		log.Warn().Err(err).Str("event_id", eventID).Msg("Failed to update history entry")
+	}
+}
+
+// finishHistory records the final status of an update or rollback
+func (m *Manager) finishHistory(ctx context.Context, eventID string, started time.Time, status UpdateStatusType, updateErr *UpdateError) {
+	m.updateHistoryEntry(ctx, eventID, func(e *UpdateHistoryEntry) {
+		e.Status = status
+		e.Error = updateErr
+		e.DurationMs = time.Since(started).Milliseconds()
+		if e.Notes == awaitingHealthCheckNote {
+			e.Notes = ""
+		}
+	})
+}
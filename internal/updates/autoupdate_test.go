@@ -0,0 +1,64 @@
+package updates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+func TestParseWindowTime(t *testing.T) {
+	valid := map[string]int{"00:00": 0, "03:30": 210, "23:59": 1439}
+	for input, want := range valid {
+		got, err := ParseWindowTime(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseWindowTime(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "24:00", "3", "03:5", "aa:bb", "12:60"} {
+		if _, err := ParseWindowTime(input); err == nil {
+			t.Fatalf("ParseWindowTime(%q) should fail", input)
+		}
+	}
+}
+
+func TestAutoUpdatePolicyWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	policy := PolicyFromConfig(&config.Config{AutoUpdateTime: "03:00", AutoUpdateWindowEnd: "05:00"})
+	if !policy.InWindow(at(3, 0)) || !policy.InWindow(at(4, 59)) {
+		t.Fatal("expected times inside 03:00-05:00 to be in window")
+	}
+	if policy.InWindow(at(5, 0)) || policy.InWindow(at(2, 59)) {
+		t.Fatal("expected times outside 03:00-05:00 to be out of window")
+	}
+
+	overnight := PolicyFromConfig(&config.Config{AutoUpdateTime: "23:00", AutoUpdateWindowEnd: "01:00"})
+	if !overnight.InWindow(at(23, 30)) || !overnight.InWindow(at(0, 30)) || overnight.InWindow(at(12, 0)) {
+		t.Fatal("expected overnight window to span midnight")
+	}
+
+	defaults := PolicyFromConfig(&config.Config{})
+	if defaults.WindowStart != 180 || defaults.WindowEnd != 300 || defaults.CheckInterval != 24*time.Hour {
+		t.Fatalf("unexpected defaults %+v", defaults)
+	}
+}
+
+func TestAutoUpdatePolicyReadyAt(t *testing.T) {
+	published := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := AutoUpdatePolicy{RolloutPeriod: 24 * time.Hour}
+
+	first := policy.ReadyAt(published, "host-a")
+	if first.Before(published) || !first.Before(published.Add(24*time.Hour)) {
+		t.Fatalf("ready time %v outside rollout period", first)
+	}
+	if again := policy.ReadyAt(published, "host-a"); !again.Equal(first) {
+		t.Fatal("expected a stable rollout offset per host")
+	}
+
+	if immediate := (AutoUpdatePolicy{}).ReadyAt(published, "host-a"); !immediate.Equal(published) {
+		t.Fatal("expected no delay without a rollout period")
+	}
+}
@@ -50,6 +50,7 @@ const (
 	InitiatedViaCLI     InitiatedVia = "cli"
 	InitiatedViaScript  InitiatedVia = "script"
 	InitiatedViaWebhook InitiatedVia = "webhook"
+	InitiatedViaPolicy  InitiatedVia = "policy"
 )
 
 // UpdateHistoryEntry represents a single update event
@@ -71,6 +72,8 @@ type UpdateHistoryEntry struct {
 	DownloadBytes  int64               `json:"download_bytes,omitempty"`
 	RelatedEventID string              `json:"related_event_id,omitempty"`
 	Notes          string              `json:"notes,omitempty"`
+	Source         string              `json:"source,omitempty"`           // Download URL of the installed artifact
+	SignatureKeyID string              `json:"signature_key_id,omitempty"` // Release key that signed the checksums
 }
 
 // UpdateError represents error information
@@ -94,6 +97,7 @@ type UpdateHistory struct {
 	mu       sync.RWMutex
 	cache    []UpdateHistoryEntry
 	maxCache int
+	loaded   os.FileInfo // log file state the cache reflects; the update supervisor writes from another process
 }
 
 // NewUpdateHistory creates a new update history manager
@@ -127,6 +131,7 @@ func NewUpdateHistory(dataDir string) (*UpdateHistory, error) {
 func (h *UpdateHistory) CreateEntry(ctx context.Context, entry UpdateHistoryEntry) (string, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.reloadIfChangedLocked()
 
 	// Generate event ID if not provided
 	if entry.EventID == "" {
@@ -142,6 +147,7 @@ func (h *UpdateHistory) CreateEntry(ctx context.Context, entry UpdateHistoryEntr
 	if err := h.appendToFile(entry); err != nil {
 		return "", fmt.Errorf("failed to write to history file: %w", err)
 	}
+	h.markLoaded()
 
 	// Add to cache
 	h.addToCache(entry)
@@ -160,6 +166,7 @@ func (h *UpdateHistory) CreateEntry(ctx context.Context, entry UpdateHistoryEntr
 func (h *UpdateHistory) UpdateEntry(ctx context.Context, eventID string, updateFn func(*UpdateHistoryEntry) error) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.reloadIfChangedLocked()
 
 	// Find entry in cache
 	var entry *UpdateHistoryEntry
@@ -184,6 +191,7 @@ func (h *UpdateHistory) UpdateEntry(ctx context.Context, eventID string, updateF
 	if err := h.rewriteFile(); err != nil {
 		return fmt.Errorf("failed to update history file: %w", err)
 	}
+	h.markLoaded()
 
 	log.Info().
 		Str("event_id", eventID).
@@ -195,6 +203,8 @@ func (h *UpdateHistory) UpdateEntry(ctx context.Context, eventID string, updateF
 
 // GetEntry retrieves a specific entry by ID
 func (h *UpdateHistory) GetEntry(eventID string) (*UpdateHistoryEntry, error) {
+	h.reloadIfChanged()
+
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -209,6 +219,8 @@ func (h *UpdateHistory) GetEntry(eventID string) (*UpdateHistoryEntry, error) {
 
 // ListEntries returns entries matching the filter
 func (h *UpdateHistory) ListEntries(filter HistoryFilter) []UpdateHistoryEntry {
+	h.reloadIfChanged()
+
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -241,6 +253,8 @@ func (h *UpdateHistory) ListEntries(filter HistoryFilter) []UpdateHistoryEntry {
 
 // GetLatestSuccessful returns the most recent successful update
 func (h *UpdateHistory) GetLatestSuccessful() (*UpdateHistoryEntry, error) {
+	h.reloadIfChanged()
+
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -293,12 +307,40 @@ func (h *UpdateHistory) loadCache() error {
 	}
 
 	h.cache = entries
+	h.loaded, _ = file.Stat()
 
 	log.Info().Int("count", len(h.cache)).Msg("Loaded update history cache")
 
 	return nil
 }
 
+// reloadIfChanged reloads the cache when another process rewrote the log file
+func (h *UpdateHistory) reloadIfChanged() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reloadIfChangedLocked()
+}
+
+func (h *UpdateHistory) reloadIfChangedLocked() {
+	info, err := os.Stat(h.logPath)
+	if err != nil {
+		return
+	}
+	if h.loaded != nil && info.ModTime().Equal(h.loaded.ModTime()) && info.Size() == h.loaded.Size() {
+		return
+	}
+	if err := h.loadCache(); err != nil {
+		log.Warn().Err(err).Msg("Failed to reload update history")
+	}
+}
+
+// markLoaded records the log file state written by this process
+func (h *UpdateHistory) markLoaded() {
+	if info, err := os.Stat(h.logPath); err == nil {
+		h.loaded = info
+	}
+}
+
 // appendToFile appends an entry to the JSONL file
 func (h *UpdateHistory) appendToFile(entry UpdateHistoryEntry) error {
 	file, err := os.OpenFile(h.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -59,6 +59,9 @@ type UpdateInfo struct {
 
 var errGitHubRateLimited = errors.New("GitHub API rate limit exceeded")
 
+// noReleaseKeyWarning is shown for available updates when this build cannot verify releases
+const noReleaseKeyWarning = "This build has no release signing key, so updates cannot be installed from Pulse and automatic updates are skipped. Update with install.sh or your package manager."
+
 // Manager handles update operations
 type Manager struct {
 	config        *config.Config
@@ -68,6 +71,14 @@ type Manager struct {
 	cacheTime     map[string]time.Time   // keyed by channel
 	cacheDuration time.Duration
 	progressChan  chan UpdateStatus
+
+	history       *UpdateHistory
+	trustedKeys   []ReleaseKey // nil uses the keys embedded at build time
+	healthURL     string       // overrides the local health endpoint handed to the supervisor
+	binaryPath    string       // binary a restore replaces; defaults to the running executable
+	applyMu       sync.Mutex   // serialises manual and automatic installs
+	lastAutoCheck time.Time
+	warnedNoKeys  bool // the missing release key was logged by the auto updater
 }
 
 // NewManager creates a new update manager
@@ -90,6 +101,11 @@ func NewManager(cfg *config.Config) *Manager {
 	return m
 }
 
+// SetHistory records update attempts, verification results and rollbacks in history
+func (m *Manager) SetHistory(history *UpdateHistory) {
+	m.history = history
+}
+
 // GetProgressChannel returns the channel for update progress
 func (m *Manager) GetProgressChannel() <-chan UpdateStatus {
 	return m.progressChan
@@ -289,6 +305,9 @@ func (m *Manager) CheckForUpdatesWithChannel(ctx context.Context, channel string
 		DownloadURL:    downloadURL,
 		IsPrerelease:   release.Prerelease,
 	}
+	if info.Available && !m.canVerifyReleases() {
+		info.Warning = noReleaseKeyWarning
+	}
 
 	// Cache the result (only if using saved channel)
 	if useCache {
@@ -309,13 +328,32 @@ func (m *Manager) CheckForUpdatesWithChannel(ctx context.Context, channel string
 	return info, nil
 }
 
-// ApplyUpdate downloads and applies an update
+// ApplyOptions describes who requested an update and how
+type ApplyOptions struct {
+	InitiatedBy  InitiatedBy
+	InitiatedVia InitiatedVia
+	Channel      string
+}
+
+// ApplyUpdate downloads and applies an update requested by a user
 func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
+	return m.ApplyUpdateWithOptions(ctx, downloadURL, ApplyOptions{
+		InitiatedBy:  InitiatedByUser,
+		InitiatedVia: InitiatedViaUI,
+	})
+}
+
+// ApplyUpdateWithOptions downloads, verifies and applies an update.
+// The release checksum file must carry a valid signature from an embedded release key.
+func (m *Manager) ApplyUpdateWithOptions(ctx context.Context, downloadURL string, opts ApplyOptions) error {
 	// Validate download URL (allow test server URLs when PULSE_UPDATE_SERVER is set)
-	if os.Getenv("PULSE_UPDATE_SERVER") == "" {
-		if !strings.HasPrefix(downloadURL, "https://github.com/RouXx67/PulseUp/releases/download/") {
-			return fmt.Errorf("invalid download URL")
-		}
+	if os.Getenv("PULSE_UPDATE_SERVER") == "" && !m.isAllowedDownloadURL(downloadURL) {
+		return fmt.Errorf("invalid download URL")
+	}
+
+	// Fail before downloading anything when the release cannot be verified
+	if !m.canVerifyReleases() {
+		return errNoReleaseKeys
 	}
 
 	// Check if Docker
@@ -329,6 +367,36 @@ func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
 		return fmt.Errorf("manual migration required: Pulse v4 is a complete rewrite. Please create a fresh installation. See https://github.com/rcourtman/Pulse/releases/v4.0.0")
 	}
 
+	if !m.applyMu.TryLock() {
+		return fmt.Errorf("an update is already in progress")
+	}
+	defer m.applyMu.Unlock()
+
+	// Extract version from download URL
+	version := versionFromDownloadURL(downloadURL)
+
+	channel := opts.Channel
+	if channel == "" {
+		channel = m.config.UpdateChannel
+	}
+
+	started := time.Now()
+	eventID := m.recordHistory(ctx, UpdateHistoryEntry{
+		Action:         ActionUpdate,
+		Channel:        channel,
+		VersionFrom:    currentInfo.Version,
+		VersionTo:      version,
+		DeploymentType: currentInfo.DeploymentType,
+		InitiatedBy:    opts.InitiatedBy,
+		InitiatedVia:   opts.InitiatedVia,
+		Status:         StatusInProgress,
+		Source:         downloadURL,
+	})
+	fail := func(code string, err error) error {
+		m.finishHistory(ctx, eventID, started, StatusFailed, &UpdateError{Message: err.Error(), Code: code})
+		return err
+	}
+
 	m.updateStatus("downloading", 10, "Downloading update...")
 
 	// Create temp directory in a location we can write to
@@ -336,14 +404,8 @@ func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
 	var tempDir string
 	var err error
 
-	// Try data directory first
-	dataDir := os.Getenv("PULSE_DATA_DIR")
-	if dataDir == "" {
-		dataDir = "/etc/pulse"
-	}
-
-	// Try to create temp dir in data directory
-	tempDir, err = os.MkdirTemp(dataDir, "pulse-update-*")
+	// Try to create temp dir in data directory first
+	tempDir, err = os.MkdirTemp(updateDataDir(), "pulse-update-*")
 	if err != nil {
 		// Fallback to /tmp
 		tempDir, err = os.MkdirTemp("/tmp", "pulse-update-*")
@@ -352,7 +414,7 @@ func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
 			tempDir, err = os.MkdirTemp(".", "pulse-update-*")
 			if err != nil {
 				m.updateStatus("error", 10, "Failed to create temp directory")
-				return fmt.Errorf("failed to create temp directory in any location: %w", err)
+				return fail("temp_dir_failed", fmt.Errorf("failed to create temp directory in any location: %w", err))
 			}
 		}
 	}
@@ -362,17 +424,20 @@ func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
 	tarballPath := filepath.Join(tempDir, "update.tar.gz")
 	if err := m.downloadFile(ctx, downloadURL, tarballPath); err != nil {
 		m.updateStatus("error", 20, "Failed to download update")
-		return fmt.Errorf("failed to download update: %w", err)
+		return fail("download_failed", fmt.Errorf("failed to download update: %w", err))
 	}
 
-	// Verify checksum if available
-	m.updateStatus("verifying", 30, "Verifying download...")
-	if err := m.verifyChecksum(ctx, downloadURL, tarballPath); err != nil {
-		// Log warning but don't fail - checksums might not be available for all releases
-		log.Warn().Err(err).Msg("Checksum verification failed or unavailable")
-	} else {
-		log.Info().Msg("Checksum verification passed")
+	// Verify the signed checksum - unsigned or tampered releases are never installed
+	m.updateStatus("verifying", 30, "Verifying release signature...")
+	signature, err := m.verifyRelease(ctx, downloadURL, tarballPath)
+	if err != nil {
+		m.updateStatus("error", 30, "Release verification failed")
+		return fail("verification_failed", fmt.Errorf("release verification failed: %w", err))
 	}
+	log.Info().Str("keyId", signature.KeyID).Msg("Release signature verified")
+	m.updateHistoryEntry(ctx, eventID, func(e *UpdateHistoryEntry) {
+		e.SignatureKeyID = signature.KeyID
+	})
 
 	m.updateStatus("extracting", 40, "Extracting update...")
 
@@ -380,7 +445,7 @@ func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
 	extractDir := filepath.Join(tempDir, "extracted")
 	if err := m.extractTarball(tarballPath, extractDir); err != nil {
 		m.updateStatus("error", 40, "Failed to extract update")
-		return fmt.Errorf("failed to extract update: %w", err)
+		return fail("extract_failed", fmt.Errorf("failed to extract update: %w", err))
 	}
 
 	m.updateStatus("backing-up", 60, "Creating backup...")
@@ -389,24 +454,15 @@ func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
 	backupPath, err := m.createBackup()
 	if err != nil {
 		m.updateStatus("error", 60, "Failed to create backup")
-		return fmt.Errorf("failed to create backup: %w", err)
+		return fail("backup_failed", fmt.Errorf("failed to create backup: %w", err))
 	}
 	log.Info().Str("backup", backupPath).Msg("Created backup")
+	m.updateHistoryEntry(ctx, eventID, func(e *UpdateHistoryEntry) {
+		e.BackupPath = backupPath
+	})
 
 	m.updateStatus("applying", 80, "Applying update...")
 
-	// Extract version from download URL or use timestamp
-	version := "unknown"
-	if parts := strings.Split(downloadURL, "/"); len(parts) > 0 {
-		for _, part := range parts {
-			if strings.HasPrefix(part, "v") {
-				version = strings.TrimPrefix(part, "v")
-				version = strings.TrimSuffix(version, ".tar.gz")
-				break
-			}
-		}
-	}
-
 	// Apply the update files
 	// With the new directory structure (/opt/pulse/bin/), the pulse user has write access
 	log.Info().Msg("Applying update files")
@@ -417,22 +473,63 @@ func (m *Manager) ApplyUpdate(ctx context.Context, downloadURL string) error {
 		if restoreErr := m.restoreBackup(backupPath); restoreErr != nil {
 			log.Error().Err(restoreErr).Msg("Failed to restore backup")
 		}
-		return fmt.Errorf("failed to apply update: %w", err)
+		return fail("apply_failed", fmt.Errorf("failed to apply update: %w", err))
 	}
 
+	// The entry stays in progress until the new binary passes its health check
+	m.updateHistoryEntry(ctx, eventID, func(e *UpdateHistoryEntry) {
+		e.DurationMs = time.Since(started).Milliseconds()
+		e.Notes = awaitingHealthCheckNote
+	})
+
 	m.updateStatus("restarting", 95, "Restarting service...")
 
-	// Schedule a clean exit after a short delay - systemd will restart us
-	go func() {
-		time.Sleep(2 * time.Second)
-		log.Info().Msg("Exiting for restart after update")
-		os.Exit(0)
-	}()
+	// The supervisor restarts us, checks the new version and rolls back if needed
+	if err := m.requestSupervisedRestart(eventID); err != nil {
+		log.Warn().Err(err).Msg("Update supervisor unavailable, restarting without a health check")
+		m.finishHistory(ctx, eventID, started, StatusSuccess, nil)
+		m.updateHistoryEntry(ctx, eventID, func(e *UpdateHistoryEntry) {
+			e.Notes = "Restarted without a health check: " + err.Error()
+		})
+
+		// Schedule a clean exit after a short delay - systemd will restart us
+		go func() {
+			time.Sleep(2 * time.Second)
+			log.Info().Msg("Exiting for restart after update")
+			os.Exit(0)
+		}()
+	}
 
 	m.updateStatus("completed", 100, "Update completed, restarting...")
 	return nil
 }
 
+// isAllowedDownloadURL accepts official GitHub release assets and the configured update source
+func (m *Manager) isAllowedDownloadURL(downloadURL string) bool {
+	if strings.HasPrefix(downloadURL, "https://github.com/RouXx67/PulseUp/releases/download/") {
+		return true
+	}
+	if source := m.updateSource(); source != "" {
+		return strings.HasPrefix(downloadURL, source+"/")
+	}
+	return false
+}
+
+// updateSource returns the configured release mirror without a trailing slash
+func (m *Manager) updateSource() string {
+	return strings.TrimRight(strings.TrimSpace(m.config.UpdateSource), "/")
+}
+
+// versionFromDownloadURL extracts the release tag from a download URL
+func versionFromDownloadURL(downloadURL string) string {
+	for _, part := range strings.Split(downloadURL, "/") {
+		if strings.HasPrefix(part, "v") {
+			return strings.TrimSuffix(strings.TrimPrefix(part, "v"), ".tar.gz")
+		}
+	}
+	return "unknown"
+}
+
 // GetStatus returns the current update status
 func (m *Manager) GetStatus() UpdateStatus {
 	m.statusMu.RLock()
@@ -491,6 +588,12 @@ func (m *Manager) getLatestReleaseForChannel(ctx context.Context, channel string
 	}
 	url := baseURL + "/repos/RouXx67/PulseUP/releases"
 
+	// A local mirror serves the same release list as releases.json
+	source := m.updateSource()
+	if source != "" && os.Getenv("PULSE_UPDATE_SERVER") == "" {
+		url = source + "/releases.json"
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
@@ -536,6 +639,9 @@ func (m *Manager) getLatestReleaseForChannel(ctx context.Context, channel string
 	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
 		return nil, fmt.Errorf("failed to decode releases: %w", err)
 	}
+	if source != "" {
+		resolveMirrorAssets(source, releases)
+	}
 
 	// Find latest release based on channel
 	// RC channel: return newest release (RC or stable), even if not newer than current
@@ -644,6 +750,18 @@ func (m *Manager) getLatestReleaseForChannel(ctx context.Context, channel string
 	return nil, fmt.Errorf("no releases found for channel %s", channel)
 }
 
+// resolveMirrorAssets turns relative asset paths in a mirror's release list into absolute URLs
+func resolveMirrorAssets(source string, releases []ReleaseInfo) {
+	for i := range releases {
+		for j := range releases[i].Assets {
+			asset := &releases[i].Assets[j]
+			if !strings.Contains(asset.BrowserDownloadURL, "://") {
+				asset.BrowserDownloadURL = source + "/" + strings.TrimLeft(asset.BrowserDownloadURL, "/")
+			}
+		}
+	}
+}
+
 // downloadFile downloads a file from URL to dest
 func (m *Manager) downloadFile(ctx context.Context, url, dest string) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -678,53 +796,57 @@ func (m *Manager) downloadFile(ctx context.Context, url, dest string) error {
 	return nil
 }
 
-// verifyChecksum downloads and verifies the SHA256 checksum of a file
-func (m *Manager) verifyChecksum(ctx context.Context, tarballURL, tarballPath string) error {
-	// Try to find checksum file URL by deriving from tarball URL
-	// Example: pulse-v4.22.0-linux-amd64.tar.gz -> SHA256SUMS or checksums.txt
+// canVerifyReleases reports whether release signatures can be checked with the trusted keys
+func (m *Manager) canVerifyReleases() bool {
+	return m.trustedKeys != nil || ReleaseSigningAvailable()
+}
+
+// verifyRelease checks the signed checksum file published next to the tarball and
+// verifies the downloaded file against it. Missing signatures are treated as failures.
+func (m *Manager) verifyRelease(ctx context.Context, tarballURL, tarballPath string) (*SignatureResult, error) {
+	keys := m.trustedKeys
+	if keys == nil {
+		var err error
+		if keys, err = releaseKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Example: pulse-v4.22.0-linux-amd64.tar.gz -> checksums.txt + checksums.txt.minisig
 	baseURL := tarballURL[:strings.LastIndex(tarballURL, "/")+1]
 
 	// Common checksum file names used in GitHub releases
-	checksumNames := []string{"SHA256SUMS", "checksums.txt", "SHA256SUMS.txt"}
-
-	var checksumContent string
-	var checksumErr error
+	checksumNames := []string{"checksums.txt", "SHA256SUMS", "SHA256SUMS.txt"}
 
-	// Try each checksum filename
+	var checksumContent []byte
+	var signature *SignatureResult
 	for _, name := range checksumNames {
-		checksumURL := baseURL + name
-
-		req, err := http.NewRequestWithContext(ctx, "GET", checksumURL, nil)
+		content, err := m.fetchReleaseFile(ctx, baseURL+name)
 		if err != nil {
 			continue
 		}
+		log.Info().Str("file", name).Msg("Found checksum file")
 
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
+		sig, err := m.fetchReleaseFile(ctx, baseURL+name+signatureSuffix)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("checksum file %s is not signed: %w", name, err)
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusOK {
-			body, err := io.ReadAll(resp.Body)
-			if err == nil {
-				checksumContent = string(body)
-				log.Info().Str("file", name).Msg("Found checksum file")
-				break
-			}
+		if signature, err = VerifySignature(keys, content, string(sig)); err != nil {
+			return nil, fmt.Errorf("%s: %w", name+signatureSuffix, err)
 		}
+		checksumContent = content
+		break
 	}
 
-	if checksumContent == "" {
-		return fmt.Errorf("no checksum file found")
+	if checksumContent == nil {
+		return nil, fmt.Errorf("no checksum file found")
 	}
 
 	// Parse checksum file to find the hash for our tarball
 	tarballName := filepath.Base(tarballURL)
 	expectedHash := ""
 
-	for _, line := range strings.Split(checksumContent, "\n") {
+	for _, line := range strings.Split(string(checksumContent), "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -746,38 +868,57 @@ func (m *Manager) verifyChecksum(ctx context.Context, tarballURL, tarballPath st
 	}
 
 	if expectedHash == "" {
-		return fmt.Errorf("checksum not found for %s in checksum file", tarballName)
+		return nil, fmt.Errorf("checksum not found for %s in checksum file", tarballName)
 	}
 
 	// Compute SHA256 of downloaded file
 	file, err := os.Open(tarballPath)
 	if err != nil {
-		return fmt.Errorf("failed to open tarball for checksum: %w", err)
+		return nil, fmt.Errorf("failed to open tarball for checksum: %w", err)
 	}
 	defer file.Close()
 
 	hash := sha256.New()
 	if _, err := io.Copy(hash, file); err != nil {
-		return fmt.Errorf("failed to compute checksum: %w", err)
+		return nil, fmt.Errorf("failed to compute checksum: %w", err)
 	}
 
 	actualHash := hex.EncodeToString(hash.Sum(nil))
 
 	// Compare hashes
 	if actualHash != expectedHash {
-		checksumErr = fmt.Errorf("checksum mismatch: expected %s, got %s", expectedHash, actualHash)
 		log.Error().
 			Str("expected", expectedHash).
 			Str("actual", actualHash).
 			Msg("Checksum verification failed")
-		return checksumErr
+		return nil, fmt.Errorf("checksum mismatch: expected %s, got %s", expectedHash, actualHash)
 	}
 
 	log.Info().
 		Str("hash", actualHash).
 		Msg("Checksum verified successfully")
 
-	return nil
+	return signature, nil
+}
+
+// fetchReleaseFile downloads a small release metadata file
+func (m *Manager) fetchReleaseFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", filepath.Base(url), resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
 }
 
 // extractTarball extracts a gzipped tarball
@@ -845,17 +986,20 @@ func (m *Manager) extractTarball(src, dest string) error {
 	return nil
 }
 
+// updateDataDir is where in-app updates keep their download and backups
+func updateDataDir() string {
+	if dataDir := os.Getenv("PULSE_DATA_DIR"); dataDir != "" {
+		return dataDir
+	}
+	return "/etc/pulse"
+}
+
 // createBackup creates a backup of the current installation
 func (m *Manager) createBackup() (string, error) {
 	timestamp := time.Now().Format("20060102-150405")
 
 	// Try to create backup in a writable location
-	dataDir := os.Getenv("PULSE_DATA_DIR")
-	if dataDir == "" {
-		dataDir = "/etc/pulse"
-	}
-
-	backupDir := filepath.Join(dataDir, fmt.Sprintf("backup-%s", timestamp))
+	backupDir := filepath.Join(updateDataDir(), fmt.Sprintf("backup-%s", timestamp))
 
 	// Create backup directory
 	if err := os.MkdirAll(backupDir, 0755); err != nil {
@@ -946,7 +1090,10 @@ func (m *Manager) restoreBackup(backupDir string) error {
 	// Restore the pulse binary if it exists in backup
 	binarySrc := filepath.Join(backupDir, "pulse")
 	if _, err := os.Stat(binarySrc); err == nil {
-		binaryPath, err := os.Executable()
+		binaryPath := m.binaryPath
+		if binaryPath == "" {
+			binaryPath, err = os.Executable()
+		}
 		if err == nil {
 			// Create temp copy first, then atomic rename
 			tempBinary := binaryPath + ".restored"
@@ -1069,12 +1216,7 @@ func (m *Manager) updateStatus(status string, progress int, message string) {
 // cleanupOldTempDirs removes old pulse-update-* temp directories from previous runs
 func (m *Manager) cleanupOldTempDirs() {
 	// Check multiple locations where temp dirs might exist
-	dataDir := os.Getenv("PULSE_DATA_DIR")
-	if dataDir == "" {
-		dataDir = "/etc/pulse"
-	}
-
-	dirsToCheck := []string{"/tmp", dataDir, "."}
+	dirsToCheck := []string{"/tmp", updateDataDir(), "."}
 
 	for _, dir := range dirsToCheck {
 		entries, err := os.ReadDir(dir)
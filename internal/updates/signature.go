@@ -0,0 +1,158 @@
+package updates
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	_ "embed"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Release artifacts are verified with minisign signatures over the release
+// checksum file. The checksum file is signed once per release and each
+// downloaded artifact is then matched against its signed SHA256 entry.
+
+//go:embed release-signing.pub
+var embeddedReleaseKeys string
+
+// signatureSuffix is appended to the checksum file name to locate its signature
+const signatureSuffix = ".minisig"
+
+// minisign signature algorithms
+const (
+	minisignAlgLegacy    = "Ed" // signature over the raw message
+	minisignAlgPrehashed = "ED" // signature over the BLAKE2b-512 hash of the message
+)
+
+var errNoReleaseKeys = errors.New("no release signing keys are embedded in this build")
+
+// ReleaseKey is a minisign public key trusted to sign Pulse releases
+type ReleaseKey struct {
+	ID        string
+	publicKey ed25519.PublicKey
+}
+
+// SignatureResult describes a verified signature
+type SignatureResult struct {
+	KeyID          string `json:"keyId"`
+	TrustedComment string `json:"trustedComment,omitempty"`
+}
+
+// ParseReleaseKeys parses minisign public keys, one base64 key per line.
+// Blank lines, "#" comments and minisign "untrusted comment:" lines are ignored.
+func ParseReleaseKeys(data string) ([]ReleaseKey, error) {
+	var keys []ReleaseKey
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "untrusted comment:") {
+			continue
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key encoding: %w", err)
+		}
+		if len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != minisignAlgLegacy {
+			return nil, fmt.Errorf("invalid minisign public key")
+		}
+
+		keys = append(keys, ReleaseKey{
+			ID:        formatKeyID(raw[2:10]),
+			publicKey: ed25519.PublicKey(raw[10:]),
+		})
+	}
+	return keys, scanner.Err()
+}
+
+// releaseKeys returns the keys embedded at build time
+func releaseKeys() ([]ReleaseKey, error) {
+	keys, err := ParseReleaseKeys(embeddedReleaseKeys)
+	if err != nil {
+		return nil, fmt.Errorf("embedded release key: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, errNoReleaseKeys
+	}
+	return keys, nil
+}
+
+// ReleaseSigningAvailable reports whether this build embeds a key that can verify releases.
+// Without one, updates cannot be installed from Pulse and automatic updates are skipped.
+func ReleaseSigningAvailable() bool {
+	_, err := releaseKeys()
+	return err == nil
+}
+
+// VerifySignature checks a minisign signature of message against the trusted keys.
+func VerifySignature(keys []ReleaseKey, message []byte, signature string) (*SignatureResult, error) {
+	lines := make([]string, 0, 4)
+	for _, line := range strings.Split(strings.ReplaceAll(signature, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return nil, fmt.Errorf("malformed signature file")
+	}
+
+	sigBlock, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sigBlock) != 2+8+ed25519.SignatureSize {
+		return nil, fmt.Errorf("malformed signature")
+	}
+	const trustedPrefix = "trusted comment: "
+	if !strings.HasPrefix(lines[2], trustedPrefix) {
+		return nil, fmt.Errorf("signature is missing its trusted comment")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], trustedPrefix)
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("malformed global signature")
+	}
+
+	alg := string(sigBlock[:2])
+	keyID := formatKeyID(sigBlock[2:10])
+	sig := sigBlock[10:]
+
+	var key *ReleaseKey
+	for i := range keys {
+		if keys[i].ID == keyID {
+			key = &keys[i]
+			break
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("signed with untrusted key %s", keyID)
+	}
+
+	signed := message
+	switch alg {
+	case minisignAlgLegacy:
+	case minisignAlgPrehashed:
+		sum := blake2b.Sum512(message)
+		signed = sum[:]
+	default:
+		return nil, fmt.Errorf("unsupported signature algorithm %q", alg)
+	}
+
+	if !ed25519.Verify(key.publicKey, signed, sig) {
+		return nil, fmt.Errorf("signature verification failed")
+	}
+
+	// The global signature binds the trusted comment to the signature
+	if !ed25519.Verify(key.publicKey, append(append([]byte{}, sig...), trustedComment...), globalSig) {
+		return nil, fmt.Errorf("trusted comment signature verification failed")
+	}
+
+	return &SignatureResult{KeyID: keyID, TrustedComment: trustedComment}, nil
+}
+
+// formatKeyID renders a minisign key ID the way `minisign -V` prints it
+func formatKeyID(id []byte) string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id))
+}
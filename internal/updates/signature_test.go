@@ -0,0 +1,190 @@
+package updates
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+type testSigner struct {
+	keyID   []byte
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+func newTestSigner(t *testing.T) *testSigner {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	keyID := make([]byte, 8)
+	if _, err := rand.Read(keyID); err != nil {
+		t.Fatalf("generate key id: %v", err)
+	}
+	return &testSigner{keyID: keyID, private: private, public: public}
+}
+
+// publicKey renders the key the way minisign writes it to a .pub file
+func (s *testSigner) publicKey() string {
+	raw := append(append([]byte("Ed"), s.keyID...), s.public...)
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(raw) + "\n"
+}
+
+func (s *testSigner) sign(message []byte, prehash bool, trustedComment string) string {
+	alg, signed := "Ed", message
+	if prehash {
+		sum := blake2b.Sum512(message)
+		alg, signed = "ED", sum[:]
+	}
+	sig := ed25519.Sign(s.private, signed)
+	global := ed25519.Sign(s.private, append(append([]byte{}, sig...), trustedComment...))
+	block := append(append([]byte(alg), s.keyID...), sig...)
+	return fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(block), trustedComment, base64.StdEncoding.EncodeToString(global))
+}
+
+func (s *testSigner) keys(t *testing.T) []ReleaseKey {
+	t.Helper()
+	keys, err := ParseReleaseKeys("# release keys\n" + s.publicKey())
+	if err != nil {
+		t.Fatalf("ParseReleaseKeys: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(keys))
+	}
+	return keys
+}
+
+func TestVerifySignature(t *testing.T) {
+	signer := newTestSigner(t)
+	keys := signer.keys(t)
+	message := []byte("abc123  pulse-v4.30.0-linux-amd64.tar.gz\n")
+
+	for _, prehash := range []bool{false, true} {
+		result, err := VerifySignature(keys, message, signer.sign(message, prehash, "timestamp:1700000000\tfile:checksums.txt"))
+		if err != nil {
+			t.Fatalf("prehash=%v: unexpected error: %v", prehash, err)
+		}
+		if result.KeyID != keys[0].ID || !strings.Contains(result.TrustedComment, "checksums.txt") {
+			t.Fatalf("unexpected result %+v", result)
+		}
+	}
+
+	if _, err := VerifySignature(keys, append(message, 'x'), signer.sign(message, true, "c")); err == nil {
+		t.Fatal("expected tampered message to fail")
+	}
+
+	tamperedComment := strings.Replace(signer.sign(message, true, "file:checksums.txt"), "file:checksums.txt", "file:other.txt", 1)
+	if _, err := VerifySignature(keys, message, tamperedComment); err == nil {
+		t.Fatal("expected tampered trusted comment to fail")
+	}
+
+	other := newTestSigner(t)
+	if _, err := VerifySignature(keys, message, other.sign(message, true, "c")); err == nil || !strings.Contains(err.Error(), "untrusted key") {
+		t.Fatalf("expected untrusted key error, got %v", err)
+	}
+
+	if _, err := VerifySignature(keys, message, "not a signature"); err == nil {
+		t.Fatal("expected malformed signature to fail")
+	}
+}
+
+func TestParseReleaseKeysRejectsInvalidKey(t *testing.T) {
+	if _, err := ParseReleaseKeys("RWQinvalid"); err == nil {
+		t.Fatal("expected invalid key to be rejected")
+	}
+	keys, err := ParseReleaseKeys("# only comments\n\n")
+	if err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v (%v)", keys, err)
+	}
+}
+
+func TestVerifyRelease(t *testing.T) {
+	signer := newTestSigner(t)
+	tarball := []byte("release archive contents")
+	sum := sha256.Sum256(tarball)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  pulse-v4.30.0-linux-amd64.tar.gz\n")
+
+	signature := signer.sign(checksums, true, "file:checksums.txt")
+	serveSignature := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4.30.0/checksums.txt":
+			w.Write(checksums)
+		case "/v4.30.0/checksums.txt.minisig":
+			if !serveSignature {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(signature))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tarballPath := filepath.Join(t.TempDir(), "update.tar.gz")
+	if err := os.WriteFile(tarballPath, tarball, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{trustedKeys: signer.keys(t)}
+	tarballURL := server.URL + "/v4.30.0/pulse-v4.30.0-linux-amd64.tar.gz"
+	ctx := context.Background()
+
+	result, err := m.verifyRelease(ctx, tarballURL, tarballPath)
+	if err != nil {
+		t.Fatalf("verifyRelease: %v", err)
+	}
+	if result.KeyID != m.trustedKeys[0].ID {
+		t.Fatalf("unexpected key id %s", result.KeyID)
+	}
+
+	// A modified archive no longer matches the signed checksum
+	if err := os.WriteFile(tarballPath, []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.verifyRelease(ctx, tarballURL, tarballPath); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+
+	// Checksums without a signature are refused
+	serveSignature = false
+	if _, err := m.verifyRelease(ctx, tarballURL, tarballPath); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("expected unsigned checksum error, got %v", err)
+	}
+}
+
+func TestUpdatesRefusedWithoutReleaseKey(t *testing.T) {
+	if ReleaseSigningAvailable() {
+		t.Skip("this build embeds a release signing key")
+	}
+	t.Setenv("PULSE_UPDATE_SERVER", "http://127.0.0.1")
+
+	m := &Manager{config: &config.Config{}}
+	err := m.ApplyUpdateWithOptions(context.Background(), "http://127.0.0.1/pulse.tar.gz", ApplyOptions{})
+	if !errors.Is(err, errNoReleaseKeys) {
+		t.Fatalf("expected missing key error, got %v", err)
+	}
+
+	m.trustedKeys = newTestSigner(t).keys(t)
+	if !m.canVerifyReleases() {
+		t.Fatal("expected configured keys to allow updates")
+	}
+}
@@ -0,0 +1,386 @@
+package updates
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// SupervisorRequestFile is written to the data directory when an in-app update needs a restart.
+	// install.sh installs pulse-update-verify.path, which starts "pulse update supervise" as root.
+	SupervisorRequestFile = ".update-supervise.json"
+
+	supervisorUnit         = "pulse-update-verify.path"
+	supervisorRestartDelay = 2 * time.Second
+
+	// supervisorUser is the account the supervisor runs as. The root process only starts,
+	// stops and restarts the service on its behalf and never reads files the service can write.
+	supervisorUser = "pulse"
+	// serviceControlEnv marks the unprivileged supervisor, which reaches the root process
+	// through descriptors 3 (requests) and 4 (replies)
+	serviceControlEnv = "PULSE_UPDATE_SERVICE_CONTROL"
+)
+
+// supervisorRequest hands a freshly applied update to the supervisor
+type supervisorRequest struct {
+	EventID   string `json:"eventId"`
+	HealthURL string `json:"healthUrl"`
+	Rollback  bool   `json:"rollback"`
+	// Binary is the executable a rollback restores, since the supervisor runs from its own copy
+	Binary string `json:"binary"`
+}
+
+// supervisorEnabled reports whether the systemd supervisor for in-app updates is installed
+func supervisorEnabled() bool {
+	return exec.Command("systemctl", "is-enabled", "--quiet", supervisorUnit).Run() == nil
+}
+
+// healthEndpoint returns the local health URL of this server
+func (m *Manager) healthEndpoint() string {
+	if m.healthURL != "" {
+		return m.healthURL
+	}
+	scheme := "http"
+	if m.config.HTTPSEnabled && m.config.TLSCertFile != "" && m.config.TLSKeyFile != "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://127.0.0.1:%d/api/health", scheme, m.config.FrontendPort)
+}
+
+// requestSupervisedRestart asks the supervisor to restart Pulse, check its health
+// and roll back if the new version does not come up. The restart happens outside
+// the service so a version that crashes or hangs on start is still rolled back.
+func (m *Manager) requestSupervisedRestart(eventID string) error {
+	if m.history == nil {
+		return fmt.Errorf("update history is unavailable")
+	}
+	if !supervisorEnabled() {
+		return fmt.Errorf("%s is not enabled", supervisorUnit)
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(supervisorRequest{
+		EventID:   eventID,
+		HealthURL: m.healthEndpoint(),
+		Rollback:  PolicyFromConfig(m.config).Rollback,
+		Binary:    binary,
+	})
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(filepath.Dir(m.history.logPath), SupervisorRequestFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// SuperviseUpdate handles a restart requested by an in-app update. It runs as the
+// pulse-update-verify service, restarts Pulse, waits for its health endpoint and
+// restores the pre-update backup when the policy allows it. Started as root, it
+// re-executes itself as the pulse user and only controls the service for that process.
+func SuperviseUpdate(ctx context.Context, dataDir string) error {
+	if os.Geteuid() == 0 {
+		return superviseAsServiceUser(ctx, dataDir)
+	}
+
+	var control serviceControlFunc
+	if os.Getenv(serviceControlEnv) != "" {
+		client := &serviceControlClient{
+			requests: os.NewFile(3, "service-control-requests"),
+			replies:  bufio.NewReader(os.NewFile(4, "service-control-replies")),
+		}
+		control = client.run
+	}
+
+	path := filepath.Join(dataDir, SupervisorRequestFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Consume the request first so the path unit does not trigger again
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	var req supervisorRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return fmt.Errorf("invalid supervisor request: %w", err)
+	}
+	if err := validateHealthURL(req.HealthURL); err != nil {
+		return err
+	}
+	if req.Binary != "" && !filepath.IsAbs(req.Binary) {
+		return fmt.Errorf("invalid binary path %q", req.Binary)
+	}
+
+	history, err := NewUpdateHistory(dataDir)
+	if err != nil {
+		return err
+	}
+	m := &Manager{config: &config.Config{AutoUpdateRollback: req.Rollback}, history: history, binaryPath: req.Binary}
+
+	adapter := NewInstallShAdapter(history)
+	adapter.healthURL = req.HealthURL
+	adapter.serviceControl = control
+
+	// Give the server time to report the update before it is restarted
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(supervisorRestartDelay):
+	}
+
+	return m.supervise(ctx, adapter, req.EventID, pendingHealthTimeout)
+}
+
+// superviseAsServiceUser runs the supervisor as the pulse user and serves its service
+// control requests. Everything the service can write, including the request, the update
+// history and the backup, is only touched by the unprivileged process.
+func superviseAsServiceUser(ctx context.Context, dataDir string) error {
+	if _, err := os.Lstat(filepath.Join(dataDir, SupervisorRequestFile)); os.IsNotExist(err) {
+		return nil
+	}
+
+	account, err := user.Lookup(supervisorUser)
+	if err != nil {
+		return fmt.Errorf("failed to look up the %s user: %w", supervisorUser, err)
+	}
+	uid, err := strconv.ParseUint(account.Uid, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid uid for %s: %w", supervisorUser, err)
+	}
+	gid, err := strconv.ParseUint(account.Gid, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid gid for %s: %w", supervisorUser, err)
+	}
+	if uid == 0 || gid == 0 {
+		return fmt.Errorf("refusing to supervise updates as root")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	serviceName, err := (&InstallShAdapter{}).detectServiceName()
+	if err != nil {
+		return err
+	}
+
+	requests, childRequests, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer requests.Close()
+	childReplies, replies, err := os.Pipe()
+	if err != nil {
+		childRequests.Close()
+		return err
+	}
+	defer replies.Close()
+
+	cmd := exec.CommandContext(ctx, executable, "update", "supervise")
+	cmd.Dir = "/"
+	cmd.Env = append(os.Environ(), serviceControlEnv+"=1")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{childRequests, childReplies}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: []uint32{}},
+	}
+	err = cmd.Start()
+	childRequests.Close()
+	childReplies.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start the supervisor as %s: %w", supervisorUser, err)
+	}
+
+	go serveServiceControl(ctx, requests, replies, serviceName)
+	return cmd.Wait()
+}
+
+// serviceControlFunc starts, stops or restarts a service
+type serviceControlFunc func(ctx context.Context, action, serviceName string) error
+
+// serveServiceControl runs the service actions requested by the unprivileged supervisor.
+// Only start, stop and restart of the detected Pulse service are accepted.
+func serveServiceControl(ctx context.Context, requests io.Reader, replies io.Writer, serviceName string) {
+	scanner := bufio.NewScanner(requests)
+	for scanner.Scan() {
+		reply := "ok"
+		switch action := scanner.Text(); action {
+		case "start", "stop", "restart":
+			if err := exec.CommandContext(ctx, "systemctl", action, serviceName).Run(); err != nil {
+				reply = "error " + err.Error()
+			}
+		default:
+			reply = fmt.Sprintf("error unsupported action %q", action)
+		}
+		if _, err := fmt.Fprintln(replies, reply); err != nil {
+			return
+		}
+	}
+}
+
+// serviceControlClient asks the root supervisor to control the service
+type serviceControlClient struct {
+	mu       sync.Mutex
+	requests io.Writer
+	replies  *bufio.Reader
+}
+
+// run requests an action; the root process picks the service itself
+func (c *serviceControlClient) run(ctx context.Context, action, _ string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintln(c.requests, action); err != nil {
+		return err
+	}
+	reply, err := c.replies.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if reply = strings.TrimSpace(reply); reply != "ok" {
+		return fmt.Errorf("%s", strings.TrimPrefix(reply, "error "))
+	}
+	return nil
+}
+
+// validateBackupPath only accepts backups made by createBackup, in the data directory or the
+// /tmp fallback
+func validateBackupPath(backupPath string) error {
+	parent, name := filepath.Split(backupPath)
+	parent = filepath.Clean(parent)
+	if filepath.Clean(backupPath) == backupPath &&
+		((parent == filepath.Clean(updateDataDir()) && strings.HasPrefix(name, "backup-")) ||
+			(parent == "/tmp" && strings.HasPrefix(name, "pulse-backup-"))) {
+		return nil
+	}
+	return fmt.Errorf("backup %q is not an update backup", backupPath)
+}
+
+// validateHealthURL only accepts the local health endpoint, since the request file is written by the pulse user
+func validateHealthURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Path != "/api/health" {
+		return fmt.Errorf("invalid health URL %q", raw)
+	}
+	switch parsed.Hostname() {
+	case "127.0.0.1", "localhost", "::1":
+		return nil
+	}
+	return fmt.Errorf("health URL %q is not a loopback address", raw)
+}
+
+// supervise restarts the service into the applied update and records the outcome in history
+func (m *Manager) supervise(ctx context.Context, adapter *InstallShAdapter, eventID string, timeout time.Duration) error {
+	entry, err := m.history.GetEntry(eventID)
+	if err != nil {
+		return err
+	}
+	pending := *entry
+	if pending.Status != StatusInProgress {
+		return fmt.Errorf("update %s is not awaiting a restart", eventID)
+	}
+
+	serviceName, err := adapter.detectServiceName()
+	if err != nil {
+		return err
+	}
+
+	healthErr := adapter.restartService(ctx, serviceName)
+	if healthErr != nil {
+		healthErr = fmt.Errorf("failed to restart %s: %w", serviceName, healthErr)
+	} else {
+		healthErr = adapter.waitForHealth(ctx, timeout)
+	}
+	if healthErr == nil {
+		m.finishHistory(ctx, pending.EventID, pending.Timestamp, StatusSuccess, nil)
+		log.Info().Str("version", pending.VersionTo).Msg("Update verified healthy")
+		return nil
+	}
+
+	log.Error().Err(healthErr).Str("version", pending.VersionTo).Msg("Updated Pulse failed its health check")
+
+	if pending.BackupPath != "" {
+		if err := validateBackupPath(pending.BackupPath); err != nil {
+			log.Error().Err(err).Msg("Refusing to roll back from an unexpected location")
+			pending.BackupPath = ""
+		}
+	}
+
+	if !PolicyFromConfig(m.config).Rollback || pending.BackupPath == "" {
+		m.finishHistory(ctx, pending.EventID, pending.Timestamp, StatusFailed, &UpdateError{
+			Message: healthErr.Error(),
+			Code:    "health_check_failed",
+		})
+		return healthErr
+	}
+
+	rollbackID := m.recordHistory(ctx, UpdateHistoryEntry{
+		Action:         ActionRollback,
+		Channel:        pending.Channel,
+		VersionFrom:    pending.VersionTo,
+		VersionTo:      pending.VersionFrom,
+		DeploymentType: pending.DeploymentType,
+		InitiatedBy:    InitiatedByAuto,
+		InitiatedVia:   InitiatedViaPolicy,
+		Status:         StatusInProgress,
+		BackupPath:     pending.BackupPath,
+		RelatedEventID: pending.EventID,
+		Notes:          "Automatic rollback after failed health check",
+	})
+
+	started := time.Now()
+	rollbackErr := adapter.stopService(ctx, serviceName)
+	if rollbackErr == nil {
+		rollbackErr = m.restoreBackup(pending.BackupPath)
+	}
+	if startErr := adapter.startService(ctx, serviceName); rollbackErr == nil && startErr != nil {
+		rollbackErr = fmt.Errorf("failed to start %s: %w", serviceName, startErr)
+	}
+	if rollbackErr == nil {
+		rollbackErr = adapter.waitForHealth(ctx, timeout)
+	}
+	if rollbackErr != nil {
+		log.Error().Err(rollbackErr).Msg("Automatic rollback failed")
+		m.finishHistory(ctx, rollbackID, started, StatusFailed, &UpdateError{Message: rollbackErr.Error(), Code: "rollback_failed"})
+		m.finishHistory(ctx, pending.EventID, pending.Timestamp, StatusFailed, &UpdateError{
+			Message: healthErr.Error(),
+			Code:    "health_check_failed",
+		})
+		return rollbackErr
+	}
+
+	m.finishHistory(ctx, rollbackID, started, StatusSuccess, nil)
+	m.finishHistory(ctx, pending.EventID, pending.Timestamp, StatusRolledBack, &UpdateError{
+		Message: healthErr.Error(),
+		Code:    "health_check_failed",
+	})
+	log.Warn().Str("version", pending.VersionFrom).Msg("Restored previous version")
+	return nil
+}
@@ -0,0 +1,230 @@
+package updates
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RouXx67/PulseUp/internal/config"
+)
+
+// fakeSystemctl puts a systemctl on PATH that reports every service active and records restarts
+func fakeSystemctl(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\n[ \"$1\" = is-active ] && echo active\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "systemctl"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return calls
+}
+
+func newPendingUpdateManager(t *testing.T, healthURL string) (*Manager, *InstallShAdapter, string) {
+	t.Helper()
+	history, err := NewUpdateHistory(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewUpdateHistory: %v", err)
+	}
+	m := &Manager{config: &config.Config{AutoUpdateRollback: true}, history: history}
+
+	eventID, err := history.CreateEntry(context.Background(), UpdateHistoryEntry{
+		Action:      ActionUpdate,
+		VersionFrom: "0.0.1",
+		VersionTo:   "99.0.0",
+		Status:      StatusInProgress,
+		Notes:       awaitingHealthCheckNote,
+	})
+	if err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+
+	adapter := NewInstallShAdapter(history)
+	adapter.healthURL = healthURL
+	return m, adapter, eventID
+}
+
+func TestSuperviseMarksHealthyUpdateSuccessful(t *testing.T) {
+	calls := fakeSystemctl(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m, adapter, eventID := newPendingUpdateManager(t, server.URL)
+	if err := m.supervise(context.Background(), adapter, eventID, 10*time.Second); err != nil {
+		t.Fatalf("supervise: %v", err)
+	}
+
+	entry, err := m.history.GetEntry(eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Status != StatusSuccess || entry.Notes != "" {
+		t.Fatalf("expected successful entry, got %+v", entry)
+	}
+	if data, _ := os.ReadFile(calls); !strings.Contains(string(data), "restart pulse\n") {
+		t.Fatalf("expected the supervisor to restart the service, systemctl calls:\n%s", data)
+	}
+}
+
+func TestSuperviseRecordsFailedHealthCheck(t *testing.T) {
+	fakeSystemctl(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	m, adapter, eventID := newPendingUpdateManager(t, server.URL)
+	m.config.AutoUpdateRollback = false
+	if err := m.supervise(context.Background(), adapter, eventID, 100*time.Millisecond); err == nil {
+		t.Fatal("expected a health check failure")
+	}
+
+	entry, err := m.history.GetEntry(eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Status != StatusFailed || entry.Error == nil || entry.Error.Code != "health_check_failed" {
+		t.Fatalf("expected health check failure, got %+v", entry)
+	}
+	if !m.previouslyFailed("v99.0.0") {
+		t.Fatal("expected failed version to be skipped by automatic updates")
+	}
+}
+
+func TestHistorySeesSupervisorWrites(t *testing.T) {
+	dir := t.TempDir()
+	server, err := NewUpdateHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventID, err := server.CreateEntry(context.Background(), UpdateHistoryEntry{Action: ActionUpdate, Status: StatusInProgress})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The supervisor process opens the same log and records the outcome
+	supervisor, err := NewUpdateHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := supervisor.UpdateEntry(context.Background(), eventID, func(e *UpdateHistoryEntry) error {
+		e.Status = StatusSuccess
+		e.Notes = "verified by supervisor"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := server.GetEntry(eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Status != StatusSuccess {
+		t.Fatalf("server history did not pick up the supervisor's update: %+v", entry)
+	}
+}
+
+func TestValidateHealthURL(t *testing.T) {
+	for raw, ok := range map[string]bool{
+		"http://127.0.0.1:7655/api/health":  true,
+		"https://localhost:8443/api/health": true,
+		"http://[::1]:7655/api/health":      true,
+		"http://example.com/api/health":     false,
+		"http://127.0.0.1:7655/other":       false,
+		"file:///api/health":                false,
+	} {
+		if err := validateHealthURL(raw); (err == nil) != ok {
+			t.Errorf("validateHealthURL(%q) = %v", raw, err)
+		}
+	}
+}
+
+func TestSuperviseRefusesUnexpectedBackupPath(t *testing.T) {
+	calls := fakeSystemctl(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	m, adapter, eventID := newPendingUpdateManager(t, server.URL)
+	// The history is writable by the pulse user, so its backup path is not trusted
+	if err := m.history.UpdateEntry(context.Background(), eventID, func(e *UpdateHistoryEntry) error {
+		e.BackupPath = "/etc"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.supervise(context.Background(), adapter, eventID, 100*time.Millisecond); err == nil {
+		t.Fatal("expected a health check failure")
+	}
+
+	entry, err := m.history.GetEntry(eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Status != StatusFailed || entry.Error == nil || entry.Error.Code != "health_check_failed" {
+		t.Fatalf("expected the update to fail without a rollback, got %+v", entry)
+	}
+	if data, _ := os.ReadFile(calls); strings.Contains(string(data), "stop pulse") {
+		t.Fatalf("expected no rollback, systemctl calls:\n%s", data)
+	}
+}
+
+func TestValidateBackupPath(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv("PULSE_DATA_DIR", dataDir)
+	for path, ok := range map[string]bool{
+		filepath.Join(dataDir, "backup-20250101-120000"): true,
+		"/tmp/pulse-backup-20250101-120000":              true,
+		filepath.Join(dataDir, "config"):                 false,
+		dataDir + "/backup-1/../../etc":                  false,
+		"/etc":                                           false,
+		"/tmp/backup-20250101-120000":                    false,
+	} {
+		if err := validateBackupPath(path); (err == nil) != ok {
+			t.Errorf("validateBackupPath(%q) = %v", path, err)
+		}
+	}
+}
+
+func TestServiceControlOnlyRunsServiceActions(t *testing.T) {
+	calls := fakeSystemctl(t)
+	requests, childRequests, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	childReplies, replies, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go serveServiceControl(context.Background(), requests, replies, "pulse")
+	t.Cleanup(func() {
+		childRequests.Close()
+		childReplies.Close()
+	})
+
+	client := &serviceControlClient{requests: childRequests, replies: bufio.NewReader(childReplies)}
+	// The unprivileged side cannot choose the service
+	if err := client.run(context.Background(), "restart", "sshd"); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	for _, action := range []string{"enable", "restart sshd", "mask"} {
+		if err := client.run(context.Background(), action, "pulse"); err == nil {
+			t.Errorf("expected %q to be refused", action)
+		}
+	}
+
+	data, _ := os.ReadFile(calls)
+	if string(data) != "restart pulse\n" {
+		t.Fatalf("unexpected systemctl calls:\n%s", data)
+	}
+}
@@ -179,6 +179,7 @@ type Client struct {
 	send     chan []byte
 	id       string
 	lastPing time.Time
+	scope    string // Tenant scope key; empty for unrestricted clients
 }
 
 // cloneAlertData returns a broadcast-safe copy of alert data to avoid data races when
@@ -274,6 +275,10 @@ type Hub struct {
 	mu             sync.RWMutex
 	getState       func() interface{} // Function to get current state
 	allowedOrigins []string           // Allowed origins for CORS
+	// Tenant scoping: scopeResolver assigns a scope key to new clients and messageFilter
+	// returns what a scope may see of a message (false drops it for that scope)
+	scopeResolver func(*http.Request) string
+	messageFilter func(scope string, msg Message) (Message, bool)
 }
 
 // Message represents a WebSocket message
@@ -290,6 +295,15 @@ func (h *Hub) SetStateGetter(getState func() interface{}) {
 	h.getState = getState
 }
 
+// SetTenantScoping configures how clients are assigned tenant scopes and how messages are
+// filtered for them. Clients with an empty scope receive every message unfiltered.
+func (h *Hub) SetTenantScoping(resolver func(*http.Request) string, filter func(scope string, msg Message) (Message, bool)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.scopeResolver = resolver
+	h.messageFilter = filter
+}
+
 // NewHub creates a new WebSocket hub
 func NewHub(getState func() interface{}) *Hub {
 	return &Hub{
@@ -353,8 +367,17 @@ func (h *Hub) Run() {
 
 					initialMsg := Message{
 						Type: "initialState",
-						Data: sanitizeData(stateData),
+						Data: stateData,
 					}
+					if client.scope != "" {
+						filtered, ok := h.filterForScope(client.scope, initialMsg)
+						if !ok {
+							log.Warn().Str("client", client.id).Msg("No state visible to tenant scoped client")
+							return
+						}
+						initialMsg = filtered
+					}
+					initialMsg.Data = sanitizeData(initialMsg.Data)
 					if data, err := json.Marshal(initialMsg); err == nil {
 						// Check if client is still registered before sending
 						if _, ok := h.clients[client]; ok {
@@ -397,6 +420,10 @@ func (h *Hub) Run() {
 			h.mu.RUnlock()
 
 			for _, client := range clients {
+				if client.scope != "" {
+					// Scoped clients get filtered copies from broadcastScoped
+					continue
+				}
 				select {
 				case client.send <- message:
 				default:
@@ -435,6 +462,14 @@ func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.mu.RLock()
+	resolver := h.scopeResolver
+	h.mu.RUnlock()
+	scope := ""
+	if resolver != nil {
+		scope = resolver(r)
+	}
+
 	clientID := utils.GenerateID("client")
 	client := &Client{
 		hub:      h,
@@ -442,6 +477,7 @@ func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		send:     make(chan []byte, 1024), // Increased buffer for high-frequency updates
 		id:       clientID,
 		lastPing: time.Now(),
+		scope:    scope,
 	}
 
 	log.Info().Str("client", clientID).Msg("WebSocket client created")
@@ -512,6 +548,8 @@ func (h *Hub) Broadcast(data interface{}) {
 
 // BroadcastMessage sends a message to all clients
 func (h *Hub) BroadcastMessage(msg Message) {
+	h.broadcastScoped(msg)
+
 	// Sanitize the message data to handle NaN values
 	msg.Data = sanitizeData(msg.Data)
 
@@ -533,6 +571,57 @@ func (h *Hub) BroadcastMessage(msg Message) {
 	}
 }
 
+// filterForScope applies the message filter for a tenant scope. Without a filter, scoped
+// clients receive nothing rather than unfiltered data.
+func (h *Hub) filterForScope(scope string, msg Message) (Message, bool) {
+	h.mu.RLock()
+	filter := h.messageFilter
+	h.mu.RUnlock()
+	if filter == nil {
+		return Message{}, false
+	}
+	return filter(scope, msg)
+}
+
+// broadcastScoped sends each tenant scoped client the part of a message its scope may see.
+// The message is filtered and encoded once per scope.
+func (h *Hub) broadcastScoped(msg Message) {
+	h.mu.RLock()
+	byScope := make(map[string][]*Client)
+	for client := range h.clients {
+		if client.scope != "" {
+			byScope[client.scope] = append(byScope[client.scope], client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for scope, clients := range byScope {
+		filtered, ok := h.filterForScope(scope, msg)
+		if !ok {
+			continue
+		}
+		filtered.Data = sanitizeData(filtered.Data)
+		data, err := json.Marshal(filtered)
+		if err != nil {
+			log.Error().Err(err).Str("type", msg.Type).Str("scope", scope).Msg("Failed to marshal scoped WebSocket message")
+			continue
+		}
+
+		h.mu.RLock()
+		for _, client := range clients {
+			if !h.clients[client] {
+				continue
+			}
+			select {
+			case client.send <- data:
+			default:
+				log.Warn().Str("client", client.id).Msg("Scoped client send buffer full, dropping message")
+			}
+		}
+		h.mu.RUnlock()
+	}
+}
+
 // sendPing sends a ping message to all clients
 func (h *Hub) sendPing() {
 	msg := Message{
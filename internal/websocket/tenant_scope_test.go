@@ -0,0 +1,44 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBroadcastScopedClients(t *testing.T) {
+	hub := NewHub(nil)
+	hub.SetTenantScoping(nil, func(scope string, msg Message) (Message, bool) {
+		if msg.Type != "counter" {
+			return msg, false
+		}
+		msg.Data = map[string]string{"scope": scope}
+		return msg, true
+	})
+
+	unscoped := &Client{hub: hub, send: make(chan []byte, 4), id: "unscoped"}
+	teamA := &Client{hub: hub, send: make(chan []byte, 4), id: "team-a", scope: "a"}
+	hub.clients[unscoped] = true
+	hub.clients[teamA] = true
+
+	hub.BroadcastMessage(Message{Type: "counter", Data: map[string]int{"value": 1}})
+	hub.BroadcastMessage(Message{Type: "custom", Data: "secret"})
+
+	if len(teamA.send) != 1 {
+		t.Fatalf("expected scoped client to get only the filtered message, got %d", len(teamA.send))
+	}
+	var msg struct {
+		Type string            `json:"type"`
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(<-teamA.send, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Type != "counter" || msg.Data["scope"] != "a" {
+		t.Fatalf("unexpected scoped message: %+v", msg)
+	}
+
+	// Unscoped clients are served from the broadcast channel by Run
+	if len(hub.broadcast) != 2 || len(unscoped.send) != 0 {
+		t.Fatalf("expected both messages queued for unscoped clients")
+	}
+}
@@ -34,6 +34,8 @@ type ClientConfig struct {
 	Fingerprint string
 	VerifySSL   bool
 	Timeout     time.Duration
+	// WrapTransport, when set, wraps the HTTP transport, e.g. to trace requests
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // auth represents authentication details
@@ -110,6 +112,9 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 		timeout = 60 * time.Second
 	}
 	httpClient := tlsutil.CreateHTTPClientWithTimeout(cfg.VerifySSL, cfg.Fingerprint, timeout)
+	if cfg.WrapTransport != nil {
+		httpClient.Transport = cfg.WrapTransport(httpClient.Transport)
+	}
 
 	client := &Client{
 		baseURL:    strings.TrimSuffix(cfg.Host, "/") + "/api2/json",
@@ -0,0 +1,103 @@
+package pbs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// AptUpdate is a pending package upgrade reported by the PBS apt cache
+type AptUpdate struct {
+	Package    string `json:"Package"`
+	Title      string `json:"Title,omitempty"`
+	Version    string `json:"Version"`
+	OldVersion string `json:"OldVersion,omitempty"`
+	Origin     string `json:"Origin,omitempty"`
+	Priority   string `json:"Priority,omitempty"`
+	Section    string `json:"Section,omitempty"`
+}
+
+// AptPackageVersion is an installed package. PBS reports the running kernel as
+// "running kernel: <release>" in ExtraInfo of the proxmox-backup-server entry.
+type AptPackageVersion struct {
+	Package      string `json:"Package"`
+	Version      string `json:"Version,omitempty"`
+	OldVersion   string `json:"OldVersion,omitempty"`
+	CurrentState string `json:"CurrentState,omitempty"`
+	ExtraInfo    string `json:"ExtraInfo,omitempty"`
+}
+
+// Subscription is the PBS subscription status; the key itself is not decoded
+type Subscription struct {
+	Status      string `json:"status"`
+	Level       string `json:"level,omitempty"`
+	ProductName string `json:"productname,omitempty"`
+	NextDueDate string `json:"nextduedate,omitempty"`
+	RegDate     string `json:"regdate,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// CertificateInfo describes a TLS certificate installed on the PBS node
+type CertificateInfo struct {
+	Filename    string   `json:"filename"`
+	Subject     string   `json:"subject,omitempty"`
+	Issuer      string   `json:"issuer,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	NotBefore   int64    `json:"notbefore,omitempty"`
+	NotAfter    int64    `json:"notafter,omitempty"`
+	SAN         []string `json:"san,omitempty"`
+}
+
+// GetAptUpdates returns the pending package upgrades on the PBS node
+func (c *Client) GetAptUpdates(ctx context.Context) ([]AptUpdate, error) {
+	var result struct {
+		Data []AptUpdate `json:"data"`
+	}
+	if err := c.getData(ctx, "/nodes/localhost/apt/update", &result); err != nil {
+		return nil, fmt.Errorf("failed to get apt updates: %w", err)
+	}
+	return result.Data, nil
+}
+
+// GetAptVersions returns the installed Proxmox Backup Server packages
+func (c *Client) GetAptVersions(ctx context.Context) ([]AptPackageVersion, error) {
+	var result struct {
+		Data []AptPackageVersion `json:"data"`
+	}
+	if err := c.getData(ctx, "/nodes/localhost/apt/versions", &result); err != nil {
+		return nil, fmt.Errorf("failed to get package versions: %w", err)
+	}
+	return result.Data, nil
+}
+
+// GetSubscription returns the subscription status of the PBS node
+func (c *Client) GetSubscription(ctx context.Context) (*Subscription, error) {
+	var result struct {
+		Data Subscription `json:"data"`
+	}
+	if err := c.getData(ctx, "/nodes/localhost/subscription", &result); err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return &result.Data, nil
+}
+
+// GetCertificates returns the TLS certificates installed on the PBS node
+func (c *Client) GetCertificates(ctx context.Context) ([]CertificateInfo, error) {
+	var result struct {
+		Data []CertificateInfo `json:"data"`
+	}
+	if err := c.getData(ctx, "/nodes/localhost/certificates/info", &result); err != nil {
+		return nil, fmt.Errorf("failed to get certificates: %w", err)
+	}
+	return result.Data, nil
+}
+
+func (c *Client) getData(ctx context.Context, path string, out interface{}) error {
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
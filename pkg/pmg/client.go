@@ -33,6 +33,8 @@ type ClientConfig struct {
 	Fingerprint string
 	VerifySSL   bool
 	Timeout     time.Duration
+	// WrapTransport, when set, wraps the HTTP transport, e.g. to trace requests
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 type auth struct {
@@ -178,6 +180,9 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 	}
 
 	httpClient := tlsutil.CreateHTTPClientWithTimeout(cfg.VerifySSL, cfg.Fingerprint, cfg.Timeout)
+	if cfg.WrapTransport != nil {
+		httpClient.Transport = cfg.WrapTransport(httpClient.Transport)
+	}
 
 	client := &Client{
 		baseURL:    strings.TrimSuffix(cfg.Host, "/") + "/api2/json",
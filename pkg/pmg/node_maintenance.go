@@ -0,0 +1,85 @@
+package pmg
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// AptUpdate is a pending package upgrade reported by a PMG node
+type AptUpdate struct {
+	Package    string `json:"Package"`
+	Title      string `json:"Title,omitempty"`
+	Version    string `json:"Version"`
+	OldVersion string `json:"OldVersion,omitempty"`
+	Origin     string `json:"Origin,omitempty"`
+	Priority   string `json:"Priority,omitempty"`
+	Section    string `json:"Section,omitempty"`
+}
+
+// AptPackageVersion is an installed package; RunningKernel is set on the proxmox-mailgateway entry
+type AptPackageVersion struct {
+	Package       string `json:"Package"`
+	Version       string `json:"Version,omitempty"`
+	OldVersion    string `json:"OldVersion,omitempty"`
+	CurrentState  string `json:"CurrentState,omitempty"`
+	RunningKernel string `json:"RunningKernel,omitempty"`
+	ExtraInfo     string `json:"ExtraInfo,omitempty"`
+}
+
+// Subscription is the PMG subscription status; the key itself is not decoded
+type Subscription struct {
+	Status      string `json:"status"`
+	Level       string `json:"level,omitempty"`
+	ProductName string `json:"productname,omitempty"`
+	NextDueDate string `json:"nextduedate,omitempty"`
+	RegDate     string `json:"regdate,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// CertificateInfo describes a TLS certificate installed on a PMG node
+type CertificateInfo struct {
+	Filename    string      `json:"filename"`
+	Subject     string      `json:"subject,omitempty"`
+	Issuer      string      `json:"issuer,omitempty"`
+	Fingerprint string      `json:"fingerprint,omitempty"`
+	NotBefore   flexibleInt `json:"notbefore,omitempty"`
+	NotAfter    flexibleInt `json:"notafter,omitempty"`
+	SAN         []string    `json:"san,omitempty"`
+}
+
+// GetAptUpdates returns the pending package upgrades on a PMG node
+func (c *Client) GetAptUpdates(ctx context.Context, node string) ([]AptUpdate, error) {
+	var resp apiResponse[[]AptUpdate]
+	if err := c.getJSON(ctx, fmt.Sprintf("/nodes/%s/apt/update", url.PathEscape(node)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetAptVersions returns the installed Proxmox Mail Gateway packages on a node
+func (c *Client) GetAptVersions(ctx context.Context, node string) ([]AptPackageVersion, error) {
+	var resp apiResponse[[]AptPackageVersion]
+	if err := c.getJSON(ctx, fmt.Sprintf("/nodes/%s/apt/versions", url.PathEscape(node)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetSubscription returns the subscription status of a PMG node
+func (c *Client) GetSubscription(ctx context.Context, node string) (*Subscription, error) {
+	var resp apiResponse[Subscription]
+	if err := c.getJSON(ctx, fmt.Sprintf("/nodes/%s/subscription", url.PathEscape(node)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// GetCertificates returns the TLS certificates installed on a PMG node
+func (c *Client) GetCertificates(ctx context.Context, node string) ([]CertificateInfo, error) {
+	var resp apiResponse[[]CertificateInfo]
+	if err := c.getJSON(ctx, fmt.Sprintf("/nodes/%s/certificates/info", url.PathEscape(node)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,107 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BackupMode is the vzdump consistency mode
+type BackupMode string
+
+const (
+	BackupModeSnapshot BackupMode = "snapshot"
+	BackupModeSuspend  BackupMode = "suspend"
+	BackupModeStop     BackupMode = "stop"
+)
+
+// BackupOptions configures a vzdump run. Empty fields fall back to the node's vzdump.conf.
+type BackupOptions struct {
+	// Storage is the target storage ID; Proxmox Backup Server targets are PVE storages of type pbs
+	Storage string
+	Mode    BackupMode
+	// Compress is one of "0", "gzip", "lzo" or "zstd" (ignored by PBS storages)
+	Compress string
+	// Notes is passed as notes-template and may use {{guestname}}, {{node}} and {{vmid}}
+	Notes     string
+	Protected bool
+}
+
+// Validate returns an error for options vzdump would reject
+func (o BackupOptions) Validate() error {
+	switch o.Mode {
+	case "", BackupModeSnapshot, BackupModeSuspend, BackupModeStop:
+	default:
+		return fmt.Errorf("unsupported backup mode %q", o.Mode)
+	}
+	switch o.Compress {
+	case "", "0", "gzip", "lzo", "zstd":
+	default:
+		return fmt.Errorf("unsupported compression %q", o.Compress)
+	}
+	if strings.ContainsAny(o.Storage, "/ ") {
+		return fmt.Errorf("invalid storage %q", o.Storage)
+	}
+	return nil
+}
+
+// IsBackupTaskSuccess reports whether a finished vzdump task status means the backup was written.
+// vzdump ends with "WARNINGS: n" when a backup succeeded with non-fatal warnings.
+func IsBackupTaskSuccess(status string) bool {
+	return status == "OK" || strings.HasPrefix(status, "WARNINGS")
+}
+
+// StartBackup runs vzdump for one or more guests on a node and returns the task UPID
+func (c *Client) StartBackup(ctx context.Context, node string, vmids []int, opts BackupOptions) (string, error) {
+	if len(vmids) == 0 {
+		return "", fmt.Errorf("no guests to back up")
+	}
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+
+	ids := make([]string, 0, len(vmids))
+	for _, vmid := range vmids {
+		ids = append(ids, strconv.Itoa(vmid))
+	}
+
+	data := url.Values{}
+	data.Set("vmid", strings.Join(ids, ","))
+	if opts.Storage != "" {
+		data.Set("storage", opts.Storage)
+	}
+	if opts.Mode != "" {
+		data.Set("mode", string(opts.Mode))
+	}
+	if opts.Compress != "" {
+		data.Set("compress", opts.Compress)
+	}
+	if opts.Notes != "" {
+		data.Set("notes-template", opts.Notes)
+	}
+	if opts.Protected {
+		data.Set("protected", "1")
+	}
+
+	resp, err := c.post(ctx, fmt.Sprintf("/nodes/%s/vzdump", url.PathEscape(node)), data)
+	if err != nil {
+		return "", err
+	}
+	return decodeTaskID(resp, fmt.Sprintf("back up guests %s", strings.Join(ids, ",")))
+}
+
+// StartBackup runs vzdump through a healthy cluster endpoint
+func (cc *ClusterClient) StartBackup(ctx context.Context, node string, vmids []int, opts BackupOptions) (string, error) {
+	var upid string
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		result, err := client.StartBackup(ctx, node, vmids, opts)
+		if err != nil {
+			return noFailover(err)
+		}
+		upid = result
+		return nil
+	})
+	return upid, unwrapAPIResponseError(err)
+}
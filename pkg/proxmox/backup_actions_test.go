@@ -0,0 +1,59 @@
+package proxmox
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestClientStartBackup(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		posts []string
+	)
+	server := newGuestActionServer(t, &posts, &mu)
+	defer server.Close()
+
+	client, err := NewClient(testClientConfig(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	upid, err := client.StartBackup(ctx, "node1", []int{101, 102}, BackupOptions{
+		Storage:  "pbs-main",
+		Mode:     BackupModeSnapshot,
+		Compress: "zstd",
+	})
+	if err != nil || upid != testUPID {
+		t.Fatalf("StartBackup: upid=%q err=%v", upid, err)
+	}
+
+	if _, err := client.StartBackup(ctx, "node1", []int{101}, BackupOptions{Mode: "live"}); err == nil {
+		t.Fatal("expected invalid mode to be rejected")
+	}
+	if _, err := client.StartBackup(ctx, "node1", nil, BackupOptions{}); err == nil {
+		t.Fatal("expected empty guest list to be rejected")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := "/api2/json/nodes/node1/vzdump?compress=zstd&mode=snapshot&storage=pbs-main&vmid=101%2C102"
+	if len(posts) != 1 || posts[0] != want {
+		t.Fatalf("posts = %v, want [%s]", posts, want)
+	}
+}
+
+func TestIsBackupTaskSuccess(t *testing.T) {
+	for status, want := range map[string]bool{
+		"OK":                true,
+		"WARNINGS: 2":       true,
+		"job errors":        false,
+		"unexpected status": false,
+		"":                  false,
+	} {
+		if got := IsBackupTaskSuccess(status); got != want {
+			t.Errorf("IsBackupTaskSuccess(%q) = %t, want %t", status, got, want)
+		}
+	}
+}
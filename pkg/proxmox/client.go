@@ -135,6 +135,8 @@ type ClientConfig struct {
 	Fingerprint string
 	VerifySSL   bool
 	Timeout     time.Duration
+	// WrapTransport, when set, wraps the HTTP transport, e.g. to trace requests
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // auth represents authentication details
@@ -192,6 +194,9 @@ func NewClient(cfg ClientConfig) (*Client, error) {
 		timeout = 60 * time.Second
 	}
 	httpClient := tlsutil.CreateHTTPClientWithTimeout(cfg.VerifySSL, cfg.Fingerprint, timeout)
+	if cfg.WrapTransport != nil {
+		httpClient.Transport = cfg.WrapTransport(httpClient.Transport)
+	}
 
 	// Extract just the token name part for API token authentication
 	tokenName := cfg.TokenName
@@ -445,6 +450,11 @@ func (c *Client) post(ctx context.Context, path string, data url.Values) (*http.
 	return c.request(ctx, "POST", path, data)
 }
 
+// delete performs a DELETE request
+func (c *Client) delete(ctx context.Context, path string) (*http.Response, error) {
+	return c.request(ctx, "DELETE", path, nil)
+}
+
 // Node represents a Proxmox VE node
 type Node struct {
 	Node    string  `json:"node"`
@@ -1239,6 +1249,8 @@ type ClusterStatus struct {
 	Online  int    `json:"online"`  // 1 if online
 	Level   string `json:"level"`   // Connection level
 	Quorate int    `json:"quorate"` // 1 if cluster has quorum
+	Nodes   int    `json:"nodes"`   // Member count (cluster entry only)
+	Version int    `json:"version"` // Corosync config version (cluster entry only)
 }
 
 // GetClusterStatus returns the cluster status including all nodes
@@ -1676,6 +1688,7 @@ type ClusterResource struct {
 	Uptime    uint64  `json:"uptime,omitempty"`
 	Template  int     `json:"template,omitempty"`
 	Tags      string  `json:"tags,omitempty"`
+	Pool      string  `json:"pool,omitempty"`
 }
 
 // GetClusterResources returns all resources (VMs, containers) across the cluster
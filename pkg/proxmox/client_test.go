@@ -1,9 +1,14 @@
 package proxmox
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestDiskUnmarshalWearout(t *testing.T) {
@@ -370,3 +375,38 @@ func TestMemoryStatusEffectiveAvailable_RegressionIssue435(t *testing.T) {
 		})
 	}
 }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestClientWrapTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":[{"node":"pve1","status":"online"}]}`)
+	}))
+	defer server.Close()
+
+	var wrapped atomic.Int32
+	client, err := NewClient(ClientConfig{
+		Host:       server.URL,
+		TokenName:  "pulse@pve!token",
+		TokenValue: "secret",
+		Timeout:    2 * time.Second,
+		WrapTransport: func(base http.RoundTripper) http.RoundTripper {
+			return roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				wrapped.Add(1)
+				return base.RoundTrip(r)
+			})
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := client.GetNodes(context.Background()); err != nil {
+		t.Fatalf("GetNodes: %v", err)
+	}
+	if wrapped.Load() == 0 {
+		t.Fatal("expected requests to go through the wrapped transport")
+	}
+}
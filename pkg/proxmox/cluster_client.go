@@ -2,6 +2,7 @@ package proxmox
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -553,6 +554,12 @@ func (cc *ClusterClient) executeWithFailover(ctx context.Context, fn func(*Clien
 			continue
 		}
 
+		var apiErr *apiResponseError
+		if errors.As(err, &apiErr) {
+			// The endpoint answered and rejected the request; retrying elsewhere would replay it
+			return err
+		}
+
 		// Check if it's an auth error - don't retry on auth errors
 		if IsAuthError(err) {
 			return err
@@ -0,0 +1,273 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// GuestType identifies the Proxmox API family for a guest
+type GuestType string
+
+const (
+	GuestTypeVM        GuestType = "qemu"
+	GuestTypeContainer GuestType = "lxc"
+)
+
+// GuestAction is a power or lifecycle operation on a VM or container
+type GuestAction string
+
+const (
+	GuestActionStart    GuestAction = "start"
+	GuestActionShutdown GuestAction = "shutdown"
+	GuestActionReboot   GuestAction = "reboot"
+	GuestActionStop     GuestAction = "stop"
+	GuestActionSuspend  GuestAction = "suspend"
+	GuestActionResume   GuestAction = "resume"
+	GuestActionMigrate  GuestAction = "migrate"
+)
+
+// ParseGuestAction validates an action name
+func ParseGuestAction(value string) (GuestAction, error) {
+	switch action := GuestAction(strings.ToLower(strings.TrimSpace(value))); action {
+	case GuestActionStart, GuestActionShutdown, GuestActionReboot, GuestActionStop,
+		GuestActionSuspend, GuestActionResume, GuestActionMigrate:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unsupported guest action %q", value)
+	}
+}
+
+// GuestActionOptions holds optional parameters for a guest action
+type GuestActionOptions struct {
+	// TargetNode is required for migrate
+	TargetNode string
+	// Online requests live migration of a running VM (containers are restarted instead)
+	Online bool
+	// Timeout in seconds for shutdown before Proxmox gives up (0 uses the Proxmox default)
+	Timeout int
+	// ForceStop hard-stops the guest if shutdown times out
+	ForceStop bool
+}
+
+// TaskResult is the final state of a Proxmox task
+type TaskResult struct {
+	UPID       string   `json:"upid"`
+	Node       string   `json:"node"`
+	Status     string   `json:"status"`
+	ExitStatus string   `json:"exitStatus"`
+	Log        []string `json:"log,omitempty"`
+}
+
+// Succeeded reports whether the task finished without error
+func (r *TaskResult) Succeeded() bool {
+	return r != nil && r.ExitStatus == "OK"
+}
+
+// apiResponseError wraps an error from a request that may have reached a Proxmox endpoint.
+// Cluster clients return it without failing over so an action is not replayed against
+// another node.
+type apiResponseError struct {
+	err error
+}
+
+func (e *apiResponseError) Error() string { return e.err.Error() }
+func (e *apiResponseError) Unwrap() error { return e.err }
+
+// noFailover wraps every error except those raised before the request was sent. A timeout or
+// dropped connection after the POST went out may mean Proxmox already started the task.
+func noFailover(err error) error {
+	if err == nil || IsAuthError(err) || requestNotSent(err) {
+		return err
+	}
+	return &apiResponseError{err: err}
+}
+
+// requestNotSent reports whether err means the connection was never established, so the
+// request cannot have reached the endpoint
+func requestNotSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// StartGuestAction submits a power or lifecycle action and returns the task UPID
+func (c *Client) StartGuestAction(ctx context.Context, node string, guestType GuestType, vmid int, action GuestAction, opts GuestActionOptions) (string, error) {
+	if guestType != GuestTypeVM && guestType != GuestTypeContainer {
+		return "", fmt.Errorf("unsupported guest type %q", guestType)
+	}
+
+	base := fmt.Sprintf("/nodes/%s/%s/%d", url.PathEscape(node), guestType, vmid)
+	path := fmt.Sprintf("%s/status/%s", base, action)
+	data := url.Values{}
+
+	switch action {
+	case GuestActionShutdown:
+		if opts.Timeout > 0 {
+			data.Set("timeout", strconv.Itoa(opts.Timeout))
+		}
+		if opts.ForceStop {
+			data.Set("forceStop", "1")
+		}
+	case GuestActionMigrate:
+		if opts.TargetNode == "" {
+			return "", fmt.Errorf("migrate requires a target node")
+		}
+		if opts.TargetNode == node {
+			return "", fmt.Errorf("guest is already on node %s", node)
+		}
+		path = base + "/migrate"
+		data.Set("target", opts.TargetNode)
+		if opts.Online {
+			if guestType == GuestTypeVM {
+				data.Set("online", "1")
+			} else {
+				data.Set("restart", "1")
+			}
+		}
+	case GuestActionStart, GuestActionReboot, GuestActionStop, GuestActionSuspend, GuestActionResume:
+	default:
+		return "", fmt.Errorf("unsupported guest action %q", action)
+	}
+
+	resp, err := c.post(ctx, path, data)
+	if err != nil {
+		return "", err
+	}
+	return decodeTaskID(resp, fmt.Sprintf("%s guest %d", action, vmid))
+}
+
+// decodeTaskID reads the UPID returned by an asynchronous Proxmox call
+func decodeTaskID(resp *http.Response, operation string) (string, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to %s (status %d): %s", operation, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data string `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("unexpected response format: %w", err)
+	}
+	if !strings.HasPrefix(result.Data, "UPID:") {
+		return "", fmt.Errorf("unexpected response format: missing task id")
+	}
+	return result.Data, nil
+}
+
+// taskProgressInterval is how often FollowTask polls a running task for new log lines
+const taskProgressInterval = 2 * time.Second
+
+// WaitForTask blocks until the task finishes and returns its status and log
+func (c *Client) WaitForTask(ctx context.Context, node, upid string) (*TaskResult, error) {
+	status, err := c.waitForTaskCompletion(ctx, node, upid)
+	if err != nil {
+		return nil, err
+	}
+	return c.taskResult(ctx, node, upid, status), nil
+}
+
+// FollowTask blocks until the task finishes like WaitForTask. While the task runs, onProgress
+// receives its status and log whenever new log lines appear.
+func (c *Client) FollowTask(ctx context.Context, node, upid string, onProgress func(TaskResult)) (*TaskResult, error) {
+	ticker := time.NewTicker(taskProgressInterval)
+	defer ticker.Stop()
+
+	reported := 0
+	for {
+		status, err := c.getTaskStatus(ctx, node, upid)
+		if err != nil {
+			return nil, err
+		}
+		state := strings.ToLower(status.Status)
+		if state != "running" && state != "active" {
+			return c.taskResult(ctx, node, upid, status), nil
+		}
+
+		if onProgress != nil {
+			if lines, err := c.getTaskLog(ctx, node, upid); err == nil && len(lines) > reported {
+				reported = len(lines)
+				onProgress(TaskResult{UPID: upid, Node: node, Status: status.Status, Log: lines})
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) taskResult(ctx context.Context, node, upid string, status *taskStatusResponse) *TaskResult {
+	result := &TaskResult{UPID: upid, Node: node, Status: status.Status, ExitStatus: status.ExitStatus}
+	if lines, err := c.getTaskLog(ctx, node, upid); err == nil {
+		result.Log = lines
+	}
+	return result
+}
+
+// StartGuestAction submits a guest action through a healthy cluster endpoint
+func (cc *ClusterClient) StartGuestAction(ctx context.Context, node string, guestType GuestType, vmid int, action GuestAction, opts GuestActionOptions) (string, error) {
+	var upid string
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		result, err := client.StartGuestAction(ctx, node, guestType, vmid, action, opts)
+		if err != nil {
+			return noFailover(err)
+		}
+		upid = result
+		return nil
+	})
+	return upid, unwrapAPIResponseError(err)
+}
+
+// WaitForTask follows a task through any healthy cluster endpoint
+func (cc *ClusterClient) WaitForTask(ctx context.Context, node, upid string) (*TaskResult, error) {
+	var result *TaskResult
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		task, err := client.WaitForTask(ctx, node, upid)
+		if err != nil {
+			return err
+		}
+		result = task
+		return nil
+	})
+	return result, err
+}
+
+// FollowTask follows a task and its progress through any healthy cluster endpoint
+func (cc *ClusterClient) FollowTask(ctx context.Context, node, upid string, onProgress func(TaskResult)) (*TaskResult, error) {
+	var result *TaskResult
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		task, err := client.FollowTask(ctx, node, upid, onProgress)
+		if err != nil {
+			return err
+		}
+		result = task
+		return nil
+	})
+	return result, err
+}
+
+func unwrapAPIResponseError(err error) error {
+	var apiErr *apiResponseError
+	if errors.As(err, &apiErr) {
+		return apiErr.err
+	}
+	return err
+}
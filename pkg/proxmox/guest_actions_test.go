@@ -0,0 +1,231 @@
+package proxmox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+const testUPID = "UPID:node1:0000ABCD:00000001:65000000:qmstart:101:root@pam:"
+
+func newGuestActionServer(t *testing.T, posts *[]string, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			if err := r.ParseForm(); err != nil {
+				t.Errorf("parse form: %v", err)
+			}
+			mu.Lock()
+			*posts = append(*posts, r.URL.Path+"?"+r.PostForm.Encode())
+			mu.Unlock()
+			if strings.Contains(r.URL.Path, "/999/") {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"data":null,"message":"VM 999 already running"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"data":%q}`, testUPID)
+		case r.URL.Path == "/api2/json/nodes":
+			fmt.Fprint(w, `{"data":[{"node":"node1","status":"online"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			fmt.Fprint(w, `{"data":{"status":"stopped","exitstatus":"OK","type":"qmstart"}}`)
+		case strings.HasSuffix(r.URL.Path, "/log"):
+			fmt.Fprint(w, `{"data":[{"n":1,"t":"starting VM 101"},{"n":2,"t":"TASK OK"}]}`)
+		default:
+			fmt.Fprint(w, `{"data":{}}`)
+		}
+	}))
+}
+
+func testClientConfig(host string) ClientConfig {
+	return ClientConfig{
+		Host:       host,
+		TokenName:  "pulse@pve!token",
+		TokenValue: "sometokenvalue",
+		Timeout:    2 * time.Second,
+	}
+}
+
+func TestClientGuestActions(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		posts []string
+	)
+	server := newGuestActionServer(t, &posts, &mu)
+	defer server.Close()
+
+	client, err := NewClient(testClientConfig(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	upid, err := client.StartGuestAction(ctx, "node1", GuestTypeVM, 101, GuestActionShutdown, GuestActionOptions{Timeout: 60, ForceStop: true})
+	if err != nil || upid != testUPID {
+		t.Fatalf("shutdown: upid=%q err=%v", upid, err)
+	}
+	if _, err := client.StartGuestAction(ctx, "node1", GuestTypeContainer, 200, GuestActionMigrate, GuestActionOptions{TargetNode: "node2", Online: true}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err := client.StartGuestAction(ctx, "node1", GuestTypeVM, 101, GuestActionMigrate, GuestActionOptions{}); err == nil {
+		t.Fatal("expected migrate without target to fail")
+	}
+
+	want := []string{
+		"/api2/json/nodes/node1/qemu/101/status/shutdown?forceStop=1&timeout=60",
+		"/api2/json/nodes/node1/lxc/200/migrate?restart=1&target=node2",
+	}
+	if strings.Join(posts, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected requests:\n%s", strings.Join(posts, "\n"))
+	}
+
+	result, err := client.WaitForTask(ctx, "node1", upid)
+	if err != nil {
+		t.Fatalf("WaitForTask: %v", err)
+	}
+	if !result.Succeeded() || len(result.Log) != 2 || result.Log[1] != "TASK OK" {
+		t.Fatalf("unexpected task result %+v", result)
+	}
+}
+
+func TestClusterClientGuestActionRejectionDoesNotFailOver(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		posts []string
+	)
+	first := newGuestActionServer(t, &posts, &mu)
+	defer first.Close()
+	second := newGuestActionServer(t, &posts, &mu)
+	defer second.Close()
+
+	cc := NewClusterClient("test-cluster", testClientConfig(first.URL), []string{first.URL, second.URL})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := cc.StartGuestAction(ctx, "node1", GuestTypeVM, 999, GuestActionStart, GuestActionOptions{})
+	if err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("expected API rejection, got %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("expected the action to be submitted once, got %d requests", len(posts))
+	}
+	for endpoint, healthy := range cc.GetHealthStatus() {
+		if !healthy {
+			t.Fatalf("endpoint %s was marked unhealthy by an API rejection", endpoint)
+		}
+	}
+}
+
+func TestClientFollowTaskReportsProgress(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		statuses int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/status"):
+			statuses++
+			if statuses == 1 {
+				fmt.Fprint(w, `{"data":{"status":"running","type":"qmigrate"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"status":"stopped","exitstatus":"OK","type":"qmigrate"}}`)
+		case strings.HasSuffix(r.URL.Path, "/log"):
+			if statuses == 1 {
+				fmt.Fprint(w, `{"data":[{"n":1,"t":"starting migration of VM 101"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":[{"n":1,"t":"starting migration of VM 101"},{"n":2,"t":"TASK OK"}]}`)
+		default:
+			fmt.Fprint(w, `{"data":{}}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(testClientConfig(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var progress []TaskResult
+	result, err := client.FollowTask(ctx, "node1", testUPID, func(update TaskResult) {
+		progress = append(progress, update)
+	})
+	if err != nil {
+		t.Fatalf("FollowTask: %v", err)
+	}
+	if len(progress) != 1 || progress[0].Status != "running" || len(progress[0].Log) != 1 {
+		t.Fatalf("unexpected progress updates %+v", progress)
+	}
+	if !result.Succeeded() || len(result.Log) != 2 {
+		t.Fatalf("unexpected task result %+v", result)
+	}
+}
+
+func TestNoFailoverOnlyForUnsentRequests(t *testing.T) {
+	var apiErr *apiResponseError
+	refused := &url.Error{Op: "Post", URL: "https://pve1:8006", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	if errors.As(noFailover(refused), &apiErr) {
+		t.Fatal("expected a refused connection to allow failover")
+	}
+	timeout := &url.Error{Op: "Post", URL: "https://pve1:8006", Err: context.DeadlineExceeded}
+	if !errors.As(noFailover(timeout), &apiErr) {
+		t.Fatal("expected a timeout after sending to prevent failover")
+	}
+	reset := &url.Error{Op: "Post", URL: "https://pve1:8006", Err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}}
+	if !errors.As(noFailover(reset), &apiErr) {
+		t.Fatal("expected a connection reset after sending to prevent failover")
+	}
+}
+
+func TestClusterClientGuestActionTimeoutDoesNotFailOver(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		posts int
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			mu.Lock()
+			posts++
+			mu.Unlock()
+			// Accept the task but answer after the client has given up
+			time.Sleep(3 * time.Second)
+		}
+		fmt.Fprint(w, `{"data":[{"node":"node1","status":"online"}]}`)
+	})
+	first := httptest.NewServer(handler)
+	defer first.Close()
+	second := httptest.NewServer(handler)
+	defer second.Close()
+
+	cc := NewClusterClient("test-cluster", testClientConfig(first.URL), []string{first.URL, second.URL})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := cc.StartGuestAction(ctx, "node1", GuestTypeVM, 101, GuestActionReboot, GuestActionOptions{}); err == nil {
+		t.Fatal("expected the timeout to be returned")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if posts != 1 {
+		t.Fatalf("expected the reboot to be sent once, got %d requests", posts)
+	}
+}
@@ -0,0 +1,125 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// HAStatusEntry is one row of /cluster/ha/status/current. Type is "quorum", "master",
+// "lrm" or "service"; the service fields are only set for HA resources.
+type HAStatusEntry struct {
+	ID           string  `json:"id"`
+	Type         string  `json:"type"`
+	Node         string  `json:"node"`
+	Status       string  `json:"status"`
+	Quorate      FlexInt `json:"quorate,omitempty"`
+	Timestamp    int64   `json:"timestamp,omitempty"`
+	SID          string  `json:"sid,omitempty"`
+	State        string  `json:"state,omitempty"`
+	CRMState     string  `json:"crm_state,omitempty"`
+	RequestState string  `json:"request_state,omitempty"`
+	Group        string  `json:"group,omitempty"`
+	MaxRestart   FlexInt `json:"max_restart,omitempty"`
+	MaxRelocate  FlexInt `json:"max_relocate,omitempty"`
+}
+
+// DaemonState extracts the state from manager and LRM status strings such as
+// "pve1 (active, Mon Jan  1 10:00:00 2024)" or "pve2 (old timestamp - dead?, ...)".
+func (e HAStatusEntry) DaemonState() string {
+	start := strings.Index(e.Status, "(")
+	if start < 0 {
+		return strings.TrimSpace(e.Status)
+	}
+	rest := e.Status[start+1:]
+	if end := strings.IndexAny(rest, ",)"); end >= 0 {
+		rest = rest[:end]
+	}
+	return strings.TrimSpace(rest)
+}
+
+// ClusterConfigNode is a corosync node entry from /cluster/config/nodes
+type ClusterConfigNode struct {
+	Node        string  `json:"node"`
+	NodeID      FlexInt `json:"nodeid"`
+	QuorumVotes FlexInt `json:"quorum_votes"`
+	Ring0Addr   string  `json:"ring0_addr,omitempty"`
+}
+
+// GetHAStatus returns the HA manager, LRM and resource status (/cluster/ha/status/current)
+func (c *Client) GetHAStatus(ctx context.Context) ([]HAStatusEntry, error) {
+	resp, err := c.get(ctx, "/cluster/ha/status/current")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data []HAStatusEntry `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetClusterConfigNodes returns the corosync node list with quorum votes
+func (c *Client) GetClusterConfigNodes(ctx context.Context) ([]ClusterConfigNode, error) {
+	resp, err := c.get(ctx, "/cluster/config/nodes")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data []ClusterConfigNode `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetClusterStatus returns corosync membership and quorum with failover support
+func (cc *ClusterClient) GetClusterStatus(ctx context.Context) ([]ClusterStatus, error) {
+	var result []ClusterStatus
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		status, err := client.GetClusterStatus(ctx)
+		if err != nil {
+			return err
+		}
+		result = status
+		return nil
+	})
+	return result, err
+}
+
+// GetHAStatus returns HA status with failover support
+func (cc *ClusterClient) GetHAStatus(ctx context.Context) ([]HAStatusEntry, error) {
+	var result []HAStatusEntry
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		status, err := client.GetHAStatus(ctx)
+		if err != nil {
+			return err
+		}
+		result = status
+		return nil
+	})
+	return result, err
+}
+
+// GetClusterConfigNodes returns the corosync node list with failover support
+func (cc *ClusterClient) GetClusterConfigNodes(ctx context.Context) ([]ClusterConfigNode, error) {
+	var result []ClusterConfigNode
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		nodes, err := client.GetClusterConfigNodes(ctx)
+		if err != nil {
+			return err
+		}
+		result = nodes
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,17 @@
+package proxmox
+
+import "testing"
+
+func TestHAStatusEntryDaemonState(t *testing.T) {
+	cases := map[string]string{
+		"pve1 (active, Tue Nov 14 22:13:20 2023)":                "active",
+		"pve2 (old timestamp - dead?, Tue Nov 14 21:00:00 2023)": "old timestamp - dead?",
+		"pve3 (idle)": "idle",
+		"OK":          "OK",
+	}
+	for status, want := range cases {
+		if got := (HAStatusEntry{Status: status}).DaemonState(); got != want {
+			t.Errorf("DaemonState(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// AptUpdate is a pending package upgrade from /nodes/{node}/apt/update
+type AptUpdate struct {
+	Package     string `json:"Package"`
+	Title       string `json:"Title,omitempty"`
+	Description string `json:"Description,omitempty"`
+	Version     string `json:"Version"`
+	OldVersion  string `json:"OldVersion,omitempty"`
+	Origin      string `json:"Origin,omitempty"`
+	Priority    string `json:"Priority,omitempty"`
+	Section     string `json:"Section,omitempty"`
+}
+
+// AptPackageVersion is an installed package from /nodes/{node}/apt/versions.
+// RunningKernel is only set on the proxmox-ve entry.
+type AptPackageVersion struct {
+	Package       string `json:"Package"`
+	Version       string `json:"Version,omitempty"`
+	OldVersion    string `json:"OldVersion,omitempty"`
+	CurrentState  string `json:"CurrentState,omitempty"`
+	RunningKernel string `json:"RunningKernel,omitempty"`
+	ExtraInfo     string `json:"ExtraInfo,omitempty"`
+}
+
+// NodeSubscription is the subscription status of a node. The subscription key is
+// deliberately not decoded so it never ends up in Pulse state or logs.
+type NodeSubscription struct {
+	Status      string `json:"status"`
+	Level       string `json:"level,omitempty"`
+	ProductName string `json:"productname,omitempty"`
+	NextDueDate string `json:"nextduedate,omitempty"`
+	RegDate     string `json:"regdate,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// NodeCertificate describes a TLS certificate from /nodes/{node}/certificates/info
+type NodeCertificate struct {
+	Filename    string   `json:"filename"`
+	Subject     string   `json:"subject,omitempty"`
+	Issuer      string   `json:"issuer,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	NotBefore   int64    `json:"notbefore,omitempty"`
+	NotAfter    int64    `json:"notafter,omitempty"`
+	SAN         []string `json:"san,omitempty"`
+}
+
+// GetNodeAptUpdates returns the pending package upgrades known to the node's apt cache
+func (c *Client) GetNodeAptUpdates(ctx context.Context, node string) ([]AptUpdate, error) {
+	var result struct {
+		Data []AptUpdate `json:"data"`
+	}
+	if err := c.getNodeJSON(ctx, fmt.Sprintf("/nodes/%s/apt/update", url.PathEscape(node)), &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetNodeAptVersions returns the installed Proxmox-related packages, including kernels
+func (c *Client) GetNodeAptVersions(ctx context.Context, node string) ([]AptPackageVersion, error) {
+	var result struct {
+		Data []AptPackageVersion `json:"data"`
+	}
+	if err := c.getNodeJSON(ctx, fmt.Sprintf("/nodes/%s/apt/versions", url.PathEscape(node)), &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetNodeSubscription returns the subscription status of a node
+func (c *Client) GetNodeSubscription(ctx context.Context, node string) (*NodeSubscription, error) {
+	var result struct {
+		Data NodeSubscription `json:"data"`
+	}
+	if err := c.getNodeJSON(ctx, fmt.Sprintf("/nodes/%s/subscription", url.PathEscape(node)), &result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// GetNodeCertificates returns the TLS certificates installed on a node
+func (c *Client) GetNodeCertificates(ctx context.Context, node string) ([]NodeCertificate, error) {
+	var result struct {
+		Data []NodeCertificate `json:"data"`
+	}
+	if err := c.getNodeJSON(ctx, fmt.Sprintf("/nodes/%s/certificates/info", url.PathEscape(node)), &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (c *Client) getNodeJSON(ctx context.Context, path string, out interface{}) error {
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// GetNodeAptUpdates returns pending package upgrades with failover support
+func (cc *ClusterClient) GetNodeAptUpdates(ctx context.Context, node string) ([]AptUpdate, error) {
+	var result []AptUpdate
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		updates, err := client.GetNodeAptUpdates(ctx, node)
+		if err != nil {
+			return err
+		}
+		result = updates
+		return nil
+	})
+	return result, err
+}
+
+// GetNodeAptVersions returns installed package versions with failover support
+func (cc *ClusterClient) GetNodeAptVersions(ctx context.Context, node string) ([]AptPackageVersion, error) {
+	var result []AptPackageVersion
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		versions, err := client.GetNodeAptVersions(ctx, node)
+		if err != nil {
+			return err
+		}
+		result = versions
+		return nil
+	})
+	return result, err
+}
+
+// GetNodeSubscription returns node subscription status with failover support
+func (cc *ClusterClient) GetNodeSubscription(ctx context.Context, node string) (*NodeSubscription, error) {
+	var result *NodeSubscription
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		subscription, err := client.GetNodeSubscription(ctx, node)
+		if err != nil {
+			return err
+		}
+		result = subscription
+		return nil
+	})
+	return result, err
+}
+
+// GetNodeCertificates returns node certificates with failover support
+func (cc *ClusterClient) GetNodeCertificates(ctx context.Context, node string) ([]NodeCertificate, error) {
+	var result []NodeCertificate
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		certificates, err := client.GetNodeCertificates(ctx, node)
+		if err != nil {
+			return err
+		}
+		result = certificates
+		return nil
+	})
+	return result, err
+}
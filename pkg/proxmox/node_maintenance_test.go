@@ -0,0 +1,53 @@
+package proxmox
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientNodeMaintenance(t *testing.T) {
+	responses := map[string]string{
+		"/api2/json/nodes/pve1/apt/update":        `{"data":[{"Package":"libssl3","Version":"3.0.15-1~deb12u1","OldVersion":"3.0.14-1~deb12u2","Origin":"Debian","Section":"libs"}]}`,
+		"/api2/json/nodes/pve1/apt/versions":      `{"data":[{"Package":"proxmox-ve","Version":"8.2.0","CurrentState":"Installed","RunningKernel":"6.8.8-2-pve"}]}`,
+		"/api2/json/nodes/pve1/subscription":      `{"data":{"status":"active","level":"c","productname":"Proxmox VE Community","nextduedate":"2027-01-01","key":"pve1c-secret"}}`,
+		"/api2/json/nodes/pve1/certificates/info": `{"data":[{"filename":"pveproxy-ssl.pem","notafter":1893456000,"san":["pve1.example.com"]}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(testClientConfig(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	updates, err := client.GetNodeAptUpdates(ctx, "pve1")
+	if err != nil || len(updates) != 1 || updates[0].Package != "libssl3" || updates[0].OldVersion != "3.0.14-1~deb12u2" {
+		t.Fatalf("GetNodeAptUpdates = %+v, %v", updates, err)
+	}
+
+	versions, err := client.GetNodeAptVersions(ctx, "pve1")
+	if err != nil || len(versions) != 1 || versions[0].RunningKernel != "6.8.8-2-pve" {
+		t.Fatalf("GetNodeAptVersions = %+v, %v", versions, err)
+	}
+
+	subscription, err := client.GetNodeSubscription(ctx, "pve1")
+	if err != nil || subscription.Status != "active" || subscription.Level != "c" || subscription.NextDueDate != "2027-01-01" {
+		t.Fatalf("GetNodeSubscription = %+v, %v", subscription, err)
+	}
+
+	certificates, err := client.GetNodeCertificates(ctx, "pve1")
+	if err != nil || len(certificates) != 1 || certificates[0].NotAfter != 1893456000 || len(certificates[0].SAN) != 1 {
+		t.Fatalf("GetNodeCertificates = %+v, %v", certificates, err)
+	}
+}
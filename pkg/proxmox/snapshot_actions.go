@@ -0,0 +1,126 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// snapshotNamePattern mirrors the pve-configid format Proxmox accepts for snapshot names
+var snapshotNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_\-]{1,39}$`)
+
+// ValidateSnapshotName returns an error if Proxmox would reject the snapshot name
+func ValidateSnapshotName(name string) error {
+	if name == "current" {
+		return fmt.Errorf("snapshot name %q is reserved", name)
+	}
+	if !snapshotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: must start with a letter and contain 2-40 letters, digits, '-' or '_'", name)
+	}
+	return nil
+}
+
+func snapshotBasePath(node string, guestType GuestType, vmid int) (string, error) {
+	if guestType != GuestTypeVM && guestType != GuestTypeContainer {
+		return "", fmt.Errorf("unsupported guest type %q", guestType)
+	}
+	return fmt.Sprintf("/nodes/%s/%s/%d/snapshot", url.PathEscape(node), guestType, vmid), nil
+}
+
+// CreateSnapshot takes a snapshot of a guest and returns the task UPID.
+// vmState saves the RAM of a running VM and is ignored for containers.
+func (c *Client) CreateSnapshot(ctx context.Context, node string, guestType GuestType, vmid int, name, description string, vmState bool) (string, error) {
+	if err := ValidateSnapshotName(name); err != nil {
+		return "", err
+	}
+	path, err := snapshotBasePath(node, guestType, vmid)
+	if err != nil {
+		return "", err
+	}
+
+	data := url.Values{}
+	data.Set("snapname", name)
+	if description != "" {
+		data.Set("description", description)
+	}
+	if vmState && guestType == GuestTypeVM {
+		data.Set("vmstate", "1")
+	}
+
+	resp, err := c.post(ctx, path, data)
+	if err != nil {
+		return "", err
+	}
+	return decodeTaskID(resp, fmt.Sprintf("snapshot guest %d", vmid))
+}
+
+// DeleteSnapshot removes a guest snapshot and returns the task UPID
+func (c *Client) DeleteSnapshot(ctx context.Context, node string, guestType GuestType, vmid int, name string) (string, error) {
+	path, err := snapshotBasePath(node, guestType, vmid)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.delete(ctx, fmt.Sprintf("%s/%s", path, url.PathEscape(name)))
+	if err != nil {
+		return "", err
+	}
+	return decodeTaskID(resp, fmt.Sprintf("delete snapshot %s of guest %d", name, vmid))
+}
+
+// RollbackSnapshot reverts a guest to a snapshot and returns the task UPID
+func (c *Client) RollbackSnapshot(ctx context.Context, node string, guestType GuestType, vmid int, name string) (string, error) {
+	path, err := snapshotBasePath(node, guestType, vmid)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.post(ctx, fmt.Sprintf("%s/%s/rollback", path, url.PathEscape(name)), url.Values{})
+	if err != nil {
+		return "", err
+	}
+	return decodeTaskID(resp, fmt.Sprintf("roll back guest %d to snapshot %s", vmid, name))
+}
+
+// CreateSnapshot takes a guest snapshot through a healthy cluster endpoint
+func (cc *ClusterClient) CreateSnapshot(ctx context.Context, node string, guestType GuestType, vmid int, name, description string, vmState bool) (string, error) {
+	var upid string
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		result, err := client.CreateSnapshot(ctx, node, guestType, vmid, name, description, vmState)
+		if err != nil {
+			return noFailover(err)
+		}
+		upid = result
+		return nil
+	})
+	return upid, unwrapAPIResponseError(err)
+}
+
+// DeleteSnapshot removes a guest snapshot through a healthy cluster endpoint
+func (cc *ClusterClient) DeleteSnapshot(ctx context.Context, node string, guestType GuestType, vmid int, name string) (string, error) {
+	var upid string
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		result, err := client.DeleteSnapshot(ctx, node, guestType, vmid, name)
+		if err != nil {
+			return noFailover(err)
+		}
+		upid = result
+		return nil
+	})
+	return upid, unwrapAPIResponseError(err)
+}
+
+// RollbackSnapshot reverts a guest to a snapshot through a healthy cluster endpoint
+func (cc *ClusterClient) RollbackSnapshot(ctx context.Context, node string, guestType GuestType, vmid int, name string) (string, error) {
+	var upid string
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		result, err := client.RollbackSnapshot(ctx, node, guestType, vmid, name)
+		if err != nil {
+			return noFailover(err)
+		}
+		upid = result
+		return nil
+	})
+	return upid, unwrapAPIResponseError(err)
+}
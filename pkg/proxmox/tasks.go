@@ -0,0 +1,76 @@
+package proxmox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// TaskListOptions filters /nodes/{node}/tasks. Source is "archive" (finished tasks, the
+// Proxmox default), "active" or "all".
+type TaskListOptions struct {
+	Since      int64
+	Limit      int
+	Source     string
+	TypeFilter string
+	ErrorsOnly bool
+}
+
+func (o TaskListOptions) values() url.Values {
+	params := url.Values{}
+	if o.Since > 0 {
+		params.Set("since", strconv.FormatInt(o.Since, 10))
+	}
+	if o.Limit > 0 {
+		params.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Source != "" {
+		params.Set("source", o.Source)
+	}
+	if o.TypeFilter != "" {
+		params.Set("typefilter", o.TypeFilter)
+	}
+	if o.ErrorsOnly {
+		params.Set("errors", "1")
+	}
+	return params
+}
+
+// ListNodeTasks returns the tasks of a node matching the given options
+func (c *Client) ListNodeTasks(ctx context.Context, node string, opts TaskListOptions) ([]Task, error) {
+	path := fmt.Sprintf("/nodes/%s/tasks", url.PathEscape(node))
+	if params := opts.values(); len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	resp, err := c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data []Task `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// ListNodeTasks returns node tasks with failover support
+func (cc *ClusterClient) ListNodeTasks(ctx context.Context, node string, opts TaskListOptions) ([]Task, error) {
+	var result []Task
+	err := cc.executeWithFailover(ctx, func(client *Client) error {
+		tasks, err := client.ListNodeTasks(ctx, node, opts)
+		if err != nil {
+			return err
+		}
+		result = tasks
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,39 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientListNodeTasks(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api2/json/nodes/node1/tasks" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[{"upid":%q,"node":"node1","type":"qmigrate","id":"101","user":"root@pam","starttime":1700000000,"endtime":1700000060,"status":"migration aborted"}]}`, testUPID)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(testClientConfig(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	tasks, err := client.ListNodeTasks(context.Background(), "node1", TaskListOptions{Since: 1699990000, Limit: 100, Source: "all"})
+	if err != nil {
+		t.Fatalf("ListNodeTasks: %v", err)
+	}
+	if query != "limit=100&since=1699990000&source=all" {
+		t.Fatalf("query = %q", query)
+	}
+	if len(tasks) != 1 || tasks[0].Type != "qmigrate" || tasks[0].Status != "migration aborted" || tasks[0].EndTime != 1700000060 {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+}
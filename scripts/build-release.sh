@@ -16,6 +16,25 @@ export CGO_ENABLED=0
 VERSION=${1:-$(cat VERSION)}
 BUILD_DIR="build"
 RELEASE_DIR="release"
+RELEASE_KEYS="$(pwd)/internal/updates/release-signing.pub"
+
+# In-app updates only install releases whose checksums.txt carries a minisign signature
+# from a key embedded in the binary, so refuse to build a release that cannot be signed
+release_keys() {
+    grep -vE '^[[:space:]]*(#|untrusted comment:|$)' "$RELEASE_KEYS" || true
+}
+if [ -z "$(release_keys)" ]; then
+    echo "Error: $RELEASE_KEYS lists no public key; commit the release signing key first." >&2
+    exit 1
+fi
+if [ -z "${MINISIGN_SECRET_KEY:-}" ]; then
+    echo "Error: MINISIGN_SECRET_KEY is not set; in-app updates would reject this release." >&2
+    exit 1
+fi
+if ! command -v minisign >/dev/null 2>&1; then
+    echo "Error: minisign is not installed; in-app updates would reject this release." >&2
+    exit 1
+fi
 
 echo "Building Pulse v${VERSION}..."
 
@@ -227,7 +246,8 @@ if compgen -G "pulse-*.tgz" > /dev/null; then
     checksum_files+=( pulse-*.tgz )
 fi
 if [ ${#checksum_files[@]} -eq 0 ]; then
-    echo "Warning: no release artifacts found to checksum."
+    echo "Error: no release artifacts found to checksum." >&2
+    exit 1
 else
     sha256sum "${checksum_files[@]}" > checksums.txt
     if [ -n "${SIGNING_KEY_ID:-}" ]; then
@@ -241,6 +261,22 @@ else
             echo "SIGNING_KEY_ID is set but gpg is not installed; skipping signature."
         fi
     fi
+    echo "Signing checksums with minisign..."
+    minisign -S -s "${MINISIGN_SECRET_KEY}" -m checksums.txt \
+        -t "pulse v${VERSION} checksums.txt"
+
+    # The secret key must match a key the binaries embed, or every install rejects the release
+    verified=false
+    while read -r key; do
+        if minisign -V -q -P "$key" -m checksums.txt >/dev/null 2>&1; then
+            verified=true
+            break
+        fi
+    done < <(release_keys)
+    if [ "$verified" != true ]; then
+        echo "Error: checksums.txt.minisig does not verify against any key in $RELEASE_KEYS." >&2
+        exit 1
+    fi
 fi
 shopt -u nullglob
 cd ..